@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"math"
 	"math/bits"
@@ -28,6 +29,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"reflect"
 	goregexp "regexp" //nolint:depguard // The Prometheus client library requires us to pass a regexp from this package.
 	"runtime"
 	"runtime/debug"
@@ -44,6 +46,7 @@ import (
 	"github.com/grafana/regexp"
 	"github.com/mwitkow/go-conntrack"
 	"github.com/oklog/run"
+	"github.com/oklog/ulid/v2"
 	remoteapi "github.com/prometheus/client_golang/exp/api/remote"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
@@ -86,6 +89,7 @@ import (
 	"github.com/prometheus/prometheus/util/logging"
 	"github.com/prometheus/prometheus/util/notifications"
 	prom_runtime "github.com/prometheus/prometheus/util/runtime"
+	"github.com/prometheus/prometheus/util/stateevents"
 	"github.com/prometheus/prometheus/web"
 )
 
@@ -197,11 +201,16 @@ type flagConfig struct {
 	agent                       agentOptions
 	lookbackDelta               model.Duration
 	webTimeout                  model.Duration
+	remoteReadIdleWriteTimeout  model.Duration
 	queryTimeout                model.Duration
 	queryConcurrency            int
 	queryMaxSamples             int
 	RemoteFlushDeadline         model.Duration
+	remoteWriteCheckOnReload    bool
 	maxNotificationsSubscribers int
+	maxStateEventSubscribers    int
+	sdUpdateRateLimit           model.Duration
+	sdBreakerWindows            int
 
 	enableAutoReload   bool
 	autoReloadInterval model.Duration
@@ -300,6 +309,10 @@ func (c *flagConfig) setFeatureListOptions(logger *slog.Logger) error {
 				config.DefaultConfig.GlobalConfig.ScrapeProtocols = config.DefaultProtoFirstScrapeProtocols
 				config.DefaultGlobalConfig.ScrapeProtocols = config.DefaultProtoFirstScrapeProtocols
 				logger.Info("Experimental start timestamp storage enabled. OpenMetrics 1.0 parsing will parse <metric>_created metrics as ST instead of normal sample. Changed default scrape_protocols to prefer PrometheusProto format.", "global.scrape_protocols", fmt.Sprintf("%v", config.DefaultGlobalConfig.ScrapeProtocols))
+			case "wal-sample-grouping":
+				c.tsdb.EnableWALSampleGrouping = true
+				c.agent.EnableWALSampleGrouping = true
+				logger.Info("Experimental grouped WAL sample records enabled. Note that older Prometheus versions cannot replay a WAL containing these records.")
 			case "use-start-timestamps":
 				c.useStartTimestamps = true
 				logger.Info("Experimental usage of start timestamps in PromQL engine is enabled.")
@@ -315,6 +328,9 @@ func (c *flagConfig) setFeatureListOptions(logger *slog.Logger) error {
 			case "promql-binop-fill-modifiers":
 				c.parserOpts.EnableBinopFillModifiers = true
 				logger.Info("Experimental PromQL binary operator fill modifiers enabled.")
+			case "promql-lookback-modifier":
+				c.parserOpts.EnableLookbackModifier = true
+				logger.Info("Experimental PromQL lookback modifier enabled.")
 			case "old-ui":
 				c.web.UseOldUI = true
 				logger.Info("Serving previous version of the Prometheus web UI.")
@@ -341,9 +357,22 @@ func (c *flagConfig) setFeatureListOptions(logger *slog.Logger) error {
 			case "fast-startup":
 				c.tsdb.EnableFastStartup = true
 				logger.Info("Experimental fast startup is enabled.")
+			case "roaring-postings":
+				c.tsdb.EnableRoaringPostings = true
+				logger.Info("Experimental roaring bitmap postings index enabled.")
+			case "mmap-label-index":
+				c.tsdb.EnableMmapLabelIndex = true
+				logger.Info("Experimental memory-mapped label index enabled.")
+			case "chunk-summaries":
+				c.tsdb.EnableChunkSummaries = true
+				logger.Info("Experimental per-chunk summary statistics enabled.")
 			case "search-api":
 				c.web.EnableSearch = true
 				logger.Info("Experimental search API enabled.")
+			case "agent-local-query":
+				c.agent.LocalQueryEnabled = true
+				c.web.AgentLocalQuery = true
+				logger.Info("Experimental agent local querying enabled.")
 			default:
 				logger.Warn("Unknown option for --enable-feature", "option", o)
 			}
@@ -399,6 +428,9 @@ func main() {
 		parserOpts: parser.Options{ExperimentalDurationExpr: true},
 		scrape: scrape.Options{
 			FeatureRegistry: features.DefaultRegistry,
+			// The local TSDB and agent WAL never retain appended
+			// histograms past Commit, so scrape loops can reuse them.
+			ReuseHistograms: true,
 		},
 		tsdb: tsdbOptions{
 			// Default to XOR encoding; xor2-encoding feature flag overrides this to EncXOR2.
@@ -446,6 +478,9 @@ func main() {
 	a.Flag("web.max-notifications-subscribers", "Limits the maximum number of subscribers that can concurrently receive live notifications. If the limit is reached, new subscription requests will be denied until existing connections close.").
 		Default("16").IntVar(&cfg.maxNotificationsSubscribers)
 
+	a.Flag("web.max-state-event-subscribers", "Limits the maximum number of subscribers that can concurrently receive live alert state and target health change events. If the limit is reached, new subscription requests will be denied until existing connections close.").
+		Default("16").IntVar(&cfg.maxStateEventSubscribers)
+
 	a.Flag("web.external-url",
 		"The URL under which Prometheus is externally reachable (for example, if Prometheus is served via a reverse proxy). Used for generating relative and absolute links back to Prometheus itself. If the URL has a path portion, it will be used to prefix all HTTP endpoints served by Prometheus. If omitted, relevant URL components will be derived automatically.").
 		PlaceHolder("<URL>").StringVar(&cfg.prometheusURL)
@@ -475,6 +510,15 @@ func main() {
 	a.Flag("web.enable-otlp-receiver", "Enable API endpoint accepting OTLP write requests.").
 		Default("false").BoolVar(&cfg.web.EnableOTLPWriteReceiver)
 
+	a.Flag("web.otlp-receiver.delta-to-cumulative-max-stale", "Time after which a delta series is dropped from the otlp-deltatocumulative conversion state when no new samples arrive for it. Only takes effect with the otlp-deltatocumulative feature enabled.").
+		Default("5m").DurationVar(&cfg.web.ConvertOTLPDeltaMaxStale)
+
+	a.Flag("web.otlp-receiver.delta-to-cumulative-max-streams", "Maximum number of delta series tracked at once by the otlp-deltatocumulative conversion state. 0 means no limit. Only takes effect with the otlp-deltatocumulative feature enabled.").
+		Default("0").IntVar(&cfg.web.ConvertOTLPDeltaMaxStreams)
+
+	a.Flag("web.enable-openmetrics-push-receiver", "Enable API endpoint accepting OpenMetrics text pushes with Pushgateway-style job/instance grouping. Pushes are append-only and carry timestamps.").
+		Default("false").BoolVar(&cfg.web.EnableOMPushReceiver)
+
 	a.Flag("web.console.templates", "Path to the console template directory, available at /consoles.").
 		Default("consoles").StringVar(&cfg.web.ConsoleTemplatesPath)
 
@@ -484,12 +528,21 @@ func main() {
 	a.Flag("web.page-title", "Document title of Prometheus instance.").
 		Default("Prometheus Time Series Collection and Processing Server").StringVar(&cfg.web.PageTitle)
 
+	a.Flag("web.default-display-timezone", "Default timezone the web UI uses to display timestamps, as an IANA timezone name (e.g. 'Europe/Berlin') or 'local' for the browser timezone. Users can override it per browser in the UI settings.").
+		Default("UTC").StringVar(&cfg.web.DefaultDisplayTimezone)
+
 	a.Flag("web.cors.origin", `Regex for CORS origin. It is fully anchored. Example: 'https?://(domain1|domain2)\.com'`).
 		Default(".*").StringVar(&cfg.corsRegexString)
 
 	serverOnlyFlag(a, "storage.tsdb.path", "Base path for metrics storage.").
 		Default("data/").StringVar(&cfg.serverStoragePath)
 
+	serverOnlyFlag(a, "storage.tsdb.cold.path", "Path for a cold storage tier, e.g. on cheaper disks. Blocks older than --storage.tsdb.cold.min-block-age are moved there during retention maintenance and queried transparently. Empty disables tiering.").
+		Default("").StringVar(&cfg.tsdb.ColdDataPath)
+
+	serverOnlyFlag(a, "storage.tsdb.cold.min-block-age", "Minimum age of a block, relative to the newest block, before it is moved to the cold storage tier. Must be set when --storage.tsdb.cold.path is used.").
+		Default("0").SetValue(&cfg.tsdb.ColdMinBlockAge)
+
 	serverOnlyFlag(a, "storage.tsdb.min-block-duration", "Minimum duration of a data block before being persisted. For use in testing.").
 		Hidden().Default("2h").SetValue(&cfg.tsdb.MinBlockDuration)
 
@@ -537,6 +590,12 @@ func main() {
 	serverOnlyFlag(a, "storage.tsdb.delayed-compaction.max-percent", "Sets the upper limit for the random compaction delay, specified as a percentage of the head chunk range. 100 means the compaction can be delayed by up to the entire head chunk range. Only effective when the delayed-compaction feature flag is enabled.").
 		Default("10").Hidden().IntVar(&cfg.tsdb.CompactionDelayMaxPercent)
 
+	serverOnlyFlag(a, "storage.tsdb.compaction.split-shards", "Number of output blocks a compaction of one time range is split into, sharded by series hash and populated concurrently. 0 or 1 disables splitting. Experimental.").
+		Default("0").Hidden().IntVar(&cfg.tsdb.CompactionSplitShards)
+
+	serverOnlyFlag(a, "storage.tsdb.index-format-version", "Pin the index format version written by compaction, e.g. to keep new blocks readable by an older Prometheus version while canarying this one. 0 selects the version based on enabled feature flags. Valid non-zero values are 2, 4 and 5.").
+		Default("0").IntVar(&cfg.tsdb.IndexFormatVersion)
+
 	serverOnlyFlag(a, "storage.tsdb.delay-compact-file.path", "Path to a JSON file with uploaded TSDB blocks e.g. Thanos shipper meta file. If set TSDB will only compact 1 level blocks that are marked as uploaded in that file, improving external storage integrations e.g. with Thanos sidecar. 1+ level compactions won't be delayed.").
 		Default("").StringVar(&tsdbDelayCompactFilePath)
 
@@ -585,9 +644,18 @@ func main() {
 	agentOnlyFlag(a, "storage.agent.no-lockfile", "Do not create lockfile in data directory.").
 		Default("false").BoolVar(&cfg.agent.NoLockfile)
 
+	agentDefaultQueryableWindow := model.Duration(agent.DefaultQueryableWindow * int64(time.Millisecond)).String()
+
+	agentOnlyFlag(a, "storage.agent.queryable-window",
+		"Amount of the most recent data kept in memory and locally queryable. Only used with the agent-local-query feature flag.").
+		Hidden().Default(agentDefaultQueryableWindow).SetValue(&cfg.agent.QueryableWindow)
+
 	a.Flag("storage.remote.flush-deadline", "How long to wait flushing sample on shutdown or config reload.").
 		Default("1m").PlaceHolder("<duration>").SetValue(&cfg.RemoteFlushDeadline)
 
+	a.Flag("storage.remote.write-check-on-reload", "Send a small synthetic write to every remote write endpoint after each configuration load and log the result. Failures are reported but do not fail the reload.").
+		Default("false").BoolVar(&cfg.remoteWriteCheckOnReload)
+
 	serverOnlyFlag(a, "storage.remote.read-sample-limit", "Maximum overall number of samples to return via the remote read interface, in a single query. 0 means no limit. This limit is ignored for streamed response types.").
 		Default("5e7").IntVar(&cfg.web.RemoteReadSampleLimit)
 
@@ -597,6 +665,15 @@ func main() {
 	serverOnlyFlag(a, "storage.remote.read-max-bytes-in-frame", "Maximum number of bytes in a single frame for streaming remote read response types before marshalling. Note that client might have limit on frame size as well. 1MB as recommended by protobuf by default.").
 		Default("1048576").IntVar(&cfg.web.RemoteReadBytesInFrame)
 
+	serverOnlyFlag(a, "storage.remote.read-max-concurrent-streams", "Maximum number of streamed remote read responses served at once, on top of the overall concurrency limit. 0 means no extra limit.").
+		Default("0").IntVar(&cfg.web.RemoteReadMaxStreams)
+
+	serverOnlyFlag(a, "storage.remote.read-max-frame-backlog", "Maximum number of response bytes buffered on the server per streamed remote read request before they are flushed towards the client. 0 means flushing only happens once a query's series are exhausted.").
+		Default("0").IntVar(&cfg.web.RemoteReadMaxFrameBacklog)
+
+	serverOnlyFlag(a, "storage.remote.read-idle-write-timeout", "Abort a streamed remote read response when the client does not consume any data for this long, releasing the resources held by the query. 0 disables the timeout.").
+		Default("5m").SetValue(&cfg.remoteReadIdleWriteTimeout)
+
 	serverOnlyFlag(a, "web.search.max-limit", "Hard upper bound on the \"limit\" query parameter accepted by the experimental search API (--enable-feature=search-api). Requests with a higher limit are rejected with HTTP 400. 0 disables the cap.").
 		Default("10000").IntVar(&cfg.web.MaxSearchLimit)
 
@@ -627,6 +704,12 @@ func main() {
 	serverOnlyFlag(a, "alertmanager.drain-notification-queue-on-shutdown", "Send any outstanding Alertmanager notifications when shutting down. If false, any outstanding Alertmanager notifications will be dropped when shutting down.").
 		Default("true").BoolVar(&cfg.notifier.DrainOnShutdown)
 
+	serverOnlyFlag(a, "alertmanager.ha-peer", "Base URL of a replica in an HA Prometheus pair, repeated for every replica including this one and identical on each of them. When set, each alert is sent by only one healthy replica, cutting duplicate load on Alertmanager. Use with --alertmanager.ha-url.").
+		StringsVar(&cfg.notifier.HAPeerURLs)
+
+	serverOnlyFlag(a, "alertmanager.ha-url", "The entry in --alertmanager.ha-peer identifying this replica.").
+		StringVar(&cfg.notifier.HAURL)
+
 	serverOnlyFlag(a, "query.lookback-delta", "The maximum lookback duration for retrieving metrics during expression evaluations and federation.").
 		Default("5m").SetValue(&cfg.lookbackDelta)
 
@@ -642,7 +725,13 @@ func main() {
 	a.Flag("scrape.discovery-reload-interval", "Interval used by scrape manager to throttle target groups updates.").
 		Hidden().Default("5s").SetValue(&cfg.scrape.DiscoveryReloadInterval)
 
-	a.Flag("enable-feature", "Comma separated feature names to enable. Valid options: concurrent-rule-eval, created-timestamp-zero-ingestion, delayed-compaction, exemplar-storage, extra-scrape-metrics, histograms-st-encoding, memory-snapshot-on-shutdown, metadata-wal-records, old-ui, otlp-deltatocumulative, otlp-native-delta-ingestion, promql-binop-fill-modifiers, promql-delayed-name-removal, promql-experimental-functions, promql-extended-range-selectors, promql-per-step-stats, search-api, st-storage, st-synthesis, type-and-unit-labels, use-start-timestamps, use-uncached-io, xor2-encoding. See https://prometheus.io/docs/prometheus/latest/feature_flags/ for more details.").
+	a.Flag("discovery.provider-update-rate-limit", "Minimum interval between updates applied from a single service discovery provider. Updates arriving faster are coalesced, so a misbehaving provider cannot flood the scrape and notify managers. 0 disables rate limiting.").
+		Default("0s").SetValue(&cfg.sdUpdateRateLimit)
+
+	a.Flag("discovery.provider-circuit-breaker-windows", "Number of consecutive rate limit windows in which a service discovery provider's updates needed coalescing before its updates are suppressed entirely until it stays quiet for a full window. Requires --discovery.provider-update-rate-limit. 0 disables circuit breaking.").
+		Default("0").IntVar(&cfg.sdBreakerWindows)
+
+	a.Flag("enable-feature", "Comma separated feature names to enable. Valid options: agent-local-query, chunk-summaries, concurrent-rule-eval, created-timestamp-zero-ingestion, delayed-compaction, exemplar-storage, extra-scrape-metrics, histograms-st-encoding, memory-snapshot-on-shutdown, metadata-wal-records, mmap-label-index, old-ui, otlp-deltatocumulative, otlp-native-delta-ingestion, promql-binop-fill-modifiers, promql-delayed-name-removal, promql-experimental-functions, promql-extended-range-selectors, promql-lookback-modifier, promql-per-step-stats, roaring-postings, search-api, st-storage, st-synthesis, type-and-unit-labels, use-start-timestamps, use-uncached-io, wal-sample-grouping, xor2-encoding. See https://prometheus.io/docs/prometheus/latest/feature_flags/ for more details.").
 		StringsVar(&cfg.featureList)
 
 	a.Flag("agent", "Run Prometheus in 'Agent mode'.").BoolVar(&agentMode)
@@ -673,6 +762,9 @@ func main() {
 	cfg.web.NotificationsGetter = notifs.Get
 	notifs.AddNotification(notifications.StartingUp)
 
+	stateEvents := stateevents.NewBroker(cfg.maxStateEventSubscribers, prometheus.DefaultRegisterer)
+	cfg.web.StateEventsSub = stateEvents.Sub
+
 	if err := cfg.setFeatureListOptions(logger); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing feature list: %s\n", err)
 		os.Exit(1)
@@ -729,6 +821,14 @@ func main() {
 		os.Exit(2)
 	}
 
+	// The special value "local" defers to the browser timezone in the UI.
+	if cfg.web.DefaultDisplayTimezone != "local" {
+		if _, err := time.LoadLocation(cfg.web.DefaultDisplayTimezone); err != nil {
+			fmt.Fprintln(os.Stderr, fmt.Errorf("invalid default display timezone %q: %w", cfg.web.DefaultDisplayTimezone, err))
+			os.Exit(2)
+		}
+	}
+
 	// Set TSDB retention defaults from CLI flags before any config file is loaded.
 	// This makes CLI flags act as the default when no retention section is present.
 	cliRetentionDuration := cfg.tsdb.RetentionDuration
@@ -830,6 +930,7 @@ func main() {
 	configSuccessTime.SetToCurrentTime()
 
 	cfg.web.ReadTimeout = time.Duration(cfg.webTimeout)
+	cfg.web.RemoteReadIdleWriteTimeout = time.Duration(cfg.remoteReadIdleWriteTimeout)
 	// Default -web.route-prefix to path of -web.external-url.
 	if cfg.web.RoutePrefix == "" {
 		cfg.web.RoutePrefix = cfg.web.ExternalURL.Path
@@ -953,18 +1054,42 @@ func main() {
 		os.Exit(1)
 	}
 
-	discoveryManagerScrape = discovery.NewManager(ctxScrape, logger.With("component", "discovery manager scrape"), prometheus.DefaultRegisterer, sdMetrics, discovery.Name("scrape"), discovery.FeatureRegistry(features.DefaultRegistry))
+	discoveryManagerScrape = discovery.NewManager(ctxScrape, logger.With("component", "discovery manager scrape"), prometheus.DefaultRegisterer, sdMetrics, discovery.Name("scrape"), discovery.FeatureRegistry(features.DefaultRegistry), discovery.ProviderUpdateRateLimit(time.Duration(cfg.sdUpdateRateLimit), cfg.sdBreakerWindows))
 	if discoveryManagerScrape == nil {
 		logger.Error("failed to create a discovery manager scrape")
 		os.Exit(1)
 	}
 
-	discoveryManagerNotify = discovery.NewManager(ctxNotify, logger.With("component", "discovery manager notify"), prometheus.DefaultRegisterer, sdMetrics, discovery.Name("notify"), discovery.FeatureRegistry(features.DefaultRegistry))
+	discoveryManagerNotify = discovery.NewManager(ctxNotify, logger.With("component", "discovery manager notify"), prometheus.DefaultRegisterer, sdMetrics, discovery.Name("notify"), discovery.FeatureRegistry(features.DefaultRegistry), discovery.ProviderUpdateRateLimit(time.Duration(cfg.sdUpdateRateLimit), cfg.sdBreakerWindows))
 	if discoveryManagerNotify == nil {
 		logger.Error("failed to create a discovery manager notify")
 		os.Exit(1)
 	}
 
+	cfg.web.SDUpdateHistory = func() map[string][]discovery.UpdateDiff {
+		return map[string][]discovery.UpdateDiff{
+			"scrape": discoveryManagerScrape.UpdateHistory(),
+			"notify": discoveryManagerNotify.UpdateHistory(),
+		}
+	}
+
+	cfg.scrape.TargetHealthChangeFunc = func(target *scrape.Target, previous, current scrape.TargetHealth) {
+		lastError := ""
+		if err := target.LastError(); err != nil {
+			lastError = err.Error()
+		}
+		stateEvents.Publish(stateevents.Event{
+			Type: stateevents.TypeTarget,
+			Target: &stateevents.TargetEvent{
+				Labels:         target.Labels(labels.NewBuilder(labels.EmptyLabels())),
+				ScrapeURL:      target.URL().String(),
+				PreviousHealth: string(previous),
+				Health:         string(current),
+				LastError:      lastError,
+			},
+		})
+	}
+
 	scrapeManager, err := scrape.NewManager(
 		&cfg.scrape,
 		logger.With("component", "scrape manager"),
@@ -984,7 +1109,9 @@ func main() {
 		ruleManager *rules.Manager
 	)
 
-	if !agentMode {
+	// The query engine is also needed in agent mode when the local query
+	// feature is enabled.
+	if !agentMode || cfg.agent.LocalQueryEnabled {
 		activeQueryTracker, err := promql.NewActiveQueryTracker(localStoragePath, cfg.queryConcurrency, logger.With("component", "activeQueryTracker"))
 		if err != nil {
 			logger.Error("failed to initialize active query tracker", "err", err)
@@ -1012,13 +1139,29 @@ func main() {
 		}
 
 		queryEngine = promql.NewEngine(opts)
+	}
 
+	if !agentMode {
 		ruleManager = rules.NewManager(&rules.ManagerOptions{
-			NameValidationScheme:   cfgFile.GlobalConfig.MetricNameValidationScheme,
-			Appendable:             fanoutStorage,
-			Queryable:              localStorage,
-			QueryFunc:              rules.EngineQueryFunc(queryEngine, fanoutStorage),
-			NotifyFunc:             rules.SendAlerts(notifierManager, cfg.web.ExternalURL.String()),
+			NameValidationScheme: cfgFile.GlobalConfig.MetricNameValidationScheme,
+			Appendable:           fanoutStorage,
+			Queryable:            localStorage,
+			QueryFunc:            rules.EngineQueryFunc(queryEngine, fanoutStorage),
+			NotifyFunc:           rules.SendAlerts(notifierManager, cfg.web.ExternalURL.String()),
+			AlertStateChangeFunc: func(rule *rules.AlertingRule, previous rules.AlertState, alert rules.Alert) {
+				stateEvents.Publish(stateevents.Event{
+					Type: stateevents.TypeAlert,
+					Alert: &stateevents.AlertEvent{
+						RuleName:      rule.Name(),
+						Labels:        alert.Labels,
+						Annotations:   alert.Annotations,
+						PreviousState: previous.String(),
+						State:         alert.State.String(),
+						ActiveAt:      alert.ActiveAt,
+						Value:         alert.Value,
+					},
+				})
+			},
 			Context:                ctxRule,
 			ExternalURL:            cfg.web.ExternalURL,
 			Registerer:             prometheus.DefaultRegisterer,
@@ -1099,7 +1242,7 @@ func main() {
 					}
 
 					curr := cfg.StorageConfig.TSDBConfig.Retention
-					if *curr == lastTSDBRetention {
+					if reflect.DeepEqual(*curr, lastTSDBRetention) {
 						return nil
 					}
 
@@ -1200,6 +1343,26 @@ func main() {
 		},
 	}
 
+	if cfg.remoteWriteCheckOnReload {
+		reloaders = append(reloaders, reloader{
+			name: "remote_write_check",
+			reloader: func(cfg *config.Config) error {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				defer cancel()
+				for _, res := range remote.CheckEndpoints(ctx, cfg) {
+					if res.Success {
+						logger.Info("Remote write endpoint check succeeded", "name", res.Name, "url", res.URL)
+					} else {
+						logger.Warn("Remote write endpoint check failed", "name", res.Name, "url", res.URL, "err", res.Error)
+					}
+				}
+				// A broken endpoint is reported but must not prevent the rest
+				// of the configuration from applying.
+				return nil
+			},
+		})
+	}
+
 	prometheus.MustRegister(configSuccess)
 	prometheus.MustRegister(configSuccessTime)
 
@@ -1307,15 +1470,17 @@ func main() {
 	}
 	{
 		// Scrape manager.
+		// The delta channel must be requested before the discovery manager starts running.
+		scrapeDeltas := discoveryManagerScrape.DeltaSyncCh()
 		g.Add(
 			func() error {
-				// When the scrape manager receives a new targets list
+				// When the scrape manager receives new target deltas
 				// it needs to read a valid config for each job.
 				// It depends on the config being in sync with the discovery manager so
 				// we wait until the config is fully loaded.
 				<-reloadReady.C
 
-				err := scrapeManager.Run(discoveryManagerScrape.SyncCh())
+				err := scrapeManager.RunDeltas(scrapeDeltas)
 				logger.Info("Scrape manager stopped")
 				return err
 			},
@@ -1476,6 +1641,9 @@ func main() {
 						return errors.New("flag 'storage.tsdb.max-block-chunk-segment-size' must be set over 1MB")
 					}
 				}
+				if cfg.tsdb.ColdDataPath != "" && cfg.tsdb.ColdMinBlockAge <= 0 {
+					return errors.New("flag 'storage.tsdb.cold.min-block-age' must be set when 'storage.tsdb.cold.path' is used")
+				}
 
 				db, err := openDBWithMetrics(localStoragePath, logger, prometheus.DefaultRegisterer, &opts, localStorage.getStats())
 				if err != nil {
@@ -1998,6 +2166,66 @@ func (s *readyStorage) BlockMetas() ([]tsdb.BlockMeta, error) {
 	return nil, tsdb.ErrNotReady
 }
 
+// BlockQueryStats implements the api_v1.TSDBAdminStats interface.
+func (s *readyStorage) BlockQueryStats() ([]tsdb.BlockQueryStat, error) {
+	if x := s.get(); x != nil {
+		switch db := x.(type) {
+		case *tsdb.DB:
+			return db.BlockQueryStats(), nil
+		case *agent.DB:
+			return nil, agent.ErrUnsupported
+		default:
+			panic(fmt.Sprintf("unknown storage type %T", db))
+		}
+	}
+	return nil, tsdb.ErrNotReady
+}
+
+// DeleteAuditLog implements the api_v1.TSDBAdminStats interface.
+func (s *readyStorage) DeleteAuditLog() ([]tsdb.DeleteAuditEntry, error) {
+	if x := s.get(); x != nil {
+		switch db := x.(type) {
+		case *tsdb.DB:
+			return db.DeleteAuditLog()
+		case *agent.DB:
+			return nil, agent.ErrUnsupported
+		default:
+			panic(fmt.Sprintf("unknown storage type %T", db))
+		}
+	}
+	return nil, tsdb.ErrNotReady
+}
+
+// ImportBlock implements the api_v1.TSDBAdminStats interface.
+func (s *readyStorage) ImportBlock(r io.Reader) (ulid.ULID, error) {
+	if x := s.get(); x != nil {
+		switch db := x.(type) {
+		case *tsdb.DB:
+			return db.ImportBlock(r)
+		case *agent.DB:
+			return ulid.ULID{}, agent.ErrUnsupported
+		default:
+			panic(fmt.Sprintf("unknown storage type %T", db))
+		}
+	}
+	return ulid.ULID{}, tsdb.ErrNotReady
+}
+
+// ExportBlock implements the api_v1.TSDBAdminStats interface.
+func (s *readyStorage) ExportBlock(ctx context.Context, w io.Writer, mint, maxt int64, matcherSets ...[]*labels.Matcher) (ulid.ULID, error) {
+	if x := s.get(); x != nil {
+		switch db := x.(type) {
+		case *tsdb.DB:
+			return db.ExportBlock(ctx, w, mint, maxt, matcherSets...)
+		case *agent.DB:
+			return ulid.ULID{}, agent.ErrUnsupported
+		default:
+			panic(fmt.Sprintf("unknown storage type %T", db))
+		}
+	}
+	return ulid.ULID{}, tsdb.ErrNotReady
+}
+
 // Delete implements the api_v1.TSDBAdminStats and api_v2.TSDBAdmin interfaces.
 func (s *readyStorage) Delete(ctx context.Context, mint, maxt int64, ms ...*labels.Matcher) error {
 	if x := s.get(); x != nil {
@@ -2028,6 +2256,21 @@ func (s *readyStorage) Snapshot(dir string, withHead bool) error {
 	return tsdb.ErrNotReady
 }
 
+// SnapshotIncremental implements the api_v1.TSDBAdminStats interface.
+func (s *readyStorage) SnapshotIncremental(dir, baseDir string, withHead bool) error {
+	if x := s.get(); x != nil {
+		switch db := x.(type) {
+		case *tsdb.DB:
+			return db.SnapshotIncremental(dir, baseDir, withHead)
+		case *agent.DB:
+			return agent.ErrUnsupported
+		default:
+			panic(fmt.Sprintf("unknown storage type %T", db))
+		}
+	}
+	return tsdb.ErrNotReady
+}
+
 // Stats implements the api_v1.TSDBAdminStats interface.
 func (s *readyStorage) Stats(statsByLabelName string, limit int) (*tsdb.Stats, error) {
 	if x := s.get(); x != nil {
@@ -2101,20 +2344,35 @@ type tsdbOptions struct {
 	EnableMemorySnapshotOnShutdown bool
 	EnableDelayedCompaction        bool
 	CompactionDelayMaxPercent      int
+	CompactionSplitShards          int
 	EnableOverlappingCompaction    bool
 	UseUncachedIO                  bool
 	BlockCompactionExcludeFunc     tsdb.BlockExcludeFilterFunc
 	BlockReloadInterval            model.Duration
+	ColdDataPath                   string
+	ColdMinBlockAge                model.Duration
 	EnableSTAsZeroSample           bool
 	EnableSTStorage                bool
+	EnableWALSampleGrouping        bool
 	EnableHistogramSTEncoding      bool
 	StaleSeriesCompactionThreshold float64
 	EnableFastStartup              bool
 	FloatChunkEncoding             chunkenc.Encoding
 	XOR2EncodingAllowed            bool
+	EnableRoaringPostings          bool
+	EnableChunkSummaries           bool
+	IndexFormatVersion             int
+	EnableMmapLabelIndex           bool
 }
 
 func (opts tsdbOptions) ToTSDBOptions() tsdb.Options {
+	var blockTiers []tsdb.BlockTier
+	if opts.ColdDataPath != "" {
+		blockTiers = []tsdb.BlockTier{{
+			Dir:         opts.ColdDataPath,
+			MinBlockAge: int64(time.Duration(opts.ColdMinBlockAge) / time.Millisecond),
+		}}
+	}
 	return tsdb.Options{
 		WALSegmentSize:                 int(opts.WALSegmentSize),
 		MaxBlockChunkSegmentSize:       int64(opts.MaxBlockChunkSegmentSize),
@@ -2134,18 +2392,25 @@ func (opts tsdbOptions) ToTSDBOptions() tsdb.Options {
 		OutOfOrderTimeWindow:           opts.OutOfOrderTimeWindow,
 		EnableDelayedCompaction:        opts.EnableDelayedCompaction,
 		CompactionDelayMaxPercent:      opts.CompactionDelayMaxPercent,
+		CompactionSplitShards:          opts.CompactionSplitShards,
 		EnableOverlappingCompaction:    opts.EnableOverlappingCompaction,
 		UseUncachedIO:                  opts.UseUncachedIO,
 		BlockCompactionExcludeFunc:     opts.BlockCompactionExcludeFunc,
 		BlockReloadInterval:            time.Duration(opts.BlockReloadInterval),
+		BlockTiers:                     blockTiers,
 		FeatureRegistry:                features.DefaultRegistry,
 		EnableSTAsZeroSample:           opts.EnableSTAsZeroSample,
 		EnableSTStorage:                opts.EnableSTStorage,
+		EnableWALSampleGrouping:        opts.EnableWALSampleGrouping,
 		EnableHistogramSTEncoding:      opts.EnableHistogramSTEncoding,
 		StaleSeriesCompactionThreshold: opts.StaleSeriesCompactionThreshold,
 		EnableFastStartup:              opts.EnableFastStartup,
 		FloatChunkEncoding:             opts.FloatChunkEncoding,
 		XOR2EncodingAllowed:            opts.XOR2EncodingAllowed,
+		EnableRoaringPostings:          opts.EnableRoaringPostings,
+		EnableChunkSummaries:           opts.EnableChunkSummaries,
+		IndexFormatVersion:             opts.IndexFormatVersion,
+		EnableMmapLabelIndex:           opts.EnableMmapLabelIndex,
 	}
 }
 
@@ -2161,14 +2426,21 @@ type agentOptions struct {
 	OutOfOrderTimeWindow         int64 // TODO(bwplotka): Unused option, fix it or remove.
 	EnableSTAsZeroSample         bool
 	EnableSTStorage              bool
+	EnableWALSampleGrouping      bool
 	CheckpointFromInMemorySeries bool
 	CheckpointBatchSize          int
+	QueryableWindow              model.Duration
+	LocalQueryEnabled            bool
 }
 
 func (opts agentOptions) ToAgentOptions(outOfOrderTimeWindow int64) agent.Options {
 	if outOfOrderTimeWindow < 0 {
 		outOfOrderTimeWindow = 0
 	}
+	var queryableWindow int64
+	if opts.LocalQueryEnabled {
+		queryableWindow = durationToInt64Millis(time.Duration(opts.QueryableWindow))
+	}
 	return agent.Options{
 		WALSegmentSize:               int(opts.WALSegmentSize),
 		WALCompression:               opts.WALCompressionType,
@@ -2180,8 +2452,10 @@ func (opts agentOptions) ToAgentOptions(outOfOrderTimeWindow int64) agent.Option
 		OutOfOrderTimeWindow:         outOfOrderTimeWindow,
 		EnableSTAsZeroSample:         opts.EnableSTAsZeroSample,
 		EnableSTStorage:              opts.EnableSTStorage,
+		EnableWALSampleGrouping:      opts.EnableWALSampleGrouping,
 		CheckpointFromInMemorySeries: opts.CheckpointFromInMemorySeries,
 		CheckpointBatchSize:          opts.CheckpointBatchSize,
+		QueryableWindow:              queryableWindow,
 	}
 }
 
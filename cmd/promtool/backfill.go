@@ -19,13 +19,20 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net/http"
+	"net/url"
+	"os"
 	"time"
 
 	"github.com/oklog/ulid/v2"
+	remoteapi "github.com/prometheus/client_golang/exp/api/remote"
 	"github.com/prometheus/common/promslog"
+	"golang.org/x/time/rate"
 
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/prometheus/prometheus/tsdb"
 )
 
@@ -228,6 +235,176 @@ func createBlocks(input []byte, mint, maxt, maxBlockDuration int64, maxSamplesIn
 	return nil
 }
 
+// backfillOpenMetricsToRemoteWrite reads an OpenMetrics file and streams its
+// samples to a remote write endpoint in batches instead of creating local
+// blocks. This allows backfilling backends that only expose a remote write
+// API. The given URL must be the full URL of the write endpoint.
+func backfillOpenMetricsToRemoteWrite(path string, url *url.URL, roundTripper http.RoundTripper, timeout time.Duration, protoMsg string, batchSize, rateLimit int, quiet bool, customLabels map[string]string) int {
+	input, err := os.ReadFile(path)
+	if err != nil {
+		return checkErr(err)
+	}
+
+	var messageType remoteapi.WriteMessageType
+	switch protoMsg {
+	case "io.prometheus.write.v2.Request":
+		messageType = remoteapi.WriteV2MessageType
+	case "prometheus.WriteRequest":
+		messageType = remoteapi.WriteV1MessageType
+	default:
+		return checkErr(fmt.Errorf("invalid protobuf message %q, must be prometheus.WriteRequest or io.prometheus.write.v2.Request", protoMsg))
+	}
+
+	// An empty API path keeps the user-provided URL as the write endpoint.
+	writeAPI, err := remoteapi.NewAPI(
+		url.String(),
+		remoteapi.WithAPIPath(""),
+		remoteapi.WithAPIHTTPClient(&http.Client{Transport: roundTripper, Timeout: timeout}),
+	)
+	if err != nil {
+		return checkErr(err)
+	}
+
+	sender := &remoteWriteSender{
+		api:     writeAPI,
+		msgType: messageType,
+		symbols: writev2.NewSymbolTable(),
+	}
+	if rateLimit > 0 {
+		// The burst must cover a whole batch, as a batch is sent at once.
+		sender.limiter = rate.NewLimiter(rate.Limit(rateLimit), max(rateLimit, batchSize))
+	}
+
+	if err := streamOpenMetrics(context.Background(), input, sender, batchSize, customLabels); err != nil {
+		return checkErr(err)
+	}
+	if !quiet {
+		fmt.Printf("Sent %d samples in %d remote write requests.\n", sender.sentSamples, sender.sentRequests)
+	}
+	return successExitCode
+}
+
+// streamOpenMetrics parses the OpenMetrics input and appends every sample to
+// the sender, flushing whenever batchSize samples have accumulated.
+func streamOpenMetrics(ctx context.Context, input []byte, sender *remoteWriteSender, batchSize int, customLabels map[string]string) error {
+	p := textparse.NewOpenMetricsParser(input, labels.NewSymbolTable())
+	lb := labels.NewBuilder(labels.EmptyLabels())
+	for {
+		e, err := p.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("parse: %w", err)
+		}
+		if e != textparse.EntrySeries {
+			continue
+		}
+
+		_, ts, v := p.Series()
+		l := labels.Labels{}
+		p.Labels(&l)
+		if ts == nil {
+			return fmt.Errorf("expected timestamp for series %v, got none", l)
+		}
+
+		lb.Reset(l)
+		for name, value := range customLabels {
+			lb.Set(name, value)
+		}
+		sender.append(lb.Labels(), *ts, v)
+
+		if sender.samples >= batchSize {
+			if err := sender.flush(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return sender.flush(ctx)
+}
+
+// remoteWriteSender accumulates samples into a remote write request of the
+// configured message type and sends it out, optionally rate limited.
+type remoteWriteSender struct {
+	api     *remoteapi.API
+	msgType remoteapi.WriteMessageType
+	limiter *rate.Limiter
+
+	v1       []prompb.TimeSeries
+	v2       []writev2.TimeSeries
+	symbols  writev2.SymbolsTable
+	lastLbls labels.Labels
+	samples  int
+
+	sentSamples  int
+	sentRequests int
+}
+
+// append adds a sample to the pending request, reusing the last time series
+// when consecutive samples share the same labels.
+func (s *remoteWriteSender) append(lbls labels.Labels, ts int64, v float64) {
+	sameSeries := s.samples > 0 && labels.Equal(lbls, s.lastLbls)
+	if s.msgType == remoteapi.WriteV2MessageType {
+		if sameSeries {
+			last := &s.v2[len(s.v2)-1]
+			last.Samples = append(last.Samples, writev2.Sample{Value: v, Timestamp: ts})
+		} else {
+			s.v2 = append(s.v2, writev2.TimeSeries{
+				LabelsRefs: s.symbols.SymbolizeLabels(lbls, nil),
+				Samples:    []writev2.Sample{{Value: v, Timestamp: ts}},
+			})
+		}
+	} else {
+		if sameSeries {
+			last := &s.v1[len(s.v1)-1]
+			last.Samples = append(last.Samples, prompb.Sample{Value: v, Timestamp: ts})
+		} else {
+			pLbls := make([]prompb.Label, 0, lbls.Len())
+			lbls.Range(func(l labels.Label) {
+				pLbls = append(pLbls, prompb.Label{Name: l.Name, Value: l.Value})
+			})
+			s.v1 = append(s.v1, prompb.TimeSeries{
+				Labels:  pLbls,
+				Samples: []prompb.Sample{{Value: v, Timestamp: ts}},
+			})
+		}
+	}
+	s.lastLbls = lbls
+	s.samples++
+}
+
+// flush sends the pending request, if any, and resets the sender for the next
+// batch.
+func (s *remoteWriteSender) flush(ctx context.Context) error {
+	if s.samples == 0 {
+		return nil
+	}
+	if s.limiter != nil {
+		if err := s.limiter.WaitN(ctx, s.samples); err != nil {
+			return err
+		}
+	}
+
+	var msg any
+	if s.msgType == remoteapi.WriteV2MessageType {
+		msg = &writev2.Request{Symbols: s.symbols.Symbols(), Timeseries: s.v2}
+	} else {
+		msg = &prompb.WriteRequest{Timeseries: s.v1}
+	}
+	if _, err := s.api.Write(ctx, s.msgType, msg); err != nil {
+		return fmt.Errorf("remote write: %w", err)
+	}
+
+	s.sentSamples += s.samples
+	s.sentRequests++
+	s.samples = 0
+	s.v1 = s.v1[:0]
+	s.v2 = s.v2[:0]
+	s.symbols.Reset()
+	s.lastLbls = labels.EmptyLabels()
+	return nil
+}
+
 func backfill(maxSamplesInAppender int, input []byte, outputDir string, humanReadable, quiet bool, maxBlockDuration time.Duration, customLabels map[string]string) (err error) {
 	p := textparse.NewOpenMetricsParser(input, nil) // Don't need a SymbolTable to get max and min timestamps.
 	maxt, mint, err := getMinAndMaxTimestamps(p)
@@ -15,14 +15,21 @@ package main
 
 import (
 	"context"
+	"io"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"sort"
 	"testing"
 	"time"
 
+	remoteapi "github.com/prometheus/client_golang/exp/api/remote"
 	"github.com/stretchr/testify/require"
 
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb"
 	"github.com/prometheus/prometheus/tsdb/chunkenc"
@@ -755,3 +762,116 @@ after_eof 1 2
 		})
 	}
 }
+
+// backfillWriteStorage collects and decodes the remote write requests
+// received while backfilling to a remote write endpoint.
+type backfillWriteStorage struct {
+	v1 []prompb.WriteRequest
+	v2 []writev2.Request
+}
+
+func (s *backfillWriteStorage) Store(req *http.Request, msgType remoteapi.WriteMessageType) (*remoteapi.WriteResponse, error) {
+	// The handler middleware has already snappy-decompressed the body.
+	decoded, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if msgType == remoteapi.WriteV2MessageType {
+		var r writev2.Request
+		if err := r.Unmarshal(decoded); err != nil {
+			return nil, err
+		}
+		s.v2 = append(s.v2, r)
+	} else {
+		var r prompb.WriteRequest
+		if err := r.Unmarshal(decoded); err != nil {
+			return nil, err
+		}
+		s.v1 = append(s.v1, r)
+	}
+
+	resp := remoteapi.NewWriteResponse()
+	resp.SetStatusCode(http.StatusNoContent)
+	return resp, nil
+}
+
+func TestBackfillOpenMetricsToRemoteWrite(t *testing.T) {
+	input := `# HELP http_requests_total The total number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{code="200"} 1021 1625844018
+http_requests_total{code="200"} 1022 1625844078
+http_requests_total{code="400"} 2 1625844018
+http_requests_total{code="400"} 3 1625844078
+# EOF
+`
+
+	for _, tc := range []struct {
+		name      string
+		protoMsg  string
+		rateLimit int
+	}{
+		{
+			name:     "streaming with prometheus.WriteRequest",
+			protoMsg: "prometheus.WriteRequest",
+		},
+		{
+			name:     "streaming with io.prometheus.write.v2.Request",
+			protoMsg: "io.prometheus.write.v2.Request",
+		},
+		{
+			name:      "streaming with rate limiting",
+			protoMsg:  "prometheus.WriteRequest",
+			rateLimit: 1000,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &backfillWriteStorage{}
+			server := httptest.NewServer(remoteapi.NewWriteHandler(store, remoteapi.MessageTypes{remoteapi.WriteV1MessageType, remoteapi.WriteV2MessageType}))
+			defer server.Close()
+			serverURL, err := url.Parse(server.URL)
+			require.NoError(t, err)
+
+			tmpFile := createMetricsFile(t, input)
+
+			// A batch size of 3 splits the 4 samples over 2 requests.
+			code := backfillOpenMetricsToRemoteWrite(tmpFile, serverURL, http.DefaultTransport, 30*time.Second, tc.protoMsg, 3, tc.rateLimit, true, map[string]string{"job": "backfill"})
+			require.Equal(t, successExitCode, code)
+
+			samples := 0
+			if tc.protoMsg == "io.prometheus.write.v2.Request" {
+				require.Len(t, store.v2, 2)
+				for _, r := range store.v2 {
+					require.Contains(t, r.Symbols, "job")
+					require.Contains(t, r.Symbols, "backfill")
+					for _, series := range r.Timeseries {
+						samples += len(series.Samples)
+					}
+				}
+			} else {
+				require.Len(t, store.v1, 2)
+				for _, r := range store.v1 {
+					for _, series := range r.Timeseries {
+						require.Contains(t, series.Labels, prompb.Label{Name: "job", Value: "backfill"})
+						samples += len(series.Samples)
+					}
+				}
+			}
+			require.Equal(t, 4, samples)
+		})
+	}
+}
+
+func TestBackfillOpenMetricsToRemoteWriteInvalidProtoMsg(t *testing.T) {
+	store := &backfillWriteStorage{}
+	server := httptest.NewServer(remoteapi.NewWriteHandler(store, remoteapi.MessageTypes{remoteapi.WriteV1MessageType}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	tmpFile := createMetricsFile(t, "http_requests_total 1 1625844018\n# EOF\n")
+
+	code := backfillOpenMetricsToRemoteWrite(tmpFile, serverURL, http.DefaultTransport, 30*time.Second, "blabla", 3, 0, true, nil)
+	require.Equal(t, failureExitCode, code)
+	require.Empty(t, store.v1, "no request should reach the server for an invalid protobuf message")
+}
@@ -17,12 +17,32 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
 )
 
+// debugTriggerPollInterval is how often the trigger condition is re-evaluated
+// while waiting for it to be met.
+const debugTriggerPollInterval = 10 * time.Second
+
 type debugWriterConfig struct {
 	serverURL      string
 	tarballName    string
 	endPointGroups []endpointsGroup
+
+	// Continuous collection session: snapshots spaced by interval are
+	// written into per-snapshot directories of the archive. A snapshots
+	// value below two collects a single snapshot at the archive root,
+	// matching the non-continuous behavior.
+	snapshots int
+	interval  time.Duration
+	// When set, collection only starts once the condition is met on the
+	// server's /metrics endpoint.
+	trigger *debugTrigger
 }
 
 func debugWrite(cfg debugWriterConfig) error {
@@ -31,6 +51,38 @@ func debugWrite(cfg debugWriterConfig) error {
 		return fmt.Errorf("error creating a new archiver: %w", err)
 	}
 
+	if cfg.trigger != nil {
+		if err := cfg.trigger.wait(cfg.serverURL); err != nil {
+			return fmt.Errorf("error waiting for the trigger condition: %w", err)
+		}
+	}
+
+	snapshots := max(cfg.snapshots, 1)
+	for i := range snapshots {
+		if i > 0 {
+			fmt.Printf("waiting %s before snapshot %d/%d\n", cfg.interval, i+1, snapshots)
+			time.Sleep(cfg.interval)
+		}
+		prefix := ""
+		if snapshots > 1 {
+			prefix = fmt.Sprintf("snapshot-%02d/", i+1)
+		}
+		if err := debugCollect(archiver, cfg, prefix); err != nil {
+			return err
+		}
+	}
+
+	if err := archiver.close(); err != nil {
+		return fmt.Errorf("error closing archive writer: %w", err)
+	}
+
+	fmt.Printf("Compiling debug information complete, all files written in %q.\n", cfg.tarballName)
+	return nil
+}
+
+// debugCollect fetches every configured endpoint once and writes the
+// responses into the archive under the given directory prefix.
+func debugCollect(archiver *tarGzFileWriter, cfg debugWriterConfig, prefix string) error {
 	for _, endPointGroup := range cfg.endPointGroups {
 		for url, filename := range endPointGroup.urlToFilename {
 			url := cfg.serverURL + url
@@ -51,16 +103,104 @@ func debugWrite(cfg debugWriterConfig) error {
 					return fmt.Errorf("error post-processing HTTP response body: %w", err)
 				}
 			}
-			if err := archiver.write(filename, body); err != nil {
+			if err := archiver.write(prefix+filename, body); err != nil {
 				return fmt.Errorf("error writing into the archive: %w", err)
 			}
 		}
 	}
+	return nil
+}
 
-	if err := archiver.close(); err != nil {
-		return fmt.Errorf("error closing archive writer: %w", err)
+// debugTrigger is a threshold condition on a metric exposed by the server,
+// e.g. "process_resident_memory_bytes > 2e9".
+type debugTrigger struct {
+	metric string
+	above  bool // Whether the metric has to be above (true) or below (false) the threshold.
+	value  float64
+}
+
+// parseDebugTrigger parses a condition of the form '<metric> > <value>' or
+// '<metric> < <value>'.
+func parseDebugTrigger(s string) (*debugTrigger, error) {
+	for _, op := range []string{">", "<"} {
+		metric, value, ok := strings.Cut(s, op)
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trigger threshold %q: %w", strings.TrimSpace(value), err)
+		}
+		if strings.TrimSpace(metric) == "" {
+			return nil, fmt.Errorf("trigger condition %q is missing a metric name", s)
+		}
+		return &debugTrigger{metric: strings.TrimSpace(metric), above: op == ">", value: v}, nil
 	}
+	return nil, fmt.Errorf("invalid trigger condition %q, expected '<metric> > <value>' or '<metric> < <value>'", s)
+}
 
-	fmt.Printf("Compiling debug information complete, all files written in %q.\n", cfg.tarballName)
-	return nil
+// String implements fmt.Stringer, reproducing the parsed condition.
+func (t *debugTrigger) String() string {
+	op := "<"
+	if t.above {
+		op = ">"
+	}
+	return fmt.Sprintf("%s %s %g", t.metric, op, t.value)
+}
+
+// wait blocks until the condition is met on the server's /metrics endpoint,
+// polling every debugTriggerPollInterval.
+func (t *debugTrigger) wait(serverURL string) error {
+	for {
+		met, err := t.met(serverURL)
+		if err != nil {
+			return err
+		}
+		if met {
+			fmt.Printf("trigger condition %q met, starting collection\n", t)
+			return nil
+		}
+		fmt.Printf("waiting %s for trigger condition %q\n", debugTriggerPollInterval, t)
+		time.Sleep(debugTriggerPollInterval)
+	}
+}
+
+// met reports whether any series of the metric currently satisfies the
+// condition.
+func (t *debugTrigger) met(serverURL string) (bool, error) {
+	res, err := http.Get(serverURL + "/metrics")
+	if err != nil {
+		return false, fmt.Errorf("error executing HTTP request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("server returned HTTP status %s", res.Status)
+	}
+
+	p := expfmt.NewTextParser(model.NameValidationScheme)
+	families, err := p.TextToMetricFamilies(res.Body)
+	if err != nil {
+		return false, fmt.Errorf("error parsing metrics: %w", err)
+	}
+	mf, ok := families[t.metric]
+	if !ok {
+		return false, nil
+	}
+	for _, m := range mf.GetMetric() {
+		var v float64
+		switch {
+		case m.GetGauge() != nil:
+			v = m.GetGauge().GetValue()
+		case m.GetCounter() != nil:
+			v = m.GetCounter().GetValue()
+		case m.GetUntyped() != nil:
+			v = m.GetUntyped().GetValue()
+		default:
+			continue
+		}
+		if t.above && v > t.value || !t.above && v < t.value {
+			return true, nil
+		}
+	}
+	return false, nil
 }
@@ -0,0 +1,145 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDebugTrigger(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected *debugTrigger
+		errMsg   string
+	}{
+		{
+			input:    "process_resident_memory_bytes > 2e9",
+			expected: &debugTrigger{metric: "process_resident_memory_bytes", above: true, value: 2e9},
+		},
+		{
+			input:    "prometheus_tsdb_head_series<1000",
+			expected: &debugTrigger{metric: "prometheus_tsdb_head_series", above: false, value: 1000},
+		},
+		{
+			input:  "process_resident_memory_bytes > lots",
+			errMsg: `invalid trigger threshold "lots"`,
+		},
+		{
+			input:  "> 100",
+			errMsg: `trigger condition "> 100" is missing a metric name`,
+		},
+		{
+			input:  "process_resident_memory_bytes >= 100",
+			errMsg: `invalid trigger threshold "= 100"`,
+		},
+		{
+			input:  "process_resident_memory_bytes",
+			errMsg: "invalid trigger condition",
+		},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			trigger, err := parseDebugTrigger(tc.input)
+			if tc.errMsg != "" {
+				require.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, trigger)
+		})
+	}
+}
+
+func TestDebugTriggerMet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "# TYPE process_resident_memory_bytes gauge")
+		fmt.Fprintln(w, "process_resident_memory_bytes 5e8")
+	}))
+	defer server.Close()
+
+	for _, tc := range []struct {
+		condition string
+		expected  bool
+	}{
+		{condition: "process_resident_memory_bytes > 2e8", expected: true},
+		{condition: "process_resident_memory_bytes > 2e9", expected: false},
+		{condition: "process_resident_memory_bytes < 2e9", expected: true},
+		// A metric the server does not expose never triggers.
+		{condition: "prometheus_tsdb_head_series > 0", expected: false},
+	} {
+		t.Run(tc.condition, func(t *testing.T) {
+			trigger, err := parseDebugTrigger(tc.condition)
+			require.NoError(t, err)
+			met, err := trigger.met(server.URL)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, met)
+		})
+	}
+}
+
+func TestDebugWriteContinuous(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "# TYPE process_resident_memory_bytes gauge")
+		fmt.Fprintln(w, "process_resident_memory_bytes 5e8")
+	}))
+	defer server.Close()
+
+	trigger, err := parseDebugTrigger("process_resident_memory_bytes > 2e8")
+	require.NoError(t, err)
+
+	tarball := filepath.Join(t.TempDir(), "debug.tar.gz")
+	require.NoError(t, debugWrite(debugWriterConfig{
+		serverURL:   server.URL,
+		tarballName: tarball,
+		endPointGroups: []endpointsGroup{
+			{urlToFilename: map[string]string{"/metrics": "metrics.txt"}},
+		},
+		snapshots: 2,
+		trigger:   trigger,
+	}))
+
+	require.Equal(t, []string{"snapshot-01/metrics.txt", "snapshot-02/metrics.txt"}, tarballFileNames(t, tarball))
+}
+
+// tarballFileNames returns the names of all files in a gzipped tarball.
+func tarballFileNames(t *testing.T, path string) []string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gzr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gzr)
+
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, header.Name)
+	}
+	return names
+}
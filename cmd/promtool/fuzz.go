@@ -0,0 +1,136 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/prometheus/common/promslog"
+
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// errPanic wraps a panic recovered while parsing a corpus entry.
+var errPanic = errors.New("panic while parsing")
+
+// FuzzPromQLCorpus parses every expression of the given corpus files, one
+// expression per line, and reports crashes and parse failures. It mirrors the
+// FuzzParseExpr fuzz target, so operators can replay their production queries
+// against the parser of this Prometheus version.
+func FuzzPromQLCorpus(files ...string) int {
+	var exprs, failures, crashes int
+
+	p := parser.NewParser(parser.Options{
+		EnableExperimentalFunctions:  true,
+		ExperimentalDurationExpr:     true,
+		EnableExtendedRangeSelectors: true,
+		EnableBinopFillModifiers:     true,
+		EnableLookbackModifier:       true,
+	})
+	for _, f := range files {
+		file, err := os.Open(f)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "  FAILED:", err)
+			return failureExitCode
+		}
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(nil, 1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			expr := strings.TrimSpace(scanner.Text())
+			if expr == "" || strings.HasPrefix(expr, "#") {
+				continue
+			}
+			exprs++
+			switch err := parseExprSafe(p, expr); {
+			case err == nil:
+			case errors.Is(err, parser.ErrUnexpected), errors.Is(err, errPanic):
+				// The parser recovers from runtime panics and returns
+				// ErrUnexpected; those indicate a real bug.
+				crashes++
+				fmt.Fprintf(os.Stderr, "  CRASH: %s:%d: %v\n", f, line, err)
+			default:
+				failures++
+				fmt.Fprintf(os.Stderr, "  FAILED: %s:%d: %v\n", f, line, err)
+			}
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "  FAILED:", err)
+			return failureExitCode
+		}
+	}
+
+	fmt.Printf("%d expressions parsed, %d parse failures, %d crashes\n", exprs, failures, crashes)
+	switch {
+	case crashes > 0:
+		return failureExitCode
+	case failures > 0:
+		return lintErrExitCode
+	}
+	return successExitCode
+}
+
+func parseExprSafe(p parser.Parser, expr string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", errPanic, r)
+		}
+	}()
+	_, err = p.ParseExpr(expr)
+	return err
+}
+
+// FuzzConfigCorpus loads every given file as a Prometheus configuration and
+// reports crashes and load failures, so operators can replay their production
+// configs against the config parser of this Prometheus version.
+func FuzzConfigCorpus(agentMode bool, files ...string) int {
+	var failures, crashes int
+
+	for _, f := range files {
+		switch err := loadConfigSafe(f, agentMode); {
+		case err == nil:
+		case errors.Is(err, errPanic):
+			crashes++
+			fmt.Fprintf(os.Stderr, "  CRASH: %s: %v\n", f, err)
+		default:
+			failures++
+			fmt.Fprintf(os.Stderr, "  FAILED: %s: %v\n", f, err)
+		}
+	}
+
+	fmt.Printf("%d configs parsed, %d load failures, %d crashes\n", len(files), failures, crashes)
+	switch {
+	case crashes > 0:
+		return failureExitCode
+	case failures > 0:
+		return lintErrExitCode
+	}
+	return successExitCode
+}
+
+func loadConfigSafe(fn string, agentMode bool) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", errPanic, r)
+		}
+	}()
+	_, err = config.LoadFile(fn, agentMode, promslog.NewNopLogger())
+	return err
+}
@@ -0,0 +1,52 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzPromQLCorpus(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		corpus   string
+		exitCode int
+	}{
+		{
+			name:     "valid expressions",
+			corpus:   "up\nrate(http_requests_total[5m])\n# a comment\n\nsum by (job) (up)\n",
+			exitCode: successExitCode,
+		},
+		{
+			name:     "parse failure",
+			corpus:   "up\nsum by (job) (\n",
+			exitCode: lintErrExitCode,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := filepath.Join(t.TempDir(), "corpus.txt")
+			require.NoError(t, os.WriteFile(fn, []byte(tc.corpus), 0o600))
+			require.Equal(t, tc.exitCode, FuzzPromQLCorpus(fn))
+		})
+	}
+}
+
+func TestFuzzConfigCorpus(t *testing.T) {
+	require.Equal(t, successExitCode, FuzzConfigCorpus(false, "./testdata/config_with_service_discovery_files.yml"))
+	require.Equal(t, lintErrExitCode, FuzzConfigCorpus(false, "./testdata/prometheus-config.bad.yml"))
+}
@@ -124,6 +124,7 @@ func main() {
 	sdConfigFile := sdCheckCmd.Arg("config-file", "The prometheus config file.").Required().ExistingFile()
 	sdJobName := sdCheckCmd.Arg("job", "The job to run service discovery for.").Required().String()
 	sdTimeout := sdCheckCmd.Flag("timeout", "The time to wait for discovery results.").Default("30s").Duration()
+	sdWatch := sdCheckCmd.Flag("watch", "Keep running and print target adds, removes and relabeling changes as they happen, until interrupted. Ignores --timeout.").Bool()
 
 	checkConfigCmd := checkCmd.Command("config", "Check if the config files are valid or not.")
 	configFiles := checkConfigCmd.Arg(
@@ -203,6 +204,10 @@ func main() {
 	debugCmd := app.Command("debug", "Fetch debug information.")
 	debugPprofCmd := debugCmd.Command("pprof", "Fetch profiling debug information.")
 	debugPprofServer := debugPprofCmd.Arg("server", "Prometheus server to get pprof files from.").Required().String()
+	debugPprofContinuous := debugPprofCmd.Flag("continuous", "Collect multiple snapshots spaced by --snapshot-interval into one archive.").Bool()
+	debugPprofSnapshots := debugPprofCmd.Flag("snapshots", "Number of snapshots to collect in continuous mode.").Default("3").Int()
+	debugPprofInterval := debugPprofCmd.Flag("snapshot-interval", "Time to wait between two snapshots in continuous mode.").Default("1m").Duration()
+	debugPprofTrigger := debugPprofCmd.Flag("trigger", "Only start collecting once the given condition is met on the server's metrics endpoint, e.g. 'process_resident_memory_bytes > 2e9'. Conditions have the form '<metric> > <value>' or '<metric> < <value>'.").String()
 	debugMetricsCmd := debugCmd.Command("metrics", "Fetch metrics debug information.")
 	debugMetricsServer := debugMetricsCmd.Arg("server", "Prometheus server to get metrics from.").Required().String()
 	debugAllCmd := debugCmd.Command("all", "Fetch all debug information.")
@@ -249,6 +254,19 @@ func main() {
 	testRulesDiff := testRulesCmd.Flag("diff", "[Experimental] Print colored differential output between expected & received output.").Default("false").Bool()
 	testRulesIgnoreUnknownFields := testRulesCmd.Flag("ignore-unknown-fields", "Ignore unknown fields in the test files. This is useful when you want to extend rule files with custom metadata. Ensure that those fields are removed before loading them into the Prometheus server as it performs strict checks by default.").Default("false").Bool()
 
+	fuzzCmd := app.Command("fuzz", "Replay a corpus of inputs against Prometheus parsers and report crashes.")
+	fuzzPromQLCmd := fuzzCmd.Command("promql", "Parse every PromQL expression in the corpus files (one expression per line, '#' starts a comment) and report crashes and parse failures.")
+	fuzzPromQLFiles := fuzzPromQLCmd.Arg(
+		"corpus-file",
+		"Corpus files with one PromQL expression per line.",
+	).Required().ExistingFiles()
+	fuzzConfigCmd := fuzzCmd.Command("config", "Load every given file as a Prometheus configuration and report crashes and load failures.")
+	fuzzConfigFiles := fuzzConfigCmd.Arg(
+		"corpus-file",
+		"Configuration files to load.",
+	).Required().ExistingFiles()
+	fuzzConfigAgentMode := fuzzConfigCmd.Flag("agent", "Load config files for Prometheus in Agent mode.").Bool()
+
 	defaultDBPath := "data/"
 	tsdbCmd := app.Command("tsdb", "Run tsdb commands.")
 
@@ -266,10 +284,18 @@ func main() {
 	analyzeRunExtended := tsdbAnalyzeCmd.Flag("extended", "Run extended analysis.").Bool()
 	analyzeMatchers := tsdbAnalyzeCmd.Flag("match", "Series selector to analyze. Only 1 set of matchers is supported now.").String()
 
+	tsdbRestoreSnapshotCmd := tsdbCmd.Command("restore-snapshot", "Validate a snapshot taken by the snapshot API and install its blocks into a data directory.")
+	restoreSnapshotPath := tsdbRestoreSnapshotCmd.Arg("snapshot path", "Snapshot directory to restore from.").Required().String()
+	restoreSnapshotDBPath := tsdbRestoreSnapshotCmd.Arg("db path", "Database path to restore into (default is "+defaultDBPath+").").Default(defaultDBPath).String()
+
 	tsdbListCmd := tsdbCmd.Command("list", "List tsdb blocks.")
 	listHumanReadable := tsdbListCmd.Flag("human-readable", "Print human readable values.").Short('r').Bool()
 	listPath := tsdbListCmd.Arg("db path", "Database path (default is "+defaultDBPath+").").Default(defaultDBPath).String()
 
+	tsdbInspectCmd := tsdbCmd.Command("inspect", "Print the delete audit log of a TSDB, listing recorded delete and tombstone-clean actions.")
+	inspectHumanReadable := tsdbInspectCmd.Flag("human-readable", "Print human readable values.").Short('r').Bool()
+	inspectPath := tsdbInspectCmd.Arg("db path", "Database path (default is "+defaultDBPath+").").Default(defaultDBPath).String()
+
 	tsdbDumpCmd := tsdbCmd.Command("dump", "Dump data (series+samples or optionally just series) from a TSDB.")
 	dumpPath := tsdbDumpCmd.Arg("db path", "Database path (default is "+defaultDBPath+").").Default(defaultDBPath).String()
 	dumpSandboxDirRoot := tsdbDumpCmd.Flag("sandbox-dir-root", "Root directory where a sandbox directory will be created, this sandbox is used in case WAL replay generates chunks (default is the database path). The sandbox is cleaned up at the end.").String()
@@ -285,12 +311,24 @@ func main() {
 	dumpOpenMetricsMaxTime := tsdbDumpOpenMetricsCmd.Flag("max-time", "Maximum timestamp to dump, in milliseconds since the Unix epoch.").Default(strconv.FormatInt(math.MaxInt64, 10)).Int64()
 	dumpOpenMetricsMatch := tsdbDumpOpenMetricsCmd.Flag("match", "Series selector. Can be specified multiple times.").Default("{__name__=~'(?s:.*)'}").Strings()
 
+	tsdbListSeriesCmd := tsdbCmd.Command("list-series", "[Experimental] Dump series and samples matching the given selectors from a TSDB in OpenMetrics text format. Native histograms are written using the PromQL test expression notation, as OpenMetrics cannot represent them yet.")
+	listSeriesPath := tsdbListSeriesCmd.Arg("db path", "Database path (default is "+defaultDBPath+").").Default(defaultDBPath).String()
+	listSeriesSandboxDirRoot := tsdbListSeriesCmd.Flag("sandbox-dir-root", "Root directory where a sandbox directory will be created, this sandbox is used in case WAL replay generates chunks (default is the database path). The sandbox is cleaned up at the end.").String()
+	listSeriesMinTime := tsdbListSeriesCmd.Flag("min-time", "Minimum timestamp to dump, in milliseconds since the Unix epoch.").Default(strconv.FormatInt(math.MinInt64, 10)).Int64()
+	listSeriesMaxTime := tsdbListSeriesCmd.Flag("max-time", "Maximum timestamp to dump, in milliseconds since the Unix epoch.").Default(strconv.FormatInt(math.MaxInt64, 10)).Int64()
+	listSeriesMatch := tsdbListSeriesCmd.Flag("match", "Series selector. Can be specified multiple times.").Default("{__name__=~'(?s:.*)'}").Strings()
+
 	importCmd := tsdbCmd.Command("create-blocks-from", "[Experimental] Import samples from input and produce TSDB blocks. Please refer to the storage docs for more details.")
 	importHumanReadable := importCmd.Flag("human-readable", "Print human readable values.").Short('r').Bool()
 	importQuiet := importCmd.Flag("quiet", "Do not print created blocks.").Short('q').Bool()
 	maxBlockDuration := importCmd.Flag("max-block-duration", "Maximum duration created blocks may span. Anything less than 2h is ignored.").Hidden().PlaceHolder("<duration>").Duration()
 	openMetricsImportCmd := importCmd.Command("openmetrics", "Import samples from OpenMetrics input and produce TSDB blocks. Please refer to the storage docs for more details.")
 	openMetricsLabels := openMetricsImportCmd.Flag("label", "Label to attach to metrics. Can be specified multiple times. Example --label=label_name=label_value").StringMap()
+	importIntoRemoteWrite := openMetricsImportCmd.Flag("into-remote-write", "Stream the parsed samples to this remote write endpoint instead of creating local blocks. Must be the full URL of the write endpoint. The output directory argument is ignored.").PlaceHolder("<url>").URL()
+	importRemoteWriteProtoMsg := openMetricsImportCmd.Flag("remote-write.protobuf-message", "Remote write protobuf message to use when streaming (prometheus.WriteRequest or io.prometheus.write.v2.Request).").Default("prometheus.WriteRequest").String()
+	importRemoteWriteBatchSize := openMetricsImportCmd.Flag("remote-write.batch-size", "Maximum number of samples to send in a single remote write request when streaming.").Default("10000").Int()
+	importRemoteWriteRateLimit := openMetricsImportCmd.Flag("remote-write.rate-limit", "Maximum number of samples per second to send when streaming. 0 disables rate limiting.").Default("0").Int()
+	importRemoteWriteTimeout := openMetricsImportCmd.Flag("remote-write.timeout", "Timeout for a single remote write request when streaming.").Default("30s").Duration()
 	importFilePath := openMetricsImportCmd.Arg("input file", "OpenMetrics file to read samples from.").Required().String()
 	importDBPath := openMetricsImportCmd.Arg("output directory", "Output directory for generated blocks.").Default(defaultDBPath).String()
 	importRulesCmd := importCmd.Command("rules", "Create blocks of data for new recording rules.")
@@ -366,6 +404,8 @@ func main() {
 				promtoolParserOpts.EnableExtendedRangeSelectors = true
 			case "promql-binop-fill-modifiers":
 				promtoolParserOpts.EnableBinopFillModifiers = true
+			case "promql-lookback-modifier":
+				promtoolParserOpts.EnableLookbackModifier = true
 			case "":
 				continue
 			default:
@@ -377,7 +417,7 @@ func main() {
 
 	switch parsedCmd {
 	case sdCheckCmd.FullCommand():
-		os.Exit(CheckSD(*sdConfigFile, *sdJobName, *sdTimeout, prometheus.DefaultRegisterer))
+		os.Exit(CheckSD(*sdConfigFile, *sdJobName, *sdTimeout, *sdWatch, prometheus.DefaultRegisterer))
 
 	case checkConfigCmd.FullCommand():
 		os.Exit(CheckConfig(*agentMode, *checkConfigSyntaxOnly, newConfigLintConfig(*checkConfigLint, *checkConfigLintFatal, *checkConfigIgnoreUnknownFields, model.UTF8Validation, model.Duration(*checkLookbackDelta)), promtoolParser, *configFiles...))
@@ -410,7 +450,7 @@ func main() {
 		os.Exit(QuerySeries(serverURL, httpRoundTripper, *querySeriesMatch, *querySeriesBegin, *querySeriesEnd, p))
 
 	case debugPprofCmd.FullCommand():
-		os.Exit(debugPprof(*debugPprofServer))
+		os.Exit(debugPprof(*debugPprofServer, *debugPprofContinuous, *debugPprofSnapshots, *debugPprofInterval, *debugPprofTrigger))
 
 	case debugMetricsCmd.FullCommand():
 		os.Exit(debugMetrics(*debugMetricsServer))
@@ -440,15 +480,27 @@ func main() {
 			*testRulesFiles...),
 		)
 
+	case fuzzPromQLCmd.FullCommand():
+		os.Exit(FuzzPromQLCorpus(*fuzzPromQLFiles...))
+
+	case fuzzConfigCmd.FullCommand():
+		os.Exit(FuzzConfigCorpus(*fuzzConfigAgentMode, *fuzzConfigFiles...))
+
 	case tsdbBenchWriteCmd.FullCommand():
 		os.Exit(checkErr(benchmarkWrite(*benchWriteOutPath, *benchSamplesFile, *benchWriteNumMetrics, *benchWriteNumScrapes)))
 
 	case tsdbAnalyzeCmd.FullCommand():
 		os.Exit(checkErr(analyzeBlock(ctx, *analyzePath, *analyzeBlockID, *analyzeLimit, *analyzeRunExtended, *analyzeMatchers, promtoolParser)))
 
+	case tsdbRestoreSnapshotCmd.FullCommand():
+		os.Exit(checkErr(restoreSnapshot(*restoreSnapshotPath, *restoreSnapshotDBPath)))
+
 	case tsdbListCmd.FullCommand():
 		os.Exit(checkErr(listBlocks(*listPath, *listHumanReadable)))
 
+	case tsdbInspectCmd.FullCommand():
+		os.Exit(checkErr(inspectDeleteAudit(*inspectPath, *inspectHumanReadable)))
+
 	case tsdbDumpCmd.FullCommand():
 		format := formatSeriesSet
 		if *dumpFormat == "seriesjson" {
@@ -458,8 +510,14 @@ func main() {
 
 	case tsdbDumpOpenMetricsCmd.FullCommand():
 		os.Exit(checkErr(dumpTSDBData(ctx, *dumpOpenMetricsPath, *dumpOpenMetricsSandboxDirRoot, *dumpOpenMetricsMinTime, *dumpOpenMetricsMaxTime, *dumpOpenMetricsMatch, formatSeriesSetOpenMetrics, promtoolParser)))
+
+	case tsdbListSeriesCmd.FullCommand():
+		os.Exit(checkErr(dumpTSDBData(ctx, *listSeriesPath, *listSeriesSandboxDirRoot, *listSeriesMinTime, *listSeriesMaxTime, *listSeriesMatch, formatSeriesSetOpenMetricsWithHistograms, promtoolParser)))
 	// TODO(aSquare14): Work on adding support for custom block size.
 	case openMetricsImportCmd.FullCommand():
+		if *importIntoRemoteWrite != nil {
+			os.Exit(backfillOpenMetricsToRemoteWrite(*importFilePath, *importIntoRemoteWrite, httpRoundTripper, *importRemoteWriteTimeout, *importRemoteWriteProtoMsg, *importRemoteWriteBatchSize, *importRemoteWriteRateLimit, *importQuiet, *openMetricsLabels))
+		}
 		os.Exit(backfillOpenMetrics(*importFilePath, *importDBPath, *importHumanReadable, *importQuiet, *maxBlockDuration, *openMetricsLabels))
 
 	case importRulesCmd.FullCommand():
@@ -1203,12 +1261,29 @@ var (
 	allEndpoints = append(pprofEndpoints, metricsEndpoints...)
 )
 
-func debugPprof(url string) int {
-	if err := debugWrite(debugWriterConfig{
+func debugPprof(url string, continuous bool, snapshots int, interval time.Duration, trigger string) int {
+	cfg := debugWriterConfig{
 		serverURL:      url,
 		tarballName:    "debug.tar.gz",
 		endPointGroups: pprofEndpoints,
-	}); err != nil {
+	}
+	if continuous {
+		if snapshots < 1 {
+			fmt.Fprintln(os.Stderr, "error completing debug command: --snapshots must be positive")
+			return failureExitCode
+		}
+		cfg.snapshots = snapshots
+		cfg.interval = interval
+	}
+	if trigger != "" {
+		t, err := parseDebugTrigger(trigger)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error completing debug command:", err)
+			return failureExitCode
+		}
+		cfg.trigger = t
+	}
+	if err := debugWrite(cfg); err != nil {
 		fmt.Fprintln(os.Stderr, "error completing debug command:", err)
 		return failureExitCode
 	}
@@ -18,6 +18,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -37,8 +39,19 @@ type sdCheckResult struct {
 	Error            error         `json:"error,omitempty"`
 }
 
+// sdCheckEvent describes a single target change observed in watch mode.
+type sdCheckEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Op is "add" for a new target, "remove" for a vanished target and
+	// "update" for a target whose relabeling outcome changed.
+	Op     string `json:"op"`
+	Source string `json:"source"`
+	sdCheckResult
+}
+
 // CheckSD performs service discovery for the given job name and reports the results.
-func CheckSD(sdConfigFiles, sdJobName string, sdTimeout time.Duration, _ prometheus.Registerer) int {
+// In watch mode it keeps running and prints a line per target change instead.
+func CheckSD(sdConfigFiles, sdJobName string, sdTimeout time.Duration, sdWatch bool, _ prometheus.Registerer) int {
 	logger := promslog.New(&promslog.Config{})
 
 	cfg, err := config.LoadFile(sdConfigFiles, false, logger)
@@ -74,7 +87,16 @@ func CheckSD(sdConfigFiles, sdJobName string, sdTimeout time.Duration, _ prometh
 	}
 
 	targetGroupChan := make(chan []*targetgroup.Group)
-	ctx, cancel := context.WithTimeout(context.Background(), sdTimeout)
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if sdWatch {
+		// Watch mode runs until interrupted; the timeout does not apply.
+		ctx, cancel = signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	} else {
+		ctx, cancel = context.WithTimeout(context.Background(), sdTimeout)
+	}
 	defer cancel()
 
 	for _, cfg := range scrapeConfig.ServiceDiscoveryConfigs {
@@ -99,6 +121,10 @@ func CheckSD(sdConfigFiles, sdJobName string, sdTimeout time.Duration, _ prometh
 		}()
 	}
 
+	if sdWatch {
+		return watchSD(ctx, targetGroupChan, scrapeConfig)
+	}
+
 	var targetGroups []*targetgroup.Group
 	sdCheckResults := make(map[string][]*targetgroup.Group)
 outerLoop:
@@ -127,6 +153,69 @@ outerLoop:
 	return successExitCode
 }
 
+// watchSD consumes target group updates until the context is cancelled and
+// prints one JSON event per line for every target that is added, removed or
+// whose relabeling outcome changed. The first update per source reports all
+// its targets as added.
+func watchSD(ctx context.Context, targetGroupChan <-chan []*targetgroup.Group, scrapeConfig *config.ScrapeConfig) int {
+	enc := json.NewEncoder(os.Stdout)
+	state := make(map[string][]sdCheckResult)
+	for {
+		select {
+		case targetGroups := <-targetGroupChan:
+			now := time.Now().UTC()
+			for _, tg := range targetGroups {
+				cur := getSDCheckResult([]*targetgroup.Group{tg}, scrapeConfig)
+				for _, event := range getSDCheckEvents(now, tg.Source, state[tg.Source], cur) {
+					if err := enc.Encode(event); err != nil {
+						fmt.Fprintf(os.Stderr, "Could not marshal event json: %s", err)
+						return failureExitCode
+					}
+				}
+				state[tg.Source] = cur
+			}
+		case <-ctx.Done():
+			return successExitCode
+		}
+	}
+}
+
+// getSDCheckEvents diffs two relabeling results of the same target group
+// source, keyed by the discovered labels that identify a target.
+func getSDCheckEvents(now time.Time, source string, prev, cur []sdCheckResult) []sdCheckEvent {
+	prevByTarget := make(map[string]sdCheckResult, len(prev))
+	for _, res := range prev {
+		prevByTarget[res.DiscoveredLabels.String()] = res
+	}
+
+	var events []sdCheckEvent
+	seen := make(map[string]struct{}, len(cur))
+	for _, res := range cur {
+		key := res.DiscoveredLabels.String()
+		seen[key] = struct{}{}
+		prevRes, ok := prevByTarget[key]
+		switch {
+		case !ok:
+			events = append(events, sdCheckEvent{Timestamp: now, Op: "add", Source: source, sdCheckResult: res})
+		case !labels.Equal(prevRes.Labels, res.Labels) || errText(prevRes.Error) != errText(res.Error):
+			events = append(events, sdCheckEvent{Timestamp: now, Op: "update", Source: source, sdCheckResult: res})
+		}
+	}
+	for _, res := range prev {
+		if _, ok := seen[res.DiscoveredLabels.String()]; !ok {
+			events = append(events, sdCheckEvent{Timestamp: now, Op: "remove", Source: source, sdCheckResult: res})
+		}
+	}
+	return events
+}
+
+func errText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
 func getSDCheckResult(targetGroups []*targetgroup.Group, scrapeConfig *config.ScrapeConfig) []sdCheckResult {
 	sdCheckResults := []sdCheckResult{}
 	lb := labels.NewBuilder(labels.EmptyLabels())
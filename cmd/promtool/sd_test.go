@@ -17,6 +17,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
@@ -60,6 +61,7 @@ func TestSDCheckResult(t *testing.T) {
 				"__scrape_interval__", "1m",
 				"__scrape_native_histograms__", "false",
 				"__scrape_timeout__", "10s",
+				"__scrape_without_jitter__", "false",
 				"foo", "bar",
 			),
 			Labels: labels.FromStrings(
@@ -69,6 +71,7 @@ func TestSDCheckResult(t *testing.T) {
 				"__scrape_interval__", "1m",
 				"__scrape_native_histograms__", "false",
 				"__scrape_timeout__", "10s",
+				"__scrape_without_jitter__", "false",
 				"foo", "bar",
 				"instance", "localhost:8080",
 				"newfoo", "bar",
@@ -79,3 +82,61 @@ func TestSDCheckResult(t *testing.T) {
 
 	testutil.RequireEqual(t, expectedSDCheckResult, getSDCheckResult(targetGroups, scrapeConfig))
 }
+
+func TestSDCheckEvents(t *testing.T) {
+	t.Parallel()
+
+	res := func(addr, instance string) sdCheckResult {
+		return sdCheckResult{
+			DiscoveredLabels: labels.FromStrings("__address__", addr),
+			Labels:           labels.FromStrings("__address__", addr, "instance", instance),
+		}
+	}
+	now := time.Unix(1700000000, 0).UTC()
+	event := func(op string, r sdCheckResult) sdCheckEvent {
+		return sdCheckEvent{Timestamp: now, Op: op, Source: "test/0", sdCheckResult: r}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		prev, cur []sdCheckResult
+		expected  []sdCheckEvent
+	}{
+		{
+			name:     "initial targets are adds",
+			cur:      []sdCheckResult{res("localhost:8080", "a")},
+			expected: []sdCheckEvent{event("add", res("localhost:8080", "a"))},
+		},
+		{
+			name: "unchanged target yields no event",
+			prev: []sdCheckResult{res("localhost:8080", "a")},
+			cur:  []sdCheckResult{res("localhost:8080", "a")},
+		},
+		{
+			name:     "vanished target is a remove",
+			prev:     []sdCheckResult{res("localhost:8080", "a")},
+			expected: []sdCheckEvent{event("remove", res("localhost:8080", "a"))},
+		},
+		{
+			name:     "changed relabeling outcome is an update",
+			prev:     []sdCheckResult{res("localhost:8080", "a")},
+			cur:      []sdCheckResult{res("localhost:8080", "b")},
+			expected: []sdCheckEvent{event("update", res("localhost:8080", "b"))},
+		},
+		{
+			name: "mixed changes",
+			prev: []sdCheckResult{res("localhost:8080", "a"), res("localhost:8081", "b")},
+			cur:  []sdCheckResult{res("localhost:8080", "a"), res("localhost:8082", "c")},
+			expected: []sdCheckEvent{
+				event("add", res("localhost:8082", "c")),
+				event("remove", res("localhost:8081", "b")),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			testutil.RequireEqualWithOptions(t, tc.expected, getSDCheckEvents(now, "test/0", tc.prev, tc.cur),
+				[]cmp.Option{cmp.AllowUnexported(sdCheckEvent{})})
+		})
+	}
+}
@@ -34,6 +34,7 @@ import (
 	"time"
 
 	"github.com/alecthomas/units"
+	"github.com/oklog/ulid/v2"
 	"github.com/prometheus/common/promslog"
 	"go.uber.org/atomic"
 
@@ -345,6 +346,105 @@ func listBlocks(path string, humanReadable bool) error {
 	return nil
 }
 
+func inspectDeleteAudit(path string, humanReadable bool) error {
+	entries, err := tsdb.ReadDeleteAuditLog(path)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 13, 0, 2, ' ', 0)
+	defer tw.Flush()
+
+	fmt.Fprintln(tw, "TIME\tACTION\tSOURCE\tMIN TIME\tMAX TIME\tSERIES AFFECTED\tMATCHERS")
+	for _, e := range entries {
+		mint, maxt := "", ""
+		if e.Action == tsdb.DeleteAuditActionDelete {
+			mint = getFormattedTime(e.MinTime, humanReadable)
+			maxt = getFormattedTime(e.MaxTime, humanReadable)
+		}
+		fmt.Fprintf(tw,
+			"%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
+			getFormattedTime(e.Time, humanReadable),
+			e.Action,
+			e.Source,
+			mint,
+			maxt,
+			e.SeriesAffected,
+			strings.Join(e.Matchers, " "),
+		)
+	}
+	return nil
+}
+
+// restoreSnapshot validates the blocks of a snapshot taken by the snapshot API
+// and installs them into a data directory. Blocks already present in the data
+// directory are left untouched, so incremental snapshots can be restored on
+// top of their base. Prometheus must not be running on the data directory.
+func restoreSnapshot(snapshotPath, dbPath string) error {
+	logger := promslog.New(&promslog.Config{})
+
+	manifest, err := tsdb.ReadSnapshotManifest(snapshotPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read snapshot manifest: %w", err)
+	}
+	if manifest == nil {
+		fmt.Fprintln(os.Stderr, "snapshot has no manifest, restoring all blocks found in the snapshot directory")
+	}
+
+	dirs, err := os.ReadDir(snapshotPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dbPath, 0o777); err != nil {
+		return err
+	}
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		if _, err := ulid.ParseStrict(d.Name()); err != nil {
+			continue
+		}
+		src := filepath.Join(snapshotPath, d.Name())
+
+		// Open the block to validate it before installing it.
+		b, err := tsdb.OpenBlock(logger, src, nil, nil)
+		if err != nil {
+			return fmt.Errorf("invalid block %s in snapshot: %w", d.Name(), err)
+		}
+		if err := b.Close(); err != nil {
+			return err
+		}
+
+		dest := filepath.Join(dbPath, d.Name())
+		if _, err := os.Stat(dest); err == nil {
+			fmt.Printf("BLOCK %s already present, skipping\n", d.Name())
+			continue
+		}
+		if err := fileutil.CopyDirs(src, dest); err != nil {
+			return fmt.Errorf("install block %s: %w", d.Name(), err)
+		}
+		fmt.Printf("BLOCK %s installed\n", d.Name())
+	}
+
+	if manifest == nil {
+		return nil
+	}
+	// For incremental snapshots, blocks of the base snapshot are expected to
+	// be in the data directory already. Verify the restored directory covers
+	// the full manifest.
+	var missing []string
+	for _, name := range manifest.Blocks {
+		if _, err := os.Stat(filepath.Join(dbPath, name)); err != nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("snapshot restored incompletely, blocks listed in the manifest are missing from the data directory (restore the base snapshot first): %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 func printBlocks(blocks []tsdb.BlockReader, writeHeader, humanReadable bool) {
 	tw := tabwriter.NewWriter(os.Stdout, 13, 0, 2, ' ', 0)
 	defer tw.Flush()
@@ -836,6 +936,39 @@ func formatSeriesSetOpenMetrics(ss storage.SeriesSet) error {
 	return nil
 }
 
+// formatSeriesSetOpenMetricsWithHistograms writes samples in OpenMetrics text
+// format like formatSeriesSetOpenMetrics, but includes native histogram
+// samples instead of dropping them. As OpenMetrics has no representation for
+// native histograms yet, they are written using the PromQL test expression
+// notation, which `promtool promql` and the unit test format understand.
+func formatSeriesSetOpenMetricsWithHistograms(ss storage.SeriesSet) error {
+	for ss.Next() {
+		series := ss.At()
+		lbs := series.Labels()
+		metricName := lbs.Get(labels.MetricName)
+		lbs = lbs.DropReserved(func(n string) bool { return n == labels.MetricName })
+		it := series.Iterator(nil)
+		for vt := it.Next(); vt != chunkenc.ValNone; vt = it.Next() {
+			switch vt {
+			case chunkenc.ValFloat:
+				ts, val := it.At()
+				fmt.Printf("%s%s %g %.3f\n", metricName, CondensedString(lbs), val, float64(ts)/1000)
+			case chunkenc.ValHistogram:
+				ts, h := it.AtHistogram(nil)
+				fmt.Printf("%s%s %s %.3f\n", metricName, CondensedString(lbs), h.ToFloat(nil).TestExpression(), float64(ts)/1000)
+			case chunkenc.ValFloatHistogram:
+				ts, fh := it.AtFloatHistogram(nil)
+				fmt.Printf("%s%s %s %.3f\n", metricName, CondensedString(lbs), fh.TestExpression(), float64(ts)/1000)
+			}
+		}
+		if it.Err() != nil {
+			return it.Err()
+		}
+	}
+	fmt.Println("# EOF")
+	return nil
+}
+
 func checkErr(err error) int {
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -221,6 +221,40 @@ func TestTSDBDumpOpenMetrics(t *testing.T) {
 	}
 }
 
+func TestTSDBListSeries(t *testing.T) {
+	storage := promqltest.LoadedStorage(t, `
+		load 1m
+			my_gauge{foo="bar", baz="abc"} 1 2 3
+			my_histogram{foo="bar"} {{schema:1 count:5 sum:18.4 buckets:[1 2 2]}}x2
+	`)
+
+	tests := []struct {
+		name         string
+		match        []string
+		expectedDump string
+	}{
+		{
+			name:         "default match",
+			match:        []string{"{__name__=~'(?s:.*)'}"},
+			expectedDump: "testdata/list-series-test-1.prom",
+		},
+		{
+			name:         "histogram only",
+			match:        []string{"{__name__='my_histogram'}"},
+			expectedDump: "testdata/list-series-test-2.prom",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectedMetrics, err := os.ReadFile(tt.expectedDump)
+			require.NoError(t, err)
+			expectedMetrics = normalizeNewLine(expectedMetrics)
+			dumpedMetrics := getDumpedSamples(t, storage.Dir(), "", math.MinInt64, math.MaxInt64, tt.match, formatSeriesSetOpenMetricsWithHistograms)
+			require.Equal(t, sortLines(string(expectedMetrics)), sortLines(dumpedMetrics))
+		})
+	}
+}
+
 func TestTSDBDumpOpenMetricsRoundTrip(t *testing.T) {
 	initialMetrics, err := os.ReadFile("testdata/dump-openmetrics-roundtrip-test.prom")
 	require.NoError(t, err)
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"log/slog"
 	"mime"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -37,10 +38,14 @@ import (
 	"go.yaml.in/yaml/v2"
 
 	"github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/prometheus/prometheus/storage/remote/azuread"
 	"github.com/prometheus/prometheus/storage/remote/googleiam"
+	"github.com/prometheus/prometheus/storage/remote/kafka"
+	"github.com/prometheus/prometheus/util/compression"
 )
 
 var (
@@ -225,11 +230,13 @@ var (
 
 	// DefaultRemoteWriteConfig is the default remote write configuration.
 	DefaultRemoteWriteConfig = RemoteWriteConfig{
-		RemoteTimeout:    model.Duration(30 * time.Second),
-		ProtobufMessage:  remoteapi.WriteV1MessageType,
-		QueueConfig:      DefaultQueueConfig,
-		MetadataConfig:   DefaultMetadataConfig,
-		HTTPClientConfig: DefaultRemoteWriteHTTPClientConfig,
+		RemoteTimeout:        model.Duration(30 * time.Second),
+		ProtobufMessage:      remoteapi.WriteV1MessageType,
+		Compression:          compression.Snappy,
+		SendStalenessMarkers: true,
+		QueueConfig:          DefaultQueueConfig,
+		MetadataConfig:       DefaultMetadataConfig,
+		HTTPClientConfig:     DefaultRemoteWriteHTTPClientConfig,
 	}
 
 	// DefaultQueueConfig is the default remote queue configuration.
@@ -451,6 +458,9 @@ func (c *Config) UnmarshalYAML(unmarshal func(any) error) error {
 	}
 
 	rwNames := map[string]struct{}{}
+	rwPrimarySeen := false
+	rwGroupQuorums := map[string]int{}
+	rwGroupSizes := map[string]int{}
 	for _, rwcfg := range c.RemoteWriteConfigs {
 		if rwcfg == nil {
 			return errors.New("empty or null remote write config section")
@@ -459,11 +469,32 @@ func (c *Config) UnmarshalYAML(unmarshal func(any) error) error {
 		if _, ok := rwNames[rwcfg.Name]; ok && rwcfg.Name != "" {
 			return fmt.Errorf("found multiple remote write configs with job name %q", rwcfg.Name)
 		}
+		if rwcfg.Primary {
+			if rwPrimarySeen {
+				return errors.New("found multiple remote write configs marked as primary, at most one is allowed")
+			}
+			rwPrimarySeen = true
+		}
+		if r := rwcfg.Replication; r != nil {
+			if quorum, ok := rwGroupQuorums[r.Group]; ok && quorum != r.Quorum {
+				return fmt.Errorf("replication group %q members declare different quorums (%d and %d)", r.Group, quorum, r.Quorum)
+			}
+			rwGroupQuorums[r.Group] = r.Quorum
+			rwGroupSizes[r.Group]++
+		}
 		if err := rwcfg.Validate(c.GlobalConfig.MetricNameValidationScheme); err != nil {
 			return err
 		}
 		rwNames[rwcfg.Name] = struct{}{}
 	}
+	if rwPrimarySeen && len(rwGroupQuorums) > 0 {
+		return errors.New("a primary remote write config cannot be combined with replication groups")
+	}
+	for group, quorum := range rwGroupQuorums {
+		if size := rwGroupSizes[group]; quorum > size {
+			return fmt.Errorf("replication group %q has quorum %d but only %d members", group, quorum, size)
+		}
+	}
 	rrNames := map[string]struct{}{}
 	for _, rrcfg := range c.RemoteReadConfigs {
 		if rrcfg == nil {
@@ -576,6 +607,8 @@ var (
 	PrometheusText1_0_0  ScrapeProtocol = "PrometheusText1.0.0"
 	OpenMetricsText0_0_1 ScrapeProtocol = "OpenMetricsText0.0.1"
 	OpenMetricsText1_0_0 ScrapeProtocol = "OpenMetricsText1.0.0"
+	OTLPJSON             ScrapeProtocol = "OTLPJSON"
+	OTLPProto            ScrapeProtocol = "OTLPProto"
 	UTF8NamesHeader      string         = model.EscapingKey + "=" + model.AllowUTF8
 
 	ScrapeProtocolsHeaders = map[ScrapeProtocol]string{
@@ -584,6 +617,8 @@ var (
 		PrometheusText1_0_0:  "text/plain;version=1.0.0",
 		OpenMetricsText0_0_1: "application/openmetrics-text;version=0.0.1",
 		OpenMetricsText1_0_0: "application/openmetrics-text;version=1.0.0",
+		OTLPJSON:             "application/vnd.otlp+json",
+		OTLPProto:            "application/vnd.otlp+proto",
 	}
 
 	// DefaultScrapeProtocols is the set of scrape protocols that will be proposed
@@ -608,6 +643,112 @@ var (
 	}
 )
 
+// DNSResolutionStrategy controls which IP address family is used when
+// connecting to scrape targets.
+type DNSResolutionStrategy string
+
+// The supported DNS resolution strategies. The empty strategy keeps the Go
+// default dual-stack behaviour ("happy eyeballs", RFC 6555).
+const (
+	DNSResolutionAny        DNSResolutionStrategy = ""
+	DNSResolutionIPv4       DNSResolutionStrategy = "ipv4"
+	DNSResolutionIPv6       DNSResolutionStrategy = "ipv6"
+	DNSResolutionPreferIPv4 DNSResolutionStrategy = "prefer_ipv4"
+	DNSResolutionPreferIPv6 DNSResolutionStrategy = "prefer_ipv6"
+)
+
+// Validate returns an error if the given DNS resolution strategy is not supported.
+func (s DNSResolutionStrategy) Validate() error {
+	switch s {
+	case DNSResolutionAny, DNSResolutionIPv4, DNSResolutionIPv6, DNSResolutionPreferIPv4, DNSResolutionPreferIPv6:
+		return nil
+	default:
+		return fmt.Errorf("unknown DNS resolution strategy %q, supported: ipv4, ipv6, prefer_ipv4, prefer_ipv6", string(s))
+	}
+}
+
+// FallbackScrapeEndpoint describes an alternative endpoint of a target that
+// is tried when scraping the primary endpoint fails.
+type FallbackScrapeEndpoint struct {
+	// MetricsPath overrides the target's metrics path. Empty keeps the
+	// target's path.
+	MetricsPath string `yaml:"metrics_path,omitempty"`
+	// Port overrides the port of the target's address. 0 keeps the target's
+	// port.
+	Port int `yaml:"port,omitempty"`
+}
+
+// Validate returns an error if the fallback scrape endpoint is invalid.
+func (e FallbackScrapeEndpoint) Validate() error {
+	if e.MetricsPath == "" && e.Port == 0 {
+		return errors.New("fallback scrape endpoint must set at least one of metrics_path and port")
+	}
+	if e.MetricsPath != "" && !strings.HasPrefix(e.MetricsPath, "/") {
+		return fmt.Errorf("fallback scrape endpoint metrics_path %q must start with '/'", e.MetricsPath)
+	}
+	if e.Port < 0 || e.Port > 65535 {
+		return fmt.Errorf("fallback scrape endpoint port %d is out of range", e.Port)
+	}
+	return nil
+}
+
+// SheddingPolicy controls how samples exceeding the configured sample rate
+// limit of a scrape config are handled.
+type SheddingPolicy string
+
+// The supported shedding policies. The empty policy is equivalent to
+// SheddingPolicyDrop.
+const (
+	// SheddingPolicyDrop sheds samples of series that were not seen in
+	// previous scrapes of the target, so already known series keep reporting.
+	SheddingPolicyDrop SheddingPolicy = "drop"
+	// SheddingPolicyFail fails the whole scrape, like sample_limit.
+	SheddingPolicyFail SheddingPolicy = "fail"
+	// SheddingPolicyDownsample sheds every other sample while the rate limit
+	// is exceeded, halving the ingested volume.
+	SheddingPolicyDownsample SheddingPolicy = "downsample"
+)
+
+// Validate returns an error if the given shedding policy is not supported.
+func (p SheddingPolicy) Validate() error {
+	switch p {
+	case "", SheddingPolicyDrop, SheddingPolicyFail, SheddingPolicyDownsample:
+		return nil
+	default:
+		return fmt.Errorf("unknown sample rate shedding policy %q, supported: drop, fail, downsample", string(p))
+	}
+}
+
+// StalenessMarkerPolicy controls when staleness markers are written for the
+// series of a scrape target that goes away.
+type StalenessMarkerPolicy string
+
+// The supported staleness marker policies. The empty policy is equivalent to
+// StalenessMarkerDelayed.
+const (
+	// StalenessMarkerDelayed writes staleness markers roughly two scrape
+	// intervals after the target went away, leaving room for the target to
+	// reappear under another scrape loop.
+	StalenessMarkerDelayed StalenessMarkerPolicy = "delayed"
+	// StalenessMarkerImmediate writes staleness markers as soon as the
+	// target is removed, useful for batch jobs and spot instances that are
+	// known not to come back.
+	StalenessMarkerImmediate StalenessMarkerPolicy = "immediate"
+	// StalenessMarkerNever disables end-of-life staleness markers entirely.
+	StalenessMarkerNever StalenessMarkerPolicy = "never"
+)
+
+// Validate returns an error if the given staleness marker policy is not
+// supported.
+func (p StalenessMarkerPolicy) Validate() error {
+	switch p {
+	case "", StalenessMarkerDelayed, StalenessMarkerImmediate, StalenessMarkerNever:
+		return nil
+	default:
+		return fmt.Errorf("unknown staleness marker policy %q, supported: delayed, immediate, never", string(p))
+	}
+}
+
 // validateAcceptScrapeProtocols return errors if we see problems with accept scrape protocols option.
 func validateAcceptScrapeProtocols(sps []ScrapeProtocol) error {
 	if len(sps) == 0 {
@@ -770,12 +911,43 @@ type ScrapeConfig struct {
 	HonorTimestamps bool `yaml:"honor_timestamps"`
 	// Indicator whether to track the staleness of the scraped timestamps.
 	TrackTimestampsStaleness bool `yaml:"track_timestamps_staleness"`
+	// When staleness markers for the series of a disappearing target are
+	// written. Supported values (case sensitive): delayed (the default),
+	// immediate, never.
+	StalenessMarkerPolicy StalenessMarkerPolicy `yaml:"staleness_marker_policy,omitempty"`
+	// The number of consecutive failed scrapes after which the series of a
+	// target are marked stale. 0 is equivalent to the default of 1, which
+	// writes staleness markers on the first failed scrape already; higher
+	// values keep series alive through transient failures.
+	StaleAfterFailedScrapes int `yaml:"stale_after_failed_scrapes,omitempty"`
 	// A set of query parameters with which the target is scraped.
 	Params url.Values `yaml:"params,omitempty"`
 	// How frequently to scrape the targets of this scrape config.
 	ScrapeInterval model.Duration `yaml:"scrape_interval,omitempty"`
 	// The timeout for scraping targets of this config.
 	ScrapeTimeout model.Duration `yaml:"scrape_timeout,omitempty"`
+	// The maximum interval to back off to for a repeatedly failing target.
+	// If set, the effective interval of a target is doubled after every
+	// failed scrape, up to this value, and restored to the configured
+	// scrape interval after the next successful scrape. 0 disables backoff.
+	MaxScrapeFailureBackoff model.Duration `yaml:"max_scrape_failure_backoff,omitempty"`
+	// The upper bound for the adaptive scrape timeout. If set, the timeout
+	// of a target adapts to its recent scrape durations: the 99th percentile
+	// of the observed durations plus a 50% margin, never below the configured
+	// scrape timeout and never above this value. This gives consistently slow
+	// targets more time without raising the scrape timeout for everyone.
+	// 0 disables the adaptation.
+	AdaptiveScrapeTimeout model.Duration `yaml:"adaptive_scrape_timeout,omitempty"`
+	// ScrapeAlignmentGroup, if non-empty, derives the scrape offset of all
+	// targets of this config from the group name instead of from the
+	// individual target, so that targets sharing a group are scraped at the
+	// same wall-clock offset. Can be overridden per target by relabeling.
+	ScrapeAlignmentGroup string `yaml:"scrape_alignment_group,omitempty"`
+	// ScrapeWithoutJitter disables the per-target scrape offset entirely so
+	// that scrapes happen at wall-clock multiples of the scrape interval.
+	// Mutually exclusive with ScrapeAlignmentGroup. Can be overridden per
+	// target by relabeling.
+	ScrapeWithoutJitter bool `yaml:"scrape_without_jitter,omitempty"`
 	// The protocols to negotiate during a scrape. It tells clients what
 	// protocol are accepted by Prometheus and with what preference (most wanted is first).
 	// Supported values (case sensitive): PrometheusProto, OpenMetricsText0.0.1,
@@ -792,20 +964,69 @@ type ScrapeConfig struct {
 	AlwaysScrapeClassicHistograms *bool `yaml:"always_scrape_classic_histograms,omitempty"`
 	// Whether to convert all scraped classic histograms into a native histogram with custom buckets.
 	ConvertClassicHistogramsToNHCB *bool `yaml:"convert_classic_histograms_to_nhcb,omitempty"`
+	// StrictExposition fails scrapes whose exposition violates the format
+	// conventions: duplicate metric families, metric families declared with
+	// inconsistent types, or counter samples without the _total suffix. The
+	// individual violations are reported in the target status.
+	StrictExposition bool `yaml:"strict_exposition,omitempty"`
 	// File to which scrape failures are logged.
 	ScrapeFailureLogFile string `yaml:"scrape_failure_log_file,omitempty"`
 	// The HTTP resource path on which to fetch metrics from targets.
 	MetricsPath string `yaml:"metrics_path,omitempty"`
+	// FallbackScrapeEndpoints is an ordered list of alternative endpoints
+	// that are tried, in order, when scraping the primary endpoint of a
+	// target fails. This covers migrations where the metrics endpoint moves
+	// to a different path or port without declaring duplicate targets.
+	FallbackScrapeEndpoints []FallbackScrapeEndpoint `yaml:"fallback_scrape_endpoints,omitempty"`
+	// The HTTP method with which to fetch metrics from targets. An empty
+	// value means GET.
+	Method string `yaml:"method,omitempty"`
+	// The HTTP request body sent when scraping targets. Only allowed for
+	// methods that take a body. Mutually exclusive with BodyFile.
+	Body string `yaml:"body,omitempty"`
+	// File to read the scrape request body from. Only allowed for methods
+	// that take a body. Mutually exclusive with Body.
+	BodyFile string `yaml:"body_file,omitempty"`
+	// RequestHeaders are additional HTTP headers sent with every scrape
+	// request. Values are expanded against the target's label set before the
+	// request is built: ${labelname} is replaced by the value of that label
+	// and $$ yields a literal $. Unlike http_headers, User-Agent may be set,
+	// replacing the default Prometheus user agent.
+	RequestHeaders map[string]string `yaml:"request_headers,omitempty"`
 	// The URL scheme with which to fetch metrics from targets.
 	Scheme string `yaml:"scheme,omitempty"`
+	// DNSResolutionStrategy restricts or orders the IP address families used
+	// when connecting to targets. If empty, connections use the Go default
+	// dual-stack behaviour.
+	DNSResolutionStrategy DNSResolutionStrategy `yaml:"dns_resolution_strategy,omitempty"`
+	// ReportNetworkMetrics emits per-target synthetic series for
+	// connection-level observations made while scraping: the expiry
+	// timestamp of the target's TLS certificate chain and the duration of
+	// the DNS lookup for the scrape connection. This allows alerting on
+	// expiring certificates without probing the same endpoints separately.
+	ReportNetworkMetrics bool `yaml:"report_network_metrics,omitempty"`
 	// Indicator whether to request compressed response from the target.
 	EnableCompression bool `yaml:"enable_compression"`
+	// UseConditionalRequests sends If-None-Match/If-Modified-Since headers
+	// when the target's previous response carried cache validators, and
+	// replays a retained copy of the last scrape body on a 304 Not Modified
+	// response. The retained body costs up to one response body of memory
+	// per target.
+	UseConditionalRequests bool `yaml:"use_conditional_requests,omitempty"`
 	// An uncompressed response body larger than this many bytes will cause the
 	// scrape to fail. 0 means no limit.
 	BodySizeLimit units.Base2Bytes `yaml:"body_size_limit,omitempty"`
 	// More than this many samples post metric-relabeling will cause the scrape to
 	// fail. 0 means no limit.
 	SampleLimit uint `yaml:"sample_limit,omitempty"`
+	// SampleRateLimit caps the average rate at which samples are ingested
+	// per target, in samples per second averaged over the scrape interval.
+	// Samples in excess of the rate are handled according to
+	// SampleRateSheddingPolicy. 0 means no limit.
+	SampleRateLimit float64 `yaml:"sample_rate_limit,omitempty"`
+	// SampleRateSheddingPolicy selects what happens to samples exceeding
+	// SampleRateLimit. Defaults to SheddingPolicyDrop.
+	SampleRateSheddingPolicy SheddingPolicy `yaml:"sample_rate_shedding_policy,omitempty"`
 	// More than this many targets after the target relabeling will cause the
 	// scrapes to fail. 0 means no limit.
 	TargetLimit uint `yaml:"target_limit,omitempty"`
@@ -824,6 +1045,11 @@ type ScrapeConfig struct {
 	// If the growth factor of one bucket to the next is smaller than this,
 	// buckets will be merged to increase the factor sufficiently.
 	NativeHistogramMinBucketFactor float64 `yaml:"native_histogram_min_bucket_factor,omitempty"`
+	// If set, ingested native histograms with a standard exponential schema
+	// higher than this are reduced to this schema before they are appended.
+	// Picking a fixed target schema avoids chunk churn when targets switch
+	// between resolutions. If unset, no fixed target schema is enforced.
+	NativeHistogramTargetSchema *int32 `yaml:"native_histogram_target_schema,omitempty"`
 	// Keep no more than this many dropped targets per job.
 	// 0 means no limit.
 	KeepDroppedTargets uint `yaml:"keep_dropped_targets,omitempty"`
@@ -857,6 +1083,7 @@ func (c *ScrapeConfig) SetDirectory(dir string) {
 	c.ServiceDiscoveryConfigs.SetDirectory(dir)
 	c.HTTPClientConfig.SetDirectory(dir)
 	c.ScrapeFailureLogFile = config.JoinDir(dir, c.ScrapeFailureLogFile)
+	c.BodyFile = config.JoinDir(dir, c.BodyFile)
 }
 
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
@@ -913,6 +1140,49 @@ func (c *ScrapeConfig) Validate(globalConfig GlobalConfig) error {
 	if c.ScrapeTimeout == 0 {
 		c.ScrapeTimeout = min(globalConfig.ScrapeTimeout, c.ScrapeInterval)
 	}
+	if c.MaxScrapeFailureBackoff != 0 && c.MaxScrapeFailureBackoff < c.ScrapeInterval {
+		return fmt.Errorf("max scrape failure backoff smaller than scrape interval for scrape config with job name %q", c.JobName)
+	}
+	if c.AdaptiveScrapeTimeout != 0 {
+		if c.AdaptiveScrapeTimeout < c.ScrapeTimeout {
+			return fmt.Errorf("adaptive scrape timeout smaller than scrape timeout for scrape config with job name %q", c.JobName)
+		}
+		if c.AdaptiveScrapeTimeout > c.ScrapeInterval {
+			return fmt.Errorf("adaptive scrape timeout greater than scrape interval for scrape config with job name %q", c.JobName)
+		}
+	}
+	if err := c.StalenessMarkerPolicy.Validate(); err != nil {
+		return fmt.Errorf("%w for scrape config with job name %q", err, c.JobName)
+	}
+	if c.StaleAfterFailedScrapes < 0 {
+		return fmt.Errorf("stale_after_failed_scrapes cannot be negative for scrape config with job name %q", c.JobName)
+	}
+	c.Method = strings.ToUpper(c.Method)
+	switch c.Method {
+	case "", "GET", "POST", "PUT":
+	default:
+		return fmt.Errorf("invalid scrape method %q for scrape config with job name %q", c.Method, c.JobName)
+	}
+	if c.Body != "" && c.BodyFile != "" {
+		return fmt.Errorf("at most one of body and body_file must be configured for scrape config with job name %q", c.JobName)
+	}
+	if c.ScrapeAlignmentGroup != "" && c.ScrapeWithoutJitter {
+		return fmt.Errorf("at most one of scrape_alignment_group and scrape_without_jitter must be configured for scrape config with job name %q", c.JobName)
+	}
+	if (c.Body != "" || c.BodyFile != "") && (c.Method == "" || c.Method == "GET") {
+		return fmt.Errorf("scrape request body requires the method to be POST or PUT for scrape config with job name %q", c.JobName)
+	}
+	for name := range c.RequestHeaders {
+		canonical := http.CanonicalHeaderKey(name)
+		// User-Agent is deliberately allowed so that jobs can identify
+		// themselves to gateways in front of the targets.
+		if canonical == "User-Agent" {
+			continue
+		}
+		if _, reserved := config.ReservedHeaders[canonical]; reserved {
+			return fmt.Errorf("setting scrape request header %q is not allowed for scrape config with job name %q", canonical, c.JobName)
+		}
+	}
 	if c.BodySizeLimit == 0 {
 		c.BodySizeLimit = globalConfig.BodySizeLimit
 	}
@@ -968,6 +1238,30 @@ func (c *ScrapeConfig) Validate(globalConfig GlobalConfig) error {
 		}
 	}
 
+	if err := c.DNSResolutionStrategy.Validate(); err != nil {
+		return fmt.Errorf("invalid dns_resolution_strategy for scrape config with job name %q: %w", c.JobName, err)
+	}
+
+	for _, ep := range c.FallbackScrapeEndpoints {
+		if err := ep.Validate(); err != nil {
+			return fmt.Errorf("%w for scrape config with job name %q", err, c.JobName)
+		}
+	}
+
+	if c.SampleRateLimit < 0 {
+		return fmt.Errorf("sample_rate_limit must not be negative for scrape config with job name %q", c.JobName)
+	}
+	if err := c.SampleRateSheddingPolicy.Validate(); err != nil {
+		return fmt.Errorf("invalid sample_rate_shedding_policy for scrape config with job name %q: %w", c.JobName, err)
+	}
+	if c.SampleRateSheddingPolicy != "" && c.SampleRateLimit == 0 {
+		return fmt.Errorf("sample_rate_shedding_policy requires sample_rate_limit to be set for scrape config with job name %q", c.JobName)
+	}
+
+	if s := c.NativeHistogramTargetSchema; s != nil && (*s < histogram.ExponentialSchemaMin || *s > histogram.ExponentialSchemaMax) {
+		return fmt.Errorf("native_histogram_target_schema must be between %d and %d for scrape config with job name %q, got %d", histogram.ExponentialSchemaMin, histogram.ExponentialSchemaMax, c.JobName, *s)
+	}
+
 	//nolint:staticcheck
 	if model.NameValidationScheme != model.UTF8Validation {
 		return errors.New("model.NameValidationScheme must be set to UTF8")
@@ -1112,6 +1406,84 @@ type TSDBRetentionConfig struct {
 
 	// Maximum percentage of disk used for TSDB storage.
 	Percentage float64 `yaml:"percentage,omitempty"`
+
+	// Per-selector retention policies overriding the global retention time
+	// for matching series.
+	Policies []RetentionPolicyConfig `yaml:"policies,omitempty"`
+
+	// LabelPolicies configure label names to drop from series of blocks older
+	// than a threshold during compaction, reducing long-term cardinality.
+	LabelPolicies []LabelRetentionPolicyConfig `yaml:"label_policies,omitempty"`
+}
+
+// RetentionPolicyConfig configures how long samples of series matching a
+// selector are retained in storage.
+type RetentionPolicyConfig struct {
+	// Selector is a series selector identifying the series the policy applies to.
+	Selector string `yaml:"selector"`
+
+	// Time is how long to retain samples of matching series.
+	Time model.Duration `yaml:"time"`
+
+	// Matchers holds the parsed Selector. This is set while unmarshaling the
+	// configuration file and should not be set directly.
+	Matchers []*labels.Matcher `yaml:"-"`
+}
+
+// LabelRetentionPolicyConfig configures label names to drop from all series
+// once blocks reach a certain age. Series that become identical after the
+// labels are removed are merged during compaction.
+type LabelRetentionPolicyConfig struct {
+	// Labels are the label names to drop.
+	Labels []string `yaml:"labels"`
+
+	// After is the minimum block age before the labels are dropped.
+	After model.Duration `yaml:"after"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (r *LabelRetentionPolicyConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	*r = LabelRetentionPolicyConfig{}
+	type plain LabelRetentionPolicyConfig
+	if err := unmarshal((*plain)(r)); err != nil {
+		return err
+	}
+	if len(r.Labels) == 0 {
+		return errors.New("label retention policy requires at least one label name")
+	}
+	for _, l := range r.Labels {
+		if l == model.MetricNameLabel {
+			return fmt.Errorf("label retention policy must not drop the %s label", model.MetricNameLabel)
+		}
+		if !model.LabelName(l).IsValid() {
+			return fmt.Errorf("invalid label name %q in label retention policy", l)
+		}
+	}
+	if r.After <= 0 {
+		return errors.New("label retention policy requires a positive 'after'")
+	}
+	return nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (r *RetentionPolicyConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	*r = RetentionPolicyConfig{}
+	type plain RetentionPolicyConfig
+	if err := unmarshal((*plain)(r)); err != nil {
+		return err
+	}
+	if r.Selector == "" {
+		return errors.New("retention policy requires a 'selector'")
+	}
+	if r.Time <= 0 {
+		return errors.New("retention policy requires a positive 'time'")
+	}
+	matchers, err := parser.NewParser(parser.Options{}).ParseMetricSelector(r.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid retention policy selector %q: %w", r.Selector, err)
+	}
+	r.Matchers = matchers
+	return nil
 }
 
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
@@ -1158,6 +1530,11 @@ type TSDBConfig struct {
 	// This should not be used directly and must be converted into OutOfOrderTimeWindow.
 	OutOfOrderTimeWindowFlag model.Duration `yaml:"out_of_order_time_window,omitempty"`
 
+	// OutOfOrderTimeWindowOverrides are per-selector overrides of the
+	// out-of-order time window, so the memory overhead of out-of-order chunks
+	// can be limited to the series that need them.
+	OutOfOrderTimeWindowOverrides []OOOTimeWindowOverrideConfig `yaml:"out_of_order_time_window_overrides,omitempty"`
+
 	// StaleSeriesCompactionThreshold is a number between 0.0-1.0 indicating the % of stale series in
 	// the in-memory Head block. If the % of stale series crosses this threshold, stale series compaction is run immediately.
 	StaleSeriesCompactionThreshold float64 `yaml:"stale_series_compaction_threshold,omitempty"`
@@ -1168,6 +1545,47 @@ type TSDBConfig struct {
 	Retention *TSDBRetentionConfig `yaml:"retention,omitempty"`
 }
 
+// OOOTimeWindowOverrideConfig overrides the out-of-order time window for
+// series matching a selector.
+type OOOTimeWindowOverrideConfig struct {
+	// Selector is a series selector identifying the series the override applies to.
+	Selector string `yaml:"selector"`
+
+	// TimeWindowFlag holds the parsed duration from the config file.
+	// During unmarshall, this is converted into milliseconds and stored in TimeWindow.
+	// This should not be used directly and must be converted into TimeWindow.
+	TimeWindowFlag model.Duration `yaml:"time_window"`
+
+	// TimeWindow is TimeWindowFlag converted into milliseconds.
+	TimeWindow int64
+
+	// Matchers holds the parsed Selector. This is set while unmarshaling the
+	// configuration file and should not be set directly.
+	Matchers []*labels.Matcher `yaml:"-"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (o *OOOTimeWindowOverrideConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	*o = OOOTimeWindowOverrideConfig{}
+	type plain OOOTimeWindowOverrideConfig
+	if err := unmarshal((*plain)(o)); err != nil {
+		return err
+	}
+	if o.Selector == "" {
+		return errors.New("out-of-order time window override requires a 'selector'")
+	}
+	if o.TimeWindowFlag < 0 {
+		return errors.New("'time_window' must not be negative")
+	}
+	matchers, err := parser.NewParser(parser.Options{}).ParseMetricSelector(o.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid out-of-order time window override selector %q: %w", o.Selector, err)
+	}
+	o.Matchers = matchers
+	o.TimeWindow = time.Duration(o.TimeWindowFlag).Milliseconds()
+	return nil
+}
+
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
 func (t *TSDBConfig) UnmarshalYAML(unmarshal func(any) error) error {
 	*t = TSDBConfig{}
@@ -1487,19 +1905,44 @@ type RemoteWriteConfig struct {
 	Name                 string            `yaml:"name,omitempty"`
 	SendExemplars        bool              `yaml:"send_exemplars,omitempty"`
 	SendNativeHistograms bool              `yaml:"send_native_histograms,omitempty"`
-	RoundRobinDNS        bool              `yaml:"round_robin_dns,omitempty"`
+	// Whether to forward staleness markers to the remote endpoint. Disable
+	// this for backends that misinterpret the NaN marker values.
+	SendStalenessMarkers bool `yaml:"send_staleness_markers,omitempty"`
+	RoundRobinDNS        bool `yaml:"round_robin_dns,omitempty"`
 	// ProtobufMessage specifies the protobuf message to use against the remote
 	// receiver as specified in https://prometheus.io/docs/specs/remote_write_spec_2_0/
 	ProtobufMessage remoteapi.WriteMessageType `yaml:"protobuf_message,omitempty"`
+	// Compression is the content encoding used for write requests. If the
+	// receiver rejects it, the sender falls back to snappy, which every
+	// receiver has to support.
+	Compression compression.Type `yaml:"compression,omitempty"`
+	// Primary marks this endpoint as the one whose delivery progress gates
+	// WAL truncation in agent mode. Endpoints not marked as primary are
+	// best-effort: they no longer hold back truncation and may lose data if
+	// they fall too far behind. At most one remote write config may be
+	// primary. Has no effect outside agent mode. Mutually exclusive with
+	// replication.
+	Primary bool `yaml:"primary,omitempty"`
+	// Replication groups this endpoint with all others that share the same
+	// group name. Data is considered durable, and eligible for WAL
+	// truncation in agent mode, once the group's quorum of endpoints has
+	// acknowledged it; the remaining members catch up from the WAL on a
+	// best-effort basis. Mutually exclusive with primary.
+	Replication *ReplicationConfig `yaml:"replication,omitempty"`
 
 	// We cannot do proper Go type embedding below as the parser will then parse
 	// values arbitrarily into the overflow maps of further-down types.
 	HTTPClientConfig config.HTTPClientConfig `yaml:",inline"`
 	QueueConfig      QueueConfig             `yaml:"queue_config,omitempty"`
 	MetadataConfig   MetadataConfig          `yaml:"metadata_config,omitempty"`
+	SpillBuffer      SpillBufferConfig       `yaml:"spill_buffer,omitempty"`
 	SigV4Config      *sigv4.SigV4Config      `yaml:"sigv4,omitempty"`
 	AzureADConfig    *azuread.AzureADConfig  `yaml:"azuread,omitempty"`
 	GoogleIAMConfig  *googleiam.Config       `yaml:"google_iam,omitempty"`
+	// KafkaConfig, when set, publishes write requests to a Kafka topic
+	// instead of sending them to an HTTP receiver. Mutually exclusive
+	// with url.
+	KafkaConfig *kafka.Config `yaml:"kafka,omitempty"`
 }
 
 // SetDirectory joins any relative file paths with dir.
@@ -1514,9 +1957,23 @@ func (c *RemoteWriteConfig) UnmarshalYAML(unmarshal func(any) error) error {
 	if err := unmarshal((*plain)(c)); err != nil {
 		return err
 	}
-	if c.URL == nil {
+	if c.URL == nil && c.KafkaConfig == nil {
 		return errors.New("url for remote_write is empty")
 	}
+	if c.URL != nil && c.KafkaConfig != nil {
+		return errors.New("at most one of url and kafka must be configured for remote_write")
+	}
+	if c.KafkaConfig != nil {
+		if err := c.KafkaConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid kafka config for remote_write: %w", err)
+		}
+		if c.ProtobufMessage != remoteapi.WriteV1MessageType {
+			return errors.New("kafka transport for remote_write only supports the prometheus.WriteRequest protobuf message")
+		}
+		if c.Compression != compression.Snappy {
+			return errors.New("kafka transport for remote_write only supports snappy compression")
+		}
+	}
 	for _, rlcfg := range c.WriteRelabelConfigs {
 		if rlcfg == nil {
 			return errors.New("empty or null relabeling rule in remote write config")
@@ -1530,6 +1987,21 @@ func (c *RemoteWriteConfig) UnmarshalYAML(unmarshal func(any) error) error {
 		return fmt.Errorf("invalid protobuf_message value: %w", err)
 	}
 
+	switch c.Compression {
+	case compression.None, compression.Snappy, compression.Zstd:
+	default:
+		return fmt.Errorf("invalid compression value %q for remote write, supported: %v", c.Compression, strings.Join(compression.Types(), ", "))
+	}
+
+	if c.Replication != nil {
+		if c.Primary {
+			return errors.New("remote write config cannot be both primary and part of a replication group")
+		}
+		if err := c.Replication.Validate(); err != nil {
+			return err
+		}
+	}
+
 	// The UnmarshalYAML method of HTTPClientConfig is not being called because it's not a pointer.
 	// We cannot make it a pointer as the parser panics for inlined pointer structs.
 	// Thus we just do its validation here.
@@ -1541,6 +2013,10 @@ func (c *RemoteWriteConfig) UnmarshalYAML(unmarshal func(any) error) error {
 		return err
 	}
 
+	if err := c.SpillBuffer.Validate(); err != nil {
+		return err
+	}
+
 	return validateAuthConfigs(c)
 }
 
@@ -1605,6 +2081,30 @@ func validateHeaders(headers map[string]string) error {
 	return nil
 }
 
+// ReplicationConfig assigns a remote write endpoint to a named replication
+// group with a quorum. All members of a group must declare the same quorum,
+// and the quorum cannot exceed the number of members; both are enforced when
+// the full configuration is loaded.
+type ReplicationConfig struct {
+	// Group is the name of the replication group this endpoint belongs to.
+	Group string `yaml:"group"`
+	// Quorum is the number of endpoints in the group that must acknowledge
+	// a write before it is considered durable.
+	Quorum int `yaml:"quorum"`
+}
+
+// Validate checks the replication config for errors that can be detected on
+// a single endpoint.
+func (c *ReplicationConfig) Validate() error {
+	if c.Group == "" {
+		return errors.New("replication group name for remote write is empty")
+	}
+	if c.Quorum < 1 {
+		return fmt.Errorf("replication quorum for remote write must be at least 1, got %d", c.Quorum)
+	}
+	return nil
+}
+
 // QueueConfig is the configuration for the queue used to write to remote
 // storage.
 type QueueConfig struct {
@@ -1631,6 +2131,18 @@ type QueueConfig struct {
 
 	// Samples older than the limit will be dropped.
 	SampleAgeLimit model.Duration `yaml:"sample_age_limit,omitempty"`
+
+	// DropOnBackpressure lists the data types that are shed instead of
+	// retried indefinitely when the shard queues are full, so that e.g.
+	// exemplars can be sacrificed to keep samples flowing. Valid values:
+	// "samples", "exemplars", "histograms". Metadata is not queued and
+	// therefore has no policy.
+	DropOnBackpressure []string `yaml:"drop_on_backpressure,omitempty"`
+
+	// Target end-to-end delivery latency. When set, the shard calculation
+	// scales the queue to keep the delivery delay within this duration
+	// instead of using the fixed backlog catch-up rate. 0 disables it.
+	DeliveryLatencySLO model.Duration `yaml:"delivery_latency_slo,omitempty"`
 }
 
 // Validate checks QueueConfig fields for invalid values.
@@ -1653,6 +2165,48 @@ func (c *QueueConfig) Validate() error {
 	if c.MaxBackoff < c.MinBackoff {
 		return errors.New("remote write queue max_backoff must not be less than min_backoff")
 	}
+	seen := map[string]struct{}{}
+	for _, dt := range c.DropOnBackpressure {
+		switch dt {
+		case "samples", "exemplars", "histograms":
+		default:
+			return fmt.Errorf("remote write queue drop_on_backpressure contains unknown data type %q", dt)
+		}
+		if _, ok := seen[dt]; ok {
+			return fmt.Errorf("remote write queue drop_on_backpressure lists data type %q twice", dt)
+		}
+		seen[dt] = struct{}{}
+	}
+	return nil
+}
+
+// SpillBufferConfig configures an optional on-disk retry buffer for write
+// requests that cannot be delivered while the remote endpoint is down.
+// Spilled requests survive WAL truncation and restarts and are replayed in
+// the background once the endpoint recovers. The buffer is enabled by setting
+// a non-zero max_size.
+type SpillBufferConfig struct {
+	// MaxSize is the disk quota for spilled requests per remote. When the
+	// quota is exceeded, the oldest spilled requests are dropped. 0 disables
+	// the spill buffer.
+	MaxSize units.Base2Bytes `yaml:"max_size,omitempty"`
+
+	// MaxAge is how long a spilled request is kept before it is dropped.
+	// 0 means spilled requests are only dropped due to the size quota.
+	MaxAge model.Duration `yaml:"max_age,omitempty"`
+}
+
+// Validate checks SpillBufferConfig fields for invalid values.
+func (c *SpillBufferConfig) Validate() error {
+	if c.MaxSize < 0 {
+		return errors.New("remote write spill_buffer max_size must not be negative")
+	}
+	if c.MaxAge < 0 {
+		return errors.New("remote write spill_buffer max_age must not be negative")
+	}
+	if c.MaxAge > 0 && c.MaxSize == 0 {
+		return errors.New("remote write spill_buffer max_age requires max_size to be set")
+	}
 	return nil
 }
 
@@ -1748,9 +2302,12 @@ func getGoGC() int {
 
 // OTLPConfig is the configuration for writing to the OTLP endpoint.
 type OTLPConfig struct {
-	PromoteAllResourceAttributes      bool                                     `yaml:"promote_all_resource_attributes,omitempty"`
-	PromoteResourceAttributes         []string                                 `yaml:"promote_resource_attributes,omitempty"`
-	IgnoreResourceAttributes          []string                                 `yaml:"ignore_resource_attributes,omitempty"`
+	PromoteAllResourceAttributes bool     `yaml:"promote_all_resource_attributes,omitempty"`
+	PromoteResourceAttributes    []string `yaml:"promote_resource_attributes,omitempty"`
+	IgnoreResourceAttributes     []string `yaml:"ignore_resource_attributes,omitempty"`
+	// AttributePromotionRules overrides the promotion settings above for
+	// resources matching a rule's conditions. The first matching rule wins.
+	AttributePromotionRules           []AttributePromotionRule                 `yaml:"attribute_promotion_rules,omitempty"`
 	TranslationStrategy               otlptranslator.TranslationStrategyOption `yaml:"translation_strategy,omitempty"`
 	KeepIdentifyingResourceAttributes bool                                     `yaml:"keep_identifying_resource_attributes,omitempty"`
 	ConvertHistogramsToNHCB           bool                                     `yaml:"convert_histograms_to_nhcb,omitempty"`
@@ -1767,6 +2324,38 @@ type OTLPConfig struct {
 	LabelNamePreserveMultipleUnderscores bool `yaml:"label_name_preserve_multiple_underscores,omitempty"`
 }
 
+// AttributePromotionRule overrides how OTLP resource and scope attributes are
+// promoted for resources matching a set of conditions. A matching rule
+// replaces the top-level promotion settings entirely for that resource.
+type AttributePromotionRule struct {
+	// Match maps resource attribute names to anchored regular expressions. A
+	// resource matches when every listed attribute is present and its value
+	// matches the expression. An empty map matches every resource.
+	Match map[string]relabel.Regexp `yaml:"match,omitempty"`
+
+	PromoteAllResourceAttributes bool     `yaml:"promote_all_resource_attributes,omitempty"`
+	PromoteResourceAttributes    []string `yaml:"promote_resource_attributes,omitempty"`
+	IgnoreResourceAttributes     []string `yaml:"ignore_resource_attributes,omitempty"`
+	// PromoteScopeMetadata overrides the top-level promote_scope_metadata
+	// setting for matching resources. When unset, the top-level setting
+	// applies.
+	PromoteScopeMetadata *bool `yaml:"promote_scope_metadata,omitempty"`
+	// DisableTargetInfo suppresses target_info generation for matching
+	// resources.
+	DisableTargetInfo bool `yaml:"disable_target_info,omitempty"`
+}
+
+// Validate checks the rule for mutually exclusive settings and malformed
+// attribute names.
+func (r *AttributePromotionRule) Validate() error {
+	for name := range r.Match {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("empty matched OTel resource attribute name")
+		}
+	}
+	return validatePromotedAttributes(r.PromoteAllResourceAttributes, r.PromoteResourceAttributes, r.IgnoreResourceAttributes)
+}
+
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
 func (c *OTLPConfig) UnmarshalYAML(unmarshal func(any) error) error {
 	*c = DefaultOTLPConfig
@@ -1775,22 +2364,35 @@ func (c *OTLPConfig) UnmarshalYAML(unmarshal func(any) error) error {
 		return err
 	}
 
-	if c.PromoteAllResourceAttributes {
-		if len(c.PromoteResourceAttributes) > 0 {
+	if err := validatePromotedAttributes(c.PromoteAllResourceAttributes, c.PromoteResourceAttributes, c.IgnoreResourceAttributes); err != nil {
+		return err
+	}
+
+	for i := range c.AttributePromotionRules {
+		if err := c.AttributePromotionRules[i].Validate(); err != nil {
+			return fmt.Errorf("invalid 'attribute_promotion_rules' rule %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func validatePromotedAttributes(promoteAll bool, promoted, ignored []string) error {
+	if promoteAll {
+		if len(promoted) > 0 {
 			return errors.New("'promote_all_resource_attributes' and 'promote_resource_attributes' cannot be configured simultaneously")
 		}
-		if err := sanitizeAttributes(c.IgnoreResourceAttributes, "ignored"); err != nil {
+		if err := sanitizeAttributes(ignored, "ignored"); err != nil {
 			return fmt.Errorf("invalid 'ignore_resource_attributes': %w", err)
 		}
 	} else {
-		if len(c.IgnoreResourceAttributes) > 0 {
+		if len(ignored) > 0 {
 			return errors.New("'ignore_resource_attributes' cannot be configured unless 'promote_all_resource_attributes' is true")
 		}
-		if err := sanitizeAttributes(c.PromoteResourceAttributes, "promoted"); err != nil {
+		if err := sanitizeAttributes(promoted, "promoted"); err != nil {
 			return fmt.Errorf("invalid 'promote_resource_attributes': %w", err)
 		}
 	}
-
 	return nil
 }
 
@@ -56,6 +56,7 @@ import (
 	"github.com/prometheus/prometheus/discovery/ovhcloud"
 	"github.com/prometheus/prometheus/discovery/puppetdb"
 	"github.com/prometheus/prometheus/discovery/scaleway"
+	promsql "github.com/prometheus/prometheus/discovery/sql"
 	"github.com/prometheus/prometheus/discovery/stackit"
 	"github.com/prometheus/prometheus/discovery/targetgroup"
 	"github.com/prometheus/prometheus/discovery/triton"
@@ -65,9 +66,14 @@ import (
 	"github.com/prometheus/prometheus/discovery/zookeeper"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/prometheus/prometheus/util/compression"
 	"github.com/prometheus/prometheus/util/testutil"
 )
 
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
 func mustParseURL(u string) *config.URL {
 	parsed, err := url.Parse(u)
 	if err != nil {
@@ -122,10 +128,13 @@ var expectedConf = &Config{
 
 	RemoteWriteConfigs: []*RemoteWriteConfig{
 		{
-			URL:             mustParseURL("http://remote1/push"),
-			ProtobufMessage: remoteapi.WriteV1MessageType,
-			RemoteTimeout:   model.Duration(30 * time.Second),
-			Name:            "drop_expensive",
+			URL:                  mustParseURL("http://remote1/push"),
+			ProtobufMessage:      remoteapi.WriteV1MessageType,
+			Compression:          compression.Zstd,
+			Primary:              true,
+			RemoteTimeout:        model.Duration(30 * time.Second),
+			SendStalenessMarkers: true,
+			Name:                 "drop_expensive",
 			WriteRelabelConfigs: []*relabel.Config{
 				{
 					SourceLabels:         model.LabelNames{"__name__"},
@@ -153,12 +162,18 @@ var expectedConf = &Config{
 			},
 		},
 		{
-			URL:             mustParseURL("http://remote2/push"),
-			ProtobufMessage: remoteapi.WriteV2MessageType,
-			RemoteTimeout:   model.Duration(30 * time.Second),
-			QueueConfig:     DefaultQueueConfig,
-			MetadataConfig:  DefaultMetadataConfig,
-			Name:            "rw_tls",
+			URL:                  mustParseURL("http://remote2/push"),
+			ProtobufMessage:      remoteapi.WriteV2MessageType,
+			Compression:          compression.Snappy,
+			RemoteTimeout:        model.Duration(30 * time.Second),
+			SendStalenessMarkers: true,
+			QueueConfig:          DefaultQueueConfig,
+			MetadataConfig:       DefaultMetadataConfig,
+			SpillBuffer: SpillBufferConfig{
+				MaxSize: 512 * units.MiB,
+				MaxAge:  model.Duration(12 * time.Hour),
+			},
+			Name: "rw_tls",
 			HTTPClientConfig: config.HTTPClientConfig{
 				TLSConfig: config.TLSConfig{
 					CertFile: filepath.FromSlash("testdata/valid_cert_file"),
@@ -229,6 +244,8 @@ var expectedConf = &Config{
 			LabelValueLengthLimit:          globLabelValueLengthLimit,
 			ScrapeProtocols:                DefaultScrapeProtocols,
 			ScrapeFallbackProtocol:         PrometheusText0_0_4,
+			DNSResolutionStrategy:          DNSResolutionPreferIPv4,
+			NativeHistogramTargetSchema:    int32Ptr(3),
 			ScrapeFailureLogFile:           "testdata/fail_prom.log",
 			MetricNameValidationScheme:     DefaultGlobalConfig.MetricNameValidationScheme,
 			MetricNameEscapingScheme:       DefaultGlobalConfig.MetricNameEscapingScheme,
@@ -381,6 +398,7 @@ var expectedConf = &Config{
 					},
 					RefreshInterval: model.Duration(15 * time.Second),
 					Type:            "SRV",
+					Timeout:         model.Duration(5 * time.Second),
 				},
 				&dns.SDConfig{
 					Names: []string{
@@ -388,6 +406,7 @@ var expectedConf = &Config{
 					},
 					RefreshInterval: model.Duration(30 * time.Second),
 					Type:            "SRV",
+					Timeout:         model.Duration(5 * time.Second),
 				},
 			},
 
@@ -1794,6 +1813,43 @@ var expectedConf = &Config{
 				},
 			},
 		},
+		{
+			JobName: "sql",
+
+			HonorTimestamps:                true,
+			ScrapeInterval:                 model.Duration(15 * time.Second),
+			ScrapeTimeout:                  DefaultGlobalConfig.ScrapeTimeout,
+			EnableCompression:              true,
+			BodySizeLimit:                  globBodySizeLimit,
+			SampleLimit:                    globSampleLimit,
+			TargetLimit:                    globTargetLimit,
+			LabelLimit:                     globLabelLimit,
+			LabelNameLengthLimit:           globLabelNameLengthLimit,
+			LabelValueLengthLimit:          globLabelValueLengthLimit,
+			ScrapeProtocols:                DefaultScrapeProtocols,
+			ScrapeFailureLogFile:           globScrapeFailureLogFile,
+			MetricNameValidationScheme:     DefaultGlobalConfig.MetricNameValidationScheme,
+			MetricNameEscapingScheme:       DefaultGlobalConfig.MetricNameEscapingScheme,
+			ScrapeNativeHistograms:         boolPtr(false),
+			AlwaysScrapeClassicHistograms:  boolPtr(false),
+			ConvertClassicHistogramsToNHCB: boolPtr(false),
+			ExtraScrapeMetrics:             boolPtr(false),
+
+			MetricsPath:      DefaultScrapeConfig.MetricsPath,
+			Scheme:           DefaultScrapeConfig.Scheme,
+			HTTPClientConfig: config.DefaultHTTPClientConfig,
+
+			ServiceDiscoveryConfigs: discovery.Configs{
+				&promsql.SDConfig{
+					Driver:             "mysql",
+					DataSourceName:     "user:password@tcp(localhost:3306)/inventory",
+					Query:              "SELECT address, env FROM targets",
+					RefreshInterval:    model.Duration(60 * time.Second),
+					MaxOpenConnections: 2,
+					MaxIdleConnections: 2,
+				},
+			},
+		},
 	},
 	AlertingConfig: AlertingConfig{
 		AlertmanagerConfigs: []*AlertmanagerConfig{
@@ -1826,6 +1882,12 @@ var expectedConf = &Config{
 				Time:       model.Duration(24 * time.Hour),
 				Size:       1 * units.GiB,
 				Percentage: 28,
+				LabelPolicies: []LabelRetentionPolicyConfig{
+					{
+						Labels: []string{"pod", "instance"},
+						After:  model.Duration(30 * 24 * time.Hour),
+					},
+				},
 			},
 		},
 	},
@@ -1876,6 +1938,22 @@ func TestRemoteWriteRetryOnRateLimit(t *testing.T) {
 	require.False(t, got.RemoteWriteConfigs[1].QueueConfig.RetryOnRateLimit)
 }
 
+func TestRemoteWriteReplication(t *testing.T) {
+	want, err := LoadFile("testdata/remote_write_replication.good.yml", false, promslog.NewNopLogger())
+	require.NoError(t, err)
+
+	out, err := yaml.Marshal(want)
+	require.NoError(t, err)
+
+	got, err := Load(string(out), promslog.NewNopLogger())
+	require.NoError(t, err)
+
+	for i := range 3 {
+		require.Equal(t, &ReplicationConfig{Group: "eu", Quorum: 2}, got.RemoteWriteConfigs[i].Replication)
+	}
+	require.Nil(t, got.RemoteWriteConfigs[3].Replication)
+}
+
 func TestOTLPSanitizeResourceAttributes(t *testing.T) {
 	t.Run("good config - default resource attributes", func(t *testing.T) {
 		want, err := LoadFile(filepath.Join("testdata", "otlp_sanitize_default_resource_attributes.good.yml"), false, promslog.NewNopLogger())
@@ -1956,6 +2034,37 @@ func TestOTLPSanitizeResourceAttributes(t *testing.T) {
 	})
 }
 
+func TestOTLPAttributePromotionRules(t *testing.T) {
+	t.Run("good config", func(t *testing.T) {
+		want, err := LoadFile(filepath.Join("testdata", "otlp_attribute_promotion_rules.good.yml"), false, promslog.NewNopLogger())
+		require.NoError(t, err)
+
+		out, err := yaml.Marshal(want)
+		require.NoError(t, err)
+		var got Config
+		require.NoError(t, yaml.UnmarshalStrict(out, &got))
+
+		require.Len(t, got.OTLPConfig.AttributePromotionRules, 2)
+		first := got.OTLPConfig.AttributePromotionRules[0]
+		require.Equal(t, []string{"k8s.pod.name", "k8s.namespace.name"}, first.PromoteResourceAttributes)
+		require.True(t, first.DisableTargetInfo)
+		require.Nil(t, first.PromoteScopeMetadata)
+		require.True(t, first.Match["service.name"].MatchString("billing-frontend"))
+		require.False(t, first.Match["service.name"].MatchString("checkout"))
+		second := got.OTLPConfig.AttributePromotionRules[1]
+		require.True(t, second.PromoteAllResourceAttributes)
+		require.Equal(t, []string{"process.pid"}, second.IgnoreResourceAttributes)
+		require.NotNil(t, second.PromoteScopeMetadata)
+		require.False(t, *second.PromoteScopeMetadata)
+	})
+
+	t.Run("bad config - conflicting promotion settings in a rule", func(t *testing.T) {
+		_, err := LoadFile(filepath.Join("testdata", "otlp_attribute_promotion_rules.bad.yml"), false, promslog.NewNopLogger())
+		require.ErrorContains(t, err, `invalid 'attribute_promotion_rules' rule 0`)
+		require.ErrorContains(t, err, `'promote_all_resource_attributes' and 'promote_resource_attributes' cannot be configured simultaneously`)
+	})
+}
+
 func TestOTLPAllowServiceNameInTargetInfo(t *testing.T) {
 	t.Run("good config", func(t *testing.T) {
 		want, err := LoadFile(filepath.Join("testdata", "otlp_allow_keep_identifying_resource_attributes.good.yml"), false, promslog.NewNopLogger())
@@ -2202,7 +2311,7 @@ func TestElideSecrets(t *testing.T) {
 	yamlConfig := string(config)
 
 	matches := secretRe.FindAllStringIndex(yamlConfig, -1)
-	require.Len(t, matches, 29, "wrong number of secret matches found")
+	require.Len(t, matches, 30, "wrong number of secret matches found")
 	require.NotContains(t, yamlConfig, "mysecret",
 		"yaml marshal reveals authentication credentials.")
 }
@@ -2253,6 +2362,54 @@ var expectedErrors = []struct {
 		filename: "scrape_interval.bad.yml",
 		errMsg:   `scrape timeout greater than scrape interval`,
 	},
+	{
+		filename: "scrape_failure_backoff.bad.yml",
+		errMsg:   `max scrape failure backoff smaller than scrape interval`,
+	},
+	{
+		filename: "scrape_adaptive_timeout.bad.yml",
+		errMsg:   `adaptive scrape timeout smaller than scrape timeout`,
+	},
+	{
+		filename: "scrape_method.bad.yml",
+		errMsg:   `invalid scrape method "DELETE"`,
+	},
+	{
+		filename: "scrape_body_and_body_file.bad.yml",
+		errMsg:   `at most one of body and body_file must be configured`,
+	},
+	{
+		filename: "scrape_alignment_group_without_jitter.bad.yml",
+		errMsg:   `at most one of scrape_alignment_group and scrape_without_jitter must be configured`,
+	},
+	{
+		filename: "scrape_body_method.bad.yml",
+		errMsg:   `scrape request body requires the method to be POST or PUT`,
+	},
+	{
+		filename: "scrape_request_header_reserved.bad.yml",
+		errMsg:   `setting scrape request header "Authorization" is not allowed`,
+	},
+	{
+		filename: "scrape_fallback_endpoint.bad.yml",
+		errMsg:   `fallback scrape endpoint must set at least one of metrics_path and port`,
+	},
+	{
+		filename: "scrape_sample_rate_shedding_policy.bad.yml",
+		errMsg:   `unknown sample rate shedding policy "explode"`,
+	},
+	{
+		filename: "scrape_sample_rate_limit_missing.bad.yml",
+		errMsg:   `sample_rate_shedding_policy requires sample_rate_limit to be set`,
+	},
+	{
+		filename: "scrape_staleness_marker_policy.bad.yml",
+		errMsg:   `unknown staleness marker policy "eventually"`,
+	},
+	{
+		filename: "scrape_stale_after_failed_scrapes.bad.yml",
+		errMsg:   `stale_after_failed_scrapes cannot be negative for scrape config with job name "prometheus"`,
+	},
 	{
 		filename: "labelname.bad.yml",
 		errMsg:   `"\xff" is not a valid label name`,
@@ -2497,6 +2654,38 @@ var expectedErrors = []struct {
 		filename: "remote_write_url_missing.bad.yml",
 		errMsg:   `url for remote_write is empty`,
 	},
+	{
+		filename: "remote_write_kafka_and_url.bad.yml",
+		errMsg:   `at most one of url and kafka must be configured for remote_write`,
+	},
+	{
+		filename: "remote_write_compression.bad.yml",
+		errMsg:   `invalid compression value "gzip" for remote write, supported: none, snappy, zstd`,
+	},
+	{
+		filename: "remote_write_dup_primary.bad.yml",
+		errMsg:   `found multiple remote write configs marked as primary, at most one is allowed`,
+	},
+	{
+		filename: "remote_write_replication_quorum_mismatch.bad.yml",
+		errMsg:   `replication group "eu" members declare different quorums (2 and 1)`,
+	},
+	{
+		filename: "remote_write_replication_quorum_size.bad.yml",
+		errMsg:   `replication group "eu" has quorum 3 but only 2 members`,
+	},
+	{
+		filename: "remote_write_replication_primary.bad.yml",
+		errMsg:   `remote write config cannot be both primary and part of a replication group`,
+	},
+	{
+		filename: "remote_write_kafka_compression.bad.yml",
+		errMsg:   `kafka transport for remote_write only supports snappy compression`,
+	},
+	{
+		filename: "remote_write_kafka_no_topic.bad.yml",
+		errMsg:   `invalid kafka config for remote_write: kafka topic must not be empty`,
+	},
 	{
 		filename: "remote_write_dup.bad.yml",
 		errMsg:   `found multiple remote write configs with job name "queue1"`,
@@ -2525,6 +2714,14 @@ var expectedErrors = []struct {
 		filename: "remote_write_queue_max_backoff_less_than_min.bad.yml",
 		errMsg:   `remote write queue max_backoff must not be less than min_backoff`,
 	},
+	{
+		filename: "remote_write_spill_buffer_age_without_size.bad.yml",
+		errMsg:   `remote write spill_buffer max_age requires max_size to be set`,
+	},
+	{
+		filename: "remote_write_drop_on_backpressure.bad.yml",
+		errMsg:   `remote write queue drop_on_backpressure contains unknown data type "metadata"`,
+	},
 	{
 		filename: "remote_read_dup.bad.yml",
 		errMsg:   `found multiple remote read configs with job name "queue1"`,
@@ -2731,7 +2928,7 @@ var expectedErrors = []struct {
 	},
 	{
 		filename: "scrape_config_files_scrape_protocols.bad.yml",
-		errMsg:   `parsing YAML file testdata/scrape_config_files_scrape_protocols.bad.yml: scrape_protocols: unknown scrape protocol prometheusproto, supported: [OpenMetricsText0.0.1 OpenMetricsText1.0.0 PrometheusProto PrometheusText0.0.4 PrometheusText1.0.0] for scrape config with job name "node"`,
+		errMsg:   `parsing YAML file testdata/scrape_config_files_scrape_protocols.bad.yml: scrape_protocols: unknown scrape protocol prometheusproto, supported: [OTLPJSON OTLPProto OpenMetricsText0.0.1 OpenMetricsText1.0.0 PrometheusProto PrometheusText0.0.4 PrometheusText1.0.0] for scrape config with job name "node"`,
 	},
 	{
 		filename: "scrape_config_files_scrape_protocols2.bad.yml",
@@ -2739,12 +2936,20 @@ var expectedErrors = []struct {
 	},
 	{
 		filename: "scrape_config_files_fallback_scrape_protocol1.bad.yml",
-		errMsg:   `parsing YAML file testdata/scrape_config_files_fallback_scrape_protocol1.bad.yml: invalid fallback_scrape_protocol for scrape config with job name "node": unknown scrape protocol prometheusproto, supported: [OpenMetricsText0.0.1 OpenMetricsText1.0.0 PrometheusProto PrometheusText0.0.4 PrometheusText1.0.0]`,
+		errMsg:   `parsing YAML file testdata/scrape_config_files_fallback_scrape_protocol1.bad.yml: invalid fallback_scrape_protocol for scrape config with job name "node": unknown scrape protocol prometheusproto, supported: [OTLPJSON OTLPProto OpenMetricsText0.0.1 OpenMetricsText1.0.0 PrometheusProto PrometheusText0.0.4 PrometheusText1.0.0]`,
 	},
 	{
 		filename: "scrape_config_files_fallback_scrape_protocol2.bad.yml",
 		errMsg:   `unmarshal errors`,
 	},
+	{
+		filename: "scrape_config_dns_resolution_strategy.bad.yml",
+		errMsg:   `invalid dns_resolution_strategy for scrape config with job name "node": unknown DNS resolution strategy "ipv5", supported: ipv4, ipv6, prefer_ipv4, prefer_ipv6`,
+	},
+	{
+		filename: "scrape_config_native_histogram_target_schema.bad.yml",
+		errMsg:   `native_histogram_target_schema must be between -4 and 8 for scrape config with job name "node", got 9`,
+	},
 	{
 		filename: "scrape_config_utf8_conflicting.bad.yml",
 		errMsg:   `utf8 metric names requested but validation scheme is not set to UTF8`,
@@ -2769,6 +2974,14 @@ var expectedErrors = []struct {
 		filename: "tsdb_retention_percentage_negative.bad.yml",
 		errMsg:   "'storage.tsdb.retention.percentage' must be in the range [0, 100]",
 	},
+	{
+		filename: "tsdb_retention_label_policy_no_labels.bad.yml",
+		errMsg:   "label retention policy requires at least one label name",
+	},
+	{
+		filename: "tsdb_retention_label_policy_metric_name.bad.yml",
+		errMsg:   "label retention policy must not drop the __name__ label",
+	},
 	{
 		filename: "tsdb_chunk_encoding_floats.bad.yml",
 		errMsg:   `'storage.tsdb.chunk_encoding.floats' must be 'xor' or 'xor2', or the field must be omitted entirely, got "xor3"`,
@@ -21,6 +21,7 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -116,9 +117,65 @@ type SDConfig struct {
 	AuthenticationMethod string             `yaml:"authentication_method,omitempty"`
 	ResourceGroup        string             `yaml:"resource_group,omitempty"`
 
+	// TagRules selects which machine tags are exposed as meta labels and
+	// under which name. When empty, every tag is exposed under its
+	// sanitized name.
+	TagRules []TagRule `yaml:"tag_rules,omitempty"`
+
 	HTTPClientConfig config_util.HTTPClientConfig `yaml:",inline"`
 }
 
+// TagRule maps an Azure machine tag to a target meta label. Rules are applied
+// in order and the first matching rule wins; tags not matched by any rule are
+// not exposed as labels.
+type TagRule struct {
+	// Tag is the name of the tag to match. A single trailing '*' matches
+	// every tag with the prefix before it.
+	Tag string `yaml:"tag"`
+	// Label is the name the tag is exposed under, always prefixed with
+	// __meta_azure_machine_tag_. If empty, the sanitized tag name is used.
+	// For a prefix match, the sanitized remainder of the tag name is
+	// appended to it.
+	Label string `yaml:"label,omitempty"`
+}
+
+// labelName returns the label name, without the __meta_azure_machine_tag_
+// prefix, that the given tag maps to, or false if the rule does not match.
+func (r TagRule) labelName(tag string) (string, bool) {
+	if prefix, wildcard := strings.CutSuffix(r.Tag, "*"); wildcard {
+		rest, ok := strings.CutPrefix(tag, prefix)
+		if !ok {
+			return "", false
+		}
+		if r.Label == "" {
+			return strutil.SanitizeLabelName(tag), true
+		}
+		return r.Label + strutil.SanitizeLabelName(rest), true
+	}
+	if tag != r.Tag {
+		return "", false
+	}
+	if r.Label == "" {
+		return strutil.SanitizeLabelName(tag), true
+	}
+	return r.Label, true
+}
+
+// tagLabelName resolves the label name for a tag through the configured
+// rules. Without rules every tag is exposed under its sanitized name,
+// preserving the historical behaviour.
+func tagLabelName(rules []TagRule, tag string) (string, bool) {
+	if len(rules) == 0 {
+		return strutil.SanitizeLabelName(tag), true
+	}
+	for _, r := range rules {
+		if name, ok := r.labelName(tag); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
 // NewDiscovererMetrics implements discovery.Config.
 func (*SDConfig) NewDiscovererMetrics(reg prometheus.Registerer, rmi discovery.RefreshMetricsInstantiator) discovery.DiscovererMetrics {
 	return newDiscovererMetrics(reg, rmi)
@@ -167,6 +224,18 @@ func (c *SDConfig) UnmarshalYAML(unmarshal func(any) error) error {
 		return fmt.Errorf("unknown authentication_type %q. Supported types are %q, %q, %q or %q", c.AuthenticationMethod, authMethodOAuth, authMethodManagedIdentity, authMethodSDK, authMethodWorkloadIdentity)
 	}
 
+	for _, rule := range c.TagRules {
+		if rule.Tag == "" {
+			return errors.New("azure SD tag rule requires a tag name")
+		}
+		if i := strings.Index(rule.Tag, "*"); i >= 0 && i != len(rule.Tag)-1 {
+			return fmt.Errorf("azure SD tag rule %q: '*' is only supported as a trailing wildcard", rule.Tag)
+		}
+		if rule.Label != "" && !model.LabelName(azureLabelMachineTag+rule.Label).IsValidLegacy() {
+			return fmt.Errorf("azure SD tag rule %q has an invalid label name %q", rule.Tag, rule.Label)
+		}
+	}
+
 	return c.HTTPClientConfig.Validate()
 }
 
@@ -176,9 +245,18 @@ type Discovery struct {
 	cfg     *SDConfig
 	port    int
 	cache   *cache.Cache[string, *armnetwork.Interface]
+	vmCache *cache.Cache[string, *vmCacheEntry]
 	metrics *azureMetrics
 }
 
+// vmCacheEntry pairs the machine metadata a label set was built from with the
+// resulting labels, so the network interface enrichment can be skipped across
+// refreshes while the metadata is unchanged.
+type vmCacheEntry struct {
+	vm     virtualMachine
+	labels model.LabelSet
+}
+
 // NewDiscovery returns a new AzureDiscovery which periodically refreshes its targets.
 func NewDiscovery(cfg *SDConfig, opts discovery.DiscovererOptions) (*Discovery, error) {
 	m, ok := opts.Metrics.(*azureMetrics)
@@ -190,11 +268,13 @@ func NewDiscovery(cfg *SDConfig, opts discovery.DiscovererOptions) (*Discovery,
 		opts.Logger = promslog.NewNopLogger()
 	}
 	l := cache.New(cache.AsLRU[string, *armnetwork.Interface](lru.WithCapacity(5000)))
+	v := cache.New(cache.AsLRU[string, *vmCacheEntry](lru.WithCapacity(5000)))
 	d := &Discovery{
 		cfg:     cfg,
 		port:    cfg.Port,
 		logger:  opts.Logger,
 		cache:   l,
+		vmCache: v,
 		metrics: m,
 	}
 
@@ -438,6 +518,35 @@ type virtualMachine struct {
 	Size              string
 }
 
+// equal reports whether two virtual machines carry the same metadata. It is
+// used to detect machines that changed between refreshes.
+func (vm virtualMachine) equal(other virtualMachine) bool {
+	if vm.ID != other.ID ||
+		vm.Name != other.Name ||
+		vm.ComputerName != other.ComputerName ||
+		vm.Type != other.Type ||
+		vm.Location != other.Location ||
+		vm.OsType != other.OsType ||
+		vm.ScaleSet != other.ScaleSet ||
+		vm.InstanceID != other.InstanceID ||
+		vm.Size != other.Size {
+		return false
+	}
+	if !slices.Equal(vm.NetworkInterfaces, other.NetworkInterfaces) {
+		return false
+	}
+	if len(vm.Tags) != len(other.Tags) {
+		return false
+	}
+	for k, v := range vm.Tags {
+		o, ok := other.Tags[k]
+		if !ok || (v == nil) != (o == nil) || (v != nil && *v != *o) {
+			return false
+		}
+	}
+	return true
+}
+
 // Create a new azureResource object from an ID string.
 func newAzureResourceFromID(id string, logger *slog.Logger) (*arm.ResourceID, error) {
 	if logger == nil {
@@ -469,6 +578,12 @@ func (d *Discovery) refreshAzureClient(ctx context.Context, client client) ([]*t
 	}
 
 	for _, scaleSet := range scaleSets {
+		if scaleSet.Properties != nil && scaleSet.Properties.OrchestrationMode != nil && *scaleSet.Properties.OrchestrationMode == armcompute.OrchestrationModeFlexible {
+			// Instances of a Flexible orchestration scale set are regular
+			// virtual machines and were already returned by getVMs; listing
+			// them here as well would duplicate the targets.
+			continue
+		}
 		scaleSetVms, err := client.getScaleSetVMs(ctx, scaleSet)
 		if err != nil {
 			d.metrics.failuresCount.Inc()
@@ -524,7 +639,26 @@ func (d *Discovery) refresh(ctx context.Context) ([]*targetgroup.Group, error) {
 	return d.refreshAzureClient(ctx, client)
 }
 
+// vmToLabelSet returns the target labels for a virtual machine. Results are
+// cached together with the machine metadata they were built from, so machines
+// whose metadata is unchanged since the previous refresh do not trigger
+// network interface lookups again.
 func (d *Discovery) vmToLabelSet(ctx context.Context, client client, vm virtualMachine) (model.LabelSet, error) {
+	if entry, ok := d.getVMFromCache(vm.ID); ok && entry.vm.equal(vm) {
+		d.metrics.vmCacheHitCount.Inc()
+		return entry.labels, nil
+	}
+	d.metrics.vmCacheMissCount.Inc()
+
+	labels, err := d.buildLabelSet(ctx, client, vm)
+	if err != nil {
+		return nil, err
+	}
+	d.addVMToCache(vm, labels)
+	return labels, nil
+}
+
+func (d *Discovery) buildLabelSet(ctx context.Context, client client, vm virtualMachine) (model.LabelSet, error) {
 	r, err := newAzureResourceFromID(vm.ID, d.logger)
 	if err != nil {
 		return nil, err
@@ -547,8 +681,9 @@ func (d *Discovery) vmToLabelSet(ctx context.Context, client client, vm virtualM
 	}
 
 	for k, v := range vm.Tags {
-		name := strutil.SanitizeLabelName(k)
-		labels[azureLabelMachineTag+model.LabelName(name)] = model.LabelValue(*v)
+		if name, ok := tagLabelName(d.cfg.TagRules, k); ok {
+			labels[azureLabelMachineTag+model.LabelName(name)] = model.LabelValue(*v)
+		}
 	}
 
 	// Get the IP address information via separate call to the network provider.
@@ -558,7 +693,10 @@ func (d *Discovery) vmToLabelSet(ctx context.Context, client client, vm virtualM
 			networkInterface = v
 			d.metrics.cacheHitCount.Add(1)
 		} else {
-			if vm.ScaleSet == "" {
+			// Instances of a Flexible orchestration scale set report their
+			// scale set but have no instance ID; their interfaces are
+			// regular NICs, not scale set NICs.
+			if vm.ScaleSet == "" || vm.InstanceID == "" {
 				networkInterface, err = client.getVMNetworkInterfaceByID(ctx, nicID)
 			} else {
 				networkInterface, err = client.getVMScaleSetVMNetworkInterfaceByID(ctx, nicID, vm.ScaleSet, vm.InstanceID)
@@ -696,6 +834,7 @@ func mapFromVM(vm armcompute.VirtualMachine) virtualMachine {
 	networkInterfaces := []string{}
 	var computerName string
 	var size string
+	var scaleSet string
 
 	if vm.Tags != nil {
 		tags = vm.Tags
@@ -719,6 +858,13 @@ func mapFromVM(vm armcompute.VirtualMachine) virtualMachine {
 		if vm.Properties.HardwareProfile != nil {
 			size = string(*vm.Properties.HardwareProfile.VMSize)
 		}
+		// Instances of a scale set in Flexible orchestration mode are listed
+		// by the regular VM API and carry a reference to their scale set.
+		if vm.Properties.VirtualMachineScaleSet != nil && vm.Properties.VirtualMachineScaleSet.ID != nil {
+			if r, err := arm.ParseResourceID(*vm.Properties.VirtualMachineScaleSet.ID); err == nil {
+				scaleSet = r.Name
+			}
+		}
 	}
 
 	return virtualMachine{
@@ -728,7 +874,7 @@ func mapFromVM(vm armcompute.VirtualMachine) virtualMachine {
 		Type:              *(vm.Type),
 		Location:          *(vm.Location),
 		OsType:            osType,
-		ScaleSet:          "",
+		ScaleSet:          scaleSet,
 		Tags:              tags,
 		NetworkInterfaces: networkInterfaces,
 		Size:              size,
@@ -838,3 +984,18 @@ func (d *Discovery) getFromCache(nicID string) (*armnetwork.Interface, bool) {
 	net, found := d.cache.Get(nicID)
 	return net, found
 }
+
+// addVMToCache caches the label set built for a virtual machine together with
+// the metadata it was derived from.
+func (d *Discovery) addVMToCache(vm virtualMachine, labels model.LabelSet) {
+	random := rand.Int63n(int64(time.Duration(d.cfg.RefreshInterval * 3).Seconds()))
+	rs := time.Duration(random) * time.Second
+	exptime := time.Duration(d.cfg.RefreshInterval*10) + rs
+	d.vmCache.Set(vm.ID, &vmCacheEntry{vm: vm, labels: labels}, cache.WithExpiration(exptime))
+}
+
+// getVMFromCache returns the cached entry for the given virtual machine ID.
+func (d *Discovery) getVMFromCache(vmID string) (*vmCacheEntry, bool) {
+	entry, found := d.vmCache.Get(vmID)
+	return entry, found
+}
@@ -33,10 +33,12 @@ import (
 	cache "github.com/Code-Hex/go-generics-cache"
 	"github.com/Code-Hex/go-generics-cache/policy/lru"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/common/promslog"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
+	"gopkg.in/yaml.v2"
 
 	"github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/discovery/targetgroup"
@@ -44,6 +46,18 @@ import (
 
 const defaultMockNetworkID string = "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Network/networkInterfaces/{networkInterfaceName}"
 
+// testDiscovery returns a Discovery with initialized caches and metrics,
+// bypassing NewDiscovery so no refresher is started.
+func testDiscovery(cfg *SDConfig) *Discovery {
+	return &Discovery{
+		cfg:     cfg,
+		logger:  promslog.NewNopLogger(),
+		cache:   cache.New(cache.AsLRU[string, *armnetwork.Interface](lru.WithCapacity(5))),
+		vmCache: cache.New(cache.AsLRU[string, *vmCacheEntry](lru.WithCapacity(5))),
+		metrics: newDiscovererMetrics(prometheus.NewRegistry(), nil).(*azureMetrics),
+	}
+}
+
 func TestMain(m *testing.M) {
 	goleak.VerifyTestMain(m,
 		goleak.IgnoreTopFunction("github.com/Code-Hex/go-generics-cache.(*janitor).run.func1"),
@@ -163,11 +177,7 @@ func TestVMToLabelSet(t *testing.T) {
 	require.Equal(t, expectedVM, actualVM)
 
 	cfg := DefaultSDConfig
-	d := &Discovery{
-		cfg:    &cfg,
-		logger: promslog.NewNopLogger(),
-		cache:  cache.New(cache.AsLRU[string, *armnetwork.Interface](lru.WithCapacity(5))),
-	}
+	d := testDiscovery(&cfg)
 	network := armnetwork.Interface{
 		Name: to.Ptr(defaultMockNetworkID),
 		ID:   to.Ptr(defaultMockNetworkID),
@@ -188,6 +198,175 @@ func TestVMToLabelSet(t *testing.T) {
 	require.Len(t, labelSet, 11)
 }
 
+func TestVMToLabelSetCache(t *testing.T) {
+	cfg := DefaultSDConfig
+	d := testDiscovery(&cfg)
+	network := armnetwork.Interface{
+		ID: to.Ptr(defaultMockNetworkID),
+		Properties: &armnetwork.InterfacePropertiesFormat{
+			Primary: to.Ptr(true),
+			IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+				{Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+					PrivateIPAddress: to.Ptr("10.20.30.40"),
+				}},
+			},
+		},
+	}
+	client := createMockAzureClient(t, nil, nil, nil, network, nil)
+
+	vm := mapFromVM(*defaultVMWithIDAndName(to.Ptr("/subscriptions/00000000-0000-0000-0000-000000000000/test"), nil))
+
+	// The first refresh builds the label set from scratch.
+	labelSet, err := d.vmToLabelSet(context.Background(), client, vm)
+	require.NoError(t, err)
+	require.Equal(t, 0.0, testutil.ToFloat64(d.metrics.vmCacheHitCount))
+	require.Equal(t, 1.0, testutil.ToFloat64(d.metrics.vmCacheMissCount))
+
+	// An unchanged machine is served from the cache on the next refresh.
+	cachedLabelSet, err := d.vmToLabelSet(context.Background(), client, vm)
+	require.NoError(t, err)
+	require.Equal(t, labelSet, cachedLabelSet)
+	require.Equal(t, 1.0, testutil.ToFloat64(d.metrics.vmCacheHitCount))
+	require.Equal(t, 1.0, testutil.ToFloat64(d.metrics.vmCacheMissCount))
+
+	// Changed metadata invalidates the cached entry.
+	vm.Tags = map[string]*string{"environment": to.Ptr("production")}
+	labelSet, err = d.vmToLabelSet(context.Background(), client, vm)
+	require.NoError(t, err)
+	require.Equal(t, model.LabelValue("production"), labelSet["__meta_azure_machine_tag_environment"])
+	require.Equal(t, 1.0, testutil.ToFloat64(d.metrics.vmCacheHitCount))
+	require.Equal(t, 2.0, testutil.ToFloat64(d.metrics.vmCacheMissCount))
+}
+
+func TestVMToLabelSetTagRules(t *testing.T) {
+	tags := map[string]*string{
+		"environment":  to.Ptr("production"),
+		"cost_center":  to.Ptr("42"),
+		"team:owner":   to.Ptr("observability"),
+		"team:contact": to.Ptr("oncall"),
+	}
+
+	for _, tc := range []struct {
+		name     string
+		rules    []TagRule
+		expected map[model.LabelName]model.LabelValue
+	}{
+		{
+			name: "no rules exposes every tag under its sanitized name",
+			expected: map[model.LabelName]model.LabelValue{
+				"__meta_azure_machine_tag_environment":  "production",
+				"__meta_azure_machine_tag_cost_center":  "42",
+				"__meta_azure_machine_tag_team_owner":   "observability",
+				"__meta_azure_machine_tag_team_contact": "oncall",
+			},
+		},
+		{
+			name:  "exact match drops unmatched tags",
+			rules: []TagRule{{Tag: "environment"}},
+			expected: map[model.LabelName]model.LabelValue{
+				"__meta_azure_machine_tag_environment": "production",
+			},
+		},
+		{
+			name:  "exact match with renaming",
+			rules: []TagRule{{Tag: "environment", Label: "env"}},
+			expected: map[model.LabelName]model.LabelValue{
+				"__meta_azure_machine_tag_env": "production",
+			},
+		},
+		{
+			name:  "prefix match appends the sanitized remainder",
+			rules: []TagRule{{Tag: "team:*", Label: "team_"}},
+			expected: map[model.LabelName]model.LabelValue{
+				"__meta_azure_machine_tag_team_owner":   "observability",
+				"__meta_azure_machine_tag_team_contact": "oncall",
+			},
+		},
+		{
+			name:  "first matching rule wins",
+			rules: []TagRule{{Tag: "team:owner", Label: "owner"}, {Tag: "team:*"}},
+			expected: map[model.LabelName]model.LabelValue{
+				"__meta_azure_machine_tag_owner":        "observability",
+				"__meta_azure_machine_tag_team_contact": "oncall",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := DefaultSDConfig
+			cfg.TagRules = tc.rules
+			d := testDiscovery(&cfg)
+			network := armnetwork.Interface{
+				ID: to.Ptr(defaultMockNetworkID),
+				Properties: &armnetwork.InterfacePropertiesFormat{
+					Primary: to.Ptr(true),
+					IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+						{Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+							PrivateIPAddress: to.Ptr("10.20.30.40"),
+						}},
+					},
+				},
+			}
+			client := createMockAzureClient(t, nil, nil, nil, network, nil)
+
+			vm := mapFromVM(*defaultVMWithIDAndName(to.Ptr("/subscriptions/00000000-0000-0000-0000-000000000000/test"), nil))
+			vm.Tags = tags
+
+			labelSet, err := d.vmToLabelSet(context.Background(), client, vm)
+			require.NoError(t, err)
+			for name, value := range tc.expected {
+				require.Equal(t, value, labelSet[name])
+			}
+			for name := range labelSet {
+				if strings.HasPrefix(string(name), azureLabelMachineTag) {
+					require.Contains(t, tc.expected, name)
+				}
+			}
+		})
+	}
+}
+
+func TestSDConfigTagRulesValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		rules       string
+		expectedErr string
+	}{
+		{
+			name:  "valid rules",
+			rules: "[{tag: environment, label: env}, {tag: 'team:*'}]",
+		},
+		{
+			name:        "missing tag name",
+			rules:       "[{label: env}]",
+			expectedErr: "azure SD tag rule requires a tag name",
+		},
+		{
+			name:        "wildcard not at the end",
+			rules:       "[{tag: 'team:*:name'}]",
+			expectedErr: "'*' is only supported as a trailing wildcard",
+		},
+		{
+			name:        "invalid label name",
+			rules:       "[{tag: environment, label: 'not valid'}]",
+			expectedErr: "invalid label name",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfgString := `
+authentication_method: SDK
+subscription_id: 00000000-0000-0000-0000-000000000000
+tag_rules: ` + tc.rules
+			var cfg SDConfig
+			err := yaml.UnmarshalStrict([]byte(cfgString), &cfg)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestMapFromVMWithEmptyOSType(t *testing.T) {
 	id := "test"
 	name := "name"
@@ -293,6 +472,20 @@ func TestMapFromVMWithTags(t *testing.T) {
 	require.Equal(t, expectedVM, actualVM)
 }
 
+func TestMapFromVMFlexScaleSet(t *testing.T) {
+	// A VM that belongs to a scale set in Flexible orchestration mode is
+	// returned by the regular VM API with a reference to its scale set.
+	testVM := defaultVMWithIDAndName(nil, nil)
+	testVM.Properties.VirtualMachineScaleSet = &armcompute.SubResource{
+		ID: to.Ptr("/subscriptions/00000000-0000-0000-0000-00000000000/resourceGroups/{resourceGroup}/providers/Microsoft.Compute/virtualMachineScaleSets/flexSet"),
+	}
+
+	actualVM := mapFromVM(*testVM)
+
+	require.Equal(t, "flexSet", actualVM.ScaleSet)
+	require.Empty(t, actualVM.InstanceID)
+}
+
 func TestMapFromVMScaleSetVMWithEmptyTags(t *testing.T) {
 	id := "test"
 	name := "name"
@@ -669,6 +862,83 @@ func TestAzureRefresh(t *testing.T) {
 				},
 			},
 		},
+		{
+			scenario: "Flexible orchestration scale set instances are discovered as VMs and not listed twice",
+			vmResp: []armcompute.VirtualMachinesClientListAllResponse{
+				{
+					VirtualMachineListResult: armcompute.VirtualMachineListResult{
+						Value: []*armcompute.VirtualMachine{
+							defaultFlexVMWithIDAndName(
+								to.Ptr("/subscriptions/00000000-0000-0000-0000-00000000000/resourceGroups/{resourceGroup}/providers/Microsoft.Compute/virtualMachines/flexVM1"),
+								to.Ptr("flexVM1"),
+								to.Ptr("/subscriptions/00000000-0000-0000-0000-00000000000/resourceGroups/{resourceGroup}/providers/Microsoft.Compute/virtualMachineScaleSets/flexScaleSet1"),
+							),
+						},
+					},
+				},
+			},
+			vmssResp: []armcompute.VirtualMachineScaleSetsClientListAllResponse{
+				{
+					VirtualMachineScaleSetListWithLinkResult: armcompute.VirtualMachineScaleSetListWithLinkResult{
+						Value: []*armcompute.VirtualMachineScaleSet{
+							{
+								ID:       to.Ptr("/subscriptions/00000000-0000-0000-0000-00000000000/resourceGroups/{resourceGroup}/providers/Microsoft.Compute/virtualMachineScaleSets/flexScaleSet1"),
+								Name:     to.Ptr("flexScaleSet1"),
+								Location: to.Ptr("australiaeast"),
+								Type:     to.Ptr("Microsoft.Compute/virtualMachineScaleSets"),
+								Properties: &armcompute.VirtualMachineScaleSetProperties{
+									OrchestrationMode: to.Ptr(armcompute.OrchestrationModeFlexible),
+								},
+							},
+						},
+					},
+				},
+			},
+			// The scale set VM API must not be consulted for a Flexible
+			// orchestration scale set; a response here would duplicate the
+			// instance discovered through the VM API.
+			vmssvmResp: []armcompute.VirtualMachineScaleSetVMsClientListResponse{
+				{
+					VirtualMachineScaleSetVMListResult: armcompute.VirtualMachineScaleSetVMListResult{
+						Value: []*armcompute.VirtualMachineScaleSetVM{
+							defaultVMSSVMWithIDAndName(to.Ptr("/subscriptions/00000000-0000-0000-0000-00000000000/resourceGroups/{resourceGroup}/providers/Microsoft.Compute/virtualMachineScaleSets/flexScaleSet1/virtualMachines/0"), to.Ptr("flexVM1")),
+						},
+					},
+				},
+			},
+			interfacesResp: armnetwork.Interface{
+				ID: to.Ptr(defaultMockNetworkID),
+				Properties: &armnetwork.InterfacePropertiesFormat{
+					Primary: to.Ptr(true),
+					IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+						{Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+							PrivateIPAddress: to.Ptr("10.0.0.1"),
+						}},
+					},
+				},
+			},
+			expectedTG: []*targetgroup.Group{
+				{
+					Targets: []model.LabelSet{
+						{
+							"__address__":                         "10.0.0.1:80",
+							"__meta_azure_machine_computer_name":  "computer_name",
+							"__meta_azure_machine_id":             "/subscriptions/00000000-0000-0000-0000-00000000000/resourceGroups/{resourceGroup}/providers/Microsoft.Compute/virtualMachines/flexVM1",
+							"__meta_azure_machine_location":       "australiaeast",
+							"__meta_azure_machine_name":           "flexVM1",
+							"__meta_azure_machine_os_type":        "Linux",
+							"__meta_azure_machine_private_ip":     "10.0.0.1",
+							"__meta_azure_machine_resource_group": "{resourceGroup}",
+							"__meta_azure_machine_scale_set":      "flexScaleSet1",
+							"__meta_azure_machine_size":           "size",
+							"__meta_azure_machine_tag_prometheus": "",
+							"__meta_azure_subscription_id":        "",
+							"__meta_azure_tenant_id":              "",
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.scenario, func(t *testing.T) {
@@ -837,6 +1107,12 @@ func defaultVMWithIDAndName(id, name *string) *armcompute.VirtualMachine {
 	}
 }
 
+func defaultFlexVMWithIDAndName(id, name, scaleSetID *string) *armcompute.VirtualMachine {
+	vm := defaultVMWithIDAndName(id, name)
+	vm.Properties.VirtualMachineScaleSet = &armcompute.SubResource{ID: scaleSetID}
+	return vm
+}
+
 func defaultVMSSVMWithIDAndName(id, name *string) *armcompute.VirtualMachineScaleSetVM {
 	vmSize := armcompute.VirtualMachineSizeTypes("size")
 	osType := armcompute.OperatingSystemTypesLinux
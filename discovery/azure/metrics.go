@@ -24,8 +24,10 @@ var _ discovery.DiscovererMetrics = (*azureMetrics)(nil)
 type azureMetrics struct {
 	refreshMetrics discovery.RefreshMetricsInstantiator
 
-	failuresCount prometheus.Counter
-	cacheHitCount prometheus.Counter
+	failuresCount    prometheus.Counter
+	cacheHitCount    prometheus.Counter
+	vmCacheHitCount  prometheus.Counter
+	vmCacheMissCount prometheus.Counter
 
 	metricRegisterer discovery.MetricRegisterer
 }
@@ -43,11 +45,23 @@ func newDiscovererMetrics(reg prometheus.Registerer, rmi discovery.RefreshMetric
 				Name: "prometheus_sd_azure_cache_hit_total",
 				Help: "Number of cache hit during refresh.",
 			}),
+		vmCacheHitCount: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "prometheus_sd_azure_vm_cache_hit_total",
+				Help: "Number of virtual machine metadata cache hits during refresh.",
+			}),
+		vmCacheMissCount: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "prometheus_sd_azure_vm_cache_miss_total",
+				Help: "Number of virtual machine metadata cache misses during refresh.",
+			}),
 	}
 
 	m.metricRegisterer = discovery.NewMetricRegisterer(reg, []prometheus.Collector{
 		m.failuresCount,
 		m.cacheHitCount,
+		m.vmCacheHitCount,
+		m.vmCacheMissCount,
 	})
 
 	return m
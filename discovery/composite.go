@@ -0,0 +1,333 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+)
+
+// The possible operations of a composite service discovery configuration.
+const (
+	CompositeOpUnion     = "union"
+	CompositeOpIntersect = "intersect"
+	CompositeOpExclude   = "exclude"
+)
+
+func init() {
+	RegisterConfig(&CompositeConfig{})
+}
+
+// CompositeConfig is the configuration for composite service discovery. It
+// combines the target sets produced by several other service discovery
+// configurations using an explicit set operation, e.g. excluding the targets
+// listed by a denylist file SD from a Consul-provided set. Targets are
+// compared by their full label sets, with group labels applied.
+type CompositeConfig struct {
+	// Operation determines how the sources are combined: "union" merges the
+	// targets of all sources, "intersect" keeps the targets of the first
+	// source that are present in every other source, and "exclude" keeps the
+	// targets of the first source that are present in no other source.
+	Operation string `yaml:"operation"`
+	// Sources are the service discovery configurations to combine.
+	Sources []Configs `yaml:"sources"`
+}
+
+// Name returns the name of the Config.
+func (*CompositeConfig) Name() string { return "composite" }
+
+// NewDiscovererMetrics returns NoopDiscovererMetrics: the composite
+// discoverer has no metrics of its own and its sources reuse the metrics of
+// their respective mechanisms.
+func (*CompositeConfig) NewDiscovererMetrics(prometheus.Registerer, RefreshMetricsInstantiator) DiscovererMetrics {
+	return &NoopDiscovererMetrics{}
+}
+
+// SetDirectory joins any relative file paths with dir.
+func (c *CompositeConfig) SetDirectory(dir string) {
+	for i := range c.Sources {
+		c.Sources[i].SetDirectory(dir)
+	}
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *CompositeConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	*c = CompositeConfig{}
+	type plain CompositeConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	switch c.Operation {
+	case CompositeOpUnion, CompositeOpIntersect, CompositeOpExclude:
+	case "":
+		return errors.New("composite service discovery config requires an operation")
+	default:
+		return fmt.Errorf("invalid composite service discovery operation %q", c.Operation)
+	}
+	if len(c.Sources) == 0 {
+		return errors.New("composite service discovery config must contain at least one source")
+	}
+	if c.Operation != CompositeOpUnion && len(c.Sources) < 2 {
+		return fmt.Errorf("composite service discovery operation %q requires at least two sources", c.Operation)
+	}
+	for _, src := range c.Sources {
+		if len(src) == 0 {
+			return errors.New("composite service discovery source must contain at least one SD configuration")
+		}
+	}
+	return nil
+}
+
+// NewDiscoverer returns a Discoverer for the Config.
+func (c *CompositeConfig) NewDiscoverer(opts DiscovererOptions) (Discoverer, error) {
+	if opts.MechanismMetrics == nil {
+		return nil, errors.New("composite service discovery can only be instantiated with access to the mechanism metrics")
+	}
+	d := &compositeDiscoverer{op: c.Operation, logger: opts.Logger}
+	for _, src := range c.Sources {
+		discs := make([]Discoverer, 0, len(src))
+		for _, cfg := range src {
+			typ := cfg.Name()
+			metrics, ok := opts.MechanismMetrics[typ]
+			if !ok {
+				return nil, fmt.Errorf("composite service discovery: no metrics registered for mechanism %q", typ)
+			}
+			sub, err := cfg.NewDiscoverer(DiscovererOptions{
+				Logger:            opts.Logger,
+				Metrics:           metrics,
+				HTTPClientOptions: opts.HTTPClientOptions,
+				SetName:           opts.SetName,
+				MechanismMetrics:  opts.MechanismMetrics,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("composite service discovery: cannot create %q discoverer: %w", typ, err)
+			}
+			discs = append(discs, sub)
+		}
+		d.sources = append(d.sources, discs)
+	}
+	return d, nil
+}
+
+// compositeDiscoverer runs the discoverers of all sources and recomputes the
+// combined target set whenever one of them sends an update.
+type compositeDiscoverer struct {
+	op      string
+	logger  *slog.Logger
+	sources [][]Discoverer
+}
+
+// compositeUpdate is an update received from one of the source discoverers.
+type compositeUpdate struct {
+	source, discoverer int
+	groups             []*targetgroup.Group
+}
+
+// Run implements the Discoverer interface.
+func (d *compositeDiscoverer) Run(ctx context.Context, up chan<- []*targetgroup.Group) {
+	updates := make(chan compositeUpdate)
+	var wg sync.WaitGroup
+
+	// state holds the groups last sent by each source discoverer, keyed by
+	// group source. seeded records which discoverers have sent their initial
+	// target set: the combined set is only computed once all have, so that
+	// e.g. an exclusion source that is slow to start does not let denied
+	// targets through.
+	state := make([][]map[string]*targetgroup.Group, len(d.sources))
+	seeded := make([][]bool, len(d.sources))
+
+	for si, discs := range d.sources {
+		state[si] = make([]map[string]*targetgroup.Group, len(discs))
+		seeded[si] = make([]bool, len(discs))
+		for di, disc := range discs {
+			state[si][di] = map[string]*targetgroup.Group{}
+
+			ch := make(chan []*targetgroup.Group)
+			wg.Add(1)
+			go func(disc Discoverer) {
+				defer wg.Done()
+				disc.Run(ctx, ch)
+			}(disc)
+
+			wg.Add(1)
+			go func(si, di int, ch chan []*targetgroup.Group) {
+				defer wg.Done()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case tgs, ok := <-ch:
+						if !ok {
+							return
+						}
+						select {
+						case updates <- compositeUpdate{source: si, discoverer: di, groups: tgs}:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+			}(si, di, ch)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case u := <-updates:
+			for _, g := range u.groups {
+				if g == nil {
+					continue
+				}
+				state[u.source][u.discoverer][g.Source] = g
+			}
+			seeded[u.source][u.discoverer] = true
+			if !allSeeded(seeded) {
+				continue
+			}
+			select {
+			case up <- d.combine(state):
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			}
+		}
+	}
+}
+
+// allSeeded reports whether every source discoverer has sent its initial
+// target set.
+func allSeeded(seeded [][]bool) bool {
+	for _, src := range seeded {
+		for _, ok := range src {
+			if !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// combine computes the combined target set from the current state of all
+// sources.
+func (d *compositeDiscoverer) combine(state [][]map[string]*targetgroup.Group) []*targetgroup.Group {
+	var out []*targetgroup.Group
+
+	if d.op == CompositeOpUnion {
+		for si := range state {
+			for di := range state[si] {
+				for _, g := range state[si][di] {
+					out = append(out, &targetgroup.Group{
+						Source:  compositeGroupSource(si, di, g.Source),
+						Labels:  g.Labels,
+						Targets: g.Targets,
+					})
+				}
+			}
+		}
+		sortGroupsBySource(out)
+		return out
+	}
+
+	// Build the target fingerprint sets of all but the first source.
+	sets := make([]map[model.Fingerprint]struct{}, 0, len(state)-1)
+	for _, src := range state[1:] {
+		set := map[model.Fingerprint]struct{}{}
+		for _, groups := range src {
+			for _, g := range groups {
+				for _, t := range g.Targets {
+					set[compositeTargetFingerprint(g, t)] = struct{}{}
+				}
+			}
+		}
+		sets = append(sets, set)
+	}
+
+	// Filter the groups of the first source. Groups are kept even when all
+	// their targets are filtered out so that previously sent targets are
+	// dropped by the receiver.
+	for di := range state[0] {
+		for _, g := range state[0][di] {
+			var targets []model.LabelSet
+			for _, t := range g.Targets {
+				fp := compositeTargetFingerprint(g, t)
+				n := 0
+				for _, set := range sets {
+					if _, ok := set[fp]; ok {
+						n++
+					}
+				}
+				switch d.op {
+				case CompositeOpIntersect:
+					if n == len(sets) {
+						targets = append(targets, t)
+					}
+				case CompositeOpExclude:
+					if n == 0 {
+						targets = append(targets, t)
+					}
+				}
+			}
+			out = append(out, &targetgroup.Group{
+				Source:  compositeGroupSource(0, di, g.Source),
+				Labels:  g.Labels,
+				Targets: targets,
+			})
+		}
+	}
+	sortGroupsBySource(out)
+	return out
+}
+
+// compositeTargetFingerprint returns the identity of a target within a group,
+// computed over the target's label set with the group labels applied. Labels
+// with empty values are dropped, mirroring how the scrape pool merges group
+// and target labels.
+func compositeTargetFingerprint(g *targetgroup.Group, t model.LabelSet) model.Fingerprint {
+	lset := make(model.LabelSet, len(g.Labels)+len(t))
+	for n, v := range g.Labels {
+		if v != "" {
+			lset[n] = v
+		}
+	}
+	for n, v := range t {
+		if v != "" {
+			lset[n] = v
+		} else {
+			delete(lset, n)
+		}
+	}
+	return lset.Fingerprint()
+}
+
+// compositeGroupSource qualifies a group source with the source and
+// discoverer indexes to keep the sources of different providers distinct.
+func compositeGroupSource(si, di int, source string) string {
+	return fmt.Sprintf("composite/%d/%d/%s", si, di, source)
+}
+
+func sortGroupsBySource(groups []*targetgroup.Group) {
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Source < groups[j].Source })
+}
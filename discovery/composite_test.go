@@ -0,0 +1,257 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/promslog"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v2"
+
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+)
+
+func TestCompositeConfigUnmarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name: "valid exclude",
+			input: `composite_sd_configs:
+- operation: exclude
+  sources:
+  - static_configs:
+    - targets: ['a:80', 'b:80']
+  - static_configs:
+    - targets: ['b:80']
+`,
+		},
+		{
+			name: "valid union with one source",
+			input: `composite_sd_configs:
+- operation: union
+  sources:
+  - static_configs:
+    - targets: ['a:80']
+`,
+		},
+		{
+			name: "missing operation",
+			input: `composite_sd_configs:
+- sources:
+  - static_configs:
+    - targets: ['a:80']
+`,
+			wantErr: "requires an operation",
+		},
+		{
+			name: "invalid operation",
+			input: `composite_sd_configs:
+- operation: subtract
+  sources:
+  - static_configs:
+    - targets: ['a:80']
+  - static_configs:
+    - targets: ['b:80']
+`,
+			wantErr: `invalid composite service discovery operation "subtract"`,
+		},
+		{
+			name: "no sources",
+			input: `composite_sd_configs:
+- operation: union
+`,
+			wantErr: "at least one source",
+		},
+		{
+			name: "intersect with one source",
+			input: `composite_sd_configs:
+- operation: intersect
+  sources:
+  - static_configs:
+    - targets: ['a:80']
+`,
+			wantErr: "requires at least two sources",
+		},
+		{
+			name: "empty source",
+			input: `composite_sd_configs:
+- operation: exclude
+  sources:
+  - static_configs:
+    - targets: ['a:80']
+  - {}
+`,
+			wantErr: "at least one SD configuration",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			cfgs := &Configs{}
+			err := yaml.UnmarshalStrict([]byte(test.input), cfgs)
+			if test.wantErr != "" {
+				require.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, *cfgs, 1)
+			require.IsType(t, &CompositeConfig{}, (*cfgs)[0])
+		})
+	}
+}
+
+func TestCompositeDiscovery(t *testing.T) {
+	t.Parallel()
+
+	targets := func(addrs ...string) []model.LabelSet {
+		tgts := make([]model.LabelSet, 0, len(addrs))
+		for _, a := range addrs {
+			tgts = append(tgts, model.LabelSet{model.AddressLabel: model.LabelValue(a)})
+		}
+		return tgts
+	}
+	source := func(groups ...*targetgroup.Group) Configs {
+		return Configs{StaticConfig(groups)}
+	}
+
+	tests := []struct {
+		name string
+		cfg  *CompositeConfig
+		want []*targetgroup.Group
+	}{
+		{
+			name: "union",
+			cfg: &CompositeConfig{
+				Operation: CompositeOpUnion,
+				Sources: []Configs{
+					source(&targetgroup.Group{Source: "s0", Targets: targets("a:80", "b:80")}),
+					source(&targetgroup.Group{Source: "s1", Targets: targets("c:80")}),
+				},
+			},
+			want: []*targetgroup.Group{
+				{Source: "composite/0/0/s0", Targets: targets("a:80", "b:80")},
+				{Source: "composite/1/0/s1", Targets: targets("c:80")},
+			},
+		},
+		{
+			name: "intersect",
+			cfg: &CompositeConfig{
+				Operation: CompositeOpIntersect,
+				Sources: []Configs{
+					source(&targetgroup.Group{Source: "s0", Targets: targets("a:80", "b:80", "c:80")}),
+					source(&targetgroup.Group{Source: "s1", Targets: targets("b:80", "c:80")}),
+					source(&targetgroup.Group{Source: "s2", Targets: targets("c:80", "d:80")}),
+				},
+			},
+			want: []*targetgroup.Group{
+				{Source: "composite/0/0/s0", Targets: targets("c:80")},
+			},
+		},
+		{
+			name: "exclude",
+			cfg: &CompositeConfig{
+				Operation: CompositeOpExclude,
+				Sources: []Configs{
+					source(&targetgroup.Group{Source: "s0", Targets: targets("a:80", "b:80", "c:80")}),
+					source(&targetgroup.Group{Source: "s1", Targets: targets("b:80")}),
+				},
+			},
+			want: []*targetgroup.Group{
+				{Source: "composite/0/0/s0", Targets: targets("a:80", "c:80")},
+			},
+		},
+		{
+			name: "exclude considers group labels",
+			cfg: &CompositeConfig{
+				Operation: CompositeOpExclude,
+				Sources: []Configs{
+					source(&targetgroup.Group{
+						Source:  "s0",
+						Labels:  model.LabelSet{"env": "prod"},
+						Targets: targets("a:80", "b:80"),
+					}),
+					source(&targetgroup.Group{Source: "s1", Targets: targets("b:80")}),
+				},
+			},
+			want: []*targetgroup.Group{
+				{
+					Source:  "composite/0/0/s0",
+					Labels:  model.LabelSet{"env": "prod"},
+					Targets: targets("a:80", "b:80"),
+				},
+			},
+		},
+		{
+			name: "exclude all targets keeps empty group",
+			cfg: &CompositeConfig{
+				Operation: CompositeOpExclude,
+				Sources: []Configs{
+					source(&targetgroup.Group{Source: "s0", Targets: targets("a:80")}),
+					source(&targetgroup.Group{Source: "s1", Targets: targets("a:80")}),
+				},
+			},
+			want: []*targetgroup.Group{
+				{Source: "composite/0/0/s0"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := test.cfg.NewDiscoverer(DiscovererOptions{
+				Logger: promslog.NewNopLogger(),
+				MechanismMetrics: map[string]DiscovererMetrics{
+					"static": &NoopDiscovererMetrics{},
+				},
+			})
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			up := make(chan []*targetgroup.Group)
+			go d.Run(ctx, up)
+
+			select {
+			case got := <-up:
+				require.Equal(t, test.want, got)
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for composite discovery update")
+			}
+		})
+	}
+}
+
+func TestCompositeDiscoveryRequiresMechanismMetrics(t *testing.T) {
+	t.Parallel()
+
+	cfg := &CompositeConfig{
+		Operation: CompositeOpUnion,
+		Sources:   []Configs{{StaticConfig{{Source: "s0"}}}},
+	}
+	_, err := cfg.NewDiscoverer(DiscovererOptions{Logger: promslog.NewNopLogger()})
+	require.ErrorContains(t, err, "mechanism metrics")
+}
@@ -0,0 +1,187 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+)
+
+// defaultUpdateHistoryLimit is the number of update diffs retained per SD
+// provider when no limit is configured explicitly.
+const defaultUpdateHistoryLimit = 20
+
+// TargetDiff describes how the labels of a single target changed in an
+// update.
+type TargetDiff struct {
+	// Address is the __address__ label of the target, or the full label set
+	// when the target carries no address.
+	Address string `json:"address"`
+	// ChangedLabels are the names of the labels whose values differ from the
+	// previous update, including labels that were added or removed.
+	ChangedLabels []string `json:"changedLabels"`
+}
+
+// GroupDiff describes how a single target group changed in an update.
+type GroupDiff struct {
+	// Source is the group identifier assigned by the SD provider.
+	Source string `json:"source"`
+	// Added is true when the previous update did not contain the group.
+	Added bool `json:"added,omitempty"`
+	// Removed is true when the update dropped the group entirely.
+	Removed bool `json:"removed,omitempty"`
+	// TargetsAdded and TargetsRemoved list the addresses of targets that
+	// appeared in and disappeared from the group.
+	TargetsAdded   []string `json:"targetsAdded,omitempty"`
+	TargetsRemoved []string `json:"targetsRemoved,omitempty"`
+	// TargetsChanged lists targets present in both updates whose labels
+	// changed.
+	TargetsChanged []TargetDiff `json:"targetsChanged,omitempty"`
+	// GroupLabelsChanged are the names of the group-wide labels whose values
+	// changed, including labels that were added or removed.
+	GroupLabelsChanged []string `json:"groupLabelsChanged,omitempty"`
+}
+
+// UpdateDiff describes one update received from an SD provider and how it
+// changed the target groups of one target set. Updates that leave all groups
+// unchanged, such as periodic re-sends from refresh-based providers, are not
+// recorded.
+type UpdateDiff struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Provider  string      `json:"provider"`
+	TargetSet string      `json:"targetSet"`
+	Groups    []GroupDiff `json:"groups"`
+}
+
+// UpdateHistory returns the update diffs recorded for the currently running
+// SD providers, ordered oldest first. At most the configured number of diffs
+// is retained per provider.
+func (m *Manager) UpdateHistory() []UpdateDiff {
+	m.targetsMtx.Lock()
+	defer m.targetsMtx.Unlock()
+
+	var history []UpdateDiff
+	for _, h := range m.updateHistory {
+		history = append(history, h...)
+	}
+	slices.SortFunc(history, func(a, b UpdateDiff) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	return history
+}
+
+// recordUpdate appends an update diff to the provider's history, evicting the
+// oldest entries beyond the limit. The caller must hold targetsMtx.
+func (m *Manager) recordUpdate(pk poolKey, groups []GroupDiff) {
+	h := append(m.updateHistory[pk.provider], UpdateDiff{
+		Timestamp: time.Now(),
+		Provider:  pk.provider,
+		TargetSet: pk.setName,
+		Groups:    groups,
+	})
+	if len(h) > m.updateHistoryLimit {
+		h = h[len(h)-m.updateHistoryLimit:]
+	}
+	m.updateHistory[pk.provider] = h
+}
+
+// diffGroups computes how a target group changed between the previously known
+// contents and an update. The second return value is false when nothing
+// changed.
+func diffGroups(before, after *targetgroup.Group) (GroupDiff, bool) {
+	d := GroupDiff{Source: after.Source}
+
+	if len(after.Targets) == 0 {
+		if before == nil {
+			// Deleting a group that was never seen is not a change.
+			return d, false
+		}
+		d.Removed = true
+		for _, t := range before.Targets {
+			d.TargetsRemoved = append(d.TargetsRemoved, targetKey(t))
+		}
+		slices.Sort(d.TargetsRemoved)
+		return d, true
+	}
+
+	if before == nil {
+		d.Added = true
+		for _, t := range after.Targets {
+			d.TargetsAdded = append(d.TargetsAdded, targetKey(t))
+		}
+		slices.Sort(d.TargetsAdded)
+		return d, true
+	}
+
+	beforeTargets := make(map[string]model.LabelSet, len(before.Targets))
+	for _, t := range before.Targets {
+		beforeTargets[targetKey(t)] = t
+	}
+	for _, t := range after.Targets {
+		k := targetKey(t)
+		old, ok := beforeTargets[k]
+		switch {
+		case !ok:
+			d.TargetsAdded = append(d.TargetsAdded, k)
+		default:
+			if changed := changedLabelNames(old, t); len(changed) > 0 {
+				d.TargetsChanged = append(d.TargetsChanged, TargetDiff{Address: k, ChangedLabels: changed})
+			}
+			delete(beforeTargets, k)
+		}
+	}
+	for k := range beforeTargets {
+		d.TargetsRemoved = append(d.TargetsRemoved, k)
+	}
+	slices.Sort(d.TargetsAdded)
+	slices.Sort(d.TargetsRemoved)
+	slices.SortFunc(d.TargetsChanged, func(a, b TargetDiff) int {
+		return strings.Compare(a.Address, b.Address)
+	})
+	d.GroupLabelsChanged = changedLabelNames(before.Labels, after.Labels)
+
+	changed := d.TargetsAdded != nil || d.TargetsRemoved != nil || d.TargetsChanged != nil || d.GroupLabelsChanged != nil
+	return d, changed
+}
+
+// targetKey identifies a target within a group for diffing purposes.
+func targetKey(lset model.LabelSet) string {
+	if a, ok := lset[model.AddressLabel]; ok {
+		return string(a)
+	}
+	return lset.String()
+}
+
+// changedLabelNames returns the sorted names of the labels whose values
+// differ between the two label sets.
+func changedLabelNames(before, after model.LabelSet) []string {
+	var names []string
+	for n, v := range after {
+		if old, ok := before[n]; !ok || old != v {
+			names = append(names, string(n))
+		}
+	}
+	for n := range before {
+		if _, ok := after[n]; !ok {
+			names = append(names, string(n))
+		}
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,187 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+)
+
+func TestDiffGroups(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		before     *targetgroup.Group
+		after      *targetgroup.Group
+		expDiff    GroupDiff
+		expChanged bool
+	}{
+		{
+			name: "new group",
+			after: &targetgroup.Group{
+				Source:  "tg1",
+				Targets: []model.LabelSet{{model.AddressLabel: "b:80"}, {model.AddressLabel: "a:80"}},
+			},
+			expDiff: GroupDiff{
+				Source:       "tg1",
+				Added:        true,
+				TargetsAdded: []string{"a:80", "b:80"},
+			},
+			expChanged: true,
+		},
+		{
+			name: "group removed",
+			before: &targetgroup.Group{
+				Source:  "tg1",
+				Targets: []model.LabelSet{{model.AddressLabel: "a:80"}},
+			},
+			after: &targetgroup.Group{Source: "tg1"},
+			expDiff: GroupDiff{
+				Source:         "tg1",
+				Removed:        true,
+				TargetsRemoved: []string{"a:80"},
+			},
+			expChanged: true,
+		},
+		{
+			name:       "removing an unknown group is not a change",
+			after:      &targetgroup.Group{Source: "tg1"},
+			expDiff:    GroupDiff{Source: "tg1"},
+			expChanged: false,
+		},
+		{
+			name: "targets added, removed and relabelled",
+			before: &targetgroup.Group{
+				Source: "tg1",
+				Targets: []model.LabelSet{
+					{model.AddressLabel: "a:80", "env": "prod"},
+					{model.AddressLabel: "b:80"},
+				},
+			},
+			after: &targetgroup.Group{
+				Source: "tg1",
+				Targets: []model.LabelSet{
+					{model.AddressLabel: "a:80", "env": "canary", "zone": "eu"},
+					{model.AddressLabel: "c:80"},
+				},
+			},
+			expDiff: GroupDiff{
+				Source:         "tg1",
+				TargetsAdded:   []string{"c:80"},
+				TargetsRemoved: []string{"b:80"},
+				TargetsChanged: []TargetDiff{
+					{Address: "a:80", ChangedLabels: []string{"env", "zone"}},
+				},
+			},
+			expChanged: true,
+		},
+		{
+			name: "group labels changed",
+			before: &targetgroup.Group{
+				Source:  "tg1",
+				Targets: []model.LabelSet{{model.AddressLabel: "a:80"}},
+				Labels:  model.LabelSet{"region": "eu"},
+			},
+			after: &targetgroup.Group{
+				Source:  "tg1",
+				Targets: []model.LabelSet{{model.AddressLabel: "a:80"}},
+				Labels:  model.LabelSet{"region": "us"},
+			},
+			expDiff: GroupDiff{
+				Source:             "tg1",
+				GroupLabelsChanged: []string{"region"},
+			},
+			expChanged: true,
+		},
+		{
+			name: "identical re-send is not a change",
+			before: &targetgroup.Group{
+				Source:  "tg1",
+				Targets: []model.LabelSet{{model.AddressLabel: "a:80", "env": "prod"}},
+			},
+			after: &targetgroup.Group{
+				Source:  "tg1",
+				Targets: []model.LabelSet{{model.AddressLabel: "a:80", "env": "prod"}},
+			},
+			expDiff:    GroupDiff{Source: "tg1"},
+			expChanged: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, changed := diffGroups(tc.before, tc.after)
+			require.Equal(t, tc.expChanged, changed)
+			require.Equal(t, tc.expDiff, d)
+		})
+	}
+}
+
+func TestManagerUpdateHistory(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+	sdMetrics := NewTestMetrics(t, reg)
+	mgr := NewManager(context.Background(), nil, reg, sdMetrics, UpdateHistoryLimit(2))
+	require.NotNil(t, mgr)
+
+	pk := poolKey{setName: "job1", provider: "mock/0"}
+	mgr.updateGroup(pk, []*targetgroup.Group{{
+		Source:  "tg1",
+		Targets: []model.LabelSet{{model.AddressLabel: "a:80"}},
+	}})
+
+	history := mgr.UpdateHistory()
+	require.Len(t, history, 1)
+	require.Equal(t, "mock/0", history[0].Provider)
+	require.Equal(t, "job1", history[0].TargetSet)
+	require.Equal(t, []GroupDiff{{Source: "tg1", Added: true, TargetsAdded: []string{"a:80"}}}, history[0].Groups)
+
+	// An identical re-send must not be recorded.
+	mgr.updateGroup(pk, []*targetgroup.Group{{
+		Source:  "tg1",
+		Targets: []model.LabelSet{{model.AddressLabel: "a:80"}},
+	}})
+	require.Len(t, mgr.UpdateHistory(), 1)
+
+	// Two further changes evict the oldest entry as the limit is 2.
+	mgr.updateGroup(pk, []*targetgroup.Group{{
+		Source:  "tg1",
+		Targets: []model.LabelSet{{model.AddressLabel: "a:80"}, {model.AddressLabel: "b:80"}},
+	}})
+	mgr.updateGroup(pk, []*targetgroup.Group{{Source: "tg1"}})
+
+	history = mgr.UpdateHistory()
+	require.Len(t, history, 2)
+	require.Equal(t, []GroupDiff{{Source: "tg1", TargetsAdded: []string{"b:80"}}}, history[0].Groups)
+	require.Equal(t, []GroupDiff{{Source: "tg1", Removed: true, TargetsRemoved: []string{"a:80", "b:80"}}}, history[1].Groups)
+}
+
+func TestManagerUpdateHistoryDisabled(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+	sdMetrics := NewTestMetrics(t, reg)
+	mgr := NewManager(context.Background(), nil, reg, sdMetrics, UpdateHistoryLimit(0))
+	require.NotNil(t, mgr)
+
+	mgr.updateGroup(poolKey{setName: "job1", provider: "mock/0"}, []*targetgroup.Group{{
+		Source:  "tg1",
+		Targets: []model.LabelSet{{model.AddressLabel: "a:80"}},
+	}})
+	require.Empty(t, mgr.UpdateHistory())
+}
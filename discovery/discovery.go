@@ -57,6 +57,12 @@ type DiscovererOptions struct {
 
 	// SetName identifies this discoverer set.
 	SetName string
+
+	// MechanismMetrics gives access to the metrics of all registered service
+	// discovery mechanisms, keyed by mechanism name. It is set by the
+	// discovery manager and used by meta-discoverers, such as composite, that
+	// instantiate discoverers of other mechanisms.
+	MechanismMetrics map[string]DiscovererMetrics
 }
 
 // RefreshMetrics are used by the "refresh" package.
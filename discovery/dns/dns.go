@@ -14,11 +14,15 @@
 package dns
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,6 +32,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/common/promslog"
+	"github.com/prometheus/common/version"
 
 	"github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/discovery/refresh"
@@ -50,10 +55,13 @@ const (
 	namespace = "prometheus"
 )
 
+var userAgent = version.PrometheusUserAgent()
+
 // DefaultSDConfig is the default DNS SD configuration.
 var DefaultSDConfig = SDConfig{
 	RefreshInterval: model.Duration(30 * time.Second),
 	Type:            "SRV",
+	Timeout:         model.Duration(5 * time.Second),
 }
 
 func init() {
@@ -66,6 +74,13 @@ type SDConfig struct {
 	RefreshInterval model.Duration `yaml:"refresh_interval,omitempty"`
 	Type            string         `yaml:"type"`
 	Port            int            `yaml:"port"` // Ignored for SRV records
+	// Resolvers are the DNS servers to query instead of the system resolver.
+	// Each entry is either a server address, with port 53 assumed when none
+	// is given, or an https:// URL of a DNS-over-HTTPS endpoint. Names are
+	// queried as given; the system search path does not apply.
+	Resolvers []string `yaml:"resolvers,omitempty"`
+	// Timeout is the timeout for a single DNS query.
+	Timeout model.Duration `yaml:"timeout,omitempty"`
 }
 
 // NewDiscovererMetrics implements discovery.Config.
@@ -101,18 +116,67 @@ func (c *SDConfig) UnmarshalYAML(unmarshal func(any) error) error {
 	default:
 		return fmt.Errorf("invalid DNS-SD records type %s", c.Type)
 	}
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be greater than 0")
+	}
+	for _, r := range c.Resolvers {
+		if _, err := parseResolver(r); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// resolver is a single upstream DNS server: either a classic DNS server
+// address or a DNS-over-HTTPS endpoint.
+type resolver struct {
+	// addr is the host:port of a classic DNS server. Empty for DoH.
+	addr string
+	// dohURL is the URL of a DNS-over-HTTPS endpoint. Empty for classic DNS.
+	dohURL string
+}
+
+// String returns the resolver's address or URL for logging.
+func (r resolver) String() string {
+	if r.dohURL != "" {
+		return r.dohURL
+	}
+	return r.addr
+}
+
+// parseResolver parses a resolvers entry: an https:// URL is a DoH endpoint,
+// anything else is a server address with port 53 assumed when none is given.
+func parseResolver(s string) (resolver, error) {
+	if strings.HasPrefix(s, "https://") {
+		u, err := url.Parse(s)
+		if err != nil {
+			return resolver{}, fmt.Errorf("invalid DoH resolver URL %q: %w", s, err)
+		}
+		if u.Host == "" {
+			return resolver{}, fmt.Errorf("invalid DoH resolver URL %q: host is missing", s)
+		}
+		return resolver{dohURL: s}, nil
+	}
+	if s == "" {
+		return resolver{}, errors.New("empty resolver address")
+	}
+	if _, _, err := net.SplitHostPort(s); err == nil {
+		return resolver{addr: s}, nil
+	}
+	return resolver{addr: net.JoinHostPort(s, "53")}, nil
+}
+
 // Discovery periodically performs DNS-SD requests. It implements
 // the Discoverer interface.
 type Discovery struct {
 	*refresh.Discovery
-	names   []string
-	port    int
-	qtype   uint16
-	logger  *slog.Logger
-	metrics *dnsMetrics
+	names     []string
+	port      int
+	qtype     uint16
+	resolvers []resolver
+	timeout   time.Duration
+	logger    *slog.Logger
+	metrics   *dnsMetrics
 
 	lookupFn func(name string, qtype uint16, logger *slog.Logger) (*dns.Msg, error)
 }
@@ -141,13 +205,30 @@ func NewDiscovery(conf SDConfig, opts discovery.DiscovererOptions) (*Discovery,
 	case "NS":
 		qtype = dns.TypeNS
 	}
+	resolvers := make([]resolver, 0, len(conf.Resolvers))
+	for _, s := range conf.Resolvers {
+		r, err := parseResolver(s)
+		if err != nil {
+			return nil, err
+		}
+		resolvers = append(resolvers, r)
+	}
+
 	d := &Discovery{
-		names:    conf.Names,
-		qtype:    qtype,
-		port:     conf.Port,
-		logger:   opts.Logger,
-		lookupFn: lookupWithSearchPath,
-		metrics:  m,
+		names:     conf.Names,
+		qtype:     qtype,
+		port:      conf.Port,
+		resolvers: resolvers,
+		timeout:   time.Duration(conf.Timeout),
+		logger:    opts.Logger,
+		metrics:   m,
+	}
+	if len(resolvers) > 0 {
+		d.lookupFn = d.lookupWithResolvers
+	} else {
+		d.lookupFn = func(name string, qtype uint16, logger *slog.Logger) (*dns.Msg, error) {
+			return lookupWithSearchPath(name, qtype, d.timeout, logger)
+		}
 	}
 
 	d.Discovery = refresh.NewDiscovery(
@@ -289,7 +370,7 @@ func (d *Discovery) refreshOne(ctx context.Context, name string, ch chan<- *targ
 // error will be generic-looking, because trying to return all the errors
 // returned by the combination of all name permutations and servers is a
 // nightmare.
-func lookupWithSearchPath(name string, qtype uint16, logger *slog.Logger) (*dns.Msg, error) {
+func lookupWithSearchPath(name string, qtype uint16, timeout time.Duration, logger *slog.Logger) (*dns.Msg, error) {
 	conf, err := dns.ClientConfigFromFile(resolvConf)
 	if err != nil {
 		return nil, fmt.Errorf("could not load resolv.conf: %w", err)
@@ -298,7 +379,7 @@ func lookupWithSearchPath(name string, qtype uint16, logger *slog.Logger) (*dns.
 	allResponsesValid := true
 
 	for _, lname := range conf.NameList(name) {
-		response, err := lookupFromAnyServer(lname, qtype, conf, logger)
+		response, err := lookupFromAnyServer(lname, qtype, conf, timeout, logger)
 
 		switch {
 		case err != nil:
@@ -338,8 +419,8 @@ func lookupWithSearchPath(name string, qtype uint16, logger *slog.Logger) (*dns.
 // A non-viable answer is "anything else", which encompasses both various
 // system-level problems (like network timeouts) and also
 // valid-but-unexpected DNS responses (SERVFAIL, REFUSED, etc).
-func lookupFromAnyServer(name string, qtype uint16, conf *dns.ClientConfig, logger *slog.Logger) (*dns.Msg, error) {
-	client := &dns.Client{}
+func lookupFromAnyServer(name string, qtype uint16, conf *dns.ClientConfig, timeout time.Duration, logger *slog.Logger) (*dns.Msg, error) {
+	client := &dns.Client{Timeout: timeout}
 
 	for _, server := range conf.Servers {
 		servAddr := net.JoinHostPort(server, conf.Port)
@@ -358,6 +439,80 @@ func lookupFromAnyServer(name string, qtype uint16, conf *dns.ClientConfig, logg
 	return nil, fmt.Errorf("could not resolve %s: no servers returned a viable answer", name)
 }
 
+// lookupWithResolvers resolves the given name against the configured
+// resolvers instead of the system resolver. The name is queried as given:
+// the system search path does not apply. As in lookupFromAnyServer, the
+// first viable answer (SUCCESS or NXDOMAIN) wins, and an error is returned
+// only when no resolver produced one.
+func (d *Discovery) lookupWithResolvers(name string, qtype uint16, logger *slog.Logger) (*dns.Msg, error) {
+	for _, r := range d.resolvers {
+		msg, err := d.askResolver(name, qtype, r)
+		if err != nil {
+			logger.Warn("DNS resolution failed", "resolver", r.String(), "name", name, "err", err)
+			continue
+		}
+
+		if msg.Rcode == dns.RcodeSuccess || msg.Rcode == dns.RcodeNameError {
+			return msg, nil
+		}
+	}
+
+	return nil, fmt.Errorf("could not resolve %s: no resolvers returned a viable answer", name)
+}
+
+// askResolver sends a query for the given name to a single resolver, over
+// DNS-over-HTTPS or classic DNS depending on the resolver's kind.
+func (d *Discovery) askResolver(name string, qtype uint16, r resolver) (*dns.Msg, error) {
+	if r.dohURL != "" {
+		msg := &dns.Msg{}
+		msg.SetQuestion(dns.Fqdn(name), qtype)
+		msg.SetEdns0(dns.DefaultMsgSize, false)
+		return exchangeDoH(msg, r.dohURL, d.timeout)
+	}
+	return askServerForName(name, qtype, &dns.Client{Timeout: d.timeout}, r.addr, true)
+}
+
+// exchangeDoH sends the query to a DNS-over-HTTPS endpoint as an RFC 8484
+// POST request and returns the unpacked response.
+func exchangeDoH(msg *dns.Msg, dohURL string, timeout time.Duration) (*dns.Msg, error) {
+	body, err := msg.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, dohURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH server returned HTTP status %s", resp.Status)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	response := &dns.Msg{}
+	if err := response.Unpack(b); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
 // askServerForName makes a request to a specific DNS server for a specific
 // name (and qtype).  Retries with TCP in the event of response truncation,
 // but otherwise just sends back whatever the server gave, whether that be a
@@ -16,8 +16,11 @@ package dns
 import (
 	"context"
 	"errors"
+	"io"
 	"log/slog"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -276,6 +279,125 @@ func TestDNS(t *testing.T) {
 	}
 }
 
+// testDNSHandler answers every question with a single A record pointing at
+// 192.0.2.1, mimicking a split-horizon resolver that knows internal names.
+func testDNSHandler(w dns.ResponseWriter, req *dns.Msg) {
+	m := &dns.Msg{}
+	m.SetReply(req)
+	rr, err := dns.NewRR(req.Question[0].Name + " 3600 IN A 192.0.2.1")
+	if err != nil {
+		panic(err)
+	}
+	m.Answer = append(m.Answer, rr)
+	w.WriteMsg(m)
+}
+
+func TestDNSCustomResolver(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &dns.Server{PacketConn: pc, Handler: dns.HandlerFunc(testDNSHandler)}
+	go server.ActivateAndServe()
+	defer server.Shutdown()
+
+	config := SDConfig{
+		Names:           []string{"web.internal.example.com."},
+		RefreshInterval: model.Duration(time.Minute),
+		Port:            80,
+		Type:            "A",
+		Resolvers:       []string{pc.LocalAddr().String()},
+		Timeout:         model.Duration(5 * time.Second),
+	}
+
+	reg := prometheus.NewRegistry()
+	refreshMetrics := discovery.NewRefreshMetrics(reg)
+	metrics := config.NewDiscovererMetrics(reg, refreshMetrics)
+	require.NoError(t, metrics.Register())
+	defer metrics.Unregister()
+
+	sd, err := NewDiscovery(config, discovery.DiscovererOptions{
+		Metrics: metrics,
+		SetName: "dns",
+	})
+	require.NoError(t, err)
+
+	tgs, err := sd.refresh(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []*targetgroup.Group{
+		{
+			Source: "web.internal.example.com.",
+			Targets: []model.LabelSet{
+				{
+					"__address__":                  "192.0.2.1:80",
+					"__meta_dns_name":              "web.internal.example.com.",
+					"__meta_dns_srv_record_target": "",
+					"__meta_dns_srv_record_port":   "",
+					"__meta_dns_mx_record_target":  "",
+					"__meta_dns_ns_record_target":  "",
+				},
+			},
+		},
+	}, tgs)
+}
+
+func TestDNSDoHResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/dns-message", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req := &dns.Msg{}
+		require.NoError(t, req.Unpack(body))
+
+		m := &dns.Msg{}
+		m.SetReply(req)
+		rr, err := dns.NewRR(req.Question[0].Name + " 3600 IN A 192.0.2.2")
+		require.NoError(t, err)
+		m.Answer = append(m.Answer, rr)
+		packed, err := m.Pack()
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(packed)
+	}))
+	defer server.Close()
+	defer http.DefaultTransport.(*http.Transport).CloseIdleConnections()
+
+	config := SDConfig{
+		Names:           []string{"db.internal.example.com."},
+		RefreshInterval: model.Duration(time.Minute),
+		Port:            5432,
+		Type:            "A",
+		Resolvers:       []string{"https://doh.example.com/dns-query"},
+		Timeout:         model.Duration(5 * time.Second),
+	}
+
+	reg := prometheus.NewRegistry()
+	refreshMetrics := discovery.NewRefreshMetrics(reg)
+	metrics := config.NewDiscovererMetrics(reg, refreshMetrics)
+	require.NoError(t, metrics.Register())
+	defer metrics.Unregister()
+
+	sd, err := NewDiscovery(config, discovery.DiscovererOptions{
+		Metrics: metrics,
+		SetName: "dns",
+	})
+	require.NoError(t, err)
+	// Point the DoH resolver at the test server, which has no TLS.
+	sd.resolvers = []resolver{{dohURL: server.URL}}
+
+	tgs, err := sd.refresh(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tgs, 1)
+	require.Equal(t, []model.LabelSet{
+		{
+			"__address__":                  "192.0.2.2:5432",
+			"__meta_dns_name":              "db.internal.example.com.",
+			"__meta_dns_srv_record_target": "",
+			"__meta_dns_srv_record_port":   "",
+			"__meta_dns_mx_record_target":  "",
+			"__meta_dns_ns_record_target":  "",
+		},
+	}, tgs[0].Targets)
+}
+
 func TestSDConfigUnmarshalYAML(t *testing.T) {
 	marshal := func(c SDConfig) []byte {
 		d, err := yaml.Marshal(c)
@@ -354,6 +476,42 @@ func TestSDConfigUnmarshalYAML(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "valid custom resolvers",
+			input: SDConfig{
+				Names:     []string{"a.example.com"},
+				Type:      "SRV",
+				Resolvers: []string{"10.0.0.53", "10.0.0.54:5353", "https://doh.example.com/dns-query"},
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid DoH resolver without host",
+			input: SDConfig{
+				Names:     []string{"a.example.com"},
+				Type:      "SRV",
+				Resolvers: []string{"https:///dns-query"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid empty resolver",
+			input: SDConfig{
+				Names:     []string{"a.example.com"},
+				Type:      "SRV",
+				Resolvers: []string{""},
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid negative timeout",
+			input: SDConfig{
+				Names:   []string{"a.example.com"},
+				Type:    "SRV",
+				Timeout: model.Duration(-1 * time.Second),
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, c := range cases {
@@ -40,7 +40,7 @@ import (
 )
 
 var (
-	patFileSDName = regexp.MustCompile(`^[^*]*(\*[^/]*)?\.(json|yml|yaml|JSON|YML|YAML)$`)
+	patFileSDName = regexp.MustCompile(`^[^*]*(\*\*/)*[^*]*(\*[^/]*)?\.(json|yml|yaml|JSON|YML|YAML)$`)
 
 	// DefaultSDConfig is the default file SD configuration.
 	DefaultSDConfig = SDConfig{
@@ -99,6 +99,11 @@ func (c *SDConfig) UnmarshalYAML(unmarshal func(any) error) error {
 
 const fileSDFilepathLabel = model.MetaLabelPrefix + "filepath"
 
+// watchDebounceInterval is how long the discovery waits after a watch event
+// before refreshing, so that a burst of events (e.g. a GitOps sync rewriting
+// many files at once) results in a single refresh.
+const watchDebounceInterval = 100 * time.Millisecond
+
 // TimestampCollector is a Custom Collector for Timestamps of the files.
 // TODO(ptodev): Now that each file SD has its own TimestampCollector
 // inside discovery/file/metrics.go, we can refactor this collector
@@ -207,7 +212,7 @@ func NewDiscovery(conf *SDConfig, logger *slog.Logger, metrics discovery.Discove
 func (d *Discovery) listFiles() []string {
 	var paths []string
 	for _, p := range d.paths {
-		files, err := filepath.Glob(p)
+		files, err := expandGlob(p)
 		if err != nil {
 			d.logger.Error("Error expanding glob", "glob", p, "err", err)
 			continue
@@ -217,6 +222,83 @@ func (d *Discovery) listFiles() []string {
 	return paths
 }
 
+// expandGlob returns all files matching the pattern. In addition to the
+// syntax understood by filepath.Glob, a "**" path segment matches any number
+// of nested directories, including none.
+func expandGlob(pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		return filepath.Glob(pattern)
+	}
+	root := globRoot(pattern)
+	if _, err := os.Stat(root); err != nil {
+		// Same behaviour as filepath.Glob: a pattern rooted in a directory
+		// that does not exist matches nothing.
+		return nil, nil
+	}
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, de os.DirEntry, err error) error {
+		if err != nil || de.IsDir() {
+			// Skip unreadable entries; the periodic refresh will retry them.
+			return nil
+		}
+		if globMatches(pattern, path) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// globRoot returns the longest directory prefix of the pattern that contains
+// no glob meta characters, e.g. "my/path" for "my/path/**/tg_*.json".
+func globRoot(pattern string) string {
+	root := "."
+	for i, seg := range strings.Split(pattern, "/") {
+		if strings.ContainsAny(seg, "*?[") {
+			break
+		}
+		if i == 0 {
+			root = seg
+			if root == "" {
+				root = "/"
+			}
+			continue
+		}
+		root = filepath.Join(root, seg)
+	}
+	return root
+}
+
+// globMatches reports whether path matches pattern, where a "**" path segment
+// in pattern matches any number of directories, including none, and all other
+// segments use filepath.Match semantics.
+func globMatches(pattern, path string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(path, "/"))
+}
+
+func matchSegments(pattern, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			if matchSegments(pattern[1:], path) {
+				return true
+			}
+			if len(path) == 0 {
+				return false
+			}
+			path = path[1:]
+			continue
+		}
+		if len(path) == 0 {
+			return false
+		}
+		if ok, err := filepath.Match(pattern[0], path[0]); err != nil || !ok {
+			return false
+		}
+		pattern, path = pattern[1:], path[1:]
+	}
+	return len(path) == 0
+}
+
 // watchFiles sets watches on all full paths or directories that were configured for
 // this file discovery.
 func (d *Discovery) watchFiles() {
@@ -224,6 +306,14 @@ func (d *Discovery) watchFiles() {
 		panic("no watcher configured")
 	}
 	for _, p := range d.paths {
+		if strings.Contains(p, "**") {
+			// Watches are not recursive, so watch the static root of the
+			// pattern and every directory below it. Directories created later
+			// trigger an event on their parent and are picked up by the
+			// refresh that follows.
+			d.watchRecursive(globRoot(p))
+			continue
+		}
 		if dir, _ := filepath.Split(p); dir != "" {
 			p = dir
 		} else {
@@ -235,6 +325,22 @@ func (d *Discovery) watchFiles() {
 	}
 }
 
+// watchRecursive sets watches on the given directory and all directories below it.
+func (d *Discovery) watchRecursive(root string) {
+	err := filepath.WalkDir(root, func(path string, de os.DirEntry, err error) error {
+		if err != nil || !de.IsDir() {
+			return nil
+		}
+		if err := d.watcher.Add(path); err != nil {
+			d.logger.Error("Error adding file watch", "path", path, "err", err)
+		}
+		return nil
+	})
+	if err != nil {
+		d.logger.Error("Error walking directory for file watches", "path", root, "err", err)
+	}
+}
+
 // Run implements the Discoverer interface.
 func (d *Discovery) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
 	watcher, err := fsnotify.NewWatcher()
@@ -251,6 +357,13 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
 	ticker := time.NewTicker(d.interval)
 	defer ticker.Stop()
 
+	// The debounce timer is created stopped and armed by watch events.
+	debounce := time.NewTimer(0)
+	if !debounce.Stop() {
+		<-debounce.C
+	}
+	defer debounce.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -269,7 +382,12 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
 			// Changes to a file can spawn various sequences of events with
 			// different combinations of operations. For all practical purposes
 			// this is inaccurate.
-			// The most reliable solution is to reload everything if anything happens.
+			// The most reliable solution is to reload everything if anything
+			// happens, debounced so that a burst of events triggers a single
+			// reload.
+			debounce.Reset(watchDebounceInterval)
+
+		case <-debounce.C:
 			d.refresh(ctx, ch)
 
 		case <-ticker.C:
@@ -357,6 +475,7 @@ func (d *Discovery) refresh(ctx context.Context, ch chan<- []*targetgroup.Group)
 		if !ok || n > m {
 			d.logger.Debug("file_sd refresh found file that should be removed", "file", f)
 			d.deleteTimestamp(f)
+			d.metrics.fileSDParseErrorsCount.DeleteLabelValues(f)
 			for i := m; i < n; i++ {
 				select {
 				case ch <- []*targetgroup.Group{{Source: fileSource(f, i)}}:
@@ -395,10 +514,12 @@ func (d *Discovery) readFile(filename string) ([]*targetgroup.Group, error) {
 	switch ext := filepath.Ext(filename); strings.ToLower(ext) {
 	case ".json":
 		if err := json.Unmarshal(content, &targetGroups); err != nil {
+			d.metrics.fileSDParseErrorsCount.WithLabelValues(filename).Inc()
 			return nil, err
 		}
 	case ".yml", ".yaml":
 		if err := yaml.UnmarshalStrict(content, &targetGroups); err != nil {
+			d.metrics.fileSDParseErrorsCount.WithLabelValues(filename).Inc()
 			return nil, err
 		}
 	default:
@@ -77,6 +77,7 @@ func (t *testRunner) copyFileTo(src, name string) string {
 	require.NoError(t, err)
 
 	dst := filepath.Join(t.dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0o755))
 	t.atomicWrite(dst, content)
 
 	return dst
@@ -475,6 +476,49 @@ func TestUpdateFileWithPartialWrites(t *testing.T) {
 	)
 }
 
+func TestRecursiveGlob(t *testing.T) {
+	t.Parallel()
+
+	runner := newTestRunner(t)
+	sdFile := runner.copyFileTo("fixtures/valid.yml", filepath.Join("teams", "payments", "valid.yml"))
+
+	runner.run(filepath.Join("**", "*.yml"))
+	defer runner.stop()
+
+	// Verify that we receive the target groups from the nested directory.
+	runner.requireUpdate(time.Time{}, validTg(sdFile))
+
+	// Verify that a file appearing in a new subdirectory is picked up via the
+	// directory watches.
+	ref := runner.lastReceive()
+	sdFile2 := runner.copyFileTo("fixtures/valid2.yml", filepath.Join("teams", "search", "valid2.yml"))
+	runner.requireUpdate(ref, append(validTg(sdFile), valid2Tg(sdFile2)...))
+}
+
+func TestGlobMatches(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		pattern, path string
+		match         bool
+	}{
+		{pattern: "path/*.yml", path: "path/tg.yml", match: true},
+		{pattern: "path/*.yml", path: "path/sub/tg.yml", match: false},
+		{pattern: "path/**/*.yml", path: "path/tg.yml", match: true},
+		{pattern: "path/**/*.yml", path: "path/sub/tg.yml", match: true},
+		{pattern: "path/**/*.yml", path: "path/a/b/c/tg.yml", match: true},
+		{pattern: "path/**/*.yml", path: "other/tg.yml", match: false},
+		{pattern: "path/**/tg_*.json", path: "path/sub/tg_1.json", match: true},
+		{pattern: "path/**/tg_*.json", path: "path/sub/other.json", match: false},
+		{pattern: "**/*.yml", path: "tg.yml", match: true},
+		{pattern: "**/*.yml", path: "a/b/tg.yml", match: true},
+	} {
+		t.Run(tc.pattern+" "+tc.path, func(t *testing.T) {
+			require.Equal(t, tc.match, globMatches(tc.pattern, tc.path))
+		})
+	}
+}
+
 func TestRemoveFile(t *testing.T) {
 	t.Parallel()
 
@@ -23,6 +23,7 @@ var _ discovery.DiscovererMetrics = (*fileMetrics)(nil)
 
 type fileMetrics struct {
 	fileSDReadErrorsCount  prometheus.Counter
+	fileSDParseErrorsCount *prometheus.CounterVec
 	fileSDScanDuration     prometheus.Summary
 	fileWatcherErrorsCount prometheus.Counter
 	fileSDTimeStamp        *TimestampCollector
@@ -37,6 +38,13 @@ func newDiscovererMetrics(reg prometheus.Registerer, _ discovery.RefreshMetricsI
 				Name: "prometheus_sd_file_read_errors_total",
 				Help: "The number of File-SD read errors.",
 			}),
+		fileSDParseErrorsCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "prometheus_sd_file_parse_errors_total",
+				Help: "The number of File-SD parse failures per file.",
+			},
+			[]string{"file"},
+		),
 		fileSDScanDuration: prometheus.NewSummary(
 			prometheus.SummaryOpts{
 				Name:       "prometheus_sd_file_scan_duration_seconds",
@@ -53,6 +61,7 @@ func newDiscovererMetrics(reg prometheus.Registerer, _ discovery.RefreshMetricsI
 
 	fm.metricRegisterer = discovery.NewMetricRegisterer(reg, []prometheus.Collector{
 		fm.fileSDReadErrorsCount,
+		fm.fileSDParseErrorsCount,
 		fm.fileSDScanDuration,
 		fm.fileWatcherErrorsCount,
 		fm.fileSDTimeStamp,
@@ -19,8 +19,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -56,6 +58,10 @@ type SDConfig struct {
 	HTTPClientConfig config.HTTPClientConfig `yaml:",inline"`
 	RefreshInterval  model.Duration          `yaml:"refresh_interval,omitempty"`
 	URL              string                  `yaml:"url"`
+	// Delta enables the delta protocol: instead of a JSON list of target
+	// groups, the endpoint returns an object with added and removed groups
+	// keyed by ID, so large target sets are not resent on every refresh.
+	Delta bool `yaml:"delta,omitempty"`
 }
 
 // NewDiscovererMetrics implements discovery.Config.
@@ -111,6 +117,30 @@ type Discovery struct {
 	refreshInterval time.Duration
 	tgLastLength    int
 	metrics         *httpMetrics
+
+	// lastETag is the ETag of the last successful response, sent back as
+	// If-None-Match so unchanged target sets are answered with 304.
+	lastETag string
+	// lastGroups is the last full target group set, replayed when the
+	// endpoint responds with 304 Not Modified.
+	lastGroups []*targetgroup.Group
+
+	// delta indicates that the endpoint speaks the delta protocol, and
+	// groups holds the current set keyed by the server-assigned group ID.
+	delta  bool
+	groups map[string]*targetgroup.Group
+}
+
+// deltaResponse is the response body of a delta-mode HTTP SD endpoint.
+type deltaResponse struct {
+	// Full indicates that Added holds the complete target group set and any
+	// previous state must be discarded. The first response to a request
+	// without an If-None-Match header must be full.
+	Full bool `json:"full"`
+	// Added maps group IDs to added or updated target groups.
+	Added map[string]*targetgroup.Group `json:"added"`
+	// Removed lists IDs of target groups that no longer exist.
+	Removed []string `json:"removed"`
 }
 
 // NewDiscovery returns a new HTTP discovery for the given config.
@@ -134,6 +164,8 @@ func NewDiscovery(conf *SDConfig, opts discovery.DiscovererOptions) (*Discovery,
 		url:             conf.URL,
 		client:          client,
 		refreshInterval: time.Duration(conf.RefreshInterval), // Stored to be sent as headers.
+		delta:           conf.Delta,
+		groups:          map[string]*targetgroup.Group{},
 		metrics:         m,
 	}
 
@@ -158,6 +190,9 @@ func (d *Discovery) Refresh(ctx context.Context) ([]*targetgroup.Group, error) {
 	req.Header.Set("User-Agent", userAgent)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("X-Prometheus-Refresh-Interval-Seconds", strconv.FormatFloat(d.refreshInterval.Seconds(), 'f', -1, 64))
+	if d.lastETag != "" {
+		req.Header.Set("If-None-Match", d.lastETag)
+	}
 
 	resp, err := d.client.Do(req.WithContext(ctx))
 	if err != nil {
@@ -169,6 +204,12 @@ func (d *Discovery) Refresh(ctx context.Context) ([]*targetgroup.Group, error) {
 		resp.Body.Close()
 	}()
 
+	if resp.StatusCode == http.StatusNotModified {
+		// The target set is unchanged since the last response; replay the
+		// last known state.
+		return d.lastGroups, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		d.metrics.failuresCount.Inc()
 		return nil, fmt.Errorf("server returned HTTP status %s", resp.Status)
@@ -187,36 +228,107 @@ func (d *Discovery) Refresh(ctx context.Context) ([]*targetgroup.Group, error) {
 
 	var targetGroups []*targetgroup.Group
 
-	if err := json.Unmarshal(b, &targetGroups); err != nil {
-		d.metrics.failuresCount.Inc()
-		return nil, err
-	}
-
-	for i, tg := range targetGroups {
-		if tg == nil {
+	if d.delta {
+		targetGroups, err = d.applyDelta(b)
+		if err != nil {
+			d.metrics.failuresCount.Inc()
+			return nil, err
+		}
+	} else {
+		if err := json.Unmarshal(b, &targetGroups); err != nil {
 			d.metrics.failuresCount.Inc()
-			err = errors.New("nil target group item found")
 			return nil, err
 		}
 
-		tg.Source = urlSource(d.url, i)
-		if tg.Labels == nil {
-			tg.Labels = model.LabelSet{}
+		for i, tg := range targetGroups {
+			if tg == nil {
+				d.metrics.failuresCount.Inc()
+				err = errors.New("nil target group item found")
+				return nil, err
+			}
+
+			tg.Source = urlSource(d.url, i)
+			if tg.Labels == nil {
+				tg.Labels = model.LabelSet{}
+			}
+			tg.Labels[httpSDURLLabel] = model.LabelValue(d.url)
 		}
-		tg.Labels[httpSDURLLabel] = model.LabelValue(d.url)
+
+		// Generate empty updates for sources that disappeared.
+		l := len(targetGroups)
+		for i := l; i < d.tgLastLength; i++ {
+			targetGroups = append(targetGroups, &targetgroup.Group{Source: urlSource(d.url, i)})
+		}
+		d.tgLastLength = l
 	}
 
-	// Generate empty updates for sources that disappeared.
-	l := len(targetGroups)
-	for i := l; i < d.tgLastLength; i++ {
-		targetGroups = append(targetGroups, &targetgroup.Group{Source: urlSource(d.url, i)})
+	// Remember the state to replay when the endpoint answers with 304.
+	d.lastETag = resp.Header.Get("ETag")
+	if d.lastETag == "" {
+		d.lastGroups = nil
+	} else if d.delta {
+		d.lastGroups = make([]*targetgroup.Group, 0, len(d.groups))
+		for _, id := range slices.Sorted(maps.Keys(d.groups)) {
+			d.lastGroups = append(d.lastGroups, d.groups[id])
+		}
+	} else {
+		d.lastGroups = targetGroups
 	}
-	d.tgLastLength = l
 
 	return targetGroups, nil
 }
 
+// applyDelta parses a delta response body, updates the discovery's group
+// state and returns the target group updates to send, including empty groups
+// for removed IDs.
+func (d *Discovery) applyDelta(b []byte) ([]*targetgroup.Group, error) {
+	var delta deltaResponse
+	if err := json.Unmarshal(b, &delta); err != nil {
+		return nil, err
+	}
+	if !delta.Full && d.lastETag == "" {
+		return nil, errors.New("delta response to a request without If-None-Match, expected a full response")
+	}
+
+	var updates []*targetgroup.Group
+	if delta.Full {
+		// Removals are implicit in a full response.
+		for _, id := range slices.Sorted(maps.Keys(d.groups)) {
+			if _, ok := delta.Added[id]; !ok {
+				updates = append(updates, &targetgroup.Group{Source: deltaSource(d.url, id)})
+			}
+		}
+		d.groups = make(map[string]*targetgroup.Group, len(delta.Added))
+	}
+	for _, id := range delta.Removed {
+		if _, ok := d.groups[id]; ok {
+			delete(d.groups, id)
+			updates = append(updates, &targetgroup.Group{Source: deltaSource(d.url, id)})
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(delta.Added)) {
+		tg := delta.Added[id]
+		if tg == nil {
+			return nil, errors.New("nil target group item found")
+		}
+		tg.Source = deltaSource(d.url, id)
+		if tg.Labels == nil {
+			tg.Labels = model.LabelSet{}
+		}
+		tg.Labels[httpSDURLLabel] = model.LabelValue(d.url)
+		d.groups[id] = tg
+		updates = append(updates, tg)
+	}
+	return updates, nil
+}
+
 // urlSource returns a source ID for the i-th target group per URL.
 func urlSource(url string, i int) string {
 	return fmt.Sprintf("%s:%d", url, i)
 }
+
+// deltaSource returns a source ID for the target group with the given
+// server-assigned ID per URL.
+func deltaSource(url, id string) string {
+	return url + ":" + id
+}
@@ -474,3 +474,200 @@ func TestSourceDisappeared(t *testing.T) {
 		}
 	}
 }
+
+func TestHTTPNotModified(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprintln(w, `[{"labels": {"k": "1"}, "targets": ["127.0.0.1"]}]`)
+	}))
+	t.Cleanup(ts.Close)
+
+	cfg := SDConfig{
+		HTTPClientConfig: config.DefaultHTTPClientConfig,
+		URL:              ts.URL,
+		RefreshInterval:  model.Duration(1 * time.Second),
+	}
+
+	reg := prometheus.NewRegistry()
+	refreshMetrics := discovery.NewRefreshMetrics(reg)
+	defer refreshMetrics.Unregister()
+	metrics := cfg.NewDiscovererMetrics(reg, refreshMetrics)
+	require.NoError(t, metrics.Register())
+	defer metrics.Unregister()
+
+	d, err := NewDiscovery(&cfg, discovery.DiscovererOptions{
+		Logger:            promslog.NewNopLogger(),
+		HTTPClientOptions: nil,
+		Metrics:           metrics,
+		SetName:           "http",
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	expectedTargets := []*targetgroup.Group{
+		{
+			Targets: []model.LabelSet{
+				{
+					model.AddressLabel: model.LabelValue("127.0.0.1"),
+				},
+			},
+			Labels: model.LabelSet{
+				model.LabelName("k"):          model.LabelValue("1"),
+				model.LabelName("__meta_url"): model.LabelValue(ts.URL),
+			},
+			Source: urlSource(ts.URL, 0),
+		},
+	}
+
+	tgs, err := d.Refresh(ctx)
+	require.NoError(t, err)
+	require.Equal(t, expectedTargets, tgs)
+
+	// The second refresh is answered with 304 and replays the cached state.
+	tgs, err = d.Refresh(ctx)
+	require.NoError(t, err)
+	require.Equal(t, expectedTargets, tgs)
+	require.Equal(t, 2, requests)
+	require.Equal(t, 0.0, getFailureCount(d.metrics.failuresCount))
+}
+
+func TestHTTPDeltaRefresh(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Header.Get("If-None-Match") {
+		case "":
+			w.Header().Set("ETag", `"v1"`)
+			fmt.Fprintln(w, `{"full": true, "added": {"a": {"labels": {"k": "1"}, "targets": ["127.0.0.1"]}, "b": {"labels": {"k": "2"}, "targets": ["127.0.0.2"]}}}`)
+		case `"v1"`:
+			w.Header().Set("ETag", `"v2"`)
+			fmt.Fprintln(w, `{"added": {"c": {"labels": {"k": "3"}, "targets": ["127.0.0.3"]}}, "removed": ["a"]}`)
+		default:
+			w.WriteHeader(http.StatusNotModified)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	cfg := SDConfig{
+		HTTPClientConfig: config.DefaultHTTPClientConfig,
+		URL:              ts.URL,
+		RefreshInterval:  model.Duration(1 * time.Second),
+		Delta:            true,
+	}
+
+	reg := prometheus.NewRegistry()
+	refreshMetrics := discovery.NewRefreshMetrics(reg)
+	defer refreshMetrics.Unregister()
+	metrics := cfg.NewDiscovererMetrics(reg, refreshMetrics)
+	require.NoError(t, metrics.Register())
+	defer metrics.Unregister()
+
+	d, err := NewDiscovery(&cfg, discovery.DiscovererOptions{
+		Logger:            promslog.NewNopLogger(),
+		HTTPClientOptions: nil,
+		Metrics:           metrics,
+		SetName:           "http",
+	})
+	require.NoError(t, err)
+
+	groupA := &targetgroup.Group{
+		Targets: []model.LabelSet{
+			{
+				model.AddressLabel: model.LabelValue("127.0.0.1"),
+			},
+		},
+		Labels: model.LabelSet{
+			model.LabelName("k"):          model.LabelValue("1"),
+			model.LabelName("__meta_url"): model.LabelValue(ts.URL),
+		},
+		Source: deltaSource(ts.URL, "a"),
+	}
+	groupB := &targetgroup.Group{
+		Targets: []model.LabelSet{
+			{
+				model.AddressLabel: model.LabelValue("127.0.0.2"),
+			},
+		},
+		Labels: model.LabelSet{
+			model.LabelName("k"):          model.LabelValue("2"),
+			model.LabelName("__meta_url"): model.LabelValue(ts.URL),
+		},
+		Source: deltaSource(ts.URL, "b"),
+	}
+	groupC := &targetgroup.Group{
+		Targets: []model.LabelSet{
+			{
+				model.AddressLabel: model.LabelValue("127.0.0.3"),
+			},
+		},
+		Labels: model.LabelSet{
+			model.LabelName("k"):          model.LabelValue("3"),
+			model.LabelName("__meta_url"): model.LabelValue(ts.URL),
+		},
+		Source: deltaSource(ts.URL, "c"),
+	}
+
+	ctx := context.Background()
+
+	// The first response is a full set.
+	tgs, err := d.Refresh(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []*targetgroup.Group{groupA, groupB}, tgs)
+
+	// The second response is a delta: group a is removed, group c is added.
+	tgs, err = d.Refresh(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []*targetgroup.Group{
+		{Source: deltaSource(ts.URL, "a")},
+		groupC,
+	}, tgs)
+
+	// The third response is 304, replaying the current full state.
+	tgs, err = d.Refresh(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []*targetgroup.Group{groupB, groupC}, tgs)
+	require.Equal(t, 3, requests)
+	require.Equal(t, 0.0, getFailureCount(d.metrics.failuresCount))
+}
+
+func TestHTTPDeltaWithoutFullResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"added": {"a": {"labels": {"k": "1"}, "targets": ["127.0.0.1"]}}}`)
+	}))
+	t.Cleanup(ts.Close)
+
+	cfg := SDConfig{
+		HTTPClientConfig: config.DefaultHTTPClientConfig,
+		URL:              ts.URL,
+		RefreshInterval:  model.Duration(1 * time.Second),
+		Delta:            true,
+	}
+
+	reg := prometheus.NewRegistry()
+	refreshMetrics := discovery.NewRefreshMetrics(reg)
+	defer refreshMetrics.Unregister()
+	metrics := cfg.NewDiscovererMetrics(reg, refreshMetrics)
+	require.NoError(t, metrics.Register())
+	defer metrics.Unregister()
+
+	d, err := NewDiscovery(&cfg, discovery.DiscovererOptions{
+		Logger:            promslog.NewNopLogger(),
+		HTTPClientOptions: nil,
+		Metrics:           metrics,
+		SetName:           "http",
+	})
+	require.NoError(t, err)
+
+	_, err = d.Refresh(context.Background())
+	require.ErrorContains(t, err, "expected a full response")
+	require.Equal(t, 1.0, getFailureCount(d.metrics.failuresCount))
+}
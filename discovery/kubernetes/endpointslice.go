@@ -19,6 +19,7 @@ import (
 	"log/slog"
 	"net"
 	"strconv"
+	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
@@ -50,6 +51,8 @@ type EndpointSlice struct {
 	jobInf                 cache.SharedInformer
 	withJobMetadata        bool
 	withCronJobMetadata    bool
+	topologyFilter         TopologyFilterConfig
+	conditionsFilter       ConditionsFilterConfig
 
 	podStore           cache.Store
 	endpointSliceStore cache.Store
@@ -59,7 +62,7 @@ type EndpointSlice struct {
 }
 
 // NewEndpointSlice returns a new endpointslice discovery.
-func NewEndpointSlice(l *slog.Logger, eps cache.SharedIndexInformer, svc, pod, node, namespace, rs, job cache.SharedInformer, withDeploymentMetadata, withJobMetadata, withCronJobMetadata bool, eventCount *prometheus.CounterVec) *EndpointSlice {
+func NewEndpointSlice(l *slog.Logger, eps cache.SharedIndexInformer, svc, pod, node, namespace, rs, job cache.SharedInformer, withDeploymentMetadata, withJobMetadata, withCronJobMetadata bool, topologyFilter TopologyFilterConfig, conditionsFilter ConditionsFilterConfig, eventCount *prometheus.CounterVec) *EndpointSlice {
 	if l == nil {
 		l = promslog.NewNopLogger()
 	}
@@ -89,6 +92,8 @@ func NewEndpointSlice(l *slog.Logger, eps cache.SharedIndexInformer, svc, pod, n
 		jobInf:                 job,
 		withJobMetadata:        withJobMetadata,
 		withCronJobMetadata:    withCronJobMetadata,
+		topologyFilter:         topologyFilter,
+		conditionsFilter:       conditionsFilter,
 		queue: workqueue.NewTypedWithConfig(workqueue.TypedQueueConfig[string]{
 			Name: RoleEndpointSlice.String(),
 		}),
@@ -297,6 +302,7 @@ const (
 	endpointSliceEndpointConditionsServingLabel     = metaLabelPrefix + "endpointslice_endpoint_conditions_serving"
 	endpointSliceEndpointConditionsTerminatingLabel = metaLabelPrefix + "endpointslice_endpoint_conditions_terminating"
 	endpointSliceEndpointZoneLabel                  = metaLabelPrefix + "endpointslice_endpoint_zone"
+	endpointSliceEndpointHintsForZonesLabel         = metaLabelPrefix + "endpointslice_endpoint_hints_for_zones"
 	endpointSliceEndpointHostnameLabel              = metaLabelPrefix + "endpointslice_endpoint_hostname"
 	endpointSliceEndpointNodenameLabel              = metaLabelPrefix + "endpointslice_endpoint_node_name"
 	endpointSliceAddressTargetKindLabel             = metaLabelPrefix + "endpointslice_address_target_kind"
@@ -305,6 +311,31 @@ const (
 	endpointSliceEndpointTopologyLabelPresentPrefix = metaLabelPrefix + "endpointslice_endpoint_topology_present_"
 )
 
+// keepEndpoint decides whether an endpoint passes the configured topology and
+// conditions filters. Endpoints without zone information, topology hints or
+// conditions are always kept, so that clusters with incomplete data do not
+// silently lose targets.
+func (e *EndpointSlice) keepEndpoint(ep v1.Endpoint) bool {
+	if e.conditionsFilter.DropTerminating && ep.Conditions.Terminating != nil && *ep.Conditions.Terminating {
+		return false
+	}
+	if e.conditionsFilter.RequireServing && ep.Conditions.Serving != nil && !*ep.Conditions.Serving {
+		return false
+	}
+	if e.topologyFilter.Zone == "" {
+		return true
+	}
+	if e.topologyFilter.UseHints && ep.Hints != nil && len(ep.Hints.ForZones) > 0 {
+		for _, z := range ep.Hints.ForZones {
+			if z.Name == e.topologyFilter.Zone {
+				return true
+			}
+		}
+		return false
+	}
+	return ep.Zone == nil || *ep.Zone == e.topologyFilter.Zone
+}
+
 func (e *EndpointSlice) buildEndpointSlice(eps v1.EndpointSlice) *targetgroup.Group {
 	tg := &targetgroup.Group{
 		Source: endpointSliceSource(eps),
@@ -387,6 +418,14 @@ func (e *EndpointSlice) buildEndpointSlice(eps v1.EndpointSlice) *targetgroup.Gr
 			target[model.LabelName(endpointSliceEndpointZoneLabel)] = lv(*ep.Zone)
 		}
 
+		if ep.Hints != nil && len(ep.Hints.ForZones) > 0 {
+			zones := make([]string, 0, len(ep.Hints.ForZones))
+			for _, z := range ep.Hints.ForZones {
+				zones = append(zones, z.Name)
+			}
+			target[endpointSliceEndpointHintsForZonesLabel] = lv(strings.Join(zones, ","))
+		}
+
 		for k, v := range ep.DeprecatedTopology {
 			ln := strutil.SanitizeLabelName(k)
 			target[model.LabelName(endpointSliceEndpointTopologyLabelPrefix+ln)] = lv(v)
@@ -448,6 +487,9 @@ func (e *EndpointSlice) buildEndpointSlice(eps v1.EndpointSlice) *targetgroup.Gr
 	}
 
 	for _, ep := range eps.Endpoints {
+		if !e.keepEndpoint(ep) {
+			continue
+		}
 		for _, port := range eps.Ports {
 			for _, addr := range ep.Addresses {
 				add(addr, ep, port)
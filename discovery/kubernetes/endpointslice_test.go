@@ -206,6 +206,180 @@ func TestEndpointSliceDiscoveryBeforeRun(t *testing.T) {
 	}.Run(t)
 }
 
+func TestEndpointSliceDiscoveryTopologyFilter(t *testing.T) {
+	t.Parallel()
+	n, c := makeDiscovery(RoleEndpointSlice, NamespaceDiscovery{Names: []string{"default"}})
+	n.topologyFilter = TopologyFilterConfig{Zone: "us-east-1a", UseHints: true}
+
+	k8sDiscoveryTest{
+		discovery: n,
+		beforeRun: func() {
+			obj := &v1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testendpoints",
+					Namespace: "default",
+				},
+				AddressType: v1.AddressTypeIPv4,
+				Ports: []v1.EndpointPort{
+					{
+						Name:     strptr("testport"),
+						Port:     int32ptr(9000),
+						Protocol: protocolptr(corev1.ProtocolTCP),
+					},
+				},
+				Endpoints: []v1.Endpoint{
+					{
+						// Local zone, no hints: kept.
+						Addresses: []string{"1.2.3.4"},
+						Zone:      strptr("us-east-1a"),
+					}, {
+						// Remote zone, no hints: dropped.
+						Addresses: []string{"2.3.4.5"},
+						Zone:      strptr("us-east-1b"),
+					}, {
+						// Remote zone but hinted for the local zone: kept.
+						Addresses: []string{"3.4.5.6"},
+						Zone:      strptr("us-east-1b"),
+						Hints: &v1.EndpointHints{
+							ForZones: []v1.ForZone{{Name: "us-east-1a"}},
+						},
+					}, {
+						// Local zone but hinted for a remote zone: dropped.
+						Addresses: []string{"4.5.6.7"},
+						Zone:      strptr("us-east-1a"),
+						Hints: &v1.EndpointHints{
+							ForZones: []v1.ForZone{{Name: "us-east-1b"}},
+						},
+					}, {
+						// No topology information: kept.
+						Addresses: []string{"5.6.7.8"},
+					},
+				},
+			}
+			c.DiscoveryV1().EndpointSlices(obj.Namespace).Create(context.Background(), obj, metav1.CreateOptions{})
+		},
+		expectedMaxItems: 1,
+		expectedRes: map[string]*targetgroup.Group{
+			"endpointslice/default/testendpoints": {
+				Targets: []model.LabelSet{
+					{
+						"__address__": "1.2.3.4:9000",
+						"__meta_kubernetes_endpointslice_endpoint_zone": "us-east-1a",
+						"__meta_kubernetes_endpointslice_port":          "9000",
+						"__meta_kubernetes_endpointslice_port_name":     "testport",
+						"__meta_kubernetes_endpointslice_port_protocol": "TCP",
+					},
+					{
+						"__address__": "3.4.5.6:9000",
+						"__meta_kubernetes_endpointslice_endpoint_hints_for_zones": "us-east-1a",
+						"__meta_kubernetes_endpointslice_endpoint_zone":            "us-east-1b",
+						"__meta_kubernetes_endpointslice_port":                     "9000",
+						"__meta_kubernetes_endpointslice_port_name":                "testport",
+						"__meta_kubernetes_endpointslice_port_protocol":            "TCP",
+					},
+					{
+						"__address__":                                   "5.6.7.8:9000",
+						"__meta_kubernetes_endpointslice_port":          "9000",
+						"__meta_kubernetes_endpointslice_port_name":     "testport",
+						"__meta_kubernetes_endpointslice_port_protocol": "TCP",
+					},
+				},
+				Labels: model.LabelSet{
+					"__meta_kubernetes_endpointslice_address_type": "IPv4",
+					"__meta_kubernetes_namespace":                  "default",
+					"__meta_kubernetes_endpointslice_name":         "testendpoints",
+				},
+				Source: "endpointslice/default/testendpoints",
+			},
+		},
+	}.Run(t)
+}
+
+func TestEndpointSliceDiscoveryConditionsFilter(t *testing.T) {
+	t.Parallel()
+	n, c := makeDiscovery(RoleEndpointSlice, NamespaceDiscovery{Names: []string{"default"}})
+	n.conditionsFilter = ConditionsFilterConfig{DropTerminating: true, RequireServing: true}
+
+	k8sDiscoveryTest{
+		discovery: n,
+		beforeRun: func() {
+			obj := &v1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testendpoints",
+					Namespace: "default",
+				},
+				AddressType: v1.AddressTypeIPv4,
+				Ports: []v1.EndpointPort{
+					{
+						Name:     strptr("testport"),
+						Port:     int32ptr(9000),
+						Protocol: protocolptr(corev1.ProtocolTCP),
+					},
+				},
+				Endpoints: []v1.Endpoint{
+					{
+						// Ready and serving: kept.
+						Addresses: []string{"1.2.3.4"},
+						Conditions: v1.EndpointConditions{
+							Ready:       boolptr(true),
+							Serving:     boolptr(true),
+							Terminating: boolptr(false),
+						},
+					}, {
+						// Draining during a rollout: dropped.
+						Addresses: []string{"2.3.4.5"},
+						Conditions: v1.EndpointConditions{
+							Ready:       boolptr(false),
+							Serving:     boolptr(true),
+							Terminating: boolptr(true),
+						},
+					}, {
+						// Not serving: dropped.
+						Addresses: []string{"3.4.5.6"},
+						Conditions: v1.EndpointConditions{
+							Ready:       boolptr(false),
+							Serving:     boolptr(false),
+							Terminating: boolptr(false),
+						},
+					}, {
+						// No conditions: kept.
+						Addresses: []string{"4.5.6.7"},
+					},
+				},
+			}
+			c.DiscoveryV1().EndpointSlices(obj.Namespace).Create(context.Background(), obj, metav1.CreateOptions{})
+		},
+		expectedMaxItems: 1,
+		expectedRes: map[string]*targetgroup.Group{
+			"endpointslice/default/testendpoints": {
+				Targets: []model.LabelSet{
+					{
+						"__address__": "1.2.3.4:9000",
+						"__meta_kubernetes_endpointslice_endpoint_conditions_ready":       "true",
+						"__meta_kubernetes_endpointslice_endpoint_conditions_serving":     "true",
+						"__meta_kubernetes_endpointslice_endpoint_conditions_terminating": "false",
+						"__meta_kubernetes_endpointslice_port":                            "9000",
+						"__meta_kubernetes_endpointslice_port_name":                       "testport",
+						"__meta_kubernetes_endpointslice_port_protocol":                   "TCP",
+					},
+					{
+						"__address__":                                   "4.5.6.7:9000",
+						"__meta_kubernetes_endpointslice_port":          "9000",
+						"__meta_kubernetes_endpointslice_port_name":     "testport",
+						"__meta_kubernetes_endpointslice_port_protocol": "TCP",
+					},
+				},
+				Labels: model.LabelSet{
+					"__meta_kubernetes_endpointslice_address_type": "IPv4",
+					"__meta_kubernetes_namespace":                  "default",
+					"__meta_kubernetes_endpointslice_name":         "testendpoints",
+				},
+				Source: "endpointslice/default/testendpoints",
+			},
+		},
+	}.Run(t)
+}
+
 func TestEndpointSliceDiscoveryAdd(t *testing.T) {
 	t.Parallel()
 	obj := &corev1.Pod{
@@ -35,6 +35,7 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	disv1 "k8s.io/api/discovery/v1"
 	networkv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
@@ -113,6 +114,9 @@ type SDConfig struct {
 	NamespaceDiscovery NamespaceDiscovery      `yaml:"namespaces,omitempty"`
 	Selectors          []SelectorConfig        `yaml:"selectors,omitempty"`
 	AttachMetadata     AttachMetadataConfig    `yaml:"attach_metadata,omitempty"`
+	TopologyFilter     TopologyFilterConfig    `yaml:"topology_filter,omitempty"`
+	ConditionsFilter   ConditionsFilterConfig  `yaml:"conditions_filter,omitempty"`
+	CacheTransform     CacheTransformConfig    `yaml:"cache_transform,omitempty"`
 }
 
 // NewDiscovererMetrics implements discovery.Config.
@@ -169,6 +173,47 @@ type PodMetadataConfig struct {
 	CronJob    bool `yaml:"cronjob"`
 }
 
+// TopologyFilterConfig is the configuration for filtering endpointslice
+// targets by their topology, so that a per-zone Prometheus only discovers
+// endpoints local to its zone.
+type TopologyFilterConfig struct {
+	// Zone restricts discovery to endpoints located in the given zone.
+	// Endpoints without zone information are always kept.
+	Zone string `yaml:"zone,omitempty"`
+	// UseHints filters on the topology hints of an endpoint instead of its
+	// zone where hints are present, keeping endpoints hinted for Zone even
+	// when they are located in another zone.
+	UseHints bool `yaml:"use_hints,omitempty"`
+}
+
+// ConditionsFilterConfig is the configuration for dropping endpointslice
+// endpoints based on their conditions before targets are built, so that
+// draining pods are not scraped at all instead of being filtered out via
+// relabeling.
+type ConditionsFilterConfig struct {
+	// DropTerminating drops endpoints whose terminating condition is true.
+	DropTerminating bool `yaml:"drop_terminating,omitempty"`
+	// RequireServing drops endpoints whose serving condition is false.
+	// Endpoints without a serving condition are kept.
+	RequireServing bool `yaml:"require_serving,omitempty"`
+}
+
+// CacheTransformConfig is the configuration for trimming fields from watched
+// objects before they are stored in the informer caches, reducing memory
+// usage on large clusters.
+type CacheTransformConfig struct {
+	// StripManagedFields drops metadata.managedFields from all cached
+	// objects. Managed fields are server-side bookkeeping and are never
+	// turned into target labels.
+	StripManagedFields bool `yaml:"strip_managed_fields,omitempty"`
+	// MetadataOnly reduces objects that are cached solely to attach metadata
+	// to targets (the nodes, namespaces, replica sets and jobs referenced
+	// through attach_metadata) to their metadata. The informers backing the
+	// configured role are never trimmed this way, as building targets
+	// requires the full objects.
+	MetadataOnly bool `yaml:"metadata_only,omitempty"`
+}
+
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
 func (c *SDConfig) UnmarshalYAML(unmarshal func(any) error) error {
 	*c = DefaultSDConfig
@@ -201,6 +246,17 @@ func (c *SDConfig) UnmarshalYAML(unmarshal func(any) error) error {
 	if c.KubeConfig != "" && c.NamespaceDiscovery.IncludeOwnNamespace {
 		return errors.New("cannot use 'kubeconfig_file' and 'namespaces.own_namespace' simultaneously")
 	}
+	if c.TopologyFilter != (TopologyFilterConfig{}) {
+		if c.Role != RoleEndpointSlice {
+			return errors.New("topology_filter is only supported for role: endpointslice")
+		}
+		if c.TopologyFilter.Zone == "" {
+			return errors.New("topology_filter requires a zone")
+		}
+	}
+	if c.ConditionsFilter != (ConditionsFilterConfig{}) && c.Role != RoleEndpointSlice {
+		return errors.New("conditions_filter is only supported for role: endpointslice")
+	}
 
 	foundSelectorRoles := make(map[Role]struct{})
 	allowedSelectors := map[Role][]string{
@@ -262,6 +318,9 @@ type Discovery struct {
 	selectors          roleSelector
 	ownNamespace       string
 	attachMetadata     AttachMetadataConfig
+	topologyFilter     TopologyFilterConfig
+	conditionsFilter   ConditionsFilterConfig
+	cacheTransform     CacheTransformConfig
 	metrics            *kubernetesMetrics
 }
 
@@ -349,6 +408,9 @@ func New(l *slog.Logger, metrics discovery.DiscovererMetrics, conf *SDConfig) (*
 		selectors:          mapSelector(conf.Selectors),
 		ownNamespace:       ownNamespace,
 		attachMetadata:     conf.AttachMetadata,
+		topologyFilter:     conf.TopologyFilter,
+		conditionsFilter:   conf.ConditionsFilter,
+		cacheTransform:     conf.CacheTransform,
 		metrics:            m,
 	}
 
@@ -467,9 +529,12 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
 				d.attachMetadata.Deployment,
 				d.attachMetadata.Job,
 				d.attachMetadata.CronJob,
+				d.topologyFilter,
+				d.conditionsFilter,
 				d.metrics.eventCount,
 			)
 			d.discoverers = append(d.discoverers, eps)
+			d.trackCacheSize(eps.endpointSliceInf)
 			go eps.endpointSliceInf.Run(ctx.Done())
 			go eps.serviceInf.Run(ctx.Done())
 			go eps.podInf.Run(ctx.Done())
@@ -551,6 +616,7 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
 				d.metrics.eventCount,
 			)
 			d.discoverers = append(d.discoverers, eps)
+			d.trackCacheSize(eps.endpointsInf)
 			go eps.endpointsInf.Run(ctx.Done())
 			go eps.serviceInf.Run(ctx.Done())
 			go eps.podInf.Run(ctx.Done())
@@ -603,6 +669,7 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
 				d.metrics.eventCount,
 			)
 			d.discoverers = append(d.discoverers, pod)
+			d.trackCacheSize(pod.podInf)
 			go pod.podInf.Run(ctx.Done())
 		}
 	case RoleService:
@@ -633,6 +700,7 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
 				d.metrics.eventCount,
 			)
 			d.discoverers = append(d.discoverers, svc)
+			d.trackCacheSize(svc.informer)
 			go svc.informer.Run(ctx.Done())
 		}
 	case RoleIngress:
@@ -663,12 +731,14 @@ func (d *Discovery) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
 				d.metrics.eventCount,
 			)
 			d.discoverers = append(d.discoverers, ingress)
+			d.trackCacheSize(ingress.informer)
 			go ingress.informer.Run(ctx.Done())
 		}
 	case RoleNode:
 		nodeInformer := d.newNodeInformer(ctx)
 		node := NewNode(d.logger.With("role", "node"), nodeInformer, d.metrics.eventCount)
 		d.discoverers = append(d.discoverers, node)
+		d.trackCacheSize(node.informer)
 		go node.informer.Run(ctx.Done())
 	default:
 		d.logger.Error("unknown Kubernetes discovery kind", "role", d.role)
@@ -729,7 +799,9 @@ func (d *Discovery) newReplicaSetInformer(_ context.Context, namespace string) c
 			return rs.Watch(ctx, options)
 		},
 	}
-	return d.mustNewSharedInformer(rslw, &appsv1.ReplicaSet{}, resyncDisabled)
+	// Replica sets are only looked up to resolve deployment names, which
+	// needs nothing beyond the object metadata.
+	return d.mustSetMetadataOnlyTransform(d.mustNewSharedInformer(rslw, &appsv1.ReplicaSet{}, resyncDisabled))
 }
 
 func (d *Discovery) newJobInformer(_ context.Context, namespace string) cache.SharedInformer {
@@ -742,7 +814,9 @@ func (d *Discovery) newJobInformer(_ context.Context, namespace string) cache.Sh
 			return j.Watch(ctx, options)
 		},
 	}
-	return d.mustNewSharedInformer(jlw, &batchv1.Job{}, resyncDisabled)
+	// Jobs are only looked up to resolve job and cron job names, which needs
+	// nothing beyond the object metadata.
+	return d.mustSetMetadataOnlyTransform(d.mustNewSharedInformer(jlw, &batchv1.Job{}, resyncDisabled))
 }
 
 func (d *Discovery) newNodeInformer(_ context.Context) cache.SharedInformer {
@@ -758,7 +832,14 @@ func (d *Discovery) newNodeInformer(_ context.Context) cache.SharedInformer {
 			return d.client.CoreV1().Nodes().Watch(ctx, options)
 		},
 	}
-	return d.mustNewSharedInformer(nlw, &apiv1.Node{}, resyncDisabled)
+	informer := d.mustNewSharedInformer(nlw, &apiv1.Node{}, resyncDisabled)
+	// For role: node the informer backs full node targets and must not be
+	// trimmed. Attaching node metadata to other roles only reads the object
+	// metadata.
+	if d.role != RoleNode {
+		d.mustSetMetadataOnlyTransform(informer)
+	}
+	return informer
 }
 
 func (d *Discovery) newNamespaceInformer(_ context.Context) cache.SharedInformer {
@@ -771,7 +852,9 @@ func (d *Discovery) newNamespaceInformer(_ context.Context) cache.SharedInformer
 			return d.client.CoreV1().Namespaces().Watch(ctx, options)
 		},
 	}
-	return d.mustNewSharedInformer(nlw, &apiv1.Namespace{}, resyncDisabled)
+	// Namespaces are only looked up to attach namespace metadata, which
+	// needs nothing beyond the object metadata.
+	return d.mustSetMetadataOnlyTransform(d.mustNewSharedInformer(nlw, &apiv1.Namespace{}, resyncDisabled))
 }
 
 func (d *Discovery) newIndexedPodsInformer(plw *cache.ListWatch) cache.SharedIndexInformer {
@@ -941,9 +1024,65 @@ func (d *Discovery) newIndexedIngressesInformer(ilw *cache.ListWatch) cache.Shar
 
 func (d *Discovery) informerWatchErrorHandler(ctx context.Context, r *cache.Reflector, err error) {
 	d.metrics.failuresCount.Inc()
+	d.metrics.watchRestarts.WithLabelValues(d.role.String()).Inc()
 	cache.DefaultWatchErrorHandler(ctx, r, err)
 }
 
+// trackCacheSize keeps the cache size metric of the role up to date based on
+// the add and delete events observed by the given role informer.
+func (d *Discovery) trackCacheSize(informer cache.SharedInformer) {
+	g := d.metrics.cacheSize.WithLabelValues(d.role.String())
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(any) { g.Inc() },
+		DeleteFunc: func(any) { g.Dec() },
+	})
+	if err != nil {
+		d.logger.Error("Error adding cache size handler.", "err", err)
+	}
+}
+
+// stripManagedFieldsTransform drops metadata.managedFields from an object
+// before it is cached. Managed fields are server-side bookkeeping and are
+// never turned into target labels.
+func stripManagedFieldsTransform(obj any) (any, error) {
+	if acc, err := meta.Accessor(obj); err == nil {
+		acc.SetManagedFields(nil)
+	}
+	return obj, nil
+}
+
+// metadataOnlyTransform reduces an object to its metadata before it is
+// cached. It must only be applied to informers whose consumers read nothing
+// but the object metadata, i.e. the attach_metadata helper informers.
+func metadataOnlyTransform(obj any) (any, error) {
+	switch o := obj.(type) {
+	case *apiv1.Node:
+		obj = &apiv1.Node{ObjectMeta: o.ObjectMeta}
+	case *apiv1.Namespace:
+		obj = &apiv1.Namespace{ObjectMeta: o.ObjectMeta}
+	case *appsv1.ReplicaSet:
+		obj = &appsv1.ReplicaSet{ObjectMeta: o.ObjectMeta}
+	case *batchv1.Job:
+		obj = &batchv1.Job{ObjectMeta: o.ObjectMeta}
+	}
+	// Managed fields are part of the object metadata, so drop them here
+	// unconditionally as well.
+	return stripManagedFieldsTransform(obj)
+}
+
+// mustSetMetadataOnlyTransform trims cached objects down to their metadata if
+// configured. It must only be called for informers whose consumers read
+// nothing but the object metadata, and before the informer is started.
+func (d *Discovery) mustSetMetadataOnlyTransform(informer cache.SharedInformer) cache.SharedInformer {
+	if !d.cacheTransform.MetadataOnly {
+		return informer
+	}
+	if err := informer.SetTransform(metadataOnlyTransform); err != nil {
+		panic(err)
+	}
+	return informer
+}
+
 func (d *Discovery) mustNewSharedInformer(lw cache.ListerWatcher, exampleObject runtime.Object, defaultEventHandlerResyncPeriod time.Duration) cache.SharedInformer {
 	informer := cache.NewSharedInformer(lw, exampleObject, defaultEventHandlerResyncPeriod)
 	// Invoking SetWatchErrorHandler should fail only if the informer has been started beforehand.
@@ -951,6 +1090,11 @@ func (d *Discovery) mustNewSharedInformer(lw cache.ListerWatcher, exampleObject
 	if err := informer.SetWatchErrorHandlerWithContext(d.informerWatchErrorHandler); err != nil {
 		panic(err)
 	}
+	if d.cacheTransform.StripManagedFields {
+		if err := informer.SetTransform(stripManagedFieldsTransform); err != nil {
+			panic(err)
+		}
+	}
 	return informer
 }
 
@@ -961,6 +1105,11 @@ func (d *Discovery) mustNewSharedIndexInformer(lw cache.ListerWatcher, exampleOb
 	if err := informer.SetWatchErrorHandlerWithContext(d.informerWatchErrorHandler); err != nil {
 		panic(err)
 	}
+	if d.cacheTransform.StripManagedFields {
+		if err := informer.SetTransform(stripManagedFieldsTransform); err != nil {
+			panic(err)
+		}
+	}
 	return informer
 }
 
@@ -328,10 +328,93 @@ func TestFailuresCountMetric(t *testing.T) {
 
 			// At least the errors of the initial watches should be caught (watches are retried on errors).
 			require.GreaterOrEqual(t, prom_testutil.ToFloat64(n.metrics.failuresCount), float64(tc.minFailedWatches))
+			// Every failed watch is re-established, so the per-role restart counter must keep up.
+			require.GreaterOrEqual(t, prom_testutil.ToFloat64(n.metrics.watchRestarts.WithLabelValues(tc.role.String())), float64(tc.minFailedWatches))
 		})
 	}
 }
 
+func TestCacheSizeMetric(t *testing.T) {
+	t.Parallel()
+
+	n, c := makeDiscovery(RolePod, NamespaceDiscovery{}, makePods("ns1"))
+
+	cacheSize := func() float64 {
+		return prom_testutil.ToFloat64(n.metrics.cacheSize.WithLabelValues(RolePod.String()))
+	}
+
+	k8sDiscoveryTest{
+		discovery: n,
+		afterStart: func() {
+			require.Eventually(t, func() bool { return cacheSize() == 1 }, time.Second, 10*time.Millisecond)
+
+			pod := makePods("ns2")
+			_, err := c.CoreV1().Pods(pod.Namespace).Create(context.Background(), pod, metav1.CreateOptions{})
+			require.NoError(t, err)
+			require.Eventually(t, func() bool { return cacheSize() == 2 }, time.Second, 10*time.Millisecond)
+
+			err = c.CoreV1().Pods(pod.Namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
+			require.NoError(t, err)
+			require.Eventually(t, func() bool { return cacheSize() == 1 }, time.Second, 10*time.Millisecond)
+		},
+	}.Run(t)
+}
+
+func TestStripManagedFieldsTransform(t *testing.T) {
+	t.Parallel()
+	pod := makePods("default")
+	pod.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubelet"}}
+
+	obj, err := stripManagedFieldsTransform(pod)
+	require.NoError(t, err)
+
+	transformed, ok := obj.(*apiv1.Pod)
+	require.True(t, ok)
+	require.Empty(t, transformed.ManagedFields)
+	// Everything besides the managed fields must be left untouched.
+	require.Equal(t, "testnode", transformed.Spec.NodeName)
+	require.Equal(t, "1.2.3.4", transformed.Status.PodIP)
+}
+
+func TestMetadataOnlyTransform(t *testing.T) {
+	t.Parallel()
+	node := &apiv1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "testnode",
+			Labels:        map[string]string{"topology.kubernetes.io/zone": "us-east-1a"},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubelet"}},
+		},
+		Status: apiv1.NodeStatus{
+			Addresses: []apiv1.NodeAddress{{Type: apiv1.NodeInternalIP, Address: "10.0.0.1"}},
+		},
+	}
+
+	obj, err := metadataOnlyTransform(node)
+	require.NoError(t, err)
+
+	transformed, ok := obj.(*apiv1.Node)
+	require.True(t, ok)
+	// The metadata needed for attaching labels must survive, everything else is dropped.
+	require.Equal(t, "testnode", transformed.Name)
+	require.Equal(t, "us-east-1a", transformed.Labels["topology.kubernetes.io/zone"])
+	require.Empty(t, transformed.ManagedFields)
+	require.Empty(t, transformed.Status.Addresses)
+}
+
+func TestMetadataOnlyTransformLeavesUnknownTypesUntouched(t *testing.T) {
+	t.Parallel()
+	// Role objects such as pods pass through the transform unmodified, apart
+	// from their managed fields.
+	pod := makePods("default")
+	obj, err := metadataOnlyTransform(pod)
+	require.NoError(t, err)
+
+	transformed, ok := obj.(*apiv1.Pod)
+	require.True(t, ok)
+	require.Equal(t, "testnode", transformed.Spec.NodeName)
+	require.Equal(t, "1.2.3.4", transformed.Status.PodIP)
+}
+
 func TestNodeName(t *testing.T) {
 	t.Parallel()
 	node := &apiv1.Node{
@@ -24,6 +24,8 @@ var _ discovery.DiscovererMetrics = (*kubernetesMetrics)(nil)
 type kubernetesMetrics struct {
 	eventCount    *prometheus.CounterVec
 	failuresCount prometheus.Counter
+	cacheSize     *prometheus.GaugeVec
+	watchRestarts *prometheus.CounterVec
 
 	metricRegisterer discovery.MetricRegisterer
 }
@@ -45,11 +47,29 @@ func newDiscovererMetrics(reg prometheus.Registerer, _ discovery.RefreshMetricsI
 				Help:      "The number of failed WATCH/LIST requests.",
 			},
 		),
+		cacheSize: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: discovery.KubernetesMetricsNamespace,
+				Name:      "cache_size",
+				Help:      "The number of objects held in the informer cache, summed over all discoverers of a role.",
+			},
+			[]string{"role"},
+		),
+		watchRestarts: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: discovery.KubernetesMetricsNamespace,
+				Name:      "watch_restarts_total",
+				Help:      "The number of times a WATCH had to be re-established after an error.",
+			},
+			[]string{"role"},
+		),
 	}
 
 	m.metricRegisterer = discovery.NewMetricRegisterer(reg, []prometheus.Collector{
 		m.eventCount,
 		m.failuresCount,
+		m.cacheSize,
+		m.watchRestarts,
 	})
 
 	// Initialize metric vectors.
@@ -68,6 +88,8 @@ func newDiscovererMetrics(reg prometheus.Registerer, _ discovery.RefreshMetricsI
 		} {
 			m.eventCount.WithLabelValues(role, evt)
 		}
+		m.cacheSize.WithLabelValues(role)
+		m.watchRestarts.WithLabelValues(role)
 	}
 
 	m.failuresCount.Add(0)
@@ -97,14 +97,16 @@ func NewManager(ctx context.Context, logger *slog.Logger, registerer prometheus.
 		return nil
 	}
 	mgr := &Manager{
-		logger:      logger,
-		syncCh:      make(chan map[string][]*targetgroup.Group),
-		targets:     make(map[poolKey]map[string]*targetgroup.Group),
-		ctx:         ctx,
-		updatert:    5 * time.Second,
-		triggerSend: make(chan struct{}, 1), // At least one element to ensure we can do a delayed read.
-		registerer:  registerer,
-		sdMetrics:   sdMetrics,
+		logger:             logger,
+		syncCh:             make(chan map[string][]*targetgroup.Group),
+		targets:            make(map[poolKey]map[string]*targetgroup.Group),
+		updateHistory:      make(map[string][]UpdateDiff),
+		updateHistoryLimit: defaultUpdateHistoryLimit,
+		ctx:                ctx,
+		updatert:           5 * time.Second,
+		triggerSend:        make(chan struct{}, 1), // At least one element to ensure we can do a delayed read.
+		registerer:         registerer,
+		sdMetrics:          sdMetrics,
 	}
 	for _, option := range options {
 		option(mgr)
@@ -156,6 +158,33 @@ func HTTPClientOptions(opts ...config.HTTPClientOption) func(*Manager) {
 	}
 }
 
+// UpdateHistoryLimit sets how many update diffs are retained per SD provider
+// for the UpdateHistory debug API. 0 disables recording.
+func UpdateHistoryLimit(n int) func(*Manager) {
+	return func(m *Manager) {
+		m.mtx.Lock()
+		defer m.mtx.Unlock()
+		m.updateHistoryLimit = n
+	}
+}
+
+// ProviderUpdateRateLimit enforces a minimum interval between updates applied
+// from a single SD provider, so a misbehaving provider (e.g. a flapping
+// Consul) cannot flood the consumers. Updates arriving faster are coalesced:
+// only the most recent one is applied once the window elapses. If
+// breakerWindows is non-zero, a circuit breaker opens after that many
+// consecutive windows required coalescing; updates from the provider are then
+// suppressed entirely until it stays quiet for a full window. A zero window
+// disables rate limiting.
+func ProviderUpdateRateLimit(window time.Duration, breakerWindows int) func(*Manager) {
+	return func(m *Manager) {
+		m.mtx.Lock()
+		defer m.mtx.Unlock()
+		m.providerUpdateWindow = window
+		m.providerBreakerWindows = breakerWindows
+	}
+}
+
 // FeatureRegistry sets the feature registry for the manager.
 func FeatureRegistry(fr features.Collector) func(*Manager) {
 	return func(m *Manager) {
@@ -179,11 +208,32 @@ type Manager struct {
 	targets    map[poolKey]map[string]*targetgroup.Group
 	targetsMtx sync.Mutex
 
+	// updateHistory keeps the most recent update diffs per provider for
+	// debugging, see UpdateHistory. Guarded by targetsMtx.
+	updateHistory      map[string][]UpdateDiff
+	updateHistoryLimit int
+
+	// providerUpdateWindow and providerBreakerWindows configure per-provider
+	// rate limiting of updates, see ProviderUpdateRateLimit.
+	providerUpdateWindow   time.Duration
+	providerBreakerWindows int
+
 	// providers keeps track of SD providers.
 	providers []*Provider
 	// The sync channel sends the updates as a map where the key is the job value from the scrape config.
 	syncCh chan map[string][]*targetgroup.Group
 
+	// The delta channel sends incremental updates instead of full snapshots.
+	// It is created lazily by DeltaSyncCh; once set, the sender emits deltas
+	// on it and stops sending on syncCh.
+	deltaCh chan map[string]*targetgroup.Delta
+
+	// deltaLastSent and deltaEpochs track the target group state last
+	// delivered to the delta consumer. Both are only accessed from the
+	// sender goroutine.
+	deltaLastSent map[string]map[string]*targetgroup.Group
+	deltaEpochs   map[string]uint64
+
 	// How long to wait before sending updates to the channel. The variable
 	// should only be modified in unit tests.
 	updatert time.Duration
@@ -229,6 +279,24 @@ func (m *Manager) SyncCh() <-chan map[string][]*targetgroup.Group {
 	return m.syncCh
 }
 
+// DeltaSyncCh returns a read only channel delivering incremental target group
+// updates, keyed by the target set name. It is an alternative to SyncCh for
+// consumers that maintain their own merged view of the target groups: only
+// groups that changed since the previous update are sent, so a small SD change
+// in a large setup doesn't force consumers to reprocess every target.
+// Once DeltaSyncCh has been called the manager stops sending on the SyncCh
+// channel. It must be called before Run.
+func (m *Manager) DeltaSyncCh() <-chan map[string]*targetgroup.Delta {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.deltaCh == nil {
+		m.deltaCh = make(chan map[string]*targetgroup.Delta)
+		m.deltaLastSent = map[string]map[string]*targetgroup.Group{}
+		m.deltaEpochs = map[string]uint64{}
+	}
+	return m.deltaCh
+}
+
 // ApplyConfig checks if discovery provider with supplied config is already running and keeps them as is.
 // Remaining providers are then stopped and new required providers are started using the provided config.
 func (m *Manager) ApplyConfig(cfg map[string]Configs) error {
@@ -369,7 +437,10 @@ func (m *Manager) cleaner(p *Provider) {
 	for s := range p.subs {
 		delete(m.targets, poolKey{s, p.name})
 	}
+	delete(m.updateHistory, p.name)
 	m.targetsMtx.Unlock()
+	m.metrics.SuppressedUpdates.DeleteLabelValues(p.name)
+	m.metrics.ProviderBreakerOpen.DeleteLabelValues(p.name)
 	if p.done != nil {
 		p.done()
 	}
@@ -378,9 +449,57 @@ func (m *Manager) cleaner(p *Provider) {
 	p.cancel = nil
 }
 
+// providerThrottle holds the rate limiting state of a single provider. It is
+// only accessed from the provider's updater goroutine.
+type providerThrottle struct {
+	lastApplied time.Time
+	// pending is the most recent update that wasn't applied yet; earlier
+	// updates within the same window are coalesced into it.
+	pending []*targetgroup.Group
+	armed   bool
+	// hotWindows counts consecutive windows in which updates had to be
+	// coalesced. It trips the circuit breaker when it reaches the
+	// configured number of windows.
+	hotWindows int
+	open       bool
+}
+
 func (m *Manager) updater(ctx context.Context, p *Provider, updates chan []*targetgroup.Group) {
 	// Ensure targets from this provider are cleaned up.
 	defer m.cleaner(p)
+
+	apply := func(tgs []*targetgroup.Group) {
+		p.mu.RLock()
+		for s := range p.subs {
+			m.updateGroup(poolKey{setName: s, provider: p.name}, tgs)
+		}
+		p.mu.RUnlock()
+
+		select {
+		case m.triggerSend <- struct{}{}:
+		default:
+		}
+	}
+
+	// The timer applies a coalesced update once the rate limiting window
+	// elapses and, with an open circuit breaker, detects that the provider
+	// stayed quiet for a full window.
+	var throttle providerThrottle
+	timer := time.NewTimer(time.Hour)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+	arm := func(d time.Duration) {
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(d)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -394,15 +513,54 @@ func (m *Manager) updater(ctx context.Context, p *Provider, updates chan []*targ
 				return
 			}
 
-			p.mu.RLock()
-			for s := range p.subs {
-				m.updateGroup(poolKey{setName: s, provider: p.name}, tgs)
+			if m.providerUpdateWindow <= 0 {
+				apply(tgs)
+				continue
 			}
-			p.mu.RUnlock()
-
-			select {
-			case m.triggerSend <- struct{}{}:
-			default:
+			if throttle.open {
+				// The circuit is open: remember the update but don't apply
+				// it. The timer closes the circuit once the provider stays
+				// quiet for a full window.
+				throttle.pending = tgs
+				m.metrics.SuppressedUpdates.WithLabelValues(p.name).Inc()
+				arm(m.providerUpdateWindow)
+				continue
+			}
+			since := time.Since(throttle.lastApplied)
+			if since >= m.providerUpdateWindow {
+				throttle.hotWindows = 0
+				throttle.lastApplied = time.Now()
+				apply(tgs)
+				continue
+			}
+			// Within the window: coalesce, keeping only the most recent update.
+			throttle.pending = tgs
+			m.metrics.SuppressedUpdates.WithLabelValues(p.name).Inc()
+			if !throttle.armed {
+				throttle.armed = true
+				arm(m.providerUpdateWindow - since)
+			}
+		case <-timer.C:
+			throttle.armed = false
+			if throttle.open {
+				// The provider stayed quiet for a full window; close the
+				// circuit and deliver the update it left behind.
+				throttle.open = false
+				throttle.hotWindows = 0
+				m.metrics.ProviderBreakerOpen.WithLabelValues(p.name).Set(0)
+				m.logger.Info("Provider quieted down, closing circuit breaker", "provider", p.name)
+			} else {
+				throttle.hotWindows++
+				if m.providerBreakerWindows > 0 && throttle.hotWindows >= m.providerBreakerWindows {
+					throttle.open = true
+					m.metrics.ProviderBreakerOpen.WithLabelValues(p.name).Set(1)
+					m.logger.Warn("Provider keeps exceeding the update rate limit, opening circuit breaker", "provider", p.name, "window", m.providerUpdateWindow)
+				}
+			}
+			if throttle.pending != nil {
+				throttle.lastApplied = time.Now()
+				apply(throttle.pending)
+				throttle.pending = nil
 			}
 		}
 	}
@@ -411,6 +569,9 @@ func (m *Manager) updater(ctx context.Context, p *Provider, updates chan []*targ
 func (m *Manager) sender() {
 	defer func() {
 		close(m.syncCh)
+		if m.deltaCh != nil {
+			close(m.deltaCh)
+		}
 	}()
 	// Some discoverers send updates too often, so we throttle these with a backoff interval that
 	// increases the interval up to m.updatert delay.
@@ -429,18 +590,8 @@ func (m *Manager) sender() {
 		case <-time.After(b.NextBackOff()):
 			select {
 			case <-m.triggerSend:
-				m.metrics.SentUpdates.Inc()
-				select {
-				case m.syncCh <- m.allGroups():
+				if m.trySend() {
 					lastSent = time.Now()
-				default:
-					m.metrics.DelayedUpdates.Inc()
-					m.logger.Debug("Discovery receiver's channel was full so will retry the next cycle")
-					// Ensure we don't miss this update.
-					select {
-					case m.triggerSend <- struct{}{}:
-					default:
-					}
 				}
 			default:
 			}
@@ -451,6 +602,100 @@ func (m *Manager) sender() {
 	}
 }
 
+// trySend attempts a non-blocking send of the pending update on the channel
+// the consumer subscribed to. It returns true if no further retry is needed.
+// If the consumer wasn't ready, the trigger is re-armed so the update is
+// retried on the next cycle.
+func (m *Manager) trySend() bool {
+	if m.deltaCh != nil {
+		return m.trySendDeltas()
+	}
+	m.metrics.SentUpdates.Inc()
+	select {
+	case m.syncCh <- m.allGroups():
+		return true
+	default:
+		m.retrySend()
+		return false
+	}
+}
+
+// trySendDeltas diffs the current target groups against the state last
+// delivered to the consumer and attempts a non-blocking send of the result.
+// The bookkeeping only advances when the send succeeds, so a slow consumer
+// never misses a change: the next attempt recomputes the delta from the same
+// base.
+func (m *Manager) trySendDeltas() bool {
+	current := m.allGroupsKeyed()
+	deltas := map[string]*targetgroup.Delta{}
+	for s, groups := range current {
+		last, ok := m.deltaLastSent[s]
+		d := &targetgroup.Delta{Epoch: m.deltaEpochs[s] + 1, Full: !ok}
+		for k, tg := range groups {
+			// Pointer equality catches providers that resend the same groups,
+			// DeepEqual catches refresh-style providers that reallocate
+			// identical groups every interval.
+			if old, ok := last[k]; !ok || (old != tg && !reflect.DeepEqual(old, tg)) {
+				if d.Changed == nil {
+					d.Changed = map[string]*targetgroup.Group{}
+				}
+				d.Changed[k] = tg
+			}
+		}
+		for k := range last {
+			if _, ok := groups[k]; !ok {
+				d.Dropped = append(d.Dropped, k)
+			}
+		}
+		if d.Full || d.Changed != nil || d.Dropped != nil {
+			deltas[s] = d
+		}
+	}
+	// Target sets that vanished entirely get a final delta dropping all their groups.
+	for s, last := range m.deltaLastSent {
+		if _, ok := current[s]; ok {
+			continue
+		}
+		d := &targetgroup.Delta{Epoch: m.deltaEpochs[s] + 1}
+		for k := range last {
+			d.Dropped = append(d.Dropped, k)
+		}
+		deltas[s] = d
+	}
+	if len(deltas) == 0 {
+		// The trigger fired but no group changed, so there is nothing to deliver.
+		return true
+	}
+	m.metrics.SentUpdates.Inc()
+	select {
+	case m.deltaCh <- deltas:
+		m.deltaLastSent = current
+		for s, d := range deltas {
+			m.deltaEpochs[s] = d.Epoch
+		}
+		for s := range m.deltaEpochs {
+			if _, ok := current[s]; !ok {
+				delete(m.deltaEpochs, s)
+			}
+		}
+		return true
+	default:
+		m.retrySend()
+		return false
+	}
+}
+
+// retrySend records a delayed update and re-arms the trigger so the update is
+// not missed.
+func (m *Manager) retrySend() {
+	m.metrics.DelayedUpdates.Inc()
+	m.logger.Debug("Discovery receiver's channel was full so will retry the next cycle")
+	select {
+	case m.triggerSend <- struct{}{}:
+	default:
+	}
+}
+
 func (m *Manager) cancelDiscoverers() {
 	m.mtx.RLock()
 	defer m.mtx.RUnlock()
@@ -470,11 +715,17 @@ func (m *Manager) updateGroup(poolKey poolKey, tgs []*targetgroup.Group) {
 	if _, ok := m.targets[poolKey]; !ok {
 		m.targets[poolKey] = make(map[string]*targetgroup.Group)
 	}
+	var groupDiffs []GroupDiff
 	for _, tg := range tgs {
 		// Some Discoverers send nil target group so need to check for it to avoid panics.
 		if tg == nil {
 			continue
 		}
+		if m.updateHistoryLimit > 0 {
+			if d, changed := diffGroups(m.targets[poolKey][tg.Source], tg); changed {
+				groupDiffs = append(groupDiffs, d)
+			}
+		}
 		if len(tg.Targets) > 0 {
 			m.targets[poolKey][tg.Source] = tg
 		} else {
@@ -483,10 +734,17 @@ func (m *Manager) updateGroup(poolKey poolKey, tgs []*targetgroup.Group) {
 			delete(m.targets[poolKey], tg.Source)
 		}
 	}
+	if len(groupDiffs) > 0 {
+		m.recordUpdate(poolKey, groupDiffs)
+	}
 }
 
-func (m *Manager) allGroups() map[string][]*targetgroup.Group {
-	tSets := map[string][]*targetgroup.Group{}
+// allGroupsKeyed assembles the current target groups of every target set,
+// keyed by an identifier that is unique within the set, and updates the
+// discovered targets metrics. The keys are shared with the deltas sent on the
+// delta channel so consumers can correlate dropped groups.
+func (m *Manager) allGroupsKeyed() map[string]map[string]*targetgroup.Group {
+	tSets := map[string]map[string]*targetgroup.Group{}
 	n := map[string]int{}
 
 	m.mtx.RLock()
@@ -494,15 +752,15 @@ func (m *Manager) allGroups() map[string][]*targetgroup.Group {
 		p.mu.RLock()
 		m.targetsMtx.Lock()
 		for s := range p.subs {
-			// Send empty lists for subs without any targets to make sure old stale targets are dropped by consumers.
+			// Create entries for subs without any targets to make sure old stale targets are dropped by consumers.
 			// See: https://github.com/prometheus/prometheus/issues/12858 for details.
 			if _, ok := tSets[s]; !ok {
-				tSets[s] = []*targetgroup.Group{}
+				tSets[s] = map[string]*targetgroup.Group{}
 				n[s] = 0
 			}
-			if tsets, ok := m.targets[poolKey{s, p.name}]; ok {
-				for _, tg := range tsets {
-					tSets[s] = append(tSets[s], tg)
+			if tgroups, ok := m.targets[poolKey{s, p.name}]; ok {
+				for _, tg := range tgroups {
+					tSets[s][p.name+"/"+tg.Source] = tg
 					n[s] += len(tg.Targets)
 				}
 			}
@@ -520,6 +778,18 @@ func (m *Manager) allGroups() map[string][]*targetgroup.Group {
 	return tSets
 }
 
+func (m *Manager) allGroups() map[string][]*targetgroup.Group {
+	tSets := map[string][]*targetgroup.Group{}
+	for s, groups := range m.allGroupsKeyed() {
+		list := make([]*targetgroup.Group, 0, len(groups))
+		for _, tg := range groups {
+			list = append(list, tg)
+		}
+		tSets[s] = list
+	}
+	return tSets
+}
+
 // registerProviders returns a number of failed SD config.
 func (m *Manager) registerProviders(cfgs Configs, setName string) int {
 	var (
@@ -540,6 +810,7 @@ func (m *Manager) registerProviders(cfgs Configs, setName string) int {
 			HTTPClientOptions: m.httpOpts,
 			Metrics:           m.sdMetrics.MechanismMetrics[typ],
 			SetName:           setName,
+			MechanismMetrics:  m.sdMetrics.MechanismMetrics,
 		})
 		if err != nil {
 			m.logger.Error("Cannot create service discovery", "err", err, "type", typ, "config", setName)
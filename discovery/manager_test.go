@@ -788,6 +788,76 @@ func pk(provider, setName string, n int) poolKey {
 	}
 }
 
+func TestProviderUpdateRateLimit(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx := t.Context()
+		reg := prometheus.NewRegistry()
+		sdMetrics := NewTestMetrics(t, reg)
+		mgr := NewManager(ctx, promslog.NewNopLogger(), reg, sdMetrics, ProviderUpdateRateLimit(time.Second, 2))
+		require.NotNil(t, mgr)
+
+		p := &Provider{name: "mock/0", subs: map[string]struct{}{"job1": {}}}
+		updates := make(chan []*targetgroup.Group)
+		go mgr.updater(ctx, p, updates)
+
+		send := func(addr string) {
+			updates <- []*targetgroup.Group{{Source: "tg1", Targets: []model.LabelSet{{model.AddressLabel: model.LabelValue(addr)}}}}
+			synctest.Wait()
+		}
+		current := func() string {
+			mgr.targetsMtx.Lock()
+			defer mgr.targetsMtx.Unlock()
+			tg := mgr.targets[poolKey{setName: "job1", provider: "mock/0"}]["tg1"]
+			if tg == nil || len(tg.Targets) == 0 {
+				return ""
+			}
+			return string(tg.Targets[0][model.AddressLabel])
+		}
+		suppressed := func() float64 {
+			return client_testutil.ToFloat64(mgr.metrics.SuppressedUpdates.WithLabelValues("mock/0"))
+		}
+		breakerOpen := func() float64 {
+			return client_testutil.ToFloat64(mgr.metrics.ProviderBreakerOpen.WithLabelValues("mock/0"))
+		}
+
+		// The first update after a quiet period is applied immediately.
+		send("a:80")
+		require.Equal(t, "a:80", current())
+
+		// Updates within the window are coalesced: only the most recent one
+		// is applied, once the window elapses.
+		send("b:80")
+		send("c:80")
+		require.Equal(t, "a:80", current())
+		require.Equal(t, 2.0, suppressed())
+		time.Sleep(time.Second)
+		synctest.Wait()
+		require.Equal(t, "c:80", current())
+		require.Equal(t, 0.0, breakerOpen())
+
+		// A second consecutive window that needs coalescing trips the
+		// circuit breaker.
+		send("d:80")
+		time.Sleep(time.Second)
+		synctest.Wait()
+		require.Equal(t, "d:80", current())
+		require.Equal(t, 1.0, breakerOpen())
+
+		// While the circuit is open, updates are suppressed entirely.
+		send("e:80")
+		send("f:80")
+		require.Equal(t, "d:80", current())
+		require.Equal(t, 5.0, suppressed())
+
+		// Once the provider stays quiet for a full window, the circuit
+		// closes and the update it left behind is delivered.
+		time.Sleep(time.Second)
+		synctest.Wait()
+		require.Equal(t, "f:80", current())
+		require.Equal(t, 0.0, breakerOpen())
+	})
+}
+
 func TestTargetSetTargetGroupsPresentOnStartup(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -905,6 +975,86 @@ func TestTargetSetTargetGroupsPresentOnConfigReload(t *testing.T) {
 	require.Len(t, syncedTargets["prometheus"], 1)
 }
 
+func TestDeltaSyncCh(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	reg := prometheus.NewRegistry()
+	sdMetrics := NewTestMetrics(t, reg)
+
+	discoveryManager := NewManager(ctx, promslog.NewNopLogger(), reg, sdMetrics)
+	require.NotNil(t, discoveryManager)
+	discoveryManager.updatert = 100 * time.Millisecond
+	deltaCh := discoveryManager.DeltaSyncCh()
+	go discoveryManager.Run()
+
+	c := map[string]Configs{
+		"prometheus": {
+			staticConfig("foo:9090"),
+		},
+	}
+	discoveryManager.ApplyConfig(c)
+
+	// The first delta must carry the full state of the target set.
+	deltas := <-deltaCh
+	require.Len(t, deltas, 1)
+	d := deltas["prometheus"]
+	require.NotNil(t, d)
+	require.True(t, d.Full)
+	require.Equal(t, uint64(1), d.Epoch)
+	require.Empty(t, d.Dropped)
+
+	merged := map[string]*targetgroup.Group{}
+	lastEpoch := d.Epoch
+	apply := func(d *targetgroup.Delta) {
+		for k, tg := range d.Changed {
+			merged[k] = tg
+		}
+		for _, k := range d.Dropped {
+			delete(merged, k)
+		}
+	}
+	apply(d)
+
+	// Merge deltas until the merged view holds exactly one group with the
+	// given address. Subsequent deltas must be incremental with contiguous
+	// epochs.
+	waitFor := func(addr model.LabelValue) {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			if len(merged) == 1 {
+				for _, tg := range merged {
+					if len(tg.Targets) == 1 && tg.Targets[0][model.AddressLabel] == addr {
+						return
+					}
+				}
+			}
+			select {
+			case deltas := <-deltaCh:
+				d, ok := deltas["prometheus"]
+				require.True(t, ok)
+				require.False(t, d.Full)
+				require.Equal(t, lastEpoch+1, d.Epoch)
+				lastEpoch = d.Epoch
+				apply(d)
+			case <-timeout:
+				t.Fatalf("timed out waiting for %q, merged state: %v", addr, merged)
+			}
+		}
+	}
+	waitFor("foo:9090")
+
+	// Changing the config must only produce deltas for the affected groups.
+	c = map[string]Configs{
+		"prometheus": {
+			staticConfig("bar:9090"),
+		},
+	}
+	discoveryManager.ApplyConfig(c)
+	waitFor("bar:9090")
+}
+
 func TestTargetSetTargetGroupsPresentOnConfigRename(t *testing.T) {
 	t.Parallel()
 	ctx := t.Context()
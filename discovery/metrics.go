@@ -27,6 +27,9 @@ type Metrics struct {
 	DelayedUpdates    prometheus.Counter
 	SentUpdates       prometheus.Counter
 	LastUpdated       *prometheus.GaugeVec
+
+	SuppressedUpdates   *prometheus.CounterVec
+	ProviderBreakerOpen *prometheus.GaugeVec
 }
 
 func NewManagerMetrics(registerer prometheus.Registerer, sdManagerName string) (*Metrics, error) {
@@ -82,6 +85,24 @@ func NewManagerMetrics(registerer prometheus.Registerer, sdManagerName string) (
 		[]string{"config"},
 	)
 
+	m.SuppressedUpdates = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "prometheus_sd_updates_suppressed_total",
+			Help:        "Total number of update events suppressed by per-provider rate limiting, either coalesced into a later update or dropped while the provider's circuit breaker was open.",
+			ConstLabels: prometheus.Labels{"name": sdManagerName},
+		},
+		[]string{"provider"},
+	)
+
+	m.ProviderBreakerOpen = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "prometheus_sd_provider_circuit_breaker_open",
+			Help:        "Whether the circuit breaker suppressing updates from a flapping SD provider is currently open (1) or closed (0).",
+			ConstLabels: prometheus.Labels{"name": sdManagerName},
+		},
+		[]string{"provider"},
+	)
+
 	metrics := []prometheus.Collector{
 		m.FailedConfigs,
 		m.DiscoveredTargets,
@@ -89,6 +110,8 @@ func NewManagerMetrics(registerer prometheus.Registerer, sdManagerName string) (
 		m.DelayedUpdates,
 		m.SentUpdates,
 		m.LastUpdated,
+		m.SuppressedUpdates,
+		m.ProviderBreakerOpen,
 	}
 
 	for _, collector := range metrics {
@@ -109,4 +132,6 @@ func (m *Metrics) Unregister(registerer prometheus.Registerer) {
 	registerer.Unregister(m.DelayedUpdates)
 	registerer.Unregister(m.SentUpdates)
 	registerer.Unregister(m.LastUpdated)
+	registerer.Unregister(m.SuppressedUpdates)
+	registerer.Unregister(m.ProviderBreakerOpen)
 }
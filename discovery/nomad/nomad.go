@@ -17,6 +17,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"strconv"
 	"strings"
@@ -30,21 +31,29 @@ import (
 	"github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/discovery/refresh"
 	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/prometheus/prometheus/util/strutil"
 )
 
 const (
 	// nomadLabel is the name for the label containing a target.
 	nomadLabel = model.MetaLabelPrefix + "nomad_"
 	// serviceLabel is the name of the label containing the service name.
-	nomadAddress        = nomadLabel + "address"
-	nomadService        = nomadLabel + "service"
-	nomadNamespace      = nomadLabel + "namespace"
-	nomadNodeID         = nomadLabel + "node_id"
-	nomadDatacenter     = nomadLabel + "dc"
-	nomadServiceAddress = nomadService + "_address"
-	nomadServicePort    = nomadService + "_port"
-	nomadServiceID      = nomadService + "_id"
-	nomadTags           = nomadLabel + "tags"
+	nomadAddress         = nomadLabel + "address"
+	nomadService         = nomadLabel + "service"
+	nomadNamespace       = nomadLabel + "namespace"
+	nomadNodeID          = nomadLabel + "node_id"
+	nomadDatacenter      = nomadLabel + "dc"
+	nomadJobID           = nomadLabel + "job_id"
+	nomadAllocID         = nomadLabel + "alloc_id"
+	nomadAllocName       = nomadLabel + "alloc_name"
+	nomadAllocTaskGroup  = nomadLabel + "alloc_task_group"
+	nomadAllocCanary     = nomadLabel + "alloc_canary"
+	nomadAllocPortPrefix = nomadLabel + "alloc_port_"
+	nomadAllocMetaPrefix = nomadLabel + "alloc_metadata_"
+	nomadServiceAddress  = nomadService + "_address"
+	nomadServicePort     = nomadService + "_port"
+	nomadServiceID       = nomadService + "_id"
+	nomadTags            = nomadLabel + "tags"
 )
 
 // DefaultSDConfig is the default nomad SD configuration.
@@ -67,6 +76,7 @@ type SDConfig struct {
 	AllowStale       bool                    `yaml:"allow_stale"`
 	HTTPClientConfig config.HTTPClientConfig `yaml:",inline"`
 	Namespace        string                  `yaml:"namespace"`
+	Namespaces       []string                `yaml:"namespaces,omitempty"`
 	RefreshInterval  model.Duration          `yaml:"refresh_interval"`
 	Region           string                  `yaml:"region"`
 	Server           string                  `yaml:"server"`
@@ -102,6 +112,9 @@ func (c *SDConfig) UnmarshalYAML(unmarshal func(any) error) error {
 	if strings.TrimSpace(c.Server) == "" {
 		return errors.New("nomad SD configuration requires a server address")
 	}
+	if len(c.Namespaces) > 0 && c.Namespace != DefaultSDConfig.Namespace {
+		return errors.New("at most one of nomad SD configuration namespace and namespaces must be configured")
+	}
 	return c.HTTPClientConfig.Validate()
 }
 
@@ -111,7 +124,7 @@ type Discovery struct {
 	*refresh.Discovery
 	allowStale      bool
 	client          *nomad.Client
-	namespace       string
+	namespaces      []string
 	refreshInterval time.Duration
 	region          string
 	server          string
@@ -126,9 +139,14 @@ func NewDiscovery(conf *SDConfig, opts discovery.DiscovererOptions) (*Discovery,
 		return nil, errors.New("invalid discovery metrics type")
 	}
 
+	namespaces := conf.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{conf.Namespace}
+	}
+
 	d := &Discovery{
 		allowStale:      conf.AllowStale,
-		namespace:       conf.Namespace,
+		namespaces:      namespaces,
 		refreshInterval: time.Duration(conf.RefreshInterval),
 		region:          conf.Region,
 		server:          conf.Server,
@@ -168,49 +186,101 @@ func NewDiscovery(conf *SDConfig, opts discovery.DiscovererOptions) (*Discovery,
 }
 
 func (d *Discovery) refresh(context.Context) ([]*targetgroup.Group, error) {
-	opts := &nomad.QueryOptions{
-		AllowStale: d.allowStale,
-	}
-	stubs, _, err := d.client.Services().List(opts)
-	if err != nil {
-		d.metrics.failuresCount.Inc()
-		return nil, err
-	}
-
 	tg := &targetgroup.Group{
 		Source: "Nomad",
 	}
+	// Allocations are shared between the services they expose, so the lookups
+	// are cached for the duration of one refresh.
+	allocs := map[string]*nomad.Allocation{}
 
-	for _, stub := range stubs {
-		for _, service := range stub.Services {
-			instances, _, err := d.client.Services().Get(service.ServiceName, opts)
-			if err != nil {
-				d.metrics.failuresCount.Inc()
-				return nil, fmt.Errorf("failed to fetch services: %w", err)
-			}
+	for _, namespace := range d.namespaces {
+		opts := &nomad.QueryOptions{
+			AllowStale: d.allowStale,
+			Namespace:  namespace,
+		}
+		stubs, _, err := d.client.Services().List(opts)
+		if err != nil {
+			d.metrics.failuresCount.Inc()
+			return nil, err
+		}
 
-			for _, instance := range instances {
-				labels := model.LabelSet{
-					nomadAddress:        model.LabelValue(instance.Address),
-					nomadDatacenter:     model.LabelValue(instance.Datacenter),
-					nomadNodeID:         model.LabelValue(instance.NodeID),
-					nomadNamespace:      model.LabelValue(instance.Namespace),
-					nomadServiceAddress: model.LabelValue(instance.Address),
-					nomadServiceID:      model.LabelValue(instance.ID),
-					nomadServicePort:    model.LabelValue(strconv.Itoa(instance.Port)),
-					nomadService:        model.LabelValue(instance.ServiceName),
+		for _, stub := range stubs {
+			for _, service := range stub.Services {
+				instances, _, err := d.client.Services().Get(service.ServiceName, opts)
+				if err != nil {
+					d.metrics.failuresCount.Inc()
+					return nil, fmt.Errorf("failed to fetch services: %w", err)
 				}
-				addr := net.JoinHostPort(instance.Address, strconv.FormatInt(int64(instance.Port), 10))
-				labels[model.AddressLabel] = model.LabelValue(addr)
 
-				if len(instance.Tags) > 0 {
-					tags := d.tagSeparator + strings.Join(instance.Tags, d.tagSeparator) + d.tagSeparator
-					labels[nomadTags] = model.LabelValue(tags)
-				}
+				for _, instance := range instances {
+					labels := model.LabelSet{
+						nomadAddress:        model.LabelValue(instance.Address),
+						nomadDatacenter:     model.LabelValue(instance.Datacenter),
+						nomadNodeID:         model.LabelValue(instance.NodeID),
+						nomadNamespace:      model.LabelValue(instance.Namespace),
+						nomadJobID:          model.LabelValue(instance.JobID),
+						nomadAllocID:        model.LabelValue(instance.AllocID),
+						nomadServiceAddress: model.LabelValue(instance.Address),
+						nomadServiceID:      model.LabelValue(instance.ID),
+						nomadServicePort:    model.LabelValue(strconv.Itoa(instance.Port)),
+						nomadService:        model.LabelValue(instance.ServiceName),
+					}
+					addr := net.JoinHostPort(instance.Address, strconv.FormatInt(int64(instance.Port), 10))
+					labels[model.AddressLabel] = model.LabelValue(addr)
+
+					if len(instance.Tags) > 0 {
+						tags := d.tagSeparator + strings.Join(instance.Tags, d.tagSeparator) + d.tagSeparator
+						labels[nomadTags] = model.LabelValue(tags)
+					}
 
-				tg.Targets = append(tg.Targets, labels)
+					alloc, ok := allocs[instance.AllocID]
+					if !ok {
+						alloc, _, err = d.client.Allocations().Info(instance.AllocID, opts)
+						if err != nil {
+							d.metrics.failuresCount.Inc()
+							return nil, fmt.Errorf("failed to fetch allocation %s: %w", instance.AllocID, err)
+						}
+						allocs[instance.AllocID] = alloc
+					}
+					addAllocationLabels(labels, alloc)
+
+					tg.Targets = append(tg.Targets, labels)
+				}
 			}
 		}
 	}
 	return []*targetgroup.Group{tg}, nil
 }
+
+// addAllocationLabels decorates the labels of a service registration target
+// with metadata from the allocation backing it.
+func addAllocationLabels(labels model.LabelSet, alloc *nomad.Allocation) {
+	canary := alloc.DeploymentStatus != nil && alloc.DeploymentStatus.Canary
+	labels[nomadAllocName] = model.LabelValue(alloc.Name)
+	labels[nomadAllocTaskGroup] = model.LabelValue(alloc.TaskGroup)
+	labels[nomadAllocCanary] = model.LabelValue(strconv.FormatBool(canary))
+
+	if alloc.AllocatedResources != nil {
+		for _, port := range alloc.AllocatedResources.Shared.Ports {
+			name := nomadAllocPortPrefix + strutil.SanitizeLabelName(port.Label)
+			labels[model.LabelName(name)] = model.LabelValue(strconv.Itoa(port.Value))
+		}
+	}
+
+	if alloc.Job == nil {
+		return
+	}
+	meta := map[string]string{}
+	maps.Copy(meta, alloc.Job.Meta)
+	// Task group metadata overrides job metadata, mirroring how Nomad itself
+	// merges the two.
+	for _, group := range alloc.Job.TaskGroups {
+		if group.Name != nil && *group.Name == alloc.TaskGroup {
+			maps.Copy(meta, group.Meta)
+		}
+	}
+	for k, v := range meta {
+		name := nomadAllocMetaPrefix + strutil.SanitizeLabelName(k)
+		labels[model.LabelName(name)] = model.LabelValue(v)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/common/promslog"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 
 	"github.com/prometheus/prometheus/discovery"
 )
@@ -73,13 +74,33 @@ func (s *NomadSDTestSuite) SetupTest(t *testing.T) {
 
 	s.Mock.HandleServicesList()
 	s.Mock.HandleServiceHashiCupsGet()
+	s.Mock.HandleServiceNodeExporterGet()
+	s.Mock.HandleAllocationDashboardGet()
+	s.Mock.HandleAllocationNodeExporterGet()
 }
 
 func (m *SDMock) HandleServicesList() {
-	m.Mux.HandleFunc("/v1/services", func(w http.ResponseWriter, _ *http.Request) {
+	m.Mux.HandleFunc("/v1/services", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("content-type", "application/json; charset=utf-8")
 		w.WriteHeader(http.StatusOK)
 
+		if r.URL.Query().Get("namespace") == "dev" {
+			fmt.Fprint(w, `
+			[
+				{
+					"Namespace": "dev",
+					"Services": [
+					{
+						"ServiceName": "nodeexporter",
+						"Tags": []
+					}
+					]
+				}
+			]`,
+			)
+			return
+		}
+
 		fmt.Fprint(w, `
 		[
 			{
@@ -126,6 +147,102 @@ func (m *SDMock) HandleServiceHashiCupsGet() {
 	})
 }
 
+func (m *SDMock) HandleServiceNodeExporterGet() {
+	m.Mux.HandleFunc("/v1/service/nodeexporter", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("content-type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprint(w, `
+		[
+			{
+				"ID": "_nomad-task-b7f1b3c6-6dd6-4b5f-b1bb-4b8e2b862e07-group-node-nodeexporter-metrics",
+				"ServiceName": "nodeexporter",
+				"Namespace": "dev",
+				"NodeID": "d92fdc3c-9c2b-298a-e8f4-c33f3a449f09",
+				"Datacenter": "dc1",
+				"JobID": "node",
+				"AllocID": "b7f1b3c6-6dd6-4b5f-b1bb-4b8e2b862e07",
+				"Tags": [],
+				"Address": "127.0.0.1",
+				"Port": 9100,
+				"CreateIndex": 230,
+				"ModifyIndex": 230
+			}
+		]`,
+		)
+	})
+}
+
+func (m *SDMock) HandleAllocationDashboardGet() {
+	m.Mux.HandleFunc("/v1/allocation/6a1d5f0a-7362-3f5d-9baf-5ed438918e50", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("content-type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprint(w, `
+		{
+			"ID": "6a1d5f0a-7362-3f5d-9baf-5ed438918e50",
+			"Name": "dashboard.hashicups[0]",
+			"Namespace": "default",
+			"JobID": "dashboard",
+			"TaskGroup": "hashicups",
+			"DeploymentStatus": {
+				"Canary": true
+			},
+			"AllocatedResources": {
+				"Shared": {
+					"Ports": [
+					{
+						"Label": "http",
+						"Value": 30456,
+						"To": 8080,
+						"HostIP": "127.0.0.1"
+					},
+					{
+						"Label": "envoy-metrics",
+						"Value": 29418,
+						"To": 9102,
+						"HostIP": "127.0.0.1"
+					}
+					]
+				}
+			},
+			"Job": {
+				"ID": "dashboard",
+				"Meta": {
+					"team": "payments",
+					"tier": "backend"
+				},
+				"TaskGroups": [
+				{
+					"Name": "hashicups",
+					"Meta": {
+						"tier": "frontend"
+					}
+				}
+				]
+			}
+		}`,
+		)
+	})
+}
+
+func (m *SDMock) HandleAllocationNodeExporterGet() {
+	m.Mux.HandleFunc("/v1/allocation/b7f1b3c6-6dd6-4b5f-b1bb-4b8e2b862e07", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("content-type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprint(w, `
+		{
+			"ID": "b7f1b3c6-6dd6-4b5f-b1bb-4b8e2b862e07",
+			"Name": "node.node[0]",
+			"Namespace": "dev",
+			"JobID": "node",
+			"TaskGroup": "node"
+		}`,
+		)
+	})
+}
+
 func TestConfiguredService(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -203,16 +320,111 @@ func TestNomadSDRefresh(t *testing.T) {
 	require.Len(t, tg.Targets, 1)
 
 	lbls := model.LabelSet{
-		"__address__":                  model.LabelValue("127.0.0.1:30456"),
-		"__meta_nomad_address":         model.LabelValue("127.0.0.1"),
-		"__meta_nomad_dc":              model.LabelValue("dc1"),
-		"__meta_nomad_namespace":       model.LabelValue("default"),
-		"__meta_nomad_node_id":         model.LabelValue("d92fdc3c-9c2b-298a-e8f4-c33f3a449f09"),
-		"__meta_nomad_service":         model.LabelValue("hashicups"),
-		"__meta_nomad_service_address": model.LabelValue("127.0.0.1"),
-		"__meta_nomad_service_id":      model.LabelValue("_nomad-task-6a1d5f0a-7362-3f5d-9baf-5ed438918e50-group-hashicups-hashicups-hashicups_ui"),
-		"__meta_nomad_service_port":    model.LabelValue("30456"),
-		"__meta_nomad_tags":            model.LabelValue(",metrics,"),
+		"__address__":                           model.LabelValue("127.0.0.1:30456"),
+		"__meta_nomad_address":                  model.LabelValue("127.0.0.1"),
+		"__meta_nomad_alloc_canary":             model.LabelValue("true"),
+		"__meta_nomad_alloc_id":                 model.LabelValue("6a1d5f0a-7362-3f5d-9baf-5ed438918e50"),
+		"__meta_nomad_alloc_metadata_team":      model.LabelValue("payments"),
+		"__meta_nomad_alloc_metadata_tier":      model.LabelValue("frontend"),
+		"__meta_nomad_alloc_name":               model.LabelValue("dashboard.hashicups[0]"),
+		"__meta_nomad_alloc_port_envoy_metrics": model.LabelValue("29418"),
+		"__meta_nomad_alloc_port_http":          model.LabelValue("30456"),
+		"__meta_nomad_alloc_task_group":         model.LabelValue("hashicups"),
+		"__meta_nomad_dc":                       model.LabelValue("dc1"),
+		"__meta_nomad_job_id":                   model.LabelValue("dashboard"),
+		"__meta_nomad_namespace":                model.LabelValue("default"),
+		"__meta_nomad_node_id":                  model.LabelValue("d92fdc3c-9c2b-298a-e8f4-c33f3a449f09"),
+		"__meta_nomad_service":                  model.LabelValue("hashicups"),
+		"__meta_nomad_service_address":          model.LabelValue("127.0.0.1"),
+		"__meta_nomad_service_id":               model.LabelValue("_nomad-task-6a1d5f0a-7362-3f5d-9baf-5ed438918e50-group-hashicups-hashicups-hashicups_ui"),
+		"__meta_nomad_service_port":             model.LabelValue("30456"),
+		"__meta_nomad_tags":                     model.LabelValue(",metrics,"),
 	}
 	require.Equal(t, lbls, tg.Targets[0])
 }
+
+func TestNomadSDRefreshMultipleNamespaces(t *testing.T) {
+	t.Parallel()
+	sdmock := &NomadSDTestSuite{}
+	sdmock.SetupTest(t)
+	t.Cleanup(sdmock.TearDownSuite)
+
+	endpoint, err := url.Parse(sdmock.Mock.Endpoint())
+	require.NoError(t, err)
+
+	cfg := DefaultSDConfig
+	cfg.Server = endpoint.String()
+	cfg.Namespaces = []string{"default", "dev"}
+
+	reg := prometheus.NewRegistry()
+	refreshMetrics := discovery.NewRefreshMetrics(reg)
+	metrics := cfg.NewDiscovererMetrics(reg, refreshMetrics)
+	require.NoError(t, metrics.Register())
+	defer metrics.Unregister()
+	defer refreshMetrics.Unregister()
+
+	d, err := NewDiscovery(&cfg, discovery.DiscovererOptions{
+		Logger:  promslog.NewNopLogger(),
+		Metrics: metrics,
+		SetName: "nomad",
+	})
+	require.NoError(t, err)
+
+	tgs, err := d.refresh(context.Background())
+	require.NoError(t, err)
+
+	// One target per configured namespace.
+	require.Len(t, tgs, 1)
+	require.Len(t, tgs[0].Targets, 2)
+
+	require.Equal(t, model.LabelSet{
+		"__address__":                   model.LabelValue("127.0.0.1:9100"),
+		"__meta_nomad_address":          model.LabelValue("127.0.0.1"),
+		"__meta_nomad_alloc_canary":     model.LabelValue("false"),
+		"__meta_nomad_alloc_id":         model.LabelValue("b7f1b3c6-6dd6-4b5f-b1bb-4b8e2b862e07"),
+		"__meta_nomad_alloc_name":       model.LabelValue("node.node[0]"),
+		"__meta_nomad_alloc_task_group": model.LabelValue("node"),
+		"__meta_nomad_dc":               model.LabelValue("dc1"),
+		"__meta_nomad_job_id":           model.LabelValue("node"),
+		"__meta_nomad_namespace":        model.LabelValue("dev"),
+		"__meta_nomad_node_id":          model.LabelValue("d92fdc3c-9c2b-298a-e8f4-c33f3a449f09"),
+		"__meta_nomad_service":          model.LabelValue("nodeexporter"),
+		"__meta_nomad_service_address":  model.LabelValue("127.0.0.1"),
+		"__meta_nomad_service_id":       model.LabelValue("_nomad-task-b7f1b3c6-6dd6-4b5f-b1bb-4b8e2b862e07-group-node-nodeexporter-metrics"),
+		"__meta_nomad_service_port":     model.LabelValue("9100"),
+	}, tgs[0].Targets[1])
+}
+
+func TestSDConfigUnmarshalYAMLNamespaces(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "namespaces only",
+			yaml: "server: http://localhost:4646\nnamespaces: [dev, prod]",
+		},
+		{
+			name: "namespace only",
+			yaml: "server: http://localhost:4646\nnamespace: dev",
+		},
+		{
+			name:    "both namespace and namespaces",
+			yaml:    "server: http://localhost:4646\nnamespace: dev\nnamespaces: [prod]",
+			wantErr: "at most one of nomad SD configuration namespace and namespaces must be configured",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var cfg SDConfig
+			err := yaml.UnmarshalStrict([]byte(tc.yaml), &cfg)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
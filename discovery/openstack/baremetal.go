@@ -0,0 +1,116 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openstack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack"
+	"github.com/gophercloud/gophercloud/v2/openstack/baremetal/v1/nodes"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/promslog"
+
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+)
+
+const (
+	openstackLabelBaremetalID             = openstackLabelPrefix + "baremetal_id"
+	openstackLabelBaremetalName           = openstackLabelPrefix + "baremetal_name"
+	openstackLabelBaremetalProvisionState = openstackLabelPrefix + "baremetal_provision_state"
+	openstackLabelBaremetalPowerState     = openstackLabelPrefix + "baremetal_power_state"
+	openstackLabelBaremetalMaintenance    = openstackLabelPrefix + "baremetal_maintenance"
+	openstackLabelBaremetalResourceClass  = openstackLabelPrefix + "baremetal_resource_class"
+	openstackLabelBaremetalInstanceID     = openstackLabelPrefix + "baremetal_instance_id"
+)
+
+// BaremetalDiscovery discovers OpenStack Ironic bare metal nodes.
+type BaremetalDiscovery struct {
+	provider     *gophercloud.ProviderClient
+	authOpts     *gophercloud.AuthOptions
+	region       string
+	logger       *slog.Logger
+	port         int
+	availability gophercloud.Availability
+}
+
+// newBaremetalDiscovery returns a new baremetal discovery.
+func newBaremetalDiscovery(provider *gophercloud.ProviderClient, opts *gophercloud.AuthOptions,
+	port int, region string, availability gophercloud.Availability, l *slog.Logger,
+) *BaremetalDiscovery {
+	if l == nil {
+		l = promslog.NewNopLogger()
+	}
+	return &BaremetalDiscovery{
+		provider: provider, authOpts: opts,
+		region: region, port: port, availability: availability, logger: l,
+	}
+}
+
+func (b *BaremetalDiscovery) refresh(ctx context.Context) ([]*targetgroup.Group, error) {
+	err := openstack.Authenticate(ctx, b.provider, *b.authOpts)
+	if err != nil {
+		return nil, fmt.Errorf("could not authenticate to OpenStack: %w", err)
+	}
+
+	client, err := openstack.NewBareMetalV1(b.provider, gophercloud.EndpointOpts{
+		Region: b.region, Availability: b.availability,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create OpenStack bare metal session: %w", err)
+	}
+
+	allPages, err := nodes.ListDetail(client, nodes.ListOpts{}).AllPages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bare metal nodes: %w", err)
+	}
+
+	allNodes, err := nodes.ExtractNodes(allPages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract bare metal nodes: %w", err)
+	}
+
+	tg := &targetgroup.Group{
+		Source: "OS_" + b.region,
+	}
+
+	for _, node := range allNodes {
+		// The node name is the only stable address the bare metal API
+		// exposes; nodes that have not been named cannot be scraped.
+		if node.Name == "" {
+			b.logger.Debug("Skipping node without a name", "node", node.UUID)
+			continue
+		}
+
+		labels := model.LabelSet{}
+		addr := net.JoinHostPort(node.Name, strconv.Itoa(b.port))
+		labels[model.AddressLabel] = model.LabelValue(addr)
+		labels[openstackLabelBaremetalID] = model.LabelValue(node.UUID)
+		labels[openstackLabelBaremetalName] = model.LabelValue(node.Name)
+		labels[openstackLabelBaremetalProvisionState] = model.LabelValue(node.ProvisionState)
+		labels[openstackLabelBaremetalPowerState] = model.LabelValue(node.PowerState)
+		labels[openstackLabelBaremetalMaintenance] = model.LabelValue(strconv.FormatBool(node.Maintenance))
+		labels[openstackLabelBaremetalResourceClass] = model.LabelValue(node.ResourceClass)
+		if node.InstanceUUID != "" {
+			labels[openstackLabelBaremetalInstanceID] = model.LabelValue(node.InstanceUUID)
+		}
+		tg.Targets = append(tg.Targets, labels)
+	}
+
+	return []*targetgroup.Group{tg}, nil
+}
@@ -0,0 +1,188 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openstack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+type OpenstackSDBaremetalTestSuite struct {
+	Mock *SDMock
+}
+
+func (s *OpenstackSDBaremetalTestSuite) SetupTest(t *testing.T) {
+	s.Mock = NewSDMock(t)
+	s.Mock.Setup()
+
+	s.Mock.HandleBaremetalListSuccessfully()
+	s.Mock.HandleVersionsSuccessfully()
+	s.Mock.HandleAuthSuccessfully()
+}
+
+func (s *OpenstackSDBaremetalTestSuite) openstackAuthSuccess() (refresher, error) {
+	conf := SDConfig{
+		IdentityEndpoint: s.Mock.Endpoint(),
+		Password:         "test",
+		Username:         "test",
+		DomainName:       "12345",
+		Region:           "RegionOne",
+		Role:             "baremetal",
+		Port:             9100,
+	}
+	return newRefresher(&conf, nil)
+}
+
+func TestOpenstackSDBaremetalRefresh(t *testing.T) {
+	mock := &OpenstackSDBaremetalTestSuite{}
+	mock.SetupTest(t)
+
+	baremetal, _ := mock.openstackAuthSuccess()
+	ctx := context.Background()
+	tgs, err := baremetal.refresh(ctx)
+	require.NoError(t, err)
+	require.Len(t, tgs, 1)
+	tg := tgs[0]
+	require.NotNil(t, tg)
+	require.NotNil(t, tg.Targets)
+	// The unnamed node is skipped.
+	require.Len(t, tg.Targets, 2)
+
+	for l, v := range map[string]string{
+		"__address__":                                "bm-node-01:9100",
+		"__meta_openstack_baremetal_id":              "1a148b7b-8ef6-4d13-b2f4-4dbd7e061431",
+		"__meta_openstack_baremetal_name":            "bm-node-01",
+		"__meta_openstack_baremetal_provision_state": "active",
+		"__meta_openstack_baremetal_power_state":     "power on",
+		"__meta_openstack_baremetal_maintenance":     "false",
+		"__meta_openstack_baremetal_resource_class":  "baremetal-general",
+		"__meta_openstack_baremetal_instance_id":     "2c1f31e5-1f9d-4cd2-ad0a-0f47b7487cbb",
+	} {
+		require.Equal(t, model.LabelValue(v), tg.Targets[0][model.LabelName(l)])
+	}
+
+	for l, v := range map[string]string{
+		"__address__":                                "bm-node-02:9100",
+		"__meta_openstack_baremetal_id":              "5b421a77-2fbd-44e1-bf52-01e62e11b25b",
+		"__meta_openstack_baremetal_name":            "bm-node-02",
+		"__meta_openstack_baremetal_provision_state": "available",
+		"__meta_openstack_baremetal_power_state":     "power off",
+		"__meta_openstack_baremetal_maintenance":     "true",
+		"__meta_openstack_baremetal_resource_class":  "baremetal-general",
+	} {
+		require.Equal(t, model.LabelValue(v), tg.Targets[1][model.LabelName(l)])
+	}
+	require.NotContains(t, tg.Targets[1], model.LabelName("__meta_openstack_baremetal_instance_id"))
+}
+
+func TestOpenstackSDBaremetalRefreshWithDoneContext(t *testing.T) {
+	mock := &OpenstackSDBaremetalTestSuite{}
+	mock.SetupTest(t)
+
+	baremetal, _ := mock.openstackAuthSuccess()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := baremetal.refresh(ctx)
+	require.ErrorContains(t, err, context.Canceled.Error(), "%q doesn't contain %q", err, context.Canceled)
+}
+
+func TestOpenstackSDRefreshReloadsApplicationCredentials(t *testing.T) {
+	mock := &OpenstackSDBaremetalTestSuite{}
+	mock.SetupTest(t)
+
+	dir := t.TempDir()
+	idFile := filepath.Join(dir, "id")
+	secretFile := filepath.Join(dir, "secret")
+	require.NoError(t, os.WriteFile(idFile, []byte("app-cred-id\n"), 0o600))
+	require.NoError(t, os.WriteFile(secretFile, []byte("initial-secret\n"), 0o600))
+
+	conf := SDConfig{
+		IdentityEndpoint:                mock.Mock.Endpoint(),
+		Region:                          "RegionOne",
+		Role:                            "baremetal",
+		ApplicationCredentialIDFile:     idFile,
+		ApplicationCredentialSecretFile: secretFile,
+	}
+	r, err := newRefresher(&conf, nil)
+	require.NoError(t, err)
+
+	reloader, ok := r.(*credentialsReloader)
+	require.True(t, ok, "expected a credentialsReloader when credential files are configured")
+
+	ctx := context.Background()
+	_, err = r.refresh(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "app-cred-id", reloader.authOpts.ApplicationCredentialID)
+	require.Equal(t, "initial-secret", reloader.authOpts.ApplicationCredentialSecret)
+
+	// Rotate the secret on disk; the next refresh must pick it up.
+	require.NoError(t, os.WriteFile(secretFile, []byte("rotated-secret\n"), 0o600))
+	_, err = r.refresh(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "rotated-secret", reloader.authOpts.ApplicationCredentialSecret)
+}
+
+func TestSDConfigApplicationCredentialFilesValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		cfg    string
+		errMsg string
+	}{
+		{
+			name: "valid",
+			cfg: `
+role: baremetal
+region: RegionOne
+application_credential_id_file: /etc/prometheus/app_cred_id
+application_credential_secret_file: /etc/prometheus/app_cred_secret
+`,
+		},
+		{
+			name: "inline and file ID",
+			cfg: `
+role: baremetal
+region: RegionOne
+application_credential_id: abc
+application_credential_id_file: /etc/prometheus/app_cred_id
+`,
+			errMsg: "at most one of application_credential_id and application_credential_id_file",
+		},
+		{
+			name: "inline and file secret",
+			cfg: `
+role: baremetal
+region: RegionOne
+application_credential_secret: abc
+application_credential_secret_file: /etc/prometheus/app_cred_secret
+`,
+			errMsg: "at most one of application_credential_secret and application_credential_secret_file",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var cfg SDConfig
+			err := yaml.UnmarshalStrict([]byte(tc.cfg), &cfg)
+			if tc.errMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.errMsg)
+			}
+		})
+	}
+}
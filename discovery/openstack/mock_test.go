@@ -162,6 +162,19 @@ func (m *SDMock) HandleAuthSuccessfully() {
                 ],
                 "id": "26968f704a68417bbddd29508455ff90",
                 "type": "load-balancer"
+            },
+            {
+                "endpoints": [
+                    {
+                        "id": "23e4f0f2a38346eda6b7f9f2f977c563",
+                        "interface": "public",
+                        "region": "RegionOne",
+                        "region_id": "RegionOne",
+                        "url": "%s"
+                    }
+                ],
+                "id": "d0fd1c2dbcef4c4b8eb4b37e1244a8e4",
+                "type": "baremetal"
             }
         ],
         "expires_at": "2013-02-27T18:30:59.999999Z",
@@ -199,7 +212,7 @@ func (m *SDMock) HandleAuthSuccessfully() {
         }
     }
 }
-	`, m.Endpoint(), m.Endpoint(), m.Endpoint())
+	`, m.Endpoint(), m.Endpoint(), m.Endpoint(), m.Endpoint()+"ironic/v1/")
 	})
 }
 
@@ -261,6 +274,92 @@ const hypervisorListBody = `
     ]
 }`
 
+const baremetalListBody = `
+{
+    "nodes": [
+        {
+            "uuid": "1a148b7b-8ef6-4d13-b2f4-4dbd7e061431",
+            "name": "bm-node-01",
+            "power_state": "power on",
+            "target_power_state": null,
+            "provision_state": "active",
+            "target_provision_state": null,
+            "maintenance": false,
+            "maintenance_reason": null,
+            "last_error": null,
+            "driver": "ipmi",
+            "driver_info": {
+                "ipmi_address": "192.168.1.11"
+            },
+            "instance_uuid": "2c1f31e5-1f9d-4cd2-ad0a-0f47b7487cbb",
+            "resource_class": "baremetal-general",
+            "console_enabled": false
+        },
+        {
+            "uuid": "5b421a77-2fbd-44e1-bf52-01e62e11b25b",
+            "name": "bm-node-02",
+            "power_state": "power off",
+            "target_power_state": null,
+            "provision_state": "available",
+            "target_provision_state": null,
+            "maintenance": true,
+            "maintenance_reason": "PSU replacement",
+            "last_error": null,
+            "driver": "ipmi",
+            "driver_info": {
+                "ipmi_address": "192.168.1.12"
+            },
+            "instance_uuid": null,
+            "resource_class": "baremetal-general",
+            "console_enabled": false
+        },
+        {
+            "uuid": "8f0c3b00-6f7c-4726-9d5c-dbe22523cb6c",
+            "name": null,
+            "power_state": "power off",
+            "target_power_state": null,
+            "provision_state": "enroll",
+            "target_provision_state": null,
+            "maintenance": false,
+            "maintenance_reason": null,
+            "last_error": null,
+            "driver": "ipmi",
+            "driver_info": {},
+            "instance_uuid": null,
+            "resource_class": "",
+            "console_enabled": false
+        }
+    ]
+}`
+
+// HandleBaremetalListSuccessfully mocks the bare metal version discovery and
+// nodes detail calls.
+func (m *SDMock) HandleBaremetalListSuccessfully() {
+	m.Mux.HandleFunc("/ironic/", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprintf(w, `
+                        {
+                                "versions": [
+                                        {
+                                                "status": "CURRENT",
+                                                "id": "v1",
+                                                "links": [
+                                                        { "href": "%s", "rel": "self" }
+                                                ]
+                                        }
+                                ]
+                        }
+                `, m.Endpoint()+"ironic/v1/")
+	})
+	m.Mux.HandleFunc("/ironic/v1/nodes/detail", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(m.t, r, http.MethodGet)
+		testHeader(m.t, r, "X-Auth-Token", tokenID)
+
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, baremetalListBody)
+	})
+}
+
 // HandleHypervisorListSuccessfully mocks os-hypervisors detail call.
 func (m *SDMock) HandleHypervisorListSuccessfully() {
 	m.Mux.HandleFunc("/os-hypervisors/detail", func(w http.ResponseWriter, r *http.Request) {
@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/gophercloud/gophercloud/v2"
@@ -46,24 +48,29 @@ func init() {
 
 // SDConfig is the configuration for OpenStack based service discovery.
 type SDConfig struct {
-	IdentityEndpoint            string           `yaml:"identity_endpoint"`
-	Username                    string           `yaml:"username"`
-	UserID                      string           `yaml:"userid"`
-	Password                    config.Secret    `yaml:"password"`
-	ProjectName                 string           `yaml:"project_name"`
-	ProjectID                   string           `yaml:"project_id"`
-	DomainName                  string           `yaml:"domain_name"`
-	DomainID                    string           `yaml:"domain_id"`
-	ApplicationCredentialName   string           `yaml:"application_credential_name"`
-	ApplicationCredentialID     string           `yaml:"application_credential_id"`
-	ApplicationCredentialSecret config.Secret    `yaml:"application_credential_secret"`
-	Role                        Role             `yaml:"role"`
-	Region                      string           `yaml:"region"`
-	RefreshInterval             model.Duration   `yaml:"refresh_interval"`
-	Port                        int              `yaml:"port"`
-	AllTenants                  bool             `yaml:"all_tenants,omitempty"`
-	TLSConfig                   config.TLSConfig `yaml:"tls_config,omitempty"`
-	Availability                string           `yaml:"availability,omitempty"`
+	IdentityEndpoint            string        `yaml:"identity_endpoint"`
+	Username                    string        `yaml:"username"`
+	UserID                      string        `yaml:"userid"`
+	Password                    config.Secret `yaml:"password"`
+	ProjectName                 string        `yaml:"project_name"`
+	ProjectID                   string        `yaml:"project_id"`
+	DomainName                  string        `yaml:"domain_name"`
+	DomainID                    string        `yaml:"domain_id"`
+	ApplicationCredentialName   string        `yaml:"application_credential_name"`
+	ApplicationCredentialID     string        `yaml:"application_credential_id"`
+	ApplicationCredentialSecret config.Secret `yaml:"application_credential_secret"`
+	// ApplicationCredentialIDFile and ApplicationCredentialSecretFile are
+	// re-read before every refresh, so rotated credentials are picked up
+	// without restarting Prometheus.
+	ApplicationCredentialIDFile     string           `yaml:"application_credential_id_file,omitempty"`
+	ApplicationCredentialSecretFile string           `yaml:"application_credential_secret_file,omitempty"`
+	Role                            Role             `yaml:"role"`
+	Region                          string           `yaml:"region"`
+	RefreshInterval                 model.Duration   `yaml:"refresh_interval"`
+	Port                            int              `yaml:"port"`
+	AllTenants                      bool             `yaml:"all_tenants,omitempty"`
+	TLSConfig                       config.TLSConfig `yaml:"tls_config,omitempty"`
+	Availability                    string           `yaml:"availability,omitempty"`
 }
 
 // NewDiscovererMetrics implements discovery.Config.
@@ -83,6 +90,8 @@ func (c *SDConfig) NewDiscoverer(opts discovery.DiscovererOptions) (discovery.Di
 
 // SetDirectory joins any relative file paths with dir.
 func (c *SDConfig) SetDirectory(dir string) {
+	c.ApplicationCredentialIDFile = config.JoinDir(dir, c.ApplicationCredentialIDFile)
+	c.ApplicationCredentialSecretFile = config.JoinDir(dir, c.ApplicationCredentialSecretFile)
 	c.TLSConfig.SetDirectory(dir)
 }
 
@@ -100,6 +109,9 @@ const (
 	// Openstack document reference
 	// https://docs.openstack.org/openstacksdk/rocky/user/resources/load_balancer/index.html
 	OpenStackRoleLoadBalancer Role = "loadbalancer"
+	// OpenStack document reference
+	// https://docs.openstack.org/ironic/latest/
+	OpenStackRoleBaremetal Role = "baremetal"
 )
 
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
@@ -108,7 +120,7 @@ func (c *Role) UnmarshalYAML(unmarshal func(any) error) error {
 		return err
 	}
 	switch *c {
-	case OpenStackRoleHypervisor, OpenStackRoleInstance, OpenStackRoleLoadBalancer:
+	case OpenStackRoleHypervisor, OpenStackRoleInstance, OpenStackRoleLoadBalancer, OpenStackRoleBaremetal:
 		return nil
 	default:
 		return fmt.Errorf("unknown OpenStack SD role %q", *c)
@@ -131,11 +143,17 @@ func (c *SDConfig) UnmarshalYAML(unmarshal func(any) error) error {
 	}
 
 	if c.Role == "" {
-		return errors.New("role missing (one of: instance, hypervisor, loadbalancer)")
+		return errors.New("role missing (one of: instance, hypervisor, loadbalancer, baremetal)")
 	}
 	if c.Region == "" {
 		return errors.New("openstack SD configuration requires a region")
 	}
+	if c.ApplicationCredentialID != "" && c.ApplicationCredentialIDFile != "" {
+		return errors.New("at most one of application_credential_id and application_credential_id_file must be configured")
+	}
+	if c.ApplicationCredentialSecret != "" && c.ApplicationCredentialSecretFile != "" {
+		return errors.New("at most one of application_credential_secret and application_credential_secret_file must be configured")
+	}
 
 	return nil
 }
@@ -210,13 +228,55 @@ func newRefresher(conf *SDConfig, l *slog.Logger) (refresher, error) {
 		Timeout: time.Duration(conf.RefreshInterval),
 	}
 	availability := gophercloud.Availability(conf.Availability)
+	var r refresher
 	switch conf.Role {
 	case OpenStackRoleHypervisor:
-		return newHypervisorDiscovery(client, &opts, conf.Port, conf.Region, availability, l), nil
+		r = newHypervisorDiscovery(client, &opts, conf.Port, conf.Region, availability, l)
 	case OpenStackRoleInstance:
-		return newInstanceDiscovery(client, &opts, conf.Port, conf.Region, conf.AllTenants, availability, l), nil
+		r = newInstanceDiscovery(client, &opts, conf.Port, conf.Region, conf.AllTenants, availability, l)
 	case OpenStackRoleLoadBalancer:
-		return newLoadBalancerDiscovery(client, &opts, conf.Region, availability, l), nil
+		r = newLoadBalancerDiscovery(client, &opts, conf.Region, availability, l)
+	case OpenStackRoleBaremetal:
+		r = newBaremetalDiscovery(client, &opts, conf.Port, conf.Region, availability, l)
+	default:
+		return nil, errors.New("unknown OpenStack discovery role")
+	}
+	if conf.ApplicationCredentialIDFile != "" || conf.ApplicationCredentialSecretFile != "" {
+		r = &credentialsReloader{
+			refresher:  r,
+			authOpts:   &opts,
+			idFile:     conf.ApplicationCredentialIDFile,
+			secretFile: conf.ApplicationCredentialSecretFile,
+		}
+	}
+	return r, nil
+}
+
+// credentialsReloader wraps a refresher and re-reads application credentials
+// from disk before every refresh. The role discoveries share the wrapped
+// AuthOptions by pointer, so an update here is seen by the next
+// Authenticate call.
+type credentialsReloader struct {
+	refresher  refresher
+	authOpts   *gophercloud.AuthOptions
+	idFile     string
+	secretFile string
+}
+
+func (c *credentialsReloader) refresh(ctx context.Context) ([]*targetgroup.Group, error) {
+	if c.idFile != "" {
+		id, err := os.ReadFile(c.idFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read application credential ID file: %w", err)
+		}
+		c.authOpts.ApplicationCredentialID = strings.TrimSpace(string(id))
+	}
+	if c.secretFile != "" {
+		secret, err := os.ReadFile(c.secretFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read application credential secret file: %w", err)
+		}
+		c.authOpts.ApplicationCredentialSecret = strings.TrimSpace(string(secret))
 	}
-	return nil, errors.New("unknown OpenStack discovery role")
+	return c.refresher.refresh(ctx)
 }
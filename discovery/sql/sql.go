@@ -0,0 +1,288 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq" // Register the postgres driver.
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/promslog"
+
+	"github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/refresh"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/prometheus/prometheus/util/strutil"
+)
+
+const (
+	// addressColumn is the query result column that provides the target address.
+	addressColumn = "address"
+
+	sqlLabelPrefix    = model.MetaLabelPrefix + "sql_"
+	sqlDriverLabel    = sqlLabelPrefix + "driver"
+	sqlColumnLabelPfx = sqlLabelPrefix + "column_"
+)
+
+// DefaultSDConfig is the default SQL SD configuration.
+var DefaultSDConfig = SDConfig{
+	RefreshInterval:    model.Duration(60 * time.Second),
+	MaxOpenConnections: 2,
+	MaxIdleConnections: 2,
+}
+
+func init() {
+	discovery.RegisterConfig(&SDConfig{})
+}
+
+// SDConfig is the configuration for SQL based discovery.
+type SDConfig struct {
+	// Driver is the database driver to use, either "mysql" or "postgres".
+	Driver string `yaml:"driver"`
+	// DataSourceName is the driver-specific data source name, including
+	// address and credentials.
+	DataSourceName config.Secret `yaml:"data_source_name"`
+	// Query is run on every refresh. It must return an "address" column;
+	// all other columns become labels of the target.
+	Query           string         `yaml:"query"`
+	RefreshInterval model.Duration `yaml:"refresh_interval,omitempty"`
+
+	// Connection pool settings, applied to the underlying sql.DB.
+	// Zero or negative values mean no limit.
+	MaxOpenConnections    int            `yaml:"max_open_connections,omitempty"`
+	MaxIdleConnections    int            `yaml:"max_idle_connections,omitempty"`
+	ConnectionMaxLifetime model.Duration `yaml:"connection_max_lifetime,omitempty"`
+
+	// TLSConfig is only supported for the mysql driver. For postgres,
+	// configure TLS through the ssl* data source name parameters instead.
+	TLSConfig config.TLSConfig `yaml:"tls_config,omitempty"`
+}
+
+// NewDiscovererMetrics implements discovery.Config.
+func (*SDConfig) NewDiscovererMetrics(reg prometheus.Registerer, rmi discovery.RefreshMetricsInstantiator) discovery.DiscovererMetrics {
+	return newDiscovererMetrics(reg, rmi)
+}
+
+// Name returns the name of the Config.
+func (*SDConfig) Name() string { return "sql" }
+
+// NewDiscoverer returns a Discoverer for the Config.
+func (c *SDConfig) NewDiscoverer(opts discovery.DiscovererOptions) (discovery.Discoverer, error) {
+	return NewDiscovery(c, opts)
+}
+
+// SetDirectory joins any relative file paths with dir.
+func (c *SDConfig) SetDirectory(dir string) {
+	c.TLSConfig.SetDirectory(dir)
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *SDConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	*c = DefaultSDConfig
+	type plain SDConfig
+	err := unmarshal((*plain)(c))
+	if err != nil {
+		return err
+	}
+	switch c.Driver {
+	case "mysql", "postgres":
+	case "":
+		return errors.New("driver is missing")
+	default:
+		return fmt.Errorf("unsupported driver %q, must be one of 'mysql' or 'postgres'", c.Driver)
+	}
+	if c.DataSourceName == "" {
+		return errors.New("data_source_name is missing")
+	}
+	if c.Query == "" {
+		return errors.New("query is missing")
+	}
+	if c.TLSConfig != (config.TLSConfig{}) && c.Driver != "mysql" {
+		return errors.New("tls_config is only supported for the mysql driver, use the ssl* data source name parameters for postgres")
+	}
+	return c.TLSConfig.Validate()
+}
+
+// tlsConfigID distinguishes the TLS configs this process registers with the
+// mysql driver, which only supports process-global registration by name.
+var tlsConfigID atomic.Int64
+
+// Discovery periodically runs a query against a SQL database and turns the
+// resulting rows into targets.
+type Discovery struct {
+	*refresh.Discovery
+	db      *sql.DB
+	driver  string
+	query   string
+	metrics *sqlMetrics
+}
+
+// NewDiscovery returns a new SQL discovery for the given config.
+func NewDiscovery(conf *SDConfig, opts discovery.DiscovererOptions) (*Discovery, error) {
+	m, ok := opts.Metrics.(*sqlMetrics)
+	if !ok {
+		return nil, errors.New("invalid discovery metrics type")
+	}
+
+	if opts.Logger == nil {
+		opts.Logger = promslog.NewNopLogger()
+	}
+
+	dsn := string(conf.DataSourceName)
+	if conf.Driver == "mysql" && conf.TLSConfig != (config.TLSConfig{}) {
+		tlsCfg, err := config.NewTLSConfig(&conf.TLSConfig)
+		if err != nil {
+			return nil, err
+		}
+		dsnCfg, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("parsing data source name: %w", err)
+		}
+		name := "prometheus-sd-" + strconv.FormatInt(tlsConfigID.Add(1), 10)
+		if err := mysql.RegisterTLSConfig(name, tlsCfg); err != nil {
+			return nil, err
+		}
+		dsnCfg.TLSConfig = name
+		dsn = dsnCfg.FormatDSN()
+	}
+
+	db, err := sql.Open(conf.Driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(conf.MaxOpenConnections)
+	db.SetMaxIdleConns(conf.MaxIdleConnections)
+	db.SetConnMaxLifetime(time.Duration(conf.ConnectionMaxLifetime))
+
+	d := &Discovery{
+		db:      db,
+		driver:  conf.Driver,
+		query:   conf.Query,
+		metrics: m,
+	}
+
+	d.Discovery = refresh.NewDiscovery(
+		refresh.Options{
+			Logger:              opts.Logger,
+			Mech:                "sql",
+			SetName:             opts.SetName,
+			Interval:            time.Duration(conf.RefreshInterval),
+			RefreshF:            d.Refresh,
+			MetricsInstantiator: m.refreshMetrics,
+		},
+	)
+	return d, nil
+}
+
+// Run implements the Discoverer interface.
+func (d *Discovery) Run(ctx context.Context, up chan<- []*targetgroup.Group) {
+	defer d.db.Close()
+	d.Discovery.Run(ctx, up)
+}
+
+// Refresh runs the configured query and maps the result rows to targets.
+func (d *Discovery) Refresh(ctx context.Context) ([]*targetgroup.Group, error) {
+	rows, err := d.db.QueryContext(ctx, d.query)
+	if err != nil {
+		d.metrics.failuresCount.Inc()
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		d.metrics.failuresCount.Inc()
+		return nil, err
+	}
+	addrIdx := -1
+	for i, col := range cols {
+		if col == addressColumn {
+			addrIdx = i
+			break
+		}
+	}
+	if addrIdx < 0 {
+		d.metrics.failuresCount.Inc()
+		return nil, fmt.Errorf("query result is missing an %q column", addressColumn)
+	}
+
+	tg := &targetgroup.Group{
+		Source: "sql",
+		Labels: model.LabelSet{
+			sqlDriverLabel: model.LabelValue(d.driver),
+		},
+	}
+
+	vals := make([]any, len(cols))
+	for i := range vals {
+		vals[i] = new(any)
+	}
+	for rows.Next() {
+		if err := rows.Scan(vals...); err != nil {
+			d.metrics.failuresCount.Inc()
+			return nil, err
+		}
+		target := model.LabelSet{}
+		for i, col := range cols {
+			v, ok := labelValue(*vals[i].(*any))
+			if !ok {
+				// NULL values do not produce a label.
+				continue
+			}
+			if i == addrIdx {
+				target[model.AddressLabel] = model.LabelValue(v)
+				continue
+			}
+			name := model.LabelName(sqlColumnLabelPfx + strutil.SanitizeLabelName(col))
+			target[name] = model.LabelValue(v)
+		}
+		if target[model.AddressLabel] == "" {
+			d.metrics.failuresCount.Inc()
+			return nil, fmt.Errorf("query returned a row with an empty %q column", addressColumn)
+		}
+		tg.Targets = append(tg.Targets, target)
+	}
+	if err := rows.Err(); err != nil {
+		d.metrics.failuresCount.Inc()
+		return nil, err
+	}
+
+	return []*targetgroup.Group{tg}, nil
+}
+
+// labelValue converts a scanned column value to a label value. The second
+// return value is false for NULL values.
+func labelValue(v any) (string, bool) {
+	switch v := v.(type) {
+	case nil:
+		return "", false
+	case []byte:
+		return string(v), true
+	case string:
+		return v, true
+	case time.Time:
+		return v.Format(time.RFC3339), true
+	default:
+		return fmt.Sprint(v), true
+	}
+}
@@ -36,6 +36,26 @@ func (tg Group) String() string {
 	return tg.Source
 }
 
+// Delta describes an incremental update to the target groups of a single
+// target set. It is the streaming counterpart to sending the full list of
+// groups on every change: consumers maintain a merged view of the groups,
+// keyed by the Changed map keys, and apply deltas in epoch order.
+type Delta struct {
+	// Epoch increases by one with every delta emitted for a target set. A
+	// consumer observing a gap has missed an update and holds stale state.
+	Epoch uint64
+	// Full indicates that Changed holds the complete state of the target set
+	// and that any previously held groups must be discarded. The first delta
+	// emitted for a target set always has Full set.
+	Full bool
+	// Changed holds the target groups added or modified since the previous
+	// delta. Keys are opaque identifiers that are unique within the target
+	// set; consumers must use them verbatim when merging.
+	Changed map[string]*Group
+	// Dropped lists the keys of target groups removed since the previous delta.
+	Dropped []string
+}
+
 // UnmarshalYAML implements the yaml.Unmarshaler interface.
 func (tg *Group) UnmarshalYAML(unmarshal func(any) error) error {
 	g := struct {
@@ -0,0 +1,377 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	edsv3 "github.com/envoyproxy/go-control-plane/envoy/service/endpoint/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/promslog"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/prometheus/prometheus/util/osutil"
+)
+
+// DefaultEDSSDConfig is the default EDS SD configuration.
+var DefaultEDSSDConfig = EDSSDConfig{
+	RetryInterval: model.Duration(5 * time.Second),
+}
+
+const (
+	// edsResourceTypeURL is the xDS type URL of EDS resources.
+	edsResourceTypeURL = "type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment"
+
+	// edsMetaLabelPrefix is the meta prefix used for all EDS meta labels.
+	edsMetaLabelPrefix = model.MetaLabelPrefix + "eds_"
+
+	// edsClusterLabel is the name of the label that holds the cluster name.
+	edsClusterLabel = edsMetaLabelPrefix + "cluster"
+	// edsLocalityRegionLabel is the name of the label that holds the endpoint locality region.
+	edsLocalityRegionLabel = edsMetaLabelPrefix + "locality_region"
+	// edsLocalityZoneLabel is the name of the label that holds the endpoint locality zone.
+	edsLocalityZoneLabel = edsMetaLabelPrefix + "locality_zone"
+	// edsLocalitySubZoneLabel is the name of the label that holds the endpoint locality sub-zone.
+	edsLocalitySubZoneLabel = edsMetaLabelPrefix + "locality_sub_zone"
+	// edsLocalityWeightLabel is the name of the label that holds the locality load-balancing weight.
+	edsLocalityWeightLabel = edsMetaLabelPrefix + "locality_weight"
+	// edsEndpointWeightLabel is the name of the label that holds the endpoint load-balancing weight.
+	edsEndpointWeightLabel = edsMetaLabelPrefix + "endpoint_weight"
+	// edsHealthStatusLabel is the name of the label that holds the endpoint health status.
+	edsHealthStatusLabel = edsMetaLabelPrefix + "health_status"
+)
+
+// EDSSDConfig is the configuration for gRPC xDS endpoint discovery (EDS).
+type EDSSDConfig struct {
+	// Server is the xDS management server to stream endpoints from, in the
+	// form grpc://host:port or grpcs://host:port for TLS.
+	Server string `yaml:"server"`
+	// ResourceNames are the cluster names to subscribe to. An empty list
+	// subscribes to all clusters known to the server (wildcard mode).
+	ResourceNames []string `yaml:"resource_names,omitempty"`
+	// ClientID is the node ID sent to the management server. Defaults to the
+	// local FQDN.
+	ClientID string `yaml:"client_id,omitempty"`
+	// TLSConfig configures the transport for grpcs:// servers.
+	TLSConfig config.TLSConfig `yaml:"tls_config,omitempty"`
+	// Authorization configures the credentials sent with every stream as an
+	// Authorization header.
+	Authorization *config.Authorization `yaml:"authorization,omitempty"`
+	// RetryInterval is how long to wait before re-establishing a broken
+	// stream.
+	RetryInterval model.Duration `yaml:"retry_interval,omitempty"`
+}
+
+// NewDiscovererMetrics implements discovery.Config.
+func (*EDSSDConfig) NewDiscovererMetrics(reg prometheus.Registerer, _ discovery.RefreshMetricsInstantiator) discovery.DiscovererMetrics {
+	return newEDSMetrics(reg)
+}
+
+// Name returns the name of the Config.
+func (*EDSSDConfig) Name() string { return "eds" }
+
+// SetDirectory joins any relative file paths with dir.
+func (c *EDSSDConfig) SetDirectory(dir string) {
+	c.TLSConfig.SetDirectory(dir)
+	if c.Authorization != nil {
+		c.Authorization.SetDirectory(dir)
+	}
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *EDSSDConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	*c = DefaultEDSSDConfig
+	type plainEDSConf EDSSDConfig
+	if err := unmarshal((*plainEDSConf)(c)); err != nil {
+		return err
+	}
+	if c.Server == "" {
+		return errors.New("EDS SD server must not be empty")
+	}
+	parsedURL, err := url.Parse(c.Server)
+	if err != nil {
+		return err
+	}
+	if parsedURL.Scheme != "grpc" && parsedURL.Scheme != "grpcs" {
+		return fmt.Errorf("EDS SD server must use the grpc or grpcs scheme: %s", c.Server)
+	}
+	if parsedURL.Host == "" {
+		return fmt.Errorf("host is missing in EDS SD server: %s", c.Server)
+	}
+	if c.RetryInterval <= 0 {
+		return errors.New("EDS SD retry interval must be greater than 0")
+	}
+	return nil
+}
+
+// NewDiscoverer returns a Discoverer for the Config.
+func (c *EDSSDConfig) NewDiscoverer(opts discovery.DiscovererOptions) (discovery.Discoverer, error) {
+	logger := opts.Logger
+	if logger == nil {
+		logger = promslog.NewNopLogger()
+	}
+	return newEDSDiscovery(c, logger, opts.Metrics)
+}
+
+// edsDiscovery consumes an Envoy xDS endpoint discovery stream. It implements
+// the Discoverer interface.
+type edsDiscovery struct {
+	conf     *EDSSDConfig
+	clientID string
+	logger   *slog.Logger
+	metrics  *edsMetrics
+
+	// lastSources are the group sources sent with the previous update, used
+	// to generate empty groups for clusters that disappeared.
+	lastSources map[string]struct{}
+}
+
+func newEDSDiscovery(conf *EDSSDConfig, logger *slog.Logger, metrics discovery.DiscovererMetrics) (*edsDiscovery, error) {
+	m, ok := metrics.(*edsMetrics)
+	if !ok {
+		return nil, errors.New("invalid discovery metrics type")
+	}
+
+	// Default to "prometheus" if hostname is unavailable.
+	clientID := conf.ClientID
+	if clientID == "" {
+		var err error
+		clientID, err = osutil.GetFQDN()
+		if err != nil {
+			logger.Debug("error getting FQDN", "err", err)
+			clientID = "prometheus"
+		}
+	}
+
+	return &edsDiscovery{
+		conf:        conf,
+		clientID:    clientID,
+		logger:      logger,
+		metrics:     m,
+		lastSources: map[string]struct{}{},
+	}, nil
+}
+
+// Run implements the Discoverer interface.
+func (d *edsDiscovery) Run(ctx context.Context, ch chan<- []*targetgroup.Group) {
+	for ctx.Err() == nil {
+		if err := d.runStream(ctx, ch); err != nil && !errors.Is(err, context.Canceled) {
+			d.metrics.streamFailuresCount.Inc()
+			d.logger.Error("EDS stream failed, retrying", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(d.conf.RetryInterval)):
+		}
+	}
+}
+
+// runStream dials the management server and consumes a single EDS stream
+// until it breaks or the context is canceled.
+func (d *edsDiscovery) runStream(ctx context.Context, ch chan<- []*targetgroup.Group) error {
+	parsedURL, err := url.Parse(d.conf.Server)
+	if err != nil {
+		return err
+	}
+
+	creds := insecure.NewCredentials()
+	if parsedURL.Scheme == "grpcs" {
+		tlsConfig, err := config.NewTLSConfig(&d.conf.TLSConfig)
+		if err != nil {
+			return err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(parsedURL.Host, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	streamCtx := ctx
+	if d.conf.Authorization != nil {
+		header, err := authorizationHeader(d.conf.Authorization)
+		if err != nil {
+			return err
+		}
+		streamCtx = metadata.AppendToOutgoingContext(ctx, "authorization", header)
+	}
+
+	stream, err := edsv3.NewEndpointDiscoveryServiceClient(conn).StreamEndpoints(streamCtx)
+	if err != nil {
+		return err
+	}
+
+	req := &discoveryv3.DiscoveryRequest{
+		Node:          &corev3.Node{Id: d.clientID},
+		TypeUrl:       edsResourceTypeURL,
+		ResourceNames: d.conf.ResourceNames,
+	}
+	if err := stream.Send(req); err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		groups, err := d.parseResponse(resp)
+
+		ack := &discoveryv3.DiscoveryRequest{
+			Node:          &corev3.Node{Id: d.clientID},
+			TypeUrl:       edsResourceTypeURL,
+			ResourceNames: d.conf.ResourceNames,
+			VersionInfo:   resp.VersionInfo,
+			ResponseNonce: resp.Nonce,
+		}
+		if err != nil {
+			d.metrics.parseFailuresCount.Inc()
+			d.logger.Error("Error parsing EDS resources", "err", err)
+			// NACK the update so the server knows it was not applied.
+			ack.VersionInfo = ""
+			ack.ErrorDetail = &status.Status{Message: err.Error()}
+			if err := stream.Send(ack); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := stream.Send(ack); err != nil {
+			return err
+		}
+
+		d.metrics.updatesCount.Inc()
+		d.logger.Debug("Updated to version", "version", resp.VersionInfo, "groups", len(groups))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ch <- groups:
+		}
+	}
+}
+
+// parseResponse converts the cluster load assignments in an EDS response to
+// target group updates, including empty groups for clusters that were present
+// in the previous update but are now gone.
+func (d *edsDiscovery) parseResponse(resp *discoveryv3.DiscoveryResponse) ([]*targetgroup.Group, error) {
+	if resp.TypeUrl != edsResourceTypeURL {
+		return nil, fmt.Errorf("received invalid typeURL for EDS resource: %s", resp.TypeUrl)
+	}
+
+	var groups []*targetgroup.Group
+	sources := make(map[string]struct{}, len(resp.Resources))
+
+	for _, resource := range resp.Resources {
+		cla := &endpointv3.ClusterLoadAssignment{}
+		if err := resource.UnmarshalTo(cla); err != nil {
+			return nil, err
+		}
+
+		group := &targetgroup.Group{
+			Source: "eds:" + cla.ClusterName,
+			Labels: model.LabelSet{
+				edsClusterLabel: model.LabelValue(cla.ClusterName),
+			},
+		}
+		for _, localityEndpoints := range cla.Endpoints {
+			commonLabels := model.LabelSet{}
+			if locality := localityEndpoints.GetLocality(); locality != nil {
+				commonLabels[edsLocalityRegionLabel] = model.LabelValue(locality.Region)
+				commonLabels[edsLocalityZoneLabel] = model.LabelValue(locality.Zone)
+				commonLabels[edsLocalitySubZoneLabel] = model.LabelValue(locality.SubZone)
+			}
+			if w := localityEndpoints.GetLoadBalancingWeight(); w != nil {
+				commonLabels[edsLocalityWeightLabel] = model.LabelValue(strconv.Itoa(int(w.GetValue())))
+			}
+
+			for _, lbEndpoint := range localityEndpoints.LbEndpoints {
+				endpoint := lbEndpoint.GetEndpoint()
+				if endpoint == nil {
+					continue
+				}
+				socketAddress := endpoint.GetAddress().GetSocketAddress()
+				if socketAddress == nil {
+					continue
+				}
+
+				labels := commonLabels.Clone()
+				labels[model.AddressLabel] = model.LabelValue(
+					net.JoinHostPort(socketAddress.GetAddress(), strconv.Itoa(int(socketAddress.GetPortValue()))))
+				labels[edsHealthStatusLabel] = model.LabelValue(lbEndpoint.GetHealthStatus().String())
+				if w := lbEndpoint.GetLoadBalancingWeight(); w != nil {
+					labels[edsEndpointWeightLabel] = model.LabelValue(strconv.Itoa(int(w.GetValue())))
+				}
+
+				group.Targets = append(group.Targets, labels)
+			}
+		}
+
+		groups = append(groups, group)
+		sources[group.Source] = struct{}{}
+	}
+
+	// Generate empty updates for clusters that disappeared.
+	for source := range d.lastSources {
+		if _, ok := sources[source]; !ok {
+			groups = append(groups, &targetgroup.Group{Source: source})
+		}
+	}
+	d.lastSources = sources
+
+	return groups, nil
+}
+
+// authorizationHeader renders an Authorization header value from the given
+// config, reading the credentials file if one is configured.
+func authorizationHeader(auth *config.Authorization) (string, error) {
+	credentials := string(auth.Credentials)
+	if auth.CredentialsFile != "" {
+		b, err := os.ReadFile(auth.CredentialsFile)
+		if err != nil {
+			return "", fmt.Errorf("unable to read authorization credentials file %s: %w", auth.CredentialsFile, err)
+		}
+		credentials = strings.TrimSpace(string(b))
+	}
+	authType := auth.Type
+	if authType == "" {
+		authType = "Bearer"
+	}
+	return authType + " " + credentials, nil
+}
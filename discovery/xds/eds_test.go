@@ -0,0 +1,312 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	edsv3 "github.com/envoyproxy/go-control-plane/envoy/service/endpoint/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	config_util "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+)
+
+// testEDSServer is a minimal EDS management server that serves a sequence of
+// responses on each stream and records the requests it received.
+type testEDSServer struct {
+	edsv3.UnimplementedEndpointDiscoveryServiceServer
+
+	responses []*discoveryv3.DiscoveryResponse
+	requests  chan *discoveryv3.DiscoveryRequest
+	metadata  chan metadata.MD
+}
+
+func (s *testEDSServer) StreamEndpoints(stream edsv3.EndpointDiscoveryService_StreamEndpointsServer) error {
+	if md, ok := metadata.FromIncomingContext(stream.Context()); ok {
+		select {
+		case s.metadata <- md:
+		default:
+		}
+	}
+
+	for _, resp := range s.responses {
+		req, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		s.requests <- req
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	// Consume the final ACK, then hold the stream open until the client
+	// goes away.
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	s.requests <- req
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
+
+func makeClusterLoadAssignment(t *testing.T, cluster string) *anypb.Any {
+	t.Helper()
+
+	cla := &endpointv3.ClusterLoadAssignment{
+		ClusterName: cluster,
+		Endpoints: []*endpointv3.LocalityLbEndpoints{
+			{
+				Locality:            &corev3.Locality{Region: "eu-west-1", Zone: "eu-west-1a"},
+				LoadBalancingWeight: wrapperspb.UInt32(10),
+				LbEndpoints: []*endpointv3.LbEndpoint{
+					{
+						HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+							Endpoint: &endpointv3.Endpoint{
+								Address: &corev3.Address{
+									Address: &corev3.Address_SocketAddress{
+										SocketAddress: &corev3.SocketAddress{
+											Address:       "10.1.2.3",
+											PortSpecifier: &corev3.SocketAddress_PortValue{PortValue: 9090},
+										},
+									},
+								},
+							},
+						},
+						HealthStatus:        corev3.HealthStatus_HEALTHY,
+						LoadBalancingWeight: wrapperspb.UInt32(3),
+					},
+				},
+			},
+		},
+	}
+	res, err := anypb.New(cla)
+	require.NoError(t, err)
+	return res
+}
+
+func startTestEDSServer(t *testing.T, server *testEDSServer) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	edsv3.RegisterEndpointDiscoveryServiceServer(grpcServer, server)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	return lis.Addr().String()
+}
+
+func newTestEDSDiscovery(t *testing.T, conf *EDSSDConfig) *edsDiscovery {
+	t.Helper()
+
+	reg := prometheus.NewRegistry()
+	metrics := conf.NewDiscovererMetrics(reg, discovery.NewRefreshMetrics(reg))
+	require.NoError(t, metrics.Register())
+	t.Cleanup(metrics.Unregister)
+
+	d, err := newEDSDiscovery(conf, nopLogger, metrics)
+	require.NoError(t, err)
+	return d
+}
+
+func TestEDSDiscoveryStream(t *testing.T) {
+	server := &testEDSServer{
+		responses: []*discoveryv3.DiscoveryResponse{
+			{
+				VersionInfo: "1",
+				Nonce:       "a",
+				TypeUrl:     edsResourceTypeURL,
+				Resources: []*anypb.Any{
+					makeClusterLoadAssignment(t, "backend"),
+					makeClusterLoadAssignment(t, "frontend"),
+				},
+			},
+			{
+				VersionInfo: "2",
+				Nonce:       "b",
+				TypeUrl:     edsResourceTypeURL,
+				Resources:   []*anypb.Any{makeClusterLoadAssignment(t, "backend")},
+			},
+		},
+		requests: make(chan *discoveryv3.DiscoveryRequest, 8),
+		metadata: make(chan metadata.MD, 1),
+	}
+	addr := startTestEDSServer(t, server)
+
+	d := newTestEDSDiscovery(t, &EDSSDConfig{
+		Server:        fmt.Sprintf("grpc://%s", addr),
+		ResourceNames: []string{"backend", "frontend"},
+		ClientID:      "test-prometheus",
+		RetryInterval: model.Duration(time.Second),
+		Authorization: &config_util.Authorization{Credentials: "token"},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan []*targetgroup.Group)
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx, ch)
+		close(done)
+	}()
+
+	// The initial request subscribes to the configured clusters.
+	initial := <-server.requests
+	require.Equal(t, "test-prometheus", initial.Node.Id)
+	require.Equal(t, edsResourceTypeURL, initial.TypeUrl)
+	require.Equal(t, []string{"backend", "frontend"}, initial.ResourceNames)
+	md := <-server.metadata
+	require.Equal(t, []string{"Bearer token"}, md.Get("authorization"))
+
+	groups := <-ch
+	require.Len(t, groups, 2)
+	require.Equal(t, "eds:backend", groups[0].Source)
+	require.Equal(t, model.LabelSet{edsClusterLabel: "backend"}, groups[0].Labels)
+	require.Equal(t, []model.LabelSet{
+		{
+			model.AddressLabel:      "10.1.2.3:9090",
+			edsLocalityRegionLabel:  "eu-west-1",
+			edsLocalityZoneLabel:    "eu-west-1a",
+			edsLocalitySubZoneLabel: "",
+			edsLocalityWeightLabel:  "10",
+			edsEndpointWeightLabel:  "3",
+			edsHealthStatusLabel:    "HEALTHY",
+		},
+	}, groups[0].Targets)
+
+	// The first response is ACKed with its version and nonce.
+	ack := <-server.requests
+	require.Equal(t, "1", ack.VersionInfo)
+	require.Equal(t, "a", ack.ResponseNonce)
+	require.Nil(t, ack.ErrorDetail)
+
+	// The second response drops the frontend cluster, which must be sent as
+	// an empty group so its targets are removed.
+	groups = <-ch
+	require.Len(t, groups, 2)
+	require.Equal(t, "eds:backend", groups[0].Source)
+	require.Equal(t, &targetgroup.Group{Source: "eds:frontend"}, groups[1])
+
+	ack = <-server.requests
+	require.Equal(t, "2", ack.VersionInfo)
+	require.Equal(t, "b", ack.ResponseNonce)
+
+	cancel()
+	<-done
+}
+
+func TestEDSDiscoveryNACKsBadResponse(t *testing.T) {
+	badResource, err := anypb.New(&corev3.Node{Id: "not-a-cluster-load-assignment"})
+	require.NoError(t, err)
+
+	server := &testEDSServer{
+		responses: []*discoveryv3.DiscoveryResponse{
+			{
+				VersionInfo: "1",
+				Nonce:       "a",
+				TypeUrl:     edsResourceTypeURL,
+				Resources:   []*anypb.Any{badResource},
+			},
+		},
+		requests: make(chan *discoveryv3.DiscoveryRequest, 8),
+		metadata: make(chan metadata.MD, 1),
+	}
+	addr := startTestEDSServer(t, server)
+
+	d := newTestEDSDiscovery(t, &EDSSDConfig{
+		Server:        fmt.Sprintf("grpc://%s", addr),
+		ClientID:      "test-prometheus",
+		RetryInterval: model.Duration(time.Second),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan []*targetgroup.Group)
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx, ch)
+		close(done)
+	}()
+
+	<-server.requests // Initial request.
+	nack := <-server.requests
+	require.Empty(t, nack.VersionInfo)
+	require.Equal(t, "a", nack.ResponseNonce)
+	require.NotNil(t, nack.ErrorDetail)
+
+	cancel()
+	<-done
+}
+
+func TestEDSSDConfigUnmarshalYAML(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		yaml      string
+		expectErr string
+	}{
+		{
+			name: "valid",
+			yaml: "server: grpc://xds.example.com:18000\nresource_names: [backend]\n",
+		},
+		{
+			name: "valid grpcs",
+			yaml: "server: grpcs://xds.example.com:18000\n",
+		},
+		{
+			name:      "missing server",
+			yaml:      "client_id: foo\n",
+			expectErr: "server must not be empty",
+		},
+		{
+			name:      "wrong scheme",
+			yaml:      "server: http://xds.example.com:18000\n",
+			expectErr: "grpc or grpcs scheme",
+		},
+		{
+			name:      "zero retry interval",
+			yaml:      "server: grpc://xds.example.com:18000\nretry_interval: 0\n",
+			expectErr: "retry interval must be greater than 0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var conf EDSSDConfig
+			err := yaml.Unmarshal([]byte(tc.yaml), &conf)
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, DefaultEDSSDConfig.RetryInterval, conf.RetryInterval)
+		})
+	}
+}
@@ -71,3 +71,54 @@ func (m *xdsMetrics) Register() error {
 func (m *xdsMetrics) Unregister() {
 	m.metricRegisterer.UnregisterMetrics()
 }
+
+var _ discovery.DiscovererMetrics = (*edsMetrics)(nil)
+
+type edsMetrics struct {
+	streamFailuresCount prometheus.Counter
+	parseFailuresCount  prometheus.Counter
+	updatesCount        prometheus.Counter
+
+	metricRegisterer discovery.MetricRegisterer
+}
+
+func newEDSMetrics(reg prometheus.Registerer) discovery.DiscovererMetrics {
+	m := &edsMetrics{
+		streamFailuresCount: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "sd_eds_stream_failures_total",
+				Help:      "The number of EDS stream connection or receive failures.",
+			}),
+		parseFailuresCount: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "sd_eds_parse_failures_total",
+				Help:      "The number of EDS responses that could not be parsed and were NACKed.",
+			}),
+		updatesCount: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "sd_eds_updates_total",
+				Help:      "The number of EDS responses applied as target updates.",
+			}),
+	}
+
+	m.metricRegisterer = discovery.NewMetricRegisterer(reg, []prometheus.Collector{
+		m.streamFailuresCount,
+		m.parseFailuresCount,
+		m.updatesCount,
+	})
+
+	return m
+}
+
+// Register implements discovery.DiscovererMetrics.
+func (m *edsMetrics) Register() error {
+	return m.metricRegisterer.RegisterMetrics()
+}
+
+// Unregister implements discovery.DiscovererMetrics.
+func (m *edsMetrics) Unregister() {
+	m.metricRegisterer.UnregisterMetrics()
+}
@@ -67,6 +67,7 @@ func mustRegisterMessage(typeRegistry *protoregistry.Types, mt protoreflect.Mess
 func init() {
 	// Register top-level SD Configs.
 	discovery.RegisterConfig(&KumaSDConfig{})
+	discovery.RegisterConfig(&EDSSDConfig{})
 
 	// Register protobuf types that need to be marshalled/ unmarshalled.
 	mustRegisterMessage(protoTypes, (&v3.DiscoveryRequest{}).ProtoReflect().Type())
@@ -157,23 +157,25 @@ func (g *RuleGroups) Validate(node ruleGroups, nameValidationScheme model.Valida
 
 // RuleGroup is a list of sequentially evaluated recording and alerting rules.
 type RuleGroup struct {
-	Name        string            `yaml:"name"`
-	Interval    model.Duration    `yaml:"interval,omitempty"`
-	QueryOffset *model.Duration   `yaml:"query_offset,omitempty"`
-	Limit       int               `yaml:"limit,omitempty"`
-	Rules       []Rule            `yaml:"rules"`
-	Labels      map[string]string `yaml:"labels,omitempty"`
+	Name              string            `yaml:"name"`
+	Interval          model.Duration    `yaml:"interval,omitempty"`
+	QueryOffset       *model.Duration   `yaml:"query_offset,omitempty"`
+	EvaluationTimeout model.Duration    `yaml:"evaluation_timeout,omitempty"`
+	Limit             int               `yaml:"limit,omitempty"`
+	Rules             []Rule            `yaml:"rules"`
+	Labels            map[string]string `yaml:"labels,omitempty"`
 }
 
 // RuleGroupNode adds yaml.v3 layer to support line and columns outputs for invalid rule groups.
 type RuleGroupNode struct {
 	yaml.Node
-	Name        string            `yaml:"name"`
-	Interval    model.Duration    `yaml:"interval,omitempty"`
-	QueryOffset *model.Duration   `yaml:"query_offset,omitempty"`
-	Limit       int               `yaml:"limit,omitempty"`
-	Rules       []RuleNode        `yaml:"rules"`
-	Labels      map[string]string `yaml:"labels,omitempty"`
+	Name              string            `yaml:"name"`
+	Interval          model.Duration    `yaml:"interval,omitempty"`
+	QueryOffset       *model.Duration   `yaml:"query_offset,omitempty"`
+	EvaluationTimeout model.Duration    `yaml:"evaluation_timeout,omitempty"`
+	Limit             int               `yaml:"limit,omitempty"`
+	Rules             []RuleNode        `yaml:"rules"`
+	Labels            map[string]string `yaml:"labels,omitempty"`
 }
 
 // Rule describes an alerting or recording rule.
@@ -185,6 +187,7 @@ type Rule struct {
 	KeepFiringFor model.Duration    `yaml:"keep_firing_for,omitempty"`
 	Labels        map[string]string `yaml:"labels,omitempty"`
 	Annotations   map[string]string `yaml:"annotations,omitempty"`
+	GeneratorURL  string            `yaml:"generator_url,omitempty"`
 }
 
 // RuleNode adds yaml.v3 layer to support line and column outputs for invalid rules.
@@ -196,6 +199,7 @@ type RuleNode struct {
 	KeepFiringFor model.Duration    `yaml:"keep_firing_for,omitempty"`
 	Labels        map[string]string `yaml:"labels,omitempty"`
 	Annotations   map[string]string `yaml:"annotations,omitempty"`
+	GeneratorURL  string            `yaml:"generator_url,omitempty"`
 }
 
 // Validate the rule and return a list of encountered errors.
@@ -245,6 +249,12 @@ func (r *Rule) Validate(node RuleNode, nameValidationScheme model.ValidationSche
 				node: &node.Record,
 			})
 		}
+		if r.GeneratorURL != "" {
+			nodes = append(nodes, WrappedError{
+				err:  errors.New("invalid field 'generator_url' in recording rule"),
+				node: &node.Record,
+			})
+		}
 		if !nameValidationScheme.IsValidMetricName(r.Record) {
 			nodes = append(nodes, WrappedError{
 				err:  fmt.Errorf("invalid recording rule name: %s", r.Record),
@@ -116,7 +116,14 @@ func BenchmarkParsePromProto(b *testing.B) {
 	data := createTestProtoBuf(b).Bytes()
 	// TODO(bwplotka): Add comparison with expfmt.TypeProtoDelim once expfmt
 	// support GAUGE_HISTOGRAM, see https://github.com/prometheus/common/issues/430.
-	benchParse(b, data, "promproto")
+	for _, parser := range []string{
+		"promproto",            // Measure protobuf parser baseline.
+		"promproto_with_arena", // Measure histogram reuse across parses, as done by scrape loops.
+	} {
+		b.Run(fmt.Sprintf("parser=%v", parser), func(b *testing.B) {
+			benchParse(b, data, parser)
+		})
+	}
 }
 
 /*
@@ -151,6 +158,14 @@ func benchParse(b *testing.B, data []byte, parser string) {
 		newParserFn = func(b []byte, st *labels.SymbolTable) Parser {
 			return NewProtobufParser(b, false, true, false, false, st)
 		}
+	case "promproto_with_arena":
+		arena := NewHistogramArena()
+		newParserFn = func(b []byte, st *labels.SymbolTable) Parser {
+			arena.Reset()
+			p := NewProtobufParser(b, false, true, false, false, st)
+			p.SetHistogramArena(arena)
+			return p
+		}
 	case "omtext":
 		newParserFn = func(b []byte, st *labels.SymbolTable) Parser {
 			return NewOpenMetricsParser(b, st, WithOMParserSTSeriesSkipped())
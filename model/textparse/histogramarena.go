@@ -0,0 +1,109 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textparse
+
+import (
+	"github.com/prometheus/prometheus/model/histogram"
+)
+
+// HistogramArena allows reusing histogram structs and their span and bucket
+// slices across parses, e.g. across repeated scrapes of the same target. It
+// removes the per-sample allocations the protobuf parser otherwise performs
+// for every native histogram.
+//
+// Histograms obtained from a parser backed by an arena are only valid until
+// the next call to Reset. Callers that need to retain a histogram beyond that
+// point (the storage.Appender contract allows retaining appended histograms
+// until Commit or Rollback) must only call Reset afterwards, or copy the
+// histogram.
+//
+// A HistogramArena is not safe for concurrent use.
+type HistogramArena struct {
+	hs  []*histogram.Histogram
+	fhs []*histogram.FloatHistogram
+	// Number of histograms handed out since the last Reset.
+	hn, fhn int
+}
+
+// NewHistogramArena returns an empty arena ready for use.
+func NewHistogramArena() *HistogramArena {
+	return &HistogramArena{}
+}
+
+// Reset makes all histograms previously obtained from the arena available
+// for reuse. The caller must not use any of them afterwards.
+func (a *HistogramArena) Reset() {
+	a.hn, a.fhn = 0, 0
+}
+
+// histogram returns a zeroed integer histogram, reusing a previously handed
+// out one if available. Its span and bucket slices keep their capacity but
+// have zero length.
+func (a *HistogramArena) histogram() *histogram.Histogram {
+	if a.hn == len(a.hs) {
+		a.hs = append(a.hs, &histogram.Histogram{})
+	}
+	h := a.hs[a.hn]
+	a.hn++
+	*h = histogram.Histogram{
+		PositiveSpans:   h.PositiveSpans[:0],
+		PositiveBuckets: h.PositiveBuckets[:0],
+		NegativeSpans:   h.NegativeSpans[:0],
+		NegativeBuckets: h.NegativeBuckets[:0],
+		CustomValues:    h.CustomValues[:0],
+	}
+	return h
+}
+
+// floatHistogram is the float counterpart of histogram.
+func (a *HistogramArena) floatHistogram() *histogram.FloatHistogram {
+	if a.fhn == len(a.fhs) {
+		a.fhs = append(a.fhs, &histogram.FloatHistogram{})
+	}
+	fh := a.fhs[a.fhn]
+	a.fhn++
+	*fh = histogram.FloatHistogram{
+		PositiveSpans:   fh.PositiveSpans[:0],
+		PositiveBuckets: fh.PositiveBuckets[:0],
+		NegativeSpans:   fh.NegativeSpans[:0],
+		NegativeBuckets: fh.NegativeBuckets[:0],
+		CustomValues:    fh.CustomValues[:0],
+	}
+	return fh
+}
+
+// resizeSpans returns s grown to length n, allocating only if the capacity
+// is insufficient. The content of the returned slice is unspecified.
+func resizeSpans(s []histogram.Span, n int) []histogram.Span {
+	if cap(s) >= n {
+		return s[:n]
+	}
+	return make([]histogram.Span, n)
+}
+
+// resizeFloats is resizeSpans for float bucket slices.
+func resizeFloats(s []float64, n int) []float64 {
+	if cap(s) >= n {
+		return s[:n]
+	}
+	return make([]float64, n)
+}
+
+// resizeInts is resizeSpans for integer bucket slices.
+func resizeInts(s []int64, n int) []int64 {
+	if cap(s) >= n {
+		return s[:n]
+	}
+	return make([]int64, n)
+}
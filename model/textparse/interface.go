@@ -112,7 +112,8 @@ func extractMediaType(contentType, fallbackType string) (string, error) {
 	// We have a valid media type, either we recognise it and can use it
 	// or we have to error.
 	switch mediaType {
-	case "application/openmetrics-text", "application/vnd.google.protobuf", "text/plain":
+	case "application/openmetrics-text", "application/vnd.google.protobuf", "text/plain",
+		"application/vnd.otlp+json", "application/vnd.otlp+proto":
 		return mediaType, nil
 	}
 	// We're here because we have no recognised mediaType.
@@ -153,6 +154,12 @@ type ParserOptions struct {
 	// FallbackContentType specifies the fallback content type to use when the provided
 	// Content-Type header cannot be parsed or is not supported.
 	FallbackContentType string
+
+	// HistogramArena, if set, is used to obtain the native histograms the
+	// parser returns instead of allocating them. (Proto parsing only.)
+	// See HistogramArena for the lifetime rules this imposes on the
+	// returned histograms.
+	HistogramArena *HistogramArena
 }
 
 // New returns a new parser of the byte slice.
@@ -179,16 +186,27 @@ func New(b []byte, contentType string, st *labels.SymbolTable, opts ParserOption
 			o.enableTypeAndUnitLabels = opts.EnableTypeAndUnitLabels
 		})
 	case "application/vnd.google.protobuf":
-		return NewProtobufParser(
+		p := NewProtobufParser(
 			b,
 			opts.IgnoreNativeHistograms,
 			opts.KeepClassicOnClassicAndNativeHistograms,
 			opts.ConvertClassicHistogramsToNHCB,
 			opts.EnableTypeAndUnitLabels,
 			st,
-		), err
+		)
+		if opts.HistogramArena != nil {
+			p.SetHistogramArena(opts.HistogramArena)
+		}
+		return p, err
 	case "text/plain":
 		baseParser = NewPromParser(b, st, opts.EnableTypeAndUnitLabels)
+	case "application/vnd.otlp+json", "application/vnd.otlp+proto":
+		p, perr := NewOTLPParser(b, mediaType == "application/vnd.otlp+json", st, opts)
+		if perr != nil {
+			return nil, perr
+		}
+		// NHCB conversion is handled by the OTLP converter itself.
+		return p, err
 	default:
 		return nil, err
 	}
@@ -0,0 +1,241 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textparse
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/prometheus/common/model"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/storage/remote/otlptranslator/prometheusremotewrite"
+)
+
+// otlpEntry is a single parser entry produced from a converted OTLP payload.
+// Metadata entries carry only the familyName and meta fields, series and
+// histogram entries carry the remaining sample fields.
+type otlpEntry struct {
+	entry       Entry
+	ls          labels.Labels
+	seriesBytes []byte
+	st          int64
+	t           int64
+	v           float64
+	h           *histogram.Histogram
+	fh          *histogram.FloatHistogram
+	exemplars   []exemplar.Exemplar
+	familyName  []byte
+	meta        metadata.Metadata
+}
+
+// OTLPParser parses an OTLP ExportMetricsServiceRequest payload, encoded as
+// JSON or protobuf, as served by OTel SDK pull exporters. The payload is
+// converted upfront via the otlptranslator path, using the same translation
+// as the default OTLP receiver settings (underscore escaping with suffixes),
+// and the resulting samples are then replayed through the Parser interface.
+//
+// The target_info series is not synthesized because the target identity is
+// already provided by the scrape target labels.
+type OTLPParser struct {
+	entries []otlpEntry
+	i       int
+
+	// exemplarPos tracks how many exemplars of the current entry have been
+	// returned by Exemplar.
+	exemplarPos int
+}
+
+// otlpEntryCollector is a storage.AppenderV2 that records the samples the
+// OTLP converter appends so that OTLPParser can replay them.
+type otlpEntryCollector struct {
+	entries []otlpEntry
+	sb      labels.ScratchBuilder
+	// seenFamilies tracks metric families for which metadata entries have
+	// been emitted already.
+	seenFamilies map[string]struct{}
+}
+
+func (c *otlpEntryCollector) Append(_ storage.SeriesRef, ls labels.Labels, st, t int64, v float64, h *histogram.Histogram, fh *histogram.FloatHistogram, opts storage.AppendV2Options) (storage.SeriesRef, error) {
+	// The converter reuses label and exemplar memory across calls, so take
+	// copies backed by the parser's symbol table.
+	c.sb.Reset()
+	ls.Range(func(l labels.Label) {
+		c.sb.Add(l.Name, l.Value)
+	})
+	ls = c.sb.Labels()
+
+	family := opts.MetricFamilyName
+	if family == "" {
+		family = ls.Get(labels.MetricName)
+	}
+	if _, ok := c.seenFamilies[family]; !ok {
+		c.seenFamilies[family] = struct{}{}
+		familyName := []byte(family)
+		if opts.Metadata.Type != "" && opts.Metadata.Type != model.MetricTypeUnknown {
+			c.entries = append(c.entries, otlpEntry{entry: EntryType, familyName: familyName, meta: opts.Metadata})
+		}
+		if opts.Metadata.Help != "" {
+			c.entries = append(c.entries, otlpEntry{entry: EntryHelp, familyName: familyName, meta: opts.Metadata})
+		}
+		if opts.Metadata.Unit != "" {
+			c.entries = append(c.entries, otlpEntry{entry: EntryUnit, familyName: familyName, meta: opts.Metadata})
+		}
+	}
+
+	e := otlpEntry{
+		entry:       EntrySeries,
+		ls:          ls,
+		seriesBytes: seriesBytesFromLabels(ls),
+		st:          st,
+		t:           t,
+		v:           v,
+		h:           h,
+		fh:          fh,
+		exemplars:   append([]exemplar.Exemplar(nil), opts.Exemplars...),
+	}
+	if h != nil || fh != nil {
+		e.entry = EntryHistogram
+	}
+	c.entries = append(c.entries, e)
+	return 0, nil
+}
+
+func (c *otlpEntryCollector) Commit() error   { return nil }
+func (c *otlpEntryCollector) Rollback() error { return nil }
+
+// seriesBytesFromLabels renders a unique byte representation of a series,
+// in the same format the protobuf parser synthesizes for its entries.
+func seriesBytesFromLabels(ls labels.Labels) []byte {
+	var b bytes.Buffer
+	ls.Range(func(l labels.Label) {
+		if l.Name == labels.MetricName {
+			b.WriteString(l.Value)
+			return
+		}
+		b.WriteByte(model.SeparatorByte)
+		b.WriteString(l.Name)
+		b.WriteByte(model.SeparatorByte)
+		b.WriteString(l.Value)
+	})
+	return b.Bytes()
+}
+
+// NewOTLPParser parses the OTLP payload b, encoded as JSON when json is true
+// and as protobuf otherwise, and returns a parser over the converted samples.
+// A nil parser with an error is returned if the payload cannot be decoded or
+// converted.
+func NewOTLPParser(b []byte, json bool, st *labels.SymbolTable, opts ParserOptions) (Parser, error) {
+	req := pmetricotlp.NewExportRequest()
+	var err error
+	if json {
+		err = req.UnmarshalJSON(b)
+	} else {
+		err = req.UnmarshalProto(b)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding OTLP payload: %w", err)
+	}
+
+	collector := &otlpEntryCollector{
+		sb:           labels.NewScratchBuilderWithSymbolTable(st, 16),
+		seenFamilies: map[string]struct{}{},
+	}
+	converter := prometheusremotewrite.NewPrometheusConverter(collector)
+	// Use the same translation as the default OTLP receiver settings.
+	settings := prometheusremotewrite.Settings{
+		AddMetricSuffixes:       true,
+		DisableTargetInfo:       true,
+		ConvertHistogramsToNHCB: opts.ConvertClassicHistogramsToNHCB,
+		EnableTypeAndUnitLabels: opts.EnableTypeAndUnitLabels,
+	}
+	if _, err := converter.FromMetrics(context.Background(), req.Metrics(), settings); err != nil {
+		return nil, fmt.Errorf("converting OTLP payload: %w", err)
+	}
+	return &OTLPParser{entries: collector.entries, i: -1}, nil
+}
+
+// Series returns the current sample.
+func (p *OTLPParser) Series() ([]byte, *int64, float64) {
+	e := &p.entries[p.i]
+	return e.seriesBytes, &e.t, e.v
+}
+
+// Histogram returns the current native histogram sample.
+func (p *OTLPParser) Histogram() ([]byte, *int64, *histogram.Histogram, *histogram.FloatHistogram) {
+	e := &p.entries[p.i]
+	return e.seriesBytes, &e.t, e.h, e.fh
+}
+
+// Help returns the metric name and help text of the current entry.
+func (p *OTLPParser) Help() ([]byte, []byte) {
+	e := &p.entries[p.i]
+	return e.familyName, []byte(e.meta.Help)
+}
+
+// Type returns the metric name and type of the current entry.
+func (p *OTLPParser) Type() ([]byte, model.MetricType) {
+	e := &p.entries[p.i]
+	return e.familyName, e.meta.Type
+}
+
+// Unit returns the metric name and unit of the current entry.
+func (p *OTLPParser) Unit() ([]byte, []byte) {
+	e := &p.entries[p.i]
+	return e.familyName, []byte(e.meta.Unit)
+}
+
+// Comment always returns nil, OTLP payloads carry no comments.
+func (p *OTLPParser) Comment() []byte {
+	return nil
+}
+
+// Labels writes the labels of the current sample into the passed labels.
+func (p *OTLPParser) Labels(l *labels.Labels) {
+	*l = p.entries[p.i].ls
+}
+
+// Exemplar writes the next exemplar of the current sample into the passed
+// exemplar, returning false once all exemplars are retrieved.
+func (p *OTLPParser) Exemplar(ex *exemplar.Exemplar) bool {
+	e := &p.entries[p.i]
+	if p.exemplarPos >= len(e.exemplars) {
+		return false
+	}
+	*ex = e.exemplars[p.exemplarPos]
+	p.exemplarPos++
+	return true
+}
+
+// StartTimestamp returns the start timestamp of the current sample.
+func (p *OTLPParser) StartTimestamp() int64 {
+	return p.entries[p.i].st
+}
+
+// Next advances the parser to the next entry.
+func (p *OTLPParser) Next() (Entry, error) {
+	p.i++
+	if p.i >= len(p.entries) {
+		return EntryInvalid, io.EOF
+	}
+	p.exemplarPos = 0
+	return p.entries[p.i].entry, nil
+}
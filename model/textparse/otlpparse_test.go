@@ -0,0 +1,116 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textparse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// testOTLPPayload builds an OTLP export request the way an OTel SDK pull
+// exporter would serve it: one gauge and one cumulative monotonic sum.
+func testOTLPPayload() pmetricotlp.ExportRequest {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "svc")
+	sm := rm.ScopeMetrics().AppendEmpty()
+
+	g := sm.Metrics().AppendEmpty()
+	g.SetName("queue.length")
+	g.SetDescription("Current queue length.")
+	gdp := g.SetEmptyGauge().DataPoints().AppendEmpty()
+	gdp.SetDoubleValue(42)
+	gdp.SetTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(1e6)))
+	gdp.Attributes().PutStr("queue", "q1")
+
+	c := sm.Metrics().AppendEmpty()
+	c.SetName("requests")
+	c.SetDescription("Total requests.")
+	sum := c.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	cdp := sum.DataPoints().AppendEmpty()
+	cdp.SetDoubleValue(1337)
+	cdp.SetStartTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(5e5)))
+	cdp.SetTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(1e6)))
+
+	return pmetricotlp.NewExportRequestFromMetrics(md)
+}
+
+func TestOTLPParse(t *testing.T) {
+	req := testOTLPPayload()
+
+	for _, contentType := range []string{
+		"application/vnd.otlp+json",
+		"application/vnd.otlp+proto",
+	} {
+		t.Run(contentType, func(t *testing.T) {
+			var (
+				payload []byte
+				err     error
+			)
+			if contentType == "application/vnd.otlp+json" {
+				payload, err = req.MarshalJSON()
+			} else {
+				payload, err = req.MarshalProto()
+			}
+			require.NoError(t, err)
+
+			p, err := New(payload, contentType, labels.NewSymbolTable(), ParserOptions{})
+			require.NoError(t, err)
+
+			got := testParse(t, p)
+			requireEntries(t, []parsedEntry{
+				{
+					m:   "queue_length",
+					typ: model.MetricTypeGauge,
+				}, {
+					m:    "queue_length",
+					help: "Current queue length.",
+				}, {
+					m:    "queue_length\xffjob\xffsvc\xffqueue\xffq1",
+					v:    42,
+					t:    int64p(1e6),
+					lset: labels.FromStrings("__name__", "queue_length", "job", "svc", "queue", "q1"),
+				}, {
+					m:   "requests_total",
+					typ: model.MetricTypeCounter,
+				}, {
+					m:    "requests_total",
+					help: "Total requests.",
+				}, {
+					m:    "requests_total\xffjob\xffsvc",
+					v:    1337,
+					t:    int64p(1e6),
+					st:   5e5,
+					lset: labels.FromStrings("__name__", "requests_total", "job", "svc"),
+				},
+			}, got)
+		})
+	}
+}
+
+func TestOTLPParseInvalidPayload(t *testing.T) {
+	p, err := New([]byte("{not json"), "application/vnd.otlp+json", labels.NewSymbolTable(), ParserOptions{})
+	require.Error(t, err)
+	require.Nil(t, p)
+}
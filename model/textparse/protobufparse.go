@@ -80,6 +80,10 @@ type ProtobufParser struct {
 	// We need to preload NHCB since we cannot do error handling in Histogram().
 	nhcbH  *histogram.Histogram
 	nhcbFH *histogram.FloatHistogram
+
+	// Optional arena to reuse histogram structs across parses. Nil means
+	// every native histogram is freshly allocated.
+	arena *HistogramArena
 }
 
 // NewProtobufParser returns a parser for the payload in the byte slice.
@@ -87,7 +91,7 @@ func NewProtobufParser(
 	b []byte,
 	ignoreNativeHistograms, parseClassicHistograms, convertClassicHistogramsToNHCB, enableTypeAndUnitLabels bool,
 	st *labels.SymbolTable,
-) Parser {
+) *ProtobufParser {
 	builder := labels.NewScratchBuilderWithSymbolTable(st, 16)
 	builder.SetUnsafeAdd(true)
 	return &ProtobufParser{
@@ -104,6 +108,30 @@ func NewProtobufParser(
 	}
 }
 
+// SetHistogramArena makes the parser obtain all native histograms it returns
+// from the given arena instead of allocating them. See HistogramArena for the
+// lifetime rules this imposes on the returned histograms.
+func (p *ProtobufParser) SetHistogramArena(a *HistogramArena) {
+	p.arena = a
+}
+
+// newHistogram returns a zeroed integer histogram from the arena, or a fresh
+// allocation if no arena is set.
+func (p *ProtobufParser) newHistogram() *histogram.Histogram {
+	if p.arena != nil {
+		return p.arena.histogram()
+	}
+	return &histogram.Histogram{}
+}
+
+// newFloatHistogram is the float counterpart of newHistogram.
+func (p *ProtobufParser) newFloatHistogram() *histogram.FloatHistogram {
+	if p.arena != nil {
+		return p.arena.floatHistogram()
+	}
+	return &histogram.FloatHistogram{}
+}
+
 // Series returns the bytes of a series with a simple float64 as a
 // value, the timestamp if set, and the value of the current sample.
 func (p *ProtobufParser) Series() ([]byte, *int64, float64) {
@@ -205,80 +233,71 @@ func (p *ProtobufParser) Histogram() ([]byte, *int64, *histogram.Histogram, *his
 	}
 	if h.GetSampleCountFloat() > 0 || h.GetZeroCountFloat() > 0 {
 		// It is a float histogram.
-		fh := histogram.FloatHistogram{
-			Count:         h.GetSampleCountFloat(),
-			Sum:           h.GetSampleSum(),
-			ZeroThreshold: h.GetZeroThreshold(),
-			ZeroCount:     h.GetZeroCountFloat(),
-			Schema:        h.GetSchema(),
-
-			// Decoder reuses slices, so we need to copy.
-			PositiveSpans:   make([]histogram.Span, len(h.GetPositiveSpan())),
-			PositiveBuckets: make([]float64, len(h.GetPositiveCount())),
-			NegativeSpans:   make([]histogram.Span, len(h.GetNegativeSpan())),
-			NegativeBuckets: make([]float64, len(h.GetNegativeCount())),
-		}
+		fh := p.newFloatHistogram()
+		fh.Count = h.GetSampleCountFloat()
+		fh.Sum = h.GetSampleSum()
+		fh.ZeroThreshold = h.GetZeroThreshold()
+		fh.ZeroCount = h.GetZeroCountFloat()
+		fh.Schema = h.GetSchema()
+
+		// Decoder reuses slices, so we need to copy.
+		fh.PositiveSpans = resizeSpans(fh.PositiveSpans, len(h.GetPositiveSpan()))
+		fh.PositiveBuckets = resizeFloats(fh.PositiveBuckets, len(h.GetPositiveCount()))
+		fh.NegativeSpans = resizeSpans(fh.NegativeSpans, len(h.GetNegativeSpan()))
+		fh.NegativeBuckets = resizeFloats(fh.NegativeBuckets, len(h.GetNegativeCount()))
 		for i, span := range h.GetPositiveSpan() {
 			fh.PositiveSpans[i].Offset = span.GetOffset()
 			fh.PositiveSpans[i].Length = span.GetLength()
 		}
-		for i, cnt := range h.GetPositiveCount() {
-			fh.PositiveBuckets[i] = cnt
-		}
+		copy(fh.PositiveBuckets, h.GetPositiveCount())
 		for i, span := range h.GetNegativeSpan() {
 			fh.NegativeSpans[i].Offset = span.GetOffset()
 			fh.NegativeSpans[i].Length = span.GetLength()
 		}
-		for i, cnt := range h.GetNegativeCount() {
-			fh.NegativeBuckets[i] = cnt
-		}
+		copy(fh.NegativeBuckets, h.GetNegativeCount())
 		if p.dec.GetType() == dto.MetricType_GAUGE_HISTOGRAM {
 			fh.CounterResetHint = histogram.GaugeType
 		}
 		fh.Compact(0)
 		if *ts != 0 {
-			return p.entryBytes.Bytes(), ts, nil, &fh
+			return p.entryBytes.Bytes(), ts, nil, fh
 		}
 		// Nasty hack: Assume that ts==0 means no timestamp. That's not true in
 		// general, but proto3 has no distinction between unset and
 		// default. Need to avoid in the final format.
-		return p.entryBytes.Bytes(), nil, nil, &fh
+		return p.entryBytes.Bytes(), nil, nil, fh
 	}
 
-	// TODO(bwplotka): Create sync.Pool for those structs.
-	sh := histogram.Histogram{
-		Count:           h.GetSampleCount(),
-		Sum:             h.GetSampleSum(),
-		ZeroThreshold:   h.GetZeroThreshold(),
-		ZeroCount:       h.GetZeroCount(),
-		Schema:          h.GetSchema(),
-		PositiveSpans:   make([]histogram.Span, len(h.GetPositiveSpan())),
-		PositiveBuckets: make([]int64, len(h.GetPositiveDelta())),
-		NegativeSpans:   make([]histogram.Span, len(h.GetNegativeSpan())),
-		NegativeBuckets: make([]int64, len(h.GetNegativeDelta())),
-	}
+	sh := p.newHistogram()
+	sh.Count = h.GetSampleCount()
+	sh.Sum = h.GetSampleSum()
+	sh.ZeroThreshold = h.GetZeroThreshold()
+	sh.ZeroCount = h.GetZeroCount()
+	sh.Schema = h.GetSchema()
+
+	// Decoder reuses slices, so we need to copy.
+	sh.PositiveSpans = resizeSpans(sh.PositiveSpans, len(h.GetPositiveSpan()))
+	sh.PositiveBuckets = resizeInts(sh.PositiveBuckets, len(h.GetPositiveDelta()))
+	sh.NegativeSpans = resizeSpans(sh.NegativeSpans, len(h.GetNegativeSpan()))
+	sh.NegativeBuckets = resizeInts(sh.NegativeBuckets, len(h.GetNegativeDelta()))
 	for i, span := range h.GetPositiveSpan() {
 		sh.PositiveSpans[i].Offset = span.GetOffset()
 		sh.PositiveSpans[i].Length = span.GetLength()
 	}
-	for i, cnt := range h.GetPositiveDelta() {
-		sh.PositiveBuckets[i] = cnt
-	}
+	copy(sh.PositiveBuckets, h.GetPositiveDelta())
 	for i, span := range h.GetNegativeSpan() {
 		sh.NegativeSpans[i].Offset = span.GetOffset()
 		sh.NegativeSpans[i].Length = span.GetLength()
 	}
-	for i, cnt := range h.GetNegativeDelta() {
-		sh.NegativeBuckets[i] = cnt
-	}
+	copy(sh.NegativeBuckets, h.GetNegativeDelta())
 	if p.dec.GetType() == dto.MetricType_GAUGE_HISTOGRAM {
 		sh.CounterResetHint = histogram.GaugeType
 	}
 	sh.Compact(0)
 	if *ts != 0 {
-		return p.entryBytes.Bytes(), ts, &sh, nil
+		return p.entryBytes.Bytes(), ts, sh, nil
 	}
-	return p.entryBytes.Bytes(), nil, &sh, nil
+	return p.entryBytes.Bytes(), nil, sh, nil
 }
 
 // Help returns the metric name and help text in the current entry.
@@ -4130,6 +4130,29 @@ func TestProtobufParse(t *testing.T) {
 	}
 }
 
+// TestProtobufParseWithHistogramArena ensures that a parser backed by a
+// HistogramArena produces the same results as a plain parser, including when
+// the arena is reused across parses of the same payload, as scrape loops do
+// across scrapes of the same target.
+func TestProtobufParseWithHistogramArena(t *testing.T) {
+	inputBuf := createTestProtoBuf(t)
+	newParser := func(st *labels.SymbolTable) *ProtobufParser {
+		return NewProtobufParser(inputBuf.Bytes(), false, true, false, false, st)
+	}
+
+	exp := testParse(t, newParser(labels.NewSymbolTable()))
+
+	arena := NewHistogramArena()
+	for i := 0; i < 3; i++ {
+		arena.Reset()
+		p := newParser(labels.NewSymbolTable())
+		p.SetHistogramArena(arena)
+		// Entries must be compared before the next Reset invalidates
+		// the histograms handed out by the arena.
+		requireEntries(t, exp, testParse(t, p))
+	}
+}
+
 // TestProtobufParseWithNHCB is only concerned with classic histograms.
 func TestProtobufParseWithNHCB(t *testing.T) {
 	testMetricFamilies := []string{
@@ -0,0 +1,196 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifier
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// haProbeInterval is how often HA peers are health checked.
+	haProbeInterval = 5 * time.Second
+	// haProbeTimeout bounds a single health check request.
+	haProbeTimeout = 2 * time.Second
+)
+
+// haRing deduplicates alert notifications between the replicas of an HA
+// Prometheus pair. Every replica is configured with the same static peer
+// list; each alert is assigned to exactly one healthy peer via rendezvous
+// hashing over its labels, and a replica only sends the alerts assigned to
+// itself. Peers are health checked in the background, so the alerts owned by
+// a failed replica are taken over by the remaining ones within a probe
+// interval. Replicas whose view of peer health temporarily diverges may send
+// duplicates, which Alertmanager deduplicates just like today; disagreement
+// can only ever cause an alert to be sent more than once, not to be lost.
+type haRing struct {
+	self    string
+	peers   []string
+	client  *http.Client
+	logger  *slog.Logger
+	metrics *alertMetrics
+
+	// down marks peers whose last health check failed. Guarded by mtx.
+	mtx  sync.RWMutex
+	down map[string]bool
+
+	stopOnce sync.Once
+	stopc    chan struct{}
+	done     chan struct{}
+}
+
+func newHARing(self string, peers []string, logger *slog.Logger) (*haRing, error) {
+	peers = slices.Clone(peers)
+	slices.Sort(peers)
+	peers = slices.Compact(peers)
+	if !slices.Contains(peers, self) {
+		return nil, fmt.Errorf("HA peer list %v does not contain this replica's URL %q", peers, self)
+	}
+	return &haRing{
+		self:   self,
+		peers:  peers,
+		client: &http.Client{Timeout: haProbeTimeout},
+		logger: logger,
+		down:   map[string]bool{},
+		stopc:  make(chan struct{}),
+		done:   make(chan struct{}),
+	}, nil
+}
+
+// run health checks the peers until stop is called.
+func (r *haRing) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(haProbeInterval)
+	defer ticker.Stop()
+
+	r.probePeers()
+	for {
+		select {
+		case <-r.stopc:
+			return
+		case <-ticker.C:
+			r.probePeers()
+		}
+	}
+}
+
+// stop terminates the health checking and waits for run to return.
+func (r *haRing) stop() {
+	r.stopOnce.Do(func() { close(r.stopc) })
+	<-r.done
+}
+
+func (r *haRing) probePeers() {
+	alive := 1 // This replica always counts itself as alive.
+	for _, peer := range r.peers {
+		if peer == r.self {
+			continue
+		}
+		err := r.probe(peer)
+
+		r.mtx.Lock()
+		wasDown := r.down[peer]
+		if err != nil {
+			r.down[peer] = true
+		} else {
+			delete(r.down, peer)
+			alive++
+		}
+		r.mtx.Unlock()
+
+		switch {
+		case err != nil && !wasDown:
+			r.logger.Warn("HA peer became unhealthy, taking over its alerts", "peer", peer, "err", err)
+		case err == nil && wasDown:
+			r.logger.Info("HA peer is healthy again, handing its alerts back", "peer", peer)
+		}
+	}
+	r.metrics.haAlivePeers.Set(float64(alive))
+}
+
+func (r *haRing) probe(peer string) error {
+	req, err := http.NewRequest(http.MethodGet, peer+"/-/healthy", http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned HTTP status %s", resp.Status)
+	}
+	return nil
+}
+
+// filter returns the subset of alerts this replica is responsible for
+// sending, counting the suppressed remainder.
+func (r *haRing) filter(alerts []*Alert) []*Alert {
+	kept := alerts[:0]
+	for _, a := range alerts {
+		if r.owns(a) {
+			kept = append(kept, a)
+		}
+	}
+	if suppressed := len(alerts) - len(kept); suppressed > 0 {
+		r.metrics.haSuppressed.Add(float64(suppressed))
+	}
+	return kept
+}
+
+// owns reports whether this replica is responsible for sending the alert.
+// The owner is the healthy peer with the highest rendezvous score for the
+// alert's label hash, so each alert has exactly one owner at a time and a
+// peer failure only moves ownership of the alerts that peer owned.
+func (r *haRing) owns(a *Alert) bool {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	var (
+		owner string
+		best  uint64
+	)
+	for _, peer := range r.peers {
+		if peer != r.self && r.down[peer] {
+			continue
+		}
+		if score := rendezvousScore(peer, a.Hash()); owner == "" || score > best {
+			owner, best = peer, score
+		}
+	}
+	return owner == r.self
+}
+
+func rendezvousScore(peer string, alertHash uint64) uint64 {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], alertHash)
+	h := fnv.New64a()
+	h.Write([]byte(peer))
+	h.Write(buf[:])
+	return h.Sum64()
+}
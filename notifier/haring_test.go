@@ -0,0 +1,124 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	prom_testutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/promslog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+func newTestHARing(t *testing.T, self string, peers []string) *haRing {
+	t.Helper()
+
+	r, err := newHARing(self, peers, promslog.NewNopLogger())
+	require.NoError(t, err)
+	r.metrics = newAlertMetrics(prometheus.NewRegistry(), func() float64 { return 0 })
+	return r
+}
+
+func TestHARingOwnership(t *testing.T) {
+	peers := []string{"http://prom-0:9090", "http://prom-1:9090"}
+	r0 := newTestHARing(t, peers[0], peers)
+	r1 := newTestHARing(t, peers[1], peers)
+
+	alerts := make([]*Alert, 0, 100)
+	for i := range cap(alerts) {
+		alerts = append(alerts, &Alert{Labels: labels.FromStrings(labels.AlertName, "Alert"+strconv.Itoa(i))})
+	}
+
+	// With both replicas healthy, every alert has exactly one owner and the
+	// work is spread over both replicas.
+	var own0, own1 int
+	for _, a := range alerts {
+		o0, o1 := r0.owns(a), r1.owns(a)
+		require.NotEqual(t, o0, o1, "alert %v must have exactly one owner", a)
+		if o0 {
+			own0++
+		} else {
+			own1++
+		}
+	}
+	require.Positive(t, own0)
+	require.Positive(t, own1)
+
+	// filter keeps the owned alerts and counts the suppressed remainder.
+	sent := r0.filter(slices.Clone(alerts))
+	require.Len(t, sent, own0)
+	require.Equal(t, float64(own1), prom_testutil.ToFloat64(r0.metrics.haSuppressed))
+
+	// When the peer fails, this replica takes over all its alerts.
+	r0.mtx.Lock()
+	r0.down[peers[1]] = true
+	r0.mtx.Unlock()
+	for _, a := range alerts {
+		require.True(t, r0.owns(a))
+	}
+}
+
+func TestHARingProbePeers(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/-/healthy" || !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer peer.Close()
+
+	self := "http://prom-0:9090"
+	r := newTestHARing(t, self, []string{self, peer.URL})
+
+	r.probePeers()
+	require.Equal(t, 2.0, prom_testutil.ToFloat64(r.metrics.haAlivePeers))
+
+	// A failing health check marks the peer down within one probe.
+	healthy.Store(false)
+	r.probePeers()
+	require.Equal(t, 1.0, prom_testutil.ToFloat64(r.metrics.haAlivePeers))
+	r.mtx.RLock()
+	require.True(t, r.down[peer.URL])
+	r.mtx.RUnlock()
+
+	// A recovered peer gets its alerts handed back.
+	healthy.Store(true)
+	r.probePeers()
+	require.Equal(t, 2.0, prom_testutil.ToFloat64(r.metrics.haAlivePeers))
+	r.mtx.RLock()
+	require.False(t, r.down[peer.URL])
+	r.mtx.RUnlock()
+}
+
+func TestNewManagerHARing(t *testing.T) {
+	peers := []string{"http://prom-0:9090", "http://prom-1:9090"}
+
+	h := NewManager(&Options{HAURL: peers[0], HAPeerURLs: peers}, model.UTF8Validation, nil)
+	require.NotNil(t, h.haRing)
+
+	// A peer list not containing this replica disables deduplication, which
+	// is the safe direction: duplicates instead of lost alerts.
+	h = NewManager(&Options{HAURL: "http://prom-2:9090", HAPeerURLs: peers}, model.UTF8Validation, nil)
+	require.Nil(t, h.haRing)
+}
@@ -61,6 +61,7 @@ type Manager struct {
 	stopRequested chan struct{}
 
 	alertmanagers map[string]*alertmanagerSet
+	haRing        *haRing
 	logger        *slog.Logger
 }
 
@@ -77,6 +78,14 @@ type Options struct {
 
 	// MaxBatchSize determines the maximum number of alerts to send in a single request to the alertmanager.
 	MaxBatchSize int
+
+	// HAURL and HAPeerURLs enable alert send deduplication between the
+	// replicas of an HA Prometheus pair. HAPeerURLs is the static list of
+	// base URLs of all replicas, identical on each of them, and HAURL is the
+	// entry identifying this replica. An empty peer list disables
+	// deduplication.
+	HAURL      string
+	HAPeerURLs []string
 }
 
 func do(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
@@ -119,6 +128,18 @@ func NewManager(o *Options, nameValidationScheme model.ValidationScheme, logger
 	n.metrics = newAlertMetrics(o.Registerer, alertmanagersDiscoveredFunc)
 	n.metrics.queueCapacity.Set(float64(o.QueueCapacity))
 
+	if len(o.HAPeerURLs) > 0 {
+		ring, err := newHARing(o.HAURL, o.HAPeerURLs, logger)
+		if err != nil {
+			// Sending duplicates is the safe direction, so a broken HA peer
+			// configuration disables deduplication instead of losing alerts.
+			logger.Error("Invalid HA peer configuration, alert send deduplication disabled", "err", err)
+		} else {
+			ring.metrics = n.metrics
+			n.haRing = ring
+		}
+	}
+
 	return n
 }
 
@@ -203,6 +224,10 @@ func (n *Manager) ApplyConfig(conf *config.Config) error {
 // Dispatching of notifications occurs in parallel to processing target updates to avoid one starving the other.
 // Refer to https://github.com/prometheus/prometheus/issues/13676 for more details.
 func (n *Manager) Run(tsets <-chan map[string][]*targetgroup.Group) {
+	if n.haRing != nil {
+		go n.haRing.run()
+		defer n.haRing.stop()
+	}
 	n.targetUpdateLoop(tsets)
 	// The target update loop also returns when tsets is closed, so wait for Stop before
 	// cleaning up the send loops. This keeps callers in control of shutdown ordering, so
@@ -272,6 +297,12 @@ func (n *Manager) Send(alerts ...*Alert) {
 		return
 	}
 
+	if n.haRing != nil {
+		if alerts = n.haRing.filter(alerts); len(alerts) == 0 {
+			return
+		}
+	}
+
 	for _, ams := range n.alertmanagers {
 		ams.send(alerts...)
 	}
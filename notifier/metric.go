@@ -28,6 +28,8 @@ type alertMetrics struct {
 	queueLength             *prometheus.GaugeVec
 	queueCapacity           prometheus.Gauge
 	alertmanagersDiscovered prometheus.GaugeFunc
+	haSuppressed            prometheus.Counter
+	haAlivePeers            prometheus.Gauge
 }
 
 func newAlertMetrics(r prometheus.Registerer, alertmanagersDiscovered func() float64) *alertMetrics {
@@ -92,6 +94,18 @@ func newAlertMetrics(r prometheus.Registerer, alertmanagersDiscovered func() flo
 			Name: "prometheus_notifications_alertmanagers_discovered",
 			Help: "The number of alertmanagers discovered and active.",
 		}, alertmanagersDiscovered),
+		haSuppressed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "ha_suppressed_total",
+			Help:      "Total number of alerts not sent because another HA replica owns them.",
+		}),
+		haAlivePeers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "ha_alive_peers",
+			Help:      "Number of HA replicas, including this one, currently considered healthy.",
+		}),
 	}
 
 	if r != nil {
@@ -104,6 +118,8 @@ func newAlertMetrics(r prometheus.Registerer, alertmanagersDiscovered func() flo
 			m.queueLength,
 			m.queueCapacity,
 			m.alertmanagersDiscovered,
+			m.haSuppressed,
+			m.haAlivePeers,
 		)
 	}
 
@@ -301,10 +301,22 @@ func (m *Query) GetHints() *ReadHints {
 
 type QueryResult struct {
 	// Samples within a time series must be ordered by time.
-	Timeseries           []*TimeSeries `protobuf:"bytes,1,rep,name=timeseries,proto3" json:"timeseries,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	Timeseries []*TimeSeries `protobuf:"bytes,1,rep,name=timeseries,proto3" json:"timeseries,omitempty"`
+	// hints_applied indicates that the server already applied the function from
+	// Query.hints to the returned data, i.e. the samples are final results of
+	// that function rather than raw series. Clients that sent hints may then
+	// skip re-computing it. The function must be applied per series: servers
+	// must not collapse series by the hinted grouping, as re-applying an
+	// aggregation on the client is not idempotent for all operations (e.g.
+	// count, avg, stddev); grouping hints remain an optimization aid only.
+	// Clients drop the flag when the response is merged with series from other
+	// sources, so servers should only set it when they are the sole source for
+	// the selected series. Servers that only use hints as an optimization aid
+	// must leave this unset.
+	HintsApplied         bool     `protobuf:"varint,2,opt,name=hints_applied,json=hintsApplied,proto3" json:"hints_applied,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *QueryResult) Reset()         { *m = QueryResult{} }
@@ -347,6 +359,13 @@ func (m *QueryResult) GetTimeseries() []*TimeSeries {
 	return nil
 }
 
+func (m *QueryResult) GetHintsApplied() bool {
+	if m != nil {
+		return m.HintsApplied
+	}
+	return false
+}
+
 // ChunkedReadResponse is a response when response_type equals STREAMED_XOR_CHUNKS.
 // We strictly stream full series after series, optionally split by time. This means that a single frame can contain
 // partition of the single series, but once a new series is started to be streamed it means that no more chunks will
@@ -354,7 +373,10 @@ func (m *QueryResult) GetTimeseries() []*TimeSeries {
 type ChunkedReadResponse struct {
 	ChunkedSeries []*ChunkedSeries `protobuf:"bytes,1,rep,name=chunked_series,json=chunkedSeries,proto3" json:"chunked_series,omitempty"`
 	// query_index represents an index of the query from ReadRequest.queries these chunks relates to.
-	QueryIndex           int64    `protobuf:"varint,2,opt,name=query_index,json=queryIndex,proto3" json:"query_index,omitempty"`
+	QueryIndex int64 `protobuf:"varint,2,opt,name=query_index,json=queryIndex,proto3" json:"query_index,omitempty"`
+	// hints_applied carries the same meaning as QueryResult.hints_applied and
+	// must be consistent across all frames of one query.
+	HintsApplied         bool     `protobuf:"varint,3,opt,name=hints_applied,json=hintsApplied,proto3" json:"hints_applied,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -407,6 +429,13 @@ func (m *ChunkedReadResponse) GetQueryIndex() int64 {
 	return 0
 }
 
+func (m *ChunkedReadResponse) GetHintsApplied() bool {
+	if m != nil {
+		return m.HintsApplied
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterEnum("prometheus.ReadRequest_ResponseType", ReadRequest_ResponseType_name, ReadRequest_ResponseType_value)
 	proto.RegisterType((*WriteRequest)(nil), "prometheus.WriteRequest")
@@ -696,6 +725,16 @@ func (m *QueryResult) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.HintsApplied {
+		i--
+		if m.HintsApplied {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
 	if len(m.Timeseries) > 0 {
 		for iNdEx := len(m.Timeseries) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -737,6 +776,16 @@ func (m *ChunkedReadResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.HintsApplied {
+		i--
+		if m.HintsApplied {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
 	if m.QueryIndex != 0 {
 		i = encodeVarintRemote(dAtA, i, uint64(m.QueryIndex))
 		i--
@@ -877,6 +926,9 @@ func (m *QueryResult) Size() (n int) {
 			n += 1 + l + sovRemote(uint64(l))
 		}
 	}
+	if m.HintsApplied {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -898,6 +950,9 @@ func (m *ChunkedReadResponse) Size() (n int) {
 	if m.QueryIndex != 0 {
 		n += 1 + sovRemote(uint64(m.QueryIndex))
 	}
+	if m.HintsApplied {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1490,6 +1545,26 @@ func (m *QueryResult) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HintsApplied", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.HintsApplied = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRemote(dAtA[iNdEx:])
@@ -1594,6 +1669,26 @@ func (m *ChunkedReadResponse) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HintsApplied", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRemote
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.HintsApplied = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRemote(dAtA[iNdEx:])
@@ -199,6 +199,10 @@ type query struct {
 	stats *stats.QueryTimers
 	// Sample stats for the query execution.
 	sampleStats *stats.QuerySamples
+	// Per-operation stats for the query execution.
+	operationStats *stats.QueryOperations
+	// Subexpression reuse stats for the query execution.
+	subexpressionStats *stats.QuerySubexpressions
 	// Result matrix for reuse.
 	matrix Matrix
 	// Cancellation function for the query.
@@ -225,8 +229,10 @@ func (q *query) String() string {
 // Stats implements the Query interface.
 func (q *query) Stats() *stats.Statistics {
 	return &stats.Statistics{
-		Timers:  q.stats,
-		Samples: q.sampleStats,
+		Timers:         q.stats,
+		Samples:        q.sampleStats,
+		Operations:     q.operationStats,
+		Subexpressions: q.subexpressionStats,
 	}
 }
 
@@ -601,12 +607,14 @@ func (ng *Engine) newQuery(q storage.Queryable, qs string, opts QueryOpts, start
 		LookbackDelta: lookbackDelta,
 	}
 	qry := &query{
-		q:           qs,
-		stmt:        es,
-		ng:          ng,
-		stats:       stats.NewQueryTimers(),
-		sampleStats: stats.NewQuerySamples(ng.enablePerStepStats && opts.EnablePerStepStats()),
-		queryable:   q,
+		q:                  qs,
+		stmt:               es,
+		ng:                 ng,
+		stats:              stats.NewQueryTimers(),
+		sampleStats:        stats.NewQuerySamples(ng.enablePerStepStats && opts.EnablePerStepStats()),
+		operationStats:     stats.NewQueryOperations(),
+		subexpressionStats: stats.NewQuerySubexpressions(),
+		queryable:          q,
 	}
 	return &es.Expr, qry
 }
@@ -670,11 +678,13 @@ func (ng *Engine) validateOpts(expr parser.Expr) error {
 // NewTestQuery injects special behaviour into Query for testing.
 func (ng *Engine) NewTestQuery(f func(context.Context) error) Query {
 	qry := &query{
-		q:           "test statement",
-		stmt:        parser.TestStmt(f),
-		ng:          ng,
-		stats:       stats.NewQueryTimers(),
-		sampleStats: stats.NewQuerySamples(ng.enablePerStepStats),
+		q:                  "test statement",
+		stmt:               parser.TestStmt(f),
+		ng:                 ng,
+		stats:              stats.NewQueryTimers(),
+		sampleStats:        stats.NewQuerySamples(ng.enablePerStepStats),
+		operationStats:     stats.NewQueryOperations(),
+		subexpressionStats: stats.NewQuerySubexpressions(),
 	}
 	return qry
 }
@@ -812,11 +822,15 @@ func (ng *Engine) execEvalStmt(ctx context.Context, query *query, s *parser.Eval
 			logger:                   ng.logger,
 			lookbackDelta:            s.LookbackDelta,
 			samplesStats:             query.sampleStats,
+			operationStats:           query.operationStats,
+			subexpressionStats:       query.subexpressionStats,
 			noStepSubqueryIntervalFn: ng.noStepSubqueryIntervalFn,
 			enableDelayedNameRemoval: ng.enableDelayedNameRemoval,
 			enableTypeAndUnitLabels:  ng.enableTypeAndUnitLabels,
 			useStartTimestamps:       ng.useStartTimestamps,
 			querier:                  querier,
+			commonSubexpressions:     findCommonSubexpressions(s.Expr),
+			subqueryCache:            subqueryCacheFromContext(ctx),
 		}
 		query.sampleStats.InitStepTracking(start, start, 1)
 
@@ -873,11 +887,15 @@ func (ng *Engine) execEvalStmt(ctx context.Context, query *query, s *parser.Eval
 		logger:                   ng.logger,
 		lookbackDelta:            s.LookbackDelta,
 		samplesStats:             query.sampleStats,
+		operationStats:           query.operationStats,
+		subexpressionStats:       query.subexpressionStats,
 		noStepSubqueryIntervalFn: ng.noStepSubqueryIntervalFn,
 		enableDelayedNameRemoval: ng.enableDelayedNameRemoval,
 		enableTypeAndUnitLabels:  ng.enableTypeAndUnitLabels,
 		useStartTimestamps:       ng.useStartTimestamps,
 		querier:                  querier,
+		commonSubexpressions:     findCommonSubexpressions(s.Expr),
+		subqueryCache:            subqueryCacheFromContext(ctx),
 	}
 	query.sampleStats.InitStepTracking(evaluator.startTimestamp, evaluator.endTimestamp, evaluator.interval)
 	val, warnings, err := evaluator.Eval(ctxInnerEval, s.Expr)
@@ -995,12 +1013,18 @@ func getTimeRangesForSelector(s *parser.EvalStmt, n *parser.VectorSelector, path
 	}
 
 	if evalRange == 0 {
+		// A lookback modifier on the selector overrides the query's
+		// lookback delta.
+		lookbackDelta := s.LookbackDelta
+		if n.Lookback > 0 {
+			lookbackDelta = n.Lookback
+		}
 		// Reduce the start by one fewer ms than the lookback delta
 		// because wo want to exclude samples that are precisely the
 		// lookback delta before the eval time.
-		start -= durationMilliseconds(s.LookbackDelta) - 1
+		start -= durationMilliseconds(lookbackDelta) - 1
 		if n.Smoothed {
-			end += durationMilliseconds(s.LookbackDelta)
+			end += durationMilliseconds(lookbackDelta)
 		}
 	} else {
 		// For matrix queries, adjust the start and end times to ensure the
@@ -1121,6 +1145,11 @@ func checkAndExpandSeriesSet(ctx context.Context, expr parser.Expr) (annotations
 				series[i] = newHistogramStatsSeries(series[i])
 			}
 		}
+		// The flag must be read after the set has been fully expanded, as
+		// streamed remote read implementations only learn it from the response.
+		if hs, ok := e.UnexpandedSeriesSet.(storage.HintsAppliedSeriesSet); ok && hs.HintsApplied() {
+			e.HintsApplied = true
+		}
 		e.Series = series
 		span.AddEvent("expand end", trace.WithAttributes(attribute.Int("num_series", len(series))))
 		return ws, err
@@ -1161,11 +1190,85 @@ type evaluator struct {
 	logger                   *slog.Logger
 	lookbackDelta            time.Duration
 	samplesStats             *stats.QuerySamples
+	operationStats           *stats.QueryOperations
+	subexpressionStats       *stats.QuerySubexpressions
 	noStepSubqueryIntervalFn func(rangeMillis int64) int64
 	enableDelayedNameRemoval bool
 	enableTypeAndUnitLabels  bool
 	useStartTimestamps       bool
 	querier                  storage.Querier
+
+	// commonSubexpressions maps the string form of subexpressions occurring
+	// more than once in the query to their cached result, so repeated
+	// occurrences are evaluated only once. Nil when the query has no
+	// repeated subexpressions.
+	commonSubexpressions map[string]*cachedSubexpression
+
+	// subqueryCache, when non-nil, shares materialized subquery results
+	// across the queries of a rule group evaluation cycle. It comes from
+	// the query context, see NewSubqueryCacheContext.
+	subqueryCache *SubqueryCache
+}
+
+// cachedSubexpression holds the result of a subexpression that occurs more
+// than once in a query, so further occurrences can reuse it. The stored
+// value is a private copy; consumers are handed copies of it.
+type cachedSubexpression struct {
+	value parser.Value
+	ws    annotations.Annotations
+}
+
+// SubqueryCache caches materialized subquery results so that queries sharing
+// a context can reuse them, e.g. the rules of a group that repeat the same
+// subquery in burn-rate variants. The cache key includes the subquery's
+// evaluation time grid, so only queries evaluated at identical timestamps
+// share results; attach a fresh cache per evaluation cycle. It is safe for
+// concurrent use.
+type SubqueryCache struct {
+	mtx     sync.Mutex
+	entries map[string]*cachedSubexpression
+}
+
+// NewSubqueryCache returns an empty SubqueryCache.
+func NewSubqueryCache() *SubqueryCache {
+	return &SubqueryCache{entries: map[string]*cachedSubexpression{}}
+}
+
+// get returns a private copy of the cached result for key, if present.
+func (c *SubqueryCache) get(key string) (Matrix, annotations.Annotations, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, nil, false
+	}
+	return copyValue(entry.value).(Matrix), entry.ws, true
+}
+
+// put stores a result for key. The caller hands over ownership of mat, which
+// must not be mutated or returned to the point slice pools afterwards.
+func (c *SubqueryCache) put(key string, mat Matrix, ws annotations.Annotations) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.entries[key] = &cachedSubexpression{value: mat, ws: ws}
+}
+
+// subqueryCacheContextKey is the context key NewSubqueryCacheContext stores
+// the cache under.
+type subqueryCacheContextKey struct{}
+
+// NewSubqueryCacheContext returns a new context with the given subquery cache
+// attached. Queries executed with the returned context share subquery results
+// that are evaluated on identical time grids.
+func NewSubqueryCacheContext(ctx context.Context, cache *SubqueryCache) context.Context {
+	return context.WithValue(ctx, subqueryCacheContextKey{}, cache)
+}
+
+// subqueryCacheFromContext returns the subquery cache attached to ctx, or nil
+// if there is none.
+func subqueryCacheFromContext(ctx context.Context) *SubqueryCache {
+	cache, _ := ctx.Value(subqueryCacheContextKey{}).(*SubqueryCache)
+	return cache
 }
 
 // errorf causes a panic with the input formatted into an error.
@@ -1727,14 +1830,14 @@ func (ev *evaluator) rangeEvalAgg(ctx context.Context, aggExpr *parser.Aggregate
 
 // smoothSeries is a helper function that smooths the series by interpolating the values
 // based on values before and after the timestamp.
-func (ev *evaluator) smoothSeries(series []storage.Series, offset time.Duration, pos posrange.PositionRange) (Matrix, annotations.Annotations) {
+func (ev *evaluator) smoothSeries(series []storage.Series, offset, lookbackDelta time.Duration, pos posrange.PositionRange) (Matrix, annotations.Annotations) {
 	dur := ev.endTimestamp - ev.startTimestamp
 
-	it := storage.NewBuffer(dur + 2*durationMilliseconds(ev.lookbackDelta))
+	it := storage.NewBuffer(dur + 2*durationMilliseconds(lookbackDelta))
 
 	offMS := offset.Milliseconds()
 	step := ev.interval
-	lb := durationMilliseconds(ev.lookbackDelta)
+	lb := durationMilliseconds(lookbackDelta)
 
 	var chkIter chunkenc.Iterator
 	mat := make(Matrix, 0, len(series))
@@ -1843,12 +1946,12 @@ func (ev *evaluator) smoothSeries(series []storage.Series, offset time.Duration,
 // For every storage.Series iterator in series, the method iterates in ev.interval sized steps from ev.startTimestamp until and including ev.endTimestamp,
 // collecting every corresponding sample (obtained via ev.vectorSelectorSingle) into a Series.
 // All of the generated Series are collected into a Matrix, that gets returned.
-func (ev *evaluator) evalSeries(ctx context.Context, series []storage.Series, offset time.Duration, recordOrigT bool) Matrix {
+func (ev *evaluator) evalSeries(ctx context.Context, series []storage.Series, offset, lookbackDelta time.Duration, recordOrigT bool) Matrix {
 	numSteps := int((ev.endTimestamp-ev.startTimestamp)/ev.interval) + 1
 
 	mat := make(Matrix, 0, len(series))
 	var prevSS *Series
-	it := storage.NewMemoizedEmptyIterator(durationMilliseconds(ev.lookbackDelta))
+	it := storage.NewMemoizedEmptyIterator(durationMilliseconds(lookbackDelta))
 	var chkIter chunkenc.Iterator
 	for _, s := range series {
 		if err := contextDone(ctx, "expression evaluation"); err != nil {
@@ -1863,7 +1966,7 @@ func (ev *evaluator) evalSeries(ctx context.Context, series []storage.Series, of
 
 		for ts, step := ev.startTimestamp, -1; ts <= ev.endTimestamp; ts += ev.interval {
 			step++
-			_, origT, f, h, ok := ev.vectorSelectorSingle(it, offset, ts)
+			_, origT, f, h, ok := ev.vectorSelectorSingle(it, offset, lookbackDelta, ts)
 			if !ok {
 				continue
 			}
@@ -1954,6 +2057,28 @@ func (ev *evaluator) runSubquery(ctx context.Context, e *parser.SubqueryExpr) (p
 		subqStart += subqInterval
 	}
 
+	// A result cached by an earlier query of the same evaluation cycle can
+	// be reused as long as the subquery evaluates on the identical time
+	// grid; the grid is derived from the parent timestamps, so it pins the
+	// evaluation timestamp into the key.
+	var cacheKey string
+	if ev.subqueryCache != nil {
+		cacheKey = fmt.Sprintf("%s\xff%d\xff%d\xff%d\xff%d", e.String(), subqStart, subqEnd, subqInterval, ev.lookbackDelta)
+		if mat, ws, ok := ev.subqueryCache.get(cacheKey); ok {
+			// Account for the reused samples as if the subquery had been
+			// evaluated again, so sample limits apply consistently. The
+			// callers release them when done with the matrix.
+			ev.currentSamples += mat.TotalSamples()
+			if ev.currentSamples > ev.maxSamples {
+				ev.error(ErrTooManySamples(env))
+			}
+			ev.samplesStats.UpdatePeak(ev.currentSamples)
+			// The cached matrix was materialized by another query, so this
+			// one reports no samples read for it.
+			return mat, stats.NewChildWithStepTracking(subqStart, subqEnd, subqInterval), ws
+		}
+	}
+
 	// Subquery children always track per-step samples-read (independent of
 	// the parent's per-step setting) so MergeSamplesReadFromSubquery can
 	// attribute each subquery iteration to a parent step and drop iterations
@@ -1969,11 +2094,13 @@ func (ev *evaluator) runSubquery(ctx context.Context, e *parser.SubqueryExpr) (p
 		logger:                   ev.logger,
 		lookbackDelta:            ev.lookbackDelta,
 		samplesStats:             childStats,
+		operationStats:           ev.operationStats,
 		noStepSubqueryIntervalFn: ev.noStepSubqueryIntervalFn,
 		enableDelayedNameRemoval: ev.enableDelayedNameRemoval,
 		enableTypeAndUnitLabels:  ev.enableTypeAndUnitLabels,
 		useStartTimestamps:       ev.useStartTimestamps,
 		querier:                  ev.querier,
+		subqueryCache:            ev.subqueryCache,
 	}
 
 	if subqStart != ev.startTimestamp {
@@ -1985,6 +2112,13 @@ func (ev *evaluator) runSubquery(ctx context.Context, e *parser.SubqueryExpr) (p
 
 	res, ws := newEv.eval(ctx, e.Expr)
 	ev.currentSamples = newEv.currentSamples
+	if ev.subqueryCache != nil {
+		if mat, ok := res.(Matrix); ok {
+			// Cache a private copy: consumers may mutate the returned
+			// matrix or return its sample slices to the pools.
+			ev.subqueryCache.put(cacheKey, copyValue(mat).(Matrix), ws)
+		}
+	}
 	return res, childStats, ws
 }
 
@@ -2047,8 +2181,181 @@ func (ev *evaluator) evalSubquery(ctx context.Context, subq *parser.SubqueryExpr
 	return ms, mat.TotalSamples(), ws
 }
 
-// eval evaluates the given expression as the given AST expression node requires.
+// operationName buckets an expression node into the operation class used for
+// the per-operation query statistics collected by stats.QueryOperations.
+func operationName(expr parser.Expr) string {
+	switch e := expr.(type) {
+	case *parser.VectorSelector, *parser.MatrixSelector:
+		return "selector"
+	case *parser.AggregateExpr:
+		return "aggregation"
+	case *parser.Call:
+		return "function"
+	case *parser.BinaryExpr:
+		if e.LHS.Type() == parser.ValueTypeVector && e.RHS.Type() == parser.ValueTypeVector {
+			return "join"
+		}
+		return "binary"
+	case *parser.SubqueryExpr:
+		return "subquery"
+	default:
+		return "other"
+	}
+}
+
+// eval evaluates the given expression, reusing the cached result if the
+// expression is a subexpression that occurs more than once in the query.
 func (ev *evaluator) eval(ctx context.Context, expr parser.Expr) (parser.Value, annotations.Annotations) {
+	key, ok := ev.commonSubexpressionKey(expr)
+	if !ok {
+		return ev.evalExpr(ctx, expr)
+	}
+	if cached := ev.commonSubexpressions[key]; cached != nil {
+		// Account for the reused samples as if the subexpression had been
+		// evaluated again, so sample limits apply consistently.
+		numSamples := valueTotalSamples(cached.value)
+		ev.currentSamples += numSamples
+		if ev.currentSamples > ev.maxSamples {
+			ev.error(ErrTooManySamples(env))
+		}
+		ev.samplesStats.UpdatePeak(ev.currentSamples)
+		ev.subexpressionStats.AddReuse(int64(numSamples))
+		return copyValue(cached.value), cached.ws
+	}
+	val, ws := ev.evalExpr(ctx, expr)
+	// Cache a private copy: consumers may mutate the returned value or return
+	// its sample slices to the pools.
+	cachedVal := copyValue(val)
+	ev.currentSamples += valueTotalSamples(cachedVal)
+	if ev.currentSamples > ev.maxSamples {
+		ev.error(ErrTooManySamples(env))
+	}
+	ev.samplesStats.UpdatePeak(ev.currentSamples)
+	ev.commonSubexpressions[key] = &cachedSubexpression{value: cachedVal, ws: ws}
+	return val, ws
+}
+
+// commonSubexpressionKey returns the cache key for the given expression, and
+// whether it is one of the repeated subexpressions found by
+// findCommonSubexpressions.
+func (ev *evaluator) commonSubexpressionKey(expr parser.Expr) (string, bool) {
+	if len(ev.commonSubexpressions) == 0 {
+		return "", false
+	}
+	switch expr.(type) {
+	case *parser.AggregateExpr, *parser.BinaryExpr, *parser.Call, *parser.SubqueryExpr:
+	default:
+		return "", false
+	}
+	key := expr.String()
+	_, ok := ev.commonSubexpressions[key]
+	return key, ok
+}
+
+// findCommonSubexpressions returns a cache map keyed by the string form of
+// every subexpression that occurs more than once in expr, with nil entries to
+// be filled in during evaluation. Only the topmost repeated expressions are
+// included: nested repetitions inside a repeated parent are covered by caching
+// the parent. Subquery bodies are not inspected because they are evaluated on
+// a different time grid. Returns nil when the query has no repeated
+// subexpressions.
+func findCommonSubexpressions(expr parser.Expr) map[string]*cachedSubexpression {
+	counts := map[string]int{}
+	var count func(node parser.Node)
+	count = func(node parser.Node) {
+		switch node.(type) {
+		case *parser.AggregateExpr, *parser.BinaryExpr, *parser.Call:
+			counts[node.String()]++
+		case *parser.SubqueryExpr:
+			// The subquery as a whole can be reused, but its body is
+			// evaluated on its own time grid, so do not descend into it.
+			counts[node.String()]++
+			return
+		case *parser.StepInvariantExpr:
+			// Evaluated by a child evaluator at a fixed timestamp.
+			return
+		}
+		for _, child := range parser.Children(node) {
+			count(child)
+		}
+	}
+	count(expr)
+
+	cache := map[string]*cachedSubexpression{}
+	var collect func(node parser.Node)
+	collect = func(node parser.Node) {
+		switch node.(type) {
+		case *parser.AggregateExpr, *parser.BinaryExpr, *parser.Call, *parser.SubqueryExpr:
+			if counts[node.String()] > 1 {
+				cache[node.String()] = nil
+				return
+			}
+			if _, ok := node.(*parser.SubqueryExpr); ok {
+				return
+			}
+		case *parser.StepInvariantExpr:
+			return
+		}
+		for _, child := range parser.Children(node) {
+			collect(child)
+		}
+	}
+	collect(expr)
+	if len(cache) == 0 {
+		return nil
+	}
+	return cache
+}
+
+// copyValue returns a deep copy of the sample data of val. Label sets are
+// shared as they are immutable by convention.
+func copyValue(val parser.Value) parser.Value {
+	switch v := val.(type) {
+	case Matrix:
+		mat := make(Matrix, len(v))
+		for i, s := range v {
+			cs := Series{Metric: s.Metric, DropName: s.DropName}
+			if len(s.Floats) > 0 {
+				cs.Floats = make([]FPoint, len(s.Floats))
+				copy(cs.Floats, s.Floats)
+			}
+			if len(s.Histograms) > 0 {
+				cs.Histograms = make([]HPoint, len(s.Histograms))
+				for j, hp := range s.Histograms {
+					cs.Histograms[j] = HPoint{T: hp.T, H: hp.H.Copy()}
+				}
+			}
+			mat[i] = cs
+		}
+		return mat
+	case Vector:
+		vec := make(Vector, len(v))
+		copy(vec, v)
+		for i, s := range vec {
+			if s.H != nil {
+				vec[i].H = s.H.Copy()
+			}
+		}
+		return vec
+	default:
+		return val
+	}
+}
+
+// valueTotalSamples returns the number of samples held by val.
+func valueTotalSamples(val parser.Value) int {
+	switch v := val.(type) {
+	case Matrix:
+		return v.TotalSamples()
+	case Vector:
+		return v.TotalSamples()
+	default:
+		return 0
+	}
+}
+
+// evalExpr evaluates the given expression as the given AST expression node requires.
+func (ev *evaluator) evalExpr(ctx context.Context, expr parser.Expr) (parser.Value, annotations.Annotations) {
 	// This is the top-level evaluation method.
 	// Thus, we check for timeout/cancellation here.
 	if err := contextDone(ctx, "expression evaluation"); err != nil {
@@ -2068,6 +2375,9 @@ func (ev *evaluator) eval(ctx context.Context, expr parser.Expr) (parser.Value,
 		span.SetAttributes(attribute.String("operation", ss.ShortString()))
 	}
 
+	// Attribute self time and loaded samples to the node's operation class.
+	defer ev.operationStats.StartOperation(operationName(expr), ev.samplesStats)()
+
 	switch e := expr.(type) {
 	case *parser.AggregateExpr:
 		// Grouping labels must be sorted (expected both by generateGroupingKey() and aggregation()).
@@ -2154,6 +2464,14 @@ func (ev *evaluator) eval(ctx context.Context, expr parser.Expr) (parser.Value,
 			return ev.evalLabelReplace(ctx, e.Args)
 		case "label_join":
 			return ev.evalLabelJoin(ctx, e.Args)
+		case "label_lower":
+			return ev.evalLabelLower(ctx, e.Args)
+		case "label_upper":
+			return ev.evalLabelUpper(ctx, e.Args)
+		case "label_trim":
+			return ev.evalLabelTrim(ctx, e.Args)
+		case "label_substr":
+			return ev.evalLabelSubstr(ctx, e.Args)
 		case "info":
 			return ev.evalInfo(ctx, e.Args)
 		}
@@ -2247,13 +2565,22 @@ func (ev *evaluator) eval(ctx context.Context, expr parser.Expr) (parser.Value,
 			startTimestamps = &StartTimestamps{}
 		}
 
+		// The storage may have already applied the pushed-down function hint
+		// to the returned series (e.g. a remote read backend with pushdown
+		// support). The samples are then final results, so instead of applying
+		// the function again we pick the most recent sample in each window,
+		// mirroring last_over_time, and keep the labels as returned.
+		if selVS.HintsApplied {
+			call = FunctionCalls["last_over_time"]
+		}
+
 		// The last_over_time and first_over_time functions act like
 		// offset; thus, they should keep the metric name.  For all the
 		// other range vector functions, the only change needed is to
 		// drop the metric name in the output.
 		// However, if the input series (e.g., from a subquery) already has
 		// DropName set, we should respect that.
-		dropName := (e.Func.Name != "last_over_time" && e.Func.Name != "first_over_time")
+		dropName := !selVS.HintsApplied && e.Func.Name != "last_over_time" && e.Func.Name != "first_over_time"
 		vectorVals := make([]Vector, len(e.Args)-1)
 		for i, s := range selVS.Series {
 			if err := contextDone(ctx, "expression evaluation"); err != nil {
@@ -2538,11 +2865,11 @@ func (ev *evaluator) eval(ctx context.Context, expr parser.Expr) (parser.Value,
 			ev.error(errWithWarnings{fmt.Errorf("expanding series: %w", err), ws})
 		}
 		if e.Smoothed {
-			mat, smoothAnnos := ev.smoothSeries(e.Series, e.Offset, e.PositionRange())
+			mat, smoothAnnos := ev.smoothSeries(e.Series, e.Offset, ev.lookbackDeltaFor(e), e.PositionRange())
 			ws.Merge(smoothAnnos)
 			return mat, ws
 		}
-		mat := ev.evalSeries(ctx, e.Series, e.Offset, false)
+		mat := ev.evalSeries(ctx, e.Series, e.Offset, ev.lookbackDeltaFor(e), false)
 		return mat, ws
 
 	case *parser.MatrixSelector:
@@ -2571,11 +2898,13 @@ func (ev *evaluator) eval(ctx context.Context, expr parser.Expr) (parser.Value,
 			logger:                   ev.logger,
 			lookbackDelta:            ev.lookbackDelta,
 			samplesStats:             ev.samplesStats.NewChild(),
+			operationStats:           ev.operationStats,
 			noStepSubqueryIntervalFn: ev.noStepSubqueryIntervalFn,
 			enableDelayedNameRemoval: ev.enableDelayedNameRemoval,
 			enableTypeAndUnitLabels:  ev.enableTypeAndUnitLabels,
 			useStartTimestamps:       ev.useStartTimestamps,
 			querier:                  ev.querier,
+			subqueryCache:            ev.subqueryCache,
 		}
 		res, ws := newEv.eval(ctx, e.Expr)
 		ev.currentSamples = newEv.currentSamples
@@ -2661,10 +2990,11 @@ func (ev *evaluator) rangeEvalTimestampFunctionOverVectorSelector(ctx context.Co
 		ev.error(errWithWarnings{fmt.Errorf("expanding series: %w", err), ws})
 	}
 
+	lookbackDelta := ev.lookbackDeltaFor(vs)
 	seriesIterators := make([]*storage.MemoizedSeriesIterator, len(vs.Series))
 	for i, s := range vs.Series {
 		it := s.Iterator(nil)
-		seriesIterators[i] = storage.NewMemoizedIterator(it, durationMilliseconds(ev.lookbackDelta)-1)
+		seriesIterators[i] = storage.NewMemoizedIterator(it, durationMilliseconds(lookbackDelta)-1)
 	}
 
 	return ev.rangeEval(ctx, nil, func(_ []Vector, _ Matrix, _ [][]EvalSeriesHelper, enh *EvalNodeHelper) (Vector, annotations.Annotations) {
@@ -2688,7 +3018,7 @@ func (ev *evaluator) rangeEvalTimestampFunctionOverVectorSelector(ctx context.Co
 		}
 		for i, s := range vs.Series {
 			it := seriesIterators[i]
-			st, t, _, _, ok := ev.vectorSelectorSingle(it, vs.Offset, enh.Ts)
+			st, t, _, _, ok := ev.vectorSelectorSingle(it, vs.Offset, lookbackDelta, enh.Ts)
 			if !ok {
 				continue
 			}
@@ -2726,8 +3056,17 @@ func (ev *evaluator) rangeEvalTimestampFunctionOverVectorSelector(ctx context.Co
 	})
 }
 
+// lookbackDeltaFor returns the lookback delta to use for a vector selector,
+// honoring a per-selector lookback modifier.
+func (ev *evaluator) lookbackDeltaFor(vs *parser.VectorSelector) time.Duration {
+	if vs.Lookback > 0 {
+		return vs.Lookback
+	}
+	return ev.lookbackDelta
+}
+
 // vectorSelectorSingle evaluates an instant vector for the iterator of one time series.
-func (ev *evaluator) vectorSelectorSingle(it *storage.MemoizedSeriesIterator, offset time.Duration, ts int64) (
+func (ev *evaluator) vectorSelectorSingle(it *storage.MemoizedSeriesIterator, offset, lookbackDelta time.Duration, ts int64) (
 	int64, int64, float64, *histogram.FloatHistogram, bool,
 ) {
 	refTime := ts - durationMilliseconds(offset)
@@ -2753,7 +3092,7 @@ func (ev *evaluator) vectorSelectorSingle(it *storage.MemoizedSeriesIterator, of
 	if valueType == chunkenc.ValNone || t > refTime {
 		var ok bool
 		st, t, v, h, ok = it.PeekPrev()
-		if !ok || t <= refTime-durationMilliseconds(ev.lookbackDelta) {
+		if !ok || t <= refTime-durationMilliseconds(lookbackDelta) {
 			return 0, 0, 0, nil, false
 		}
 	}
@@ -3609,7 +3948,7 @@ type groupedAggregation struct {
 	dropName                 bool // True if any sample in this group has DropName set.
 }
 
-// aggregation evaluates sum, avg, count, stdvar, stddev or quantile at one timestep on inputMatrix.
+// aggregation evaluates sum, avg, count, stdvar, stddev, quantile, median or mode at one timestep on inputMatrix.
 // These functions produce one output series for each group specified in the expression, with just the labels from `by(...)`.
 // outputMatrix should be already populated with grouping labels; groups is one-to-one with outputMatrix.
 // seriesToResult maps inputMatrix indexes to outputMatrix indexes.
@@ -3666,10 +4005,10 @@ func (ev *evaluator) aggregation(e *parser.AggregateExpr, q float64, inputMatrix
 				default:
 					group.floatValue = 0
 				}
-			case parser.QUANTILE:
+			case parser.QUANTILE, parser.MEDIAN, parser.MODE:
 				if h != nil {
 					group.seen = false
-					annos.Add(annotations.NewHistogramIgnoredInAggregationInfo("quantile", e.Expr.PositionRange()))
+					annos.Add(annotations.NewHistogramIgnoredInAggregationInfo(op.String(), e.Expr.PositionRange()))
 				}
 				group.heap = make(vectorByValueHeap, 1)
 				group.heap[0] = Sample{F: f}
@@ -3874,9 +4213,9 @@ func (ev *evaluator) aggregation(e *parser.AggregateExpr, q float64, inputMatrix
 				}
 			}
 
-		case parser.QUANTILE:
+		case parser.QUANTILE, parser.MEDIAN, parser.MODE:
 			if h != nil {
-				annos.Add(annotations.NewHistogramIgnoredInAggregationInfo("quantile", e.Expr.PositionRange()))
+				annos.Add(annotations.NewHistogramIgnoredInAggregationInfo(op.String(), e.Expr.PositionRange()))
 				continue
 			}
 			group.heap = append(group.heap, Sample{F: f})
@@ -3939,6 +4278,12 @@ func (ev *evaluator) aggregation(e *parser.AggregateExpr, q float64, inputMatrix
 		case parser.QUANTILE:
 			aggr.floatValue = quantile(q, aggr.heap)
 
+		case parser.MEDIAN:
+			aggr.floatValue = median(aggr.heap)
+
+		case parser.MODE:
+			aggr.floatValue = mode(aggr.heap)
+
 		case parser.SUM:
 			if aggr.hasFloat && aggr.hasHistogram {
 				// We cannot aggregate histogram sample with a float64 sample.
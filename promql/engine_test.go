@@ -611,6 +611,148 @@ func TestSelectHintsSetCorrectly(t *testing.T) {
 	}
 }
 
+// hintsAppliedQueryable wraps a storage.Queryable and reports for every select
+// that the storage already applied the pushed-down hints, like a remote read
+// backend with pushdown support.
+type hintsAppliedQueryable struct {
+	storage.Queryable
+}
+
+func (q hintsAppliedQueryable) Querier(mint, maxt int64) (storage.Querier, error) {
+	querier, err := q.Queryable.Querier(mint, maxt)
+	if err != nil {
+		return nil, err
+	}
+	return hintsAppliedQuerier{querier}, nil
+}
+
+type hintsAppliedQuerier struct {
+	storage.Querier
+}
+
+func (q hintsAppliedQuerier) Select(ctx context.Context, sortSeries bool, hints *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	return hintsAppliedSeriesSet{q.Querier.Select(ctx, sortSeries, hints, matchers...)}
+}
+
+type hintsAppliedSeriesSet struct {
+	storage.SeriesSet
+}
+
+func (hintsAppliedSeriesSet) HintsApplied() bool { return true }
+
+// hintsAppliedStorage wraps a storage.Storage the same way, so it can stand
+// in for a remote read endpoint behind storage.NewFanout.
+type hintsAppliedStorage struct {
+	storage.Storage
+}
+
+func (s hintsAppliedStorage) Querier(mint, maxt int64) (storage.Querier, error) {
+	querier, err := s.Storage.Querier(mint, maxt)
+	if err != nil {
+		return nil, err
+	}
+	return hintsAppliedQuerier{querier}, nil
+}
+
+func TestHintsAppliedSkipsFunctionEvaluation(t *testing.T) {
+	st := promqltest.LoadedStorage(t, `
+load 30s
+  foo{job="a"} 0+30x10
+`)
+	// The same data in a separate storage, standing in for a local TSDB whose
+	// raw series overlap the pushed-down remote data.
+	local := promqltest.LoadedStorage(t, `
+load 30s
+  foo{job="a"} 0+30x10
+`)
+	emptyLocal := promqltest.LoadedStorage(t, ``)
+
+	opts := promql.EngineOpts{
+		Logger:     nil,
+		Reg:        nil,
+		MaxSamples: 100,
+		Timeout:    10 * time.Second,
+	}
+
+	for _, tc := range []struct {
+		name      string
+		queryable storage.Queryable
+		expected  promql.Vector
+	}{
+		{
+			// The engine evaluates the function over the raw samples.
+			name:      "hints not applied",
+			queryable: st,
+			expected: promql.Vector{
+				promql.Sample{
+					F:        1020,
+					T:        300000,
+					Metric:   labels.FromStrings("job", "a"),
+					DropName: true,
+				},
+			},
+		},
+		{
+			// The storage reported that it already applied the hinted
+			// function, so the engine must return the precomputed samples
+			// instead of applying the function again, keeping the labels as
+			// returned.
+			name:      "hints applied",
+			queryable: hintsAppliedQueryable{st},
+			expected: promql.Vector{
+				promql.Sample{
+					F:      300,
+					T:      300000,
+					Metric: labels.FromStrings("__name__", "foo", "job", "a"),
+				},
+			},
+		},
+		{
+			// The standard server setup: local TSDB and remote endpoint
+			// merged through a fanout. The empty local storage contributes no
+			// series, so the flag survives the merge and the engine still
+			// skips the function.
+			name:      "hints applied through fanout with empty local storage",
+			queryable: storage.NewFanout(nil, emptyLocal, hintsAppliedStorage{st}),
+			expected: promql.Vector{
+				promql.Sample{
+					F:      300,
+					T:      300000,
+					Metric: labels.FromStrings("__name__", "foo", "job", "a"),
+				},
+			},
+		},
+		{
+			// Raw local series overlap the pushed-down data, so the merge
+			// drops the flag and the engine evaluates the function over the
+			// merged samples.
+			name:      "hints dropped when merged with local series",
+			queryable: storage.NewFanout(nil, local, hintsAppliedStorage{st}),
+			expected: promql.Vector{
+				promql.Sample{
+					F:        1020,
+					T:        300000,
+					Metric:   labels.FromStrings("job", "a"),
+					DropName: true,
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			engine := promqltest.NewTestEngineWithOpts(t, opts)
+			ctx := context.Background()
+
+			query, err := engine.NewInstantQuery(ctx, tc.queryable, nil, "sum_over_time(foo[2m])", timestamp.Time(300000))
+			require.NoError(t, err)
+			t.Cleanup(query.Close)
+
+			res := query.Exec(ctx)
+			require.NoError(t, res.Err)
+			require.Equal(t, tc.expected, res.Value)
+		})
+	}
+}
+
 func TestEngineShutdown(t *testing.T) {
 	opts := promql.EngineOpts{
 		Logger:     nil,
@@ -1191,14 +1333,14 @@ load 10s
 		{
 			Query:        "sum(max_over_time(metricWith3SampleEvery10Seconds[60s:5s])) + sum(max_over_time(metricWith3SampleEvery10Seconds[60s:5s]))",
 			Start:        time.Unix(201, 0),
-			PeakSamples:  52,
-			TotalSamples: 72, // 2 * (3 sample per query * 12 queries (60/5))
+			PeakSamples:  51,
+			TotalSamples: 36, // 3 sample per query * 12 queries (60/5); the repeated operand is evaluated once and reused.
 			TotalSamplesPerStep: stats.TotalSamplesPerStep{
-				201000: 72,
+				201000: 36,
 			},
-			SamplesRead: 72,
+			SamplesRead: 36,
 			SamplesReadPerStep: stats.TotalSamplesPerStep{
-				201000: 72,
+				201000: 36,
 			},
 		},
 		{
@@ -1481,20 +1623,20 @@ load 10s
 			Start:        time.Unix(201, 0),
 			End:          time.Unix(220, 0),
 			Interval:     5 * time.Second,
-			PeakSamples:  73,
-			TotalSamples: 288, // 2 * (3 sample per query * 12 queries (60/5) * 4 steps)
+			PeakSamples:  69,
+			TotalSamples: 144, // 3 sample per query * 12 queries (60/5) * 4 steps; the repeated operand is evaluated once and reused.
 			TotalSamplesPerStep: stats.TotalSamplesPerStep{
-				201000: 72,
-				206000: 72,
-				211000: 72,
-				216000: 72,
+				201000: 36,
+				206000: 36,
+				211000: 36,
+				216000: 36,
 			},
-			SamplesRead: 90,
+			SamplesRead: 45,
 			SamplesReadPerStep: stats.TotalSamplesPerStep{
-				201000: 72,
-				206000: 6,
-				211000: 6,
-				216000: 6,
+				201000: 36,
+				206000: 3,
+				211000: 3,
+				216000: 3,
 			},
 		},
 		{
@@ -2040,6 +2182,150 @@ load 10s
 	}
 }
 
+func TestQueryOperationStatistics(t *testing.T) {
+	storage := promqltest.LoadedStorage(t, `
+load 10s
+  metricWith3SampleEvery10Seconds{a="1",b="1"} 1+1x100
+  metricWith3SampleEvery10Seconds{a="2",b="2"} 1+1x100
+  metricWith3SampleEvery10Seconds{a="3",b="2"} 1+1x100
+`)
+	engine := newTestEngine(t)
+
+	qry, err := engine.NewInstantQuery(context.Background(), storage, nil,
+		"sum(metricWith3SampleEvery10Seconds + metricWith3SampleEvery10Seconds)", time.Unix(21, 0))
+	require.NoError(t, err)
+	res := qry.Exec(context.Background())
+	require.NoError(t, res.Err)
+
+	var selCount, selSamples, joinCount, aggCount int64
+	for _, op := range qry.Stats().Operations.Stats() {
+		switch op.Operation {
+		case "selector":
+			selCount, selSamples = op.Count, op.Samples
+		case "join":
+			joinCount = op.Count
+			require.Equal(t, int64(0), op.Samples, "join must not claim selector samples")
+		case "aggregation":
+			aggCount = op.Count
+			require.Equal(t, int64(0), op.Samples, "aggregation must not claim selector samples")
+		default:
+			t.Errorf("unexpected operation %q", op.Operation)
+		}
+		require.GreaterOrEqual(t, op.Time, 0.0, "self time of %q must not be negative", op.Operation)
+	}
+	require.Equal(t, int64(2), selCount, "expected one evaluation per vector selector")
+	require.Equal(t, int64(6), selSamples, "expected selectors to claim all loaded samples")
+	require.Equal(t, int64(1), joinCount)
+	require.Equal(t, int64(1), aggCount)
+}
+
+func TestSubqueryCacheAcrossQueries(t *testing.T) {
+	storage := promqltest.LoadedStorage(t, `
+load 10s
+  metric 1+1x100
+`)
+	const query = "metric[30s:10s]"
+	evalTime := time.Unix(60, 0)
+
+	exec := func(t *testing.T, ctx context.Context, ts time.Time) (promql.Matrix, int64) {
+		t.Helper()
+		engine := newTestEngine(t)
+		qry, err := engine.NewInstantQuery(ctx, storage, nil, query, ts)
+		require.NoError(t, err)
+		res := qry.Exec(ctx)
+		require.NoError(t, res.Err)
+		mat, err := res.Matrix()
+		require.NoError(t, err)
+		return mat, qry.Stats().Samples.TotalSamples
+	}
+
+	t.Run("shared cache reuses the subquery result", func(t *testing.T) {
+		ctx := promql.NewSubqueryCacheContext(context.Background(), promql.NewSubqueryCache())
+
+		first, firstSamples := exec(t, ctx, evalTime)
+		require.Positive(t, firstSamples)
+
+		// The second query at the identical timestamp reuses the cached
+		// matrix and reads no samples for the subquery.
+		second, secondSamples := exec(t, ctx, evalTime)
+		require.Equal(t, first, second)
+		require.Zero(t, secondSamples)
+
+		// A different evaluation timestamp must not reuse the result.
+		_, shiftedSamples := exec(t, ctx, evalTime.Add(10*time.Second))
+		require.Positive(t, shiftedSamples)
+	})
+
+	t.Run("no sharing without a cache in the context", func(t *testing.T) {
+		ctx := context.Background()
+
+		first, firstSamples := exec(t, ctx, evalTime)
+		second, secondSamples := exec(t, ctx, evalTime)
+		require.Equal(t, first, second)
+		require.Equal(t, firstSamples, secondSamples)
+		require.Positive(t, secondSamples)
+	})
+}
+
+func TestQuerySubexpressionStatistics(t *testing.T) {
+	storage := promqltest.LoadedStorage(t, `
+load 10s
+  metricWith3SampleEvery10Seconds{a="1",b="1"} 1+1x100
+  metricWith3SampleEvery10Seconds{a="2",b="2"} 1+1x100
+  metricWith3SampleEvery10Seconds{a="3",b="2"} 1+1x100
+`)
+
+	cases := []struct {
+		Query             string
+		ReusedEvaluations int64
+		ReusedSamples     int64
+		ExpectedResult    float64
+	}{
+		{
+			// The repeated sum() is evaluated once and its single resulting
+			// sample is reused for the second occurrence.
+			Query:             "sum(metricWith3SampleEvery10Seconds) / sum(metricWith3SampleEvery10Seconds)",
+			ReusedEvaluations: 1,
+			ReusedSamples:     1,
+			ExpectedResult:    1,
+		},
+		{
+			// A repeated selector is not cached on its own, but the
+			// aggregations wrapping it are identical and reused.
+			Query:             "(sum(metricWith3SampleEvery10Seconds) + sum(metricWith3SampleEvery10Seconds)) / sum(metricWith3SampleEvery10Seconds)",
+			ReusedEvaluations: 2,
+			ReusedSamples:     2,
+			ExpectedResult:    2,
+		},
+		{
+			Query:             "sum(metricWith3SampleEvery10Seconds)",
+			ReusedEvaluations: 0,
+			ReusedSamples:     0,
+			ExpectedResult:    9,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Query, func(t *testing.T) {
+			engine := newTestEngine(t)
+			qry, err := engine.NewInstantQuery(context.Background(), storage, nil, c.Query, time.Unix(21, 0))
+			require.NoError(t, err)
+			res := qry.Exec(context.Background())
+			require.NoError(t, res.Err)
+
+			vec, err := res.Vector()
+			require.NoError(t, err)
+			require.Len(t, vec, 1)
+			require.Equal(t, c.ExpectedResult, vec[0].F)
+
+			subexprStats := qry.Stats().Subexpressions
+			require.NotNil(t, subexprStats)
+			require.Equal(t, c.ReusedEvaluations, subexprStats.ReusedEvaluations)
+			require.Equal(t, c.ReusedSamples, subexprStats.ReusedSamples)
+		})
+	}
+}
+
 func TestExtendedRangeSelectors(t *testing.T) {
 	engine := newTestEngine(t)
 	storage := promqltest.LoadedStorage(t, `
@@ -3966,6 +4252,7 @@ metric 0 1 2
 
 	cases := []struct {
 		name                          string
+		query                         string
 		ts                            time.Time
 		engineLookback, queryLookback time.Duration
 		expectSamples                 bool
@@ -4013,6 +4300,26 @@ metric 0 1 2
 			queryLookback:  20 * time.Minute,
 			expectSamples:  true,
 		},
+		{
+			name:          "lookback modifier on the selector",
+			query:         "metric lookback 30m",
+			ts:            lastDatapointTs.Add(30*time.Minute - time.Millisecond),
+			expectSamples: true,
+		},
+		{
+			name:          "outside lookback modifier on the selector",
+			query:         "metric lookback 30m",
+			ts:            lastDatapointTs.Add(30 * time.Minute),
+			expectSamples: false,
+		},
+		{
+			name:           "lookback modifier overrides engine and query lookback delta",
+			query:          "metric lookback 1m",
+			ts:             lastDatapointTs.Add(time.Minute),
+			engineLookback: 10 * time.Minute,
+			queryLookback:  20 * time.Minute,
+			expectSamples:  false,
+		},
 	}
 
 	for _, c := range cases {
@@ -4020,8 +4327,12 @@ metric 0 1 2
 			engine := promqltest.NewTestEngine(t, false, c.engineLookback, promqltest.DefaultMaxSamplesPerQuery)
 			storage := promqltest.LoadedStorage(t, load)
 
+			q := query
+			if c.query != "" {
+				q = c.query
+			}
 			opts := promql.NewPrometheusQueryOpts(false, c.queryLookback)
-			qry, err := engine.NewInstantQuery(context.Background(), storage, opts, query, c.ts)
+			qry, err := engine.NewInstantQuery(context.Background(), storage, opts, q, c.ts)
 			require.NoError(t, err)
 
 			res := qry.Exec(context.Background())
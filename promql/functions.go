@@ -18,8 +18,10 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/bits"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -33,6 +35,7 @@ import (
 	"github.com/prometheus/prometheus/promql/parser/posrange"
 	"github.com/prometheus/prometheus/schema"
 	"github.com/prometheus/prometheus/util/annotations"
+	"github.com/prometheus/prometheus/util/convertnhcb"
 	"github.com/prometheus/prometheus/util/kahansum"
 )
 
@@ -1772,6 +1775,113 @@ func funcFloor(vectorVals []Vector, _ Matrix, _ parser.Expressions, enh *EvalNod
 	return simpleFloatFunc(vectorVals, enh, math.Floor), nil
 }
 
+// bitsFromFloat converts a sample value to an unsigned integer for bit
+// manipulation. Only integers in [0, 2^53] are exactly representable as
+// float64, so everything else is rejected.
+func bitsFromFloat(f float64) (uint64, bool) {
+	if f < 0 || f > 1<<53 || f != math.Trunc(f) {
+		return 0, false
+	}
+	return uint64(f), true
+}
+
+// bitwiseFunc applies op to the integer representation of every float sample.
+// Samples whose value or result is not exactly representable as an integer
+// yield NaN.
+func bitwiseFunc(vectorVals []Vector, enh *EvalNodeHelper, op func(uint64) uint64) Vector {
+	return simpleFloatFunc(vectorVals, enh, func(f float64) float64 {
+		u, ok := bitsFromFloat(f)
+		if !ok {
+			return math.NaN()
+		}
+		res := op(u)
+		if res > 1<<53 {
+			return math.NaN()
+		}
+		return float64(res)
+	})
+}
+
+// allNaNFunc replaces every float sample of a vector with NaN. It is used by
+// the bit manipulation functions when their scalar argument is invalid.
+func allNaNFunc(vectorVals []Vector, enh *EvalNodeHelper) Vector {
+	return simpleFloatFunc(vectorVals, enh, func(float64) float64 { return math.NaN() })
+}
+
+// === bitand(Vector parser.ValueTypeVector, mask Scalar) (Vector, Annotations) ===
+func funcBitand(vectorVals []Vector, _ Matrix, _ parser.Expressions, enh *EvalNodeHelper) (Vector, annotations.Annotations) {
+	mask, ok := bitsFromFloat(vectorVals[1][0].F)
+	if !ok {
+		return allNaNFunc(vectorVals, enh), nil
+	}
+	return bitwiseFunc(vectorVals, enh, func(u uint64) uint64 { return u & mask }), nil
+}
+
+// === bitor(Vector parser.ValueTypeVector, mask Scalar) (Vector, Annotations) ===
+func funcBitor(vectorVals []Vector, _ Matrix, _ parser.Expressions, enh *EvalNodeHelper) (Vector, annotations.Annotations) {
+	mask, ok := bitsFromFloat(vectorVals[1][0].F)
+	if !ok {
+		return allNaNFunc(vectorVals, enh), nil
+	}
+	return bitwiseFunc(vectorVals, enh, func(u uint64) uint64 { return u | mask }), nil
+}
+
+// === bitshift(Vector parser.ValueTypeVector, shift Scalar) (Vector, Annotations) ===
+func funcBitshift(vectorVals []Vector, _ Matrix, _ parser.Expressions, enh *EvalNodeHelper) (Vector, annotations.Annotations) {
+	shift := vectorVals[1][0].F
+	if shift != math.Trunc(shift) || math.Abs(shift) > 64 {
+		return allNaNFunc(vectorVals, enh), nil
+	}
+	n := int(shift)
+	return simpleFloatFunc(vectorVals, enh, func(f float64) float64 {
+		u, ok := bitsFromFloat(f)
+		if !ok {
+			return math.NaN()
+		}
+		if n < 0 {
+			if -n >= 64 {
+				return 0
+			}
+			return float64(u >> -n)
+		}
+		// A left shift must not push bits beyond 2^53, where float64
+		// loses integer precision.
+		if u != 0 && bits.Len64(u)+n > 54 {
+			return math.NaN()
+		}
+		return float64(u << n)
+	}), nil
+}
+
+// === from_hexstring(Vector parser.ValueTypeVector, src_label parser.ValueTypeString) (Vector, Annotations) ===
+func funcFromHexstring(vectorVals []Vector, _ Matrix, args parser.Expressions, enh *EvalNodeHelper) (Vector, annotations.Annotations) {
+	src := stringFromArg(args[1])
+	if !model.UTF8Validation.IsValidLabelName(src) {
+		panic(fmt.Errorf("invalid source label name in from_hexstring(): %s", src))
+	}
+	for _, el := range vectorVals[0] {
+		if el.H != nil { // Process only float samples.
+			continue
+		}
+		val := strings.TrimPrefix(strings.ToLower(el.Metric.Get(src)), "0x")
+		u, err := strconv.ParseUint(val, 16, 64)
+		if err != nil || u > 1<<53 {
+			// Drop series whose label is absent, not valid
+			// hexadecimal, or too large for an exact float64.
+			continue
+		}
+		if !enh.enableDelayedNameRemoval {
+			el.Metric = el.Metric.DropReserved(schema.IsMetadataLabel)
+		}
+		enh.Out = append(enh.Out, Sample{
+			Metric:   el.Metric,
+			F:        float64(u),
+			DropName: true,
+		})
+	}
+	return enh.Out, nil
+}
+
 // === exp(Vector parser.ValueTypeVector) (Vector, Annotations) ===
 func funcExp(vectorVals []Vector, _ Matrix, _ parser.Expressions, enh *EvalNodeHelper) (Vector, annotations.Annotations) {
 	return simpleFloatFunc(vectorVals, enh, math.Exp), nil
@@ -2315,6 +2425,137 @@ func funcHistogramQuantiles(vectorVals []Vector, _ Matrix, args parser.Expressio
 	return enh.Out, annos
 }
 
+// === convert_to_native_histogram(Vector parser.ValueTypeVector) (Vector, Annotations) ===
+func funcConvertToNativeHistogram(vectorVals []Vector, _ Matrix, args parser.Expressions, enh *EvalNodeHelper) (Vector, annotations.Annotations) {
+	inVec := vectorVals[0]
+	var annos annotations.Annotations
+
+	// Classic histogram series grouped by their base labels, i.e. with the
+	// _bucket/_sum/_count suffix and any le label removed.
+	type tempHistogramGroup struct {
+		metric labels.Labels
+		th     convertnhcb.TempHistogram
+	}
+	var (
+		groups      []*tempHistogramGroup
+		sigToGroup  = map[string]*tempHistogramGroup{}
+		nativeSigs  map[string]struct{}
+		excludeBoth = []string{labels.MetricName, labels.BucketLabel}
+	)
+
+	for _, sample := range inVec {
+		if sample.H != nil {
+			// Already a native histogram, pass it through unchanged.
+			// Remember its signature so a conflicting classic histogram
+			// with the same base labels is not emitted as a duplicate.
+			if nativeSigs == nil {
+				nativeSigs = map[string]struct{}{}
+			}
+			enh.lblBuf = sample.Metric.Bytes(enh.lblBuf)
+			nativeSigs[string(enh.lblBuf)] = struct{}{}
+			enh.Out = append(enh.Out, sample)
+			continue
+		}
+
+		metricName := getMetricName(sample.Metric)
+		suffix, baseName := convertnhcb.GetHistogramMetricBaseName(metricName)
+		if suffix == convertnhcb.SuffixNone {
+			annos.Add(annotations.NewConvertToNativeHistogramFailedWarning(
+				metricName, errors.New("metric name has no _bucket, _sum or _count suffix"), args[0].PositionRange()))
+			continue
+		}
+
+		var upperBound float64
+		if suffix == convertnhcb.SuffixBucket {
+			var err error
+			upperBound, err = strconv.ParseFloat(sample.Metric.Get(model.BucketLabel), 64)
+			if err != nil {
+				annos.Add(annotations.NewBadBucketLabelWarning(metricName, sample.Metric.Get(model.BucketLabel), args[0].PositionRange()))
+				continue
+			}
+		}
+
+		// The signature must be the same for all series of one classic
+		// histogram, so it excludes both the metric name and the le label,
+		// with the base name appended to keep different histograms apart.
+		enh.lblBuf = sample.Metric.BytesWithoutLabels(enh.lblBuf, excludeBoth...)
+		enh.lblBuf = append(enh.lblBuf, baseName...)
+		group, ok := sigToGroup[string(enh.lblBuf)]
+		if !ok {
+			group = &tempHistogramGroup{
+				metric: convertnhcb.GetHistogramMetricBase(sample.Metric, baseName),
+				th:     convertnhcb.NewTempHistogram(),
+			}
+			sigToGroup[string(enh.lblBuf)] = group
+			groups = append(groups, group)
+		}
+		// Errors are sticky in TempHistogram and surface in Convert below.
+		switch suffix {
+		case convertnhcb.SuffixBucket:
+			_ = group.th.SetBucketCount(upperBound, sample.F)
+		case convertnhcb.SuffixCount:
+			_ = group.th.SetCount(sample.F)
+		case convertnhcb.SuffixSum:
+			_ = group.th.SetSum(sample.F)
+		}
+	}
+
+	for _, group := range groups {
+		if nativeSigs != nil {
+			enh.lblBuf = group.metric.Bytes(enh.lblBuf)
+			if _, ok := nativeSigs[string(enh.lblBuf)]; ok {
+				// A native histogram with the same labels is already in the
+				// output, so drop the converted classic histogram.
+				annos.Add(annotations.NewMixedClassicNativeHistogramsWarning(getMetricName(group.metric), args[0].PositionRange()))
+				continue
+			}
+		}
+		h, fh, err := group.th.Convert()
+		if err != nil {
+			annos.Add(annotations.NewConvertToNativeHistogramFailedWarning(getMetricName(group.metric), err, args[0].PositionRange()))
+			continue
+		}
+		if h != nil {
+			fh = h.ToFloat(nil)
+		}
+		enh.Out = append(enh.Out, Sample{
+			Metric: group.metric,
+			H:      fh,
+		})
+	}
+
+	return enh.Out, annos
+}
+
+// === convert_to_classic_histogram(Vector parser.ValueTypeVector) (Vector, Annotations) ===
+func funcConvertToClassicHistogram(vectorVals []Vector, _ Matrix, args parser.Expressions, enh *EvalNodeHelper) (Vector, annotations.Annotations) {
+	inVec := vectorVals[0]
+	var annos annotations.Annotations
+
+	for _, sample := range inVec {
+		if sample.H == nil {
+			// Already a float sample, pass it through unchanged.
+			enh.Out = append(enh.Out, sample)
+			continue
+		}
+		enh.resetBuilder(sample.Metric)
+		err := histogram.ConvertNHCBToClassic(sample.H, sample.Metric, enh.lb, func(lset labels.Labels, value float64) error {
+			enh.Out = append(enh.Out, Sample{
+				Metric: lset,
+				F:      value,
+			})
+			return nil
+		})
+		if err != nil {
+			// ConvertNHCBToClassic rejects the histogram before emitting any
+			// series, so a failed sample never leaves partial output behind.
+			annos.Add(annotations.NewConvertToClassicHistogramFailedWarning(getMetricName(sample.Metric), err, args[0].PositionRange()))
+		}
+	}
+
+	return enh.Out, annos
+}
+
 // pickFirstSampleIndices returns the start indices into the floats and
 // histograms slices for anchored range processing. The anchor is the single
 // most recent sample at or before the range start, regardless of type; it is
@@ -2570,6 +2811,88 @@ func (ev *evaluator) evalLabelJoin(ctx context.Context, args parser.Expressions)
 	return ev.mergeSeriesWithSameLabelset(matrix), ws
 }
 
+// evalLabelTransform applies transform to the values of the given labels of
+// every series. Like label_replace and label_join it operates only on series;
+// it does not look at timestamps or values. Absent labels stay absent, and a
+// transformation result of the empty string drops the label.
+func (ev *evaluator) evalLabelTransform(ctx context.Context, name string, vector parser.Expr, labelArgs parser.Expressions, transform func(string) string) (parser.Value, annotations.Annotations) {
+	lbls := make([]string, len(labelArgs))
+	for i, arg := range labelArgs {
+		l := stringFromArg(arg)
+		if !model.UTF8Validation.IsValidLabelName(l) {
+			panic(fmt.Errorf("invalid label name in %s(): %s", name, l))
+		}
+		lbls[i] = l
+	}
+
+	val, ws := ev.eval(ctx, vector)
+	matrix := val.(Matrix)
+	lb := labels.NewBuilder(labels.EmptyLabels())
+
+	for i, el := range matrix {
+		lb.Reset(el.Metric)
+		changed := false
+		dropName := el.DropName
+		for _, l := range lbls {
+			cur := el.Metric.Get(l)
+			if cur == "" {
+				continue
+			}
+			res := transform(cur)
+			if res == cur {
+				continue
+			}
+			lb.Set(l, res)
+			changed = true
+			if l == model.MetricNameLabel {
+				dropName = false
+			}
+		}
+		if changed {
+			matrix[i].Metric = lb.Labels()
+			matrix[i].DropName = dropName
+		}
+	}
+
+	return ev.mergeSeriesWithSameLabelset(matrix), ws
+}
+
+// label_lower function operates only on series; does not look at timestamps or values.
+func (ev *evaluator) evalLabelLower(ctx context.Context, args parser.Expressions) (parser.Value, annotations.Annotations) {
+	return ev.evalLabelTransform(ctx, "label_lower", args[0], args[1:], strings.ToLower)
+}
+
+// label_upper function operates only on series; does not look at timestamps or values.
+func (ev *evaluator) evalLabelUpper(ctx context.Context, args parser.Expressions) (parser.Value, annotations.Annotations) {
+	return ev.evalLabelTransform(ctx, "label_upper", args[0], args[1:], strings.ToUpper)
+}
+
+// label_trim function operates only on series; does not look at timestamps or values.
+func (ev *evaluator) evalLabelTrim(ctx context.Context, args parser.Expressions) (parser.Value, annotations.Annotations) {
+	cutset := stringFromArg(args[1])
+	transform := strings.TrimSpace
+	if cutset != "" {
+		transform = func(s string) string { return strings.Trim(s, cutset) }
+	}
+	return ev.evalLabelTransform(ctx, "label_trim", args[0], args[2:], transform)
+}
+
+// label_substr function operates only on series; does not look at timestamps or values.
+func (ev *evaluator) evalLabelSubstr(ctx context.Context, args parser.Expressions) (parser.Value, annotations.Annotations) {
+	start := int(numberFromArg(args[1], "label_substr"))
+	end := int(numberFromArg(args[2], "label_substr"))
+	transform := func(s string) string {
+		r := []rune(s)
+		lo := max(start, 0)
+		hi := min(end, len(r))
+		if lo >= hi {
+			return ""
+		}
+		return string(r[lo:hi])
+	}
+	return ev.evalLabelTransform(ctx, "label_substr", args[0], args[3:], transform)
+}
+
 // Common code for date related functions.
 func dateWrapper(vectorVals []Vector, enh *EvalNodeHelper, f func(time.Time) float64) Vector {
 	if len(vectorVals) == 0 {
@@ -2666,11 +2989,16 @@ var FunctionCalls = map[string]FunctionCall{
 	"atan":                         funcAtan,
 	"atanh":                        funcAtanh,
 	"avg_over_time":                funcAvgOverTime,
+	"bitand":                       funcBitand,
+	"bitor":                        funcBitor,
+	"bitshift":                     funcBitshift,
 	"ceil":                         funcCeil,
 	"changes":                      funcChanges,
 	"clamp":                        funcClamp,
 	"clamp_max":                    funcClampMax,
 	"clamp_min":                    funcClampMin,
+	"convert_to_classic_histogram": funcConvertToClassicHistogram,
+	"convert_to_native_histogram":  funcConvertToNativeHistogram,
 	"cos":                          funcCos,
 	"cosh":                         funcCosh,
 	"count_over_time":              funcCountOverTime,
@@ -2685,6 +3013,7 @@ var FunctionCalls = map[string]FunctionCall{
 	"exp":                          funcExp,
 	"first_over_time":              funcFirstOverTime,
 	"floor":                        funcFloor,
+	"from_hexstring":               funcFromHexstring,
 	"histogram_avg":                funcHistogramAvg,
 	"histogram_count":              funcHistogramCount,
 	"histogram_fraction":           funcHistogramFraction,
@@ -2702,6 +3031,10 @@ var FunctionCalls = map[string]FunctionCall{
 	"max_of":                       funcMaxOf,
 	"label_replace":                nil, // evalLabelReplace not called via this map.
 	"label_join":                   nil, // evalLabelJoin not called via this map.
+	"label_lower":                  nil, // evalLabelLower not called via this map.
+	"label_upper":                  nil, // evalLabelUpper not called via this map.
+	"label_trim":                   nil, // evalLabelTrim not called via this map.
+	"label_substr":                 nil, // evalLabelSubstr not called via this map.
 	"min_of":                       funcMinOf,
 	"ln":                           funcLn,
 	"log10":                        funcLog10,
@@ -2881,6 +3214,16 @@ func stringFromArg(e parser.Expr) string {
 	return e.(*parser.StringLiteral).Val
 }
 
+// numberFromArg returns the value of a number literal argument. Arguments to
+// series-level functions must be constant since labels cannot vary per step.
+func numberFromArg(e parser.Expr, funcName string) float64 {
+	n, ok := e.(*parser.NumberLiteral)
+	if !ok {
+		panic(fmt.Errorf("expected a number literal argument in %s(), got %s", funcName, e.String()))
+	}
+	return n.Val
+}
+
 func stringSliceFromArgs(args parser.Expressions) []string {
 	tmp := make([]string, len(args))
 	for i := range args {
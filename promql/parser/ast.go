@@ -222,6 +222,10 @@ type VectorSelector struct {
 	UnexpandedSeriesSet storage.SeriesSet
 	Series              []storage.Series
 
+	// HintsApplied is set during evaluation when the storage reported that
+	// it already applied the pushed-down select hints to the returned series.
+	HintsApplied bool
+
 	// BypassEmptyMatcherCheck is true when the VectorSelector isn't required to have at least one matcher matching the empty string.
 	// This is the case when VectorSelector is used to represent the info function's second argument.
 	BypassEmptyMatcherCheck bool
@@ -231,6 +235,10 @@ type VectorSelector struct {
 	// Smoothed is true when the VectorSelector is smoothed.
 	Smoothed bool
 
+	// Lookback overrides the query's lookback delta for this selector when
+	// greater than zero. It is set by the lookback modifier.
+	Lookback time.Duration
+
 	PosRange posrange.PositionRange
 }
 
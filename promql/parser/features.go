@@ -27,6 +27,8 @@ func (pql *promQLParser) RegisterFeatures(r features.Collector) {
 				r.Set(features.PromQL, keyword, pql.options.EnableExtendedRangeSelectors)
 			case "fill", "fill_left", "fill_right":
 				r.Set(features.PromQL, keyword, pql.options.EnableBinopFillModifiers)
+			case "lookback":
+				r.Set(features.PromQL, keyword, pql.options.EnableLookbackModifier)
 			default:
 				r.Enable(features.PromQL, keyword)
 			}
@@ -75,6 +75,24 @@ var Functions = map[string]*Function{
 		ArgTypes:   []ValueType{ValueTypeMatrix},
 		ReturnType: ValueTypeVector,
 	},
+	"bitand": {
+		Name:         "bitand",
+		ArgTypes:     []ValueType{ValueTypeVector, ValueTypeScalar},
+		ReturnType:   ValueTypeVector,
+		Experimental: true,
+	},
+	"bitor": {
+		Name:         "bitor",
+		ArgTypes:     []ValueType{ValueTypeVector, ValueTypeScalar},
+		ReturnType:   ValueTypeVector,
+		Experimental: true,
+	},
+	"bitshift": {
+		Name:         "bitshift",
+		ArgTypes:     []ValueType{ValueTypeVector, ValueTypeScalar},
+		ReturnType:   ValueTypeVector,
+		Experimental: true,
+	},
 	"ceil": {
 		Name:       "ceil",
 		ArgTypes:   []ValueType{ValueTypeVector},
@@ -100,6 +118,18 @@ var Functions = map[string]*Function{
 		ArgTypes:   []ValueType{ValueTypeVector, ValueTypeScalar},
 		ReturnType: ValueTypeVector,
 	},
+	"convert_to_classic_histogram": {
+		Name:         "convert_to_classic_histogram",
+		ArgTypes:     []ValueType{ValueTypeVector},
+		ReturnType:   ValueTypeVector,
+		Experimental: true,
+	},
+	"convert_to_native_histogram": {
+		Name:         "convert_to_native_histogram",
+		ArgTypes:     []ValueType{ValueTypeVector},
+		ReturnType:   ValueTypeVector,
+		Experimental: true,
+	},
 	"cos": {
 		Name:       "cos",
 		ArgTypes:   []ValueType{ValueTypeVector},
@@ -175,6 +205,12 @@ var Functions = map[string]*Function{
 		ArgTypes:   []ValueType{ValueTypeVector},
 		ReturnType: ValueTypeVector,
 	},
+	"from_hexstring": {
+		Name:         "from_hexstring",
+		ArgTypes:     []ValueType{ValueTypeVector, ValueTypeString},
+		ReturnType:   ValueTypeVector,
+		Experimental: true,
+	},
 	"histogram_avg": {
 		Name:       "histogram_avg",
 		ArgTypes:   []ValueType{ValueTypeVector},
@@ -262,6 +298,34 @@ var Functions = map[string]*Function{
 		Variadic:   -1,
 		ReturnType: ValueTypeVector,
 	},
+	"label_lower": {
+		Name:         "label_lower",
+		ArgTypes:     []ValueType{ValueTypeVector, ValueTypeString},
+		Variadic:     -1,
+		ReturnType:   ValueTypeVector,
+		Experimental: true,
+	},
+	"label_substr": {
+		Name:         "label_substr",
+		ArgTypes:     []ValueType{ValueTypeVector, ValueTypeScalar, ValueTypeScalar, ValueTypeString},
+		Variadic:     -1,
+		ReturnType:   ValueTypeVector,
+		Experimental: true,
+	},
+	"label_trim": {
+		Name:         "label_trim",
+		ArgTypes:     []ValueType{ValueTypeVector, ValueTypeString, ValueTypeString},
+		Variadic:     -1,
+		ReturnType:   ValueTypeVector,
+		Experimental: true,
+	},
+	"label_upper": {
+		Name:         "label_upper",
+		ArgTypes:     []ValueType{ValueTypeVector, ValueTypeString},
+		Variadic:     -1,
+		ReturnType:   ValueTypeVector,
+		Experimental: true,
+	},
 	"max_of": {
 		Name:         "max_of",
 		ArgTypes:     []ValueType{ValueTypeScalar, ValueTypeScalar},
@@ -132,6 +132,8 @@ SUM
 TOPK
 LIMITK
 LIMIT_RATIO
+MEDIAN
+MODE
 %token	aggregatorsEnd
 
 // Keywords.
@@ -145,6 +147,7 @@ FILL
 FILL_LEFT
 FILL_RIGHT
 IGNORING
+LOOKBACK
 OFFSET
 SMOOTHED
 ANCHORED
@@ -197,7 +200,7 @@ START_METRIC_SELECTOR
 %type <int> int
 %type <uint> uint
 %type <float> number series_value signed_number signed_or_unsigned_number
-%type <node> step_invariant_expr aggregate_expr aggregate_modifier bin_modifier fill_modifiers binary_expr bool_modifier expr function_call function_call_args function_call_body group_modifiers fill_value label_matchers matrix_selector number_duration_literal offset_expr anchored_expr smoothed_expr on_or_ignoring paren_expr string_literal subquery_expr unary_expr vector_selector duration_expr paren_duration_expr positive_duration_expr offset_duration_expr
+%type <node> step_invariant_expr aggregate_expr aggregate_modifier bin_modifier fill_modifiers binary_expr bool_modifier expr function_call function_call_args function_call_body group_modifiers fill_value label_matchers matrix_selector number_duration_literal offset_expr lookback_expr anchored_expr smoothed_expr on_or_ignoring paren_expr string_literal subquery_expr unary_expr vector_selector duration_expr paren_duration_expr positive_duration_expr offset_duration_expr
 
 %start start
 
@@ -209,8 +212,9 @@ START_METRIC_SELECTOR
 %left MUL DIV MOD ATAN2
 %right POW
 
-// Offset modifiers do not have associativity.
+// Offset and lookback modifiers do not have associativity.
 %nonassoc OFFSET
+%nonassoc LOOKBACK
 
 // This ensures that it is always attempted to parse range or subquery selectors when a left
 // bracket is encountered.
@@ -240,6 +244,7 @@ expr            :
                 | matrix_selector
                 | number_duration_literal
                 | offset_expr
+                | lookback_expr
                 | anchored_expr
                 | smoothed_expr
                 | paren_expr
@@ -606,6 +611,20 @@ offset_expr: expr OFFSET offset_duration_expr
                         { yylex.(*parser).unexpected("offset", "number, duration, step(), or range()"); $$ = $1 }
                 ;
 
+/*
+ * Lookback modifier.
+ */
+
+lookback_expr: expr LOOKBACK number_duration_literal
+                        {
+                        numLit, _ := $3.(*NumberLiteral)
+                        yylex.(*parser).setLookback($1, time.Duration(math.Round(numLit.Val*float64(time.Second))))
+                        $$ = $1
+                        }
+                | expr LOOKBACK error
+                        { yylex.(*parser).unexpected("lookback", "duration"); $$ = $1 }
+                ;
+
 /*
  * Anchored and smoothed modifiers
  */
@@ -654,6 +673,8 @@ matrix_selector : expr LEFT_BRACKET positive_duration_expr RIGHT_BRACKET
                                 errMsg = "no offset modifiers allowed before range"
                         } else if vs.Timestamp != nil {
                                 errMsg = "no @ modifiers allowed before range"
+                        } else if vs.Lookback != 0 {
+                                errMsg = "no lookback modifiers allowed before range"
                         }
 
                         if errMsg != ""{
@@ -834,7 +855,7 @@ metric          : metric_identifier label_set
                 ;
 
 
-metric_identifier: AVG | BOTTOMK | BY | COUNT | COUNT_VALUES | FILL | FILL_LEFT | FILL_RIGHT | GROUP | IDENTIFIER |  LAND | LOR | LUNLESS | MAX | METRIC_IDENTIFIER | MIN | OFFSET | QUANTILE | STDDEV | STDVAR | SUM | TOPK | WITHOUT | START | END | LIMITK | LIMIT_RATIO | STEP | RANGE | ANCHORED | SMOOTHED | MAX_OF | MIN_OF;
+metric_identifier: AVG | BOTTOMK | BY | COUNT | COUNT_VALUES | FILL | FILL_LEFT | FILL_RIGHT | GROUP | IDENTIFIER |  LAND | LOOKBACK | LOR | LUNLESS | MAX | METRIC_IDENTIFIER | MIN | OFFSET | QUANTILE | STDDEV | STDVAR | SUM | TOPK | WITHOUT | START | END | LIMITK | LIMIT_RATIO | MEDIAN | MODE | STEP | RANGE | ANCHORED | SMOOTHED | MAX_OF | MIN_OF;
 
 label_set       : LEFT_BRACE label_set_list RIGHT_BRACE
                         { $$ = labels.New($2...) }
@@ -898,47 +919,30 @@ series_values   : /*empty*/
                 ;
 
 series_item     : BLANK
-                        { $$ = []SequenceValue{{Omitted: true}}}
+                        { $$ = yylex.(*parser).seriesRepeat(SequenceValue{Omitted: true}, 1) }
                 | BLANK TIMES uint
-                        {
-                        $$ = []SequenceValue{}
-                        for i:=uint64(0); i < $3; i++{
-                                $$ = append($$, SequenceValue{Omitted: true})
-                        }
-                        }
+                        { $$ = yylex.(*parser).seriesRepeat(SequenceValue{Omitted: true}, $3) }
                 | series_value
-                        { $$ = []SequenceValue{{Value: $1}}}
+                        { $$ = yylex.(*parser).seriesRepeat(SequenceValue{Value: $1}, 1) }
                 | series_value TIMES uint
                         {
-                        $$ = []SequenceValue{}
                         // Add an additional value for time 0, which we ignore in tests.
-                        for i:=uint64(0); i <= $3; i++{
-                                $$ = append($$, SequenceValue{Value: $1})
-                        }
+                        $$ = yylex.(*parser).seriesRepeat(SequenceValue{Value: $1}, $3+1)
                         }
                 | series_value signed_number TIMES uint
                         {
-                        $$ = []SequenceValue{}
                         // Add an additional value for time 0, which we ignore in tests.
-                        for i:=uint64(0); i <= $4; i++{
-                                $$ = append($$, SequenceValue{Value: $1})
-                                $1 += $2
-                        }
+                        $$ = yylex.(*parser).seriesSteps($1, $2, $4)
                         }
                 // Histogram descriptions (part of unit testing).
                 | histogram_series_value
                         {
-                        $$ = []SequenceValue{yylex.(*parser).newHistogramSequenceValue($1)}
+                        $$ = yylex.(*parser).seriesRepeat(yylex.(*parser).newHistogramSequenceValue($1), 1)
                         }
                 | histogram_series_value TIMES uint
                         {
-                        $$ = []SequenceValue{}
                         // Add an additional value for time 0, which we ignore in tests.
-                        sv := yylex.(*parser).newHistogramSequenceValue($1)
-                        for i:=uint64(0); i <= $3; i++{
-                                $$ = append($$, sv)
-                                //$1 += $2
-                        }
+                        $$ = yylex.(*parser).seriesRepeat(yylex.(*parser).newHistogramSequenceValue($1), $3+1)
                         }
                 | histogram_series_value ADD histogram_series_value TIMES uint
                         {
@@ -1089,10 +1093,10 @@ counter_reset_hint : UNKNOWN_COUNTER_RESET | COUNTER_RESET | NOT_COUNTER_RESET |
  * Keyword lists.
  */
 
-aggregate_op    : AVG | BOTTOMK | COUNT | COUNT_VALUES | GROUP | MAX | MIN | QUANTILE | STDDEV | STDVAR | SUM | TOPK | LIMITK | LIMIT_RATIO;
+aggregate_op    : AVG | BOTTOMK | COUNT | COUNT_VALUES | GROUP | MAX | MEDIAN | MIN | MODE | QUANTILE | STDDEV | STDVAR | SUM | TOPK | LIMITK | LIMIT_RATIO;
 
 // Inside of grouping options label names can be recognized as keywords by the lexer. This is a list of keywords that could also be a label name.
-maybe_label     : AVG | BOOL | BOTTOMK | BY | COUNT | COUNT_VALUES | GROUP | GROUP_LEFT | GROUP_RIGHT | FILL | FILL_LEFT | FILL_RIGHT | IDENTIFIER | IGNORING | LAND | LOR | LUNLESS | MAX | METRIC_IDENTIFIER | MIN | OFFSET | ON | QUANTILE | STDDEV | STDVAR | SUM | TOPK | START | END | ATAN2 | LIMITK | LIMIT_RATIO | STEP | RANGE | ANCHORED | SMOOTHED | MAX_OF | MIN_OF;
+maybe_label     : AVG | BOOL | BOTTOMK | BY | COUNT | COUNT_VALUES | GROUP | GROUP_LEFT | GROUP_RIGHT | FILL | FILL_LEFT | FILL_RIGHT | IDENTIFIER | IGNORING | LAND | LOOKBACK | LOR | LUNLESS | MAX | METRIC_IDENTIFIER | MIN | OFFSET | ON | QUANTILE | STDDEV | STDVAR | SUM | TOPK | START | END | ATAN2 | LIMITK | LIMIT_RATIO | MEDIAN | MODE | STEP | RANGE | ANCHORED | SMOOTHED | MAX_OF | MIN_OF;
 
 unary_op        : ADD | SUB;
 
@@ -109,42 +109,45 @@ const SUM = 57416
 const TOPK = 57417
 const LIMITK = 57418
 const LIMIT_RATIO = 57419
-const aggregatorsEnd = 57420
-const keywordsStart = 57421
-const BOOL = 57422
-const BY = 57423
-const GROUP_LEFT = 57424
-const GROUP_RIGHT = 57425
-const FILL = 57426
-const FILL_LEFT = 57427
-const FILL_RIGHT = 57428
-const IGNORING = 57429
-const OFFSET = 57430
-const SMOOTHED = 57431
-const ANCHORED = 57432
-const ON = 57433
-const WITHOUT = 57434
-const keywordsEnd = 57435
-const preprocessorStart = 57436
-const START = 57437
-const END = 57438
-const STEP = 57439
-const RANGE = 57440
-const MAX_OF = 57441
-const MIN_OF = 57442
-const preprocessorEnd = 57443
-const counterResetHintsStart = 57444
-const UNKNOWN_COUNTER_RESET = 57445
-const COUNTER_RESET = 57446
-const NOT_COUNTER_RESET = 57447
-const GAUGE_TYPE = 57448
-const counterResetHintsEnd = 57449
-const startSymbolsStart = 57450
-const START_METRIC = 57451
-const START_SERIES_DESCRIPTION = 57452
-const START_EXPRESSION = 57453
-const START_METRIC_SELECTOR = 57454
-const startSymbolsEnd = 57455
+const MEDIAN = 57420
+const MODE = 57421
+const aggregatorsEnd = 57422
+const keywordsStart = 57423
+const BOOL = 57424
+const BY = 57425
+const GROUP_LEFT = 57426
+const GROUP_RIGHT = 57427
+const FILL = 57428
+const FILL_LEFT = 57429
+const FILL_RIGHT = 57430
+const IGNORING = 57431
+const LOOKBACK = 57432
+const OFFSET = 57433
+const SMOOTHED = 57434
+const ANCHORED = 57435
+const ON = 57436
+const WITHOUT = 57437
+const keywordsEnd = 57438
+const preprocessorStart = 57439
+const START = 57440
+const END = 57441
+const STEP = 57442
+const RANGE = 57443
+const MAX_OF = 57444
+const MIN_OF = 57445
+const preprocessorEnd = 57446
+const counterResetHintsStart = 57447
+const UNKNOWN_COUNTER_RESET = 57448
+const COUNTER_RESET = 57449
+const NOT_COUNTER_RESET = 57450
+const GAUGE_TYPE = 57451
+const counterResetHintsEnd = 57452
+const startSymbolsStart = 57453
+const START_METRIC = 57454
+const START_SERIES_DESCRIPTION = 57455
+const START_EXPRESSION = 57456
+const START_METRIC_SELECTOR = 57457
+const startSymbolsEnd = 57458
 
 var yyToknames = [...]string{
 	"$end",
@@ -224,6 +227,8 @@ var yyToknames = [...]string{
 	"TOPK",
 	"LIMITK",
 	"LIMIT_RATIO",
+	"MEDIAN",
+	"MODE",
 	"aggregatorsEnd",
 	"keywordsStart",
 	"BOOL",
@@ -234,6 +239,7 @@ var yyToknames = [...]string{
 	"FILL_LEFT",
 	"FILL_RIGHT",
 	"IGNORING",
+	"LOOKBACK",
 	"OFFSET",
 	"SMOOTHED",
 	"ANCHORED",
@@ -272,584 +278,622 @@ var yyExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 46,
-	1, 169,
-	10, 169,
-	24, 169,
+	-1, 49,
+	1, 175,
+	10, 175,
+	24, 175,
 	-2, 0,
-	-1, 78,
-	2, 212,
-	15, 212,
-	81, 212,
-	92, 212,
-	-2, 121,
-	-1, 79,
-	2, 213,
-	15, 213,
-	81, 213,
-	92, 213,
-	-2, 122,
-	-1, 80,
-	2, 214,
-	15, 214,
-	81, 214,
-	92, 214,
-	-2, 124,
-	-1, 81,
-	2, 215,
-	15, 215,
-	81, 215,
-	92, 215,
-	-2, 125,
 	-1, 82,
-	2, 216,
-	15, 216,
-	81, 216,
-	92, 216,
-	-2, 129,
-	-1, 83,
-	2, 217,
-	15, 217,
-	81, 217,
-	92, 217,
-	-2, 134,
-	-1, 84,
 	2, 218,
 	15, 218,
-	81, 218,
-	92, 218,
-	-2, 136,
-	-1, 85,
+	83, 218,
+	95, 218,
+	-2, 124,
+	-1, 83,
 	2, 219,
 	15, 219,
-	81, 219,
-	92, 219,
-	-2, 138,
-	-1, 86,
+	83, 219,
+	95, 219,
+	-2, 125,
+	-1, 84,
 	2, 220,
 	15, 220,
-	81, 220,
-	92, 220,
-	-2, 139,
-	-1, 87,
+	83, 220,
+	95, 220,
+	-2, 127,
+	-1, 85,
 	2, 221,
 	15, 221,
-	81, 221,
-	92, 221,
-	-2, 140,
-	-1, 88,
+	83, 221,
+	95, 221,
+	-2, 128,
+	-1, 86,
 	2, 222,
 	15, 222,
-	81, 222,
-	92, 222,
-	-2, 141,
-	-1, 89,
+	83, 222,
+	95, 222,
+	-2, 132,
+	-1, 87,
 	2, 223,
 	15, 223,
-	81, 223,
-	92, 223,
-	-2, 142,
-	-1, 90,
+	83, 223,
+	95, 223,
+	-2, 138,
+	-1, 88,
 	2, 224,
 	15, 224,
-	81, 224,
-	92, 224,
-	-2, 146,
-	-1, 91,
+	83, 224,
+	95, 224,
+	-2, 152,
+	-1, 89,
 	2, 225,
 	15, 225,
-	81, 225,
-	92, 225,
-	-2, 147,
+	83, 225,
+	95, 225,
+	-2, 140,
+	-1, 90,
+	2, 226,
+	15, 226,
+	83, 226,
+	95, 226,
+	-2, 153,
+	-1, 91,
+	2, 227,
+	15, 227,
+	83, 227,
+	95, 227,
+	-2, 142,
 	-1, 92,
-	15, 93,
-	-2, 144,
+	2, 228,
+	15, 228,
+	83, 228,
+	95, 228,
+	-2, 143,
 	-1, 93,
-	15, 94,
-	-2, 145,
+	2, 229,
+	15, 229,
+	83, 229,
+	95, 229,
+	-2, 144,
 	-1, 94,
-	15, 295,
-	-2, 152,
+	2, 230,
+	15, 230,
+	83, 230,
+	95, 230,
+	-2, 145,
 	-1, 95,
-	15, 296,
-	-2, 153,
-	-1, 156,
-	41, 298,
-	42, 298,
-	54, 298,
-	55, 298,
-	59, 298,
-	-2, 22,
-	-1, 274,
-	9, 283,
-	12, 283,
-	13, 283,
-	18, 283,
-	19, 283,
-	25, 283,
-	41, 283,
-	49, 283,
-	50, 283,
-	53, 283,
-	59, 283,
-	64, 283,
-	65, 283,
-	66, 283,
-	67, 283,
-	68, 283,
-	69, 283,
-	70, 283,
-	71, 283,
-	72, 283,
-	73, 283,
-	74, 283,
-	75, 283,
-	76, 283,
-	77, 283,
-	81, 283,
-	84, 283,
-	85, 283,
-	86, 283,
-	88, 283,
-	89, 283,
-	90, 283,
-	92, 283,
-	95, 283,
-	96, 283,
-	97, 283,
-	98, 283,
-	99, 283,
-	100, 283,
+	2, 231,
+	15, 231,
+	83, 231,
+	95, 231,
+	-2, 146,
+	-1, 96,
+	2, 232,
+	15, 232,
+	83, 232,
+	95, 232,
+	-2, 150,
+	-1, 97,
+	2, 233,
+	15, 233,
+	83, 233,
+	95, 233,
+	-2, 151,
+	-1, 98,
+	15, 96,
+	-2, 148,
+	-1, 99,
+	15, 97,
+	-2, 149,
+	-1, 100,
+	15, 306,
+	-2, 158,
+	-1, 101,
+	15, 307,
+	-2, 159,
+	-1, 163,
+	41, 309,
+	42, 309,
+	54, 309,
+	55, 309,
+	59, 309,
+	-2, 23,
+	-1, 283,
+	9, 294,
+	12, 294,
+	13, 294,
+	18, 294,
+	19, 294,
+	25, 294,
+	41, 294,
+	49, 294,
+	50, 294,
+	53, 294,
+	59, 294,
+	64, 294,
+	65, 294,
+	66, 294,
+	67, 294,
+	68, 294,
+	69, 294,
+	70, 294,
+	71, 294,
+	72, 294,
+	73, 294,
+	74, 294,
+	75, 294,
+	76, 294,
+	77, 294,
+	78, 294,
+	79, 294,
+	83, 294,
+	86, 294,
+	87, 294,
+	88, 294,
+	90, 294,
+	91, 294,
+	92, 294,
+	93, 294,
+	95, 294,
+	98, 294,
+	99, 294,
+	100, 294,
+	101, 294,
+	102, 294,
+	103, 294,
 	-2, 0,
-	-1, 275,
-	9, 283,
-	12, 283,
-	13, 283,
-	18, 283,
-	19, 283,
-	25, 283,
-	41, 283,
-	49, 283,
-	50, 283,
-	53, 283,
-	59, 283,
-	64, 283,
-	65, 283,
-	66, 283,
-	67, 283,
-	68, 283,
-	69, 283,
-	70, 283,
-	71, 283,
-	72, 283,
-	73, 283,
-	74, 283,
-	75, 283,
-	76, 283,
-	77, 283,
-	81, 283,
-	84, 283,
-	85, 283,
-	86, 283,
-	88, 283,
-	89, 283,
-	90, 283,
-	92, 283,
-	95, 283,
-	96, 283,
-	97, 283,
-	98, 283,
-	99, 283,
-	100, 283,
+	-1, 284,
+	9, 294,
+	12, 294,
+	13, 294,
+	18, 294,
+	19, 294,
+	25, 294,
+	41, 294,
+	49, 294,
+	50, 294,
+	53, 294,
+	59, 294,
+	64, 294,
+	65, 294,
+	66, 294,
+	67, 294,
+	68, 294,
+	69, 294,
+	70, 294,
+	71, 294,
+	72, 294,
+	73, 294,
+	74, 294,
+	75, 294,
+	76, 294,
+	77, 294,
+	78, 294,
+	79, 294,
+	83, 294,
+	86, 294,
+	87, 294,
+	88, 294,
+	90, 294,
+	91, 294,
+	92, 294,
+	93, 294,
+	95, 294,
+	98, 294,
+	99, 294,
+	100, 294,
+	101, 294,
+	102, 294,
+	103, 294,
 	-2, 0,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 1439
+const yyLast = 1561
 
 var yyAct = [...]int16{
-	63, 386, 205, 455, 374, 457, 314, 208, 263, 216,
-	462, 53, 209, 105, 160, 6, 103, 438, 141, 439,
-	66, 392, 74, 247, 132, 140, 139, 469, 470, 471,
-	472, 144, 242, 207, 142, 69, 176, 393, 272, 132,
-	213, 415, 273, 132, 76, 143, 269, 270, 271, 138,
-	212, 110, 112, 113, 452, 114, 115, 116, 117, 118,
-	119, 120, 121, 122, 123, 124, 125, 112, 126, 127,
-	131, 111, 214, 154, 451, 156, 274, 275, 213, 123,
-	124, 456, 489, 126, 417, 131, 111, 126, 212, 131,
-	215, 145, 146, 147, 149, 151, 365, 142, 128, 130,
-	129, 488, 238, 170, 487, 484, 357, 48, 143, 364,
-	214, 473, 162, 128, 130, 129, 441, 128, 130, 129,
-	157, 356, 2, 3, 4, 5, 210, 211, 215, 201,
-	440, 362, 363, 218, 224, 225, 226, 227, 228, 229,
-	196, 407, 406, 259, 219, 219, 219, 219, 219, 219,
-	219, 197, 206, 241, 361, 220, 220, 220, 220, 220,
-	220, 220, 402, 415, 200, 230, 258, 231, 223, 223,
-	223, 223, 223, 223, 223, 153, 400, 155, 265, 71,
-	232, 235, 255, 266, 399, 204, 253, 257, 261, 70,
-	132, 136, 213, 267, 446, 264, 213, 423, 298, 267,
-	463, 424, 212, 137, 135, 302, 212, 138, 491, 293,
-	256, 96, 422, 445, 387, 397, 219, 110, 112, 421,
-	220, 298, 304, 305, 214, 490, 396, 220, 214, 97,
-	123, 124, 219, 296, 126, 127, 131, 111, 368, 71,
-	223, 213, 215, 220, 133, 136, 215, 98, 395, 70,
-	234, 212, 311, 369, 310, 309, 223, 137, 135, 162,
-	360, 138, 134, 233, 128, 130, 129, 221, 222, 202,
-	203, 370, 388, 389, 268, 367, 303, 301, 300, 299,
-	276, 277, 278, 279, 280, 281, 282, 283, 284, 285,
-	286, 287, 288, 289, 290, 291, 292, 394, 398, 249,
-	144, 152, 401, 366, 150, 148, 44, 7, 218, 219,
-	390, 391, 408, 219, 133, 136, 71, 403, 214, 219,
-	220, 486, 297, 219, 220, 308, 70, 137, 135, 307,
-	220, 138, 134, 223, 220, 71, 215, 223, 435, 251,
-	133, 136, 434, 223, 306, 70, 433, 223, 96, 477,
-	246, 250, 252, 137, 135, 245, 405, 138, 134, 413,
-	45, 432, 431, 71, 430, 429, 97, 96, 244, 204,
-	219, 414, 416, 70, 418, 404, 10, 428, 419, 420,
-	248, 220, 249, 133, 136, 97, 100, 427, 426, 425,
-	168, 169, 167, 163, 223, 96, 137, 135, 442, 436,
-	138, 134, 171, 161, 294, 295, 202, 203, 61, 219,
-	437, 359, 109, 97, 99, 1, 107, 448, 194, 411,
-	220, 8, 251, 450, 410, 46, 454, 77, 60, 458,
-	459, 460, 59, 223, 250, 252, 465, 461, 467, 409,
-	475, 476, 464, 75, 466, 478, 9, 9, 58, 474,
-	449, 221, 222, 202, 203, 57, 219, 479, 480, 358,
-	412, 106, 481, 175, 56, 55, 483, 220, 54, 133,
-	136, 104, 52, 173, 236, 51, 174, 50, 485, 254,
-	223, 163, 137, 135, 107, 317, 138, 134, 172, 219,
-	492, 161, 49, 133, 136, 330, 62, 262, 482, 159,
-	220, 336, 371, 108, 107, 447, 137, 135, 316, 260,
-	138, 134, 312, 223, 177, 178, 179, 180, 181, 182,
-	183, 184, 185, 186, 187, 188, 189, 190, 191, 192,
-	193, 101, 332, 333, 468, 315, 334, 64, 158, 0,
-	0, 0, 444, 0, 347, 0, 0, 318, 320, 322,
-	323, 324, 335, 337, 340, 341, 342, 343, 344, 348,
-	349, 0, 0, 319, 321, 325, 326, 327, 328, 329,
-	331, 338, 353, 352, 339, 317, 133, 136, 345, 346,
-	350, 351, 354, 355, 443, 330, 0, 106, 0, 137,
-	135, 336, 243, 138, 134, 313, 166, 104, 316, 0,
-	0, 165, 0, 133, 136, 102, 0, 0, 0, 0,
-	107, 133, 136, 0, 164, 0, 137, 135, 0, 0,
-	138, 134, 332, 333, 137, 135, 334, 0, 138, 134,
-	0, 0, 0, 0, 347, 0, 0, 318, 320, 322,
-	323, 324, 335, 337, 340, 341, 342, 343, 344, 348,
-	349, 0, 0, 319, 321, 325, 326, 327, 328, 329,
-	331, 338, 353, 352, 339, 0, 0, 0, 345, 346,
-	350, 351, 354, 355, 71, 0, 0, 65, 98, 0,
-	72, 0, 0, 25, 70, 0, 0, 237, 217, 0,
-	73, 0, 0, 0, 0, 71, 0, 0, 0, 0,
-	0, 204, 0, 0, 0, 70, 96, 0, 0, 0,
-	0, 0, 0, 0, 21, 22, 0, 0, 23, 0,
-	0, 0, 0, 0, 97, 0, 0, 96, 0, 78,
-	79, 80, 81, 82, 83, 84, 85, 86, 87, 88,
-	89, 90, 91, 0, 0, 97, 13, 0, 0, 16,
-	17, 18, 0, 27, 41, 40, 0, 33, 0, 0,
-	92, 93, 67, 68, 94, 95, 71, 0, 0, 65,
-	98, 0, 72, 0, 0, 25, 70, 0, 0, 240,
-	195, 0, 73, 221, 222, 202, 203, 71, 0, 0,
-	0, 0, 0, 204, 0, 0, 0, 70, 96, 0,
-	0, 0, 0, 0, 0, 0, 21, 22, 0, 0,
-	23, 0, 0, 0, 0, 0, 97, 0, 0, 96,
-	0, 78, 79, 80, 81, 82, 83, 84, 85, 86,
-	87, 88, 89, 90, 91, 0, 0, 97, 13, 0,
-	0, 16, 17, 18, 0, 27, 41, 40, 0, 33,
-	0, 0, 92, 93, 67, 68, 94, 95, 71, 0,
-	0, 65, 98, 0, 72, 0, 0, 25, 70, 0,
-	0, 239, 0, 0, 73, 198, 199, 202, 203, 0,
+	67, 214, 398, 469, 467, 386, 474, 323, 272, 225,
+	404, 405, 56, 217, 218, 111, 167, 109, 256, 6,
+	481, 482, 483, 484, 78, 147, 146, 139, 149, 450,
+	451, 216, 139, 148, 70, 251, 73, 281, 222, 80,
+	150, 183, 282, 278, 279, 280, 151, 145, 221, 283,
+	284, 464, 427, 463, 116, 118, 119, 496, 120, 121,
+	122, 123, 124, 125, 126, 127, 128, 129, 130, 131,
+	223, 132, 133, 138, 117, 222, 132, 161, 138, 163,
+	270, 429, 377, 75, 222, 221, 177, 273, 224, 376,
+	374, 276, 468, 74, 221, 152, 153, 154, 156, 158,
+	268, 485, 501, 135, 134, 137, 136, 223, 135, 134,
+	137, 136, 375, 373, 149, 102, 223, 423, 453, 164,
+	169, 500, 422, 267, 499, 224, 150, 219, 220, 2,
+	3, 4, 5, 103, 224, 208, 369, 421, 452, 419,
+	227, 233, 234, 235, 236, 237, 238, 203, 212, 418,
+	414, 368, 228, 228, 228, 228, 228, 228, 228, 204,
+	250, 241, 244, 489, 229, 229, 229, 229, 229, 229,
+	229, 207, 239, 215, 240, 458, 232, 232, 232, 232,
+	232, 232, 232, 412, 411, 75, 140, 143, 262, 417,
+	264, 274, 275, 427, 457, 74, 75, 140, 143, 144,
+	142, 380, 211, 145, 141, 307, 74, 502, 416, 276,
+	144, 142, 311, 222, 145, 141, 381, 302, 378, 143,
+	436, 399, 409, 221, 228, 503, 140, 143, 102, 229,
+	307, 144, 142, 223, 382, 145, 229, 313, 314, 144,
+	142, 305, 228, 145, 141, 223, 103, 435, 232, 222,
+	266, 224, 408, 407, 229, 104, 320, 170, 475, 221,
+	317, 226, 434, 224, 316, 498, 232, 168, 75, 433,
+	169, 372, 461, 265, 211, 371, 319, 318, 74, 315,
+	113, 312, 400, 401, 140, 143, 379, 230, 231, 209,
+	210, 140, 143, 402, 403, 406, 310, 144, 142, 202,
+	102, 145, 141, 213, 144, 142, 75, 410, 145, 141,
+	75, 413, 211, 257, 243, 258, 74, 227, 103, 228,
+	74, 420, 75, 228, 258, 309, 415, 242, 211, 228,
+	308, 229, 74, 228, 255, 229, 151, 159, 102, 254,
+	112, 229, 157, 232, 247, 229, 155, 232, 47, 51,
+	110, 7, 253, 232, 102, 260, 103, 232, 263, 230,
+	231, 209, 210, 113, 260, 10, 447, 259, 261, 455,
+	446, 425, 103, 445, 173, 106, 259, 261, 115, 172,
+	444, 426, 428, 228, 430, 443, 170, 442, 140, 143,
+	431, 432, 171, 441, 440, 229, 168, 205, 206, 209,
+	210, 144, 142, 439, 166, 145, 141, 232, 438, 113,
+	454, 176, 448, 230, 231, 209, 210, 437, 175, 139,
+	174, 160, 228, 162, 449, 112, 75, 48, 178, 1,
+	460, 65, 306, 462, 229, 110, 74, 105, 201, 466,
+	470, 471, 472, 108, 81, 64, 232, 118, 113, 477,
+	476, 479, 478, 487, 488, 473, 63, 490, 102, 129,
+	130, 62, 486, 132, 61, 138, 117, 491, 492, 228,
+	79, 8, 493, 9, 9, 49, 103, 495, 182, 60,
+	59, 229, 58, 57, 55, 180, 245, 54, 181, 53,
+	497, 179, 385, 232, 52, 135, 134, 137, 136, 384,
+	66, 504, 228, 388, 389, 387, 394, 396, 393, 395,
+	390, 391, 392, 397, 229, 271, 494, 303, 304, 209,
+	210, 383, 114, 277, 269, 321, 232, 107, 480, 285,
+	286, 287, 288, 289, 290, 291, 292, 293, 294, 295,
+	296, 297, 298, 299, 300, 301, 184, 185, 186, 187,
+	188, 189, 190, 191, 192, 193, 194, 195, 196, 197,
+	198, 199, 200, 326, 324, 370, 68, 165, 0, 0,
+	0, 0, 456, 339, 0, 0, 465, 0, 0, 346,
+	0, 0, 0, 459, 0, 0, 325, 388, 389, 387,
+	394, 396, 393, 395, 390, 391, 392, 397, 0, 140,
+	143, 0, 0, 0, 0, 0, 140, 143, 0, 0,
+	341, 343, 144, 142, 344, 0, 145, 141, 0, 144,
+	142, 0, 357, 145, 141, 327, 329, 331, 332, 333,
+	345, 347, 350, 351, 352, 353, 354, 358, 359, 360,
+	361, 0, 0, 328, 330, 334, 335, 336, 337, 338,
+	340, 342, 348, 365, 364, 349, 326, 0, 252, 355,
+	356, 362, 363, 366, 367, 0, 339, 0, 0, 0,
+	0, 0, 346, 0, 0, 0, 322, 140, 143, 325,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	144, 142, 0, 0, 145, 141, 0, 0, 0, 0,
+	0, 0, 0, 341, 343, 0, 0, 344, 0, 0,
+	0, 0, 0, 0, 424, 357, 0, 0, 327, 329,
+	331, 332, 333, 345, 347, 350, 351, 352, 353, 354,
+	358, 359, 360, 361, 0, 0, 328, 330, 334, 335,
+	336, 337, 338, 340, 342, 348, 365, 364, 349, 0,
+	0, 0, 355, 356, 362, 363, 366, 367, 75, 0,
+	0, 69, 104, 0, 76, 0, 0, 26, 74, 0,
+	0, 246, 0, 0, 77, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	102, 0, 0, 0, 0, 0, 0, 0, 21, 23,
+	0, 0, 24, 0, 0, 0, 0, 0, 103, 0,
+	0, 0, 0, 82, 83, 84, 85, 86, 87, 89,
+	91, 92, 93, 94, 95, 96, 97, 88, 90, 0,
+	0, 0, 13, 0, 0, 16, 17, 18, 0, 22,
+	28, 44, 43, 0, 34, 0, 0, 98, 99, 71,
+	72, 100, 101, 75, 0, 0, 69, 104, 0, 76,
+	0, 0, 26, 74, 0, 0, 249, 0, 0, 77,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 102, 0, 0, 0, 0,
+	0, 0, 0, 21, 23, 0, 0, 24, 0, 0,
+	0, 0, 0, 103, 0, 0, 0, 0, 82, 83,
+	84, 85, 86, 87, 89, 91, 92, 93, 94, 95,
+	96, 97, 88, 90, 0, 0, 0, 13, 0, 0,
+	16, 17, 18, 0, 22, 28, 44, 43, 0, 34,
+	0, 0, 98, 99, 71, 72, 100, 101, 75, 0,
+	0, 69, 104, 0, 76, 0, 0, 26, 74, 0,
+	0, 248, 0, 0, 77, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	96, 0, 0, 0, 0, 0, 0, 0, 21, 22,
-	0, 0, 23, 0, 0, 0, 0, 0, 97, 0,
-	0, 0, 0, 78, 79, 80, 81, 82, 83, 84,
-	85, 86, 87, 88, 89, 90, 91, 0, 0, 0,
-	13, 0, 0, 16, 17, 18, 0, 27, 41, 40,
-	0, 33, 0, 0, 92, 93, 67, 68, 94, 95,
-	71, 47, 0, 65, 98, 0, 72, 373, 0, 25,
-	70, 0, 0, 0, 372, 0, 73, 0, 376, 377,
-	375, 382, 384, 381, 383, 378, 379, 380, 385, 0,
-	0, 0, 96, 0, 0, 0, 0, 0, 0, 0,
-	21, 22, 0, 0, 23, 0, 0, 0, 0, 0,
-	97, 0, 0, 0, 0, 78, 79, 80, 81, 82,
-	83, 84, 85, 86, 87, 88, 89, 90, 91, 0,
-	0, 0, 13, 0, 0, 16, 17, 18, 0, 27,
-	41, 40, 0, 33, 0, 0, 92, 93, 67, 68,
-	94, 95, 71, 0, 0, 65, 98, 0, 72, 453,
-	0, 25, 70, 0, 0, 0, 0, 0, 73, 0,
-	376, 377, 375, 382, 384, 381, 383, 378, 379, 380,
-	385, 0, 0, 0, 96, 0, 0, 0, 0, 0,
-	0, 0, 21, 22, 0, 0, 23, 0, 0, 0,
-	0, 0, 97, 0, 0, 0, 0, 78, 79, 80,
-	81, 82, 83, 84, 85, 86, 87, 88, 89, 90,
-	91, 0, 0, 0, 13, 0, 0, 16, 17, 18,
-	0, 27, 41, 40, 0, 33, 0, 0, 92, 93,
-	67, 68, 94, 95, 20, 98, 0, 0, 0, 0,
-	25, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	102, 0, 0, 0, 0, 0, 0, 0, 21, 23,
+	0, 0, 24, 0, 0, 0, 0, 0, 103, 0,
+	0, 0, 0, 82, 83, 84, 85, 86, 87, 89,
+	91, 92, 93, 94, 95, 96, 97, 88, 90, 0,
+	0, 0, 13, 0, 0, 16, 17, 18, 0, 22,
+	28, 44, 43, 0, 34, 0, 0, 98, 99, 71,
+	72, 100, 101, 75, 50, 0, 69, 104, 0, 76,
+	0, 0, 26, 74, 0, 0, 0, 0, 0, 77,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 102, 0, 0, 0, 0,
+	0, 0, 0, 21, 23, 0, 0, 24, 0, 0,
+	0, 0, 0, 103, 0, 0, 0, 0, 82, 83,
+	84, 85, 86, 87, 89, 91, 92, 93, 94, 95,
+	96, 97, 88, 90, 0, 0, 0, 13, 0, 0,
+	16, 17, 18, 0, 22, 28, 44, 43, 0, 34,
+	0, 0, 98, 99, 71, 72, 100, 101, 75, 0,
+	0, 69, 104, 0, 76, 0, 0, 26, 74, 0,
+	0, 0, 0, 0, 77, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 21, 22, 0, 0, 23, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 11, 12, 14, 15,
-	19, 24, 26, 28, 29, 30, 31, 32, 36, 37,
-	0, 0, 0, 13, 0, 0, 16, 17, 18, 0,
-	27, 41, 40, 0, 33, 0, 0, 34, 35, 38,
-	39, 42, 43, 20, 44, 0, 0, 0, 0, 25,
+	102, 0, 0, 0, 0, 0, 0, 0, 21, 23,
+	0, 0, 24, 0, 0, 0, 0, 0, 103, 0,
+	0, 0, 0, 82, 83, 84, 85, 86, 87, 89,
+	91, 92, 93, 94, 95, 96, 97, 88, 90, 0,
+	0, 0, 13, 0, 0, 16, 17, 18, 0, 22,
+	28, 44, 43, 0, 34, 0, 0, 98, 99, 71,
+	72, 100, 101, 20, 104, 0, 0, 0, 0, 26,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	21, 22, 0, 0, 23, 0, 0, 0, 0, 0,
+	21, 23, 0, 0, 24, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 11, 12, 14, 15, 19,
-	24, 26, 28, 29, 30, 31, 32, 36, 37, 132,
-	0, 0, 13, 0, 0, 16, 17, 18, 0, 27,
-	41, 40, 0, 33, 0, 0, 34, 35, 38, 39,
-	42, 43, 132, 0, 0, 0, 110, 112, 113, 0,
-	114, 115, 116, 117, 118, 119, 120, 121, 122, 123,
-	124, 125, 0, 126, 127, 131, 111, 0, 0, 110,
-	112, 113, 0, 114, 115, 116, 117, 118, 0, 120,
-	121, 122, 123, 124, 125, 0, 126, 127, 131, 111,
-	0, 0, 132, 128, 130, 129, 0, 0, 0, 0,
+	25, 27, 29, 30, 31, 32, 33, 37, 38, 39,
+	40, 0, 0, 0, 13, 0, 0, 16, 17, 18,
+	0, 22, 28, 44, 43, 0, 34, 0, 0, 35,
+	36, 41, 42, 45, 46, 20, 47, 0, 0, 0,
+	0, 26, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 128, 130, 129, 110,
-	112, 113, 0, 114, 115, 116, 117, 0, 0, 120,
-	121, 0, 123, 124, 125, 0, 126, 127, 131, 111,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 21, 23, 0, 0, 24, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 11, 12, 14,
+	15, 19, 25, 27, 29, 30, 31, 32, 33, 37,
+	38, 39, 40, 139, 0, 0, 13, 0, 0, 16,
+	17, 18, 0, 22, 28, 44, 43, 0, 34, 0,
+	0, 35, 36, 41, 42, 45, 46, 139, 0, 0,
+	116, 118, 119, 0, 120, 121, 122, 123, 124, 125,
+	126, 127, 128, 129, 130, 131, 0, 132, 133, 138,
+	117, 0, 0, 0, 116, 118, 119, 0, 120, 121,
+	122, 123, 124, 0, 126, 127, 128, 129, 130, 131,
+	0, 132, 133, 138, 117, 0, 0, 0, 0, 135,
+	134, 137, 136, 139, 0, 0, 0, 0, 0, 0,
+	0, 139, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 135, 134, 137, 136, 0, 0, 0,
+	116, 118, 119, 0, 120, 121, 122, 123, 116, 118,
+	126, 127, 0, 129, 130, 131, 0, 132, 133, 138,
+	117, 129, 130, 0, 0, 132, 133, 138, 117, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 128, 130, 129,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 135,
+	134, 137, 136, 0, 0, 0, 0, 135, 134, 137,
+	136,
 }
 
 var yyPact = [...]int16{
-	13, 297, 1211, 1211, 941, 1122, -1000, -1000, -1000, 293,
+	17, 341, 1313, 1313, 1034, 1221, -1000, -1000, -1000, 335,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, 585, -1000, 410, -1000, 1275, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 423, -1000, 376,
+	-1000, 1379, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 145, 31, 321,
+	321, 331, 327, 322, -1000, -1000, 1129, -1000, 1129, 242,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, 273, 16, 285, 285, 290, 289, 286,
-	-1000, -1000, 1033, -1000, 1033, 234, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 479, -1000,
-	-1000, 594, -1000, -1000, 388, 387, -1000, -1000, 79, -1000,
-	-44, -44, -44, -44, -44, -44, -44, -44, -44, -44,
-	-44, -44, -44, -44, -44, -44, -44, -44, 778, -1000,
-	-1000, 31, 686, 170, 170, 170, 170, 170, 170, 285,
-	-47, -1000, 248, 248, 665, -1000, -1000, -1000, 849, -1000,
-	757, -1000, 665, 10, 570, 29, -9, -1000, 348, -1000,
-	-1000, 378, 295, -1000, -1000, 459, -1000, 185, -1000, 141,
-	183, 1033, -1000, -38, -49, -6, -1000, 1033, 1033, 1033,
-	1033, 1033, 1033, 1033, 1033, 1033, 1033, 1033, 1033, 1033,
-	1033, 1033, 1033, 1033, -1000, -1000, -1000, 307, 264, 263,
-	262, 273, -1000, -1000, 170, -1000, 261, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, 232, 232, 323, -1000, 273, -1000,
-	170, 240, 239, 237, 149, 149, -9, -9, -9, -9,
-	-1000, -1000, -1000, 573, -1000, -1000, 99, -1000, 1275, -1000,
-	-1000, 452, -1000, -1000, -1000, 391, -1000, 129, -1000, -1000,
-	-1000, -1000, -1000, 107, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 70, 277, 212, -1000, -1000, -1000, 940, 25, 199,
-	199, 199, 248, 248, 248, 248, 29, 29, 176, 176,
-	176, 176, 176, 1348, 1298, 176, 176, 1348, 29, 29,
-	176, 29, 25, -1000, 233, 211, 200, 170, -9, 162,
-	154, 170, 570, 140, -1000, -1000, -1000, 354, -1000, 120,
-	119, 170, 417, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, 384, -1000, -1000, 372, -1000, -1000,
+	416, 407, -1000, -1000, 62, -1000, -41, -41, -41, -41,
+	-41, -41, -41, -41, -41, -41, -41, -41, -41, -41,
+	-41, -41, -41, -41, 297, 301, -1000, -1000, 29, 259,
+	313, 313, 313, 313, 313, 313, 321, -55, -1000, 312,
+	312, 749, -1000, -1000, -1000, 939, -1000, 844, -1000, 749,
+	13, 636, 18, -11, -1000, 332, -1000, -1000, 311, 320,
+	-1000, -1000, 338, -1000, 248, -1000, 98, 75, 1129, -1000,
+	-43, -52, -35, -1000, 1129, 1129, 1129, 1129, 1129, 1129,
+	1129, 1129, 1129, 1129, 1129, 1129, 1129, 1129, 1129, 1129,
+	1129, -1000, -1000, -1000, 417, 315, 310, 281, 145, -1000,
+	-1000, 313, -1000, -1000, -1000, 266, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, 240, 240, 258, -1000, 145, -1000, 313,
+	262, 261, 241, 177, 177, -11, -11, -11, -11, -1000,
+	-1000, -1000, 654, -1000, -1000, 129, -1000, 1379, -1000, -1000,
+	558, -1000, -1000, -1000, 255, -1000, 88, -1000, -1000, -1000,
+	-1000, -1000, 87, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	56, 192, 175, -1000, -1000, -1000, 475, 405, 206, 206,
+	206, 312, 312, 312, 312, 18, 18, 1467, 1467, 1467,
+	1467, 1467, 1459, 1403, 1467, 1467, 1459, 18, 18, 1467,
+	18, 405, -1000, 238, 237, 207, 313, -11, 162, 161,
+	313, 636, 128, -1000, -1000, -1000, 187, -1000, 127, 117,
+	313, 115, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 1033, 170, -1000,
-	-1000, -1000, -1000, -1000, -1000, 144, 144, 58, 144, 177,
-	177, 195, 184, -1000, -1000, 383, 382, 381, 371, 359,
-	358, 356, 355, 340, 336, 332, -1000, 326, -69, -66,
-	-1000, -1000, -1000, -1000, -1000, 108, 94, 170, 562, -1000,
-	-1000, 535, -1000, 192, -1000, -1000, -1000, -1000, 452, -1000,
-	483, -1000, 1275, 428, -1000, -1000, -1000, 144, -1000, 48,
-	28, 1032, -1000, -1000, -1000, 22, 69, 69, 69, 232,
-	186, 186, 22, 186, 22, -76, 89, 230, 199, 199,
-	-1000, -1000, 342, -1000, 170, -1000, -1000, -1000, -1000, -1000,
-	-1000, 144, 144, -1000, -1000, -1000, 144, -1000, -1000, -1000,
-	-1000, -1000, -1000, 69, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, 83, -1000, -1000, 170, 299, -1000,
-	-1000, -1000, 80, -1000, -1000, 203, -1000, 187, -1000, -1000,
-	-1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 1129,
+	313, -1000, -1000, -1000, -1000, -1000, -1000, 174, 174, 55,
+	174, 193, 193, 245, 203, -1000, -1000, 411, 402, 397,
+	388, 387, 381, 379, 374, 367, 364, 360, -1000, 74,
+	-59, -57, -1000, -1000, -1000, -1000, -1000, 116, 96, 313,
+	347, -1000, -1000, 565, -1000, 173, -1000, -1000, -1000, -1000,
+	558, -1000, 561, -1000, 1379, 250, -1000, -1000, -1000, 174,
+	-1000, 27, 25, 559, -1000, -1000, -1000, 33, 66, 66,
+	66, 240, 244, 244, 33, 244, 33, -86, 79, 176,
+	206, 206, -1000, -1000, 156, -1000, 313, -1000, -1000, -1000,
+	-1000, -1000, -1000, 174, 174, -1000, -1000, -1000, 174, -1000,
+	-1000, -1000, -1000, -1000, -1000, 66, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, 35, -1000, -1000, 313,
+	243, -1000, -1000, -1000, 100, -1000, -1000, 185, -1000, 204,
+	-1000, -1000, -1000, -1000, -1000,
 }
 
 var yyPgo = [...]int16{
-	0, 538, 14, 537, 6, 23, 535, 443, 22, 20,
-	13, 534, 35, 376, 421, 531, 16, 512, 37, 21,
-	509, 503, 8, 502, 4, 10, 498, 3, 5, 7,
-	497, 12, 2, 496, 492, 26, 402, 488, 477, 476,
-	102, 475, 474, 25, 473, 1, 44, 472, 11, 468,
-	465, 464, 463, 455, 448, 432, 428, 408, 0, 427,
-	9, 418, 415, 360,
+	0, 567, 16, 566, 7, 18, 564, 470, 24, 34,
+	15, 528, 36, 365, 471, 527, 17, 525, 11, 10,
+	524, 522, 8, 521, 5, 6, 516, 4, 3, 13,
+	515, 14, 1, 500, 494, 26, 428, 491, 489, 488,
+	344, 487, 486, 25, 485, 2, 39, 484, 12, 483,
+	482, 480, 479, 478, 464, 461, 456, 445, 431, 0,
+	444, 9, 438, 429, 427,
 }
 
 var yyR1 = [...]int8{
-	0, 62, 62, 62, 62, 62, 62, 62, 40, 40,
+	0, 63, 63, 63, 63, 63, 63, 63, 40, 40,
 	40, 40, 40, 40, 40, 40, 40, 40, 40, 40,
-	40, 40, 40, 34, 34, 34, 34, 35, 35, 38,
+	40, 40, 40, 40, 34, 34, 34, 34, 35, 35,
 	38, 38, 38, 38, 38, 38, 38, 38, 38, 38,
-	38, 38, 38, 38, 38, 38, 38, 36, 39, 39,
-	52, 52, 44, 44, 44, 44, 37, 37, 37, 37,
-	37, 37, 18, 18, 18, 18, 17, 17, 17, 4,
-	4, 4, 45, 45, 41, 41, 41, 41, 41, 43,
-	43, 42, 42, 42, 53, 60, 49, 49, 50, 51,
-	33, 33, 33, 9, 9, 47, 55, 55, 55, 55,
-	55, 55, 56, 57, 57, 57, 46, 46, 46, 1,
-	1, 1, 2, 2, 2, 2, 2, 2, 2, 14,
-	14, 7, 7, 7, 7, 7, 7, 7, 7, 7,
+	38, 38, 38, 38, 38, 38, 38, 38, 36, 39,
+	39, 53, 53, 44, 44, 44, 44, 37, 37, 37,
+	37, 37, 37, 18, 18, 18, 18, 17, 17, 17,
+	4, 4, 4, 45, 45, 41, 41, 41, 41, 41,
+	43, 43, 42, 42, 42, 54, 61, 49, 49, 50,
+	50, 51, 52, 33, 33, 33, 9, 9, 47, 56,
+	56, 56, 56, 56, 56, 57, 58, 58, 58, 46,
+	46, 46, 1, 1, 1, 2, 2, 2, 2, 2,
+	2, 2, 14, 14, 7, 7, 7, 7, 7, 7,
 	7, 7, 7, 7, 7, 7, 7, 7, 7, 7,
 	7, 7, 7, 7, 7, 7, 7, 7, 7, 7,
-	7, 7, 7, 7, 13, 13, 13, 13, 15, 15,
-	15, 16, 16, 16, 16, 16, 16, 16, 63, 21,
-	21, 21, 21, 20, 20, 20, 20, 20, 20, 20,
-	20, 20, 30, 30, 30, 22, 22, 22, 22, 23,
-	23, 23, 24, 24, 24, 24, 24, 24, 24, 24,
-	24, 24, 24, 25, 25, 26, 26, 26, 11, 11,
-	11, 11, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 6, 6, 6, 6,
+	7, 7, 7, 7, 7, 7, 7, 7, 7, 7,
+	13, 13, 13, 13, 15, 15, 15, 16, 16, 16,
+	16, 16, 16, 16, 64, 21, 21, 21, 21, 20,
+	20, 20, 20, 20, 20, 20, 20, 20, 30, 30,
+	30, 22, 22, 22, 22, 23, 23, 23, 24, 24,
+	24, 24, 24, 24, 24, 24, 24, 24, 24, 25,
+	25, 26, 26, 26, 11, 11, 11, 11, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
+	3, 3, 3, 3, 6, 6, 6, 6, 6, 6,
 	6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
 	6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
 	6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
-	6, 6, 6, 6, 8, 8, 5, 5, 5, 5,
-	48, 48, 29, 29, 31, 31, 32, 32, 28, 27,
-	27, 54, 10, 19, 19, 61, 61, 61, 61, 61,
-	61, 61, 61, 61, 61, 12, 12, 58, 58, 58,
-	58, 58, 58, 58, 58, 58, 58, 58, 58, 59,
+	6, 6, 6, 6, 6, 8, 8, 5, 5, 5,
+	5, 48, 48, 29, 29, 31, 31, 32, 32, 28,
+	27, 27, 55, 10, 19, 19, 62, 62, 62, 62,
+	62, 62, 62, 62, 62, 62, 12, 12, 59, 59,
+	59, 59, 59, 59, 59, 59, 59, 59, 59, 59,
+	60,
 }
 
 var yyR2 = [...]int8{
 	0, 2, 2, 2, 2, 2, 2, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 3, 3, 2, 2, 2, 2, 4,
+	1, 1, 1, 1, 3, 3, 2, 2, 2, 2,
 	4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
-	4, 4, 4, 4, 4, 4, 4, 1, 0, 1,
-	3, 3, 1, 1, 3, 3, 1, 3, 3, 3,
-	5, 5, 3, 4, 2, 1, 3, 1, 2, 1,
-	1, 1, 3, 4, 2, 2, 2, 2, 2, 3,
-	2, 3, 1, 2, 3, 1, 3, 3, 2, 2,
-	3, 5, 3, 1, 1, 4, 6, 5, 6, 5,
-	4, 3, 2, 2, 1, 1, 3, 4, 2, 3,
-	1, 2, 3, 3, 1, 3, 3, 2, 1, 2,
+	4, 4, 4, 4, 4, 4, 4, 4, 1, 0,
+	1, 3, 3, 1, 1, 3, 3, 1, 3, 3,
+	3, 5, 5, 3, 4, 2, 1, 3, 1, 2,
+	1, 1, 1, 3, 4, 2, 2, 2, 2, 2,
+	3, 2, 3, 1, 2, 3, 1, 3, 3, 3,
+	3, 2, 2, 3, 5, 3, 1, 1, 4, 6,
+	5, 6, 5, 4, 3, 2, 2, 1, 1, 3,
+	4, 2, 3, 1, 2, 3, 3, 1, 3, 3,
+	2, 1, 2, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1, 3, 4, 2, 0, 3, 1,
-	2, 3, 3, 1, 3, 3, 2, 1, 2, 0,
-	3, 2, 1, 1, 3, 1, 3, 4, 1, 3,
-	5, 5, 1, 1, 1, 4, 3, 3, 2, 3,
-	1, 2, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 4, 3, 3, 1, 2, 1, 1,
+	3, 4, 2, 0, 3, 1, 2, 3, 3, 1,
+	3, 3, 2, 1, 2, 0, 3, 2, 1, 1,
+	3, 1, 3, 4, 1, 3, 5, 5, 1, 1,
+	1, 4, 3, 3, 2, 3, 1, 2, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 3, 3, 4,
+	3, 3, 1, 2, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1, 2, 2, 1, 1, 1, 2,
-	1, 1, 1, 0, 1, 1, 2, 3, 3, 4,
-	4, 6, 7, 4, 1, 1, 1, 1, 2, 3,
-	3, 3, 3, 3, 3, 3, 3, 6, 1, 3,
+	1, 1, 1, 1, 1, 2, 2, 1, 1, 1,
+	2, 1, 1, 1, 0, 1, 1, 2, 3, 3,
+	4, 4, 6, 7, 4, 1, 1, 1, 1, 2,
+	3, 3, 3, 3, 3, 3, 3, 3, 6, 1,
+	3,
 }
 
 var yyChk = [...]int16{
-	-1000, -62, 109, 110, 111, 112, 2, 10, -14, -7,
-	-13, 64, 65, 81, 66, 67, 84, 85, 86, 68,
-	12, 49, 50, 53, 69, 18, 70, 88, 71, 72,
-	73, 74, 75, 92, 95, 96, 76, 77, 97, 98,
-	90, 89, 99, 100, 13, -63, -14, 10, -40, -34,
-	-38, -41, -47, -48, -49, -50, -51, -53, -54, -55,
-	-56, -57, -33, -58, -3, 12, -9, 97, 98, -12,
-	19, 9, 15, 25, -8, -7, -46, -59, 64, 65,
-	66, 67, 68, 69, 70, 71, 72, 73, 74, 75,
-	76, 77, 95, 96, 99, 100, 41, 59, 13, -57,
-	-13, -15, 20, -16, 12, -10, 2, 25, -21, 2,
-	41, 61, 42, 43, 45, 46, 47, 48, 49, 50,
-	51, 52, 53, 54, 55, 56, 58, 59, 88, 90,
-	89, 60, 14, 41, 59, 55, 42, 54, 58, -35,
-	-43, 2, 81, 92, 15, -43, -43, -43, 15, -43,
-	15, -43, 15, -40, -58, -40, -58, -46, -1, 20,
-	-2, 12, -10, 2, 20, 7, 2, 4, 2, 4,
-	24, -36, -37, -44, -39, -52, 80, -36, -36, -36,
+	-1000, -63, 112, 113, 114, 115, 2, 10, -14, -7,
+	-13, 64, 65, 83, 66, 67, 86, 87, 88, 68,
+	12, 49, 90, 50, 53, 69, 18, 70, 91, 71,
+	72, 73, 74, 75, 95, 98, 99, 76, 77, 78,
+	79, 100, 101, 93, 92, 102, 103, 13, -64, -14,
+	10, -40, -34, -38, -41, -47, -48, -49, -50, -51,
+	-52, -54, -55, -56, -57, -58, -33, -59, -3, 12,
+	-9, 100, 101, -12, 19, 9, 15, 25, -8, -7,
+	-46, -60, 64, 65, 66, 67, 68, 69, 78, 70,
+	79, 71, 72, 73, 74, 75, 76, 77, 98, 99,
+	102, 103, 41, 59, 13, -58, -13, -15, 20, -16,
+	12, -10, 2, 25, -21, 2, 41, 61, 42, 43,
+	45, 46, 47, 48, 49, 50, 51, 52, 53, 54,
+	55, 56, 58, 59, 91, 90, 93, 92, 60, 14,
+	41, 59, 55, 42, 54, 58, -35, -43, 2, 83,
+	95, 15, -43, -43, -43, 15, -43, 15, -43, 15,
+	-40, -59, -40, -59, -46, -1, 20, -2, 12, -10,
+	2, 20, 7, 2, 4, 2, 4, 24, -36, -37,
+	-44, -39, -53, 82, -36, -36, -36, -36, -36, -36,
 	-36, -36, -36, -36, -36, -36, -36, -36, -36, -36,
-	-36, -36, -36, -36, -61, 2, -48, -8, 97, 98,
-	-12, -58, 99, 100, 15, -32, -9, 2, -29, -31,
-	95, 96, 19, 9, 41, 59, -60, 2, -58, -48,
-	-8, 97, 98, -12, -58, -58, -58, -58, -58, -58,
-	-43, -35, -18, 15, 2, -18, -42, 22, -40, 22,
-	22, -58, 22, 22, 20, 7, 2, -5, 2, 4,
-	56, 44, 57, -5, 20, -16, 25, 2, 25, 2,
-	-20, 5, -30, -22, 12, -29, -31, 16, -40, 84,
-	85, 86, 87, 91, 82, 83, -40, -40, -40, -40,
+	-36, -62, 2, -48, -8, 100, 101, -12, -59, 102,
+	103, 15, -48, 2, -32, -9, 2, -29, -31, 98,
+	99, 19, 9, 41, 59, -61, 2, -59, -48, -8,
+	100, 101, -12, -59, -59, -59, -59, -59, -59, -43,
+	-35, -18, 15, 2, -18, -42, 22, -40, 22, 22,
+	-59, 22, 22, 20, 7, 2, -5, 2, 4, 56,
+	44, 57, -5, 20, -16, 25, 2, 25, 2, -20,
+	5, -30, -22, 12, -29, -31, 16, -40, 86, 87,
+	88, 89, 94, 84, 85, -40, -40, -40, -40, -40,
 	-40, -40, -40, -40, -40, -40, -40, -40, -40, -40,
-	-40, -40, -40, -48, 97, 98, -12, 15, -58, 15,
-	15, 15, -58, 15, -29, -29, 21, 6, 2, 15,
-	15, 15, -17, 22, -4, -6, 25, 2, 64, 80,
-	65, 81, 66, 67, 68, 82, 83, 84, 85, 86,
-	12, 87, 49, 50, 53, 69, 18, 70, 88, 91,
-	71, 72, 73, 74, 75, 95, 96, 61, 76, 77,
-	97, 98, 90, 89, 99, 100, 22, 7, 7, 20,
-	-2, 25, 2, 25, 2, 26, 26, -31, 26, 41,
-	59, -23, 24, 17, -24, 30, 28, 29, 35, 36,
-	37, 33, 31, 34, 32, 38, -45, 15, -45, -45,
-	-18, -18, -19, -18, -19, 15, 15, 15, -58, 22,
-	22, -58, 22, -60, 21, 2, 22, 22, -58, 22,
-	7, 2, -40, -58, -28, 19, -28, 26, -28, -22,
-	-22, 24, 17, 2, 17, 6, 6, 6, 6, 6,
-	6, 6, 6, 6, 6, 6, -48, -8, 86, 85,
-	22, 22, -58, 22, 7, 21, 2, 22, -4, 22,
-	-28, 26, 26, 17, -24, -27, 59, -28, -32, -32,
-	-32, -29, -25, 14, -25, -27, -25, -27, -11, 103,
-	104, 105, 106, 22, -48, -45, -45, 7, -58, -28,
-	-28, -28, -26, -32, 22, -58, 22, 24, 21, 2,
-	22, 21, -32,
+	-40, -40, -48, 100, 101, -12, 15, -59, 15, 15,
+	15, -59, 15, -29, -29, 21, 6, 2, 15, 15,
+	15, -17, 22, -4, -6, 25, 2, 64, 82, 65,
+	83, 66, 67, 68, 84, 85, 86, 87, 88, 12,
+	89, 49, 90, 50, 53, 69, 18, 70, 91, 94,
+	71, 72, 73, 74, 75, 98, 99, 61, 76, 77,
+	78, 79, 100, 101, 93, 92, 102, 103, 22, 7,
+	7, 20, -2, 25, 2, 25, 2, 26, 26, -31,
+	26, 41, 59, -23, 24, 17, -24, 30, 28, 29,
+	35, 36, 37, 33, 31, 34, 32, 38, -45, 15,
+	-45, -45, -18, -18, -19, -18, -19, 15, 15, 15,
+	-59, 22, 22, -59, 22, -61, 21, 2, 22, 22,
+	-59, 22, 7, 2, -40, -59, -28, 19, -28, 26,
+	-28, -22, -22, 24, 17, 2, 17, 6, 6, 6,
+	6, 6, 6, 6, 6, 6, 6, 6, -48, -8,
+	88, 87, 22, 22, -59, 22, 7, 21, 2, 22,
+	-4, 22, -28, 26, 26, 17, -24, -27, 59, -28,
+	-32, -32, -32, -29, -25, 14, -25, -27, -25, -27,
+	-11, 106, 107, 108, 109, 22, -48, -45, -45, 7,
+	-59, -28, -28, -28, -26, -32, 22, -59, 22, 24,
+	21, 2, 22, 21, -32,
 }
 
 var yyDef = [...]int16{
-	0, -2, 157, 157, 0, 0, 7, 6, 1, 157,
-	120, 121, 122, 123, 124, 125, 126, 127, 128, 129,
-	130, 131, 132, 133, 134, 135, 136, 137, 138, 139,
-	140, 141, 142, 143, 144, 145, 146, 147, 148, 149,
-	150, 151, 152, 153, 0, 2, -2, 3, 4, 8,
-	9, 10, 11, 12, 13, 14, 15, 16, 17, 18,
-	19, 20, 21, 22, 0, 130, 0, 148, 149, 0,
-	270, 271, 0, 281, 0, 104, 105, 308, -2, -2,
+	0, -2, 163, 163, 0, 0, 7, 6, 1, 163,
+	123, 124, 125, 126, 127, 128, 129, 130, 131, 132,
+	133, 134, 135, 136, 137, 138, 139, 140, 141, 142,
+	143, 144, 145, 146, 147, 148, 149, 150, 151, 152,
+	153, 154, 155, 156, 157, 158, 159, 0, 2, -2,
+	3, 4, 8, 9, 10, 11, 12, 13, 14, 15,
+	16, 17, 18, 19, 20, 21, 22, 23, 0, 133,
+	0, 154, 155, 0, 281, 282, 0, 292, 0, 107,
+	108, 319, -2, -2, -2, -2, -2, -2, -2, -2,
 	-2, -2, -2, -2, -2, -2, -2, -2, -2, -2,
-	-2, -2, -2, -2, -2, -2, 264, 265, 0, 5,
-	119, 0, 156, 159, 0, 163, 167, 282, 168, 172,
-	48, 48, 48, 48, 48, 48, 48, 48, 48, 48,
-	48, 48, 48, 48, 48, 48, 48, 48, 0, 88,
-	89, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	25, 26, 0, 0, 0, 74, 75, 76, 0, 77,
-	0, 78, 0, 0, 22, 102, -2, 103, 0, 108,
-	110, 0, 114, 118, 154, 0, 160, 0, 166, 0,
-	171, 0, 47, 56, 52, 53, 49, 0, 0, 0,
+	-2, -2, 275, 276, 0, 5, 122, 0, 162, 165,
+	0, 169, 173, 293, 174, 178, 49, 49, 49, 49,
+	49, 49, 49, 49, 49, 49, 49, 49, 49, 49,
+	49, 49, 49, 49, 0, 0, 91, 92, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 26, 27, 0,
+	0, 0, 75, 76, 77, 0, 78, 0, 79, 0,
+	0, 23, 105, -2, 106, 0, 111, 113, 0, 117,
+	121, 160, 0, 166, 0, 172, 0, 177, 0, 48,
+	57, 53, 54, 50, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 86, 87, 285, 0, 0, 0,
-	0, 294, 295, 296, 0, 90, 0, 92, 276, 277,
-	93, 94, 272, 273, 0, 0, 0, 101, 85, 297,
-	0, 0, 0, 0, 299, 300, 301, 302, 303, 304,
-	23, 24, 27, 0, 65, 28, 0, 80, 82, 80,
-	80, 22, 84, 309, 106, 0, 111, 0, 117, 266,
-	267, 268, 269, 0, 155, 158, 161, 164, 162, 165,
-	170, 173, 175, 178, 182, 183, 184, 0, 29, 0,
-	0, 0, 0, 0, -2, -2, 30, 31, 32, 33,
-	34, 35, 36, 37, 38, 39, 40, 41, 42, 43,
-	44, 45, 46, 286, 0, 0, 0, 0, 298, 0,
-	0, 0, 0, 0, 274, 275, 95, 0, 100, 0,
-	0, 0, 0, 64, 67, 69, 70, 71, 226, 227,
-	228, 229, 230, 231, 232, 233, 234, 235, 236, 237,
-	238, 239, 240, 241, 242, 243, 244, 245, 246, 247,
-	248, 249, 250, 251, 252, 253, 254, 255, 256, 257,
-	258, 259, 260, 261, 262, 263, 79, 83, 0, 107,
-	109, 112, 116, 113, 115, 0, 0, 0, 0, 0,
-	0, 0, 0, 188, 190, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 57, 0, 58, 59,
-	50, 51, 54, 284, 55, 0, 0, 0, 0, 287,
-	288, 0, 91, 0, 97, 99, 305, 306, 0, 62,
-	0, 68, 81, 0, 174, 278, 176, 0, 179, 0,
-	0, 0, 186, 191, 187, 0, 0, 0, 0, 0,
+	0, 87, 88, 296, 0, 0, 0, 0, 305, 306,
+	307, 0, 89, 90, 93, 0, 95, 287, 288, 96,
+	97, 283, 284, 0, 0, 0, 104, 86, 308, 0,
+	0, 0, 0, 310, 311, 312, 313, 314, 315, 24,
+	25, 28, 0, 66, 29, 0, 81, 83, 81, 81,
+	23, 85, 320, 109, 0, 114, 0, 120, 277, 278,
+	279, 280, 0, 161, 164, 167, 170, 168, 171, 176,
+	179, 181, 184, 188, 189, 190, 0, 30, 0, 0,
+	0, 0, 0, -2, -2, 31, 32, 33, 34, 35,
+	36, 37, 38, 39, 40, 41, 42, 43, 44, 45,
+	46, 47, 297, 0, 0, 0, 0, 309, 0, 0,
+	0, 0, 0, 285, 286, 98, 0, 103, 0, 0,
+	0, 0, 65, 68, 70, 71, 72, 234, 235, 236,
+	237, 238, 239, 240, 241, 242, 243, 244, 245, 246,
+	247, 248, 249, 250, 251, 252, 253, 254, 255, 256,
+	257, 258, 259, 260, 261, 262, 263, 264, 265, 266,
+	267, 268, 269, 270, 271, 272, 273, 274, 80, 84,
+	0, 110, 112, 115, 119, 116, 118, 0, 0, 0,
+	0, 0, 0, 0, 0, 194, 196, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 58, 0,
+	59, 60, 51, 52, 55, 295, 56, 0, 0, 0,
+	0, 298, 299, 0, 94, 0, 100, 102, 316, 317,
+	0, 63, 0, 69, 82, 0, 180, 289, 182, 0,
+	185, 0, 0, 0, 192, 197, 193, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	289, 290, 0, 293, 0, 96, 98, 63, 66, 307,
-	177, 0, 0, 185, 189, 192, 0, 280, 193, 194,
-	195, 196, 197, 0, 198, 199, 200, 201, 202, 208,
-	209, 210, 211, 72, 0, 60, 61, 0, 0, 180,
-	181, 279, 0, 206, 73, 0, 291, 0, 204, 207,
-	292, 203, 205,
+	0, 0, 300, 301, 0, 304, 0, 99, 101, 64,
+	67, 318, 183, 0, 0, 191, 195, 198, 0, 291,
+	199, 200, 201, 202, 203, 0, 204, 205, 206, 207,
+	208, 214, 215, 216, 217, 73, 0, 61, 62, 0,
+	0, 186, 187, 290, 0, 212, 74, 0, 302, 0,
+	210, 213, 303, 209, 211,
 }
 
 var yyTok1 = [...]int8{
@@ -868,7 +912,7 @@ var yyTok2 = [...]int8{
 	82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
 	92, 93, 94, 95, 96, 97, 98, 99, 100, 101,
 	102, 103, 104, 105, 106, 107, 108, 109, 110, 111,
-	112, 113,
+	112, 113, 114, 115, 116,
 }
 
 var yyTok3 = [...]int8{
@@ -1235,35 +1279,35 @@ yydefault:
 		{
 			yylex.(*parser).unexpected("", "")
 		}
-	case 23:
+	case 24:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.node = yylex.(*parser).newAggregateExpr(yyDollar[1].item, yyDollar[2].node, yyDollar[3].node, false)
 		}
-	case 24:
+	case 25:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.node = yylex.(*parser).newAggregateExpr(yyDollar[1].item, yyDollar[3].node, yyDollar[2].node, false)
 		}
-	case 25:
+	case 26:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.node = yylex.(*parser).newAggregateExpr(yyDollar[1].item, &AggregateExpr{}, yyDollar[2].node, true)
 		}
-	case 26:
+	case 27:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yylex.(*parser).unexpected("aggregation", "")
 			yyVAL.node = yylex.(*parser).newAggregateExpr(yyDollar[1].item, &AggregateExpr{}, Expressions{}, false)
 		}
-	case 27:
+	case 28:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.node = &AggregateExpr{
 				Grouping: yyDollar[2].strings,
 			}
 		}
-	case 28:
+	case 29:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.node = &AggregateExpr{
@@ -1271,11 +1315,6 @@ yydefault:
 				Without:  true,
 			}
 		}
-	case 29:
-		yyDollar = yyS[yypt-4 : yypt+1]
-		{
-			yyVAL.node = yylex.(*parser).newBinaryExpression(yyDollar[1].node, yyDollar[2].item, yyDollar[3].node, yyDollar[4].node)
-		}
 	case 30:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
@@ -1361,14 +1400,19 @@ yydefault:
 		{
 			yyVAL.node = yylex.(*parser).newBinaryExpression(yyDollar[1].node, yyDollar[2].item, yyDollar[3].node, yyDollar[4].node)
 		}
-	case 48:
+	case 47:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.node = yylex.(*parser).newBinaryExpression(yyDollar[1].node, yyDollar[2].item, yyDollar[3].node, yyDollar[4].node)
+		}
+	case 49:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.node = &BinaryExpr{
 				VectorMatching: &VectorMatching{Card: CardOneToOne},
 			}
 		}
-	case 49:
+	case 50:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.node = &BinaryExpr{
@@ -1376,34 +1420,34 @@ yydefault:
 				ReturnBool:     true,
 			}
 		}
-	case 50:
+	case 51:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.node = yyDollar[1].node
 			yyVAL.node.(*BinaryExpr).VectorMatching.MatchingLabels = yyDollar[3].strings
 		}
-	case 51:
+	case 52:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.node = yyDollar[1].node
 			yyVAL.node.(*BinaryExpr).VectorMatching.MatchingLabels = yyDollar[3].strings
 			yyVAL.node.(*BinaryExpr).VectorMatching.On = true
 		}
-	case 54:
+	case 55:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.node = yyDollar[1].node
 			yyVAL.node.(*BinaryExpr).VectorMatching.Card = CardManyToOne
 			yyVAL.node.(*BinaryExpr).VectorMatching.Include = yyDollar[3].strings
 		}
-	case 55:
+	case 56:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.node = yyDollar[1].node
 			yyVAL.node.(*BinaryExpr).VectorMatching.Card = CardOneToMany
 			yyVAL.node.(*BinaryExpr).VectorMatching.Include = yyDollar[3].strings
 		}
-	case 57:
+	case 58:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.node = yyDollar[1].node
@@ -1411,21 +1455,21 @@ yydefault:
 			yyVAL.node.(*BinaryExpr).VectorMatching.FillValues.LHS = &fill
 			yyVAL.node.(*BinaryExpr).VectorMatching.FillValues.RHS = &fill
 		}
-	case 58:
+	case 59:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.node = yyDollar[1].node
 			fill := yyDollar[3].node.(*NumberLiteral).Val
 			yyVAL.node.(*BinaryExpr).VectorMatching.FillValues.LHS = &fill
 		}
-	case 59:
+	case 60:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.node = yyDollar[1].node
 			fill := yyDollar[3].node.(*NumberLiteral).Val
 			yyVAL.node.(*BinaryExpr).VectorMatching.FillValues.RHS = &fill
 		}
-	case 60:
+	case 61:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.node = yyDollar[1].node
@@ -1434,7 +1478,7 @@ yydefault:
 			yyVAL.node.(*BinaryExpr).VectorMatching.FillValues.LHS = &fill_left
 			yyVAL.node.(*BinaryExpr).VectorMatching.FillValues.RHS = &fill_right
 		}
-	case 61:
+	case 62:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			fill_right := yyDollar[3].node.(*NumberLiteral).Val
@@ -1442,44 +1486,44 @@ yydefault:
 			yyVAL.node.(*BinaryExpr).VectorMatching.FillValues.LHS = &fill_left
 			yyVAL.node.(*BinaryExpr).VectorMatching.FillValues.RHS = &fill_right
 		}
-	case 62:
+	case 63:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.strings = yyDollar[2].strings
 		}
-	case 63:
+	case 64:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.strings = yyDollar[2].strings
 		}
-	case 64:
+	case 65:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.strings = []string{}
 		}
-	case 65:
+	case 66:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yylex.(*parser).unexpected("grouping opts", "\"(\"")
 			yyVAL.strings = nil
 		}
-	case 66:
+	case 67:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.strings = append(yyDollar[1].strings, yyDollar[3].item.Val)
 		}
-	case 67:
+	case 68:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.strings = []string{yyDollar[1].item.Val}
 		}
-	case 68:
+	case 69:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yylex.(*parser).unexpected("grouping opts", "\",\" or \")\"")
 			yyVAL.strings = yyDollar[1].strings
 		}
-	case 69:
+	case 70:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			if !model.UTF8Validation.IsValidLabelName(yyDollar[1].item.Val) {
@@ -1487,7 +1531,7 @@ yydefault:
 			}
 			yyVAL.item = yyDollar[1].item
 		}
-	case 70:
+	case 71:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			unquoted := yylex.(*parser).unquoteString(yyDollar[1].item.Val)
@@ -1498,18 +1542,18 @@ yydefault:
 			yyVAL.item.Pos++
 			yyVAL.item.Val = unquoted
 		}
-	case 71:
+	case 72:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yylex.(*parser).unexpected("grouping opts", "label")
 			yyVAL.item = Item{}
 		}
-	case 72:
+	case 73:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.node = yyDollar[2].node.(*NumberLiteral)
 		}
-	case 73:
+	case 74:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			nl := yyDollar[3].node.(*NumberLiteral)
@@ -1519,7 +1563,7 @@ yydefault:
 			nl.PosRange.Start = yyDollar[2].item.Pos
 			yyVAL.node = nl
 		}
-	case 74:
+	case 75:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			fn, exist := getFunction(yyDollar[1].item.Val, yylex.(*parser).functions)
@@ -1538,7 +1582,7 @@ yydefault:
 				},
 			}
 		}
-	case 75:
+	case 76:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			fn, exist := getFunction(yyDollar[1].item.Val, yylex.(*parser).functions)
@@ -1557,7 +1601,7 @@ yydefault:
 				},
 			}
 		}
-	case 76:
+	case 77:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			fn, exist := getFunction(yyDollar[1].item.Val, yylex.(*parser).functions)
@@ -1576,7 +1620,7 @@ yydefault:
 				},
 			}
 		}
-	case 77:
+	case 78:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			fn, exist := getFunction(yyDollar[1].item.Val, yylex.(*parser).functions)
@@ -1595,7 +1639,7 @@ yydefault:
 				},
 			}
 		}
-	case 78:
+	case 79:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			fn, exist := getFunction(yyDollar[1].item.Val, yylex.(*parser).functions)
@@ -1614,38 +1658,38 @@ yydefault:
 				},
 			}
 		}
-	case 79:
+	case 80:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.node = yyDollar[2].node
 		}
-	case 80:
+	case 81:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.node = Expressions{}
 		}
-	case 81:
+	case 82:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.node = append(yyDollar[1].node.(Expressions), yyDollar[3].node.(Expr))
 		}
-	case 82:
+	case 83:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.node = Expressions{yyDollar[1].node.(Expr)}
 		}
-	case 83:
+	case 84:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yylex.(*parser).addParseErrf(yyDollar[2].item.PositionRange(), "trailing commas not allowed in function call args")
 			yyVAL.node = yyDollar[1].node
 		}
-	case 84:
+	case 85:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.node = &ParenExpr{Expr: yyDollar[2].node.(Expr), PosRange: mergeRanges(&yyDollar[1].item, &yyDollar[3].item)}
 		}
-	case 85:
+	case 86:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			if numLit, ok := yyDollar[1].node.(*NumberLiteral); ok {
@@ -1659,7 +1703,7 @@ yydefault:
 			}
 			yyVAL.node = yyDollar[1].node
 		}
-	case 86:
+	case 87:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			if numLit, ok := yyDollar[3].node.(*NumberLiteral); ok {
@@ -1670,41 +1714,54 @@ yydefault:
 			yylex.(*parser).addOffsetExpr(yyDollar[1].node, yyDollar[3].node.(*DurationExpr))
 			yyVAL.node = yyDollar[1].node
 		}
-	case 87:
+	case 88:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).unexpected("offset", "number, duration, step(), or range()")
 			yyVAL.node = yyDollar[1].node
 		}
-	case 88:
+	case 89:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			numLit, _ := yyDollar[3].node.(*NumberLiteral)
+			yylex.(*parser).setLookback(yyDollar[1].node, time.Duration(math.Round(numLit.Val*float64(time.Second))))
+			yyVAL.node = yyDollar[1].node
+		}
+	case 90:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yylex.(*parser).unexpected("lookback", "duration")
+			yyVAL.node = yyDollar[1].node
+		}
+	case 91:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yylex.(*parser).setAnchored(yyDollar[1].node)
 		}
-	case 89:
+	case 92:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yylex.(*parser).setSmoothed(yyDollar[1].node)
 		}
-	case 90:
+	case 93:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).setTimestamp(yyDollar[1].node, yyDollar[3].float)
 			yyVAL.node = yyDollar[1].node
 		}
-	case 91:
+	case 94:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yylex.(*parser).setAtModifierPreprocessor(yyDollar[1].node, yyDollar[3].item)
 			yyVAL.node = yyDollar[1].node
 		}
-	case 92:
+	case 95:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).unexpected("@", "timestamp")
 			yyVAL.node = yyDollar[1].node
 		}
-	case 95:
+	case 98:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			var errMsg string
@@ -1715,6 +1772,8 @@ yydefault:
 				errMsg = "no offset modifiers allowed before range"
 			} else if vs.Timestamp != nil {
 				errMsg = "no @ modifiers allowed before range"
+			} else if vs.Lookback != 0 {
+				errMsg = "no lookback modifiers allowed before range"
 			}
 
 			if errMsg != "" {
@@ -1734,7 +1793,7 @@ yydefault:
 				EndPos:         yylex.(*parser).lastClosing,
 			}
 		}
-	case 96:
+	case 99:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			var rangeNl time.Duration
@@ -1756,7 +1815,7 @@ yydefault:
 				EndPos:    yyDollar[6].item.Pos + 1,
 			}
 		}
-	case 97:
+	case 100:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			var rangeNl time.Duration
@@ -1771,31 +1830,31 @@ yydefault:
 				EndPos:    yyDollar[5].item.Pos + 1,
 			}
 		}
-	case 98:
+	case 101:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yylex.(*parser).unexpected("subquery selector", "\"]\"")
 			yyVAL.node = yyDollar[1].node
 		}
-	case 99:
+	case 102:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yylex.(*parser).unexpected("subquery selector", "number, duration, step(), range(), or \"]\"")
 			yyVAL.node = yyDollar[1].node
 		}
-	case 100:
+	case 103:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yylex.(*parser).unexpected("subquery or range", "\":\" or \"]\"")
 			yyVAL.node = yyDollar[1].node
 		}
-	case 101:
+	case 104:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).unexpected("subquery or range selector", "number, duration, step(), or range()")
 			yyVAL.node = yyDollar[1].node
 		}
-	case 102:
+	case 105:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			if nl, ok := yyDollar[2].node.(*NumberLiteral); ok {
@@ -1808,7 +1867,7 @@ yydefault:
 				yyVAL.node = &UnaryExpr{Op: yyDollar[1].item.Typ, Expr: yyDollar[2].node.(Expr), StartPos: yyDollar[1].item.Pos}
 			}
 		}
-	case 103:
+	case 106:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			vs := yyDollar[2].node.(*VectorSelector)
@@ -1817,7 +1876,7 @@ yydefault:
 			yylex.(*parser).assembleVectorSelector(vs)
 			yyVAL.node = vs
 		}
-	case 104:
+	case 107:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			vs := &VectorSelector{
@@ -1828,14 +1887,14 @@ yydefault:
 			yylex.(*parser).assembleVectorSelector(vs)
 			yyVAL.node = vs
 		}
-	case 105:
+	case 108:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			vs := yyDollar[1].node.(*VectorSelector)
 			yylex.(*parser).assembleVectorSelector(vs)
 			yyVAL.node = vs
 		}
-	case 106:
+	case 109:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.node = &VectorSelector{
@@ -1843,7 +1902,7 @@ yydefault:
 				PosRange:      mergeRanges(&yyDollar[1].item, &yyDollar[3].item),
 			}
 		}
-	case 107:
+	case 110:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.node = &VectorSelector{
@@ -1851,7 +1910,7 @@ yydefault:
 				PosRange:      mergeRanges(&yyDollar[1].item, &yyDollar[4].item),
 			}
 		}
-	case 108:
+	case 111:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.node = &VectorSelector{
@@ -1859,7 +1918,7 @@ yydefault:
 				PosRange:      mergeRanges(&yyDollar[1].item, &yyDollar[2].item),
 			}
 		}
-	case 109:
+	case 112:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			if yyDollar[1].matchers != nil {
@@ -1868,144 +1927,144 @@ yydefault:
 				yyVAL.matchers = yyDollar[1].matchers
 			}
 		}
-	case 110:
+	case 113:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.matchers = []*labels.Matcher{yyDollar[1].matcher}
 		}
-	case 111:
+	case 114:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yylex.(*parser).unexpected("label matching", "\",\" or \"}\"")
 			yyVAL.matchers = yyDollar[1].matchers
 		}
-	case 112:
+	case 115:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.matcher = yylex.(*parser).newLabelMatcher(yyDollar[1].item, yyDollar[2].item, yyDollar[3].item)
 		}
-	case 113:
+	case 116:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.matcher = yylex.(*parser).newLabelMatcher(yyDollar[1].item, yyDollar[2].item, yyDollar[3].item)
 		}
-	case 114:
+	case 117:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.matcher = yylex.(*parser).newMetricNameMatcher(yyDollar[1].item)
 		}
-	case 115:
+	case 118:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).unexpected("label matching", "string")
 			yyVAL.matcher = nil
 		}
-	case 116:
+	case 119:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).unexpected("label matching", "string")
 			yyVAL.matcher = nil
 		}
-	case 117:
+	case 120:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yylex.(*parser).unexpected("label matching", "label matching operator")
 			yyVAL.matcher = nil
 		}
-	case 118:
+	case 121:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yylex.(*parser).unexpected("label matching", "identifier or \"}\"")
 			yyVAL.matcher = nil
 		}
-	case 119:
+	case 122:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			b := labels.NewBuilder(yyDollar[2].labels)
 			b.Set(labels.MetricName, yyDollar[1].item.Val)
 			yyVAL.labels = b.Labels()
 		}
-	case 120:
+	case 123:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.labels = yyDollar[1].labels
 		}
-	case 154:
+	case 160:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.labels = labels.New(yyDollar[2].lblList...)
 		}
-	case 155:
+	case 161:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.labels = labels.New(yyDollar[2].lblList...)
 		}
-	case 156:
+	case 162:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.labels = labels.New()
 		}
-	case 157:
+	case 163:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.labels = labels.New()
 		}
-	case 158:
+	case 164:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.lblList = append(yyDollar[1].lblList, yyDollar[3].label)
 		}
-	case 159:
+	case 165:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.lblList = []labels.Label{yyDollar[1].label}
 		}
-	case 160:
+	case 166:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yylex.(*parser).unexpected("label set", "\",\" or \"}\"")
 			yyVAL.lblList = yyDollar[1].lblList
 		}
-	case 161:
+	case 167:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.label = labels.Label{Name: yyDollar[1].item.Val, Value: yylex.(*parser).unquoteString(yyDollar[3].item.Val)}
 		}
-	case 162:
+	case 168:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.label = labels.Label{Name: yyDollar[1].item.Val, Value: yylex.(*parser).unquoteString(yyDollar[3].item.Val)}
 		}
-	case 163:
+	case 169:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.label = labels.Label{Name: labels.MetricName, Value: yyDollar[1].item.Val}
 		}
-	case 164:
+	case 170:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).unexpected("label set", "string")
 			yyVAL.label = labels.Label{}
 		}
-	case 165:
+	case 171:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).unexpected("label set", "string")
 			yyVAL.label = labels.Label{}
 		}
-	case 166:
+	case 172:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yylex.(*parser).unexpected("label set", "\"=\"")
 			yyVAL.label = labels.Label{}
 		}
-	case 167:
+	case 173:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yylex.(*parser).unexpected("label set", "identifier or \"}\"")
 			yyVAL.label = labels.Label{}
 		}
-	case 168:
+	case 174:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yylex.(*parser).generatedParserResult = &seriesDescription{
@@ -2013,81 +2072,66 @@ yydefault:
 				values: yyDollar[2].series,
 			}
 		}
-	case 169:
+	case 175:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.series = []SequenceValue{}
 		}
-	case 170:
+	case 176:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.series = append(yyDollar[1].series, yyDollar[3].series...)
 		}
-	case 171:
+	case 177:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.series = yyDollar[1].series
 		}
-	case 172:
+	case 178:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yylex.(*parser).unexpected("series values", "")
 			yyVAL.series = nil
 		}
-	case 173:
+	case 179:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
-			yyVAL.series = []SequenceValue{{Omitted: true}}
+			yyVAL.series = yylex.(*parser).seriesRepeat(SequenceValue{Omitted: true}, 1)
 		}
-	case 174:
+	case 180:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yyVAL.series = []SequenceValue{}
-			for i := uint64(0); i < yyDollar[3].uint; i++ {
-				yyVAL.series = append(yyVAL.series, SequenceValue{Omitted: true})
-			}
+			yyVAL.series = yylex.(*parser).seriesRepeat(SequenceValue{Omitted: true}, yyDollar[3].uint)
 		}
-	case 175:
+	case 181:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
-			yyVAL.series = []SequenceValue{{Value: yyDollar[1].float}}
+			yyVAL.series = yylex.(*parser).seriesRepeat(SequenceValue{Value: yyDollar[1].float}, 1)
 		}
-	case 176:
+	case 182:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yyVAL.series = []SequenceValue{}
 			// Add an additional value for time 0, which we ignore in tests.
-			for i := uint64(0); i <= yyDollar[3].uint; i++ {
-				yyVAL.series = append(yyVAL.series, SequenceValue{Value: yyDollar[1].float})
-			}
+			yyVAL.series = yylex.(*parser).seriesRepeat(SequenceValue{Value: yyDollar[1].float}, yyDollar[3].uint+1)
 		}
-	case 177:
+	case 183:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
-			yyVAL.series = []SequenceValue{}
 			// Add an additional value for time 0, which we ignore in tests.
-			for i := uint64(0); i <= yyDollar[4].uint; i++ {
-				yyVAL.series = append(yyVAL.series, SequenceValue{Value: yyDollar[1].float})
-				yyDollar[1].float += yyDollar[2].float
-			}
+			yyVAL.series = yylex.(*parser).seriesSteps(yyDollar[1].float, yyDollar[2].float, yyDollar[4].uint)
 		}
-	case 178:
+	case 184:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
-			yyVAL.series = []SequenceValue{yylex.(*parser).newHistogramSequenceValue(yyDollar[1].histogram)}
+			yyVAL.series = yylex.(*parser).seriesRepeat(yylex.(*parser).newHistogramSequenceValue(yyDollar[1].histogram), 1)
 		}
-	case 179:
+	case 185:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yyVAL.series = []SequenceValue{}
 			// Add an additional value for time 0, which we ignore in tests.
-			sv := yylex.(*parser).newHistogramSequenceValue(yyDollar[1].histogram)
-			for i := uint64(0); i <= yyDollar[3].uint; i++ {
-				yyVAL.series = append(yyVAL.series, sv)
-				//$1 += $2
-			}
+			yyVAL.series = yylex.(*parser).seriesRepeat(yylex.(*parser).newHistogramSequenceValue(yyDollar[1].histogram), yyDollar[3].uint+1)
 		}
-	case 180:
+	case 186:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			val, err := yylex.(*parser).histogramsIncreaseSeries(yyDollar[1].histogram, yyDollar[3].histogram, yyDollar[5].uint)
@@ -2096,7 +2140,7 @@ yydefault:
 			}
 			yyVAL.series = val
 		}
-	case 181:
+	case 187:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			val, err := yylex.(*parser).histogramsDecreaseSeries(yyDollar[1].histogram, yyDollar[3].histogram, yyDollar[5].uint)
@@ -2105,7 +2149,7 @@ yydefault:
 			}
 			yyVAL.series = val
 		}
-	case 182:
+	case 188:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			if yyDollar[1].item.Val != "stale" {
@@ -2113,130 +2157,130 @@ yydefault:
 			}
 			yyVAL.float = math.Float64frombits(value.StaleNaN)
 		}
-	case 185:
+	case 191:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.histogram = yylex.(*parser).buildHistogramFromMap(&yyDollar[2].descriptors)
 		}
-	case 186:
+	case 192:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.histogram = yylex.(*parser).buildHistogramFromMap(&yyDollar[2].descriptors)
 		}
-	case 187:
+	case 193:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			m := yylex.(*parser).newMap()
 			yyVAL.histogram = yylex.(*parser).buildHistogramFromMap(&m)
 		}
-	case 188:
+	case 194:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			m := yylex.(*parser).newMap()
 			yyVAL.histogram = yylex.(*parser).buildHistogramFromMap(&m)
 		}
-	case 189:
+	case 195:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.descriptors = *(yylex.(*parser).mergeMaps(&yyDollar[1].descriptors, &yyDollar[3].descriptors))
 		}
-	case 190:
+	case 196:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.descriptors = yyDollar[1].descriptors
 		}
-	case 191:
+	case 197:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yylex.(*parser).unexpected("histogram description", "histogram description key, e.g. buckets:[5 10 7]")
 		}
-	case 192:
+	case 198:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.descriptors = yylex.(*parser).newMap()
 			yyVAL.descriptors["schema"] = yyDollar[3].int
 		}
-	case 193:
+	case 199:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.descriptors = yylex.(*parser).newMap()
 			yyVAL.descriptors["sum"] = yyDollar[3].float
 		}
-	case 194:
+	case 200:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.descriptors = yylex.(*parser).newMap()
 			yyVAL.descriptors["count"] = yyDollar[3].float
 		}
-	case 195:
+	case 201:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.descriptors = yylex.(*parser).newMap()
 			yyVAL.descriptors["z_bucket"] = yyDollar[3].float
 		}
-	case 196:
+	case 202:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.descriptors = yylex.(*parser).newMap()
 			yyVAL.descriptors["z_bucket_w"] = yyDollar[3].float
 		}
-	case 197:
+	case 203:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.descriptors = yylex.(*parser).newMap()
 			yyVAL.descriptors["custom_values"] = yyDollar[3].bucket_set
 		}
-	case 198:
+	case 204:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.descriptors = yylex.(*parser).newMap()
 			yyVAL.descriptors["buckets"] = yyDollar[3].bucket_set
 		}
-	case 199:
+	case 205:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.descriptors = yylex.(*parser).newMap()
 			yyVAL.descriptors["offset"] = yyDollar[3].int
 		}
-	case 200:
+	case 206:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.descriptors = yylex.(*parser).newMap()
 			yyVAL.descriptors["n_buckets"] = yyDollar[3].bucket_set
 		}
-	case 201:
+	case 207:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.descriptors = yylex.(*parser).newMap()
 			yyVAL.descriptors["n_offset"] = yyDollar[3].int
 		}
-	case 202:
+	case 208:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.descriptors = yylex.(*parser).newMap()
 			yyVAL.descriptors["counter_reset_hint"] = yyDollar[3].item
 		}
-	case 203:
+	case 209:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.bucket_set = yyDollar[2].bucket_set
 		}
-	case 204:
+	case 210:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.bucket_set = yyDollar[2].bucket_set
 		}
-	case 205:
+	case 211:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.bucket_set = append(yyDollar[1].bucket_set, yyDollar[3].float)
 		}
-	case 206:
+	case 212:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.bucket_set = []float64{yyDollar[1].float}
 		}
-	case 270:
+	case 281:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.node = &NumberLiteral{
@@ -2244,7 +2288,7 @@ yydefault:
 				PosRange: yyDollar[1].item.PositionRange(),
 			}
 		}
-	case 271:
+	case 282:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			var err error
@@ -2259,12 +2303,12 @@ yydefault:
 				Duration: true,
 			}
 		}
-	case 272:
+	case 283:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.float = yylex.(*parser).number(yyDollar[1].item.Val)
 		}
-	case 273:
+	case 284:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			var err error
@@ -2275,17 +2319,17 @@ yydefault:
 			}
 			yyVAL.float = dur.Seconds()
 		}
-	case 274:
+	case 285:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.float = yyDollar[2].float
 		}
-	case 275:
+	case 286:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.float = -yyDollar[2].float
 		}
-	case 278:
+	case 289:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			var err error
@@ -2294,17 +2338,17 @@ yydefault:
 				yylex.(*parser).addParseErrf(yyDollar[1].item.PositionRange(), "invalid repetition in series values: %s", err)
 			}
 		}
-	case 279:
+	case 290:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.int = -int64(yyDollar[2].uint)
 		}
-	case 280:
+	case 291:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.int = int64(yyDollar[1].uint)
 		}
-	case 281:
+	case 292:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.node = &StringLiteral{
@@ -2312,7 +2356,7 @@ yydefault:
 				PosRange: yyDollar[1].item.PositionRange(),
 			}
 		}
-	case 282:
+	case 293:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.item = Item{
@@ -2321,12 +2365,12 @@ yydefault:
 				Val: yylex.(*parser).unquoteString(yyDollar[1].item.Val),
 			}
 		}
-	case 283:
+	case 294:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.strings = nil
 		}
-	case 285:
+	case 296:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			nl := yyDollar[1].node.(*NumberLiteral)
@@ -2337,7 +2381,7 @@ yydefault:
 			}
 			yyVAL.node = nl
 		}
-	case 286:
+	case 297:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			nl := yyDollar[2].node.(*NumberLiteral)
@@ -2352,7 +2396,7 @@ yydefault:
 			nl.PosRange.Start = yyDollar[1].item.Pos
 			yyVAL.node = nl
 		}
-	case 287:
+	case 298:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			de := &DurationExpr{
@@ -2363,7 +2407,7 @@ yydefault:
 			yylex.(*parser).experimentalDurationExpr(de)
 			yyVAL.node = de
 		}
-	case 288:
+	case 299:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			de := &DurationExpr{
@@ -2374,7 +2418,7 @@ yydefault:
 			yylex.(*parser).experimentalDurationExpr(de)
 			yyVAL.node = de
 		}
-	case 289:
+	case 300:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			de := &DurationExpr{
@@ -2389,7 +2433,7 @@ yydefault:
 			yylex.(*parser).experimentalDurationExpr(de)
 			yyVAL.node = de
 		}
-	case 290:
+	case 301:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			de := &DurationExpr{
@@ -2404,7 +2448,7 @@ yydefault:
 			yylex.(*parser).experimentalDurationExpr(de)
 			yyVAL.node = de
 		}
-	case 291:
+	case 302:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			de := &DurationExpr{
@@ -2417,7 +2461,7 @@ yydefault:
 			yylex.(*parser).experimentalDurationExpr(de)
 			yyVAL.node = de
 		}
-	case 292:
+	case 303:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			de := &DurationExpr{
@@ -2435,12 +2479,12 @@ yydefault:
 			yylex.(*parser).experimentalDurationExpr(de)
 			yyVAL.node = de
 		}
-	case 293:
+	case 304:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.node = yylex.(*parser).applyUnaryOpToDurationExpr(yyDollar[1].item, yyDollar[3].node.(Node), true)
 		}
-	case 297:
+	case 308:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			nl := yyDollar[1].node.(*NumberLiteral)
@@ -2451,30 +2495,30 @@ yydefault:
 			}
 			yyVAL.node = nl
 		}
-	case 298:
+	case 309:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.node = yylex.(*parser).applyUnaryOpToDurationExpr(yyDollar[1].item, yyDollar[2].node.(Node), false)
 		}
-	case 299:
+	case 310:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).experimentalDurationExpr(yyDollar[1].node.(Expr))
 			yyVAL.node = &DurationExpr{Op: ADD, LHS: yyDollar[1].node.(Expr), RHS: yyDollar[3].node.(Expr)}
 		}
-	case 300:
+	case 311:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).experimentalDurationExpr(yyDollar[1].node.(Expr))
 			yyVAL.node = &DurationExpr{Op: SUB, LHS: yyDollar[1].node.(Expr), RHS: yyDollar[3].node.(Expr)}
 		}
-	case 301:
+	case 312:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).experimentalDurationExpr(yyDollar[1].node.(Expr))
 			yyVAL.node = &DurationExpr{Op: MUL, LHS: yyDollar[1].node.(Expr), RHS: yyDollar[3].node.(Expr)}
 		}
-	case 302:
+	case 313:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).experimentalDurationExpr(yyDollar[1].node.(Expr))
@@ -2485,7 +2529,7 @@ yydefault:
 			}
 			yyVAL.node = &DurationExpr{Op: DIV, LHS: yyDollar[1].node.(Expr), RHS: yyDollar[3].node.(Expr)}
 		}
-	case 303:
+	case 314:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).experimentalDurationExpr(yyDollar[1].node.(Expr))
@@ -2496,13 +2540,13 @@ yydefault:
 			}
 			yyVAL.node = &DurationExpr{Op: MOD, LHS: yyDollar[1].node.(Expr), RHS: yyDollar[3].node.(Expr)}
 		}
-	case 304:
+	case 315:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).experimentalDurationExpr(yyDollar[1].node.(Expr))
 			yyVAL.node = &DurationExpr{Op: POW, LHS: yyDollar[1].node.(Expr), RHS: yyDollar[3].node.(Expr)}
 		}
-	case 305:
+	case 316:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			de := &DurationExpr{
@@ -2513,7 +2557,7 @@ yydefault:
 			yylex.(*parser).experimentalDurationExpr(de)
 			yyVAL.node = de
 		}
-	case 306:
+	case 317:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			de := &DurationExpr{
@@ -2524,7 +2568,7 @@ yydefault:
 			yylex.(*parser).experimentalDurationExpr(de)
 			yyVAL.node = de
 		}
-	case 307:
+	case 318:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			de := &DurationExpr{
@@ -2537,7 +2581,7 @@ yydefault:
 			yylex.(*parser).experimentalDurationExpr(de)
 			yyVAL.node = de
 		}
-	case 309:
+	case 320:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yylex.(*parser).experimentalDurationExpr(yyDollar[2].node.(Expr))
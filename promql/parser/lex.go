@@ -71,7 +71,7 @@ func (i ItemType) IsAggregatorWithParam() bool {
 // IsExperimentalAggregator defines the experimental aggregation functions that are controlled
 // with EnableExperimentalFunctions.
 func (i ItemType) IsExperimentalAggregator() bool {
-	return i == LIMITK || i == LIMIT_RATIO
+	return i == LIMITK || i == LIMIT_RATIO || i == MEDIAN || i == MODE
 }
 
 // IsKeyword returns true if the Item corresponds to a keyword.
@@ -126,9 +126,12 @@ var key = map[string]ItemType{
 	"quantile":     QUANTILE,
 	"limitk":       LIMITK,
 	"limit_ratio":  LIMIT_RATIO,
+	"median":       MEDIAN,
+	"mode":         MODE,
 
 	// Keywords.
 	"offset":      OFFSET,
+	"lookback":    LOOKBACK,
 	"smoothed":    SMOOTHED,
 	"anchored":    ANCHORED,
 	"by":          BY,
@@ -411,6 +414,16 @@ func Lex(input string) *Lexer {
 	return l
 }
 
+// LexSeriesDesc creates a new scanner for the input string, lexing it as a
+// series description rather than as a PromQL expression. Items are produced
+// one at a time via NextItem, so callers can process arbitrarily long value
+// sequences without holding all items in memory.
+func LexSeriesDesc(input string) *Lexer {
+	l := Lex(input)
+	l.seriesDesc = true
+	return l
+}
+
 // lineComment is the character that starts a line comment.
 const lineComment = "#"
 
@@ -46,6 +46,7 @@ type Options struct {
 	ExperimentalDurationExpr     bool
 	EnableExtendedRangeSelectors bool
 	EnableBinopFillModifiers     bool
+	EnableLookbackModifier       bool
 }
 
 // Parser provides PromQL parsing methods. Create one with NewParser.
@@ -55,6 +56,7 @@ type Parser interface {
 	ParseMetricSelector(input string) ([]*labels.Matcher, error)
 	ParseMetricSelectors(matchers []string) ([][]*labels.Matcher, error)
 	ParseSeriesDesc(input string) (labels.Labels, []SequenceValue, error)
+	ParseSeriesDescIncremental(input string, fn func(SequenceValue) error) (labels.Labels, error)
 	RegisterFeatures(r features.Collector)
 }
 
@@ -140,6 +142,35 @@ func (pql *promQLParser) ParseSeriesDesc(input string) (lbls labels.Labels, valu
 	return lbls, values, err
 }
 
+// ParseSeriesDescIncremental parses a series description like ParseSeriesDesc,
+// but instead of collecting all values into a slice it calls fn for each value
+// in order. This keeps memory usage flat for descriptions with very large
+// repetition counts (e.g. `x100000`), as generated by promtool rule tests. If
+// fn returns an error, no further values are produced and the error is
+// returned after parsing finishes.
+func (pql *promQLParser) ParseSeriesDescIncremental(input string, fn func(SequenceValue) error) (lbls labels.Labels, err error) {
+	p := newParser(input, pql.options)
+	p.lex.seriesDesc = true
+	p.seriesValueFn = fn
+
+	defer p.Close()
+	defer p.recover(&err)
+
+	parseResult := p.parseGenerated(START_SERIES_DESCRIPTION)
+	if parseResult != nil {
+		lbls = parseResult.(*seriesDescription).labels
+	}
+
+	switch {
+	case len(p.parseErrors) != 0:
+		err = p.parseErrors
+	case p.seriesValueFnErr != nil:
+		err = p.seriesValueFnErr
+	}
+
+	return lbls, err
+}
+
 type parser struct {
 	lex Lexer
 
@@ -163,6 +194,12 @@ type parser struct {
 	// This is used to populate CounterResetHintSet in SequenceValue.
 	lastHistogramCounterResetHintSet bool
 
+	// seriesValueFn, when set, receives each series description value as it
+	// is produced instead of the values being collected into a slice.
+	seriesValueFn func(SequenceValue) error
+	// seriesValueFnErr holds the first error returned by seriesValueFn.
+	seriesValueFnErr error
+
 	options Options
 }
 
@@ -175,6 +212,8 @@ func newParser(input string, opts Options) *parser {
 	p.parseErrors = nil
 	p.generatedParserResult = nil
 	p.lastClosing = posrange.Pos(0)
+	p.seriesValueFn = nil
+	p.seriesValueFnErr = nil
 	p.options = opts
 
 	// Clear lexer struct before reusing.
@@ -516,6 +555,51 @@ func (p *parser) mergeMaps(left, right *map[string]any) (ret *map[string]any) {
 	return left
 }
 
+// emitSeriesValue hands v to the streaming callback. After the callback has
+// returned an error once, further values are dropped.
+func (p *parser) emitSeriesValue(v SequenceValue) {
+	if p.seriesValueFnErr != nil {
+		return
+	}
+	p.seriesValueFnErr = p.seriesValueFn(v)
+}
+
+// seriesRepeat produces count copies of v. In streaming mode the values are
+// passed to the callback and nil is returned.
+func (p *parser) seriesRepeat(v SequenceValue, count uint64) []SequenceValue {
+	if p.seriesValueFn != nil {
+		for i := uint64(0); i < count && p.seriesValueFnErr == nil; i++ {
+			p.emitSeriesValue(v)
+		}
+		return nil
+	}
+	vals := make([]SequenceValue, count)
+	for i := range vals {
+		vals[i] = v
+	}
+	return vals
+}
+
+// seriesSteps produces times+1 values starting at base and increasing by
+// delta at each step. In streaming mode the values are passed to the callback
+// and nil is returned.
+func (p *parser) seriesSteps(base, delta float64, times uint64) []SequenceValue {
+	if p.seriesValueFn != nil {
+		for i := uint64(0); i <= times && p.seriesValueFnErr == nil; i++ {
+			p.emitSeriesValue(SequenceValue{Value: base})
+			base += delta
+		}
+		return nil
+	}
+	// Add an additional value (the base) for time 0, which we ignore in tests.
+	vals := make([]SequenceValue, times+1)
+	for i := range vals {
+		vals[i] = SequenceValue{Value: base}
+		base += delta
+	}
+	return vals
+}
+
 func (p *parser) histogramsIncreaseSeries(base, inc *histogram.FloatHistogram, times uint64) ([]SequenceValue, error) {
 	// Capture the hint set flag immediately after inc histogram is built.
 	// The base histogram's hint set flag was already captured.
@@ -535,14 +619,24 @@ func (p *parser) histogramsDecreaseSeries(base, inc *histogram.FloatHistogram, t
 	})
 }
 
-func (*parser) histogramsSeries(base, inc *histogram.FloatHistogram, times uint64, counterResetHintSet bool,
+func (p *parser) histogramsSeries(base, inc *histogram.FloatHistogram, times uint64, counterResetHintSet bool,
 	combine func(*histogram.FloatHistogram, *histogram.FloatHistogram) (*histogram.FloatHistogram, error),
 ) ([]SequenceValue, error) {
-	ret := make([]SequenceValue, times+1)
+	var ret []SequenceValue
+	emit := func(v SequenceValue) {
+		ret = append(ret, v)
+	}
+	if p.seriesValueFn != nil {
+		// Streaming mode: hand each value to the callback as it is computed,
+		// so only the current histogram is retained.
+		emit = p.emitSeriesValue
+	} else {
+		ret = make([]SequenceValue, 0, times+1)
+	}
 	// Add an additional value (the base) for time 0, which we ignore in tests.
-	ret[0] = SequenceValue{Histogram: base, CounterResetHintSet: counterResetHintSet}
+	emit(SequenceValue{Histogram: base, CounterResetHintSet: counterResetHintSet})
 	cur := base
-	for i := uint64(1); i <= times; i++ {
+	for i := uint64(1); i <= times && p.seriesValueFnErr == nil; i++ {
 		if cur.Schema > inc.Schema {
 			return nil, fmt.Errorf("error combining histograms: cannot merge from schema %d to %d", inc.Schema, cur.Schema)
 		}
@@ -552,7 +646,7 @@ func (*parser) histogramsSeries(base, inc *histogram.FloatHistogram, times uint6
 		if err != nil {
 			return ret, err
 		}
-		ret[i] = SequenceValue{Histogram: cur, CounterResetHintSet: counterResetHintSet}
+		emit(SequenceValue{Histogram: cur, CounterResetHintSet: counterResetHintSet})
 	}
 
 	return ret, nil
@@ -1075,6 +1169,29 @@ func (p *parser) addOffsetExpr(e Node, expr *DurationExpr) {
 	*endPosp = p.lastClosing
 }
 
+// setLookback is used to set the lookback delta override from the lookback
+// modifier in the generated parser.
+func (p *parser) setLookback(e Node, d time.Duration) {
+	if !p.options.EnableLookbackModifier {
+		p.addParseErrf(e.PositionRange(), "lookback modifier is experimental and not enabled")
+		return
+	}
+	vs, ok := e.(*VectorSelector)
+	if !ok {
+		p.addParseErrf(e.PositionRange(), "lookback modifier must be preceded by an instant vector selector")
+		return
+	}
+	switch {
+	case d <= 0:
+		p.addParseErrf(e.PositionRange(), "lookback modifier must be greater than zero")
+	case vs.Lookback != 0:
+		p.addParseErrf(e.PositionRange(), "lookback may not be set multiple times")
+	default:
+		vs.Lookback = d
+	}
+	vs.PosRange.End = p.lastClosing
+}
+
 func (p *parser) setAnchored(e Node) {
 	if !p.options.EnableExtendedRangeSelectors {
 		p.addParseErrf(e.PositionRange(), "anchored modifier is experimental and not enabled")
@@ -1702,6 +1702,62 @@ var testExpr = []struct {
 			PosRange: posrange.PositionRange{Start: 0, End: 13},
 		},
 	},
+	{
+		input: "foo lookback 30s",
+		expected: &VectorSelector{
+			Name:     "foo",
+			Lookback: 30 * time.Second,
+			LabelMatchers: []*labels.Matcher{
+				MustLabelMatcher(labels.MatchEqual, model.MetricNameLabel, "foo"),
+			},
+			PosRange: posrange.PositionRange{Start: 0, End: 16},
+		},
+	},
+	{
+		input: "foo offset 5m lookback 1h",
+		expected: &VectorSelector{
+			Name:           "foo",
+			OriginalOffset: 5 * time.Minute,
+			Lookback:       time.Hour,
+			LabelMatchers: []*labels.Matcher{
+				MustLabelMatcher(labels.MatchEqual, model.MetricNameLabel, "foo"),
+			},
+			PosRange: posrange.PositionRange{Start: 0, End: 25},
+		},
+	},
+	{
+		input: "foo lookback 30s lookback 1m",
+		fail:  true,
+		errors: ParseErrors{
+			ParseErr{
+				PositionRange: posrange.PositionRange{Start: 0, End: 16},
+				Err:           errors.New("lookback may not be set multiple times"),
+				Query:         "foo lookback 30s lookback 1m",
+			},
+		},
+	},
+	{
+		input: "rate(foo[5m]) lookback 30s",
+		fail:  true,
+		errors: ParseErrors{
+			ParseErr{
+				PositionRange: posrange.PositionRange{Start: 0, End: 13},
+				Err:           errors.New("lookback modifier must be preceded by an instant vector selector"),
+				Query:         "rate(foo[5m]) lookback 30s",
+			},
+		},
+	},
+	{
+		input: "foo lookback 30s[5m]",
+		fail:  true,
+		errors: ParseErrors{
+			ParseErr{
+				PositionRange: posrange.PositionRange{Start: 16, End: 20},
+				Err:           errors.New("no lookback modifiers allowed before range"),
+				Query:         "foo lookback 30s[5m]",
+			},
+		},
+	},
 	{
 		input: "foo offset -7m",
 		expected: &VectorSelector{
@@ -5369,11 +5425,17 @@ func readable(s string) string {
 	return s[:maxReadableStringLen] + "..."
 }
 
+func TestLookbackModifierDisabled(t *testing.T) {
+	_, err := testParser.ParseExpr("foo lookback 30s")
+	require.ErrorContains(t, err, "lookback modifier is experimental and not enabled")
+}
+
 func TestParseExpressions(t *testing.T) {
 	optsParser := NewParser(Options{
 		EnableExperimentalFunctions:  true,
 		ExperimentalDurationExpr:     true,
 		EnableExtendedRangeSelectors: true,
+		EnableLookbackModifier:       true,
 	})
 
 	for _, test := range testExpr {
@@ -5518,6 +5580,58 @@ func TestParseSeriesDesc(t *testing.T) {
 	}
 }
 
+func TestParseSeriesDescIncremental(t *testing.T) {
+	// The streaming parser must produce exactly the values of ParseSeriesDesc,
+	// in order, without collecting them.
+	inputs := []string{
+		``,
+		`http_requests{job="api-server"}`,
+		`http_requests{job="api-server"} 1 2 3`,
+		`http_requests{job="api-server"} 0+50x10`,
+		`http_requests{job="api-server"} 5 _x3 10x4 100-10x5`,
+		`{} {{schema:1 sum:3 count:2 buckets:[1 1]}}x3`,
+		`{} {{schema:0 sum:1 count:1 buckets:[1]}}+{{schema:0 sum:2 count:1 buckets:[1]}}x4`,
+		`{} {{sum:10 count:5 buckets:[5]}}-{{sum:2 count:1 buckets:[1]}}x3`,
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			expLabels, expValues, err := testParser.ParseSeriesDesc(input)
+			require.NoError(t, err)
+
+			var got []SequenceValue
+			gotLabels, err := testParser.ParseSeriesDescIncremental(input, func(v SequenceValue) error {
+				got = append(got, v)
+				return nil
+			})
+			require.NoError(t, err)
+			require.True(t, labels.Equal(expLabels, gotLabels))
+			require.Equal(t, expValues, append([]SequenceValue{}, got...))
+		})
+	}
+
+	t.Run("parse errors are reported", func(t *testing.T) {
+		_, err := testParser.ParseSeriesDescIncremental(`{"http.requests"xx} 0+50x2`, func(SequenceValue) error {
+			return nil
+		})
+		require.ErrorContains(t, err, `unexpected identifier "xx" in label set`)
+	})
+
+	t.Run("callback error stops the value stream", func(t *testing.T) {
+		sentinel := errors.New("stop")
+		var seen int
+		_, err := testParser.ParseSeriesDescIncremental(`{} 0+1x100000`, func(SequenceValue) error {
+			seen++
+			if seen == 3 {
+				return sentinel
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, sentinel)
+		require.Equal(t, 3, seen)
+	})
+}
+
 // NaN has no equality. Thus, we need a separate test for it.
 func TestNaNExpression(t *testing.T) {
 	expr, err := testParser.ParseExpr("NaN")
@@ -427,5 +427,9 @@ func (node *VectorSelector) String() string {
 		b.WriteString(" offset -")
 		b.WriteString(model.Duration(-node.OriginalOffset).String())
 	}
+	if node.Lookback != 0 {
+		b.WriteString(" lookback ")
+		b.WriteString(model.Duration(node.Lookback).String())
+	}
 	return b.String()
 }
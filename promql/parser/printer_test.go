@@ -26,6 +26,7 @@ func TestExprString(t *testing.T) {
 		ExperimentalDurationExpr:     true,
 		EnableExtendedRangeSelectors: true,
 		EnableBinopFillModifiers:     true,
+		EnableLookbackModifier:       true,
 	})
 	// A list of valid expressions that are expected to be
 	// returned as out when calling String(). If out is empty the output
@@ -147,6 +148,12 @@ func TestExprString(t *testing.T) {
 		{
 			in: `a offset -7m`,
 		},
+		{
+			in: `a lookback 30s`,
+		},
+		{
+			in: `a offset 1m lookback 1h`,
+		},
 		{
 			in: `a{c="d"}[5m] offset 1m`,
 		},
@@ -743,3 +743,50 @@ func quantile(q float64, values vectorByValueHeap) float64 {
 	weight := rank - math.Floor(rank)
 	return values[int(lowerIndex)].F*(1-weight) + values[int(upperIndex)].F*weight
 }
+
+// median calculates the exact median of a vector of samples: the middle value
+// after sorting, or the arithmetic mean of the two middle values for an even
+// number of samples. NaN is considered the smallest possible value.
+//
+// The Vector will be sorted.
+// If 'values' has zero elements, NaN is returned.
+func median(values vectorByValueHeap) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sort.Sort(values)
+
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2].F
+	}
+	return (values[n/2-1].F + values[n/2].F) / 2
+}
+
+// mode calculates the mode of a vector of samples, i.e. the most frequent
+// value. Ties are broken by returning the smallest of the most frequent
+// values. Since NaN is never equal to itself, NaN samples each count as a
+// distinct value.
+//
+// The Vector will be sorted.
+// If 'values' has zero elements, NaN is returned.
+func mode(values vectorByValueHeap) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sort.Sort(values)
+
+	mode, modeCount := values[0].F, 1
+	runValue, runCount := values[0].F, 1
+	for _, v := range values[1:] {
+		if v.F == runValue {
+			runCount++
+		} else {
+			runValue, runCount = v.F, 1
+		}
+		if runCount > modeCount {
+			mode, modeCount = runValue, runCount
+		}
+	}
+	return mode
+}
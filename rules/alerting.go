@@ -87,6 +87,11 @@ type Alert struct {
 	Labels      labels.Labels
 	Annotations labels.Labels
 
+	// GeneratorURL is the expanded per-alert generator URL. Empty when the
+	// rule does not set a generator URL template; the notifier then links to
+	// the expression browser.
+	GeneratorURL string
+
 	// The value at the last evaluation of the alerting expression.
 	Value float64
 	// The interval during which the condition of this alert held true.
@@ -97,8 +102,22 @@ type Alert struct {
 	LastSentAt      time.Time
 	ValidUntil      time.Time
 	KeepFiringSince time.Time
+
+	// refired marks an alert that became active again after having resolved
+	// within the retention period; it counts as a flap once it starts firing.
+	refired bool
+	// firingAccruedTo is the time up to which this alert's firing state has
+	// been added to the rule's cumulative firing duration.
+	firingAccruedTo time.Time
 }
 
+// AlertStateChangeFunc is called whenever an alert instance transitions
+// between states during rule evaluation, including from and to StateInactive.
+// The alert is passed by value so implementations may retain it. The function
+// is called while the rule's active alerts are locked and therefore must not
+// block or call back into the rule.
+type AlertStateChangeFunc func(rule *AlertingRule, previous AlertState, alert Alert)
+
 func (a *Alert) needsSending(ts time.Time, resendDelay time.Duration) bool {
 	if a.State == StatePending {
 		return false
@@ -128,6 +147,9 @@ type AlertingRule struct {
 	labels labels.Labels
 	// Non-identifying key/value pairs.
 	annotations labels.Labels
+	// Template for a per-alert generator URL. Empty means the default link to
+	// the expression browser.
+	generatorURLTmpl string
 	// External labels from the global config.
 	externalLabels map[string]string
 	// The external URL from the --web.external-url flag.
@@ -137,6 +159,9 @@ type AlertingRule struct {
 	restored *atomic.Bool
 	// Time in seconds taken to evaluate rule.
 	evaluationDuration *atomic.Duration
+	// Resource usage stats of the last successful evaluation of the rule.
+	evaluationStats atomic.Pointer[EvaluationStats]
+	lastSamples     atomic.Pointer[[]promql.Sample]
 	// Timestamp of last evaluation of rule.
 	evaluationTimestamp *atomic.Time
 	// The health of the alerting rule.
@@ -148,6 +173,17 @@ type AlertingRule struct {
 	// A map of alerts which are currently active (Pending or Firing), keyed by
 	// the fingerprint of the labelset they correspond to.
 	active map[uint64]*Alert
+	// Called whenever an alert instance changes state during evaluation.
+	stateChangeFunc AlertStateChangeFunc
+	// Number of alert notifications sent for this rule, including resolved
+	// notifications.
+	notificationsSent *atomic.Int64
+	// Number of times an alert instance of this rule started firing again
+	// after having resolved within the retention period.
+	flaps *atomic.Int64
+	// Cumulative time alert instances of this rule have spent in the firing
+	// state.
+	firingDuration *atomic.Duration
 
 	logger *slog.Logger
 
@@ -180,6 +216,9 @@ func NewAlertingRule(
 		evaluationTimestamp: atomic.NewTime(time.Time{}),
 		evaluationDuration:  atomic.NewDuration(0),
 		lastError:           atomic.NewError(nil),
+		notificationsSent:   atomic.NewInt64(0),
+		flaps:               atomic.NewInt64(0),
+		firingDuration:      atomic.NewDuration(0),
 	}
 }
 
@@ -234,6 +273,32 @@ func (r *AlertingRule) Annotations() labels.Labels {
 	return r.annotations
 }
 
+// SetGeneratorURLTemplate sets the template for per-alert generator URLs. An
+// empty template makes the notifier link alerts to the expression browser.
+// It must be called before the rule is evaluated for the first time.
+func (r *AlertingRule) SetGeneratorURLTemplate(tmpl string) {
+	r.generatorURLTmpl = tmpl
+}
+
+// SetStateChangeFunc sets the function called when an alert instance changes
+// state. It must be called before the rule is evaluated for the first time.
+func (r *AlertingRule) SetStateChangeFunc(f AlertStateChangeFunc) {
+	r.stateChangeFunc = f
+}
+
+// notifyStateChange invokes the configured state change function, if any.
+// Callers must hold r.activeMtx.
+func (r *AlertingRule) notifyStateChange(previous AlertState, a *Alert) {
+	if r.stateChangeFunc != nil {
+		r.stateChangeFunc(r, previous, *a)
+	}
+}
+
+// GeneratorURLTemplate returns the template for per-alert generator URLs.
+func (r *AlertingRule) GeneratorURLTemplate() string {
+	return r.generatorURLTmpl
+}
+
 func (r *AlertingRule) sample(alert *Alert, ts time.Time) promql.Sample {
 	lb := labels.NewBuilder(r.labels)
 
@@ -308,6 +373,32 @@ func (r *AlertingRule) GetEvaluationDuration() time.Duration {
 	return r.evaluationDuration.Load()
 }
 
+// SetEvaluationStats updates the resource usage stats of the last successful evaluation of the alerting rule.
+func (r *AlertingRule) SetEvaluationStats(s EvaluationStats) {
+	r.evaluationStats.Store(&s)
+}
+
+// SetLastSamples caches a preview of the result samples of the last successful evaluation of the alerting rule.
+func (r *AlertingRule) SetLastSamples(s []promql.Sample) {
+	r.lastSamples.Store(&s)
+}
+
+// GetLastSamples returns the cached result sample preview of the last successful evaluation of the alerting rule.
+func (r *AlertingRule) GetLastSamples() []promql.Sample {
+	if s := r.lastSamples.Load(); s != nil {
+		return *s
+	}
+	return nil
+}
+
+// GetEvaluationStats returns the resource usage stats of the last successful evaluation of the alerting rule.
+func (r *AlertingRule) GetEvaluationStats() EvaluationStats {
+	if s := r.evaluationStats.Load(); s != nil {
+		return *s
+	}
+	return EvaluationStats{}
+}
+
 // SetEvaluationTimestamp updates evaluationTimestamp to the timestamp of when the rule was last evaluated.
 func (r *AlertingRule) SetEvaluationTimestamp(ts time.Time) {
 	r.evaluationTimestamp.Store(ts)
@@ -445,6 +536,11 @@ func (r *AlertingRule) Eval(ctx context.Context, queryOffset time.Duration, ts t
 		})
 		annotations := sb.Labels()
 
+		var generatorURL string
+		if r.generatorURLTmpl != "" {
+			generatorURL = expand(r.generatorURLTmpl)
+		}
+
 		lbs := lb.Labels()
 		h := lbs.Hash()
 		resultFPs[h] = struct{}{}
@@ -454,11 +550,12 @@ func (r *AlertingRule) Eval(ctx context.Context, queryOffset time.Duration, ts t
 		}
 
 		alerts[h] = &Alert{
-			Labels:      lbs,
-			Annotations: annotations,
-			ActiveAt:    ts,
-			State:       StatePending,
-			Value:       smpl.F,
+			Labels:       lbs,
+			Annotations:  annotations,
+			GeneratorURL: generatorURL,
+			ActiveAt:     ts,
+			State:        StatePending,
+			Value:        smpl.F,
 		}
 	}
 
@@ -468,18 +565,38 @@ func (r *AlertingRule) Eval(ctx context.Context, queryOffset time.Duration, ts t
 	for h, a := range alerts {
 		// Check whether we already have alerting state for the identifying label set.
 		// Update the last value and annotations if so, create a new alert entry otherwise.
-		if alert, ok := r.active[h]; ok && alert.State != StateInactive {
-			alert.Value = a.Value
-			alert.Annotations = a.Annotations
-			continue
+		if alert, ok := r.active[h]; ok {
+			if alert.State != StateInactive {
+				alert.Value = a.Value
+				alert.Annotations = a.Annotations
+				alert.GeneratorURL = a.GeneratorURL
+				continue
+			}
+			// The previous alert for this label set resolved within the
+			// retention period; if the new one starts firing, that is a flap.
+			a.refired = !alert.ResolvedAt.IsZero()
 		}
 
 		r.active[h] = a
+		r.notifyStateChange(StateInactive, a)
 	}
 
 	var numActivePending int
 	// Check if any pending alerts should be removed or fire now. Write out alert timeseries.
 	for fp, a := range r.active {
+		// Accrue the time spent firing since the last evaluation before any
+		// state transitions are applied.
+		if a.State == StateFiring {
+			since := a.FiredAt
+			if a.firingAccruedTo.After(since) {
+				since = a.firingAccruedTo
+			}
+			if ts.After(since) {
+				r.firingDuration.Add(ts.Sub(since))
+			}
+			a.firingAccruedTo = ts
+		}
+
 		if _, ok := resultFPs[fp]; !ok {
 			// There is no firing alerts for this fingerprint. The alert is no
 			// longer firing.
@@ -511,8 +628,10 @@ func (r *AlertingRule) Eval(ctx context.Context, queryOffset time.Duration, ts t
 				delete(r.active, fp)
 			}
 			if a.State != StateInactive && !keepFiring {
+				previous := a.State
 				a.State = StateInactive
 				a.ResolvedAt = ts
+				r.notifyStateChange(previous, a)
 			}
 			if !keepFiring {
 				continue
@@ -526,6 +645,11 @@ func (r *AlertingRule) Eval(ctx context.Context, queryOffset time.Duration, ts t
 		if a.State == StatePending && ts.Sub(a.ActiveAt) >= r.holdDuration {
 			a.State = StateFiring
 			a.FiredAt = ts
+			if a.refired {
+				a.refired = false
+				r.flaps.Inc()
+			}
+			r.notifyStateChange(StatePending, a)
 		}
 
 		// If the alert is firing and the active time is less than the new hold duration, set the state to pending.
@@ -534,6 +658,7 @@ func (r *AlertingRule) Eval(ctx context.Context, queryOffset time.Duration, ts t
 			a.FiredAt = time.Time{}
 			a.LastSentAt = time.Time{}
 			a.KeepFiringSince = time.Time{}
+			r.notifyStateChange(StateFiring, a)
 		}
 
 		if r.restored.Load() {
@@ -629,9 +754,28 @@ func (r *AlertingRule) sendAlerts(ctx context.Context, ts time.Time, resendDelay
 			alerts = append(alerts, &anew)
 		}
 	})
+	r.notificationsSent.Add(int64(len(alerts)))
 	notifyFunc(ctx, r.vector.String(), alerts...)
 }
 
+// NotificationsSent returns the number of alert notifications this rule has
+// handed to the notifier, including resolved notifications and resends.
+func (r *AlertingRule) NotificationsSent() int64 {
+	return r.notificationsSent.Load()
+}
+
+// Flaps returns the number of times an alert instance of this rule started
+// firing again after having resolved within the retention period.
+func (r *AlertingRule) Flaps() int64 {
+	return r.flaps.Load()
+}
+
+// FiringDuration returns the cumulative time alert instances of this rule
+// have spent in the firing state, accrued at each evaluation.
+func (r *AlertingRule) FiringDuration() time.Duration {
+	return r.firingDuration.Load()
+}
+
 func (r *AlertingRule) String() string {
 	ar := rulefmt.Rule{
 		Alert:         r.name,
@@ -259,6 +259,51 @@ func TestAlertingRuleLabelsUpdate(t *testing.T) {
 	require.Empty(t, res)
 }
 
+func TestAlertingRuleStateChangeFunc(t *testing.T) {
+	storage := promqltest.LoadedStorage(t, `
+		load 1m
+			http_requests{job="app-server", instance="0"}	75 85 stale
+	`)
+
+	expr, err := testParser.ParseExpr(`http_requests < 100`)
+	require.NoError(t, err)
+
+	rule := NewAlertingRule(
+		"HTTPRequestRateLow",
+		expr,
+		time.Minute,
+		0,
+		labels.EmptyLabels(), labels.EmptyLabels(), labels.EmptyLabels(), "", true, nil,
+	)
+
+	type stateChange struct {
+		previous, current AlertState
+	}
+	var changes []stateChange
+	rule.SetStateChangeFunc(func(r *AlertingRule, previous AlertState, alert Alert) {
+		require.Same(t, rule, r)
+		require.Equal(t, "HTTPRequestRateLow", alert.Labels.Get(model.AlertNameLabel))
+		changes = append(changes, stateChange{previous: previous, current: alert.State})
+	})
+
+	ng := testEngine(t)
+
+	// The alert goes pending on the first evaluation, fires once the hold
+	// duration has passed, and resolves when the series goes stale.
+	baseTime := time.Unix(0, 0)
+	for i := range 3 {
+		evalTime := baseTime.Add(time.Duration(i) * time.Minute)
+		_, err := rule.Eval(context.TODO(), 0, evalTime, EngineQueryFunc(ng, storage), nil, 0)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []stateChange{
+		{previous: StateInactive, current: StatePending},
+		{previous: StatePending, current: StateFiring},
+		{previous: StateFiring, current: StateInactive},
+	}, changes)
+}
+
 func TestAlertingRuleExternalLabelsInTemplate(t *testing.T) {
 	storage := promqltest.LoadedStorage(t, `
 		load 1m
@@ -447,6 +492,39 @@ func TestAlertingRuleExternalURLInTemplate(t *testing.T) {
 	testutil.RequireEqual(t, result, filteredRes)
 }
 
+func TestAlertingRuleGeneratorURLTemplate(t *testing.T) {
+	storage := promqltest.LoadedStorage(t, `
+		load 1m
+			http_requests{job="app-server", instance="0"}	75 85 70 70
+	`)
+
+	expr, err := testParser.ParseExpr(`http_requests < 100`)
+	require.NoError(t, err)
+
+	rule := NewAlertingRule(
+		"TemplatedGeneratorURL",
+		expr,
+		time.Minute,
+		0,
+		labels.EmptyLabels(),
+		labels.EmptyLabels(),
+		labels.FromStrings("cluster", "eu-1"),
+		"http://localhost:1234",
+		true, promslog.NewNopLogger(),
+	)
+	rule.SetGeneratorURLTemplate(`https://grafana.example/explore?job={{ $labels.job }}&cluster={{ $externalLabels.cluster }}`)
+
+	ng := testEngine(t)
+	_, err = rule.Eval(
+		context.TODO(), 0, time.Unix(0, 0), EngineQueryFunc(ng, storage), nil, 0,
+	)
+	require.NoError(t, err)
+
+	activeAlerts := rule.ActiveAlerts()
+	require.Len(t, activeAlerts, 1)
+	require.Equal(t, "https://grafana.example/explore?job=app-server&cluster=eu-1", activeAlerts[0].GeneratorURL)
+}
+
 func TestAlertingRuleEmptyLabelFromTemplate(t *testing.T) {
 	storage := promqltest.LoadedStorage(t, `
 		load 1m
@@ -1188,3 +1266,70 @@ func TestFiringAlertResetToPendingOnHoldDurationIncrease(t *testing.T) {
 		}
 	}
 }
+
+func TestAlertingRuleFatigueTracking(t *testing.T) {
+	firing := promql.Vector{promql.Sample{
+		Metric: labels.FromStrings("__name__", "up", "instance", "localhost:9090"),
+		F:      0,
+	}}
+	var res promql.Vector
+	query := func(_ context.Context, _ string, _ time.Time) (promql.Vector, error) {
+		return res, nil
+	}
+
+	expr, err := testParser.ParseExpr(`up == 0`)
+	require.NoError(t, err)
+	rule := NewAlertingRule(
+		"InstanceDown", expr, 0, 0,
+		labels.EmptyLabels(), labels.EmptyLabels(), labels.EmptyLabels(),
+		"", true, promslog.NewNopLogger(),
+	)
+
+	var notified int
+	notify := func(_ context.Context, _ string, alerts ...*Alert) {
+		notified += len(alerts)
+	}
+
+	baseTime := time.Unix(0, 0)
+	interval := time.Minute
+	eval := func(i int, active bool) {
+		res = nil
+		if active {
+			res = firing
+		}
+		_, err := rule.Eval(context.TODO(), 0, baseTime.Add(time.Duration(i)*interval), query, nil, 0)
+		require.NoError(t, err)
+		rule.SetEvaluationTimestamp(baseTime.Add(time.Duration(i) * interval))
+		rule.sendAlerts(context.TODO(), baseTime.Add(time.Duration(i)*interval), 0, interval, notify)
+	}
+
+	// The alert fires immediately (no hold duration) and stays firing for
+	// two intervals before resolving.
+	eval(0, true)
+	require.Equal(t, StateFiring, rule.State())
+	require.Equal(t, time.Duration(0), rule.FiringDuration())
+	require.Equal(t, int64(0), rule.Flaps())
+	require.Equal(t, int64(1), rule.NotificationsSent())
+
+	eval(1, true)
+	require.Equal(t, interval, rule.FiringDuration())
+
+	eval(2, false)
+	require.Equal(t, StateInactive, rule.State())
+	require.Equal(t, 2*interval, rule.FiringDuration())
+	require.Equal(t, int64(0), rule.Flaps())
+
+	// Firing again after having resolved counts as a flap.
+	eval(3, true)
+	require.Equal(t, StateFiring, rule.State())
+	require.Equal(t, int64(1), rule.Flaps())
+
+	eval(4, false)
+	require.Equal(t, 3*interval, rule.FiringDuration())
+	require.Equal(t, int64(1), rule.Flaps())
+
+	// With no resend delay every firing evaluation and both resolutions
+	// were notified.
+	require.Equal(t, int64(5), rule.NotificationsSent())
+	require.Equal(t, int(rule.NotificationsSent()), notified)
+}
@@ -47,6 +47,7 @@ type Group struct {
 	file                  string
 	interval              time.Duration
 	queryOffset           *time.Duration
+	evaluationTimeout     time.Duration
 	limit                 int
 	rules                 []Rule
 	seriesInPreviousEval  []map[string]labels.Labels // One per Rule.
@@ -86,6 +87,7 @@ type GroupEvalIterationFunc func(ctx context.Context, g *Group, evalTimestamp ti
 type GroupOptions struct {
 	Name, File        string
 	Interval          time.Duration
+	EvaluationTimeout time.Duration
 	Limit             int
 	Rules             []Rule
 	ShouldRestore     bool
@@ -116,6 +118,7 @@ func NewGroup(o GroupOptions) *Group {
 	metrics.GroupLastRuleDurationSum.WithLabelValues(key)
 	metrics.GroupRules.WithLabelValues(key).Set(float64(len(o.Rules)))
 	metrics.GroupSamples.WithLabelValues(key)
+	metrics.GroupLastQueryableSamples.WithLabelValues(key)
 	metrics.GroupInterval.WithLabelValues(key).Set(o.Interval.Seconds())
 
 	evalIterationFunc := o.EvalIterationFunc
@@ -132,6 +135,7 @@ func NewGroup(o GroupOptions) *Group {
 		file:                 o.File,
 		interval:             o.Interval,
 		queryOffset:          o.QueryOffset,
+		evaluationTimeout:    o.EvaluationTimeout,
 		limit:                o.Limit,
 		rules:                o.Rules,
 		shouldRestore:        o.ShouldRestore,
@@ -203,6 +207,10 @@ func (g *Group) Interval() time.Duration { return g.interval }
 // Limit returns the group's limit.
 func (g *Group) Limit() int { return g.limit }
 
+// EvaluationTimeout returns the timeout applied to each rule evaluation in
+// the group. Zero means no group-level timeout is enforced.
+func (g *Group) EvaluationTimeout() time.Duration { return g.evaluationTimeout }
+
 func (g *Group) Logger() *slog.Logger { return g.logger }
 
 func (g *Group) run(ctx context.Context) {
@@ -503,9 +511,15 @@ func (g *Group) CopyState(from *Group) {
 // Rules can be evaluated concurrently if the `concurrent-rule-eval` feature flag is enabled.
 func (g *Group) Eval(ctx context.Context, ts time.Time) {
 	var (
-		samplesTotal    atomic.Float64
-		ruleQueryOffset = g.QueryOffset()
+		samplesTotal          atomic.Float64
+		queryableSamplesTotal atomic.Int64
+		ruleQueryOffset       = g.QueryOffset()
 	)
+
+	// Share subquery results across the rules of this cycle: burn-rate rule
+	// variants commonly repeat the same subquery. Reuse is safe because all
+	// rules evaluate at the same timestamp, which is part of the cache key.
+	ctx = promql.NewSubqueryCacheContext(ctx, promql.NewSubqueryCache())
 	eval := func(i int, rule Rule, cleanup func()) {
 		if cleanup != nil {
 			defer cleanup()
@@ -530,7 +544,21 @@ func (g *Group) Eval(ctx context.Context, ts time.Time) {
 
 		g.metrics.EvalTotal.WithLabelValues(GroupKey(g.File(), g.Name())).Inc()
 
-		vector, err := rule.Eval(ctx, ruleQueryOffset, ts, g.opts.QueryFunc, g.opts.ExternalURL, g.Limit())
+		// The group-level evaluation timeout bounds each rule individually, so
+		// a runaway rule is cancelled and reported as failed while the
+		// remaining rules of the group are still evaluated.
+		evalCtx := ctx
+		if timeout := g.EvaluationTimeout(); timeout > 0 {
+			var cancel context.CancelFunc
+			evalCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		// Collect query statistics for the rule if the QueryFunc provides them.
+		var evalStats EvaluationStats
+		evalCtx = newEvalStatsContext(evalCtx, &evalStats)
+
+		vector, err := rule.Eval(evalCtx, ruleQueryOffset, ts, g.opts.QueryFunc, g.opts.ExternalURL, g.Limit())
 		if err != nil {
 			rule.SetHealth(HealthBad)
 			rule.SetLastError(err)
@@ -548,6 +576,10 @@ func (g *Group) Eval(ctx context.Context, ts time.Time) {
 		rule.SetHealth(HealthGood)
 		rule.SetLastError(nil)
 		samplesTotal.Add(float64(len(vector)))
+		evalStats.ResultSeries = len(vector)
+		rule.SetEvaluationStats(evalStats)
+		rule.SetLastSamples(samplePreview(vector))
+		queryableSamplesTotal.Add(evalStats.TotalSamples)
 
 		if ar, ok := rule.(*AlertingRule); ok {
 			ar.sendAlerts(ctx, ts, g.opts.ResendDelay, g.interval, g.opts.NotifyFunc)
@@ -687,9 +719,25 @@ func (g *Group) Eval(ctx context.Context, ts time.Time) {
 	}
 
 	g.metrics.GroupSamples.WithLabelValues(GroupKey(g.File(), g.Name())).Set(samplesTotal.Load())
+	g.metrics.GroupLastQueryableSamples.WithLabelValues(GroupKey(g.File(), g.Name())).Set(float64(queryableSamplesTotal.Load()))
 	g.cleanupStaleSeries(ctx, ts)
 }
 
+// maxLastSamples is the maximum number of result samples cached per rule for
+// the last evaluation preview exposed via the API.
+const maxLastSamples = 100
+
+// samplePreview returns a bounded copy of an evaluation result, safe to cache
+// on the rule after the vector itself has been handed to the appender.
+func samplePreview(vector promql.Vector) []promql.Sample {
+	if len(vector) == 0 {
+		return nil
+	}
+	preview := make([]promql.Sample, min(len(vector), maxLastSamples))
+	copy(preview, vector)
+	return preview
+}
+
 func (g *Group) QueryOffset() time.Duration {
 	if g.queryOffset != nil {
 		return *g.queryOffset
@@ -884,6 +932,10 @@ func (g *Group) Equals(ng *Group) bool {
 		return false
 	}
 
+	if g.evaluationTimeout != ng.evaluationTimeout {
+		return false
+	}
+
 	if ((g.queryOffset == nil) != (ng.queryOffset == nil)) || (g.queryOffset != nil && ng.queryOffset != nil && *g.queryOffset != *ng.queryOffset) {
 		return false
 	}
@@ -926,6 +978,7 @@ type Metrics struct {
 	GroupLastRestoreDuration   *prometheus.GaugeVec
 	GroupRules                 *prometheus.GaugeVec
 	GroupSamples               *prometheus.GaugeVec
+	GroupLastQueryableSamples  *prometheus.GaugeVec
 }
 
 // NewGroupMetrics creates a new instance of Metrics and registers it with the provided registerer,
@@ -1051,6 +1104,14 @@ func NewGroupMetrics(reg prometheus.Registerer) *Metrics {
 			},
 			[]string{"rule_group"},
 		),
+		GroupLastQueryableSamples: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "rule_group_last_queryable_samples",
+				Help:      "The number of samples loaded from storage during the last rule group evaluation.",
+			},
+			[]string{"rule_group"},
+		),
 	}
 
 	if reg != nil {
@@ -1070,6 +1131,7 @@ func NewGroupMetrics(reg prometheus.Registerer) *Metrics {
 			m.GroupLastRestoreDuration,
 			m.GroupRules,
 			m.GroupSamples,
+			m.GroupLastQueryableSamples,
 		)
 	}
 
@@ -59,6 +59,12 @@ func EngineQueryFunc(engine promql.QueryEngine, q storage.Queryable) QueryFunc {
 		if res.Err != nil {
 			return nil, res.Err
 		}
+		if dst := evalStatsFromContext(ctx); dst != nil {
+			if stats := q.Stats(); stats != nil && stats.Samples != nil {
+				dst.TotalSamples = stats.Samples.TotalSamples
+				dst.PeakSamples = stats.Samples.PeakSamples
+			}
+		}
 		switch v := res.Value.(type) {
 		case promql.Vector:
 			return v, nil
@@ -117,6 +123,7 @@ type ManagerOptions struct {
 	ExternalURL               *url.URL
 	QueryFunc                 QueryFunc
 	NotifyFunc                NotifyFunc
+	AlertStateChangeFunc      AlertStateChangeFunc
 	Context                   context.Context
 	Appendable                storage.Appendable
 	Queryable                 storage.Queryable
@@ -203,6 +210,10 @@ func NewManager(o *ManagerOptions) *Manager {
 		restoreNewRuleGroups: o.RestoreNewRuleGroups,
 	}
 
+	if o.Registerer != nil {
+		o.Registerer.MustRegister(newAlertFatigueCollector(m))
+	}
+
 	return m
 }
 
@@ -375,7 +386,7 @@ func (m *Manager) LoadGroups(
 				mLabels := FromMaps(rg.Labels, r.Labels)
 
 				if r.Alert != "" {
-					rules = append(rules, NewAlertingRule(
+					ar := NewAlertingRule(
 						r.Alert,
 						expr,
 						time.Duration(r.For),
@@ -386,7 +397,12 @@ func (m *Manager) LoadGroups(
 						externalURL,
 						!shouldRestore,
 						m.logger.With("alert", r.Alert),
-					))
+					)
+					ar.SetGeneratorURLTemplate(r.GeneratorURL)
+					if m.opts.AlertStateChangeFunc != nil {
+						ar.SetStateChangeFunc(m.opts.AlertStateChangeFunc)
+					}
+					rules = append(rules, ar)
 					continue
 				}
 				rules = append(rules, NewRecordingRule(
@@ -403,6 +419,7 @@ func (m *Manager) LoadGroups(
 				Name:              rg.Name,
 				File:              fn,
 				Interval:          itv,
+				EvaluationTimeout: time.Duration(rg.EvaluationTimeout),
 				Limit:             rg.Limit,
 				Rules:             rules,
 				ShouldRestore:     shouldRestore,
@@ -480,7 +497,10 @@ func SendAlerts(s Sender, externalURL string) NotifyFunc {
 				StartsAt:     alert.FiredAt,
 				Labels:       alert.Labels,
 				Annotations:  alert.Annotations,
-				GeneratorURL: externalURL + strutil.TableLinkForExpression(expr),
+				GeneratorURL: alert.GeneratorURL,
+			}
+			if a.GeneratorURL == "" {
+				a.GeneratorURL = externalURL + strutil.TableLinkForExpression(expr)
 			}
 			if !alert.ResolvedAt.IsZero() {
 				a.EndsAt = alert.ResolvedAt
@@ -654,3 +674,76 @@ func ParseFiles(
 	}
 	return nil
 }
+
+// alertFatigueCollector exposes per-alerting-rule fatigue metrics from the
+// rule manager: how many notifications a rule has produced, how often its
+// alerts flapped, and how long its alerts have been firing in total. Rules
+// with the same name within a group are aggregated into a single series.
+type alertFatigueCollector struct {
+	manager *Manager
+
+	notificationsSent *prometheus.Desc
+	flaps             *prometheus.Desc
+	firingTime        *prometheus.Desc
+}
+
+func newAlertFatigueCollector(m *Manager) *alertFatigueCollector {
+	ruleLabels := []string{"rule_group", "name"}
+	return &alertFatigueCollector{
+		manager: m,
+		notificationsSent: prometheus.NewDesc(
+			"prometheus_rule_alert_notifications_sent_total",
+			"The total number of alert notifications handed to the notifier by an alerting rule, including resolved notifications and resends.",
+			ruleLabels, nil,
+		),
+		flaps: prometheus.NewDesc(
+			"prometheus_rule_alert_flaps_total",
+			"The total number of times an alert of an alerting rule started firing again after having resolved.",
+			ruleLabels, nil,
+		),
+		firingTime: prometheus.NewDesc(
+			"prometheus_rule_alert_firing_time_seconds_total",
+			"The total time alerts of an alerting rule have spent in the firing state.",
+			ruleLabels, nil,
+		),
+	}
+}
+
+func (c *alertFatigueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.notificationsSent
+	ch <- c.flaps
+	ch <- c.firingTime
+}
+
+func (c *alertFatigueCollector) Collect(ch chan<- prometheus.Metric) {
+	type ruleKey struct {
+		group, name string
+	}
+	type ruleVals struct {
+		notificationsSent, flaps, firingTime float64
+	}
+	agg := map[ruleKey]*ruleVals{}
+	for _, g := range c.manager.RuleGroups() {
+		groupKey := GroupKey(g.File(), g.Name())
+		for _, rule := range g.Rules() {
+			ar, ok := rule.(*AlertingRule)
+			if !ok {
+				continue
+			}
+			k := ruleKey{group: groupKey, name: ar.Name()}
+			v, ok := agg[k]
+			if !ok {
+				v = &ruleVals{}
+				agg[k] = v
+			}
+			v.notificationsSent += float64(ar.NotificationsSent())
+			v.flaps += float64(ar.Flaps())
+			v.firingTime += ar.FiringDuration().Seconds()
+		}
+	}
+	for k, v := range agg {
+		ch <- prometheus.MustNewConstMetric(c.notificationsSent, prometheus.CounterValue, v.notificationsSent, k.group, k.name)
+		ch <- prometheus.MustNewConstMetric(c.flaps, prometheus.CounterValue, v.flaps, k.group, k.name)
+		ch <- prometheus.MustNewConstMetric(c.firingTime, prometheus.CounterValue, v.firingTime, k.group, k.name)
+	}
+}
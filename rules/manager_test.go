@@ -607,6 +607,121 @@ func TestStaleness(t *testing.T) {
 	}
 }
 
+func TestRuleEvaluationStats(t *testing.T) {
+	st := teststorage.New(t)
+	t.Cleanup(func() { st.Close() })
+
+	engineOpts := promql.EngineOpts{
+		Logger:     nil,
+		Reg:        nil,
+		MaxSamples: 100,
+		Timeout:    10 * time.Second,
+	}
+	engine := promqltest.NewTestEngineWithOpts(t, engineOpts)
+	opts := &ManagerOptions{
+		QueryFunc:  EngineQueryFunc(engine, st),
+		Appendable: st,
+		Queryable:  st,
+		Context:    context.Background(),
+		Logger:     promslog.NewNopLogger(),
+		NotifyFunc: func(context.Context, string, ...*Alert) {},
+	}
+
+	expr, err := testParser.ParseExpr("sum(a)")
+	require.NoError(t, err)
+	recordingRule := NewRecordingRule("sum_a", expr, labels.Labels{})
+	expr, err = testParser.ParseExpr("sum(a) > 0")
+	require.NoError(t, err)
+	alertingRule := NewAlertingRule("SumAPositive", expr, time.Minute, 0, labels.Labels{}, labels.Labels{}, labels.Labels{}, "", true, nil)
+	group := NewGroup(GroupOptions{
+		Name:          "default",
+		Interval:      time.Second,
+		Rules:         []Rule{recordingRule, alertingRule},
+		ShouldRestore: true,
+		Opts:          opts,
+	})
+
+	// Rules have not been evaluated yet, so no stats are reported.
+	require.Equal(t, EvaluationStats{}, recordingRule.GetEvaluationStats())
+
+	app := st.Appender(context.Background())
+	app.Append(0, labels.FromStrings(model.MetricNameLabel, "a", "instance", "1"), 0, 1)
+	app.Append(0, labels.FromStrings(model.MetricNameLabel, "a", "instance", "2"), 0, 2)
+	require.NoError(t, app.Commit())
+
+	group.Eval(context.Background(), time.Unix(0, 0))
+
+	recordingStats := recordingRule.GetEvaluationStats()
+	require.Equal(t, int64(2), recordingStats.TotalSamples)
+	require.Positive(t, recordingStats.PeakSamples)
+	require.Equal(t, 1, recordingStats.ResultSeries)
+
+	alertingStats := alertingRule.GetEvaluationStats()
+	require.Equal(t, int64(2), alertingStats.TotalSamples)
+	require.Positive(t, alertingStats.PeakSamples)
+	// One firing alert produces an ALERTS and an ALERTS_FOR_STATE series.
+	require.Equal(t, 2, alertingStats.ResultSeries)
+
+	// The group gauge reports the samples loaded across all rules of the group.
+	require.EqualValues(t, recordingStats.TotalSamples+alertingStats.TotalSamples, testutil.ToFloat64(group.metrics.GroupLastQueryableSamples))
+}
+
+func TestRuleLastSamples(t *testing.T) {
+	st := teststorage.New(t)
+	t.Cleanup(func() { st.Close() })
+
+	engineOpts := promql.EngineOpts{
+		Logger:     nil,
+		Reg:        nil,
+		MaxSamples: 1000,
+		Timeout:    10 * time.Second,
+	}
+	engine := promqltest.NewTestEngineWithOpts(t, engineOpts)
+	opts := &ManagerOptions{
+		QueryFunc:  EngineQueryFunc(engine, st),
+		Appendable: st,
+		Queryable:  st,
+		Context:    context.Background(),
+		Logger:     promslog.NewNopLogger(),
+		NotifyFunc: func(context.Context, string, ...*Alert) {},
+	}
+
+	expr, err := testParser.ParseExpr("sum(a)")
+	require.NoError(t, err)
+	recordingRule := NewRecordingRule("sum_a", expr, labels.Labels{})
+	expr, err = testParser.ParseExpr("b")
+	require.NoError(t, err)
+	wideRule := NewRecordingRule("b_copy", expr, labels.Labels{})
+	group := NewGroup(GroupOptions{
+		Name:          "default",
+		Interval:      time.Second,
+		Rules:         []Rule{recordingRule, wideRule},
+		ShouldRestore: true,
+		Opts:          opts,
+	})
+
+	// Rules have not been evaluated yet, so no samples are cached.
+	require.Nil(t, recordingRule.GetLastSamples())
+
+	app := st.Appender(context.Background())
+	app.Append(0, labels.FromStrings(model.MetricNameLabel, "a", "instance", "1"), 0, 1)
+	app.Append(0, labels.FromStrings(model.MetricNameLabel, "a", "instance", "2"), 0, 2)
+	for i := 0; i < maxLastSamples+5; i++ {
+		app.Append(0, labels.FromStrings(model.MetricNameLabel, "b", "instance", strconv.Itoa(i)), 0, float64(i))
+	}
+	require.NoError(t, app.Commit())
+
+	group.Eval(context.Background(), time.Unix(0, 0))
+
+	samples := recordingRule.GetLastSamples()
+	require.Len(t, samples, 1)
+	require.Equal(t, 3.0, samples[0].F)
+	require.Equal(t, "sum_a", samples[0].Metric.Get(model.MetricNameLabel))
+
+	// The cached preview is bounded, even if the rule produced more series.
+	require.Len(t, wideRule.GetLastSamples(), maxLastSamples)
+}
+
 // Convert a SeriesSet into a form usable with require.Equal.
 func readSeriesSet(ss storage.SeriesSet) (map[string][]promql.FPoint, error) {
 	result := map[string][]promql.FPoint{}
@@ -668,6 +783,85 @@ groups:
 	m.Stop()
 }
 
+func TestGroup_EvaluationTimeout(t *testing.T) {
+	config := `
+groups:
+  - name: group1
+    evaluation_timeout: 30s
+  - name: group2
+`
+
+	dir := t.TempDir()
+	fname := path.Join(dir, "rules.yaml")
+	err := os.WriteFile(fname, []byte(config), fs.ModePerm)
+	require.NoError(t, err)
+
+	m := NewManager(&ManagerOptions{
+		Logger: promslog.NewNopLogger(),
+	})
+	m.start()
+	err = m.Update(time.Second, []string{fname}, labels.EmptyLabels(), "", nil)
+	require.NoError(t, err)
+
+	rgs := m.RuleGroups()
+	sort.Slice(rgs, func(i, j int) bool {
+		return rgs[i].Name() < rgs[j].Name()
+	})
+
+	// From config.
+	require.Equal(t, 30*time.Second, rgs[0].EvaluationTimeout())
+	// No group-level timeout when nothing is set.
+	require.Equal(t, time.Duration(0), rgs[1].EvaluationTimeout())
+
+	m.Stop()
+}
+
+func TestGroupEvaluationTimeoutFailsRunawayRule(t *testing.T) {
+	st := teststorage.New(t)
+	t.Cleanup(func() { st.Close() })
+
+	// The query for the first rule blocks until its context is cancelled, the
+	// query for the second rule returns immediately.
+	queryFunc := func(ctx context.Context, qs string, _ time.Time) (promql.Vector, error) {
+		if qs == "slow" {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return promql.Vector{}, nil
+	}
+
+	opts := &ManagerOptions{
+		QueryFunc:  queryFunc,
+		Appendable: st,
+		Queryable:  st,
+		Context:    context.Background(),
+		Logger:     promslog.NewNopLogger(),
+	}
+
+	slowExpr, err := testParser.ParseExpr("slow")
+	require.NoError(t, err)
+	fastExpr, err := testParser.ParseExpr("fast")
+	require.NoError(t, err)
+	slowRule := NewRecordingRule("slow_rule", slowExpr, labels.EmptyLabels())
+	fastRule := NewRecordingRule("fast_rule", fastExpr, labels.EmptyLabels())
+	group := NewGroup(GroupOptions{
+		Name:              "default",
+		Interval:          time.Second,
+		EvaluationTimeout: 10 * time.Millisecond,
+		Rules:             []Rule{slowRule, fastRule},
+		Opts:              opts,
+	})
+
+	group.Eval(context.Background(), time.Now())
+
+	// The runaway rule is cancelled and reported as failed, while the rest of
+	// the group is still evaluated.
+	require.Equal(t, HealthBad, slowRule.Health())
+	require.ErrorIs(t, slowRule.LastError(), context.DeadlineExceeded)
+	require.Equal(t, HealthGood, fastRule.Health())
+	require.NoError(t, fastRule.LastError())
+}
+
 func TestCopyState(t *testing.T) {
 	oldGroup := &Group{
 		rules: []Rule{
@@ -1427,6 +1621,9 @@ func TestRuleGroupEvalIterationFunc(t *testing.T) {
 			evaluationTimestamp: atomic.NewTime(time.Time{}),
 			evaluationDuration:  atomic.NewDuration(0),
 			lastError:           atomic.NewError(nil),
+			notificationsSent:   atomic.NewInt64(0),
+			flaps:               atomic.NewInt64(0),
+			firingDuration:      atomic.NewDuration(0),
 		}
 
 		group := NewGroup(GroupOptions{
@@ -44,6 +44,10 @@ func (unknownRule) SetEvaluationDuration(time.Duration)  {}
 func (unknownRule) GetEvaluationDuration() time.Duration { return 0 }
 func (unknownRule) SetEvaluationTimestamp(time.Time)     {}
 func (unknownRule) GetEvaluationTimestamp() time.Time    { return time.Time{} }
+func (unknownRule) SetEvaluationStats(EvaluationStats)   {}
+func (unknownRule) GetEvaluationStats() EvaluationStats  { return EvaluationStats{} }
+func (unknownRule) SetLastSamples([]promql.Sample)       {}
+func (unknownRule) GetLastSamples() []promql.Sample      { return nil }
 func (unknownRule) SetDependentRules([]Rule)             {}
 func (unknownRule) NoDependentRules() bool               { return false }
 func (unknownRule) DependentRules() []Rule               { return nil }
@@ -47,6 +47,9 @@ type RecordingRule struct {
 	lastError *atomic.Error
 	// Duration of how long it took to evaluate the recording rule.
 	evaluationDuration *atomic.Duration
+	// Resource usage stats of the last successful evaluation of the recording rule.
+	evaluationStats atomic.Pointer[EvaluationStats]
+	lastSamples     atomic.Pointer[[]promql.Sample]
 
 	dependenciesMutex sync.RWMutex
 	dependentRules    []Rule
@@ -166,6 +169,32 @@ func (rule *RecordingRule) GetEvaluationDuration() time.Duration {
 	return rule.evaluationDuration.Load()
 }
 
+// SetEvaluationStats updates the resource usage stats of the last successful evaluation of the recording rule.
+func (rule *RecordingRule) SetEvaluationStats(s EvaluationStats) {
+	rule.evaluationStats.Store(&s)
+}
+
+// SetLastSamples caches a preview of the result samples of the last successful evaluation of the recording rule.
+func (rule *RecordingRule) SetLastSamples(s []promql.Sample) {
+	rule.lastSamples.Store(&s)
+}
+
+// GetLastSamples returns the cached result sample preview of the last successful evaluation of the recording rule.
+func (rule *RecordingRule) GetLastSamples() []promql.Sample {
+	if s := rule.lastSamples.Load(); s != nil {
+		return *s
+	}
+	return nil
+}
+
+// GetEvaluationStats returns the resource usage stats of the last successful evaluation of the recording rule.
+func (rule *RecordingRule) GetEvaluationStats() EvaluationStats {
+	if s := rule.evaluationStats.Load(); s != nil {
+		return *s
+	}
+	return EvaluationStats{}
+}
+
 // SetEvaluationTimestamp updates evaluationTimestamp to the timestamp of when the rule was last evaluated.
 func (rule *RecordingRule) SetEvaluationTimestamp(ts time.Time) {
 	rule.evaluationTimestamp.Store(ts)
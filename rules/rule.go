@@ -33,6 +33,34 @@ const (
 	HealthBad     RuleHealth = "err"
 )
 
+// EvaluationStats captures the resource usage of a single rule evaluation.
+type EvaluationStats struct {
+	// TotalSamples is the number of samples loaded from storage while
+	// evaluating the rule query.
+	TotalSamples int64 `json:"totalSamples"`
+	// PeakSamples is the maximum number of samples held in memory at once
+	// during the evaluation, the engine's measure of peak memory usage.
+	PeakSamples int `json:"peakSamples"`
+	// ResultSeries is the number of series in the evaluation result.
+	ResultSeries int `json:"resultSeries"`
+}
+
+type evalStatsCtxKey struct{}
+
+// newEvalStatsContext returns a context carrying a destination for query
+// statistics, to be filled in by QueryFunc implementations that can provide
+// them.
+func newEvalStatsContext(ctx context.Context, s *EvaluationStats) context.Context {
+	return context.WithValue(ctx, evalStatsCtxKey{}, s)
+}
+
+// evalStatsFromContext returns the query statistics destination from ctx, or
+// nil if the evaluation does not collect statistics.
+func evalStatsFromContext(ctx context.Context) *EvaluationStats {
+	s, _ := ctx.Value(evalStatsCtxKey{}).(*EvaluationStats)
+	return s
+}
+
 // A Rule encapsulates a vector expression which is evaluated at a specified
 // interval and acted upon (currently either recorded or used for alerting).
 type Rule interface {
@@ -61,6 +89,18 @@ type Rule interface {
 	// GetEvaluationTimestamp returns last evaluation timestamp.
 	// NOTE: Used dynamically by rules.html template.
 	GetEvaluationTimestamp() time.Time
+	// SetEvaluationStats updates the resource usage stats of the last
+	// successful evaluation.
+	SetEvaluationStats(EvaluationStats)
+	// GetEvaluationStats returns the resource usage stats of the last
+	// successful evaluation.
+	GetEvaluationStats() EvaluationStats
+	// SetLastSamples caches a bounded preview of the result samples of the
+	// last successful evaluation.
+	SetLastSamples([]promql.Sample)
+	// GetLastSamples returns the cached result sample preview of the last
+	// successful evaluation. Callers must not mutate the returned slice.
+	GetLastSamples() []promql.Sample
 
 	// SetDependentRules sets rules which depend on the output of this rule.
 	SetDependentRules(rules []Rule)
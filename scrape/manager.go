@@ -82,6 +82,9 @@ func NewManager(
 		newScrapeFailureLogger: newScrapeFailureLogger,
 		scrapeConfigs:          make(map[string]*config.ScrapeConfig),
 		scrapePools:            make(map[string]*scrapePool),
+		targetGroups:           make(map[string]map[string]*targetgroup.Group),
+		targetSetEpochs:        make(map[string]uint64),
+		dirtySets:              make(map[string]struct{}),
 		graceShut:              make(chan struct{}),
 		triggerReload:          make(chan struct{}, 1),
 		metrics:                sm,
@@ -113,6 +116,11 @@ type Options struct {
 	// Option to increase the interval used by scrape manager to throttle target groups updates.
 	DiscoveryReloadInterval model.Duration
 
+	// TargetHealthChangeFunc, if set, is called every time the health of a
+	// scrape target changes, with the previous and current health. It is
+	// called from the scrape loop and must not block.
+	TargetHealthChangeFunc func(target *Target, previous, current TargetHealth)
+
 	// Option to enable the ingestion of the created timestamp as a synthetic zero sample.
 	// See: https://github.com/prometheus/proposals/blob/main/proposals/2023-06-13_created-timestamp.md
 	//
@@ -141,6 +149,16 @@ type Options struct {
 	// EnableTypeAndUnitLabels represents type-and-unit-labels feature flag.
 	EnableTypeAndUnitLabels bool
 
+	// ReuseHistograms makes scrape loops reuse native histogram structs
+	// across scrapes of the same target instead of allocating them per
+	// sample, significantly reducing GC pressure on histogram-heavy
+	// targets. It is safe only if the configured Appendable does not
+	// retain appended histograms beyond Commit or Rollback. Prometheus'
+	// own storage satisfies this; downstream scrape manager users that
+	// buffer histograms longer (e.g. for asynchronous processing) must
+	// leave this disabled.
+	ReuseHistograms bool
+
 	// Optional HTTP client options to use when scraping.
 	HTTPClientOptions []config_util.HTTPClientOption
 
@@ -199,7 +217,13 @@ type Manager struct {
 	newScrapeFailureLogger func(string) (*logging.JSONFileLogger, error)
 	scrapeFailureLoggers   map[string]FailureLogger
 	targetSets             map[string][]*targetgroup.Group
-	buffers                *pool.Pool
+	// targetGroups and targetSetEpochs hold the merged view of the incremental
+	// updates received by RunDeltas, keyed by target set then by group key.
+	targetGroups    map[string]map[string]*targetgroup.Group
+	targetSetEpochs map[string]uint64
+	// dirtySets names the target sets whose groups changed since the last reload.
+	dirtySets map[string]struct{}
+	buffers   *pool.Pool
 
 	triggerReload chan struct{}
 
@@ -229,6 +253,32 @@ func (m *Manager) Run(tsets <-chan map[string][]*targetgroup.Group) error {
 	}
 }
 
+// RunDeltas is the incremental counterpart to Run. It receives target set
+// deltas, merges them into the manager's view of the target groups and
+// triggers the scraping loops reloading for the affected sets only, so a small
+// discovery change doesn't force re-running relabeling for every target.
+// Use either Run or RunDeltas, never both.
+func (m *Manager) RunDeltas(deltas <-chan map[string]*targetgroup.Delta) error {
+	go m.reloader()
+	for {
+		select {
+		case ds, ok := <-deltas:
+			if !ok {
+				return nil
+			}
+			m.applyDeltas(ds)
+
+			select {
+			case m.triggerReload <- struct{}{}:
+			default:
+			}
+
+		case <-m.graceShut:
+			return nil
+		}
+	}
+}
+
 // UnregisterMetrics unregisters manager metrics.
 func (m *Manager) UnregisterMetrics() {
 	m.metrics.Unregister()
@@ -271,7 +321,12 @@ func (m *Manager) reloader() {
 func (m *Manager) reload() {
 	m.mtxScrape.Lock()
 	var wg sync.WaitGroup
-	for setName, groups := range m.targetSets {
+	for setName := range m.dirtySets {
+		delete(m.dirtySets, setName)
+		groups, ok := m.targetSets[setName]
+		if !ok {
+			continue
+		}
 		if _, ok := m.scrapePools[setName]; !ok {
 			scrapeConfig, ok := m.scrapeConfigs[setName]
 			if !ok {
@@ -332,9 +387,51 @@ func (m *Manager) Stop() {
 func (m *Manager) updateTsets(tsets map[string][]*targetgroup.Group) {
 	m.mtxScrape.Lock()
 	m.targetSets = tsets
+	// Full snapshots carry no change information, so every set needs a sync.
+	for setName := range tsets {
+		m.dirtySets[setName] = struct{}{}
+	}
 	m.mtxScrape.Unlock()
 }
 
+// applyDeltas merges incremental target group updates into the manager's view
+// of the target sets and marks the affected sets for syncing on the next
+// reload.
+func (m *Manager) applyDeltas(deltas map[string]*targetgroup.Delta) {
+	m.mtxScrape.Lock()
+	defer m.mtxScrape.Unlock()
+
+	if m.targetSets == nil {
+		m.targetSets = make(map[string][]*targetgroup.Group)
+	}
+	for setName, d := range deltas {
+		groups := m.targetGroups[setName]
+		switch {
+		case d.Full || groups == nil:
+			groups = make(map[string]*targetgroup.Group, len(d.Changed))
+		case d.Epoch != m.targetSetEpochs[setName]+1:
+			// Deltas are delivered in-process and the sender only advances its
+			// state on successful sends, so this is not expected to happen.
+			m.logger.Warn("Unexpected discovery delta epoch; target set state may be stale", "target_set", setName, "epoch", d.Epoch, "expected", m.targetSetEpochs[setName]+1)
+		}
+		for k, tg := range d.Changed {
+			groups[k] = tg
+		}
+		for _, k := range d.Dropped {
+			delete(groups, k)
+		}
+		m.targetGroups[setName] = groups
+		m.targetSetEpochs[setName] = d.Epoch
+
+		list := make([]*targetgroup.Group, 0, len(groups))
+		for _, tg := range groups {
+			list = append(list, tg)
+		}
+		m.targetSets[setName] = list
+		m.dirtySets[setName] = struct{}{}
+	}
+}
+
 // ApplyConfig resets the manager's target providers and job configurations as defined by the new cfg.
 func (m *Manager) ApplyConfig(cfg *config.Config) error {
 	m.mtxScrape.Lock()
@@ -515,6 +612,68 @@ func (m *Manager) ScrapePoolConfig(scrapePool string) (*config.ScrapeConfig, err
 	return sp.config, nil
 }
 
+// OverrideTargets applies a temporary scrape override to all active targets
+// whose labels match the given matchers, optionally restricted to a single
+// scrape pool. A nil override clears previously applied overrides. It returns
+// the number of targets affected. Overrides are in-memory only and are
+// dropped when the target's scrape pool is reloaded or Prometheus restarts.
+func (m *Manager) OverrideTargets(pool string, matchers []*labels.Matcher, override *TargetOverride) (int, error) {
+	m.mtxScrape.Lock()
+	defer m.mtxScrape.Unlock()
+
+	pools := m.scrapePools
+	if pool != "" {
+		sp, ok := m.scrapePools[pool]
+		if !ok {
+			return 0, fmt.Errorf("scrape pool %q not found", pool)
+		}
+		pools = map[string]*scrapePool{pool: sp}
+	}
+
+	affected := 0
+	for _, sp := range pools {
+		for _, t := range sp.ActiveTargets() {
+			matches := true
+			for _, matcher := range matchers {
+				if !matcher.Matches(t.labels.Get(matcher.Name)) {
+					matches = false
+					break
+				}
+			}
+			if !matches {
+				continue
+			}
+			t.SetOverride(override)
+			affected++
+		}
+	}
+	return affected, nil
+}
+
+// MarkTargetsStale requests staleness markers for all series of the active
+// targets whose labels match the given matchers, optionally restricted to a
+// single scrape pool. The markers are written asynchronously by the scrape
+// loops of the affected targets. It returns the number of targets affected.
+func (m *Manager) MarkTargetsStale(pool string, matchers []*labels.Matcher) (int, error) {
+	m.mtxScrape.Lock()
+	defer m.mtxScrape.Unlock()
+
+	pools := m.scrapePools
+	if pool != "" {
+		sp, ok := m.scrapePools[pool]
+		if !ok {
+			return 0, fmt.Errorf("scrape pool %q not found", pool)
+		}
+		pools = map[string]*scrapePool{pool: sp}
+	}
+
+	affected := 0
+	for _, sp := range pools {
+		affected += sp.markTargetsStale(matchers)
+	}
+	return affected, nil
+}
+
 // DisableEndOfRunStalenessMarkers disables the end-of-run staleness markers for the provided targets in the given
 // targetSet. When the end-of-run staleness is disabled for a target, when it goes away, there will be no staleness
 // markers written for its series.
@@ -92,6 +92,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 				"custom":                            "value",
 			}),
 			resOrig: labels.FromMap(map[string]string{
@@ -105,6 +106,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 		},
 		// Pre-define/overwrite scrape config labels.
@@ -136,6 +138,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 			resOrig: labels.FromMap(map[string]string{
 				model.AddressLabel:                  "1.2.3.4",
@@ -147,6 +150,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 		},
 		// Provide instance label. HTTPS port default for IPv6.
@@ -173,6 +177,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 			resOrig: labels.FromMap(map[string]string{
 				model.AddressLabel:                  "[::1]",
@@ -185,6 +190,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 		},
 		// Address label missing.
@@ -232,6 +238,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 				"custom":                            "host:1234",
 			}),
 			resOrig: labels.FromMap(map[string]string{
@@ -243,6 +250,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 				"custom":                            "host:1234",
 			}),
 		},
@@ -277,6 +285,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 				"custom":                            "host:1234",
 			}),
 			resOrig: labels.FromMap(map[string]string{
@@ -288,6 +297,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 				"custom":                            "host:1234",
 			}),
 		},
@@ -359,6 +369,40 @@ func TestPopulateLabels(t *testing.T) {
 			resOrig: labels.EmptyLabels(),
 			err:     "scrape interval cannot be 0",
 		},
+		// Invalid minimum TLS version label.
+		{
+			in: model.LabelSet{
+				model.AddressLabel: "1.2.3.4:1000",
+				tlsMinVersionLabel: "SSL30",
+			},
+			cfg: &config.ScrapeConfig{
+				Scheme:         "https",
+				MetricsPath:    "/metrics",
+				JobName:        "job",
+				ScrapeInterval: model.Duration(time.Second),
+				ScrapeTimeout:  model.Duration(time.Second),
+			},
+			res:     labels.EmptyLabels(),
+			resOrig: labels.EmptyLabels(),
+			err:     "invalid minimum TLS version \"SSL30\"",
+		},
+		// Invalid SPKI pin label.
+		{
+			in: model.LabelSet{
+				model.AddressLabel: "1.2.3.4:1000",
+				tlsSPKIPinLabel:    "dG9vc2hvcnQ=",
+			},
+			cfg: &config.ScrapeConfig{
+				Scheme:         "https",
+				MetricsPath:    "/metrics",
+				JobName:        "job",
+				ScrapeInterval: model.Duration(time.Second),
+				ScrapeTimeout:  model.Duration(time.Second),
+			},
+			res:     labels.EmptyLabels(),
+			resOrig: labels.EmptyLabels(),
+			err:     "SPKI pin must be a base64 encoded SHA-256, got 8 bytes",
+		},
 		// 0 duration in timeout label.
 		{
 			in: model.LabelSet{
@@ -417,6 +461,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 			resOrig: labels.FromMap(map[string]string{
 				model.AddressLabel:                  "1.2.3.4",
@@ -428,6 +473,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 		},
 		// verify that the default port is not removed (http).
@@ -453,6 +499,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 			resOrig: labels.FromMap(map[string]string{
 				model.AddressLabel:                  "1.2.3.4:80",
@@ -464,6 +511,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 		},
 		// verify that the default port is not removed (https).
@@ -489,6 +537,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 			resOrig: labels.FromMap(map[string]string{
 				model.AddressLabel:                  "1.2.3.4:443",
@@ -500,6 +549,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 		},
 		// convert_classic_histograms_to_nhcb seeded from the config default.
@@ -526,6 +576,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "true",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 			resOrig: labels.FromMap(map[string]string{
 				model.AddressLabel:                  "1.2.3.4:1000",
@@ -537,6 +588,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "true",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 		},
 		// convert_classic_histograms_to_nhcb overridden via relabeling.
@@ -572,6 +624,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "true",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 			resOrig: labels.FromMap(map[string]string{
 				model.AddressLabel:                  "1.2.3.4:1000",
@@ -583,6 +636,7 @@ func TestPopulateLabels(t *testing.T) {
 				convertClassicHistogramsToNHCBLabel: "false",
 				alwaysScrapeClassicHistogramsLabel:  "false",
 				scrapeNativeHistogramsLabel:         "false",
+				scrapeWithoutJitterLabel:            "false",
 			}),
 		},
 		// Invalid boolean in convert_classic_histograms_to_nhcb label.
@@ -767,6 +821,49 @@ func TestManagerTargetsUpdates(t *testing.T) {
 	}
 }
 
+func TestManagerDeltaTargetsUpdates(t *testing.T) {
+	opts := Options{}
+	testRegistry := prometheus.NewRegistry()
+	m, err := NewManager(&opts, nil, nil, nil, teststorage.NewAppendable(), testRegistry)
+	require.NoError(t, err)
+
+	deltasCh := make(chan map[string]*targetgroup.Delta)
+	go m.RunDeltas(deltasCh)
+	defer m.Stop()
+
+	send := func(deltas map[string]*targetgroup.Delta) {
+		t.Helper()
+		select {
+		case deltasCh <- deltas:
+		case <-time.After(10 * time.Millisecond):
+			require.Fail(t, "Scrape manager's delta channel remained blocked after the set threshold.")
+		}
+	}
+
+	group1 := &targetgroup.Group{Source: "g1", Targets: []model.LabelSet{{model.AddressLabel: "foo:9090"}}}
+	group2 := &targetgroup.Group{Source: "g2", Targets: []model.LabelSet{{model.AddressLabel: "bar:9090"}}}
+	send(map[string]*targetgroup.Delta{
+		"job1": {Epoch: 1, Full: true, Changed: map[string]*targetgroup.Group{"p/g1": group1, "p/g2": group2}},
+	})
+	group1Changed := &targetgroup.Group{Source: "g1", Targets: []model.LabelSet{{model.AddressLabel: "baz:9090"}}}
+	send(map[string]*targetgroup.Delta{
+		"job1": {Epoch: 2, Changed: map[string]*targetgroup.Group{"p/g1": group1Changed}, Dropped: []string{"p/g2"}},
+	})
+	// An empty update flushes: it is only received once the previous delta has been applied.
+	send(map[string]*targetgroup.Delta{})
+
+	m.mtxScrape.Lock()
+	require.Equal(t, []*targetgroup.Group{group1Changed}, m.targetSets["job1"])
+	require.Contains(t, m.dirtySets, "job1")
+	m.mtxScrape.Unlock()
+
+	select {
+	case <-m.triggerReload:
+	default:
+		require.Fail(t, "No scrape loops reload was triggered after targets update.")
+	}
+}
+
 func TestManagerRunReturnsWhenTargetSetsClosed(t *testing.T) {
 	m, err := NewManager(&Options{}, nil, nil, nil, teststorage.NewAppendable(), prometheus.NewRegistry())
 	require.NoError(t, err)
@@ -42,6 +42,9 @@ type scrapeMetrics struct {
 
 	// Used by targetScraper.
 	targetScrapeExceededBodySizeLimit prometheus.Counter
+	targetScrapeBodySizeBytes         *prometheus.HistogramVec
+	targetScrapeResponseSizeBytes     *prometheus.HistogramVec
+	targetScrapeNotModified           prometheus.Counter
 
 	// Used by scrapeCache.
 	targetScrapeCacheFlushForced prometheus.Counter
@@ -56,6 +59,8 @@ type scrapeMetrics struct {
 	targetScrapeExemplarOutOfOrder         prometheus.Counter
 	targetScrapePoolExceededLabelLimits    prometheus.Counter
 	targetScrapeNativeHistogramBucketLimit prometheus.Counter
+	targetScrapeSampleRateLimit            prometheus.Counter
+	targetScrapeSamplesShed                prometheus.Counter
 	targetScrapeDuration                   prometheus.Histogram
 }
 
@@ -182,6 +187,34 @@ func newScrapeMetrics(reg prometheus.Registerer) (*scrapeMetrics, error) {
 			Help: "Total number of scrapes that hit the body size limit",
 		},
 	)
+	sm.targetScrapeNotModified = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "prometheus_target_scrapes_not_modified_total",
+			Help: "Total number of scrapes answered with HTTP 304 Not Modified where the cached response body was reused.",
+		},
+	)
+	sm.targetScrapeBodySizeBytes = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:                            "prometheus_target_scrape_body_size_bytes",
+			Help:                            "Uncompressed size of successfully read scrape response bodies, per target.",
+			Buckets:                         prometheus.ExponentialBuckets(256, 4, 9),
+			NativeHistogramBucketFactor:     1.1,
+			NativeHistogramMaxBucketNumber:  100,
+			NativeHistogramMinResetDuration: 1 * time.Hour,
+		},
+		[]string{"scrape_job", "instance"},
+	)
+	sm.targetScrapeResponseSizeBytes = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:                            "prometheus_target_scrape_response_size_bytes",
+			Help:                            "On-the-wire size of successfully read scrape response bodies before decompression, per target.",
+			Buckets:                         prometheus.ExponentialBuckets(256, 4, 9),
+			NativeHistogramBucketFactor:     1.1,
+			NativeHistogramMaxBucketNumber:  100,
+			NativeHistogramMinResetDuration: 1 * time.Hour,
+		},
+		[]string{"scrape_job", "instance"},
+	)
 
 	// Used by scrapeCache.
 	sm.targetScrapeCacheFlushForced = prometheus.NewCounter(
@@ -247,6 +280,18 @@ func newScrapeMetrics(reg prometheus.Registerer) (*scrapeMetrics, error) {
 			Help: "Total number of scrapes that hit the native histogram bucket limit and were rejected.",
 		},
 	)
+	sm.targetScrapeSampleRateLimit = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "prometheus_target_scrapes_exceeded_sample_rate_limit_total",
+			Help: "Total number of scrapes that hit the sample rate limit with the fail shedding policy and were rejected.",
+		},
+	)
+	sm.targetScrapeSamplesShed = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "prometheus_target_scrapes_shed_samples_total",
+			Help: "Total number of samples shed by the sample rate limiter.",
+		},
+	)
 	sm.targetScrapeExemplarOutOfOrder = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Name: "prometheus_target_scrapes_exemplar_out_of_order_total",
@@ -282,6 +327,9 @@ func newScrapeMetrics(reg prometheus.Registerer) (*scrapeMetrics, error) {
 		sm.targetSyncFailed,
 		// Used by targetScraper.
 		sm.targetScrapeExceededBodySizeLimit,
+		sm.targetScrapeBodySizeBytes,
+		sm.targetScrapeResponseSizeBytes,
+		sm.targetScrapeNotModified,
 		// Used by scrapeCache.
 		sm.targetScrapeCacheFlushForced,
 		// Used by scrapeLoop.
@@ -294,6 +342,8 @@ func newScrapeMetrics(reg prometheus.Registerer) (*scrapeMetrics, error) {
 		sm.targetScrapeExemplarOutOfOrder,
 		sm.targetScrapePoolExceededLabelLimits,
 		sm.targetScrapeNativeHistogramBucketLimit,
+		sm.targetScrapeSampleRateLimit,
+		sm.targetScrapeSamplesShed,
 		sm.targetScrapeDuration,
 	} {
 		err := reg.Register(collector)
@@ -325,6 +375,9 @@ func (sm *scrapeMetrics) Unregister() {
 	sm.reg.Unregister(sm.targetScrapePoolSymbolTableItems)
 	sm.reg.Unregister(sm.targetSyncFailed)
 	sm.reg.Unregister(sm.targetScrapeExceededBodySizeLimit)
+	sm.reg.Unregister(sm.targetScrapeBodySizeBytes)
+	sm.reg.Unregister(sm.targetScrapeResponseSizeBytes)
+	sm.reg.Unregister(sm.targetScrapeNotModified)
 	sm.reg.Unregister(sm.targetScrapeCacheFlushForced)
 	sm.reg.Unregister(sm.targetIntervalLength)
 	sm.reg.Unregister(sm.targetIntervalLengthHistogram)
@@ -335,6 +388,8 @@ func (sm *scrapeMetrics) Unregister() {
 	sm.reg.Unregister(sm.targetScrapeExemplarOutOfOrder)
 	sm.reg.Unregister(sm.targetScrapePoolExceededLabelLimits)
 	sm.reg.Unregister(sm.targetScrapeNativeHistogramBucketLimit)
+	sm.reg.Unregister(sm.targetScrapeSampleRateLimit)
+	sm.reg.Unregister(sm.targetScrapeSamplesShed)
 	sm.reg.Unregister(sm.targetScrapeDuration)
 }
 
@@ -17,13 +17,18 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"math"
+	"net"
 	"net/http"
 	"net/http/httptrace"
+	"os"
 	"reflect"
 	"slices"
 	"strconv"
@@ -54,6 +59,7 @@ import (
 	"github.com/prometheus/prometheus/model/timestamp"
 	"github.com/prometheus/prometheus/model/value"
 	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunks"
 	"github.com/prometheus/prometheus/util/logging"
 	"github.com/prometheus/prometheus/util/pool"
 )
@@ -151,7 +157,7 @@ func newScrapePool(
 		buffers = pool.New(1e3, 1e6, 3, func(sz int) any { return make([]byte, 0, sz) })
 	}
 
-	client, err := newScrapeClient(cfg.HTTPClientConfig, cfg.JobName, options.HTTPClientOptions...)
+	client, err := newScrapeClient(cfg.HTTPClientConfig, cfg.JobName, cfg.DNSResolutionStrategy, options.HTTPClientOptions...)
 	if err != nil {
 		return nil, err
 	}
@@ -246,6 +252,7 @@ func (sp *scrapePool) stop() {
 			wg.Done()
 		}(l)
 
+		sp.deleteTargetPayloadSizeMetrics(sp.activeTargets[fp])
 		delete(sp.loops, fp)
 		delete(sp.activeTargets, fp)
 	}
@@ -279,7 +286,7 @@ func (sp *scrapePool) reload(cfg *config.ScrapeConfig) error {
 	sp.metrics.targetScrapePoolReloads.Inc()
 	start := time.Now()
 
-	client, err := newScrapeClient(cfg.HTTPClientConfig, cfg.JobName, sp.options.HTTPClientOptions...)
+	client, err := newScrapeClient(cfg.HTTPClientConfig, cfg.JobName, cfg.DNSResolutionStrategy, sp.options.HTTPClientOptions...)
 	if err != nil {
 		sp.metrics.targetScrapePoolReloadsFailed.Inc()
 		return err
@@ -329,13 +336,17 @@ func (sp *scrapePool) restartLoops(reuseCache bool) {
 		newLoop := sp.newLoop(scrapeLoopOptions{
 			target: t,
 			scraper: &targetScraper{
-				Target:               t,
-				client:               sp.client,
-				timeout:              targetTimeout,
-				bodySizeLimit:        int64(sp.config.BodySizeLimit),
-				acceptHeader:         acceptHeader(sp.config.ScrapeProtocols, escapingScheme),
-				acceptEncodingHeader: acceptEncodingHeader(sp.config.EnableCompression),
-				metrics:              sp.metrics,
+				Target:                 t,
+				client:                 sp.scrapeClientForTarget(t),
+				timeout:                targetTimeout,
+				bodySizeLimit:          int64(sp.config.BodySizeLimit),
+				acceptHeader:           acceptHeader(sp.config.ScrapeProtocols, escapingScheme),
+				acceptEncodingHeader:   acceptEncodingHeader(sp.config.EnableCompression),
+				bodyFile:               sp.config.BodyFile,
+				requestHeaders:         sp.config.RequestHeaders,
+				fallbackEndpoints:      sp.config.FallbackScrapeEndpoints,
+				useConditionalRequests: sp.config.UseConditionalRequests,
+				metrics:                sp.metrics,
 			},
 			cache:    cache,
 			interval: targetInterval,
@@ -453,13 +464,17 @@ func (sp *scrapePool) sync(targets []*Target) {
 			l := sp.newLoop(scrapeLoopOptions{
 				target: t,
 				scraper: &targetScraper{
-					Target:               t,
-					client:               sp.client,
-					timeout:              targetTimeout,
-					bodySizeLimit:        int64(sp.config.BodySizeLimit),
-					acceptHeader:         acceptHeader(sp.config.ScrapeProtocols, escapingScheme),
-					acceptEncodingHeader: acceptEncodingHeader(sp.config.EnableCompression),
-					metrics:              sp.metrics,
+					Target:                 t,
+					client:                 sp.scrapeClientForTarget(t),
+					timeout:                targetTimeout,
+					bodySizeLimit:          int64(sp.config.BodySizeLimit),
+					acceptHeader:           acceptHeader(sp.config.ScrapeProtocols, escapingScheme),
+					acceptEncodingHeader:   acceptEncodingHeader(sp.config.EnableCompression),
+					bodyFile:               sp.config.BodyFile,
+					requestHeaders:         sp.config.RequestHeaders,
+					fallbackEndpoints:      sp.config.FallbackScrapeEndpoints,
+					useConditionalRequests: sp.config.UseConditionalRequests,
+					metrics:                sp.metrics,
 				},
 				cache:    newScrapeCache(sp.metrics),
 				interval: targetInterval,
@@ -470,6 +485,9 @@ func (sp *scrapePool) sync(targets []*Target) {
 				l.setForcedError(err)
 			}
 			l.setScrapeFailureLogger(sp.scrapeFailureLogger)
+			if sp.options.TargetHealthChangeFunc != nil {
+				t.setHealthChangeFunc(sp.options.TargetHealthChangeFunc)
+			}
 
 			sp.activeTargets[hash] = t
 			sp.loops[hash] = l
@@ -497,6 +515,7 @@ func (sp *scrapePool) sync(targets []*Target) {
 				wg.Done()
 			}(sp.loops[hash])
 
+			sp.deleteTargetPayloadSizeMetrics(sp.activeTargets[hash])
 			delete(sp.loops, hash)
 			delete(sp.activeTargets, hash)
 		}
@@ -521,6 +540,17 @@ func (sp *scrapePool) sync(targets []*Target) {
 	wg.Wait()
 }
 
+// deleteTargetPayloadSizeMetrics drops the per-target payload size histograms
+// of a removed target.
+func (sp *scrapePool) deleteTargetPayloadSizeMetrics(t *Target) {
+	if t == nil {
+		return
+	}
+	job, instance := t.GetValue(model.JobLabel), t.GetValue(model.InstanceLabel)
+	sp.metrics.targetScrapeBodySizeBytes.DeleteLabelValues(job, instance)
+	sp.metrics.targetScrapeResponseSizeBytes.DeleteLabelValues(job, instance)
+}
+
 // refreshTargetLimitErr returns an error that can be passed to the scrape loops
 // if the number of targets exceeds the configured limit.
 func (sp *scrapePool) refreshTargetLimitErr() error {
@@ -544,6 +574,32 @@ func (sp *scrapePool) disableEndOfRunStalenessMarkers(targets []*Target) {
 	}
 }
 
+// markTargetsStale requests staleness markers for all series of the active
+// targets whose labels match the given matchers. It returns the number of
+// targets affected.
+func (sp *scrapePool) markTargetsStale(matchers []*labels.Matcher) int {
+	sp.mtx.Lock()
+	defer sp.mtx.Unlock()
+	affected := 0
+	for hash, t := range sp.activeTargets {
+		matches := true
+		for _, matcher := range matchers {
+			if !matcher.Matches(t.labels.Get(matcher.Name)) {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		if l, ok := sp.loops[hash]; ok {
+			l.markStale()
+			affected++
+		}
+	}
+	return affected
+}
+
 func verifyLabelLimits(lset labels.Labels, limits *labelLimits) error {
 	if limits == nil {
 		return nil
@@ -693,6 +749,20 @@ type targetScraper struct {
 	bodySizeLimit        int64
 	acceptHeader         string
 	acceptEncodingHeader string
+	bodyFile             string
+	requestHeaders       map[string]string
+	fallbackEndpoints    []config.FallbackScrapeEndpoint
+	fallbackReqs         []*http.Request
+
+	// Conditional request state, used when the scrape config enables
+	// use_conditional_requests. When the target's response carries ETag or
+	// Last-Modified validators, the body is cached and subsequent scrapes are
+	// conditional; a 304 Not Modified response replays the cached body.
+	useConditionalRequests bool
+	lastETag               string
+	lastModified           string
+	cachedBody             []byte
+	cachedMediaType        string
 
 	metrics *scrapeMetrics
 }
@@ -729,23 +799,147 @@ func acceptEncodingHeader(enableCompression bool) string {
 
 var UserAgent = version.PrometheusUserAgent()
 
+func (s *targetScraper) newRequest(url string) (*http.Request, error) {
+	var body io.Reader = http.NoBody
+	if b := s.scrapeBody(); b != "" {
+		body = strings.NewReader(b)
+	} else if s.bodyFile != "" {
+		b, err := os.ReadFile(s.bodyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read scrape body file: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(s.Method(), url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Accept", s.acceptHeader)
+	req.Header.Add("Accept-Encoding", s.acceptEncodingHeader)
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", strconv.FormatFloat(s.timeout.Seconds(), 'f', -1, 64))
+	for name, value := range s.requestHeaders {
+		req.Header.Set(name, s.expandLabelRefs(value))
+	}
+	return req, nil
+}
+
+// doScrape performs one request against a single endpoint of the target.
+func (s *targetScraper) doScrape(ctx context.Context, cached *http.Request) (*http.Response, error) {
+	req := cached.WithContext(ctx)
+	if cached.GetBody != nil {
+		// The cached request's body is consumed by every round trip, so
+		// requests with a body need a fresh copy of it for each scrape.
+		var err error
+		if req.Body, err = cached.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err == nil && resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		// resp.TLS is populated for reused connections too, unlike the
+		// TLSHandshakeDone trace hook.
+		s.setTLSCertExpiry(earliestCertExpiry(resp.TLS.PeerCertificates))
+	}
+	return resp, err
+}
+
+// earliestCertExpiry returns the earliest NotAfter across the certificate
+// chain, matching the semantics of the blackbox exporter's
+// probe_ssl_earliest_cert_expiry: the chain becomes invalid as soon as any
+// certificate in it expires, not just the leaf.
+func earliestCertExpiry(certs []*x509.Certificate) time.Time {
+	earliest := certs[0].NotAfter
+	for _, cert := range certs[1:] {
+		if cert.NotAfter.Before(earliest) {
+			earliest = cert.NotAfter
+		}
+	}
+	return earliest
+}
+
 func (s *targetScraper) scrape(ctx context.Context) (*http.Response, error) {
 	if s.req == nil {
-		req, err := http.NewRequest(http.MethodGet, s.URL().String(), http.NoBody)
+		req, err := s.newRequest(s.URL().String())
 		if err != nil {
 			return nil, err
 		}
-		req.Header.Add("Accept", s.acceptHeader)
-		req.Header.Add("Accept-Encoding", s.acceptEncodingHeader)
-		req.Header.Set("User-Agent", UserAgent)
-		req.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", strconv.FormatFloat(s.timeout.Seconds(), 'f', -1, 64))
-
 		s.req = req
 	}
 	ctx, span := otel.Tracer("").Start(ctx, "Scrape", trace.WithSpanKind(trace.SpanKindClient))
 	defer span.End()
 
-	return s.client.Do(s.req.WithContext(ctx))
+	// Record the address the scrape actually connected to, so the targets
+	// API can report which IP family was used, and the duration of the DNS
+	// lookup for the scrape_dns_resolution_duration_seconds report sample.
+	// The DNS hooks only fire when a new connection is established for a
+	// hostname, so the last observed duration is kept on the target.
+	var dnsStart time.Time
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			s.setResolvedAddress(info.Conn.RemoteAddr().String())
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			s.setDNSResolutionDuration(time.Since(dnsStart))
+		},
+	})
+
+	// Send a conditional request if the feature is enabled and the previous
+	// response carried cache validators, so an unchanged target can answer
+	// with a bodyless 304.
+	if s.useConditionalRequests && s.lastETag != "" {
+		s.req.Header.Set("If-None-Match", s.lastETag)
+	} else {
+		s.req.Header.Del("If-None-Match")
+	}
+	if s.useConditionalRequests && s.lastModified != "" {
+		s.req.Header.Set("If-Modified-Since", s.lastModified)
+	} else {
+		s.req.Header.Del("If-Modified-Since")
+	}
+
+	resp, err := s.doScrape(ctx, s.req)
+	if len(s.fallbackEndpoints) == 0 {
+		return resp, err
+	}
+	if err == nil && (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNotModified) {
+		s.setScrapeEndpointIndex(0)
+		return resp, nil
+	}
+
+	// The cached response belongs to the primary endpoint, so it must not be
+	// reused once we fall back.
+	s.lastETag, s.lastModified, s.cachedBody, s.cachedMediaType = "", "", nil, ""
+
+	// The primary endpoint failed; try the fallback endpoints in order. All
+	// attempts share the scrape timeout.
+	if s.fallbackReqs == nil {
+		s.fallbackReqs = make([]*http.Request, len(s.fallbackEndpoints))
+	}
+	for i, ep := range s.fallbackEndpoints {
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if s.fallbackReqs[i] == nil {
+			req, err := s.newRequest(s.fallbackURL(ep).String())
+			if err != nil {
+				return nil, err
+			}
+			s.fallbackReqs[i] = req
+		}
+		resp, err = s.doScrape(ctx, s.fallbackReqs[i])
+		if err == nil && resp.StatusCode == http.StatusOK {
+			s.setScrapeEndpointIndex(i + 1)
+			return resp, nil
+		}
+	}
+	// All endpoints failed; report the error of the last one.
+	return resp, err
 }
 
 func (s *targetScraper) readResponse(_ context.Context, resp *http.Response, w io.Writer) (string, error) {
@@ -754,6 +948,18 @@ func (s *targetScraper) readResponse(_ context.Context, resp *http.Response, w i
 		resp.Body.Close()
 	}()
 
+	if resp.StatusCode == http.StatusNotModified && len(s.cachedBody) > 0 {
+		// The target reports no change since the previous scrape, so replay
+		// the cached body. Samples without explicit timestamps are stamped
+		// with the current scrape time when appended, which adjusts the
+		// reused sample set to this scrape.
+		s.metrics.targetScrapeNotModified.Inc()
+		if _, err := w.Write(s.cachedBody); err != nil {
+			return "", err
+		}
+		return s.cachedMediaType, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("server returned HTTP status %s", resp.Status)
 	}
@@ -761,8 +967,17 @@ func (s *targetScraper) readResponse(_ context.Context, resp *http.Response, w i
 	if s.bodySizeLimit <= 0 {
 		s.bodySizeLimit = math.MaxInt64
 	}
+	// Cache the body alongside reading it when conditional requests are
+	// enabled and the response carries validators, so the next scrape can
+	// be conditional.
+	dst := w
+	var cache *bytes.Buffer
+	if s.useConditionalRequests && (resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "") {
+		cache = bytes.NewBuffer(s.cachedBody[:0])
+		dst = io.MultiWriter(w, cache)
+	}
 	if resp.Header.Get("Content-Encoding") != "gzip" {
-		n, err := io.Copy(w, io.LimitReader(resp.Body, s.bodySizeLimit))
+		n, err := io.Copy(dst, io.LimitReader(resp.Body, s.bodySizeLimit))
 		if err != nil {
 			return "", err
 		}
@@ -770,24 +985,27 @@ func (s *targetScraper) readResponse(_ context.Context, resp *http.Response, w i
 			s.metrics.targetScrapeExceededBodySizeLimit.Inc()
 			return "", errBodySizeLimit
 		}
+		s.observePayloadSize(n, n)
+		s.storeCachedResponse(resp, cache)
 		return resp.Header.Get("Content-Type"), nil
 	}
 
+	cr := &countingReader{r: resp.Body}
 	if s.gzipr == nil {
-		s.buf = bufio.NewReader(resp.Body)
+		s.buf = bufio.NewReader(cr)
 		var err error
 		s.gzipr, err = gzip.NewReader(s.buf)
 		if err != nil {
 			return "", err
 		}
 	} else {
-		s.buf.Reset(resp.Body)
+		s.buf.Reset(cr)
 		if err := s.gzipr.Reset(s.buf); err != nil {
 			return "", err
 		}
 	}
 
-	n, err := io.Copy(w, io.LimitReader(s.gzipr, s.bodySizeLimit))
+	n, err := io.Copy(dst, io.LimitReader(s.gzipr, s.bodySizeLimit))
 	s.gzipr.Close()
 	if err != nil {
 		return "", err
@@ -796,9 +1014,49 @@ func (s *targetScraper) readResponse(_ context.Context, resp *http.Response, w i
 		s.metrics.targetScrapeExceededBodySizeLimit.Inc()
 		return "", errBodySizeLimit
 	}
+	s.observePayloadSize(n, cr.n)
+	s.storeCachedResponse(resp, cache)
 	return resp.Header.Get("Content-Type"), nil
 }
 
+// storeCachedResponse remembers the validators and body of a successfully
+// read response so the next scrape can be conditional. cache is nil when the
+// response carried no validators.
+func (s *targetScraper) storeCachedResponse(resp *http.Response, cache *bytes.Buffer) {
+	if !s.useConditionalRequests {
+		return
+	}
+	s.lastETag = resp.Header.Get("ETag")
+	s.lastModified = resp.Header.Get("Last-Modified")
+	if cache == nil {
+		s.cachedBody = nil
+		s.cachedMediaType = ""
+		return
+	}
+	s.cachedBody = cache.Bytes()
+	s.cachedMediaType = resp.Header.Get("Content-Type")
+}
+
+// observePayloadSize records the payload size histograms for this target
+// after a successfully read scrape response.
+func (s *targetScraper) observePayloadSize(uncompressed, compressed int64) {
+	job, instance := s.GetValue(model.JobLabel), s.GetValue(model.InstanceLabel)
+	s.metrics.targetScrapeBodySizeBytes.WithLabelValues(job, instance).Observe(float64(uncompressed))
+	s.metrics.targetScrapeResponseSizeBytes.WithLabelValues(job, instance).Observe(float64(compressed))
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
 // A loop can run and be stopped again. It must not be reused after it was stopped.
 type loop interface {
 	run(errc chan<- error)
@@ -807,6 +1065,7 @@ type loop interface {
 	stop()
 	getCache() *scrapeCache
 	disableEndOfRunStalenessMarkers()
+	markStale()
 }
 
 type cacheEntry struct {
@@ -837,9 +1096,12 @@ type scrapeLoop struct {
 
 	interval            time.Duration
 	timeout             time.Duration
+	maxFailureBackoff   time.Duration
+	adaptiveTimeout     time.Duration
 	sampleMutator       labelsMutator
 	reportSampleMutator labelsMutator
 	scraper             scraper
+	target              *Target
 
 	// Static params per scrapePool.
 	appendable   storage.Appendable
@@ -851,19 +1113,31 @@ type scrapeLoop struct {
 	symbolTable  *labels.SymbolTable
 	metrics      *scrapeMetrics
 
+	// Arena reusing native histogram structs across scrapes. Nil unless
+	// Options.ReuseHistograms is set. Only recycled after the appender
+	// committed or rolled back, as appenders may retain histograms until
+	// then.
+	histogramArena *textparse.HistogramArena
+
 	// Options from config.ScrapeConfig.
 	sampleLimit                   int
+	sampleRateLimiter             *sampleRateLimiter
+	fallbackEndpoints             int
 	bucketLimit                   int
 	maxSchema                     int32
 	labelLimits                   *labelLimits
 	honorLabels                   bool
 	honorTimestamps               bool
 	trackTimestampsStaleness      bool
+	stalenessMarkerPolicy         config.StalenessMarkerPolicy
+	staleAfterFailedScrapes       int
 	enableNativeHistogramScraping bool
 	alwaysScrapeClassicHist       bool
 	convertClassicHistToNHCB      bool
 	fallbackScrapeProtocol        string
+	strictExposition              bool
 	enableCompression             bool
+	reportNetworkMetrics          bool
 	mrc                           []*relabel.Config
 	validationScheme              model.ValidationScheme
 
@@ -885,9 +1159,27 @@ type scrapeLoop struct {
 	scrapeFailureLoggerMtx sync.RWMutex
 	scrapeFailureLogger    FailureLogger
 
+	// staleRequestc receives requests to write staleness markers for the
+	// cached series of the target, triggered through the API. At most one
+	// request is buffered; the markers are written on the scrape loop
+	// goroutine.
+	staleRequestc chan struct{}
+
 	// Locally cached data.
 	lastScrapeSize                   int
 	disabledEndOfRunStalenessMarkers atomic.Bool
+	// consecutiveFailures counts failed scrapes since the last successful
+	// one. It is only accessed from the scrape loop goroutine.
+	consecutiveFailures int
+	// lastSamplesShed is the number of samples shed by the sample rate
+	// limiter during the last scrape. It is only accessed from the scrape
+	// loop goroutine.
+	lastSamplesShed int
+	// scrapeDurations is a ring buffer of recent scrape durations feeding
+	// the adaptive timeout. It is only populated when the adaptive timeout
+	// is enabled and only accessed from the scrape loop goroutine.
+	scrapeDurations   []time.Duration
+	scrapeDurationIdx int
 }
 
 // scrapeCache tracks mappings of exposed metric strings to label sets and
@@ -1163,6 +1455,9 @@ func newScrapeLoop(opts scrapeLoopOptions) *scrapeLoop {
 	opts.target.SetMetadataStore(opts.cache)
 
 	appenderCtx := opts.sp.ctx
+	// Scraped samples are first-hand data; mark them as primary origin so
+	// HA dedup logic can tell them apart from replicated remote writes.
+	appenderCtx = storage.ContextWithSampleOrigin(appenderCtx, chunks.OriginPrimary)
 	if opts.sp.options.PassMetadataInContext {
 		// Store the cache and target in the context. This is then used by downstream OTel Collector
 		// to lookup the metadata required to process the samples. Not used by Prometheus itself.
@@ -1173,18 +1468,32 @@ func newScrapeLoop(opts scrapeLoopOptions) *scrapeLoop {
 		appenderCtx = ContextWithTarget(appenderCtx, opts.target)
 	}
 
+	var rateLimiter *sampleRateLimiter
+	if opts.sp.config.SampleRateLimit > 0 {
+		rateLimiter = newSampleRateLimiter(opts.sp.config.SampleRateLimit, opts.interval, opts.sp.config.SampleRateSheddingPolicy)
+	}
+
+	var histogramArena *textparse.HistogramArena
+	if opts.sp.options.ReuseHistograms {
+		histogramArena = textparse.NewHistogramArena()
+	}
+
 	ctx, cancel := context.WithCancel(opts.sp.ctx)
 	return &scrapeLoop{
-		ctx:         ctx,
-		cancel:      cancel,
-		stopped:     make(chan struct{}),
-		parentCtx:   opts.sp.ctx,
-		appenderCtx: appenderCtx,
-		l:           opts.sp.logger.With("target", opts.target),
-		cache:       opts.cache,
-
-		interval: opts.interval,
-		timeout:  opts.timeout,
+		ctx:           ctx,
+		cancel:        cancel,
+		stopped:       make(chan struct{}),
+		staleRequestc: make(chan struct{}, 1),
+		parentCtx:     opts.sp.ctx,
+		appenderCtx:   appenderCtx,
+		l:             opts.sp.logger.With("target", opts.target),
+		cache:         opts.cache,
+
+		interval:          opts.interval,
+		timeout:           opts.timeout,
+		maxFailureBackoff: time.Duration(opts.sp.config.MaxScrapeFailureBackoff),
+		adaptiveTimeout:   time.Duration(opts.sp.config.AdaptiveScrapeTimeout),
+		target:            opts.target,
 		sampleMutator: func(l labels.Labels) labels.Labels {
 			return mutateSampleLabels(l, opts.target, opts.sp.config.HonorLabels, opts.sp.config.MetricRelabelConfigs)
 		},
@@ -1192,17 +1501,20 @@ func newScrapeLoop(opts scrapeLoopOptions) *scrapeLoop {
 		scraper:             opts.scraper,
 
 		// Static params per scrapePool.
-		appendable:   opts.sp.appendable,
-		appendableV2: opts.sp.appendableV2,
-		buffers:      opts.sp.buffers,
-		offsetSeed:   opts.sp.offsetSeed,
-		symbolTable:  opts.sp.symbolTable,
-		metrics:      opts.sp.metrics,
+		appendable:     opts.sp.appendable,
+		appendableV2:   opts.sp.appendableV2,
+		buffers:        opts.sp.buffers,
+		offsetSeed:     opts.sp.offsetSeed,
+		symbolTable:    opts.sp.symbolTable,
+		metrics:        opts.sp.metrics,
+		histogramArena: histogramArena,
 
 		// config.ScrapeConfig.
-		sampleLimit: int(opts.sp.config.SampleLimit),
-		bucketLimit: int(opts.sp.config.NativeHistogramBucketLimit),
-		maxSchema:   pickSchema(opts.sp.config.NativeHistogramMinBucketFactor),
+		sampleLimit:       int(opts.sp.config.SampleLimit),
+		sampleRateLimiter: rateLimiter,
+		fallbackEndpoints: len(opts.sp.config.FallbackScrapeEndpoints),
+		bucketLimit:       int(opts.sp.config.NativeHistogramBucketLimit),
+		maxSchema:         maxSchemaFromConfig(opts.sp.config),
 		labelLimits: &labelLimits{
 			labelLimit:            int(opts.sp.config.LabelLimit),
 			labelNameLengthLimit:  int(opts.sp.config.LabelNameLengthLimit),
@@ -1211,11 +1523,15 @@ func newScrapeLoop(opts scrapeLoopOptions) *scrapeLoop {
 		honorLabels:                   opts.sp.config.HonorLabels,
 		honorTimestamps:               opts.sp.config.HonorTimestamps,
 		trackTimestampsStaleness:      opts.sp.config.TrackTimestampsStaleness,
+		stalenessMarkerPolicy:         opts.sp.config.StalenessMarkerPolicy,
+		staleAfterFailedScrapes:       max(1, opts.sp.config.StaleAfterFailedScrapes),
 		enableNativeHistogramScraping: opts.target.boolLabel(scrapeNativeHistogramsLabel, opts.sp.config.ScrapeNativeHistogramsEnabled()),
 		alwaysScrapeClassicHist:       opts.target.boolLabel(alwaysScrapeClassicHistogramsLabel, opts.sp.config.AlwaysScrapeClassicHistogramsEnabled()),
 		convertClassicHistToNHCB:      opts.target.boolLabel(convertClassicHistogramsToNHCBLabel, opts.sp.config.ConvertClassicHistogramsToNHCBEnabled()),
 		fallbackScrapeProtocol:        opts.sp.config.ScrapeFallbackProtocol.HeaderMediaType(),
+		strictExposition:              opts.sp.config.StrictExposition,
 		enableCompression:             opts.sp.config.EnableCompression,
+		reportNetworkMetrics:          opts.sp.config.ReportNetworkMetrics,
 		mrc:                           opts.sp.config.MetricRelabelConfigs,
 		reportExtraMetrics:            opts.sp.config.ExtraScrapeMetricsEnabled(),
 		validationScheme:              opts.sp.config.MetricNameValidationScheme,
@@ -1287,6 +1603,10 @@ func (sl *scrapeLoop) run(errc chan<- error) {
 	// Reset the ticker so target scrape times are aligned to the offset+intervals.
 	ticker.Reset(sl.interval)
 	alignedScrapeTime := time.Now().Round(0)
+	currentInterval := sl.interval
+	if sl.target != nil {
+		sl.target.setEffectiveInterval(currentInterval)
+	}
 
 	for {
 		select {
@@ -1295,36 +1615,137 @@ func (sl *scrapeLoop) run(errc chan<- error) {
 		default:
 		}
 
-		// Temporary workaround for a jitter in go timers that causes disk space
-		// increase in TSDB.
-		// See https://github.com/prometheus/prometheus/issues/7846
-		// Calling Round ensures the time used is the wall clock, as otherwise .Sub
-		// and .Add on time.Time behave differently (see time package docs).
-		scrapeTime := time.Now().Round(0)
-		if AlignScrapeTimestamps {
-			// Tolerance is clamped to maximum 1% of the scrape interval.
-			tolerance := min(sl.interval/100, ScrapeTimestampTolerance)
-			// For some reason, a tick might have been skipped, in which case we
-			// would call alignedScrapeTime.Add(interval) multiple times.
-			for scrapeTime.Sub(alignedScrapeTime) >= sl.interval {
-				alignedScrapeTime = alignedScrapeTime.Add(sl.interval)
-			}
-			// Align the scrape time if we are in the tolerance boundaries.
-			if scrapeTime.Sub(alignedScrapeTime) <= tolerance {
-				scrapeTime = alignedScrapeTime
+		if o := sl.targetOverride(); o != nil && o.Paused {
+			// Scraping is paused through the API; skip this scrape entirely.
+			sl.l.Debug("Scrape skipped, target is paused through an override")
+		} else {
+			// Temporary workaround for a jitter in go timers that causes disk space
+			// increase in TSDB.
+			// See https://github.com/prometheus/prometheus/issues/7846
+			// Calling Round ensures the time used is the wall clock, as otherwise .Sub
+			// and .Add on time.Time behave differently (see time package docs).
+			scrapeTime := time.Now().Round(0)
+			// Timestamps are not aligned while failure backoff is active, as the
+			// scrape times no longer fall on the configured interval grid.
+			if AlignScrapeTimestamps && currentInterval == sl.interval {
+				// Tolerance is clamped to maximum 1% of the scrape interval.
+				tolerance := min(sl.interval/100, ScrapeTimestampTolerance)
+				// For some reason, a tick might have been skipped, in which case we
+				// would call alignedScrapeTime.Add(interval) multiple times.
+				for scrapeTime.Sub(alignedScrapeTime) >= sl.interval {
+					alignedScrapeTime = alignedScrapeTime.Add(sl.interval)
+				}
+				// Align the scrape time if we are in the tolerance boundaries.
+				if scrapeTime.Sub(alignedScrapeTime) <= tolerance {
+					scrapeTime = alignedScrapeTime
+				}
 			}
+
+			last = sl.scrapeAndReport(last, scrapeTime, errc)
 		}
 
-		last = sl.scrapeAndReport(last, scrapeTime, errc)
+		// Back off the interval exponentially while the target keeps failing
+		// instead of hammering a failing endpoint, and recover once it is
+		// healthy again. A live interval override takes precedence.
+		if d := sl.effectiveInterval(); d != currentInterval {
+			currentInterval = d
+			ticker.Reset(currentInterval)
+			if sl.target != nil {
+				sl.target.setEffectiveInterval(currentInterval)
+			}
+			sl.l.Debug("Adjusted scrape interval", "interval", currentInterval, "failures", sl.consecutiveFailures)
+		}
 
-		select {
-		case <-sl.ctx.Done():
-			return
-		case <-ticker.C:
+	waitForTick:
+		for {
+			select {
+			case <-sl.ctx.Done():
+				return
+			case <-sl.staleRequestc:
+				// Staleness was explicitly requested through the API.
+				sl.appendStaleMarkers(time.Now().Round(0))
+			case <-ticker.C:
+				break waitForTick
+			}
 		}
 	}
 }
 
+// effectiveInterval returns the interval until the next scrape, doubling the
+// configured interval for every consecutive failed scrape, capped at the
+// configured maximum failure backoff. It must only be called from the scrape
+// loop goroutine.
+func (sl *scrapeLoop) effectiveInterval() time.Duration {
+	interval := sl.interval
+	if o := sl.targetOverride(); o != nil && o.Interval > 0 {
+		// A live override takes precedence over the configured interval and
+		// any failure backoff.
+		return o.Interval
+	}
+	if sl.maxFailureBackoff <= 0 {
+		return interval
+	}
+	for i := 0; i < sl.consecutiveFailures && interval < sl.maxFailureBackoff; i++ {
+		interval *= 2
+	}
+	return min(interval, sl.maxFailureBackoff)
+}
+
+// effectiveTimeout returns the scrape timeout currently in effect, taking a
+// live target override and the adaptive timeout into account.
+func (sl *scrapeLoop) effectiveTimeout() time.Duration {
+	if o := sl.targetOverride(); o != nil && o.Timeout > 0 {
+		return o.Timeout
+	}
+	if t := sl.adaptiveTimeoutValue(); t > 0 {
+		return t
+	}
+	return sl.timeout
+}
+
+// adaptiveTimeoutWindow is the number of recent scrape durations the adaptive
+// timeout is derived from.
+const adaptiveTimeoutWindow = 100
+
+// observeScrapeDuration records the duration of a finished scrape for the
+// adaptive timeout. It must only be called from the scrape loop goroutine.
+func (sl *scrapeLoop) observeScrapeDuration(d time.Duration) {
+	if sl.adaptiveTimeout <= 0 {
+		return
+	}
+	if len(sl.scrapeDurations) < adaptiveTimeoutWindow {
+		sl.scrapeDurations = append(sl.scrapeDurations, d)
+		return
+	}
+	sl.scrapeDurations[sl.scrapeDurationIdx] = d
+	sl.scrapeDurationIdx = (sl.scrapeDurationIdx + 1) % adaptiveTimeoutWindow
+}
+
+// adaptiveTimeoutValue derives a timeout from the recorded scrape durations:
+// the 99th percentile of the recent scrapes plus a 50% margin, clamped between
+// the configured scrape timeout and the configured adaptive upper bound. It
+// returns 0 when the adaptation is disabled or no scrape has finished yet.
+// It must only be called from the scrape loop goroutine.
+func (sl *scrapeLoop) adaptiveTimeoutValue() time.Duration {
+	if sl.adaptiveTimeout <= 0 || len(sl.scrapeDurations) == 0 {
+		return 0
+	}
+	ds := make([]time.Duration, len(sl.scrapeDurations))
+	copy(ds, sl.scrapeDurations)
+	slices.Sort(ds)
+	p99 := ds[(len(ds)*99+99)/100-1]
+	return min(max(p99+p99/2, sl.timeout), sl.adaptiveTimeout)
+}
+
+// targetOverride returns the temporary scrape override applied to the loop's
+// target, if any.
+func (sl *scrapeLoop) targetOverride() *TargetOverride {
+	if sl.target == nil {
+		return nil
+	}
+	return sl.target.Override()
+}
+
 func (sl *scrapeLoop) appender() scrapeLoopAppendAdapter {
 	if sl.appendableV2 != nil {
 		return &scrapeLoopAppenderV2{scrapeLoop: sl, AppenderV2: sl.appendableV2.AppenderV2(sl.appenderCtx)}
@@ -1355,6 +1776,11 @@ func (sl *scrapeLoop) scrapeAndReport(last, appendTime time.Time, errc chan<- er
 
 	app := sl.appender()
 	defer func() {
+		if sl.histogramArena != nil {
+			// The appender may retain appended histograms until Commit or
+			// Rollback, so the arena is only recycled afterwards.
+			defer sl.histogramArena.Reset()
+		}
 		if err != nil {
 			_ = app.Rollback()
 			return
@@ -1371,6 +1797,7 @@ func (sl *scrapeLoop) scrapeAndReport(last, appendTime time.Time, errc chan<- er
 	}()
 
 	defer func() {
+		sl.observeScrapeDuration(time.Since(start))
 		if err = sl.report(app, appendTime, time.Since(start), total, added, seriesAdded, bytesRead, scrapeErr); err != nil {
 			sl.l.Warn("Appending scrape report failed", "err", err)
 		}
@@ -1378,11 +1805,14 @@ func (sl *scrapeLoop) scrapeAndReport(last, appendTime time.Time, errc chan<- er
 
 	if forcedErr := sl.getForcedError(); forcedErr != nil {
 		scrapeErr = forcedErr
-		// Add stale markers.
-		if _, _, _, err := app.append([]byte{}, "", appendTime); err != nil {
-			_ = app.Rollback()
-			app = sl.appender()
-			sl.l.Warn("Append failed", "err", err)
+		// Add stale markers, unless more consecutive failures are tolerated
+		// before series go stale.
+		if sl.seriesShouldGoStale() {
+			if _, _, _, err := app.append([]byte{}, "", appendTime); err != nil {
+				_ = app.Rollback()
+				app = sl.appender()
+				sl.l.Warn("Append failed", "err", err)
+			}
 		}
 		if errc != nil {
 			select {
@@ -1398,7 +1828,7 @@ func (sl *scrapeLoop) scrapeAndReport(last, appendTime time.Time, errc chan<- er
 	var resp *http.Response
 	var b []byte
 	var buf *bytes.Buffer
-	scrapeCtx, cancel := context.WithTimeout(sl.parentCtx, sl.timeout)
+	scrapeCtx, cancel := context.WithTimeout(sl.parentCtx, sl.effectiveTimeout())
 	resp, scrapeErr = sl.scraper.scrape(scrapeCtx)
 	if scrapeErr == nil {
 		b = sl.buffers.Get(sl.lastScrapeSize).([]byte)
@@ -1436,7 +1866,11 @@ func (sl *scrapeLoop) scrapeAndReport(last, appendTime time.Time, errc chan<- er
 	}
 
 	// A failed scrape is the same as an empty scrape,
-	// we still call sl.append to trigger stale markers.
+	// we still call sl.append to trigger stale markers, unless
+	// stale_after_failed_scrapes tolerates more consecutive failures.
+	if scrapeErr != nil && !sl.seriesShouldGoStale() {
+		return start
+	}
 	total, added, seriesAdded, appErr = app.append(b, contentType, appendTime)
 	if appErr != nil {
 		_ = app.Rollback()
@@ -1470,6 +1904,15 @@ func (sl *scrapeLoop) getForcedError() error {
 	return sl.forcedErr
 }
 
+// seriesShouldGoStale reports whether the current failed scrape should write
+// staleness markers for the cached series of the target, honoring the
+// configured stale_after_failed_scrapes threshold. The consecutive failure
+// count is only updated by report after the append, so the current failure is
+// added on top. It must only be called from the scrape loop goroutine.
+func (sl *scrapeLoop) seriesShouldGoStale() bool {
+	return sl.consecutiveFailures+1 >= sl.staleAfterFailedScrapes
+}
+
 func (sl *scrapeLoop) endOfRunStaleness(last time.Time, ticker *time.Ticker, interval time.Duration) {
 	// Scraping has stopped. We want to write stale markers but
 	// the target may be recreated, so we wait just over 2 scrape intervals
@@ -1478,33 +1921,44 @@ func (sl *scrapeLoop) endOfRunStaleness(last time.Time, ticker *time.Ticker, int
 	// and will restart where is was. We do not attempt to write stale markers
 	// in this case.
 
+	if sl.stalenessMarkerPolicy == config.StalenessMarkerNever {
+		// End-of-life staleness markers are disabled for this scrape config.
+		return
+	}
+
 	if last.IsZero() {
 		// There never was a scrape, so there will be no stale markers.
 		return
 	}
 
-	// Wait for when the next scrape would have been, record its timestamp.
 	var staleTime time.Time
-	select {
-	case <-sl.parentCtx.Done():
-		return
-	case <-ticker.C:
+	if sl.stalenessMarkerPolicy == config.StalenessMarkerImmediate {
+		// The target is known not to come back, e.g. batch jobs or spot
+		// instances, so write the markers right away.
 		staleTime = time.Now()
-	}
+	} else {
+		// Wait for when the next scrape would have been, record its timestamp.
+		select {
+		case <-sl.parentCtx.Done():
+			return
+		case <-ticker.C:
+			staleTime = time.Now()
+		}
 
-	// Wait for when the next scrape would have been, if the target was recreated
-	// samples should have been ingested by now.
-	select {
-	case <-sl.parentCtx.Done():
-		return
-	case <-ticker.C:
-	}
+		// Wait for when the next scrape would have been, if the target was recreated
+		// samples should have been ingested by now.
+		select {
+		case <-sl.parentCtx.Done():
+			return
+		case <-ticker.C:
+		}
 
-	// Wait for an extra 10% of the interval, just to be safe.
-	select {
-	case <-sl.parentCtx.Done():
-		return
-	case <-time.After(interval / 10):
+		// Wait for an extra 10% of the interval, just to be safe.
+		select {
+		case <-sl.parentCtx.Done():
+			return
+		case <-time.After(interval / 10):
+		}
 	}
 
 	// Check if end-of-run staleness markers have been disabled while we were waiting.
@@ -1512,10 +1966,16 @@ func (sl *scrapeLoop) endOfRunStaleness(last time.Time, ticker *time.Ticker, int
 		return
 	}
 
-	// Call sl.append again with an empty scrape to trigger stale markers.
-	// If the target has since been recreated and scraped, the
-	// stale markers will be out of order and ignored.
-	// sl.context would have been cancelled, hence using sl.appenderCtx.
+	sl.appendStaleMarkers(staleTime)
+}
+
+// appendStaleMarkers writes staleness markers for all currently cached series
+// of the target, including the synthesized report series, by appending an
+// empty scrape. If the target has since been recreated and scraped, the stale
+// markers will be out of order and ignored. It must only be called from the
+// scrape loop goroutine or after run has returned.
+func (sl *scrapeLoop) appendStaleMarkers(staleTime time.Time) {
+	// sl.context may have been cancelled, hence using sl.appenderCtx.
 	app := sl.appender()
 	var err error
 	defer func() {
@@ -1549,6 +2009,17 @@ func (sl *scrapeLoop) disableEndOfRunStalenessMarkers() {
 	sl.disabledEndOfRunStalenessMarkers.Store(true)
 }
 
+// markStale requests staleness markers for all currently cached series of the
+// target. The markers are written asynchronously on the scrape loop
+// goroutine; requests are deduplicated while one is pending.
+func (sl *scrapeLoop) markStale() {
+	select {
+	case sl.staleRequestc <- struct{}{}:
+	default:
+		// A request is already pending.
+	}
+}
+
 func (sl *scrapeLoop) getCache() *scrapeCache {
 	return sl.cache
 }
@@ -1603,6 +2074,7 @@ func (sl *scrapeLoopAppender) append(b []byte, contentType string, ts time.Time)
 		KeepClassicOnClassicAndNativeHistograms: sl.alwaysScrapeClassicHist,
 		OpenMetricsSkipSTSeries:                 sl.enableSTZeroIngestion,
 		FallbackContentType:                     sl.fallbackScrapeProtocol,
+		HistogramArena:                          sl.histogramArena,
 	})
 	if p == nil {
 		sl.l.Error(
@@ -1625,6 +2097,7 @@ func (sl *scrapeLoopAppender) append(b []byte, contentType string, ts time.Time)
 		appErrs        = appendErrors{}
 		sampleLimitErr error
 		bucketLimitErr error
+		rateLimitErr   error
 		lset           labels.Labels     // Escapes to heap so hoisted out of loop.
 		e              exemplar.Exemplar // Escapes to heap so hoisted out of loop.
 		lastMeta       *metaEntry
@@ -1633,6 +2106,13 @@ func (sl *scrapeLoopAppender) append(b []byte, contentType string, ts time.Time)
 
 	exemplars := make([]exemplar.Exemplar, 0, 1)
 
+	var validator *expositionValidator
+	if sl.strictExposition {
+		validator = &expositionValidator{}
+	}
+
+	sl.lastSamplesShed = 0
+
 	// Take an appender with limits.
 	app := appenderWithLimits(sl.Appender, sl.sampleLimit, sl.bucketLimit, sl.maxSchema)
 
@@ -1668,6 +2148,9 @@ loop:
 			// TODO(bwplotka): Build meta entry directly instead of locking and updating the map. This will
 			// allow to properly update metadata when e.g unit was added, then removed;
 			lastMFName, lastMeta = sl.cache.setType(p.Type())
+			if validator != nil {
+				validator.observeFamily(p.Type())
+			}
 			continue
 		case textparse.EntryHelp:
 			lastMFName, lastMeta = sl.cache.setHelp(p.Help())
@@ -1689,6 +2172,9 @@ loop:
 		} else {
 			met, parsedTimestamp, val = p.Series()
 		}
+		if validator != nil {
+			validator.observeSample(metricNameFromSeries(met))
+		}
 		if !sl.honorTimestamps {
 			parsedTimestamp = nil
 		}
@@ -1739,6 +2225,23 @@ loop:
 			}
 		}
 
+		// The rate limit is applied after metrics are potentially dropped via
+		// relabeling, in line with sample_limit.
+		if sl.sampleRateLimiter != nil {
+			switch sl.sampleRateLimiter.admit(ts, !seriesCached) {
+			case admitSample:
+			case shedSample:
+				sl.lastSamplesShed++
+				added++
+				continue
+			case failScrape:
+				rateLimitErr = errSampleRateLimit
+				sl.lastSamplesShed++
+				added++
+				continue
+			}
+		}
+
 		if seriesAlreadyScraped && parsedTimestamp == nil {
 			err = storage.ErrDuplicateSampleForTimestamp
 		} else {
@@ -1878,6 +2381,20 @@ loop:
 		// We only want to increment this once per scrape, so this is Inc'd outside the loop.
 		sl.metrics.targetScrapeNativeHistogramBucketLimit.Inc()
 	}
+	if rateLimitErr != nil {
+		if err == nil {
+			err = rateLimitErr // Sample and bucket limit errors take precedence.
+		}
+		// We only want to increment this once per scrape, so this is Inc'd outside the loop.
+		sl.metrics.targetScrapeSampleRateLimit.Inc()
+	}
+	if validator != nil && err == nil {
+		// Limit errors take precedence over exposition violations.
+		err = validator.err()
+	}
+	if sl.lastSamplesShed > 0 {
+		sl.metrics.targetScrapeSamplesShed.Add(float64(sl.lastSamplesShed))
+	}
 	if appErrs.numOutOfOrder > 0 {
 		sl.l.Warn("Error on ingesting out-of-order samples", "num_dropped", appErrs.numOutOfOrder)
 	}
@@ -2098,11 +2615,56 @@ var (
 			Unit: "bytes",
 		},
 	}
+	scrapeSamplesShedMetric = reportSample{
+		name: []byte("scrape_samples_shed" + "\xff"),
+		Metadata: metadata.Metadata{
+			Type: model.MetricTypeGauge,
+			Help: "Number of samples shed by the sample rate limiter in the last scrape. Only reported when sample_rate_limit is configured.",
+			Unit: "samples",
+		},
+	}
+	scrapeEndpointIndexMetric = reportSample{
+		name: []byte("scrape_endpoint_index" + "\xff"),
+		Metadata: metadata.Metadata{
+			Type: model.MetricTypeGauge,
+			Help: "Index of the endpoint that served the last successful scrape: 0 is the primary endpoint, higher values index into fallback_scrape_endpoints. Only reported when fallback_scrape_endpoints is configured.",
+		},
+	}
+	scrapeEffectiveIntervalMetric = reportSample{
+		name: []byte("scrape_effective_interval_seconds" + "\xff"),
+		Metadata: metadata.Metadata{
+			Type: model.MetricTypeGauge,
+			Help: "The scrape interval currently in effect for a target, including failure backoff. Only reported when max_scrape_failure_backoff is configured.",
+			Unit: "seconds",
+		},
+	}
+	scrapeTLSCertExpiryMetric = reportSample{
+		name: []byte("scrape_tls_certificate_expiry_timestamp_seconds" + "\xff"),
+		Metadata: metadata.Metadata{
+			Type: model.MetricTypeGauge,
+			Help: "Earliest NotAfter timestamp across the certificate chain presented by the target. Only reported when report_network_metrics is configured and the target is scraped over TLS.",
+			Unit: "seconds",
+		},
+	}
+	scrapeDNSResolutionDurationMetric = reportSample{
+		name: []byte("scrape_dns_resolution_duration_seconds" + "\xff"),
+		Metadata: metadata.Metadata{
+			Type: model.MetricTypeGauge,
+			Help: "Duration of the DNS lookup made for the most recent new scrape connection. Only reported when report_network_metrics is configured and the target address required a lookup.",
+			Unit: "seconds",
+		},
+	}
 )
 
 func (sl *scrapeLoop) report(app scrapeLoopAppendAdapter, start time.Time, duration time.Duration, scraped, added, seriesAdded, bytes int, scrapeErr error) (err error) {
 	sl.scraper.Report(start, duration, scrapeErr)
 
+	if scrapeErr == nil {
+		sl.consecutiveFailures = 0
+	} else {
+		sl.consecutiveFailures++
+	}
+
 	ts := timestamp.FromTime(start)
 
 	var health float64
@@ -2137,6 +2699,33 @@ func (sl *scrapeLoop) report(app scrapeLoopAppendAdapter, start time.Time, durat
 			return err
 		}
 	}
+	if sl.sampleRateLimiter != nil {
+		if err = app.addReportSample(scrapeSamplesShedMetric, ts, float64(sl.lastSamplesShed), b, false); err != nil {
+			return err
+		}
+	}
+	if sl.fallbackEndpoints > 0 {
+		if err = app.addReportSample(scrapeEndpointIndexMetric, ts, float64(sl.target.ScrapeEndpointIndex()), b, false); err != nil {
+			return err
+		}
+	}
+	if sl.maxFailureBackoff > 0 {
+		if err = app.addReportSample(scrapeEffectiveIntervalMetric, ts, sl.effectiveInterval().Seconds(), b, false); err != nil {
+			return err
+		}
+	}
+	if sl.reportNetworkMetrics {
+		if expiry, ok := sl.target.TLSCertExpiry(); ok {
+			if err = app.addReportSample(scrapeTLSCertExpiryMetric, ts, float64(expiry.Unix()), b, false); err != nil {
+				return err
+			}
+		}
+		if d, ok := sl.target.DNSResolutionDuration(); ok {
+			if err = app.addReportSample(scrapeDNSResolutionDurationMetric, ts, d.Seconds(), b, false); err != nil {
+				return err
+			}
+		}
+	}
 	return err
 }
 
@@ -2171,6 +2760,33 @@ func (sl *scrapeLoop) reportStale(app scrapeLoopAppendAdapter, start time.Time)
 			return err
 		}
 	}
+	if sl.sampleRateLimiter != nil {
+		if err = app.addReportSample(scrapeSamplesShedMetric, ts, stale, b, true); err != nil {
+			return err
+		}
+	}
+	if sl.fallbackEndpoints > 0 {
+		if err = app.addReportSample(scrapeEndpointIndexMetric, ts, stale, b, true); err != nil {
+			return err
+		}
+	}
+	if sl.maxFailureBackoff > 0 {
+		if err = app.addReportSample(scrapeEffectiveIntervalMetric, ts, stale, b, true); err != nil {
+			return err
+		}
+	}
+	if sl.reportNetworkMetrics {
+		if _, ok := sl.target.TLSCertExpiry(); ok {
+			if err = app.addReportSample(scrapeTLSCertExpiryMetric, ts, stale, b, true); err != nil {
+				return err
+			}
+		}
+		if _, ok := sl.target.DNSResolutionDuration(); ok {
+			if err = app.addReportSample(scrapeDNSResolutionDurationMetric, ts, stale, b, true); err != nil {
+				return err
+			}
+		}
+	}
 	return err
 }
 
@@ -2269,6 +2885,17 @@ func TargetFromContext(ctx context.Context) (*Target, bool) {
 	return t, ok
 }
 
+// maxSchemaFromConfig returns the highest native histogram schema the scrape
+// config allows, combining the minimum bucket factor with the explicit target
+// schema, if any.
+func maxSchemaFromConfig(cfg *config.ScrapeConfig) int32 {
+	maxSchema := pickSchema(cfg.NativeHistogramMinBucketFactor)
+	if ts := cfg.NativeHistogramTargetSchema; ts != nil && *ts < maxSchema {
+		maxSchema = *ts
+	}
+	return maxSchema
+}
+
 func pickSchema(bucketFactor float64) int32 {
 	if bucketFactor <= 1 {
 		bucketFactor = 1.00271
@@ -2284,7 +2911,38 @@ func pickSchema(bucketFactor float64) int32 {
 	}
 }
 
-func newScrapeClient(cfg config_util.HTTPClientConfig, name string, optFuncs ...config_util.HTTPClientOption) (*http.Client, error) {
+// newDialContextFunc returns a DialContext function honouring the given DNS
+// resolution strategy, or nil when the default dual-stack dialer suffices.
+func newDialContextFunc(strategy config.DNSResolutionStrategy) config_util.DialContextFunc {
+	var primary, fallback string
+	switch strategy {
+	case config.DNSResolutionIPv4:
+		primary = "tcp4"
+	case config.DNSResolutionIPv6:
+		primary = "tcp6"
+	case config.DNSResolutionPreferIPv4:
+		primary, fallback = "tcp4", "tcp6"
+	case config.DNSResolutionPreferIPv6:
+		primary, fallback = "tcp6", "tcp4"
+	default:
+		return nil
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	return func(ctx context.Context, _, addr string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, primary, addr)
+		if err != nil && fallback != "" {
+			if conn, fbErr := dialer.DialContext(ctx, fallback, addr); fbErr == nil {
+				return conn, nil
+			}
+		}
+		return conn, err
+	}
+}
+
+func newScrapeClient(cfg config_util.HTTPClientConfig, name string, strategy config.DNSResolutionStrategy, optFuncs ...config_util.HTTPClientOption) (*http.Client, error) {
+	if dialFn := newDialContextFunc(strategy); dialFn != nil {
+		optFuncs = append(optFuncs, config_util.WithDialContextFunc(dialFn))
+	}
 	client, err := config_util.NewClientFromConfig(cfg, name, optFuncs...)
 	if err != nil {
 		return nil, fmt.Errorf("error creating HTTP client: %w", err)
@@ -2296,3 +2954,58 @@ func newScrapeClient(cfg config_util.HTTPClientConfig, name string, optFuncs ...
 		}))
 	return client, nil
 }
+
+// scrapeClientForTarget returns the HTTP client to scrape t with. If the
+// target carries per-target TLS overrides set through relabeling, a dedicated
+// client derived from the pool's HTTP config is returned; otherwise the
+// shared pool client is used.
+func (sp *scrapePool) scrapeClientForTarget(t *Target) *http.Client {
+	serverName, minVersion, spkiPin := t.tlsOverrides()
+	if serverName == "" && minVersion == "" && spkiPin == "" {
+		return sp.client
+	}
+
+	cfg := sp.config.HTTPClientConfig
+	if serverName != "" {
+		cfg.TLSConfig.ServerName = serverName
+	}
+	if minVersion != "" {
+		// The value was validated when the target labels were populated.
+		cfg.TLSConfig.MinVersion = config_util.TLSVersions[minVersion]
+	}
+	client, err := newScrapeClient(cfg, sp.config.JobName, sp.config.DNSResolutionStrategy, sp.options.HTTPClientOptions...)
+	if err != nil {
+		sp.logger.Error("Failed to create a dedicated HTTP client for target, falling back to the shared client", "target", t.URL(), "err", err)
+		return sp.client
+	}
+	if spkiPin != "" {
+		client.Transport = &spkiPinningRoundTripper{next: client.Transport, pin: spkiPin}
+	}
+	return client
+}
+
+// spkiPinningRoundTripper rejects responses served with a leaf certificate
+// whose SubjectPublicKeyInfo SHA-256 does not match the expected pin. Note
+// that the check happens after the request was sent; it protects the scraped
+// data from an impostor, it does not keep request headers from reaching one.
+type spkiPinningRoundTripper struct {
+	next http.RoundTripper
+	pin  string // Base64 encoded SHA-256 of the expected SubjectPublicKeyInfo.
+}
+
+func (rt *spkiPinningRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		resp.Body.Close()
+		return nil, errors.New("certificate pinning requires a TLS connection")
+	}
+	sum := sha256.Sum256(resp.TLS.PeerCertificates[0].RawSubjectPublicKeyInfo)
+	if got := base64.StdEncoding.EncodeToString(sum[:]); got != rt.pin {
+		resp.Body.Close()
+		return nil, fmt.Errorf("server certificate SPKI pin mismatch: got %s, want %s", got, rt.pin)
+	}
+	return resp, nil
+}
@@ -104,6 +104,7 @@ func (sl *scrapeLoopAppenderV2) append(b []byte, contentType string, ts time.Tim
 		KeepClassicOnClassicAndNativeHistograms: sl.alwaysScrapeClassicHist,
 		OpenMetricsSkipSTSeries:                 sl.parseST,
 		FallbackContentType:                     sl.fallbackScrapeProtocol,
+		HistogramArena:                          sl.histogramArena,
 	})
 	if p == nil {
 		sl.l.Error(
@@ -126,6 +127,7 @@ func (sl *scrapeLoopAppenderV2) append(b []byte, contentType string, ts time.Tim
 		appErrs        = appendErrors{}
 		sampleLimitErr error
 		bucketLimitErr error
+		rateLimitErr   error
 		lset           labels.Labels     // Escapes to heap so hoisted out of loop.
 		e              exemplar.Exemplar // Escapes to heap so hoisted out of loop.
 		lastMeta       *metaEntry
@@ -134,6 +136,13 @@ func (sl *scrapeLoopAppenderV2) append(b []byte, contentType string, ts time.Tim
 
 	exemplars := make([]exemplar.Exemplar, 0, 1)
 
+	var validator *expositionValidator
+	if sl.strictExposition {
+		validator = &expositionValidator{}
+	}
+
+	sl.lastSamplesShed = 0
+
 	// Take an appender with limits.
 	app := appenderV2WithLimits(sl.AppenderV2, sl.sampleLimit, sl.bucketLimit, sl.maxSchema)
 
@@ -172,6 +181,9 @@ loop:
 			// TODO(bwplotka): Build meta entry directly instead of locking and updating the map. This will
 			// allow to properly update metadata when e.g unit was added, then removed;
 			lastMFName, lastMeta = sl.cache.setType(p.Type())
+			if validator != nil {
+				validator.observeFamily(p.Type())
+			}
 			continue
 		case textparse.EntryHelp:
 			lastMFName, lastMeta = sl.cache.setHelp(p.Help())
@@ -193,6 +205,9 @@ loop:
 		} else {
 			met, parsedTimestamp, val = p.Series()
 		}
+		if validator != nil {
+			validator.observeSample(metricNameFromSeries(met))
+		}
 		if !sl.honorTimestamps {
 			parsedTimestamp = nil
 		}
@@ -243,6 +258,23 @@ loop:
 			}
 		}
 
+		// The rate limit is applied after metrics are potentially dropped via
+		// relabeling, in line with sample_limit.
+		if sl.sampleRateLimiter != nil {
+			switch sl.sampleRateLimiter.admit(ts, !seriesCached) {
+			case admitSample:
+			case shedSample:
+				sl.lastSamplesShed++
+				added++
+				continue
+			case failScrape:
+				rateLimitErr = errSampleRateLimit
+				sl.lastSamplesShed++
+				added++
+				continue
+			}
+		}
+
 		exemplars = exemplars[:0] // Reset and reuse the exemplar slice.
 
 		if seriesAlreadyScraped && parsedTimestamp == nil {
@@ -395,6 +427,20 @@ loop:
 		// We only want to increment this once per scrape, so this is Inc'd outside the loop.
 		sl.metrics.targetScrapeNativeHistogramBucketLimit.Inc()
 	}
+	if rateLimitErr != nil {
+		if err == nil {
+			err = rateLimitErr // Sample and bucket limit errors take precedence.
+		}
+		// We only want to increment this once per scrape, so this is Inc'd outside the loop.
+		sl.metrics.targetScrapeSampleRateLimit.Inc()
+	}
+	if validator != nil && err == nil {
+		// Limit errors take precedence over exposition violations.
+		err = validator.err()
+	}
+	if sl.lastSamplesShed > 0 {
+		sl.metrics.targetScrapeSamplesShed.Add(float64(sl.lastSamplesShed))
+	}
 	if appErrs.numOutOfOrder > 0 {
 		sl.l.Warn("Error on ingesting out-of-order samples", "num_dropped", appErrs.numOutOfOrder)
 	}
@@ -29,6 +29,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -391,7 +392,7 @@ func testDroppedTargetsList(t *testing.T, appV2 bool) {
 		}
 		sa                     = selectAppendable(app, appV2)
 		sp, _                  = newScrapePool(cfg, sa.V1(), sa.V2(), 0, nil, nil, &Options{}, newTestScrapeMetrics(t))
-		expectedLabelSetString = "{__address__=\"127.0.0.1:9090\", __always_scrape_classic_histograms__=\"false\", __convert_classic_histograms_to_nhcb__=\"false\", __scrape_interval__=\"0s\", __scrape_native_histograms__=\"false\", __scrape_timeout__=\"0s\", job=\"dropMe\"}"
+		expectedLabelSetString = "{__address__=\"127.0.0.1:9090\", __always_scrape_classic_histograms__=\"false\", __convert_classic_histograms_to_nhcb__=\"false\", __scrape_interval__=\"0s\", __scrape_native_histograms__=\"false\", __scrape_timeout__=\"0s\", __scrape_without_jitter__=\"false\", job=\"dropMe\"}"
 		expectedLength         = 2
 	)
 	sp.Sync(tgs)
@@ -445,6 +446,7 @@ type testLoop struct {
 	runOnce      bool
 	interval     time.Duration
 	timeout      time.Duration
+	markedStale  bool
 }
 
 func (*testLoop) setScrapeFailureLogger(FailureLogger) {
@@ -461,6 +463,10 @@ func (l *testLoop) run(errc chan<- error) {
 func (*testLoop) disableEndOfRunStalenessMarkers() {
 }
 
+func (l *testLoop) markStale() {
+	l.markedStale = true
+}
+
 func (l *testLoop) setForcedError(err error) {
 	l.forcedErrMtx.Lock()
 	defer l.forcedErrMtx.Unlock()
@@ -1136,6 +1142,232 @@ func testScrapeLoopStop(t *testing.T, appV2 bool) {
 	}
 }
 
+func TestScrapeLoopFailureBackoff(t *testing.T) {
+	t.Parallel()
+
+	appTest := teststorage.NewAppendable()
+	sl, scraper := newTestScrapeLoop(t, withAppendable(appTest, false), func(sl *scrapeLoop) {
+		sl.interval = 10 * time.Second
+		sl.maxFailureBackoff = 40 * time.Second
+		// Since we're writing samples directly below we need to provide a protocol fallback.
+		sl.fallbackScrapeProtocol = "text/plain"
+	})
+
+	scrapeErr := errors.New("scrape failed")
+	scraper.scrapeFunc = func(context.Context, io.Writer) error { return scrapeErr }
+
+	require.Equal(t, 10*time.Second, sl.effectiveInterval())
+
+	// The interval doubles after every failed scrape, up to the configured maximum.
+	for _, want := range []time.Duration{20 * time.Second, 40 * time.Second, 40 * time.Second} {
+		sl.scrapeAndReport(time.Time{}, time.Now(), nil)
+		require.Equal(t, want, sl.effectiveInterval())
+	}
+
+	// A successful scrape restores the configured interval.
+	scraper.scrapeFunc = func(_ context.Context, w io.Writer) error {
+		_, _ = w.Write([]byte("metric_a 42\n"))
+		return nil
+	}
+	sl.scrapeAndReport(time.Time{}, time.Now(), nil)
+	require.Equal(t, 10*time.Second, sl.effectiveInterval())
+
+	// The effective interval is reported as a sample on the target.
+	var intervals []float64
+	for _, s := range appTest.ResultSamples() {
+		if s.L.Get(model.MetricNameLabel) == "scrape_effective_interval_seconds" {
+			intervals = append(intervals, s.V)
+		}
+	}
+	require.Equal(t, []float64{20, 40, 40, 10}, intervals)
+}
+
+func TestScrapeLoopAdaptiveTimeout(t *testing.T) {
+	t.Parallel()
+
+	target := &Target{scrapeConfig: &config.ScrapeConfig{}}
+	sl, _ := newTestScrapeLoop(t, func(sl *scrapeLoop) {
+		sl.timeout = 2 * time.Second
+		sl.adaptiveTimeout = 8 * time.Second
+		sl.target = target
+	})
+
+	// Before any scrape finished, the configured timeout is in effect.
+	require.Equal(t, 2*time.Second, sl.effectiveTimeout())
+
+	// Fast scrapes keep the configured timeout as the lower bound.
+	for range 10 {
+		sl.observeScrapeDuration(100 * time.Millisecond)
+	}
+	require.Equal(t, 2*time.Second, sl.effectiveTimeout())
+
+	// A consistently slow target gets its p99 duration plus a 50% margin.
+	for range 10 {
+		sl.observeScrapeDuration(4 * time.Second)
+	}
+	require.Equal(t, 6*time.Second, sl.effectiveTimeout())
+
+	// The adaptive timeout never exceeds the configured upper bound.
+	sl.observeScrapeDuration(20 * time.Second)
+	require.Equal(t, 8*time.Second, sl.effectiveTimeout())
+
+	// A live override still takes precedence over the adaptation.
+	target.SetOverride(&TargetOverride{Timeout: 30 * time.Second})
+	require.Equal(t, 30*time.Second, sl.effectiveTimeout())
+	target.SetOverride(nil)
+
+	// Disabling the adaptation ignores the recorded durations.
+	sl.adaptiveTimeout = 0
+	require.Equal(t, 2*time.Second, sl.effectiveTimeout())
+}
+
+func TestScrapeLoopNetworkReportMetrics(t *testing.T) {
+	t.Parallel()
+
+	appTest := teststorage.NewAppendable()
+	target := &Target{scrapeConfig: &config.ScrapeConfig{}}
+	sl, scraper := newTestScrapeLoop(t, withAppendable(appTest, false), func(sl *scrapeLoop) {
+		sl.reportNetworkMetrics = true
+		sl.target = target
+		// Since we're writing samples directly below we need to provide a protocol fallback.
+		sl.fallbackScrapeProtocol = "text/plain"
+	})
+
+	scraper.scrapeFunc = func(_ context.Context, w io.Writer) error {
+		_, _ = w.Write([]byte("metric_a 42\n"))
+		return nil
+	}
+
+	reported := func() map[string]float64 {
+		m := map[string]float64{}
+		for _, s := range appTest.ResultSamples() {
+			switch name := s.L.Get(model.MetricNameLabel); name {
+			case "scrape_tls_certificate_expiry_timestamp_seconds", "scrape_dns_resolution_duration_seconds":
+				m[name] = s.V
+			}
+		}
+		return m
+	}
+
+	// Nothing is reported before a TLS certificate or DNS lookup was observed,
+	// e.g. for plain HTTP targets addressed by a literal IP.
+	sl.scrapeAndReport(time.Time{}, time.Now(), nil)
+	require.Empty(t, reported())
+
+	// Once the scraper recorded observations on the target, they are reported
+	// with every scrape.
+	expiry := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+	target.setTLSCertExpiry(expiry)
+	target.setDNSResolutionDuration(25 * time.Millisecond)
+	sl.scrapeAndReport(time.Time{}, time.Now(), nil)
+	require.Equal(t, map[string]float64{
+		"scrape_tls_certificate_expiry_timestamp_seconds": float64(expiry.Unix()),
+		"scrape_dns_resolution_duration_seconds":          0.025,
+	}, reported())
+}
+
+func TestScrapeLoopTargetOverride(t *testing.T) {
+	t.Parallel()
+
+	target := &Target{scrapeConfig: &config.ScrapeConfig{}}
+	sl, _ := newTestScrapeLoop(t, func(sl *scrapeLoop) {
+		sl.interval = 10 * time.Second
+		sl.timeout = 5 * time.Second
+		sl.target = target
+	})
+
+	require.Equal(t, 10*time.Second, sl.effectiveInterval())
+	require.Equal(t, 5*time.Second, sl.effectiveTimeout())
+
+	// An override takes precedence over the configured values and any
+	// failure backoff.
+	sl.maxFailureBackoff = 40 * time.Second
+	sl.consecutiveFailures = 3
+	target.SetOverride(&TargetOverride{Interval: time.Minute, Timeout: 30 * time.Second})
+	require.Equal(t, time.Minute, sl.effectiveInterval())
+	require.Equal(t, 30*time.Second, sl.effectiveTimeout())
+
+	// Clearing the override restores the configured values, including the
+	// failure backoff still in effect.
+	target.SetOverride(nil)
+	require.Equal(t, 40*time.Second, sl.effectiveInterval())
+	require.Equal(t, 5*time.Second, sl.effectiveTimeout())
+}
+
+func TestManagerOverrideTargets(t *testing.T) {
+	t.Parallel()
+
+	newTarget := func(instance, job string) *Target {
+		return &Target{labels: labels.FromStrings(model.AddressLabel, instance, model.InstanceLabel, instance, model.JobLabel, job)}
+	}
+	prom := newTarget("localhost:9090", "prometheus")
+	node1 := newTarget("localhost:9100", "node")
+	node2 := newTarget("example.com:9100", "node")
+
+	m := &Manager{scrapePools: map[string]*scrapePool{
+		"prometheus": {activeTargets: map[uint64]*Target{1: prom}},
+		"node":       {activeTargets: map[uint64]*Target{2: node1, 3: node2}},
+	}}
+
+	override := &TargetOverride{Interval: time.Minute, Paused: true}
+
+	// Unknown scrape pools are an error.
+	_, err := m.OverrideTargets("unknown", nil, override)
+	require.EqualError(t, err, `scrape pool "unknown" not found`)
+
+	// Only targets matching the matchers are affected.
+	matchers := []*labels.Matcher{labels.MustNewMatcher(labels.MatchRegexp, model.InstanceLabel, "localhost:.*")}
+	affected, err := m.OverrideTargets("node", matchers, override)
+	require.NoError(t, err)
+	require.Equal(t, 1, affected)
+	require.Equal(t, override, node1.Override())
+	require.Nil(t, node2.Override())
+	require.Nil(t, prom.Override())
+
+	// Without a scrape pool, all pools are searched and a nil override
+	// clears previously applied ones.
+	affected, err = m.OverrideTargets("", matchers, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, affected)
+	require.Nil(t, node1.Override())
+}
+
+func TestManagerMarkTargetsStale(t *testing.T) {
+	t.Parallel()
+
+	newTarget := func(instance, job string) *Target {
+		return &Target{labels: labels.FromStrings(model.AddressLabel, instance, model.InstanceLabel, instance, model.JobLabel, job)}
+	}
+	prom := newTarget("localhost:9090", "prometheus")
+	node1 := newTarget("localhost:9100", "node")
+	node2 := newTarget("example.com:9100", "node")
+
+	promLoop, node1Loop, node2Loop := &testLoop{}, &testLoop{}, &testLoop{}
+	m := &Manager{scrapePools: map[string]*scrapePool{
+		"prometheus": {activeTargets: map[uint64]*Target{1: prom}, loops: map[uint64]loop{1: promLoop}},
+		"node":       {activeTargets: map[uint64]*Target{2: node1, 3: node2}, loops: map[uint64]loop{2: node1Loop, 3: node2Loop}},
+	}}
+
+	// Unknown scrape pools are an error.
+	_, err := m.MarkTargetsStale("unknown", nil)
+	require.EqualError(t, err, `scrape pool "unknown" not found`)
+
+	// Only targets matching the matchers are affected.
+	matchers := []*labels.Matcher{labels.MustNewMatcher(labels.MatchRegexp, model.InstanceLabel, "localhost:.*")}
+	affected, err := m.MarkTargetsStale("node", matchers)
+	require.NoError(t, err)
+	require.Equal(t, 1, affected)
+	require.True(t, node1Loop.markedStale)
+	require.False(t, node2Loop.markedStale)
+	require.False(t, promLoop.markedStale)
+
+	// Without a scrape pool, all pools are searched.
+	affected, err = m.MarkTargetsStale("", matchers)
+	require.NoError(t, err)
+	require.Equal(t, 2, affected)
+	require.True(t, promLoop.markedStale)
+}
+
 func TestScrapeLoopRun(t *testing.T) {
 	foreachAppendable(t, func(t *testing.T, appV2 bool) {
 		testScrapeLoopRun(t, appV2)
@@ -2719,6 +2951,188 @@ func testScrapeLoopRunCreatesStaleMarkersOnFailedScrape(t *testing.T, appV2 bool
 		"Appended second sample not as expected. Wanted: stale NaN Got: %x", math.Float64bits(got[6].V))
 }
 
+func TestScrapeLoopStaleAfterFailedScrapes(t *testing.T) {
+	foreachAppendable(t, func(t *testing.T, appV2 bool) {
+		testScrapeLoopStaleAfterFailedScrapes(t, appV2)
+	})
+}
+
+func testScrapeLoopStaleAfterFailedScrapes(t *testing.T, appV2 bool) {
+	signal := make(chan struct{}, 1)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	appTest := teststorage.NewAppendable()
+	sl, scraper := newTestScrapeLoop(t, withAppendable(appTest, appV2), func(sl *scrapeLoop) {
+		sl.ctx = ctx
+		// Tolerate two consecutive failed scrapes before marking series stale.
+		sl.staleAfterFailedScrapes = 3
+		// Since we're writing samples directly below we need to provide a protocol fallback.
+		sl.fallbackScrapeProtocol = "text/plain"
+	})
+
+	// Succeed once, several failures, then stop.
+	numScrapes := 0
+	scraper.scrapeFunc = func(_ context.Context, w io.Writer) error {
+		numScrapes++
+
+		switch numScrapes {
+		case 1:
+			_, _ = w.Write([]byte("metric_a 42\n"))
+			return nil
+		case 5:
+			cancel()
+		}
+		return errors.New("scrape failed")
+	}
+
+	go func() {
+		sl.run(nil)
+		signal <- struct{}{}
+	}()
+
+	select {
+	case <-signal:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "Scrape wasn't stopped.")
+	}
+
+	got := appTest.ResultSamples()
+	// 1 successfully scraped sample
+	// 1 stale marker after the third consecutive failure
+	// 5x 5 report samples for each scrape successful or not.
+	require.Len(t, got, 27, "Appended samples not as expected:\n%s", appTest)
+	require.Equal(t, 42.0, got[0].V, "Appended first sample not as expected")
+	for i := 1; i <= 15; i++ {
+		require.False(t, value.IsStaleNaN(got[i].V),
+			"Unexpected stale marker at sample %d while failures were still tolerated", i)
+	}
+	require.True(t, value.IsStaleNaN(got[16].V),
+		"Appended sample after third failure not as expected. Wanted: stale NaN Got: %x", math.Float64bits(got[16].V))
+}
+
+func TestScrapeLoopMarkStale(t *testing.T) {
+	foreachAppendable(t, func(t *testing.T, appV2 bool) {
+		testScrapeLoopMarkStale(t, appV2)
+	})
+}
+
+func testScrapeLoopMarkStale(t *testing.T, appV2 bool) {
+	signal := make(chan struct{}, 1)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	appTest := teststorage.NewAppendable()
+	sl, scraper := newTestScrapeLoop(t, withAppendable(appTest, appV2), func(sl *scrapeLoop) {
+		sl.ctx = ctx
+		// Since we're writing samples directly below we need to provide a protocol fallback.
+		sl.fallbackScrapeProtocol = "text/plain"
+	})
+
+	// Scrape once, request staleness markers, then stop.
+	numScrapes := 0
+	scraper.scrapeFunc = func(_ context.Context, w io.Writer) error {
+		numScrapes++
+		if numScrapes == 1 {
+			_, _ = w.Write([]byte("metric_a 42\n"))
+			// The request is picked up before the next scrape is due.
+			sl.markStale()
+			return nil
+		}
+		cancel()
+		return errors.New("scrape failed")
+	}
+
+	go func() {
+		sl.run(nil)
+		signal <- struct{}{}
+	}()
+
+	select {
+	case <-signal:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "Scrape wasn't stopped.")
+	}
+
+	got := appTest.ResultSamples()
+	// 1 successfully scraped sample with 5 report samples, then the explicit
+	// staleness request marks the scraped series and the 5 report series
+	// stale, and the final failed scrape appends another 5 report samples.
+	require.Len(t, got, 17, "Appended samples not as expected:\n%s", appTest)
+	require.Equal(t, 42.0, got[0].V, "Appended first sample not as expected")
+	for i := 6; i <= 11; i++ {
+		require.True(t, value.IsStaleNaN(got[i].V),
+			"Appended sample %d not as expected. Wanted: stale NaN Got: %x", i, math.Float64bits(got[i].V))
+	}
+}
+
+func TestScrapeLoopEndOfRunStalenessMarkerPolicy(t *testing.T) {
+	foreachAppendable(t, func(t *testing.T, appV2 bool) {
+		for _, tc := range []struct {
+			policy      config.StalenessMarkerPolicy
+			expectStale bool
+		}{
+			{policy: config.StalenessMarkerImmediate, expectStale: true},
+			{policy: config.StalenessMarkerNever, expectStale: false},
+		} {
+			t.Run(string(tc.policy), func(t *testing.T) {
+				testScrapeLoopEndOfRunStalenessMarkerPolicy(t, appV2, tc.policy, tc.expectStale)
+			})
+		}
+	})
+}
+
+func testScrapeLoopEndOfRunStalenessMarkerPolicy(t *testing.T, appV2 bool, policy config.StalenessMarkerPolicy, expectStale bool) {
+	signal := make(chan struct{}, 1)
+
+	appTest := teststorage.NewAppendable()
+	sl, scraper := newTestScrapeLoop(t, withAppendable(appTest, appV2), func(sl *scrapeLoop) {
+		sl.stalenessMarkerPolicy = policy
+		// Since we're writing samples directly below we need to provide a protocol fallback.
+		sl.fallbackScrapeProtocol = "text/plain"
+	})
+
+	// Terminate loop after 2 scrapes. Scrapes always succeed so that no
+	// stale markers are produced by the failed scrape path.
+	numScrapes := 0
+	scraper.scrapeFunc = func(_ context.Context, w io.Writer) error {
+		numScrapes++
+		if numScrapes == 2 {
+			go sl.stop()
+			<-sl.ctx.Done()
+		}
+		_, _ = w.Write([]byte("metric_a 42\n"))
+		return nil
+	}
+
+	go func() {
+		// run returns after endOfRunStaleness has completed, which is
+		// immediate for both tested policies.
+		sl.run(nil)
+		signal <- struct{}{}
+	}()
+
+	select {
+	case <-signal:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "Scrape wasn't stopped.")
+	}
+
+	got := appTest.ResultSamples()
+	require.NotEmpty(t, got, "Expected at least one scraped sample:\n%s", appTest)
+	if expectStale {
+		// The scraped series and the 5 report series are marked stale right
+		// after the loop stopped, without waiting for missed scrapes.
+		require.GreaterOrEqual(t, len(got), 12, "Expected at least two scrapes plus stale markers:\n%s", appTest)
+		for i := len(got) - 6; i < len(got); i++ {
+			require.True(t, value.IsStaleNaN(got[i].V),
+				"Appended sample %d not as expected. Wanted: stale NaN Got: %x", i, math.Float64bits(got[i].V))
+		}
+	} else {
+		for i, s := range got {
+			require.False(t, value.IsStaleNaN(s.V), "Unexpected stale marker at sample %d", i)
+		}
+	}
+}
+
 func TestScrapeLoopRunCreatesStaleMarkersOnParseFailure(t *testing.T) {
 	foreachAppendable(t, func(t *testing.T, appV2 bool) {
 		testScrapeLoopRunCreatesStaleMarkersOnParseFailure(t, appV2)
@@ -3253,25 +3667,195 @@ func testScrapeLoopAppendSampleLimit(t *testing.T, appV2 bool) {
 	require.Equal(t, 1, seriesAdded)
 }
 
-func TestScrapeLoop_HistogramBucketLimit(t *testing.T) {
+func TestSampleRateLimiter(t *testing.T) {
+	type step struct {
+		dt        time.Duration
+		newSeries bool
+		want      admitDecision
+	}
+	now := time.Now()
+	for _, tc := range []struct {
+		name     string
+		rate     float64
+		interval time.Duration
+		policy   config.SheddingPolicy
+		steps    []step
+	}{
+		{
+			name: "drop policy admits within burst and sheds new series over it",
+			rate: 1, interval: 2 * time.Second, policy: config.SheddingPolicyDrop,
+			steps: []step{
+				{0, true, admitSample},
+				{0, true, admitSample},
+				{0, true, shedSample},
+				{0, false, admitSample},
+			},
+		},
+		{
+			name: "fail policy fails the scrape over the limit",
+			rate: 1, interval: time.Second, policy: config.SheddingPolicyFail,
+			steps: []step{
+				{0, false, admitSample},
+				{0, false, failScrape},
+			},
+		},
+		{
+			name: "downsample policy sheds every other sample over the limit",
+			rate: 1, interval: time.Second, policy: config.SheddingPolicyDownsample,
+			steps: []step{
+				{0, true, admitSample},
+				{0, true, shedSample},
+				{0, true, admitSample},
+				{0, true, shedSample},
+			},
+		},
+		{
+			name: "budget refills between scrapes",
+			rate: 1, interval: time.Second, policy: config.SheddingPolicyDrop,
+			steps: []step{
+				{0, true, admitSample},
+				{0, true, shedSample},
+				{2 * time.Second, true, admitSample},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l := newSampleRateLimiter(tc.rate, tc.interval, tc.policy)
+			for i, s := range tc.steps {
+				require.Equal(t, s.want, l.admit(now.Add(s.dt), s.newSeries), "step %d", i)
+			}
+		})
+	}
+}
+
+func TestScrapeLoopAppendSampleRateLimit(t *testing.T) {
 	foreachAppendable(t, func(t *testing.T, appV2 bool) {
-		testScrapeLoopHistogramBucketLimit(t, appV2)
+		testScrapeLoopAppendSampleRateLimit(t, appV2)
 	})
 }
 
-func testScrapeLoopHistogramBucketLimit(t *testing.T, appV2 bool) {
+func testScrapeLoopAppendSampleRateLimit(t *testing.T, appV2 bool) {
+	appTest := teststorage.NewAppendable()
 	sl, _ := newTestScrapeLoop(t, func(sl *scrapeLoop) {
 		if appV2 {
 			sl.appendableV2 = appendableV2Func(func(ctx context.Context) storage.AppenderV2 {
-				return &bucketLimitAppenderV2{AppenderV2: teststorage.NewAppendable().AppenderV2(ctx), limit: 2}
+				return appTest.AppenderV2(ctx)
 			})
 		} else {
 			sl.appendable = appendableFunc(func(ctx context.Context) storage.Appender {
-				return &bucketLimitAppender{Appender: teststorage.NewAppendable().Appender(ctx), limit: 2}
+				return appTest.Appender(ctx)
 			})
 		}
+		// Two samples of budget and no refill within the test.
+		sl.sampleRateLimiter = &sampleRateLimiter{policy: config.SheddingPolicyDrop, burst: 2, tokens: 2}
+	})
 
-		sl.enableNativeHistogramScraping = true
+	beforeMetric := dto.Metric{}
+	require.NoError(t, sl.metrics.targetScrapeSamplesShed.Write(&beforeMetric))
+	beforeMetricValue := beforeMetric.GetCounter().GetValue()
+
+	now := time.Now()
+	app := sl.appender()
+	total, added, seriesAdded, err := app.append([]byte("metric_a 1\nmetric_b 1\nmetric_c 1\n"), "text/plain", now)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+	require.Equal(t, 3, total)
+	require.Equal(t, 3, added)
+	require.Equal(t, 2, seriesAdded)
+	require.Equal(t, 1, sl.lastSamplesShed)
+
+	metric := dto.Metric{}
+	require.NoError(t, sl.metrics.targetScrapeSamplesShed.Write(&metric))
+	require.Equal(t, 1.0, metric.GetCounter().GetValue()-beforeMetricValue)
+
+	// The two samples that fit into the budget made it to storage.
+	want := []sample{
+		{
+			L: labels.FromStrings(model.MetricNameLabel, "metric_a"),
+			T: timestamp.FromTime(now),
+			V: 1,
+		},
+		{
+			L: labels.FromStrings(model.MetricNameLabel, "metric_b"),
+			T: timestamp.FromTime(now),
+			V: 1,
+		},
+	}
+	teststorage.RequireEqual(t, want, appTest.ResultSamples(), "Appended samples not as expected:\n%s", appTest)
+
+	// On the next scrape the budget is still exhausted: series known from the
+	// previous scrape keep reporting while the shed series stays shed.
+	app = sl.appender()
+	total, added, seriesAdded, err = app.append([]byte("metric_a 2\nmetric_b 2\nmetric_c 2\n"), "text/plain", now)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+	require.Equal(t, 3, total)
+	require.Equal(t, 3, added)
+	require.Equal(t, 0, seriesAdded)
+	require.Equal(t, 1, sl.lastSamplesShed)
+}
+
+func TestScrapeLoopAppendSampleRateLimitFail(t *testing.T) {
+	foreachAppendable(t, func(t *testing.T, appV2 bool) {
+		testScrapeLoopAppendSampleRateLimitFail(t, appV2)
+	})
+}
+
+func testScrapeLoopAppendSampleRateLimitFail(t *testing.T, appV2 bool) {
+	appTest := teststorage.NewAppendable()
+	sl, _ := newTestScrapeLoop(t, func(sl *scrapeLoop) {
+		if appV2 {
+			sl.appendableV2 = appendableV2Func(func(ctx context.Context) storage.AppenderV2 {
+				return appTest.AppenderV2(ctx)
+			})
+		} else {
+			sl.appendable = appendableFunc(func(ctx context.Context) storage.Appender {
+				return appTest.Appender(ctx)
+			})
+		}
+		sl.sampleRateLimiter = &sampleRateLimiter{policy: config.SheddingPolicyFail, burst: 1, tokens: 1}
+	})
+
+	beforeMetric := dto.Metric{}
+	require.NoError(t, sl.metrics.targetScrapeSampleRateLimit.Write(&beforeMetric))
+	beforeMetricValue := beforeMetric.GetCounter().GetValue()
+
+	now := time.Now()
+	app := sl.appender()
+	total, added, seriesAdded, err := app.append([]byte("metric_a 1\nmetric_b 1\nmetric_c 1\n"), "text/plain", now)
+	require.ErrorIs(t, err, errSampleRateLimit)
+	require.NoError(t, app.Rollback())
+	require.Equal(t, 3, total)
+	require.Equal(t, 3, added)
+	require.Equal(t, 1, seriesAdded)
+	require.Equal(t, 2, sl.lastSamplesShed)
+
+	// Check that the Counter has been incremented a single time for the scrape,
+	// not multiple times for each sample.
+	metric := dto.Metric{}
+	require.NoError(t, sl.metrics.targetScrapeSampleRateLimit.Write(&metric))
+	require.Equal(t, 1.0, metric.GetCounter().GetValue()-beforeMetricValue)
+}
+
+func TestScrapeLoop_HistogramBucketLimit(t *testing.T) {
+	foreachAppendable(t, func(t *testing.T, appV2 bool) {
+		testScrapeLoopHistogramBucketLimit(t, appV2)
+	})
+}
+
+func testScrapeLoopHistogramBucketLimit(t *testing.T, appV2 bool) {
+	sl, _ := newTestScrapeLoop(t, func(sl *scrapeLoop) {
+		if appV2 {
+			sl.appendableV2 = appendableV2Func(func(ctx context.Context) storage.AppenderV2 {
+				return &bucketLimitAppenderV2{AppenderV2: teststorage.NewAppendable().AppenderV2(ctx), limit: 2}
+			})
+		} else {
+			sl.appendable = appendableFunc(func(ctx context.Context) storage.Appender {
+				return &bucketLimitAppender{Appender: teststorage.NewAppendable().Appender(ctx), limit: 2}
+			})
+		}
+
+		sl.enableNativeHistogramScraping = true
 		sl.sampleMutator = func(l labels.Labels) labels.Labels {
 			if l.Has("deleteme") {
 				return labels.EmptyLabels()
@@ -3414,6 +3998,62 @@ func testScrapeLoopChangingMetricString(t *testing.T, appV2 bool) {
 	teststorage.RequireEqual(t, want, appTest.ResultSamples(), "Appended samples not as expected:\n%s", appTest)
 }
 
+func TestScrapeLoopAppendStrictExposition(t *testing.T) {
+	foreachAppendable(t, func(t *testing.T, appV2 bool) {
+		for _, tc := range []struct {
+			name        string
+			strict      bool
+			scrape      string
+			errContains string
+		}{
+			{
+				name:   "clean exposition",
+				strict: true,
+				scrape: "# TYPE requests counter\nrequests_total 10\n# TYPE temp gauge\ntemp 20\n",
+			},
+			{
+				name:        "duplicate metric family",
+				strict:      true,
+				scrape:      "# TYPE temp gauge\ntemp 20\n# TYPE requests counter\nrequests_total 1\n# TYPE temp gauge\ntemp{zone=\"b\"} 21\n",
+				errContains: `duplicate metric family "temp"`,
+			},
+			{
+				name:        "inconsistent types",
+				strict:      true,
+				scrape:      "# TYPE requests counter\nrequests_total 1\n# TYPE requests gauge\nrequests 2\n",
+				errContains: `metric family "requests" declared with inconsistent types "counter" and "gauge"`,
+			},
+			{
+				name:        "counter without _total suffix",
+				strict:      true,
+				scrape:      "# TYPE requests counter\nrequests 1\n",
+				errContains: `sample "requests" of counter family "requests" lacks the _total suffix`,
+			},
+			{
+				name:   "violations ignored without strict mode",
+				scrape: "# TYPE temp gauge\ntemp 20\n# TYPE requests counter\nrequests 1\n# TYPE temp gauge\ntemp{zone=\"b\"} 21\n",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				sl, _ := newTestScrapeLoop(t, withAppendable(teststorage.NewAppendable(), appV2), func(sl *scrapeLoop) {
+					sl.strictExposition = tc.strict
+				})
+
+				app := sl.appender()
+				_, _, _, err := app.append([]byte(tc.scrape), "text/plain", time.Now())
+				if tc.errContains == "" {
+					require.NoError(t, err)
+					require.NoError(t, app.Commit())
+					return
+				}
+				require.ErrorContains(t, err, "strict exposition check failed")
+				require.ErrorContains(t, err, tc.errContains)
+				require.NoError(t, app.Rollback())
+			})
+		}
+	})
+}
+
 func TestScrapeLoopAppendFailsWithNoContentType(t *testing.T) {
 	foreachAppendable(t, func(t *testing.T, appV2 bool) {
 		testScrapeLoopAppendFailsWithNoContentType(t, appV2)
@@ -4434,25 +5074,31 @@ func TestAcceptHeader(t *testing.T) {
 			name:            "default scrape protocols with underscore escaping",
 			scrapeProtocols: config.DefaultScrapeProtocols,
 			scheme:          model.UnderscoreEscaping,
-			expectedHeader:  "application/openmetrics-text;version=1.0.0;escaping=underscores;q=0.6,application/openmetrics-text;version=0.0.1;q=0.5,text/plain;version=1.0.0;escaping=underscores;q=0.4,text/plain;version=0.0.4;q=0.3,*/*;q=0.2",
+			expectedHeader:  "application/openmetrics-text;version=1.0.0;escaping=underscores;q=0.8,application/openmetrics-text;version=0.0.1;q=0.7,text/plain;version=1.0.0;escaping=underscores;q=0.6,text/plain;version=0.0.4;q=0.5,*/*;q=0.4",
 		},
 		{
 			name:            "default proto first scrape protocols with underscore escaping",
 			scrapeProtocols: config.DefaultProtoFirstScrapeProtocols,
 			scheme:          model.DotsEscaping,
-			expectedHeader:  "application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.6,application/openmetrics-text;version=1.0.0;escaping=dots;q=0.5,application/openmetrics-text;version=0.0.1;q=0.4,text/plain;version=1.0.0;escaping=dots;q=0.3,text/plain;version=0.0.4;q=0.2,*/*;q=0.1",
+			expectedHeader:  "application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.8,application/openmetrics-text;version=1.0.0;escaping=dots;q=0.7,application/openmetrics-text;version=0.0.1;q=0.6,text/plain;version=1.0.0;escaping=dots;q=0.5,text/plain;version=0.0.4;q=0.4,*/*;q=0.3",
 		},
 		{
 			name:            "default scrape protocols with no escaping",
 			scrapeProtocols: config.DefaultScrapeProtocols,
 			scheme:          model.NoEscaping,
-			expectedHeader:  "application/openmetrics-text;version=1.0.0;escaping=allow-utf-8;q=0.6,application/openmetrics-text;version=0.0.1;q=0.5,text/plain;version=1.0.0;escaping=allow-utf-8;q=0.4,text/plain;version=0.0.4;q=0.3,*/*;q=0.2",
+			expectedHeader:  "application/openmetrics-text;version=1.0.0;escaping=allow-utf-8;q=0.8,application/openmetrics-text;version=0.0.1;q=0.7,text/plain;version=1.0.0;escaping=allow-utf-8;q=0.6,text/plain;version=0.0.4;q=0.5,*/*;q=0.4",
 		},
 		{
 			name:            "default proto first scrape protocols with no escaping",
 			scrapeProtocols: config.DefaultProtoFirstScrapeProtocols,
 			scheme:          model.NoEscaping,
-			expectedHeader:  "application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.6,application/openmetrics-text;version=1.0.0;escaping=allow-utf-8;q=0.5,application/openmetrics-text;version=0.0.1;q=0.4,text/plain;version=1.0.0;escaping=allow-utf-8;q=0.3,text/plain;version=0.0.4;q=0.2,*/*;q=0.1",
+			expectedHeader:  "application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.8,application/openmetrics-text;version=1.0.0;escaping=allow-utf-8;q=0.7,application/openmetrics-text;version=0.0.1;q=0.6,text/plain;version=1.0.0;escaping=allow-utf-8;q=0.5,text/plain;version=0.0.4;q=0.4,*/*;q=0.3",
+		},
+		{
+			name:            "otlp scrape protocols preferred over text",
+			scrapeProtocols: []config.ScrapeProtocol{config.OTLPJSON, config.OTLPProto, config.PrometheusText0_0_4},
+			scheme:          model.NoEscaping,
+			expectedHeader:  "application/vnd.otlp+json;q=0.8,application/vnd.otlp+proto;q=0.7,text/plain;version=0.0.4;q=0.6,*/*;q=0.5",
 		},
 	}
 
@@ -4495,7 +5141,7 @@ func TestRequestTraceparentHeader(t *testing.T) {
 	serverURL, err := url.Parse(server.URL)
 	require.NoError(t, err)
 
-	client, err := newScrapeClient(config_util.DefaultHTTPClientConfig, "test")
+	client, err := newScrapeClient(config_util.DefaultHTTPClientConfig, "test", config.DNSResolutionAny)
 	require.NoError(t, err)
 
 	ts := &targetScraper{
@@ -4515,6 +5161,310 @@ func TestRequestTraceparentHeader(t *testing.T) {
 	t.Cleanup(func() { _ = resp.Body.Close() })
 }
 
+// TestTargetScraperMethodAndBody verifies that the scrape method and request
+// body configured through target labels or a body file are sent with every
+// scrape, including repeated scrapes that reuse the cached request.
+func TestTargetScraperMethodAndBody(t *testing.T) {
+	bodyFile := filepath.Join(t.TempDir(), "body.json")
+	require.NoError(t, os.WriteFile(bodyFile, []byte(`{"query": "all"}`), 0o600))
+
+	for _, tc := range []struct {
+		name           string
+		labels         []string
+		bodyFile       string
+		expectedMethod string
+		expectedBody   string
+	}{
+		{
+			name:           "default GET without body",
+			expectedMethod: "GET",
+			expectedBody:   "",
+		},
+		{
+			name:           "POST with body from label",
+			labels:         []string{scrapeMethodLabel, "POST", scrapeBodyLabel, `{"query": "all"}`},
+			expectedMethod: "POST",
+			expectedBody:   `{"query": "all"}`,
+		},
+		{
+			name:           "PUT with body from file",
+			labels:         []string{scrapeMethodLabel, "PUT"},
+			bodyFile:       bodyFile,
+			expectedMethod: "PUT",
+			expectedBody:   `{"query": "all"}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				require.Equal(t, tc.expectedMethod, r.Method)
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedBody, string(body))
+			}))
+			defer server.Close()
+			serverURL, err := url.Parse(server.URL)
+			require.NoError(t, err)
+
+			client, err := newScrapeClient(config_util.DefaultHTTPClientConfig, "test", config.DNSResolutionAny)
+			require.NoError(t, err)
+
+			lbls := append([]string{
+				model.SchemeLabel, serverURL.Scheme,
+				model.AddressLabel, serverURL.Host,
+			}, tc.labels...)
+			ts := &targetScraper{
+				Target: &Target{
+					labels:       labels.FromStrings(lbls...),
+					scrapeConfig: &config.ScrapeConfig{},
+				},
+				client:   client,
+				bodyFile: tc.bodyFile,
+			}
+
+			// Scrape twice so the request body is also verified on the
+			// cached request path.
+			for range 2 {
+				resp, err := ts.scrape(context.Background())
+				require.NoError(t, err)
+				resp.Body.Close()
+			}
+		})
+	}
+}
+
+func TestTargetScraperRequestHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "prometheus-edge", r.Header.Get("User-Agent"))
+		require.Equal(t, "tenant-42", r.Header.Get("X-Scope-OrgID"))
+		require.Equal(t, "audience $ literal", r.Header.Get("X-Audience"))
+		require.Empty(t, r.Header.Get("X-Missing"))
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client, err := newScrapeClient(config_util.DefaultHTTPClientConfig, "test", config.DNSResolutionAny)
+	require.NoError(t, err)
+
+	ts := &targetScraper{
+		Target: &Target{
+			labels: labels.FromStrings(
+				model.SchemeLabel, serverURL.Scheme,
+				model.AddressLabel, serverURL.Host,
+				"tenant", "tenant-42",
+			),
+			scrapeConfig: &config.ScrapeConfig{},
+		},
+		client: client,
+		requestHeaders: map[string]string{
+			"User-Agent":    "prometheus-edge",
+			"X-Scope-OrgID": "${tenant}",
+			"X-Audience":    "audience $$ literal",
+			"X-Missing":     "${no_such_label}",
+		},
+	}
+
+	resp, err := ts.scrape(context.Background())
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+func TestTargetScraperFallbackEndpoints(t *testing.T) {
+	var primaryUp bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/metrics":
+			if !primaryUp {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte("metric_primary 1\n"))
+		case "/new-metrics":
+			w.Write([]byte("metric_fallback 1\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client, err := newScrapeClient(config_util.DefaultHTTPClientConfig, "test", config.DNSResolutionAny)
+	require.NoError(t, err)
+
+	ts := &targetScraper{
+		Target: &Target{
+			labels: labels.FromStrings(
+				model.SchemeLabel, serverURL.Scheme,
+				model.AddressLabel, serverURL.Host,
+				model.MetricsPathLabel, "/metrics",
+			),
+			scrapeConfig: &config.ScrapeConfig{},
+		},
+		client: client,
+		fallbackEndpoints: []config.FallbackScrapeEndpoint{
+			{MetricsPath: "/does-not-exist"},
+			{MetricsPath: "/new-metrics"},
+		},
+		metrics: newTestScrapeMetrics(t),
+	}
+
+	readBody := func() string {
+		resp, err := ts.scrape(context.Background())
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		_, err = ts.readResponse(context.Background(), resp, &buf)
+		require.NoError(t, err)
+		return buf.String()
+	}
+
+	// The primary endpoint is down, so the scrape is served by the second
+	// fallback endpoint.
+	require.Equal(t, "metric_fallback 1\n", readBody())
+	require.Equal(t, 2, ts.ScrapeEndpointIndex())
+
+	// Once the primary endpoint recovers it takes precedence again.
+	primaryUp = true
+	require.Equal(t, "metric_primary 1\n", readBody())
+	require.Equal(t, 0, ts.ScrapeEndpointIndex())
+}
+
+func TestNewDialContextFunc(t *testing.T) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	require.Nil(t, newDialContextFunc(config.DNSResolutionAny))
+
+	for _, tc := range []struct {
+		strategy config.DNSResolutionStrategy
+		success  bool
+	}{
+		// The listener is IPv4 only, so an IPv6-only dial must fail while
+		// the fallback strategies succeed.
+		{strategy: config.DNSResolutionIPv4, success: true},
+		{strategy: config.DNSResolutionIPv6, success: false},
+		{strategy: config.DNSResolutionPreferIPv4, success: true},
+		{strategy: config.DNSResolutionPreferIPv6, success: true},
+	} {
+		t.Run(string(tc.strategy), func(t *testing.T) {
+			dial := newDialContextFunc(tc.strategy)
+			require.NotNil(t, dial)
+			conn, err := dial(context.Background(), "tcp", listener.Addr().String())
+			if !tc.success {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			conn.Close()
+		})
+	}
+}
+
+// TestTargetScraperResolvedAddress verifies that scraping records the remote
+// address of the connection on the target.
+func TestTargetScraperResolvedAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client, err := newScrapeClient(config_util.DefaultHTTPClientConfig, "test", config.DNSResolutionAny)
+	require.NoError(t, err)
+
+	ts := &targetScraper{
+		Target: &Target{
+			labels: labels.FromStrings(
+				model.SchemeLabel, serverURL.Scheme,
+				model.AddressLabel, serverURL.Host,
+			),
+			scrapeConfig: &config.ScrapeConfig{},
+		},
+		client: client,
+	}
+	require.Empty(t, ts.ResolvedAddress())
+
+	resp, err := ts.scrape(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	require.Equal(t, serverURL.Host, ts.ResolvedAddress())
+}
+
+// TestTargetScraperTLSCertExpiry verifies that a scrape over TLS records the
+// expiry of the certificate chain presented by the target.
+func TestTargetScraperTLSCertExpiry(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ts := &targetScraper{
+		Target: &Target{
+			labels: labels.FromStrings(
+				model.SchemeLabel, serverURL.Scheme,
+				model.AddressLabel, serverURL.Host,
+			),
+			scrapeConfig: &config.ScrapeConfig{},
+		},
+		client: server.Client(),
+	}
+	_, ok := ts.TLSCertExpiry()
+	require.False(t, ok)
+
+	resp, err := ts.scrape(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	expiry, ok := ts.TLSCertExpiry()
+	require.True(t, ok)
+	require.True(t, expiry.Equal(server.Certificate().NotAfter))
+}
+
+// TestTargetScraperDNSResolutionDuration verifies that scraping a target
+// addressed by hostname records the duration of the DNS lookup.
+func TestTargetScraperDNSResolutionDuration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client, err := newScrapeClient(config_util.DefaultHTTPClientConfig, "test", config.DNSResolutionAny)
+	require.NoError(t, err)
+
+	ts := &targetScraper{
+		Target: &Target{
+			labels: labels.FromStrings(
+				model.SchemeLabel, serverURL.Scheme,
+				// The server listens on a literal IP, which needs no lookup,
+				// so address it via a name the resolver can answer.
+				model.AddressLabel, net.JoinHostPort("localhost", serverURL.Port()),
+			),
+			scrapeConfig: &config.ScrapeConfig{},
+		},
+		client: client,
+	}
+	_, ok := ts.DNSResolutionDuration()
+	require.False(t, ok)
+
+	resp, err := ts.scrape(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	d, ok := ts.DNSResolutionDuration()
+	require.True(t, ok)
+	require.Positive(t, d)
+}
+
 func TestTargetScraperScrapeOK(t *testing.T) {
 	const (
 		configTimeout   = 1500 * time.Millisecond
@@ -4581,6 +5531,7 @@ func TestTargetScraperScrapeOK(t *testing.T) {
 			client:       http.DefaultClient,
 			timeout:      configTimeout,
 			acceptHeader: acceptHeader,
+			metrics:      newTestScrapeMetrics(t),
 		}
 		var buf bytes.Buffer
 
@@ -4716,6 +5667,7 @@ func TestTargetScrapeScrapeNotFound(t *testing.T) {
 		},
 		client:       http.DefaultClient,
 		acceptHeader: acceptHeader(config.DefaultGlobalConfig.ScrapeProtocols, model.UnderscoreEscaping),
+		metrics:      newTestScrapeMetrics(t),
 	}
 
 	resp, err := ts.scrape(context.Background())
@@ -4798,6 +5750,191 @@ func TestTargetScraperBodySizeLimit(t *testing.T) {
 	require.Len(t, responseBody, buf.Len())
 }
 
+func TestTargetScraperConditionalRequests(t *testing.T) {
+	var (
+		etag         = `"v1"`
+		responseBody = "metric_a 1\nmetric_b 2\n"
+		scrapes      int
+	)
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scrapes++
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Content-Type", `text/plain; version=0.0.4`)
+			w.Header().Set("ETag", etag)
+			_, _ = w.Write([]byte(responseBody))
+		}),
+	)
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ts := &targetScraper{
+		Target: &Target{
+			labels: labels.FromStrings(
+				model.SchemeLabel, serverURL.Scheme,
+				model.AddressLabel, serverURL.Host,
+			),
+			scrapeConfig: &config.ScrapeConfig{},
+		},
+		client:                 http.DefaultClient,
+		acceptHeader:           acceptHeader(config.DefaultGlobalConfig.ScrapeProtocols, model.UnderscoreEscaping),
+		useConditionalRequests: true,
+		metrics:                newTestScrapeMetrics(t),
+	}
+	var buf bytes.Buffer
+
+	doScrape := func() string {
+		t.Helper()
+		buf.Reset()
+		resp, err := ts.scrape(context.Background())
+		require.NoError(t, err)
+		contentType, err := ts.readResponse(context.Background(), resp, &buf)
+		require.NoError(t, err)
+		return contentType
+	}
+
+	// The first scrape reads the full body and caches the validators.
+	contentType := doScrape()
+	require.Equal(t, `text/plain; version=0.0.4`, contentType)
+	require.Equal(t, responseBody, buf.String())
+	require.Equal(t, 1, scrapes)
+
+	// The second scrape is conditional: the target answers 304 and the cached
+	// body is replayed, including the original content type.
+	contentType = doScrape()
+	require.Equal(t, `text/plain; version=0.0.4`, contentType)
+	require.Equal(t, responseBody, buf.String())
+	require.Equal(t, 2, scrapes)
+	require.Equal(t, 1.0, prom_testutil.ToFloat64(ts.metrics.targetScrapeNotModified))
+
+	// Once the target's content changes, the conditional request no longer
+	// matches and the new body replaces the cached one.
+	etag = `"v2"`
+	responseBody = "metric_a 3\nmetric_b 4\n"
+	doScrape()
+	require.Equal(t, responseBody, buf.String())
+	require.Equal(t, 3, scrapes)
+
+	contentType = doScrape()
+	require.Equal(t, `text/plain; version=0.0.4`, contentType)
+	require.Equal(t, responseBody, buf.String())
+	require.Equal(t, 4, scrapes)
+	require.Equal(t, 2.0, prom_testutil.ToFloat64(ts.metrics.targetScrapeNotModified))
+
+	// With the feature off, scrapes are unconditional and nothing is cached,
+	// even though the target keeps sending validators.
+	ts.useConditionalRequests = false
+	ts.lastETag, ts.lastModified, ts.cachedBody, ts.cachedMediaType = "", "", nil, ""
+	doScrape()
+	doScrape()
+	require.Equal(t, responseBody, buf.String())
+	require.Equal(t, 6, scrapes)
+	require.Empty(t, ts.lastETag)
+	require.Nil(t, ts.cachedBody)
+	require.Equal(t, 2.0, prom_testutil.ToFloat64(ts.metrics.targetScrapeNotModified))
+}
+
+func TestTargetScraperPayloadSizeMetrics(t *testing.T) {
+	const responseBody = "metric_a 1\nmetric_b 2\n"
+	var gzipResponse bool
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", `text/plain; version=0.0.4`)
+			if gzipResponse {
+				w.Header().Set("Content-Encoding", "gzip")
+				gw := gzip.NewWriter(w)
+				defer func() { _ = gw.Close() }()
+				_, _ = gw.Write([]byte(responseBody))
+				return
+			}
+			_, _ = w.Write([]byte(responseBody))
+		}),
+	)
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	reg, metrics := newTestRegistryAndScrapeMetrics(t)
+	ts := &targetScraper{
+		Target: &Target{
+			labels: labels.FromStrings(
+				model.SchemeLabel, serverURL.Scheme,
+				model.AddressLabel, serverURL.Host,
+				model.JobLabel, "payload_size",
+				model.InstanceLabel, serverURL.Host,
+			),
+			scrapeConfig: &config.ScrapeConfig{},
+		},
+		client:               http.DefaultClient,
+		acceptHeader:         acceptHeader(config.DefaultGlobalConfig.ScrapeProtocols, model.UnderscoreEscaping),
+		acceptEncodingHeader: acceptEncodingHeader(true),
+		metrics:              metrics,
+	}
+
+	// histogram returns the per-target histogram for the metric family with
+	// the given name.
+	histogram := func(name string) *dto.Histogram {
+		t.Helper()
+		mfs, err := reg.Gather()
+		require.NoError(t, err)
+		for _, mf := range mfs {
+			if mf.GetName() != name {
+				continue
+			}
+			require.Len(t, mf.GetMetric(), 1)
+			m := mf.GetMetric()[0]
+			require.ElementsMatch(t, []string{"payload_size", serverURL.Host}, []string{
+				m.GetLabel()[0].GetValue(), m.GetLabel()[1].GetValue(),
+			})
+			return m.GetHistogram()
+		}
+		t.Fatalf("metric family %q not found", name)
+		return nil
+	}
+
+	// Uncompressed response: both histograms observe the raw body size.
+	var buf bytes.Buffer
+	resp, err := ts.scrape(context.Background())
+	require.NoError(t, err)
+	_, err = ts.readResponse(context.Background(), resp, &buf)
+	require.NoError(t, err)
+
+	h := histogram("prometheus_target_scrape_body_size_bytes")
+	require.Equal(t, uint64(1), h.GetSampleCount())
+	require.Equal(t, float64(len(responseBody)), h.GetSampleSum())
+	h = histogram("prometheus_target_scrape_response_size_bytes")
+	require.Equal(t, uint64(1), h.GetSampleCount())
+	require.Equal(t, float64(len(responseBody)), h.GetSampleSum())
+
+	// Gzipped response: the response size histogram observes the on-the-wire
+	// size, which differs from the uncompressed body size.
+	gzipResponse = true
+	buf.Reset()
+	resp, err = ts.scrape(context.Background())
+	require.NoError(t, err)
+	_, err = ts.readResponse(context.Background(), resp, &buf)
+	require.NoError(t, err)
+
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	_, err = gw.Write([]byte(responseBody))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	h = histogram("prometheus_target_scrape_body_size_bytes")
+	require.Equal(t, uint64(2), h.GetSampleCount())
+	require.Equal(t, float64(2*len(responseBody)), h.GetSampleSum())
+	h = histogram("prometheus_target_scrape_response_size_bytes")
+	require.Equal(t, uint64(2), h.GetSampleCount())
+	require.Equal(t, float64(len(responseBody)+gzipped.Len()), h.GetSampleSum())
+}
+
 // testScraper implements the scraper interface and allows setting values
 // returned by its methods. It also allows setting a custom scrape function.
 type testScraper struct {
@@ -6563,6 +7700,46 @@ func TestPickSchema(t *testing.T) {
 	}
 }
 
+func TestMaxSchemaFromConfig(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+	for _, tc := range []struct {
+		name         string
+		factor       float64
+		targetSchema *int32
+		schema       int32
+	}{
+		{
+			name:   "default",
+			schema: 8,
+		},
+		{
+			name:         "target schema only",
+			targetSchema: int32Ptr(3),
+			schema:       3,
+		},
+		{
+			name:         "target schema lower than min bucket factor",
+			factor:       1.4,
+			targetSchema: int32Ptr(0),
+			schema:       0,
+		},
+		{
+			name:         "min bucket factor lower than target schema",
+			factor:       2,
+			targetSchema: int32Ptr(3),
+			schema:       0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.ScrapeConfig{
+				NativeHistogramMinBucketFactor: tc.factor,
+				NativeHistogramTargetSchema:    tc.targetSchema,
+			}
+			require.Equal(t, tc.schema, maxSchemaFromConfig(cfg))
+		})
+	}
+}
+
 func BenchmarkTargetScraperGzip(b *testing.B) {
 	scenarios := []struct {
 		metricsCount int
@@ -0,0 +1,123 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scrape
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/common/model"
+)
+
+// metricNameFromSeries extracts the metric name from the series bytes handed
+// out by the parsers, which include the label portion. The returned string
+// shares memory with the input and must not be retained.
+func metricNameFromSeries(series []byte) string {
+	if i := bytes.IndexByte(series, '{'); i >= 0 {
+		series = series[:i]
+	}
+	return yoloString(series)
+}
+
+// maxExpositionViolations caps the number of violations recorded per scrape so
+// that the error reported in the target status stays readable.
+const maxExpositionViolations = 5
+
+// expositionValidator checks a scrape against the exposition format
+// conventions enforced by strict_exposition. It is fed metric family
+// declarations and sample names in exposition order and collects one message
+// per violation.
+type expositionValidator struct {
+	currentFamily string
+	currentType   model.MetricType
+	// families maps every family seen so far to its declared type, to detect
+	// duplicate declarations and type mismatches.
+	families map[string]model.MetricType
+	// suffixReported tracks counter families already reported for suffix
+	// misuse, so a family with many samples yields a single violation.
+	suffixReported map[string]struct{}
+
+	violations []string
+	truncated  int
+}
+
+// observeFamily records a metric family declaration from a TYPE line.
+func (v *expositionValidator) observeFamily(name []byte, typ model.MetricType) {
+	family := string(name)
+	defer func() {
+		v.currentFamily = family
+		v.currentType = typ
+	}()
+
+	if family == v.currentFamily {
+		if typ != v.currentType {
+			v.addViolation(fmt.Sprintf("metric family %q declared with inconsistent types %q and %q", family, v.currentType, typ))
+		}
+		return
+	}
+	if v.families == nil {
+		v.families = map[string]model.MetricType{}
+	}
+	if prev, ok := v.families[family]; ok {
+		if prev != typ {
+			v.addViolation(fmt.Sprintf("metric family %q declared with inconsistent types %q and %q", family, prev, typ))
+		} else {
+			v.addViolation(fmt.Sprintf("duplicate metric family %q", family))
+		}
+	}
+	v.families[family] = typ
+}
+
+// observeSample records a sample of the current metric family. The metric
+// name must not include labels.
+func (v *expositionValidator) observeSample(metricName string) {
+	if v.currentType != model.MetricTypeCounter || !strings.HasPrefix(metricName, v.currentFamily) {
+		return
+	}
+	// Counter samples must carry the _total suffix; _created series are part
+	// of the family in OpenMetrics and exempt.
+	if strings.HasSuffix(metricName, "_total") || strings.HasSuffix(metricName, "_created") {
+		return
+	}
+	if _, ok := v.suffixReported[v.currentFamily]; ok {
+		return
+	}
+	if v.suffixReported == nil {
+		v.suffixReported = map[string]struct{}{}
+	}
+	v.suffixReported[v.currentFamily] = struct{}{}
+	v.addViolation(fmt.Sprintf("sample %q of counter family %q lacks the _total suffix", metricName, v.currentFamily))
+}
+
+func (v *expositionValidator) addViolation(msg string) {
+	if len(v.violations) >= maxExpositionViolations {
+		v.truncated++
+		return
+	}
+	v.violations = append(v.violations, msg)
+}
+
+// err returns an error describing all recorded violations, or nil if the
+// exposition was clean.
+func (v *expositionValidator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	msg := strings.Join(v.violations, "; ")
+	if v.truncated > 0 {
+		return fmt.Errorf("strict exposition check failed: %s; and %d more violations", msg, v.truncated)
+	}
+	return fmt.Errorf("strict exposition check failed: %s", msg)
+}
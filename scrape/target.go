@@ -14,15 +14,20 @@
 package scrape
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"hash/fnv"
+	"net"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	config_util "github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
 
 	"github.com/prometheus/prometheus/config"
@@ -48,6 +53,36 @@ const alwaysScrapeClassicHistogramsLabel = "__always_scrape_classic_histograms__
 // scrape native histograms when scraping a target.
 const scrapeNativeHistogramsLabel = "__scrape_native_histograms__"
 
+// scrapeAlignmentGroupLabel is the name of the label that holds the alignment
+// group the scrape offset of a target is derived from.
+const scrapeAlignmentGroupLabel = "__scrape_alignment_group__"
+
+// scrapeWithoutJitterLabel is the name of the label that holds whether the
+// per-target scrape offset is disabled for a target.
+const scrapeWithoutJitterLabel = "__scrape_without_jitter__"
+
+// scrapeMethodLabel is the name of the label that holds the HTTP method used
+// when scraping a target.
+const scrapeMethodLabel = "__scrape_method__"
+
+// scrapeBodyLabel is the name of the label that holds the HTTP request body
+// sent when scraping a target.
+const scrapeBodyLabel = "__scrape_body__"
+
+// Labels that override TLS settings for a single target through relabeling.
+// They allow targets behind a multi-tenant ingress to be scraped with a
+// different SNI, certificate pin, or minimum TLS version than the rest of
+// the scrape config.
+const (
+	// tlsServerNameLabel overrides the server name (SNI) sent in the TLS handshake.
+	tlsServerNameLabel = "__tls_server_name__"
+	// tlsMinVersionLabel overrides the minimum accepted TLS version, e.g. "TLS12".
+	tlsMinVersionLabel = "__tls_min_version__"
+	// tlsSPKIPinLabel pins the target's certificate to the base64 encoded
+	// SHA-256 of its SubjectPublicKeyInfo.
+	tlsSPKIPinLabel = "__tls_spki_pin_sha256__"
+)
+
 // TargetHealth describes the health state of a target.
 type TargetHealth string
 
@@ -72,7 +107,20 @@ type Target struct {
 	lastScrape         time.Time
 	lastScrapeDuration time.Duration
 	health             TargetHealth
+	healthChangeFunc   func(target *Target, previous, current TargetHealth)
 	metadata           MetricMetadataStore
+	resolvedAddress    string
+	// scrapeEndpointIndex is the index of the endpoint that served the most
+	// recent successful scrape, with 0 being the primary endpoint.
+	scrapeEndpointIndex int
+	effectiveInterval   time.Duration
+	// tlsCertExpiry is the earliest NotAfter across the certificate chain
+	// presented by the target, zero if the target was never scraped over TLS.
+	tlsCertExpiry time.Time
+	// dnsResolutionDuration is the duration of the DNS lookup made for the
+	// most recent new scrape connection, zero if no lookup was observed.
+	dnsResolutionDuration time.Duration
+	override              *TargetOverride
 }
 
 // NewTarget creates a reasonably configured target for querying.
@@ -171,12 +219,25 @@ func (t *Target) offset(interval time.Duration, offsetSeed uint64) time.Duration
 	now := time.Now().UnixNano()
 
 	// Base is a pinned to absolute time, no matter how often offset is called.
-	var (
-		base   = int64(interval) - now%int64(interval)
+	base := int64(interval) - now%int64(interval)
+
+	var offset uint64
+	switch {
+	case t.boolLabel(scrapeWithoutJitterLabel, false):
+		// De-jittered targets are scraped at wall-clock multiples of the
+		// interval.
+		return time.Duration(base)
+	case t.labels.Get(scrapeAlignmentGroupLabel) != "":
+		// All targets sharing an alignment group get the same offset. The
+		// offsetSeed still spreads the groups across an HA setup.
+		h := fnv.New64a()
+		h.Write([]byte(t.labels.Get(scrapeAlignmentGroupLabel)))
+		offset = (h.Sum64() ^ offsetSeed) % uint64(interval)
+	default:
 		offset = (t.hash() ^ offsetSeed) % uint64(interval)
-		next   = base + int64(offset)
-	)
+	}
 
+	next := base + int64(offset)
 	if next > int64(interval) {
 		next -= int64(interval)
 	}
@@ -253,11 +314,54 @@ func (t *Target) URL() *url.URL {
 	}
 }
 
+// fallbackURL returns the target's URL with the metrics path and port
+// replaced according to the given fallback endpoint.
+func (t *Target) fallbackURL(ep config.FallbackScrapeEndpoint) *url.URL {
+	u := t.URL()
+	if ep.MetricsPath != "" {
+		u.Path = ep.MetricsPath
+	}
+	if ep.Port != 0 {
+		host := u.Host
+		if h, _, err := net.SplitHostPort(u.Host); err == nil {
+			host = h
+		}
+		u.Host = net.JoinHostPort(host, strconv.Itoa(ep.Port))
+	}
+	return u
+}
+
+// Method returns the HTTP method used when scraping the target.
+func (t *Target) Method() string {
+	if m := t.labels.Get(scrapeMethodLabel); m != "" {
+		return m
+	}
+	return "GET"
+}
+
+// scrapeBody returns the HTTP request body sent when scraping the target, or
+// an empty string if no body is configured.
+func (t *Target) scrapeBody() string {
+	return t.labels.Get(scrapeBodyLabel)
+}
+
+// expandLabelRefs expands ${labelname} references in s against the target's
+// label set. References to absent labels expand to the empty string and $$
+// yields a literal $.
+func (t *Target) expandLabelRefs(s string) string {
+	return os.Expand(s, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		return t.labels.Get(name)
+	})
+}
+
 // Report sets target data about the last scrape.
 func (t *Target) Report(start time.Time, dur time.Duration, err error) {
 	t.mtx.Lock()
-	defer t.mtx.Unlock()
 
+	previous := t.health
 	if err == nil {
 		t.health = HealthGood
 	} else {
@@ -267,6 +371,24 @@ func (t *Target) Report(start time.Time, dur time.Duration, err error) {
 	t.lastError = err
 	t.lastScrape = start
 	t.lastScrapeDuration = dur
+
+	current, f := t.health, t.healthChangeFunc
+	t.mtx.Unlock()
+
+	// Invoke the health change function outside the lock so that it can use
+	// the target's accessors.
+	if f != nil && current != previous {
+		f(t, previous, current)
+	}
+}
+
+// setHealthChangeFunc sets the function called when the target's health
+// changes.
+func (t *Target) setHealthChangeFunc(f func(target *Target, previous, current TargetHealth)) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.healthChangeFunc = f
 }
 
 // LastError returns the error encountered during the last scrape.
@@ -293,6 +415,126 @@ func (t *Target) LastScrapeDuration() time.Duration {
 	return t.lastScrapeDuration
 }
 
+// ResolvedAddress returns the remote address of the connection used by the
+// most recent scrape, or an empty string if the target was never reached.
+func (t *Target) ResolvedAddress() string {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	return t.resolvedAddress
+}
+
+func (t *Target) setResolvedAddress(addr string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.resolvedAddress = addr
+}
+
+// ScrapeEndpointIndex returns the index of the endpoint that served the most
+// recent successful scrape: 0 is the primary endpoint, higher values index
+// into the configured fallback scrape endpoints. It returns 0 before the
+// target was scraped successfully.
+func (t *Target) ScrapeEndpointIndex() int {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	return t.scrapeEndpointIndex
+}
+
+func (t *Target) setScrapeEndpointIndex(i int) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.scrapeEndpointIndex = i
+}
+
+// TLSCertExpiry returns the earliest NotAfter timestamp across the
+// certificate chain presented by the target during the most recent scrape.
+// The second return value is false if the target was never scraped over TLS.
+func (t *Target) TLSCertExpiry() (time.Time, bool) {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	return t.tlsCertExpiry, !t.tlsCertExpiry.IsZero()
+}
+
+func (t *Target) setTLSCertExpiry(expiry time.Time) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.tlsCertExpiry = expiry
+}
+
+// DNSResolutionDuration returns the duration of the DNS lookup made for the
+// most recent new scrape connection. The second return value is false if no
+// lookup was observed yet, e.g. because the target address is a literal IP
+// or all scrapes so far reused an existing connection.
+func (t *Target) DNSResolutionDuration() (time.Duration, bool) {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	return t.dnsResolutionDuration, t.dnsResolutionDuration > 0
+}
+
+func (t *Target) setDNSResolutionDuration(d time.Duration) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.dnsResolutionDuration = d
+}
+
+// EffectiveInterval returns the scrape interval currently in effect for the
+// target. It may be larger than the configured interval while failure backoff
+// is active. It returns 0 before the target's scrape loop has started.
+func (t *Target) EffectiveInterval() time.Duration {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	return t.effectiveInterval
+}
+
+func (t *Target) setEffectiveInterval(interval time.Duration) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.effectiveInterval = interval
+}
+
+// TargetOverride is a temporary, in-memory override of a target's scrape
+// parameters, applied through the API without a configuration reload. It does
+// not survive a restart or a reload of the target's scrape pool.
+type TargetOverride struct {
+	// Interval overrides the scrape interval when non-zero.
+	Interval time.Duration
+	// Timeout overrides the scrape timeout when non-zero.
+	Timeout time.Duration
+	// Paused stops scraping the target entirely while set.
+	Paused bool
+}
+
+// SetOverride applies a temporary scrape override to the target. A nil
+// override clears any previously applied one.
+func (t *Target) SetOverride(o *TargetOverride) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.override = o
+}
+
+// Override returns the temporary scrape override applied to the target, or
+// nil if there is none.
+func (t *Target) Override() *TargetOverride {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	if t.override == nil {
+		return nil
+	}
+	o := *t.override
+	return &o
+}
+
 // Health returns the last known health state of the target.
 func (t *Target) Health() TargetHealth {
 	t.mtx.RLock()
@@ -321,6 +563,16 @@ func (t *Target) intervalAndTimeout(defaultInterval, defaultDuration time.Durati
 	return time.Duration(interval), time.Duration(timeout), nil
 }
 
+// tlsOverrides returns the per-target TLS settings set through relabeling.
+// All returned values are empty when the target does not override the TLS
+// settings of its scrape config.
+func (t *Target) tlsOverrides() (serverName, minVersion, spkiPin string) {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	return t.labels.Get(tlsServerNameLabel), t.labels.Get(tlsMinVersionLabel), t.labels.Get(tlsSPKIPinLabel)
+}
+
 // boolLabel returns the boolean value of the target label named name, falling
 // back to def when the label is unset or its value does not parse as a boolean.
 func (t *Target) boolLabel(name string, def bool) bool {
@@ -351,8 +603,9 @@ func (ts Targets) Less(i, j int) bool { return ts[i].URL().String() < ts[j].URL(
 func (ts Targets) Swap(i, j int)      { ts[i], ts[j] = ts[j], ts[i] }
 
 var (
-	errSampleLimit = errors.New("sample limit exceeded")
-	errBucketLimit = errors.New("histogram bucket limit exceeded")
+	errSampleLimit     = errors.New("sample limit exceeded")
+	errBucketLimit     = errors.New("histogram bucket limit exceeded")
+	errSampleRateLimit = errors.New("sample rate limit exceeded")
 )
 
 // limitAppender limits the number of total appended samples in a batch.
@@ -486,6 +739,81 @@ func (app *maxSchemaAppender) AppendHistogram(ref storage.SeriesRef, lset labels
 	return ref, nil
 }
 
+// admitDecision is the outcome of checking a single sample against the
+// sample rate limit of a target.
+type admitDecision int
+
+const (
+	// admitSample lets the sample through.
+	admitSample admitDecision = iota
+	// shedSample silently drops the sample.
+	shedSample
+	// failScrape fails the whole scrape, like sample_limit.
+	failScrape
+)
+
+// sampleRateLimiter is a token bucket admitting samples at a configured
+// average rate. It persists across scrapes of a target so that a temporary
+// burst can borrow against budget accumulated during quiet intervals.
+// It is only accessed from the scrape loop goroutine.
+type sampleRateLimiter struct {
+	rate   float64 // Admitted samples per second.
+	burst  float64 // Bucket capacity, sized to one scrape interval worth of samples.
+	policy config.SheddingPolicy
+
+	tokens    float64
+	last      time.Time
+	alternate bool // Downsampling keep/shed flip-flop.
+}
+
+func newSampleRateLimiter(rate float64, interval time.Duration, policy config.SheddingPolicy) *sampleRateLimiter {
+	if policy == "" {
+		policy = config.SheddingPolicyDrop
+	}
+	burst := rate * interval.Seconds()
+	if burst < 1 {
+		burst = 1
+	}
+	return &sampleRateLimiter{
+		rate:   rate,
+		burst:  burst,
+		policy: policy,
+		tokens: burst,
+	}
+}
+
+// admit decides the fate of a single sample scraped at now. newSeries tells
+// whether the sample belongs to a series that was not seen in previous
+// scrapes of the target.
+func (l *sampleRateLimiter) admit(now time.Time, newSeries bool) admitDecision {
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return admitSample
+	}
+	switch l.policy {
+	case config.SheddingPolicyFail:
+		return failScrape
+	case config.SheddingPolicyDownsample:
+		// Shed every other sample while over the limit.
+		l.alternate = !l.alternate
+		if l.alternate {
+			return shedSample
+		}
+		return admitSample
+	default:
+		// Drop: series already known from previous scrapes keep reporting,
+		// samples of new series are shed.
+		if newSeries {
+			return shedSample
+		}
+		return admitSample
+	}
+}
+
 // limitAppenderV2 limits the number of total appended samples in a batch.
 type limitAppenderV2 struct {
 	storage.AppenderV2
@@ -608,6 +936,10 @@ func PopulateDiscoveredLabels(lb *labels.Builder, cfg *config.ScrapeConfig, tLab
 		{Name: convertClassicHistogramsToNHCBLabel, Value: strconv.FormatBool(cfg.ConvertClassicHistogramsToNHCBEnabled())},
 		{Name: alwaysScrapeClassicHistogramsLabel, Value: strconv.FormatBool(cfg.AlwaysScrapeClassicHistogramsEnabled())},
 		{Name: scrapeNativeHistogramsLabel, Value: strconv.FormatBool(cfg.ScrapeNativeHistogramsEnabled())},
+		{Name: scrapeAlignmentGroupLabel, Value: cfg.ScrapeAlignmentGroup},
+		{Name: scrapeWithoutJitterLabel, Value: strconv.FormatBool(cfg.ScrapeWithoutJitter)},
+		{Name: scrapeMethodLabel, Value: cfg.Method},
+		{Name: scrapeBodyLabel, Value: cfg.Body},
 	}
 
 	for _, l := range scrapeLabels {
@@ -670,6 +1002,7 @@ func PopulateLabels(lb *labels.Builder, cfg *config.ScrapeConfig, tLabels, tgLab
 		{convertClassicHistogramsToNHCBLabel, "convert classic histograms to nhcb"},
 		{alwaysScrapeClassicHistogramsLabel, "always scrape classic histograms"},
 		{scrapeNativeHistogramsLabel, "scrape native histograms"},
+		{scrapeWithoutJitterLabel, "scrape without jitter"},
 	} {
 		if v := lb.Get(l.name); v != "" {
 			if _, err := strconv.ParseBool(v); err != nil {
@@ -678,6 +1011,33 @@ func PopulateLabels(lb *labels.Builder, cfg *config.ScrapeConfig, tLabels, tgLab
 		}
 	}
 
+	if v := lb.Get(scrapeWithoutJitterLabel); v != "" && lb.Get(scrapeAlignmentGroupLabel) != "" {
+		if noJitter, _ := strconv.ParseBool(v); noJitter {
+			return labels.EmptyLabels(), errors.New("at most one of scrape alignment group and scrape without jitter must be set")
+		}
+	}
+
+	switch v := lb.Get(scrapeMethodLabel); v {
+	case "", "GET", "POST", "PUT":
+	default:
+		return labels.EmptyLabels(), fmt.Errorf("invalid scrape method %q", v)
+	}
+
+	if v := lb.Get(tlsMinVersionLabel); v != "" {
+		if _, ok := config_util.TLSVersions[v]; !ok {
+			return labels.EmptyLabels(), fmt.Errorf("invalid minimum TLS version %q", v)
+		}
+	}
+	if v := lb.Get(tlsSPKIPinLabel); v != "" {
+		pin, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return labels.EmptyLabels(), fmt.Errorf("error parsing SPKI pin: %w", err)
+		}
+		if len(pin) != sha256.Size {
+			return labels.EmptyLabels(), fmt.Errorf("SPKI pin must be a base64 encoded SHA-256, got %d bytes", len(pin))
+		}
+	}
+
 	// Meta labels are deleted after relabelling. Other internal labels propagate to
 	// the target which decides whether they will be part of their label set.
 	lb.Range(func(l labels.Label) {
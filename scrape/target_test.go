@@ -16,6 +16,7 @@ package scrape
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -105,6 +106,60 @@ func TestTargetOffset(t *testing.T) {
 	}
 }
 
+func TestTargetOffsetAlignmentGroup(t *testing.T) {
+	interval := 10 * time.Second
+	offsetSeed := uint64(0)
+
+	// The offsets of two calls are returned relative to different wall-clock
+	// times, so compare the circular distance of the scrape phases instead.
+	phaseDistance := func(o1, o2 time.Duration) time.Duration {
+		d := ((o2-o1)%interval + interval) % interval
+		if d > interval/2 {
+			d = interval - d
+		}
+		return d
+	}
+	// Generous bound for the time that passes between two offset calls.
+	slack := 100 * time.Millisecond
+
+	// Targets with different labels but the same alignment group share an offset.
+	t1 := newTestTarget("example.com:80", 0, labels.FromStrings(
+		"label", "a", scrapeAlignmentGroupLabel, "ratio-group",
+	))
+	t2 := newTestTarget("example.org:80", 0, labels.FromStrings(
+		"label", "b", scrapeAlignmentGroupLabel, "ratio-group",
+	))
+	require.Less(t, phaseDistance(t1.offset(interval, offsetSeed), t2.offset(interval, offsetSeed)), slack)
+
+	// A different group gets a different offset.
+	t3 := newTestTarget("example.com:80", 0, labels.FromStrings(
+		"label", "a", scrapeAlignmentGroupLabel, "other-group",
+	))
+	require.Greater(t, phaseDistance(t1.offset(interval, offsetSeed), t3.offset(interval, offsetSeed)), slack)
+
+	// A different offset seed moves the whole group.
+	require.Greater(t, phaseDistance(t1.offset(interval, offsetSeed), t1.offset(interval, 0x4d65822107fcfd52)), slack)
+}
+
+func TestTargetOffsetWithoutJitter(t *testing.T) {
+	interval := 10 * time.Second
+
+	target := newTestTarget("example.com:80", 0, labels.FromStrings(
+		scrapeWithoutJitterLabel, "true",
+	))
+
+	before := time.Now().UnixNano()
+	offset := target.offset(interval, 12345)
+	after := time.Now().UnixNano()
+
+	// The next scrape must land on a wall-clock multiple of the interval,
+	// up to the time that passed while computing the offset.
+	rem := (after + int64(offset)) % int64(interval)
+	require.LessOrEqual(t, rem, after-before, "Next scrape is not aligned to the interval.")
+	require.Greater(t, offset, time.Duration(0))
+	require.LessOrEqual(t, offset, interval)
+}
+
 func TestTargetURL(t *testing.T) {
 	scrapeConfig := &config.ScrapeConfig{
 		Params: url.Values{
@@ -138,6 +193,66 @@ func TestTargetURL(t *testing.T) {
 	require.Equal(t, expectedURL, target.URL())
 }
 
+func TestTargetFallbackURL(t *testing.T) {
+	target := NewTarget(labels.FromMap(map[string]string{
+		model.AddressLabel:     "example.com:1234",
+		model.SchemeLabel:      "https",
+		model.MetricsPathLabel: "/metrics",
+	}), &config.ScrapeConfig{}, nil, nil)
+
+	for _, tc := range []struct {
+		name string
+		ep   config.FallbackScrapeEndpoint
+		want string
+	}{
+		{
+			name: "path override",
+			ep:   config.FallbackScrapeEndpoint{MetricsPath: "/new-metrics"},
+			want: "https://example.com:1234/new-metrics",
+		},
+		{
+			name: "port override",
+			ep:   config.FallbackScrapeEndpoint{Port: 9091},
+			want: "https://example.com:9091/metrics",
+		},
+		{
+			name: "path and port override",
+			ep:   config.FallbackScrapeEndpoint{MetricsPath: "/new-metrics", Port: 9091},
+			want: "https://example.com:9091/new-metrics",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, target.fallbackURL(tc.ep).String())
+		})
+	}
+}
+
+func TestTargetHealthChangeFunc(t *testing.T) {
+	target := NewTarget(labels.FromStrings(model.AddressLabel, "example.com:80", model.JobLabel, "some_job"), &config.ScrapeConfig{}, nil, nil)
+
+	type healthChange struct {
+		previous, current TargetHealth
+	}
+	var changes []healthChange
+	target.setHealthChangeFunc(func(tgt *Target, previous, current TargetHealth) {
+		require.Same(t, target, tgt)
+		// The target's accessors must be usable from the function.
+		require.Equal(t, current, tgt.Health())
+		changes = append(changes, healthChange{previous: previous, current: current})
+	})
+
+	now := time.Now()
+	target.Report(now, 0, nil)
+	// A scrape with an unchanged outcome must not trigger the function.
+	target.Report(now, 0, nil)
+	target.Report(now, 0, errors.New("scrape failed"))
+
+	require.Equal(t, []healthChange{
+		{previous: HealthUnknown, current: HealthGood},
+		{previous: HealthGood, current: HealthBad},
+	}, changes)
+}
+
 func newTestTarget(targetURL string, _ time.Duration, lbls labels.Labels) *Target {
 	lb := labels.NewBuilder(lbls)
 	lb.Set(model.SchemeLabel, "http")
@@ -17,7 +17,7 @@ for pkg in ${INSTALL_PKGS}; do
 done
 popd
 
-DIRS="prompb"
+DIRS="prompb web/api/v2"
 
 echo "generating code"
 for dir in ${DIRS}; do
@@ -43,6 +43,20 @@ type genericSeriesSet interface {
 
 type genericSeriesMergeFunc func(...Labels) Labels
 
+// hintsAppliedReporter is the method set of HintsAppliedSeriesSet without the
+// SeriesSet part, so the flag can be forwarded through the generic adapters
+// that wrap both SeriesSet and ChunkSeriesSet.
+type hintsAppliedReporter interface {
+	HintsApplied() bool
+}
+
+// hintsAppliedFrom returns the hints-applied flag of set, or false if set does
+// not report one.
+func hintsAppliedFrom(set any) bool {
+	hs, ok := set.(hintsAppliedReporter)
+	return ok && hs.HintsApplied()
+}
+
 type genericSeriesSetAdapter struct {
 	SeriesSet
 }
@@ -51,6 +65,11 @@ func (a *genericSeriesSetAdapter) At() Labels {
 	return a.SeriesSet.At()
 }
 
+// HintsApplied forwards the flag from the wrapped series set.
+func (a *genericSeriesSetAdapter) HintsApplied() bool {
+	return hintsAppliedFrom(a.SeriesSet)
+}
+
 type genericChunkSeriesSetAdapter struct {
 	ChunkSeriesSet
 }
@@ -59,6 +78,11 @@ func (a *genericChunkSeriesSetAdapter) At() Labels {
 	return a.ChunkSeriesSet.At()
 }
 
+// HintsApplied forwards the flag from the wrapped chunk series set.
+func (a *genericChunkSeriesSetAdapter) HintsApplied() bool {
+	return hintsAppliedFrom(a.ChunkSeriesSet)
+}
+
 type genericQuerierAdapter struct {
 	LabelQuerier
 
@@ -94,6 +118,12 @@ func (a *seriesSetAdapter) At() Series {
 	return a.genericSeriesSet.At().(Series)
 }
 
+// HintsApplied implements HintsAppliedSeriesSet by forwarding the flag from
+// the wrapped generic series set.
+func (a *seriesSetAdapter) HintsApplied() bool {
+	return hintsAppliedFrom(a.genericSeriesSet)
+}
+
 func (q *querierAdapter) Select(ctx context.Context, sortSeries bool, hints *SelectHints, matchers ...*labels.Matcher) SeriesSet {
 	return &seriesSetAdapter{q.genericQuerier.Select(ctx, sortSeries, hints, matchers...)}
 }
@@ -110,6 +140,11 @@ func (a *chunkSeriesSetAdapter) At() ChunkSeries {
 	return a.genericSeriesSet.At().(ChunkSeries)
 }
 
+// HintsApplied forwards the flag from the wrapped generic series set.
+func (a *chunkSeriesSetAdapter) HintsApplied() bool {
+	return hintsAppliedFrom(a.genericSeriesSet)
+}
+
 func (q *chunkQuerierAdapter) Select(ctx context.Context, sortSeries bool, hints *SelectHints, matchers ...*labels.Matcher) ChunkSeriesSet {
 	return &chunkSeriesSetAdapter{q.genericQuerier.Select(ctx, sortSeries, hints, matchers...)}
 }
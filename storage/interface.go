@@ -541,6 +541,23 @@ type SeriesSet interface {
 	Warnings() annotations.Annotations
 }
 
+// HintsAppliedSeriesSet is a SeriesSet that can report whether the storage
+// already applied the function from the SelectHints to the returned data,
+// e.g. a remote read backend with pushdown support. When HintsApplied
+// returns true, the samples are final results of the hinted function and
+// callers must not re-compute it. The contract covers the function only:
+// implementations must not collapse series by the hinted grouping, as
+// re-applying an aggregation on the client is not idempotent for all
+// operations (e.g. count, avg, stddev). Merge layers keep the flag only
+// when every wrapped set that contributed series reports it, and drop it as
+// soon as raw series from another source are merged in. The value is only
+// guaranteed to be final once the set has been fully iterated, as streamed
+// implementations learn it from the response.
+type HintsAppliedSeriesSet interface {
+	SeriesSet
+	HintsApplied() bool
+}
+
 var emptySeriesSet = errSeriesSet{}
 
 // EmptySeriesSet returns a series set that's always empty.
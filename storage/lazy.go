@@ -54,6 +54,12 @@ func (c *lazyGenericSeriesSet) Warnings() annotations.Annotations {
 	return nil
 }
 
+// HintsApplied forwards the flag from the initialised set. Before the first
+// Next call nothing has been read, so the flag is false.
+func (c *lazyGenericSeriesSet) HintsApplied() bool {
+	return c.set != nil && hintsAppliedFrom(c.set)
+}
+
 type warningsOnlySeriesSet annotations.Annotations
 
 func (warningsOnlySeriesSet) Next() bool                          { return false }
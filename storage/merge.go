@@ -322,6 +322,7 @@ type genericMergeSeriesSet struct {
 	heap         genericSeriesSetHeap
 	sets         []genericSeriesSet
 	currentSets  []genericSeriesSet
+	contributing []genericSeriesSet // Sets that yielded at least one series.
 	seriesLimit  int
 	mergedSeries int // tracks the total number of series merged and returned.
 }
@@ -339,23 +340,28 @@ func newGenericMergeSeriesSet(sets []genericSeriesSet, seriesLimit int, mergeFun
 
 	// We are pre-advancing sets, so we can introspect the label of the
 	// series under the cursor.
-	var h genericSeriesSetHeap
+	var (
+		h            genericSeriesSetHeap
+		contributing []genericSeriesSet
+	)
 	for _, set := range sets {
 		if set == nil {
 			continue
 		}
 		if set.Next() {
 			heap.Push(&h, set)
+			contributing = append(contributing, set)
 		}
 		if err := set.Err(); err != nil {
 			return errorOnlySeriesSet{err}
 		}
 	}
 	return &genericMergeSeriesSet{
-		mergeFunc:   mergeFunc,
-		sets:        sets,
-		heap:        h,
-		seriesLimit: seriesLimit,
+		mergeFunc:    mergeFunc,
+		sets:         sets,
+		heap:         h,
+		contributing: contributing,
+		seriesLimit:  seriesLimit,
 	}
 }
 
@@ -410,6 +416,24 @@ func (c *genericMergeSeriesSet) At() Labels {
 	return c.mergeFunc(series...)
 }
 
+// HintsApplied reports whether the storages behind the merged sets already
+// applied the select hints. The flag is kept only when every set that yielded
+// at least one series reports it; sets without series are neutral, so an
+// empty local storage does not mask pushdown from a remote backend. As soon
+// as raw series from any source are merged in, the flag is dropped. Like the
+// wrapped sets, the value is only final once the set has been fully iterated.
+func (c *genericMergeSeriesSet) HintsApplied() bool {
+	if len(c.contributing) == 0 {
+		return false
+	}
+	for _, set := range c.contributing {
+		if !hintsAppliedFrom(set) {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *genericMergeSeriesSet) Err() error {
 	for _, set := range c.sets {
 		if err := set.Err(); err != nil {
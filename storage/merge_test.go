@@ -215,6 +215,67 @@ func TestMergeQuerierWithChainMerger(t *testing.T) {
 	}
 }
 
+// hintsAppliedMockSeriesSet wraps a SeriesSet and reports that the storage
+// already applied the select hints, like a remote read backend with pushdown
+// support.
+type hintsAppliedMockSeriesSet struct {
+	SeriesSet
+}
+
+func (hintsAppliedMockSeriesSet) HintsApplied() bool { return true }
+
+func TestMergeSeriesSetHintsApplied(t *testing.T) {
+	appliedSet := func(series ...Series) SeriesSet {
+		return hintsAppliedMockSeriesSet{NewMockSeriesSet(series...)}
+	}
+	seriesA := NewListSeries(labels.FromStrings("bar", "baz"), []chunks.Sample{fSample{0, 1, 1}})
+	seriesB := NewListSeries(labels.FromStrings("foo", "bar"), []chunks.Sample{fSample{0, 2, 2}})
+
+	for _, tc := range []struct {
+		name     string
+		input    []SeriesSet
+		expected bool
+	}{
+		{
+			name:     "all sets applied the hints",
+			input:    []SeriesSet{appliedSet(seriesA), appliedSet(seriesB)},
+			expected: true,
+		},
+		{
+			name:     "raw series merged in",
+			input:    []SeriesSet{appliedSet(seriesA), NewMockSeriesSet(seriesB)},
+			expected: false,
+		},
+		{
+			name:     "empty raw sets are neutral",
+			input:    []SeriesSet{NewMockSeriesSet(), appliedSet(seriesA), EmptySeriesSet()},
+			expected: true,
+		},
+		{
+			name:     "no series at all",
+			input:    []SeriesSet{NewMockSeriesSet(), appliedSet()},
+			expected: false,
+		},
+		{
+			name:     "raw series only",
+			input:    []SeriesSet{NewMockSeriesSet(seriesA), NewMockSeriesSet(seriesB)},
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			merged := NewMergeSeriesSet(tc.input, 0, ChainedSeriesMerge)
+			// The flag is only final once the set has been fully iterated.
+			for merged.Next() {
+			}
+			require.NoError(t, merged.Err())
+
+			hs, ok := merged.(HintsAppliedSeriesSet)
+			require.True(t, ok, "Expected merged series set to implement HintsAppliedSeriesSet")
+			require.Equal(t, tc.expected, hs.HintsApplied())
+		})
+	}
+}
+
 func TestMergeChunkQuerierWithNoVerticalChunkSeriesMerger(t *testing.T) {
 	for _, tc := range []struct {
 		name                    string
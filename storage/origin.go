@@ -0,0 +1,59 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/prometheus/tsdb/chunks"
+)
+
+// SeriesOriginProvider is implemented by Series from storages that track
+// sample provenance. Deduplication logic merging data from a
+// highly-available pair can type-assert series to it and prefer
+// primary-origin data after a failover.
+type SeriesOriginProvider interface {
+	// SampleOrigin returns the provenance recorded for the series' most
+	// recent data, or chunks.OriginUnknown when none was recorded.
+	SampleOrigin() chunks.SampleOrigin
+}
+
+// ParseSampleOrigin parses the string form of a sample origin, as used in
+// the X-Prometheus-Sample-Origin remote write header.
+func ParseSampleOrigin(s string) (chunks.SampleOrigin, error) {
+	switch s {
+	case "primary":
+		return chunks.OriginPrimary, nil
+	case "replicated":
+		return chunks.OriginReplicated, nil
+	default:
+		return chunks.OriginUnknown, fmt.Errorf("invalid sample origin %q", s)
+	}
+}
+
+type sampleOriginKey struct{}
+
+// ContextWithSampleOrigin returns a context that marks all samples appended
+// through appenders created from it with the given origin.
+func ContextWithSampleOrigin(ctx context.Context, origin chunks.SampleOrigin) context.Context {
+	return context.WithValue(ctx, sampleOriginKey{}, origin)
+}
+
+// SampleOriginFromContext returns the origin set by ContextWithSampleOrigin,
+// or chunks.OriginUnknown if none was set.
+func SampleOriginFromContext(ctx context.Context) chunks.SampleOrigin {
+	o, _ := ctx.Value(sampleOriginKey{}).(chunks.SampleOrigin)
+	return o
+}
@@ -0,0 +1,145 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"time"
+
+	remoteapi "github.com/prometheus/client_golang/exp/api/remote"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/promslog"
+
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"github.com/prometheus/prometheus/util/compression"
+)
+
+// checkMetricName is the series name of the synthetic sample sent by
+// endpoint checks.
+const checkMetricName = "prometheus_remote_write_check"
+
+// CheckResult reports the outcome of a synthetic write sent to a single
+// remote write endpoint.
+type CheckResult struct {
+	// Name is the name of the remote write config, or a hash prefix if the
+	// config is unnamed, matching the name used for queue metrics.
+	Name string `json:"name"`
+	// URL is the redacted endpoint URL.
+	URL string `json:"url"`
+	// Success indicates that the endpoint accepted the synthetic write.
+	Success bool `json:"success"`
+	// Error describes why the synthetic write failed, if it did.
+	Error string `json:"error,omitempty"`
+}
+
+// CheckEndpoints sends a small synthetic write to every remote write endpoint
+// in conf and reports per-endpoint results. It exercises the full client
+// stack, so TLS handshake, authentication and protocol negotiation failures
+// surface immediately instead of via queue lag. Each check writes a single
+// sample of the series prometheus_remote_write_check to the endpoint.
+func CheckEndpoints(ctx context.Context, conf *config.Config) []CheckResult {
+	results := make([]CheckResult, 0, len(conf.RemoteWriteConfigs))
+	for _, rwConf := range conf.RemoteWriteConfigs {
+		name, err := configName(rwConf)
+		res := CheckResult{Name: name, URL: endpointString(rwConf), Success: true}
+		if err == nil {
+			err = checkEndpoint(ctx, rwConf, name)
+		}
+		if err != nil {
+			res.Success = false
+			res.Error = err.Error()
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// configName returns the name used for the given remote write config in queue
+// metrics: the configured name, or a prefix of the config hash if unnamed.
+func configName(rwConf *config.RemoteWriteConfig) (string, error) {
+	if rwConf.Name != "" {
+		return rwConf.Name, nil
+	}
+	hash, err := toHash(rwConf)
+	if err != nil {
+		return "", err
+	}
+	return hash[:6], nil
+}
+
+// endpointString returns the redacted endpoint representation of the config,
+// matching the endpoint label of the queue metrics.
+func endpointString(rwConf *config.RemoteWriteConfig) string {
+	if rwConf.KafkaConfig != nil {
+		return rwConf.KafkaConfig.Endpoint()
+	}
+	return rwConf.URL.Redacted()
+}
+
+// checkEndpoint builds a write client for the given config and sends the
+// synthetic write request.
+func checkEndpoint(ctx context.Context, rwConf *config.RemoteWriteConfig, name string) error {
+	var (
+		c   WriteClient
+		err error
+	)
+	if rwConf.KafkaConfig != nil {
+		c, err = NewKafkaWriteClient(name, rwConf.KafkaConfig, time.Duration(rwConf.RemoteTimeout))
+	} else {
+		c, err = NewWriteClient(name, &ClientConfig{
+			URL:              rwConf.URL,
+			WriteProtoMsg:    rwConf.ProtobufMessage,
+			Timeout:          rwConf.RemoteTimeout,
+			HTTPClientConfig: rwConf.HTTPClientConfig,
+			SigV4Config:      rwConf.SigV4Config,
+			AzureADConfig:    rwConf.AzureADConfig,
+			GoogleIAMConfig:  rwConf.GoogleIAMConfig,
+			Headers:          rwConf.Headers,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	req, err := buildCheckRequest(rwConf.ProtobufMessage)
+	if err != nil {
+		return err
+	}
+	_, err = c.Store(ctx, req, 0)
+	return err
+}
+
+// buildCheckRequest returns a compressed write request containing a single
+// sample of the synthetic check series, in the configured protobuf message
+// version.
+func buildCheckRequest(msg remoteapi.WriteMessageType) ([]byte, error) {
+	ts := time.Now().UnixMilli()
+	if msg == remoteapi.WriteV2MessageType {
+		symbols := writev2.NewSymbolTable()
+		series := []writev2.TimeSeries{{
+			LabelsRefs: symbols.SymbolizeLabels(labels.FromStrings(model.MetricNameLabel, checkMetricName), nil),
+			Samples:    []writev2.Sample{{Timestamp: ts, Value: 1}},
+		}}
+		compressed, _, _, err := buildV2WriteRequest(promslog.NewNopLogger(), series, symbols.Symbols(), nil, nil, nil, compression.Snappy)
+		return compressed, err
+	}
+	series := []prompb.TimeSeries{{
+		Labels:  []prompb.Label{{Name: model.MetricNameLabel, Value: checkMetricName}},
+		Samples: []prompb.Sample{{Timestamp: ts, Value: 1}},
+	}}
+	compressed, _, _, err := buildWriteRequest(promslog.NewNopLogger(), series, nil, nil, nil, nil, compression.Snappy)
+	return compressed, err
+}
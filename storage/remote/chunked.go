@@ -42,6 +42,11 @@ type ChunkedWriter struct {
 	flusher http.Flusher
 
 	crc32 hash.Hash32
+
+	// flushThreshold is the number of unflushed bytes above which Write
+	// invokes the flusher itself. 0 means flushing happens only on Close.
+	flushThreshold int
+	unflushed      int
 }
 
 // NewChunkedWriter constructs a ChunkedWriter. After using the ChunkWriter,
@@ -50,13 +55,23 @@ func NewChunkedWriter(w io.Writer, f http.Flusher) *ChunkedWriter {
 	return &ChunkedWriter{writer: w, flusher: f, crc32: crc32.New(castagnoliTable)}
 }
 
+// NewChunkedWriterWithFlushThreshold constructs a ChunkedWriter that flushes
+// on its own whenever more than threshold bytes have been written since the
+// last flush. This bounds how much response data can pile up in the server's
+// buffers for a slow client. After using the ChunkWriter, Close() needs to be
+// called.
+func NewChunkedWriterWithFlushThreshold(w io.Writer, f http.Flusher, threshold int) *ChunkedWriter {
+	return &ChunkedWriter{writer: w, flusher: f, crc32: crc32.New(castagnoliTable), flushThreshold: threshold}
+}
+
 // Close ensures that all data ends up on the wire.
 func (w *ChunkedWriter) Close() {
 	w.flusher.Flush()
 }
 
-// Write writes given bytes to the stream. The underlying flusher is invoked
-// only on Close, so callers control batching by choosing when to Close.
+// Write writes given bytes to the stream. Unless a flush threshold is
+// configured, the underlying flusher is invoked only on Close, so callers
+// control batching by choosing when to Close.
 // Each frame includes:
 //
 // 1. uvarint for the size of the data frame.
@@ -84,7 +99,16 @@ func (w *ChunkedWriter) Write(b []byte) (int, error) {
 		return 0, err
 	}
 
-	return w.writer.Write(b)
+	n, err := w.writer.Write(b)
+	if err != nil {
+		return n, err
+	}
+	w.unflushed += v + 4 + n
+	if w.flushThreshold > 0 && w.unflushed >= w.flushThreshold {
+		w.flusher.Flush()
+		w.unflushed = 0
+	}
+	return n, nil
 }
 
 // ChunkedReader is a buffered reader that expects uvarint delimiter and checksum before each message.
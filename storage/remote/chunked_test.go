@@ -74,6 +74,25 @@ func TestChunkedReaderCanReadFromChunkedWriter(t *testing.T) {
 	require.Equal(t, 1, f.flushed)
 }
 
+func TestChunkedWriter_FlushThreshold(t *testing.T) {
+	b := &bytes.Buffer{}
+	f := &mockedFlusher{}
+	// Each written frame is 1 varint length byte + 4 checksum bytes + 12 payload bytes.
+	w := NewChunkedWriterWithFlushThreshold(b, f, 30)
+
+	for i := 0; i < 4; i++ {
+		_, err := w.Write([]byte("twelve bytes"))
+		require.NoError(t, err)
+	}
+
+	// Two frames (34 bytes) cross the threshold, so two flushes happened
+	// mid-stream for four frames.
+	require.Equal(t, 2, f.flushed)
+
+	w.Close()
+	require.Equal(t, 3, f.flushed)
+}
+
 func TestChunkedReader_Overflow(t *testing.T) {
 	b := &bytes.Buffer{}
 	_, err := NewChunkedWriter(b, &mockedFlusher{}).Write([]byte("twelve bytes"))
@@ -43,6 +43,7 @@ import (
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/storage/remote/azuread"
 	"github.com/prometheus/prometheus/storage/remote/googleiam"
+	"github.com/prometheus/prometheus/util/compression"
 )
 
 const (
@@ -121,20 +122,23 @@ type Client struct {
 	readQueriesDuration prometheus.ObserverVec
 
 	writeProtoMsg    remoteapi.WriteMessageType
-	writeCompression remoteapi.Compression
+	writeCompression compression.Type
 }
 
 // ClientConfig configures a client.
 type ClientConfig struct {
-	URL                   *config_util.URL
-	Timeout               model.Duration
-	HTTPClientConfig      config_util.HTTPClientConfig
-	SigV4Config           *sigv4.SigV4Config
-	AzureADConfig         *azuread.AzureADConfig
-	GoogleIAMConfig       *googleiam.Config
-	Headers               map[string]string
-	RetryOnRateLimit      bool
-	WriteProtoMsg         remoteapi.WriteMessageType
+	URL              *config_util.URL
+	Timeout          model.Duration
+	HTTPClientConfig config_util.HTTPClientConfig
+	SigV4Config      *sigv4.SigV4Config
+	AzureADConfig    *azuread.AzureADConfig
+	GoogleIAMConfig  *googleiam.Config
+	Headers          map[string]string
+	RetryOnRateLimit bool
+	WriteProtoMsg    remoteapi.WriteMessageType
+	// Compression is the content encoding advertised and used for write
+	// requests. Defaults to snappy when empty.
+	Compression           compression.Type
 	ChunkedReadLimit      uint64
 	RoundRobinDNS         bool
 	AcceptedResponseTypes []prompb.ReadRequest_ResponseType
@@ -220,6 +224,10 @@ func NewWriteClient(name string, conf *ClientConfig) (WriteClient, error) {
 	if conf.WriteProtoMsg != "" {
 		writeProtoMsg = conf.WriteProtoMsg
 	}
+	writeCompression := compression.Snappy
+	if conf.Compression != "" {
+		writeCompression = conf.Compression
+	}
 	httpClient.Transport = otelhttp.NewTransport(
 		t,
 		otelhttp.WithClientTrace(func(ctx context.Context) *httptrace.ClientTrace {
@@ -233,7 +241,7 @@ func NewWriteClient(name string, conf *ClientConfig) (WriteClient, error) {
 		retryOnRateLimit: conf.RetryOnRateLimit,
 		timeout:          time.Duration(conf.Timeout),
 		writeProtoMsg:    writeProtoMsg,
-		writeCompression: remoteapi.SnappyBlockCompression,
+		writeCompression: writeCompression,
 	}, nil
 }
 
@@ -260,9 +268,26 @@ type RecoverableError struct {
 	retryAfter model.Duration
 }
 
+// ErrUnsupportedMediaType is returned by Store when the remote endpoint
+// responds with HTTP 415, i.e. it does not accept the content type or content
+// encoding of the request. The queue manager uses it to fall back to snappy
+// compression when the endpoint rejects the configured compression.
+var ErrUnsupportedMediaType = errors.New("remote endpoint does not accept the request media type or content encoding")
+
 // Store sends a batch of samples to the HTTP endpoint, the request is the proto marshalled
 // and encoded bytes from codec.go.
 func (c *Client) Store(ctx context.Context, req []byte, attempt int) (WriteResponseStats, error) {
+	return c.store(ctx, req, attempt, c.writeCompression)
+}
+
+// StoreCompressed implements CompressedWriteClient. The req payload must be
+// compressed with compr, which is advertised as the request content encoding
+// instead of the configured one.
+func (c *Client) StoreCompressed(ctx context.Context, req []byte, attempt int, compr compression.Type) (WriteResponseStats, error) {
+	return c.store(ctx, req, attempt, compr)
+}
+
+func (c *Client) store(ctx context.Context, req []byte, attempt int, compr compression.Type) (WriteResponseStats, error) {
 	httpReq, err := http.NewRequest(http.MethodPost, c.urlString, bytes.NewReader(req))
 	if err != nil {
 		// Errors from NewRequest are from unparsable URLs, so are not
@@ -270,7 +295,9 @@ func (c *Client) Store(ctx context.Context, req []byte, attempt int) (WriteRespo
 		return WriteResponseStats{}, err
 	}
 
-	httpReq.Header.Add("Content-Encoding", string(c.writeCompression))
+	if compr != compression.None {
+		httpReq.Header.Add("Content-Encoding", string(compr))
+	}
 	httpReq.Header.Set("Content-Type", remoteWriteContentTypeHeaders[c.writeProtoMsg])
 	httpReq.Header.Set("User-Agent", UserAgent)
 	if c.writeProtoMsg == remoteapi.WriteV1MessageType {
@@ -318,6 +345,9 @@ func (c *Client) Store(ctx context.Context, req []byte, attempt int) (WriteRespo
 	body, _ := io.ReadAll(io.LimitReader(httpResp.Body, maxErrMsgLen))
 	err = fmt.Errorf("server returned HTTP status %s: %s", httpResp.Status, body)
 
+	if httpResp.StatusCode == http.StatusUnsupportedMediaType {
+		return rs, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, err)
+	}
 	if httpResp.StatusCode/100 == 5 ||
 		(c.retryOnRateLimit && httpResp.StatusCode == http.StatusTooManyRequests) {
 		return rs, RecoverableError{err, retryAfterDuration(httpResp.Header.Get("Retry-After"))}
@@ -35,6 +35,7 @@ import (
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/util/compression"
 	"github.com/prometheus/prometheus/util/testutil"
 )
 
@@ -57,6 +58,10 @@ func TestStoreHTTPErrorHandling(t *testing.T) {
 			code: 404,
 			err:  errors.New("server returned HTTP status 404 Not Found: " + longErrMessage[:maxErrMsgLen]),
 		},
+		{
+			code: 415,
+			err:  fmt.Errorf("%w: %s", ErrUnsupportedMediaType, "server returned HTTP status 415 Unsupported Media Type: "+longErrMessage[:maxErrMsgLen]),
+		},
 		{
 			code: 500,
 			err:  RecoverableError{errors.New("server returned HTTP status 500 Internal Server Error: " + longErrMessage[:maxErrMsgLen]), defaultBackoff},
@@ -94,6 +99,50 @@ func TestStoreHTTPErrorHandling(t *testing.T) {
 	}
 }
 
+func TestStoreContentEncoding(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		compr          compression.Type
+		expectedHeader []string
+	}{
+		{name: "empty defaults to snappy", compr: "", expectedHeader: []string{"snappy"}},
+		{name: "zstd", compr: compression.Zstd, expectedHeader: []string{"zstd"}},
+		{name: "none omits the header", compr: compression.None, expectedHeader: nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotHeader []string
+			server := httptest.NewServer(
+				http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+					gotHeader = r.Header.Values("Content-Encoding")
+				}),
+			)
+			defer server.Close()
+
+			serverURL, err := url.Parse(server.URL)
+			require.NoError(t, err)
+
+			c, err := NewWriteClient("test", &ClientConfig{
+				URL:         &config_util.URL{URL: serverURL},
+				Timeout:     model.Duration(time.Second),
+				Compression: tc.compr,
+			})
+			require.NoError(t, err)
+
+			_, err = c.Store(context.Background(), []byte{}, 0)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedHeader, gotHeader)
+
+			// StoreCompressed advertises the encoding of the given payload
+			// instead of the configured one.
+			cc, ok := c.(CompressedWriteClient)
+			require.True(t, ok)
+			_, err = cc.StoreCompressed(context.Background(), []byte{}, 0, compression.Snappy)
+			require.NoError(t, err)
+			require.Equal(t, []string{"snappy"}, gotHeader)
+		})
+	}
+}
+
 func TestClientRetryAfter(t *testing.T) {
 	setupServer := func(statusCode int) *httptest.Server {
 		return httptest.NewServer(
@@ -199,7 +199,8 @@ func FromQueryResult(sortSeries bool, res *prompb.QueryResult) storage.SeriesSet
 		})
 	}
 	return &concreteSeriesSet{
-		series: series,
+		series:       series,
+		hintsApplied: res.HintsApplied,
 	}
 }
 
@@ -352,8 +353,9 @@ func (errSeriesSet) Warnings() annotations.Annotations { return nil }
 
 // concreteSeriesSet implements storage.SeriesSet.
 type concreteSeriesSet struct {
-	cur    int
-	series []storage.Series
+	cur          int
+	series       []storage.Series
+	hintsApplied bool
 }
 
 func (c *concreteSeriesSet) Next() bool {
@@ -371,6 +373,9 @@ func (*concreteSeriesSet) Err() error {
 
 func (*concreteSeriesSet) Warnings() annotations.Annotations { return nil }
 
+// HintsApplied implements storage.HintsAppliedSeriesSet.
+func (c *concreteSeriesSet) HintsApplied() bool { return c.hintsApplied }
+
 // concreteSeries implements storage.Series.
 type concreteSeries struct {
 	labels     labels.Labels
@@ -635,9 +640,10 @@ type chunkedSeriesSet struct {
 	mint, maxt    int64
 	cancel        func(error)
 
-	current   storage.Series
-	err       error
-	exhausted bool
+	current      storage.Series
+	err          error
+	exhausted    bool
+	hintsApplied bool
 }
 
 func NewChunkedSeriesSet(chunkedReader *ChunkedReader, respBody io.ReadCloser, mint, maxt int64, cancel func(error)) storage.SeriesSet {
@@ -675,6 +681,10 @@ func (s *chunkedSeriesSet) Next() bool {
 		return false
 	}
 
+	if res.HintsApplied {
+		s.hintsApplied = true
+	}
+
 	s.current = &chunkedSeries{
 		ChunkedSeries: prompb.ChunkedSeries{
 			Labels: res.ChunkedSeries[0].Labels,
@@ -699,6 +709,12 @@ func (*chunkedSeriesSet) Warnings() annotations.Annotations {
 	return nil
 }
 
+// HintsApplied implements storage.HintsAppliedSeriesSet. Its value is only
+// final once the stream has been fully consumed.
+func (s *chunkedSeriesSet) HintsApplied() bool {
+	return s.hintsApplied
+}
+
 type chunkedSeries struct {
 	prompb.ChunkedSeries
 	mint, maxt int64
@@ -691,6 +691,30 @@ func TestFromQueryResultWithDuplicates(t *testing.T) {
 	require.Equalf(t, "duplicate label with name: foo", errMessage, "Expected error to be from duplicate label, but got: %s", errMessage)
 }
 
+func TestFromQueryResultHintsApplied(t *testing.T) {
+	ts1 := prompb.TimeSeries{
+		Labels: []prompb.Label{
+			{Name: "__name__", Value: "foo"},
+		},
+		Samples: []prompb.Sample{
+			{Value: 0.1, Timestamp: 0},
+		},
+	}
+
+	for _, hintsApplied := range []bool{false, true} {
+		res := prompb.QueryResult{
+			Timeseries:   []*prompb.TimeSeries{&ts1},
+			HintsApplied: hintsApplied,
+		}
+
+		series := FromQueryResult(false, &res)
+
+		hs, ok := series.(storage.HintsAppliedSeriesSet)
+		require.True(t, ok, "Expected resulting series set to implement storage.HintsAppliedSeriesSet")
+		require.Equal(t, hintsApplied, hs.HintsApplied())
+	}
+}
+
 func TestNegotiateResponseType(t *testing.T) {
 	r, err := NegotiateResponseType([]prompb.ReadRequest_ResponseType{
 		prompb.ReadRequest_STREAMED_XOR_CHUNKS,
@@ -1104,6 +1128,47 @@ func TestChunkedSeriesSet(t *testing.T) {
 		require.NoError(t, ss.Err(), "Err() should not return an error if Next() is called again after it previously returned false")
 	})
 
+	t.Run("hints applied", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		flusher := &mockFlusher{}
+
+		w := NewChunkedWriter(buf, flusher)
+		wrappedReader := newOneShotCloser(buf)
+		r := NewChunkedReader(wrappedReader, config.DefaultChunkedReadLimit, nil)
+
+		chks := buildTestChunks(t)
+		l := []prompb.Label{
+			{Name: "foo", Value: "bar"},
+		}
+
+		for i, c := range chks {
+			cSeries := prompb.ChunkedSeries{Labels: l, Chunks: []prompb.Chunk{c}}
+			readResp := prompb.ChunkedReadResponse{
+				ChunkedSeries: []*prompb.ChunkedSeries{&cSeries},
+				QueryIndex:    int64(i),
+				HintsApplied:  true,
+			}
+
+			b, err := proto.Marshal(&readResp)
+			require.NoError(t, err)
+
+			_, err = w.Write(b)
+			require.NoError(t, err)
+		}
+
+		ss := NewChunkedSeriesSet(r, wrappedReader, 0, 14000, func(error) {})
+		hs, ok := ss.(storage.HintsAppliedSeriesSet)
+		require.True(t, ok, "Expected chunked series set to implement storage.HintsAppliedSeriesSet")
+		// The flag is learned from the response, so it is only final once the
+		// stream has been consumed.
+		require.False(t, hs.HintsApplied())
+
+		for ss.Next() {
+		}
+		require.NoError(t, ss.Err())
+		require.True(t, hs.HintsApplied())
+	})
+
 	t.Run("chunked reader error", func(t *testing.T) {
 		buf := &bytes.Buffer{}
 		flusher := &mockFlusher{}
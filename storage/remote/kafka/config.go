@@ -0,0 +1,85 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kafka implements a minimal produce-only Kafka client used as a
+// remote write transport. It speaks the subset of the Kafka protocol needed
+// to publish record batches (message format v2, Kafka 0.11 or later) and
+// deliberately avoids pulling a full client library into Prometheus.
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Acknowledgement levels accepted in Config.RequiredAcks, in increasing
+// order of delivery guarantee.
+const (
+	// AcksNone returns as soon as the request is written to the socket.
+	AcksNone = "none"
+	// AcksLeader waits for the partition leader to append the records.
+	AcksLeader = "leader"
+	// AcksAll waits for the full in-sync replica set to append the records.
+	AcksAll = "all"
+)
+
+// Config is the configuration for publishing remote write payloads to Kafka.
+type Config struct {
+	// Brokers is the list of bootstrap broker addresses (host:port).
+	Brokers []string `yaml:"brokers"`
+	// Topic is the topic remote write payloads are published to.
+	Topic string `yaml:"topic"`
+	// RequiredAcks selects the delivery guarantee: none, leader or all.
+	RequiredAcks string `yaml:"required_acks,omitempty"`
+	// DeadLetterTopic, when set, receives payloads whose delivery to Topic
+	// failed instead of blocking the queue on retries.
+	DeadLetterTopic string `yaml:"dead_letter_topic,omitempty"`
+	// ClientID is the client id reported to the brokers.
+	ClientID string `yaml:"client_id,omitempty"`
+}
+
+// Validate checks the config for missing or invalid fields.
+func (c *Config) Validate() error {
+	if len(c.Brokers) == 0 {
+		return errors.New("at least one kafka broker must be configured")
+	}
+	if c.Topic == "" {
+		return errors.New("kafka topic must not be empty")
+	}
+	switch c.RequiredAcks {
+	case "", AcksNone, AcksLeader, AcksAll:
+	default:
+		return fmt.Errorf("invalid required_acks %q, expected one of: none, leader, all", c.RequiredAcks)
+	}
+	return nil
+}
+
+// Endpoint returns a URL-like representation of the configured cluster and
+// topic, used for metric labels and log lines.
+func (c *Config) Endpoint() string {
+	return "kafka://" + strings.Join(c.Brokers, ",") + "/" + c.Topic
+}
+
+// requiredAcks translates the configured acknowledgement level into the wire
+// value of the produce request acks field. The default is AcksAll.
+func (c *Config) requiredAcks() int16 {
+	switch c.RequiredAcks {
+	case AcksNone:
+		return 0
+	case AcksLeader:
+		return 1
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+const defaultClientID = "prometheus"
+
+// topicMetadata holds the partition leaders of a topic, indexed by partition.
+type topicMetadata struct {
+	// leaders maps a partition id to the address of its leader broker.
+	leaders []string
+}
+
+// Producer publishes record batches to Kafka partitions. It maintains one
+// connection per broker and caches topic metadata until a produce error
+// invalidates it. Producer is not safe for concurrent use; callers must
+// serialize access.
+type Producer struct {
+	cfg      *Config
+	clientID string
+	timeout  time.Duration
+
+	correlation int32
+	conns       map[string]*brokerConn
+	topics      map[string]*topicMetadata
+}
+
+// brokerConn is a connection to a single broker.
+type brokerConn struct {
+	conn net.Conn
+}
+
+// NewProducer returns a producer for the given cluster. Connections are
+// established lazily on the first produce. The timeout bounds every network
+// operation as well as the replication timeout requested from the broker.
+func NewProducer(cfg *Config, timeout time.Duration) *Producer {
+	clientID := cfg.ClientID
+	if clientID == "" {
+		clientID = defaultClientID
+	}
+	return &Producer{
+		cfg:      cfg,
+		clientID: clientID,
+		timeout:  timeout,
+		conns:    map[string]*brokerConn{},
+		topics:   map[string]*topicMetadata{},
+	}
+}
+
+// NumPartitions returns the number of partitions of the given topic.
+func (p *Producer) NumPartitions(ctx context.Context, topic string) (int, error) {
+	md, err := p.metadata(ctx, topic)
+	if err != nil {
+		return 0, err
+	}
+	return len(md.leaders), nil
+}
+
+// Produce publishes a single record to the given topic partition and waits
+// for the acknowledgement level the producer was configured with. The key and
+// value are not retained.
+func (p *Producer) Produce(ctx context.Context, topic string, partition int, key, value []byte) error {
+	md, err := p.metadata(ctx, topic)
+	if err != nil {
+		return err
+	}
+	if partition < 0 || partition >= len(md.leaders) {
+		return fmt.Errorf("kafka: partition %d out of range for topic %q with %d partitions", partition, topic, len(md.leaders))
+	}
+	addr := md.leaders[partition]
+	if err := p.produce(ctx, addr, topic, partition, key, value); err != nil {
+		// The leader may have moved; refetch metadata on the next attempt.
+		delete(p.topics, topic)
+		p.dropConn(addr)
+		return err
+	}
+	return nil
+}
+
+// Close closes all broker connections.
+func (p *Producer) Close() error {
+	var firstErr error
+	for addr, bc := range p.conns {
+		if err := bc.conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(p.conns, addr)
+	}
+	return firstErr
+}
+
+func (p *Producer) produce(ctx context.Context, addr, topic string, partition int, key, value []byte) error {
+	bc, err := p.getConn(ctx, addr)
+	if err != nil {
+		return err
+	}
+
+	batch := newRecordBatch(time.Now().UnixMilli(), key, value)
+	acks := p.cfg.requiredAcks()
+
+	var body []byte
+	body = appendInt16(body, -1) // Null transactional id.
+	body = appendInt16(body, acks)
+	body = appendInt32(body, int32(p.timeout.Milliseconds()))
+	body = appendInt32(body, 1) // One topic.
+	body = appendString(body, topic)
+	body = appendInt32(body, 1) // One partition.
+	body = appendInt32(body, int32(partition))
+	body = appendInt32(body, int32(len(batch)))
+	body = append(body, batch...)
+
+	if err := p.send(ctx, bc, apiKeyProduce, produceVersion, body); err != nil {
+		return err
+	}
+	if acks == 0 {
+		// The broker sends no response for acks=none.
+		return nil
+	}
+	resp, err := p.receive(ctx, bc)
+	if err != nil {
+		return err
+	}
+	return parseProduceResponse(resp, topic, partition)
+}
+
+// metadata returns the cached partition leaders for topic, querying the
+// bootstrap brokers on a cache miss.
+func (p *Producer) metadata(ctx context.Context, topic string) (*topicMetadata, error) {
+	if md, ok := p.topics[topic]; ok {
+		return md, nil
+	}
+
+	var body []byte
+	body = appendInt32(body, 1) // One topic.
+	body = appendString(body, topic)
+
+	var lastErr error
+	for _, addr := range p.cfg.Brokers {
+		bc, err := p.getConn(ctx, addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if err := p.send(ctx, bc, apiKeyMetadata, metadataVersion, body); err != nil {
+			p.dropConn(addr)
+			lastErr = err
+			continue
+		}
+		resp, err := p.receive(ctx, bc)
+		if err != nil {
+			p.dropConn(addr)
+			lastErr = err
+			continue
+		}
+		md, err := parseMetadataResponse(resp, topic)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		p.topics[topic] = md
+		return md, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("kafka: no brokers configured")
+	}
+	return nil, fmt.Errorf("fetching metadata for topic %q: %w", topic, lastErr)
+}
+
+func (p *Producer) getConn(ctx context.Context, addr string) (*brokerConn, error) {
+	if bc, ok := p.conns[addr]; ok {
+		return bc, nil
+	}
+	d := net.Dialer{Timeout: p.timeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dialing kafka broker %s: %w", addr, err)
+	}
+	bc := &brokerConn{conn: conn}
+	p.conns[addr] = bc
+	return bc, nil
+}
+
+func (p *Producer) dropConn(addr string) {
+	if bc, ok := p.conns[addr]; ok {
+		bc.conn.Close()
+		delete(p.conns, addr)
+	}
+}
+
+// send frames and writes a single request.
+func (p *Producer) send(ctx context.Context, bc *brokerConn, apiKey, apiVersion int16, body []byte) error {
+	p.correlation++
+	header := requestHeader(apiKey, apiVersion, p.correlation, p.clientID)
+
+	var req []byte
+	req = appendInt32(req, int32(len(header)+len(body)))
+	req = append(req, header...)
+	req = append(req, body...)
+
+	if err := bc.conn.SetWriteDeadline(p.deadline(ctx)); err != nil {
+		return err
+	}
+	_, err := bc.conn.Write(req)
+	return err
+}
+
+// receive reads a single response and strips the correlation id.
+func (p *Producer) receive(ctx context.Context, bc *brokerConn) ([]byte, error) {
+	if err := bc.conn.SetReadDeadline(p.deadline(ctx)); err != nil {
+		return nil, err
+	}
+	var sizeBuf [4]byte
+	if _, err := io.ReadFull(bc.conn, sizeBuf[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(sizeBuf[:])
+	resp := make([]byte, size)
+	if _, err := io.ReadFull(bc.conn, resp); err != nil {
+		return nil, err
+	}
+	d := &decoder{b: resp}
+	if got := d.int32(); got != p.correlation {
+		return nil, fmt.Errorf("kafka: correlation id mismatch, got %d want %d", got, p.correlation)
+	}
+	return resp[d.off:], nil
+}
+
+// deadline derives the network deadline from the context and the configured
+// timeout, whichever is earlier.
+func (p *Producer) deadline(ctx context.Context) time.Time {
+	dl := time.Now().Add(p.timeout)
+	if ctxDl, ok := ctx.Deadline(); ok && ctxDl.Before(dl) {
+		dl = ctxDl
+	}
+	return dl
+}
+
+// parseMetadataResponse extracts the partition leaders of topic from a
+// metadata v0 response.
+func parseMetadataResponse(resp []byte, topic string) (*topicMetadata, error) {
+	d := &decoder{b: resp}
+
+	addrs := map[int32]string{}
+	for i, n := 0, int(d.int32()); i < n; i++ {
+		id := d.int32()
+		host := d.string()
+		port := d.int32()
+		addrs[id] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+
+	for i, n := 0, int(d.int32()); i < n; i++ {
+		topicErr := d.int16()
+		name := d.string()
+		numPartitions := int(d.int32())
+		leaders := make([]string, numPartitions)
+		for j := 0; j < numPartitions; j++ {
+			partErr := d.int16()
+			id := d.int32()
+			leader := d.int32()
+			d.skipInt32Array() // Replicas.
+			d.skipInt32Array() // In-sync replicas.
+			if partErr != 0 || int(id) >= numPartitions {
+				continue
+			}
+			leaders[id] = addrs[leader]
+		}
+		if name != topic {
+			continue
+		}
+		if err := d.err(); err != nil {
+			return nil, err
+		}
+		if topicErr != 0 {
+			return nil, fmt.Errorf("metadata for topic %q: %w", topic, brokerError(topicErr))
+		}
+		if numPartitions == 0 {
+			return nil, fmt.Errorf("kafka: topic %q has no partitions", topic)
+		}
+		for id, leader := range leaders {
+			if leader == "" {
+				return nil, fmt.Errorf("kafka: partition %d of topic %q has no leader", id, topic)
+			}
+		}
+		return &topicMetadata{leaders: leaders}, nil
+	}
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("kafka: topic %q not found in metadata response", topic)
+}
+
+// parseProduceResponse extracts the error code for the given topic partition
+// from a produce v3 response.
+func parseProduceResponse(resp []byte, topic string, partition int) error {
+	d := &decoder{b: resp}
+	for i, n := 0, int(d.int32()); i < n; i++ {
+		name := d.string()
+		for j, m := 0, int(d.int32()); j < m; j++ {
+			id := d.int32()
+			errCode := d.int16()
+			d.int64() // Base offset.
+			d.int64() // Log append time.
+			if name != topic || int(id) != partition {
+				continue
+			}
+			if err := d.err(); err != nil {
+				return err
+			}
+			if errCode != 0 {
+				return fmt.Errorf("producing to %s[%d]: %w", topic, partition, brokerError(errCode))
+			}
+			return nil
+		}
+	}
+	if err := d.err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("kafka: produce response misses partition %s[%d]", topic, partition)
+}
@@ -0,0 +1,312 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// producedRecord is a record received by the fake broker.
+type producedRecord struct {
+	topic     string
+	partition int32
+	acks      int16
+	key       []byte
+	value     []byte
+}
+
+// fakeBroker is a single-node in-process broker speaking the protocol subset
+// the producer uses. It reports itself as the leader of all partitions.
+type fakeBroker struct {
+	ln            net.Listener
+	numPartitions int32
+
+	mtx        sync.Mutex
+	produceErr int16
+	records    []producedRecord
+}
+
+func newFakeBroker(t *testing.T, numPartitions int32) *fakeBroker {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	b := &fakeBroker{ln: ln, numPartitions: numPartitions}
+	go b.serve()
+	t.Cleanup(func() { ln.Close() })
+	return b
+}
+
+func (b *fakeBroker) addr() string { return b.ln.Addr().String() }
+
+func (b *fakeBroker) setProduceErr(code int16) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.produceErr = code
+}
+
+func (b *fakeBroker) producedRecords() []producedRecord {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return append([]producedRecord(nil), b.records...)
+}
+
+func (b *fakeBroker) serve() {
+	for {
+		conn, err := b.ln.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(conn)
+	}
+}
+
+func (b *fakeBroker) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var sizeBuf [4]byte
+		if _, err := io.ReadFull(conn, sizeBuf[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint32(sizeBuf[:]))
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+
+		d := &decoder{b: req}
+		apiKey := d.int16()
+		d.int16() // API version.
+		correlation := d.int32()
+		d.string() // Client id.
+
+		var body []byte
+		switch apiKey {
+		case apiKeyMetadata:
+			d.int32() // Topic count, always 1.
+			body = b.metadataResponse(d.string())
+		case apiKeyProduce:
+			var acks int16
+			body, acks = b.produceResponse(d, req)
+			if acks == 0 {
+				// No response for acks=none.
+				continue
+			}
+		}
+
+		var resp []byte
+		resp = appendInt32(resp, int32(4+len(body)))
+		resp = appendInt32(resp, correlation)
+		resp = append(resp, body...)
+		if _, err := conn.Write(resp); err != nil {
+			return
+		}
+	}
+}
+
+func (b *fakeBroker) metadataResponse(topic string) []byte {
+	host, portStr, _ := net.SplitHostPort(b.addr())
+	port, _ := strconv.Atoi(portStr)
+
+	var body []byte
+	body = appendInt32(body, 1) // One broker.
+	body = appendInt32(body, 0) // Node id.
+	body = appendString(body, host)
+	body = appendInt32(body, int32(port))
+	body = appendInt32(body, 1) // One topic.
+	body = appendInt16(body, 0) // Topic error code.
+	body = appendString(body, topic)
+	body = appendInt32(body, b.numPartitions)
+	for p := int32(0); p < b.numPartitions; p++ {
+		body = appendInt16(body, 0) // Partition error code.
+		body = appendInt32(body, p)
+		body = appendInt32(body, 0) // Leader node id.
+		body = appendInt32(body, 0) // Empty replica list.
+		body = appendInt32(body, 0) // Empty ISR list.
+	}
+	return body
+}
+
+func (b *fakeBroker) produceResponse(d *decoder, req []byte) ([]byte, int16) {
+	d.int16() // Transactional id.
+	acks := d.int16()
+	d.int32() // Timeout.
+	d.int32() // Topic count, always 1.
+	topic := d.string()
+	d.int32() // Partition count, always 1.
+	partition := d.int32()
+	batchLen := int(d.int32())
+	key, value := decodeSingleRecordBatch(req[d.off : d.off+batchLen])
+
+	b.mtx.Lock()
+	b.records = append(b.records, producedRecord{topic: topic, partition: partition, acks: acks, key: key, value: value})
+	errCode := b.produceErr
+	b.mtx.Unlock()
+
+	var body []byte
+	body = appendInt32(body, 1) // One topic.
+	body = appendString(body, topic)
+	body = appendInt32(body, 1) // One partition.
+	body = appendInt32(body, partition)
+	body = appendInt16(body, errCode)
+	body = appendInt64(body, 0)  // Base offset.
+	body = appendInt64(body, -1) // Log append time.
+	body = appendInt32(body, 0)  // Throttle time.
+	return body, acks
+}
+
+// decodeSingleRecordBatch extracts the key and value of the only record in a
+// message format v2 batch.
+func decodeSingleRecordBatch(batch []byte) (key, value []byte) {
+	// Skip the batch header up to and including the record count.
+	rec := batch[61:]
+	_, n := binary.Varint(rec) // Record length.
+	rec = rec[n:]
+	rec = rec[1:]             // Attributes.
+	_, n = binary.Varint(rec) // Timestamp delta.
+	rec = rec[n:]
+	_, n = binary.Varint(rec) // Offset delta.
+	rec = rec[n:]
+	keyLen, n := binary.Varint(rec)
+	rec = rec[n:]
+	if keyLen >= 0 {
+		key = rec[:keyLen]
+		rec = rec[keyLen:]
+	}
+	valueLen, n := binary.Varint(rec)
+	rec = rec[n:]
+	value = rec[:valueLen]
+	return key, value
+}
+
+func TestProducerProduce(t *testing.T) {
+	t.Parallel()
+	broker := newFakeBroker(t, 3)
+	cfg := &Config{Brokers: []string{broker.addr()}, Topic: "metrics"}
+	p := NewProducer(cfg, time.Second)
+	defer p.Close()
+
+	ctx := context.Background()
+	n, err := p.NumPartitions(ctx, "metrics")
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	require.NoError(t, p.Produce(ctx, "metrics", 0, []byte("key-a"), []byte("payload-a")))
+	require.NoError(t, p.Produce(ctx, "metrics", 2, nil, []byte("payload-b")))
+
+	records := broker.producedRecords()
+	require.Len(t, records, 2)
+	require.Equal(t, producedRecord{topic: "metrics", partition: 0, acks: -1, key: []byte("key-a"), value: []byte("payload-a")}, records[0])
+	require.Equal(t, producedRecord{topic: "metrics", partition: 2, acks: -1, value: []byte("payload-b")}, records[1])
+}
+
+func TestProducerRequiredAcks(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		requiredAcks string
+		wantAcks     int16
+	}{
+		{requiredAcks: AcksAll, wantAcks: -1},
+		{requiredAcks: AcksLeader, wantAcks: 1},
+		{requiredAcks: AcksNone, wantAcks: 0},
+	} {
+		t.Run(tc.requiredAcks, func(t *testing.T) {
+			t.Parallel()
+			broker := newFakeBroker(t, 1)
+			cfg := &Config{Brokers: []string{broker.addr()}, Topic: "metrics", RequiredAcks: tc.requiredAcks}
+			p := NewProducer(cfg, time.Second)
+			defer p.Close()
+
+			require.NoError(t, p.Produce(context.Background(), "metrics", 0, nil, []byte("payload")))
+			require.Eventually(t, func() bool {
+				records := broker.producedRecords()
+				return len(records) == 1 && records[0].acks == tc.wantAcks
+			}, time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestProducerProduceError(t *testing.T) {
+	t.Parallel()
+	broker := newFakeBroker(t, 1)
+	cfg := &Config{Brokers: []string{broker.addr()}, Topic: "metrics"}
+	p := NewProducer(cfg, time.Second)
+	defer p.Close()
+
+	ctx := context.Background()
+	broker.setProduceErr(6) // NOT_LEADER_FOR_PARTITION.
+	err := p.Produce(ctx, "metrics", 0, nil, []byte("payload"))
+	require.ErrorIs(t, err, brokerError(6))
+
+	// The error invalidates the cached metadata; once the broker recovers
+	// the next produce succeeds again.
+	broker.setProduceErr(0)
+	require.NoError(t, p.Produce(ctx, "metrics", 0, nil, []byte("payload")))
+}
+
+func TestProducerPartitionOutOfRange(t *testing.T) {
+	t.Parallel()
+	broker := newFakeBroker(t, 2)
+	cfg := &Config{Brokers: []string{broker.addr()}, Topic: "metrics"}
+	p := NewProducer(cfg, time.Second)
+	defer p.Close()
+
+	require.Error(t, p.Produce(context.Background(), "metrics", 5, nil, []byte("payload")))
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name   string
+		cfg    Config
+		errMsg string
+	}{
+		{
+			name: "valid",
+			cfg:  Config{Brokers: []string{"localhost:9092"}, Topic: "metrics", RequiredAcks: AcksAll},
+		},
+		{
+			name:   "no brokers",
+			cfg:    Config{Topic: "metrics"},
+			errMsg: "at least one kafka broker",
+		},
+		{
+			name:   "no topic",
+			cfg:    Config{Brokers: []string{"localhost:9092"}},
+			errMsg: "topic must not be empty",
+		},
+		{
+			name:   "bad acks",
+			cfg:    Config{Brokers: []string{"localhost:9092"}, Topic: "metrics", RequiredAcks: "most"},
+			errMsg: "invalid required_acks",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.cfg.Validate()
+			if tc.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
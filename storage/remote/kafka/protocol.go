@@ -0,0 +1,185 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// Kafka protocol API keys and versions used by the producer.
+const (
+	apiKeyProduce  = 0
+	apiKeyMetadata = 3
+
+	// Produce v3 is the first version carrying message format v2 record
+	// batches, supported by Kafka 0.11 and all later releases.
+	produceVersion = 3
+	// Metadata v0 is sufficient: we only need partition leaders.
+	metadataVersion = 0
+)
+
+// castagnoli is the CRC32C polynomial table used by record batch checksums.
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// errShortResponse is returned when a broker response ends before all
+// expected fields were read.
+var errShortResponse = errors.New("kafka: short response from broker")
+
+func appendInt16(b []byte, v int16) []byte {
+	return binary.BigEndian.AppendUint16(b, uint16(v))
+}
+
+func appendInt32(b []byte, v int32) []byte {
+	return binary.BigEndian.AppendUint32(b, uint32(v))
+}
+
+func appendInt64(b []byte, v int64) []byte {
+	return binary.BigEndian.AppendUint64(b, uint64(v))
+}
+
+// appendString appends an int16-length-prefixed protocol string.
+func appendString(b []byte, s string) []byte {
+	b = appendInt16(b, int16(len(s)))
+	return append(b, s...)
+}
+
+// requestHeader builds the v1 request header shared by all requests.
+func requestHeader(apiKey, apiVersion int16, correlationID int32, clientID string) []byte {
+	var b []byte
+	b = appendInt16(b, apiKey)
+	b = appendInt16(b, apiVersion)
+	b = appendInt32(b, correlationID)
+	return appendString(b, clientID)
+}
+
+// newRecordBatch encodes a single key/value record as a message format v2
+// record batch with the given timestamp in milliseconds.
+func newRecordBatch(timestampMs int64, key, value []byte) []byte {
+	// Record fields after the length varint.
+	var rec []byte
+	rec = append(rec, 0)              // Attributes.
+	rec = binary.AppendVarint(rec, 0) // Timestamp delta.
+	rec = binary.AppendVarint(rec, 0) // Offset delta.
+	if key == nil {
+		rec = binary.AppendVarint(rec, -1)
+	} else {
+		rec = binary.AppendVarint(rec, int64(len(key)))
+		rec = append(rec, key...)
+	}
+	rec = binary.AppendVarint(rec, int64(len(value)))
+	rec = append(rec, value...)
+	rec = binary.AppendVarint(rec, 0) // Header count.
+
+	// Batch fields covered by the CRC.
+	var crcPart []byte
+	crcPart = appendInt16(crcPart, 0)           // Attributes: no compression.
+	crcPart = appendInt32(crcPart, 0)           // Last offset delta.
+	crcPart = appendInt64(crcPart, timestampMs) // Base timestamp.
+	crcPart = appendInt64(crcPart, timestampMs) // Max timestamp.
+	crcPart = appendInt64(crcPart, -1)          // Producer id (idempotence unused).
+	crcPart = appendInt16(crcPart, -1)          // Producer epoch.
+	crcPart = appendInt32(crcPart, -1)          // Base sequence.
+	crcPart = appendInt32(crcPart, 1)           // Record count.
+	crcPart = binary.AppendVarint(crcPart, int64(len(rec)))
+	crcPart = append(crcPart, rec...)
+
+	var batch []byte
+	batch = appendInt64(batch, 0) // Base offset, assigned by the broker.
+	// Batch length covers everything after this field.
+	batch = appendInt32(batch, int32(4+1+4+len(crcPart)))
+	batch = appendInt32(batch, -1) // Partition leader epoch.
+	batch = append(batch, 2)       // Magic: message format v2.
+	batch = appendInt32(batch, int32(crc32.Checksum(crcPart, castagnoli)))
+	return append(batch, crcPart...)
+}
+
+// decoder reads big-endian protocol fields from a response payload. Reads
+// past the end set the short flag instead of panicking; callers check err()
+// once after decoding.
+type decoder struct {
+	b     []byte
+	off   int
+	short bool
+}
+
+func (d *decoder) err() error {
+	if d.short {
+		return errShortResponse
+	}
+	return nil
+}
+
+func (d *decoder) int16() int16 {
+	if d.off+2 > len(d.b) {
+		d.short = true
+		return 0
+	}
+	v := int16(binary.BigEndian.Uint16(d.b[d.off:]))
+	d.off += 2
+	return v
+}
+
+func (d *decoder) int32() int32 {
+	if d.off+4 > len(d.b) {
+		d.short = true
+		return 0
+	}
+	v := int32(binary.BigEndian.Uint32(d.b[d.off:]))
+	d.off += 4
+	return v
+}
+
+func (d *decoder) int64() int64 {
+	if d.off+8 > len(d.b) {
+		d.short = true
+		return 0
+	}
+	v := int64(binary.BigEndian.Uint64(d.b[d.off:]))
+	d.off += 8
+	return v
+}
+
+func (d *decoder) string() string {
+	n := int(d.int16())
+	if n < 0 {
+		return ""
+	}
+	if d.off+n > len(d.b) {
+		d.short = true
+		return ""
+	}
+	s := string(d.b[d.off : d.off+n])
+	d.off += n
+	return s
+}
+
+// skipInt32Array skips an array of int32 values, e.g. replica or ISR lists.
+func (d *decoder) skipInt32Array() {
+	n := int(d.int32())
+	if d.off+4*n > len(d.b) {
+		d.short = true
+		return
+	}
+	d.off += 4 * n
+}
+
+// brokerError represents a Kafka error code returned in a response.
+type brokerError int16
+
+func (e brokerError) Error() string {
+	return fmt.Sprintf("kafka: broker returned error code %d", int16(e))
+}
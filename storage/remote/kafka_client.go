@@ -0,0 +1,138 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/storage/remote/kafka"
+)
+
+// kafkaProducer is the subset of kafka.Producer the client uses, extracted so
+// tests can substitute an in-memory implementation.
+type kafkaProducer interface {
+	NumPartitions(ctx context.Context, topic string) (int, error)
+	Produce(ctx context.Context, topic string, partition int, key, value []byte) error
+	Close() error
+}
+
+// kafkaClient publishes remote write payloads to a Kafka topic instead of
+// sending them to an HTTP receiver. Series are assigned to partitions by
+// their label hash, so all samples of a series land on the same partition
+// and consumers see them in order.
+type kafkaClient struct {
+	remoteName string
+	cfg        *kafka.Config
+
+	// mtx serializes sends from concurrent queue shards, as the producer
+	// multiplexes all partitions over one connection per broker.
+	mtx      sync.Mutex
+	producer kafkaProducer
+	builder  labels.ScratchBuilder
+}
+
+// NewKafkaWriteClient creates a new client publishing remote write payloads
+// to Kafka. Each message value is a snappy-compressed prometheus.WriteRequest
+// holding the series of a single partition, i.e. the same payload an HTTP
+// receiver would get.
+func NewKafkaWriteClient(name string, cfg *kafka.Config, timeout time.Duration) (WriteClient, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &kafkaClient{
+		remoteName: name,
+		cfg:        cfg,
+		producer:   kafka.NewProducer(cfg, timeout),
+		builder:    labels.NewScratchBuilder(0),
+	}, nil
+}
+
+// Store implements the WriteClient interface.
+func (c *kafkaClient) Store(ctx context.Context, req []byte, _ int) (WriteResponseStats, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	stats := WriteResponseStats{Confirmed: true}
+
+	raw, err := snappy.Decode(nil, req)
+	if err != nil {
+		return stats, err
+	}
+	var wr prompb.WriteRequest
+	if err := proto.Unmarshal(raw, &wr); err != nil {
+		return stats, err
+	}
+
+	numPartitions, err := c.producer.NumPartitions(ctx, c.cfg.Topic)
+	if err != nil {
+		// The cluster may be temporarily unreachable; let the queue retry.
+		return stats, RecoverableError{err, defaultBackoff}
+	}
+
+	// Group the series of the request by the partition their label hash
+	// assigns them to.
+	groups := make(map[int][]prompb.TimeSeries)
+	for _, ts := range wr.Timeseries {
+		partition := int(ts.ToLabels(&c.builder, nil).Hash() % uint64(numPartitions))
+		groups[partition] = append(groups[partition], ts)
+	}
+
+	for partition, group := range groups {
+		data, err := proto.Marshal(&prompb.WriteRequest{Timeseries: group})
+		if err != nil {
+			return stats, err
+		}
+		payload := snappy.Encode(nil, data)
+
+		if err := c.producer.Produce(ctx, c.cfg.Topic, partition, nil, payload); err != nil {
+			if dlqErr := c.deadLetter(ctx, partition, payload); dlqErr != nil {
+				return stats, RecoverableError{fmt.Errorf("producing to topic %q: %w", c.cfg.Topic, err), defaultBackoff}
+			}
+		}
+		for _, ts := range group {
+			stats.Samples += len(ts.Samples)
+			stats.Histograms += len(ts.Histograms)
+			stats.Exemplars += len(ts.Exemplars)
+		}
+	}
+	return stats, nil
+}
+
+// deadLetter publishes a payload that could not be delivered to the
+// dead-letter topic, if one is configured. A successful dead-letter publish
+// counts as delivery; otherwise the original payload is retried.
+func (c *kafkaClient) deadLetter(ctx context.Context, partition int, payload []byte) error {
+	if c.cfg.DeadLetterTopic == "" {
+		return fmt.Errorf("no dead-letter topic configured")
+	}
+	numPartitions, err := c.producer.NumPartitions(ctx, c.cfg.DeadLetterTopic)
+	if err != nil {
+		return err
+	}
+	return c.producer.Produce(ctx, c.cfg.DeadLetterTopic, partition%numPartitions, nil, payload)
+}
+
+// Name implements the WriteClient interface.
+func (c *kafkaClient) Name() string { return c.remoteName }
+
+// Endpoint implements the WriteClient interface.
+func (c *kafkaClient) Endpoint() string { return c.cfg.Endpoint() }
@@ -0,0 +1,177 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/storage/remote/kafka"
+)
+
+// fakeKafkaMessage is a message recorded by fakeKafkaProducer.
+type fakeKafkaMessage struct {
+	topic     string
+	partition int
+	value     []byte
+}
+
+// fakeKafkaProducer is an in-memory kafkaProducer for testing the client
+// without a broker.
+type fakeKafkaProducer struct {
+	partitions map[string]int
+	produceErr map[string]error
+
+	messages []fakeKafkaMessage
+}
+
+func (f *fakeKafkaProducer) NumPartitions(_ context.Context, topic string) (int, error) {
+	n, ok := f.partitions[topic]
+	if !ok {
+		return 0, fmt.Errorf("unknown topic %q", topic)
+	}
+	return n, nil
+}
+
+func (f *fakeKafkaProducer) Produce(_ context.Context, topic string, partition int, _, value []byte) error {
+	if err := f.produceErr[topic]; err != nil {
+		return err
+	}
+	f.messages = append(f.messages, fakeKafkaMessage{topic: topic, partition: partition, value: value})
+	return nil
+}
+
+func (*fakeKafkaProducer) Close() error { return nil }
+
+// encodeTestWriteRequest builds the compressed payload the queue manager
+// would hand to the client for the given series.
+func encodeTestWriteRequest(t *testing.T, series []prompb.TimeSeries) []byte {
+	t.Helper()
+	data, err := proto.Marshal(&prompb.WriteRequest{Timeseries: series})
+	require.NoError(t, err)
+	return snappy.Encode(nil, data)
+}
+
+func testKafkaSeries(i int) prompb.TimeSeries {
+	return prompb.TimeSeries{
+		Labels: []prompb.Label{
+			{Name: "__name__", Value: "http_requests_total"},
+			{Name: "instance", Value: fmt.Sprintf("host-%d:9090", i)},
+			{Name: "job", Value: "api"},
+		},
+		Samples: []prompb.Sample{{Timestamp: int64(i) * 1000, Value: float64(i)}},
+	}
+}
+
+func TestKafkaClientStorePartitioning(t *testing.T) {
+	t.Parallel()
+	const numPartitions = 4
+
+	producer := &fakeKafkaProducer{partitions: map[string]int{"metrics": numPartitions}}
+	c := &kafkaClient{
+		remoteName: "kafka-test",
+		cfg:        &kafka.Config{Brokers: []string{"localhost:9092"}, Topic: "metrics"},
+		producer:   producer,
+		builder:    labels.NewScratchBuilder(0),
+	}
+
+	series := make([]prompb.TimeSeries, 10)
+	for i := range series {
+		series[i] = testKafkaSeries(i)
+	}
+	stats, err := c.Store(context.Background(), encodeTestWriteRequest(t, series), 0)
+	require.NoError(t, err)
+	require.True(t, stats.Confirmed)
+	require.Equal(t, len(series), stats.Samples)
+
+	// Every series must end up on the partition its label hash selects.
+	b := labels.NewScratchBuilder(0)
+	seen := 0
+	for _, msg := range producer.messages {
+		require.Equal(t, "metrics", msg.topic)
+		var wr prompb.WriteRequest
+		raw, err := snappy.Decode(nil, msg.value)
+		require.NoError(t, err)
+		require.NoError(t, proto.Unmarshal(raw, &wr))
+		for _, ts := range wr.Timeseries {
+			require.Equal(t, int(ts.ToLabels(&b, nil).Hash()%numPartitions), msg.partition)
+			seen++
+		}
+	}
+	require.Equal(t, len(series), seen)
+}
+
+func TestKafkaClientStoreDeadLetter(t *testing.T) {
+	t.Parallel()
+	series := []prompb.TimeSeries{testKafkaSeries(0)}
+	payload := encodeTestWriteRequest(t, series)
+
+	t.Run("failed delivery goes to the dead-letter topic", func(t *testing.T) {
+		t.Parallel()
+		producer := &fakeKafkaProducer{
+			partitions: map[string]int{"metrics": 4, "metrics-dlq": 1},
+			produceErr: map[string]error{"metrics": errors.New("broker down")},
+		}
+		c := &kafkaClient{
+			remoteName: "kafka-test",
+			cfg:        &kafka.Config{Brokers: []string{"localhost:9092"}, Topic: "metrics", DeadLetterTopic: "metrics-dlq"},
+			producer:   producer,
+			builder:    labels.NewScratchBuilder(0),
+		}
+
+		stats, err := c.Store(context.Background(), payload, 0)
+		require.NoError(t, err)
+		require.Equal(t, 1, stats.Samples)
+		require.Len(t, producer.messages, 1)
+		require.Equal(t, "metrics-dlq", producer.messages[0].topic)
+	})
+
+	t.Run("without a dead-letter topic the error is recoverable", func(t *testing.T) {
+		t.Parallel()
+		producer := &fakeKafkaProducer{
+			partitions: map[string]int{"metrics": 4},
+			produceErr: map[string]error{"metrics": errors.New("broker down")},
+		}
+		c := &kafkaClient{
+			remoteName: "kafka-test",
+			cfg:        &kafka.Config{Brokers: []string{"localhost:9092"}, Topic: "metrics"},
+			producer:   producer,
+			builder:    labels.NewScratchBuilder(0),
+		}
+
+		_, err := c.Store(context.Background(), payload, 0)
+		var recErr RecoverableError
+		require.ErrorAs(t, err, &recErr)
+	})
+}
+
+func TestNewKafkaWriteClient(t *testing.T) {
+	t.Parallel()
+	c, err := NewKafkaWriteClient("kafka-test", &kafka.Config{Brokers: []string{"localhost:9092"}, Topic: "metrics"}, 10*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "kafka-test", c.Name())
+	require.Equal(t, "kafka://localhost:9092/metrics", c.Endpoint())
+
+	_, err = NewKafkaWriteClient("kafka-test", &kafka.Config{Brokers: []string{"localhost:9092"}}, 10*time.Second)
+	require.Error(t, err)
+}
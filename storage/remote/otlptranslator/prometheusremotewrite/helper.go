@@ -531,7 +531,7 @@ func (c *PrometheusConverter) addLabels(name string, baseLabels labels.Labels, e
 
 // addResourceTargetInfo converts the resource to the target info metric.
 func (c *PrometheusConverter) addResourceTargetInfo(resource pcommon.Resource, settings Settings, earliestTimestamp, latestTimestamp time.Time) error {
-	if settings.DisableTargetInfo {
+	if settings.DisableTargetInfo || (c.resourceLabels != nil && c.resourceLabels.disableTargetInfo) {
 		return nil
 	}
 
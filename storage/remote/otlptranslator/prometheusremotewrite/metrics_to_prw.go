@@ -23,6 +23,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/grafana/regexp"
 	"github.com/prometheus/otlptranslator"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
@@ -38,6 +39,29 @@ import (
 type PromoteResourceAttributes struct {
 	promoteAll bool
 	attrs      map[string]struct{}
+	rules      []promotionRule
+}
+
+// promotionRule is a compiled attribute promotion rule. The first rule whose
+// match conditions all hold replaces the default promotion behaviour for a
+// resource.
+type promotionRule struct {
+	match                map[string]*regexp.Regexp
+	promote              *PromoteResourceAttributes
+	promoteScopeMetadata *bool
+	disableTargetInfo    bool
+}
+
+// matches reports whether every match condition holds for the resource
+// attributes. A missing attribute never matches.
+func (r *promotionRule) matches(resourceAttributes pcommon.Map) bool {
+	for name, re := range r.match {
+		v, ok := resourceAttributes.Get(name)
+		if !ok || !re.MatchString(v.AsString()) {
+			return false
+		}
+	}
+	return true
 }
 
 type Settings struct {
@@ -70,6 +94,10 @@ type cachedResourceLabels struct {
 	instanceLabel  string        // from service.instance.id.
 	promotedLabels labels.Labels // promoted resource attributes.
 	externalLabels map[string]string
+	// Per-rule overrides from the attribute promotion rule matching this
+	// resource, if any.
+	promoteScopeMetadata *bool
+	disableTargetInfo    bool
 }
 
 // cachedScopeLabels holds precomputed scope metadata labels.
@@ -384,20 +412,51 @@ func (c *PrometheusConverter) FromMetrics(ctx context.Context, md pmetric.Metric
 }
 
 func NewPromoteResourceAttributes(otlpCfg config.OTLPConfig) *PromoteResourceAttributes {
-	attrs := otlpCfg.PromoteResourceAttributes
-	if otlpCfg.PromoteAllResourceAttributes {
-		attrs = otlpCfg.IgnoreResourceAttributes
+	s := newPromoteResourceAttributes(otlpCfg.PromoteAllResourceAttributes, otlpCfg.PromoteResourceAttributes, otlpCfg.IgnoreResourceAttributes)
+	for _, r := range otlpCfg.AttributePromotionRules {
+		match := make(map[string]*regexp.Regexp, len(r.Match))
+		for name, re := range r.Match {
+			match[name] = re.Regexp
+		}
+		s.rules = append(s.rules, promotionRule{
+			match:                match,
+			promote:              newPromoteResourceAttributes(r.PromoteAllResourceAttributes, r.PromoteResourceAttributes, r.IgnoreResourceAttributes),
+			promoteScopeMetadata: r.PromoteScopeMetadata,
+			disableTargetInfo:    r.DisableTargetInfo,
+		})
+	}
+	return s
+}
+
+func newPromoteResourceAttributes(promoteAll bool, promoted, ignored []string) *PromoteResourceAttributes {
+	attrs := promoted
+	if promoteAll {
+		attrs = ignored
 	}
 	attrsMap := make(map[string]struct{}, len(attrs))
 	for _, s := range attrs {
 		attrsMap[s] = struct{}{}
 	}
 	return &PromoteResourceAttributes{
-		promoteAll: otlpCfg.PromoteAllResourceAttributes,
+		promoteAll: promoteAll,
 		attrs:      attrsMap,
 	}
 }
 
+// resolve returns the first rule whose match conditions hold for the resource
+// attributes, or nil if no rule matches.
+func (s *PromoteResourceAttributes) resolve(resourceAttributes pcommon.Map) *promotionRule {
+	if s == nil {
+		return nil
+	}
+	for i := range s.rules {
+		if s.rules[i].matches(resourceAttributes) {
+			return &s.rules[i]
+		}
+	}
+	return nil
+}
+
 // LabelNameBuilder is a function that builds/sanitizes label names.
 type LabelNameBuilder func(string) (string, error)
 
@@ -464,9 +523,15 @@ func (c *PrometheusConverter) setResourceContext(resource pcommon.Resource, sett
 		c.resourceLabels.instanceLabel = instance.AsString()
 	}
 
-	if settings.PromoteResourceAttributes != nil {
+	promote := settings.PromoteResourceAttributes
+	if rule := promote.resolve(resourceAttrs); rule != nil {
+		promote = rule.promote
+		c.resourceLabels.promoteScopeMetadata = rule.promoteScopeMetadata
+		c.resourceLabels.disableTargetInfo = rule.disableTargetInfo
+	}
+	if promote != nil {
 		c.builder.Reset(labels.EmptyLabels())
-		if err := settings.PromoteResourceAttributes.addPromotedAttributes(c.builder, resourceAttrs, c.buildLabelName); err != nil {
+		if err := promote.addPromotedAttributes(c.builder, resourceAttrs, c.buildLabelName); err != nil {
 			c.clearResourceContext()
 			return err
 		}
@@ -479,7 +544,11 @@ func (c *PrometheusConverter) setResourceContext(resource pcommon.Resource, sett
 // Called once per ScopeMetrics boundary, before processing any metrics.
 // If an error is returned, scope level cache is reset.
 func (c *PrometheusConverter) setScopeContext(scope scope, settings Settings) error {
-	if !settings.PromoteScopeMetadata || scope.name == "" {
+	promoteScopeMetadata := settings.PromoteScopeMetadata
+	if c.resourceLabels != nil && c.resourceLabels.promoteScopeMetadata != nil {
+		promoteScopeMetadata = *c.resourceLabels.promoteScopeMetadata
+	}
+	if !promoteScopeMetadata || scope.name == "" {
 		c.scopeLabels = nil
 		return nil
 	}
@@ -33,6 +33,7 @@ import (
 	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/util/teststorage"
 )
@@ -797,6 +798,87 @@ func TestFromMetrics(t *testing.T) {
 	})
 }
 
+func TestAttributePromotionRules(t *testing.T) {
+	request := pmetricotlp.NewExportRequest()
+	ts := pcommon.NewTimestampFromTime(time.Now())
+
+	addResource := func(serviceName, metricName string) {
+		rm := request.Metrics().ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", serviceName)
+		rm.Resource().Attributes().PutStr("service.instance.id", "instance-1")
+		rm.Resource().Attributes().PutStr("custom.promoted.attr", "promoted-value")
+		rm.Resource().Attributes().PutStr("k8s.pod.name", "pod-0")
+		scopeMetrics := rm.ScopeMetrics().AppendEmpty()
+		scopeMetrics.Scope().SetName("my-scope")
+		m := scopeMetrics.Metrics().AppendEmpty()
+		m.SetEmptyGauge()
+		m.SetName(metricName)
+		point := m.Gauge().DataPoints().AppendEmpty()
+		point.SetTimestamp(ts)
+		point.SetDoubleValue(1.0)
+	}
+	addResource("billing-frontend", "billing_gauge")
+	addResource("checkout", "checkout_gauge")
+
+	promoteScopeMetadata := false
+	appTest := teststorage.NewAppendable()
+	app := appTest.AppenderV2(t.Context())
+	converter := NewPrometheusConverter(app)
+	annots, err := converter.FromMetrics(
+		context.Background(),
+		request.Metrics(),
+		Settings{
+			PromoteResourceAttributes: NewPromoteResourceAttributes(config.OTLPConfig{
+				PromoteResourceAttributes: []string{"custom.promoted.attr"},
+				AttributePromotionRules: []config.AttributePromotionRule{
+					{
+						Match:                     map[string]relabel.Regexp{"service.name": relabel.MustNewRegexp("billing-.*")},
+						PromoteResourceAttributes: []string{"k8s.pod.name"},
+						PromoteScopeMetadata:      &promoteScopeMetadata,
+						DisableTargetInfo:         true,
+					},
+				},
+			}),
+			PromoteScopeMetadata: true,
+			LookbackDelta:        defaultLookbackDelta,
+		},
+	)
+	require.NoError(t, err)
+	require.Empty(t, annots)
+	require.NoError(t, app.Commit())
+
+	var billingSamples, checkoutSamples, targetInfoSamples []sample
+	for _, s := range appTest.ResultSamples() {
+		switch s.L.Get(labels.MetricName) {
+		case "billing_gauge":
+			billingSamples = append(billingSamples, s)
+		case "checkout_gauge":
+			checkoutSamples = append(checkoutSamples, s)
+		case "target_info":
+			targetInfoSamples = append(targetInfoSamples, s)
+		}
+	}
+
+	// The matched resource follows its rule: only the rule's attributes are
+	// promoted and scope metadata promotion is overridden off.
+	require.NotEmpty(t, billingSamples, "expected billing metric samples")
+	require.Equal(t, "pod-0", billingSamples[0].L.Get("k8s_pod_name"), "matched resource should promote the rule's attributes")
+	require.Empty(t, billingSamples[0].L.Get("custom_promoted_attr"), "matched resource should not promote the default attributes")
+	require.Empty(t, billingSamples[0].L.Get("otel_scope_name"), "rule should override scope metadata promotion")
+
+	// The unmatched resource keeps the top-level settings.
+	require.NotEmpty(t, checkoutSamples, "expected checkout metric samples")
+	require.Equal(t, "promoted-value", checkoutSamples[0].L.Get("custom_promoted_attr"), "unmatched resource should promote the default attributes")
+	require.Empty(t, checkoutSamples[0].L.Get("k8s_pod_name"), "unmatched resource should not promote the rule's attributes")
+	require.Equal(t, "my-scope", checkoutSamples[0].L.Get("otel_scope_name"), "unmatched resource should keep scope metadata promotion")
+
+	// target_info is suppressed for the matched resource only.
+	require.NotEmpty(t, targetInfoSamples, "expected target_info samples for the unmatched resource")
+	for _, s := range targetInfoSamples {
+		require.Equal(t, "checkout", s.L.Get(model.JobLabel), "target_info should only be generated for the unmatched resource")
+	}
+}
+
 func TestTemporality(t *testing.T) {
 	ts := time.Unix(100, 0)
 
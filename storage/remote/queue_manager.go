@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"sync"
@@ -41,6 +42,7 @@ import (
 	"github.com/prometheus/prometheus/model/metadata"
 	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/model/value"
 	"github.com/prometheus/prometheus/prompb"
 	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/prometheus/prometheus/schema"
@@ -60,12 +62,31 @@ const (
 	// Allow 30% too many shards before scaling down.
 	shardToleranceFraction = 0.3
 
+	// Weight of the newest observation in the smoothed desired shard count
+	// maintained by the latency SLO controller. A low weight dampens
+	// oscillation on bursty workloads.
+	sloShardSmoothingWeight = 0.3
+
 	reasonTooOld                     = "too_old"
 	reasonDroppedSeries              = "dropped_series"
 	reasonUnintentionalDroppedSeries = "unintentionally_dropped_series"
 	reasonNHCBNotSupported           = "nhcb_in_rw1_not_supported"
+	reasonStalenessMarker            = "staleness_marker"
+	reasonQueueFull                  = "queue_full"
 )
 
+// shedBackoffLimit is how far the enqueue backoff may grow before data of a
+// type listed in drop_on_backpressure is shed. Retrying briefly first avoids
+// shedding on the short queue unavailability during a reshard.
+const shedBackoffLimit = model.Duration(100 * time.Millisecond)
+
+// RemoteWriteTargetLabel is a reserved label that write_relabel_configs can
+// set to route a series to a single remote write endpoint. A series carrying
+// this label is only sent by the endpoint whose name matches the label value
+// and is dropped by all others; the label itself is never sent. Series
+// without the label are sent to every endpoint as before.
+const RemoteWriteTargetLabel = "__remote_write_target__"
+
 type queueManagerMetrics struct {
 	reg prometheus.Registerer
 
@@ -84,7 +105,7 @@ type queueManagerMetrics struct {
 	droppedSamplesTotal    *prometheus.CounterVec
 	droppedExemplarsTotal  *prometheus.CounterVec
 	droppedHistogramsTotal *prometheus.CounterVec
-	enqueueRetriesTotal    prometheus.Counter
+	enqueueRetriesTotal    *prometheus.CounterVec
 	sentBatchDuration      prometheus.Histogram
 	highestTimestamp       *maxTimestamp
 	highestSentTimestamp   *maxTimestamp
@@ -96,9 +117,12 @@ type queueManagerMetrics struct {
 	maxNumShards           prometheus.Gauge
 	minNumShards           prometheus.Gauge
 	desiredNumShards       prometheus.Gauge
+	latencySLOError        prometheus.Gauge
 	sentBytesTotal         prometheus.Counter
 	metadataBytesTotal     prometheus.Counter
 	maxSamplesPerSend      prometheus.Gauge
+	spillBufferBytes       prometheus.Gauge
+	spillDroppedBytesTotal *prometheus.CounterVec
 }
 
 func newQueueManagerMetrics(r prometheus.Registerer, rn, e string) *queueManagerMetrics {
@@ -215,13 +239,13 @@ func newQueueManagerMetrics(r prometheus.Registerer, rn, e string) *queueManager
 		Help:        "Total number of histograms which were dropped after being read from the WAL before being sent via remote write, either via relabelling, due to being too old or unintentionally because of an unknown reference ID.",
 		ConstLabels: constLabels,
 	}, []string{"reason"})
-	m.enqueueRetriesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	m.enqueueRetriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		Name:        "enqueue_retries_total",
-		Help:        "Total number of times enqueue has failed because a shards queue was full.",
+		Help:        "Total number of times enqueue has failed because a shards queue was full, partitioned by data type.",
 		ConstLabels: constLabels,
-	})
+	}, []string{"type"})
 	m.sentBatchDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
 		Namespace:                       namespace,
 		Subsystem:                       subsystem,
@@ -307,6 +331,13 @@ func newQueueManagerMetrics(r prometheus.Registerer, rn, e string) *queueManager
 		Help:        "The number of shards that the queues shard calculation wants to run based on the rate of samples in vs. samples out.",
 		ConstLabels: constLabels,
 	})
+	m.latencySLOError = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Subsystem:   subsystem,
+		Name:        "delivery_latency_slo_error_seconds",
+		Help:        "The difference between the observed end-to-end delivery delay and the configured delivery latency SLO. Positive when delivery is slower than the SLO. Only updated when delivery_latency_slo is configured.",
+		ConstLabels: constLabels,
+	})
 	m.sentBytesTotal = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace:   namespace,
 		Subsystem:   subsystem,
@@ -328,6 +359,20 @@ func newQueueManagerMetrics(r prometheus.Registerer, rn, e string) *queueManager
 		Help:        "The maximum number of samples to be sent, in a single request, to the remote storage. Note that, when sending of exemplars over remote write is enabled, exemplars count towards this limit.",
 		ConstLabels: constLabels,
 	})
+	m.spillBufferBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Subsystem:   subsystem,
+		Name:        "spill_buffer_bytes",
+		Help:        "The number of bytes of write requests currently held in the on-disk spill buffer awaiting replay.",
+		ConstLabels: constLabels,
+	})
+	m.spillDroppedBytesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   namespace,
+		Subsystem:   subsystem,
+		Name:        "spill_buffer_dropped_bytes_total",
+		Help:        "The total number of bytes of spilled write requests dropped from the on-disk spill buffer, either because the size quota was exceeded or because the requests outlived the configured maximum age.",
+		ConstLabels: constLabels,
+	}, []string{"reason"})
 
 	return m
 }
@@ -362,9 +407,12 @@ func (m *queueManagerMetrics) register() {
 			m.maxNumShards,
 			m.minNumShards,
 			m.desiredNumShards,
+			m.latencySLOError,
 			m.sentBytesTotal,
 			m.metadataBytesTotal,
 			m.maxSamplesPerSend,
+			m.spillBufferBytes,
+			m.spillDroppedBytesTotal,
 		)
 	}
 }
@@ -398,9 +446,12 @@ func (m *queueManagerMetrics) unregister() {
 		m.reg.Unregister(m.maxNumShards)
 		m.reg.Unregister(m.minNumShards)
 		m.reg.Unregister(m.desiredNumShards)
+		m.reg.Unregister(m.latencySLOError)
 		m.reg.Unregister(m.sentBytesTotal)
 		m.reg.Unregister(m.metadataBytesTotal)
 		m.reg.Unregister(m.maxSamplesPerSend)
+		m.reg.Unregister(m.spillBufferBytes)
+		m.reg.Unregister(m.spillDroppedBytesTotal)
 	}
 }
 
@@ -415,6 +466,17 @@ type WriteClient interface {
 	Endpoint() string
 }
 
+// CompressedWriteClient is an optional interface for WriteClients that let the
+// caller choose the content encoding of each request. The queue manager uses
+// it to keep the advertised encoding in sync with how the request payload was
+// actually compressed, which matters once it falls back to snappy after the
+// endpoint rejected the configured compression.
+type CompressedWriteClient interface {
+	WriteClient
+	// StoreCompressed is like Store for a req payload compressed with compr.
+	StoreCompressed(ctx context.Context, req []byte, retryAttempt int, compr compression.Type) (WriteResponseStats, error)
+}
+
 // QueueManager manages a queue of samples to be sent to the Storage
 // indicated by the provided WriteClient. Implements writeTo interface
 // used by WAL Watcher.
@@ -432,14 +494,22 @@ type QueueManager struct {
 	relabelConfigs          []*relabel.Config
 	sendExemplars           bool
 	sendNativeHistograms    bool
+	sendStalenessMarkers    bool
 	enableTypeAndUnitLabels bool
 	watcher                 *wlog.Watcher
 	metadataWatcher         *MetadataWatcher
 
+	// Per-type backpressure policies derived from
+	// QueueConfig.DropOnBackpressure: whether data of the type is shed
+	// instead of retried indefinitely when the shard queues stay full.
+	dropSamplesOnFull    bool
+	dropExemplarsOnFull  bool
+	dropHistogramsOnFull bool
+
 	clientMtx   sync.RWMutex
 	storeClient WriteClient
 	protoMsg    remoteapi.WriteMessageType
-	compr       compression.Type
+	compr       compression.Type // Guarded by clientMtx; changes when falling back to snappy.
 
 	seriesMtx      sync.Mutex // Covers seriesLabels, seriesMetadata, droppedSeries and builder.
 	seriesLabels   map[chunks.HeadSeriesRef]labels.Labels
@@ -456,6 +526,15 @@ type QueueManager struct {
 	quit        chan struct{}
 	wg          sync.WaitGroup
 
+	// spill holds write requests that exhausted their retry backoff on disk
+	// until the remote endpoint recovers. Nil when the spill buffer is
+	// disabled.
+	spill *spillBuffer
+
+	// Exponentially smoothed desired shard count used by the latency SLO
+	// controller. Only accessed from updateShardsLoop.
+	sloSmoothedShards float64
+
 	dataIn, dataDropped, dataOut, dataOutDuration *ewmaRate
 
 	metrics              *queueManagerMetrics
@@ -477,6 +556,7 @@ func NewQueueManager(
 	samplesIn *ewmaRate,
 	cfg config.QueueConfig,
 	mCfg config.MetadataConfig,
+	spillCfg config.SpillBufferConfig,
 	externalLabels labels.Labels,
 	relabelConfigs []*relabel.Config,
 	client WriteClient,
@@ -486,8 +566,10 @@ func NewQueueManager(
 	sm ReadyScrapeManager,
 	enableExemplarRemoteWrite bool,
 	enableNativeHistogramRemoteWrite bool,
+	enableStalenessMarkersRemoteWrite bool,
 	enableTypeAndUnitLabels bool,
 	protoMsg remoteapi.WriteMessageType,
+	compr compression.Type,
 	recordBuf *record.BuffersPool,
 ) *QueueManager {
 	if logger == nil {
@@ -501,6 +583,15 @@ func NewQueueManager(
 	})
 
 	logger = logger.With(remoteName, client.Name(), endpoint, client.Endpoint())
+	if compr == "" {
+		compr = compression.Snappy
+	}
+	if _, ok := client.(CompressedWriteClient); !ok && compr != compression.Snappy {
+		// The client cannot advertise any other content encoding, so sending
+		// it differently compressed payloads would only produce garbage.
+		logger.Warn("Write client does not support choosing a content encoding; using snappy compression", "compression", compr)
+		compr = compression.Snappy
+	}
 	t := &QueueManager{
 		logger:                  logger,
 		flushDeadline:           flushDeadline,
@@ -511,6 +602,7 @@ func NewQueueManager(
 		storeClient:             client,
 		sendExemplars:           enableExemplarRemoteWrite,
 		sendNativeHistograms:    enableNativeHistogramRemoteWrite,
+		sendStalenessMarkers:    enableStalenessMarkersRemoteWrite,
 		enableTypeAndUnitLabels: enableTypeAndUnitLabels,
 
 		seriesLabels:         make(map[chunks.HeadSeriesRef]labels.Labels),
@@ -533,7 +625,27 @@ func NewQueueManager(
 		highestRecvTimestamp: highestRecvTimestamp,
 
 		protoMsg: protoMsg,
-		compr:    compression.Snappy, // Hardcoded for now, but scaffolding exists for likely future use.
+		compr:    compr,
+	}
+
+	for _, dt := range cfg.DropOnBackpressure {
+		switch dt {
+		case "samples":
+			t.dropSamplesOnFull = true
+		case "exemplars":
+			t.dropExemplarsOnFull = true
+		case "histograms":
+			t.dropHistogramsOnFull = true
+		}
+	}
+
+	if spillCfg.MaxSize > 0 {
+		spill, err := newSpillBuffer(logger, filepath.Join(dir, "remote_spill", client.Name()), int64(spillCfg.MaxSize), time.Duration(spillCfg.MaxAge), metrics)
+		if err != nil {
+			logger.Error("Failed to open remote write spill buffer; disabling it", "err", err)
+		} else {
+			t.spill = spill
+		}
 	}
 
 	walMetadata := t.protoMsg != remoteapi.WriteV1MessageType
@@ -590,7 +702,8 @@ func (t *QueueManager) AppendWatcherMetadata(ctx context.Context, metadata []scr
 
 func (t *QueueManager) sendMetadataWithBackoff(ctx context.Context, metadata []prompb.MetricMetadata, pBuf *proto.Buffer) error {
 	// Build the WriteRequest with no samples (v1 flow).
-	req, _, _, err := buildWriteRequest(t.logger, nil, metadata, pBuf, nil, nil, t.compr)
+	compr := t.currentCompression()
+	req, _, _, err := buildWriteRequest(t.logger, nil, metadata, pBuf, nil, nil, compr)
 	if err != nil {
 		return err
 	}
@@ -612,10 +725,21 @@ func (t *QueueManager) sendMetadataWithBackoff(ctx context.Context, metadata []p
 			span.SetAttributes(semconv.HTTPResendCount(try))
 		}
 
+		if cur := t.currentCompression(); cur != compr {
+			// An earlier attempt fell back to another compression; rebuild the
+			// request so the payload and the advertised encoding keep matching.
+			compr = cur
+			req2, _, _, err := buildWriteRequest(t.logger, nil, metadata, pBuf, nil, nil, compr)
+			if err != nil {
+				return err
+			}
+			req = req2
+		}
+
 		begin := time.Now()
 		// Ignoring WriteResponseStats, because there is nothing for metadata, since it's
 		// embedded in v2 calls now, and we do v1 here.
-		_, err := t.storeClient.Store(ctx, req, try)
+		_, err := t.storeWithFallback(ctx, req, try, compr)
 		t.metrics.sentBatchDuration.Observe(time.Since(begin).Seconds())
 
 		if err != nil {
@@ -628,7 +752,7 @@ func (t *QueueManager) sendMetadataWithBackoff(ctx context.Context, metadata []p
 	retry := func() {
 		t.metrics.retriedMetadataTotal.Add(float64(len(metadata)))
 	}
-	err = t.sendWriteRequestWithBackoff(ctx, attemptStore, retry)
+	err = t.sendWriteRequestWithBackoff(ctx, attemptStore, retry, nil)
 	if err != nil {
 		return err
 	}
@@ -732,6 +856,10 @@ outer:
 			t.metrics.droppedSamplesTotal.WithLabelValues(reasonTooOld).Inc()
 			continue
 		}
+		if !t.sendStalenessMarkers && value.IsStaleNaN(s.V) {
+			t.metrics.droppedSamplesTotal.WithLabelValues(reasonStalenessMarker).Inc()
+			continue
+		}
 		t.seriesMtx.Lock()
 		lbls, ok := t.seriesLabels[s.Ref]
 		if !ok {
@@ -771,7 +899,12 @@ outer:
 				continue outer
 			}
 
-			t.metrics.enqueueRetriesTotal.Inc()
+			t.metrics.enqueueRetriesTotal.WithLabelValues("samples").Inc()
+			if t.dropSamplesOnFull && backoff >= shedBackoffLimit {
+				t.dataDropped.incr(1)
+				t.metrics.droppedSamplesTotal.WithLabelValues(reasonQueueFull).Inc()
+				continue outer
+			}
 			time.Sleep(time.Duration(backoff))
 			backoff *= 2
 			// It is reasonable to use t.cfg.MaxBackoff here, as if we have hit
@@ -830,7 +963,12 @@ outer:
 				continue outer
 			}
 
-			t.metrics.enqueueRetriesTotal.Inc()
+			t.metrics.enqueueRetriesTotal.WithLabelValues("exemplars").Inc()
+			if t.dropExemplarsOnFull && backoff >= shedBackoffLimit {
+				t.dataDropped.incr(1)
+				t.metrics.droppedExemplarsTotal.WithLabelValues(reasonQueueFull).Inc()
+				continue outer
+			}
 			time.Sleep(time.Duration(backoff))
 			backoff *= 2
 			if backoff > t.cfg.MaxBackoff {
@@ -858,6 +996,10 @@ outer:
 			t.logger.Warn("Dropped native histogram with custom buckets (NHCB) as remote write v1 does not support itB", "ref", h.Ref)
 			continue
 		}
+		if !t.sendStalenessMarkers && h.H != nil && value.IsStaleNaN(h.H.Sum) {
+			t.metrics.droppedHistogramsTotal.WithLabelValues(reasonStalenessMarker).Inc()
+			continue
+		}
 		t.seriesMtx.Lock()
 		lbls, ok := t.seriesLabels[h.Ref]
 		if !ok {
@@ -892,7 +1034,12 @@ outer:
 				continue outer
 			}
 
-			t.metrics.enqueueRetriesTotal.Inc()
+			t.metrics.enqueueRetriesTotal.WithLabelValues("histograms").Inc()
+			if t.dropHistogramsOnFull && backoff >= shedBackoffLimit {
+				t.dataDropped.incr(1)
+				t.metrics.droppedHistogramsTotal.WithLabelValues(reasonQueueFull).Inc()
+				continue outer
+			}
 			time.Sleep(time.Duration(backoff))
 			backoff *= 2
 			if backoff > t.cfg.MaxBackoff {
@@ -920,6 +1067,10 @@ outer:
 			t.logger.Warn("Dropped float native histogram with custom buckets (NHCB) as remote write v1 does not support itB", "ref", h.Ref)
 			continue
 		}
+		if !t.sendStalenessMarkers && h.FH != nil && value.IsStaleNaN(h.FH.Sum) {
+			t.metrics.droppedHistogramsTotal.WithLabelValues(reasonStalenessMarker).Inc()
+			continue
+		}
 		t.seriesMtx.Lock()
 		lbls, ok := t.seriesLabels[h.Ref]
 		if !ok {
@@ -954,7 +1105,12 @@ outer:
 				continue outer
 			}
 
-			t.metrics.enqueueRetriesTotal.Inc()
+			t.metrics.enqueueRetriesTotal.WithLabelValues("histograms").Inc()
+			if t.dropHistogramsOnFull && backoff >= shedBackoffLimit {
+				t.dataDropped.incr(1)
+				t.metrics.droppedHistogramsTotal.WithLabelValues(reasonQueueFull).Inc()
+				continue outer
+			}
 			time.Sleep(time.Duration(backoff))
 			backoff *= 2
 			if backoff > t.cfg.MaxBackoff {
@@ -985,6 +1141,11 @@ func (t *QueueManager) Start() {
 	t.wg.Add(2)
 	go t.updateShardsLoop()
 	go t.reshardLoop()
+
+	if t.spill != nil {
+		t.wg.Add(1)
+		go t.replaySpillLoop()
+	}
 }
 
 // Stop stops sending samples to the remote storage and waits for pending
@@ -1023,6 +1184,14 @@ func (t *QueueManager) StoreSeries(series []record.RefSeries, index int) {
 			t.droppedSeries[s.Ref] = struct{}{}
 			continue
 		}
+		if target := t.builder.Get(RemoteWriteTargetLabel); target != "" {
+			if target != t.client().Name() {
+				// The series is routed to a different endpoint.
+				t.droppedSeries[s.Ref] = struct{}{}
+				continue
+			}
+			t.builder.Del(RemoteWriteTargetLabel)
+		}
 		lbls := t.builder.Labels()
 		t.seriesLabels[s.Ref] = lbls
 	}
@@ -1089,6 +1258,51 @@ func (t *QueueManager) client() WriteClient {
 	return t.storeClient
 }
 
+// currentCompression returns the compression to use for the next write
+// request. It starts out as the configured compression and changes to snappy
+// once the endpoint rejects the configured one.
+func (t *QueueManager) currentCompression() compression.Type {
+	t.clientMtx.RLock()
+	defer t.clientMtx.RUnlock()
+	return t.compr
+}
+
+// fallBackToSnappy switches the queue to snappy compression after the remote
+// endpoint turned down the rejected content encoding.
+func (t *QueueManager) fallBackToSnappy(rejected compression.Type) {
+	t.clientMtx.Lock()
+	defer t.clientMtx.Unlock()
+	if t.compr == compression.Snappy {
+		return
+	}
+	t.logger.Warn("Remote endpoint does not accept the configured compression; falling back to snappy", "compression", rejected)
+	t.compr = compression.Snappy
+}
+
+// store sends req, compressed with compr, via the current write client,
+// advertising compr as the content encoding when the client supports choosing
+// one per request.
+func (t *QueueManager) store(ctx context.Context, req []byte, attempt int, compr compression.Type) (WriteResponseStats, error) {
+	client := t.client()
+	if cc, ok := client.(CompressedWriteClient); ok {
+		return cc.StoreCompressed(ctx, req, attempt, compr)
+	}
+	return client.Store(ctx, req, attempt)
+}
+
+// storeWithFallback is store, except that an endpoint rejecting a non-snappy
+// content encoding makes the queue fall back to snappy and yields a
+// recoverable error, so that the caller rebuilds the request with the new
+// encoding and retries.
+func (t *QueueManager) storeWithFallback(ctx context.Context, req []byte, attempt int, compr compression.Type) (WriteResponseStats, error) {
+	rs, err := t.store(ctx, req, attempt, compr)
+	if err != nil && compr != compression.Snappy && errors.Is(err, ErrUnsupportedMediaType) {
+		t.fallBackToSnappy(compr)
+		return rs, RecoverableError{err, defaultBackoff}
+	}
+	return rs, err
+}
+
 // processExternalLabels merges externalLabels into b. If b contains
 // a label in externalLabels, the value in b wins.
 func processExternalLabels(b *labels.Builder, externalLabels []labels.Label) {
@@ -1185,6 +1399,28 @@ func (t *QueueManager) calculateDesiredShards() int {
 		timePerSample = dataOutDuration / dataOutRate
 		desiredShards = timePerSample * (dataInRate*dataKeptRatio + backlogCatchup)
 	)
+	sloSeconds := time.Duration(t.cfg.DeliveryLatencySLO).Seconds()
+	if sloSeconds > 0 {
+		// Latency SLO controller: instead of catching up on a fixed fraction
+		// of the backlog, scale the queue so that the delivery delay returns
+		// to the SLO within one SLO window. A delay below the SLO needs no
+		// correction, so short bursts do not trigger a scale-up at all.
+		latencyError := delay - sloSeconds
+		t.metrics.latencySLOError.Set(latencyError)
+		catchupRate := 0.0
+		if latencyError > 0 {
+			catchupRate = latencyError * dataInRate * dataKeptRatio / sloSeconds
+		}
+		target := timePerSample * (dataInRate*dataKeptRatio + catchupRate)
+		// Smooth the target so that a single spiky tick moves the desired
+		// shard count only part of the way, avoiding oscillation.
+		if t.sloSmoothedShards <= 0 {
+			t.sloSmoothedShards = target
+		} else {
+			t.sloSmoothedShards += sloShardSmoothingWeight * (target - t.sloSmoothedShards)
+		}
+		desiredShards = t.sloSmoothedShards
+	}
 	t.metrics.desiredNumShards.Set(desiredShards)
 	t.logger.Debug("QueueManager.calculateDesiredShards",
 		"dataInRate", dataInRate,
@@ -1213,8 +1449,13 @@ func (t *QueueManager) calculateDesiredShards() int {
 	}
 
 	numShards := int(desiredShards)
-	// Do not downshard if we are more than ten seconds back.
-	if numShards < t.numShards && delay > 10.0 {
+	// Do not downshard if we are more than ten seconds back, or beyond the
+	// latency SLO when one is configured.
+	downshardDelayLimit := 10.0
+	if sloSeconds > 0 {
+		downshardDelayLimit = sloSeconds
+	}
+	if numShards < t.numShards && delay > downshardDelayLimit {
 		t.logger.Debug("Not downsharding due to being too far behind")
 		return t.numShards
 	}
@@ -1634,7 +1875,7 @@ func (s *shards) runShard(ctx context.Context, shardID int, queue *queue) {
 				return
 			}
 
-			sendBatch(batch, s.qm.protoMsg, s.qm.compr, false)
+			sendBatch(batch, s.qm.protoMsg, s.qm.currentCompression(), false)
 			// TODO(bwplotka): Previously the return was between popular and send.
 			// Consider this when DRY-ing https://github.com/prometheus/prometheus/issues/14409
 			queue.ReturnForReuse(batch)
@@ -1645,7 +1886,7 @@ func (s *shards) runShard(ctx context.Context, shardID int, queue *queue) {
 		case <-timer.C:
 			batch := queue.Batch()
 			if len(batch) > 0 {
-				sendBatch(batch, s.qm.protoMsg, s.qm.compr, true)
+				sendBatch(batch, s.qm.protoMsg, s.qm.currentCompression(), true)
 			}
 			queue.ReturnForReuse(batch)
 			timer.Reset(time.Duration(s.qm.cfg.BatchSendDeadline))
@@ -1786,6 +2027,16 @@ func (s *shards) sendSamplesWithBackoff(ctx context.Context, samples []prompb.Ti
 	// parameters for sendSamplesWithBackoff/3.
 	attemptStore := func(try int) error {
 		currentTime := time.Now()
+		if cur := s.qm.currentCompression(); cur != compr {
+			// An earlier attempt fell back to another compression; rebuild the
+			// request so the payload and the advertised encoding keep matching.
+			compr = cur
+			req2, _, _, err := buildWriteRequest(s.qm.logger, samples, nil, pBuf, nil, buf, compr)
+			if err != nil {
+				return err
+			}
+			req = req2
+		}
 		lowest := s.qm.buildRequestLimitTimestamp.Load()
 		if isSampleOld(currentTime, time.Duration(s.qm.cfg.SampleAgeLimit), lowest) {
 			// This will filter out old samples during retries.
@@ -1812,7 +2063,7 @@ func (s *shards) sendSamplesWithBackoff(ctx context.Context, samples []prompb.Ti
 		metricsUpdater.recordBatchAttempt(sc)
 		// Technically for v1, we will likely have empty response stats, but for
 		// newer Receivers this might be not, so used it in a best effort.
-		rs, err := s.qm.client().Store(ctx, req, try)
+		rs, err := s.qm.storeWithFallback(ctx, req, try, compr)
 		metricsUpdater.recordLatency(begin)
 		// TODO(bwplotka): Revisit this once we have Receivers doing retriable partial error
 		// so far we don't have those, so it's ok to potentially skew statistics.
@@ -1829,7 +2080,12 @@ func (s *shards) sendSamplesWithBackoff(ctx context.Context, samples []prompb.Ti
 		metricsUpdater.recordRetry(sc)
 	}
 
-	err = s.qm.sendWriteRequestWithBackoff(ctx, attemptStore, onRetry)
+	var spill func() bool
+	if s.qm.spill != nil {
+		spill = func() bool { return s.qm.spill.add(req, compr) }
+	}
+
+	err = s.qm.sendWriteRequestWithBackoff(ctx, attemptStore, onRetry, spill)
 	if errors.Is(err, context.Canceled) {
 		// When there is resharding, we cancel the context for this queue, which means the data is not sent.
 		// So we exit early to not update the metrics.
@@ -1839,6 +2095,16 @@ func (s *shards) sendSamplesWithBackoff(ctx context.Context, samples []prompb.Ti
 	s.qm.metrics.sentBytesTotal.Add(float64(reqSize))
 	s.qm.metrics.highestSentTimestamp.Set(float64(highest / 1000))
 
+	if errors.Is(err, errSpilledToDisk) {
+		// The request is durably buffered on disk and will be replayed, so
+		// treat it as sent to keep the WAL watcher moving.
+		return WriteResponseStats{
+			Samples:    sampleCount,
+			Histograms: histogramCount,
+			Exemplars:  exemplarCount,
+		}, nil
+	}
+
 	if err == nil && !accumulatedStats.Confirmed {
 		// No 2.0 response headers, and we sent v1 message, so likely it's 1.0 Receiver.
 		// Assume success, don't rely on headers.
@@ -1891,6 +2157,16 @@ func (s *shards) sendV2SamplesWithBackoff(ctx context.Context, samples []writev2
 	// parameters for sendSamplesWithBackoff/3.
 	attemptStore := func(try int) error {
 		currentTime := time.Now()
+		if cur := s.qm.currentCompression(); cur != compr {
+			// An earlier attempt fell back to another compression; rebuild the
+			// request so the payload and the advertised encoding keep matching.
+			compr = cur
+			req2, _, _, err := buildV2WriteRequest(s.qm.logger, samples, labels, pBuf, nil, buf, compr)
+			if err != nil {
+				return err
+			}
+			req = req2
+		}
 		lowest := s.qm.buildRequestLimitTimestamp.Load()
 		if isSampleOld(currentTime, time.Duration(s.qm.cfg.SampleAgeLimit), lowest) {
 			// This will filter out old samples during retries.
@@ -1915,7 +2191,7 @@ func (s *shards) sendV2SamplesWithBackoff(ctx context.Context, samples []writev2
 
 		begin := time.Now()
 		metricsUpdater.recordBatchAttempt(sc)
-		rs, err := s.qm.client().Store(ctx, req, try)
+		rs, err := s.qm.storeWithFallback(ctx, req, try, compr)
 		metricsUpdater.recordLatency(begin)
 		// TODO(bwplotka): Revisit this once we have Receivers doing retriable partial error
 		// so far we don't have those, so it's ok to potentially skew statistics.
@@ -1943,7 +2219,12 @@ func (s *shards) sendV2SamplesWithBackoff(ctx context.Context, samples []writev2
 		metricsUpdater.recordRetry(sc)
 	}
 
-	err = s.qm.sendWriteRequestWithBackoff(ctx, attemptStore, onRetry)
+	var spill func() bool
+	if s.qm.spill != nil {
+		spill = func() bool { return s.qm.spill.add(req, compr) }
+	}
+
+	err = s.qm.sendWriteRequestWithBackoff(ctx, attemptStore, onRetry, spill)
 	if errors.Is(err, context.Canceled) {
 		// When there is resharding, we cancel the context for this queue, which means the data is not sent.
 		// So we exit early to not update the metrics.
@@ -1952,6 +2233,16 @@ func (s *shards) sendV2SamplesWithBackoff(ctx context.Context, samples []writev2
 
 	s.qm.metrics.sentBytesTotal.Add(float64(reqSize))
 	s.qm.metrics.highestSentTimestamp.Set(float64(highest / 1000))
+
+	if errors.Is(err, errSpilledToDisk) {
+		// The request is durably buffered on disk and will be replayed, so
+		// treat it as sent to keep the WAL watcher moving.
+		return WriteResponseStats{
+			Samples:    sampleCount,
+			Histograms: histogramCount,
+			Exemplars:  exemplarCount,
+		}, nil
+	}
 	return accumulatedStats, err
 }
 
@@ -2022,7 +2313,12 @@ func populateV2TimeSeries(symbolTable *writev2.SymbolsTable, batch []timeSeries,
 	return nPendingSamples, nPendingExemplars, nPendingHistograms, nPendingMetadata, nUnexpectedMetadata
 }
 
-func (t *QueueManager) sendWriteRequestWithBackoff(ctx context.Context, attempt func(int) error, onRetry func()) error {
+// sendWriteRequestWithBackoff retries attempt with exponential backoff for as
+// long as it returns a RecoverableError. If spill is non-nil, the request is
+// handed to it once the backoff has grown to its configured maximum and
+// errSpilledToDisk is returned; the spilled request is replayed later by
+// replaySpillLoop.
+func (t *QueueManager) sendWriteRequestWithBackoff(ctx context.Context, attempt func(int) error, onRetry func(), spill func() bool) error {
 	backoff := t.cfg.MinBackoff
 	sleepDuration := model.Duration(0)
 	try := 0
@@ -2046,6 +2342,17 @@ func (t *QueueManager) sendWriteRequestWithBackoff(ctx context.Context, attempt
 			return err
 		}
 
+		// Once the backoff has grown to its maximum the endpoint has been
+		// unreachable for a while. Rather than blocking the shard (and with it
+		// the WAL watcher) indefinitely, move the request to the on-disk spill
+		// buffer and carry on.
+		if spill != nil && backoff >= t.cfg.MaxBackoff {
+			if spill() {
+				t.logger.Warn("Spilled write request to disk after exhausting retry backoff", "err", err)
+				return errSpilledToDisk
+			}
+		}
+
 		sleepDuration = backoff
 		switch {
 		case backoffErr.retryAfter > 0:
@@ -17,7 +17,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"runtime/pprof"
 	"strconv"
@@ -32,6 +37,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	client_testutil "github.com/prometheus/client_golang/prometheus/testutil"
 	dto "github.com/prometheus/client_model/go"
+	config_util "github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/common/promslog"
 	"github.com/stretchr/testify/require"
@@ -43,6 +49,7 @@ import (
 	"github.com/prometheus/prometheus/model/metadata"
 	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/model/value"
 	"github.com/prometheus/prometheus/prompb"
 	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/prometheus/prometheus/schema"
@@ -306,7 +313,7 @@ func newTestClientAndQueueManager(t testing.TB, flushDeadline time.Duration, pro
 func newTestQueueManager(t testing.TB, cfg config.QueueConfig, mcfg config.MetadataConfig, deadline time.Duration, c WriteClient, protoMsg remoteapi.WriteMessageType) *QueueManager {
 	dir := t.TempDir()
 	metrics := newQueueManagerMetrics(nil, "", "")
-	m := NewQueueManager(metrics, nil, nil, nil, dir, newEWMARate(ewmaWeight, shardUpdateDuration), cfg, mcfg, labels.EmptyLabels(), nil, c, deadline, newPool(), newHighestTimestampMetric(), nil, false, false, false, protoMsg, record.NewBuffersPool())
+	m := NewQueueManager(metrics, nil, nil, nil, dir, newEWMARate(ewmaWeight, shardUpdateDuration), cfg, mcfg, config.SpillBufferConfig{}, labels.EmptyLabels(), nil, c, deadline, newPool(), newHighestTimestampMetric(), nil, false, false, true, false, protoMsg, compression.Snappy, record.NewBuffersPool())
 
 	return m
 }
@@ -694,6 +701,38 @@ func TestQueueFilledDeadlock(t *testing.T) {
 	}
 }
 
+func TestDropExemplarsOnBackpressure(t *testing.T) {
+	t.Parallel()
+
+	c := NewTestBlockedWriteClient()
+	cfg := testDefaultQueueConfig()
+	cfg.MinShards, cfg.MaxShards = 1, 1
+	cfg.MaxSamplesPerSend = 2
+	cfg.Capacity = 2
+	// Start the enqueue backoff at the shed limit so the test sheds without
+	// waiting out the retry grace period.
+	cfg.MinBackoff = shedBackoffLimit
+	cfg.DropOnBackpressure = []string{"exemplars"}
+	mcfg := config.DefaultMetadataConfig
+
+	metrics := newQueueManagerMetrics(nil, "", "")
+	m := NewQueueManager(metrics, nil, nil, nil, t.TempDir(), newEWMARate(ewmaWeight, shardUpdateDuration), cfg, mcfg, config.SpillBufferConfig{}, labels.EmptyLabels(), nil, c, time.Second, newPool(), newHighestTimestampMetric(), nil, true, false, true, false, remoteapi.WriteV1MessageType, compression.Snappy, record.NewBuffersPool())
+
+	recs := testwal.GenerateRecords(recCase{
+		NoST:   true, // RW1 does not support ST.
+		Series: 1, SamplesPerSeries: 1, ExemplarsPerSeries: 40,
+	})
+	m.StoreSeries(recs.Series, 0)
+	m.Start()
+	defer m.Stop()
+
+	// The client never acknowledges anything, so the shard queue fills up and
+	// stays full. With the drop policy in place, AppendExemplars must shed the
+	// excess exemplars and return instead of blocking forever.
+	require.True(t, m.AppendExemplars(recs.Exemplars))
+	require.Positive(t, client_testutil.ToFloat64(metrics.droppedExemplarsTotal.WithLabelValues(reasonQueueFull)))
+}
+
 func TestReleaseNoninternedString(t *testing.T) {
 	for _, protoMsg := range []remoteapi.WriteMessageType{remoteapi.WriteV1MessageType, remoteapi.WriteV2MessageType} {
 		t.Run(fmt.Sprint(protoMsg), func(t *testing.T) {
@@ -791,7 +830,7 @@ func TestDisableReshardOnRetry(t *testing.T) {
 		}
 	)
 
-	m := NewQueueManager(metrics, nil, nil, nil, "", newEWMARate(ewmaWeight, shardUpdateDuration), cfg, mcfg, labels.EmptyLabels(), nil, client, 0, newPool(), newHighestTimestampMetric(), nil, false, false, false, remoteapi.WriteV1MessageType, nil)
+	m := NewQueueManager(metrics, nil, nil, nil, "", newEWMARate(ewmaWeight, shardUpdateDuration), cfg, mcfg, config.SpillBufferConfig{}, labels.EmptyLabels(), nil, client, 0, newPool(), newHighestTimestampMetric(), nil, false, false, true, false, remoteapi.WriteV1MessageType, compression.Snappy, nil)
 	m.StoreSeries(recs.Series, 0)
 
 	// Attempt to samples while the manager is running. We immediately stop the
@@ -1399,7 +1438,7 @@ func BenchmarkStoreSeries(b *testing.B) {
 				mcfg := config.DefaultMetadataConfig
 				metrics := newQueueManagerMetrics(nil, "", "")
 
-				m := NewQueueManager(metrics, nil, nil, nil, dir, newEWMARate(ewmaWeight, shardUpdateDuration), cfg, mcfg, labels.EmptyLabels(), nil, c, defaultFlushDeadline, newPool(), newHighestTimestampMetric(), nil, false, false, false, remoteapi.WriteV1MessageType, record.NewBuffersPool())
+				m := NewQueueManager(metrics, nil, nil, nil, dir, newEWMARate(ewmaWeight, shardUpdateDuration), cfg, mcfg, config.SpillBufferConfig{}, labels.EmptyLabels(), nil, c, defaultFlushDeadline, newPool(), newHighestTimestampMetric(), nil, false, false, true, false, remoteapi.WriteV1MessageType, compression.Snappy, record.NewBuffersPool())
 				m.externalLabels = tc.externalLabels
 				m.relabelConfigs = tc.relabelConfigs
 
@@ -1409,6 +1448,51 @@ func BenchmarkStoreSeries(b *testing.B) {
 	}
 }
 
+func TestStoreSeriesRouting(t *testing.T) {
+	t.Parallel()
+	// Route ops metrics to this endpoint (the test client is named
+	// "testwriteclient") and business metrics to another endpoint.
+	relabelConfigs := []*relabel.Config{
+		{
+			SourceLabels:         model.LabelNames{"__name__"},
+			Regex:                relabel.MustNewRegexp("ops_.*"),
+			TargetLabel:          RemoteWriteTargetLabel,
+			Replacement:          "testwriteclient",
+			Action:               relabel.Replace,
+			NameValidationScheme: model.UTF8Validation,
+		},
+		{
+			SourceLabels:         model.LabelNames{"__name__"},
+			Regex:                relabel.MustNewRegexp("business_.*"),
+			TargetLabel:          RemoteWriteTargetLabel,
+			Replacement:          "business-backend",
+			Action:               relabel.Replace,
+			NameValidationScheme: model.UTF8Validation,
+		},
+	}
+
+	c := NewTestWriteClient(remoteapi.WriteV1MessageType)
+	cfg := config.DefaultQueueConfig
+	mcfg := config.DefaultMetadataConfig
+	metrics := newQueueManagerMetrics(nil, "", "")
+	m := NewQueueManager(metrics, nil, nil, nil, t.TempDir(), newEWMARate(ewmaWeight, shardUpdateDuration), cfg, mcfg, config.SpillBufferConfig{}, labels.EmptyLabels(), relabelConfigs, c, defaultFlushDeadline, newPool(), newHighestTimestampMetric(), nil, false, false, true, false, remoteapi.WriteV1MessageType, compression.Snappy, record.NewBuffersPool())
+
+	m.StoreSeries([]record.RefSeries{
+		{Ref: 1, Labels: labels.FromStrings("__name__", "ops_up", "job", "api")},
+		{Ref: 2, Labels: labels.FromStrings("__name__", "business_orders_total", "job", "api")},
+		{Ref: 3, Labels: labels.FromStrings("__name__", "unrouted_metric", "job", "api")},
+	}, 0)
+
+	// The series routed to this endpoint is kept, with the routing label
+	// stripped so it is never sent.
+	require.True(t, labels.Equal(labels.FromStrings("__name__", "ops_up", "job", "api"), m.seriesLabels[1]))
+	// The series routed to the other endpoint is dropped like a relabel drop.
+	require.NotContains(t, m.seriesLabels, chunks.HeadSeriesRef(2))
+	require.Contains(t, m.droppedSeries, chunks.HeadSeriesRef(2))
+	// Series without a routing label are still sent to every endpoint.
+	require.Contains(t, m.seriesLabels, chunks.HeadSeriesRef(3))
+}
+
 func TestProcessExternalLabels(t *testing.T) {
 	b := labels.NewBuilder(labels.EmptyLabels())
 	for i, tc := range []struct {
@@ -1716,6 +1800,98 @@ func TestCalculateDesiredShardsDetail(t *testing.T) {
 	}
 }
 
+func TestCalculateDesiredShardsLatencySLO(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		prevShards      int
+		dataIn          int64 // Quantities normalised to seconds.
+		dataOut         int64
+		dataOutDuration float64
+		delay           float64
+		slo             time.Duration
+		expectedShards  int
+	}{
+		{
+			name:            "within SLO no correction",
+			prevShards:      1,
+			dataIn:          10,
+			dataOut:         10,
+			dataOutDuration: 1,
+			delay:           5,
+			slo:             30 * time.Second,
+			expectedShards:  1,
+		},
+		{
+			name:            "burst below SLO does not scale up",
+			prevShards:      2,
+			dataIn:          10,
+			dataOut:         10,
+			dataOutDuration: 2,
+			delay:           25,
+			slo:             30 * time.Second,
+			expectedShards:  2, // The fixed 5% catch-up would have wanted more.
+		},
+		{
+			name:            "over SLO catches up within one window",
+			prevShards:      2,
+			dataIn:          10,
+			dataOut:         10,
+			dataOutDuration: 2,
+			delay:           90,
+			slo:             30 * time.Second,
+			expectedShards:  6, // 0.2s/sample * (10/s + 60s error * 10/s / 30s).
+		},
+		{
+			name:            "over SLO constrained by max shards",
+			prevShards:      2,
+			dataIn:          100,
+			dataOut:         100,
+			dataOutDuration: 10,
+			delay:           600,
+			slo:             30 * time.Second,
+			expectedShards:  config.DefaultQueueConfig.MaxShards,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, m := newTestClientAndQueueManager(t, defaultFlushDeadline, remoteapi.WriteV1MessageType)
+			m.cfg.DeliveryLatencySLO = model.Duration(tc.slo)
+
+			m.numShards = tc.prevShards
+			forceEMWA(m.dataIn, tc.dataIn*int64(shardUpdateDuration/time.Second))
+			m.dataIn.tick()
+			forceEMWA(m.dataOut, tc.dataOut*int64(shardUpdateDuration/time.Second))
+			forceEMWA(m.dataDropped, 0)
+			forceEMWA(m.dataOutDuration, int64(tc.dataOutDuration*float64(shardUpdateDuration)))
+			m.highestRecvTimestamp.value = tc.delay // Not Set() because it can only increase value.
+
+			require.Equal(t, tc.expectedShards, m.calculateDesiredShards())
+			require.Equal(t, tc.delay-tc.slo.Seconds(), client_testutil.ToFloat64(m.metrics.latencySLOError))
+		})
+	}
+
+	t.Run("smoothing dampens oscillation", func(t *testing.T) {
+		_, m := newTestClientAndQueueManager(t, defaultFlushDeadline, remoteapi.WriteV1MessageType)
+		m.cfg.DeliveryLatencySLO = model.Duration(30 * time.Second)
+
+		// Steady state at one shard.
+		m.numShards = 1
+		forceEMWA(m.dataIn, 10*int64(shardUpdateDuration/time.Second))
+		m.dataIn.tick()
+		forceEMWA(m.dataOut, 10*int64(shardUpdateDuration/time.Second))
+		forceEMWA(m.dataDropped, 0)
+		forceEMWA(m.dataOutDuration, int64(shardUpdateDuration))
+		m.highestRecvTimestamp.value = 5
+		require.Equal(t, 1, m.calculateDesiredShards())
+
+		// A single tick far beyond the SLO moves the desired shard count only
+		// part of the way towards the raw controller target of 31 shards.
+		m.highestRecvTimestamp.value = 300
+		desired := m.calculateDesiredShards()
+		require.Greater(t, desired, 1, "expected a scale-up on a latency SLO violation")
+		require.Less(t, desired, 31, "expected smoothing to dampen the reaction to a single spike")
+	})
+}
+
 func forceEMWA(r *ewmaRate, rate int64) {
 	r.init = false
 	r.newEvents.Store(rate)
@@ -2735,6 +2911,90 @@ func TestAppendHistogramSchemaValidation(t *testing.T) {
 	}
 }
 
+func TestAppendDropsStalenessMarkersWhenDisabled(t *testing.T) {
+	for _, protoMsg := range []remoteapi.WriteMessageType{remoteapi.WriteV1MessageType, remoteapi.WriteV2MessageType} {
+		t.Run(string(protoMsg), func(t *testing.T) {
+			c := NewTestWriteClient(protoMsg)
+			cfg := testDefaultQueueConfig()
+			mcfg := config.DefaultMetadataConfig
+			cfg.MaxShards = 1
+
+			m := newTestQueueManager(t, cfg, mcfg, defaultFlushDeadline, c, protoMsg)
+			m.sendNativeHistograms = true
+			m.sendStalenessMarkers = false
+
+			series := []record.RefSeries{
+				{
+					Ref:    chunks.HeadSeriesRef(0),
+					Labels: labels.FromStrings("__name__", "test_metric"),
+				},
+			}
+			m.StoreSeries(series, 0)
+
+			staleNaN := math.Float64frombits(value.StaleNaN)
+			samples := []record.RefSample{
+				{Ref: chunks.HeadSeriesRef(0), T: 1234567890, V: 42.0},
+				{Ref: chunks.HeadSeriesRef(0), T: 1234567891, V: staleNaN},
+			}
+			histograms := []record.RefHistogramSample{
+				{
+					Ref: chunks.HeadSeriesRef(0),
+					T:   1234567892,
+					H: &histogram.Histogram{
+						Schema:          0,
+						ZeroThreshold:   1e-128,
+						Count:           2,
+						Sum:             5.0,
+						PositiveSpans:   []histogram.Span{{Offset: 0, Length: 1}},
+						PositiveBuckets: []int64{2},
+					},
+				},
+				{
+					Ref: chunks.HeadSeriesRef(0),
+					T:   1234567893,
+					H:   &histogram.Histogram{Sum: staleNaN},
+				},
+			}
+			floatHistograms := []record.RefFloatHistogramSample{
+				{
+					Ref: chunks.HeadSeriesRef(0),
+					T:   1234567894,
+					FH: &histogram.FloatHistogram{
+						Schema:          0,
+						ZeroThreshold:   1e-128,
+						Count:           2,
+						Sum:             5.0,
+						PositiveSpans:   []histogram.Span{{Offset: 0, Length: 1}},
+						PositiveBuckets: []float64{2.0},
+					},
+				},
+				{
+					Ref: chunks.HeadSeriesRef(0),
+					T:   1234567895,
+					FH:  &histogram.FloatHistogram{Sum: staleNaN},
+				},
+			}
+
+			// Only the non-stale data must reach the client.
+			c.expectSamples(samples[:1], series)
+			c.expectHistograms(histograms[:1], series)
+			c.expectFloatHistograms(floatHistograms[:1], series)
+
+			m.Start()
+			defer m.Stop()
+
+			require.True(t, m.Append(samples))
+			require.True(t, m.AppendHistograms(histograms))
+			require.True(t, m.AppendFloatHistograms(floatHistograms))
+
+			c.waitForExpectedData(t, 30*time.Second)
+
+			require.Equal(t, 1.0, client_testutil.ToFloat64(m.metrics.droppedSamplesTotal.WithLabelValues(reasonStalenessMarker)))
+			require.Equal(t, 2.0, client_testutil.ToFloat64(m.metrics.droppedHistogramsTotal.WithLabelValues(reasonStalenessMarker)))
+		})
+	}
+}
+
 // TestAppendHistogramsWithStartTimestamp verifies that AppendHistograms and
 // AppendFloatHistograms propagate the per-sample start timestamp end-to-end
 // through the queue manager into a Remote Write 2.0 request. ST is only
@@ -2803,3 +3063,81 @@ func TestAppendHistogramsWithStartTimestamp(t *testing.T) {
 
 	c.waitForExpectedData(t, 30*time.Second)
 }
+
+// TestCompressionNegotiation exercises sending write requests with a
+// non-snappy compression and the automatic fallback to snappy when the
+// receiver rejects the configured compression with HTTP 415.
+func TestCompressionNegotiation(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name          string
+		serverAccepts func(compression.Type) bool
+		expectedCompr compression.Type
+	}{
+		{
+			name:          "zstd accepted",
+			serverAccepts: func(compression.Type) bool { return true },
+			expectedCompr: compression.Zstd,
+		},
+		{
+			name:          "zstd rejected, falls back to snappy",
+			serverAccepts: func(compr compression.Type) bool { return compr == compression.Snappy },
+			expectedCompr: compression.Snappy,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			received := make(chan prompb.WriteRequest, 10)
+			decBuf := compression.NewSyncDecodeBuffer()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				compr := compression.Type(r.Header.Get("Content-Encoding"))
+				if !tc.serverAccepts(compr) {
+					http.Error(w, "unsupported content encoding", http.StatusUnsupportedMediaType)
+					return
+				}
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				decoded, err := compression.Decode(compr, body, decBuf)
+				require.NoError(t, err)
+				var req prompb.WriteRequest
+				require.NoError(t, proto.Unmarshal(decoded, &req))
+				received <- req
+			}))
+			defer server.Close()
+
+			serverURL, err := url.Parse(server.URL)
+			require.NoError(t, err)
+			c, err := NewWriteClient("test", &ClientConfig{
+				URL:         &config_util.URL{URL: serverURL},
+				Timeout:     model.Duration(time.Second),
+				Compression: compression.Zstd,
+			})
+			require.NoError(t, err)
+
+			cfg := testDefaultQueueConfig()
+			cfg.MinBackoff = model.Duration(time.Millisecond)
+			cfg.MaxBackoff = model.Duration(5 * time.Millisecond)
+			mcfg := config.DefaultMetadataConfig
+			metrics := newQueueManagerMetrics(nil, "", "")
+			m := NewQueueManager(metrics, nil, nil, nil, t.TempDir(), newEWMARate(ewmaWeight, shardUpdateDuration), cfg, mcfg, config.SpillBufferConfig{}, labels.EmptyLabels(), nil, c, defaultFlushDeadline, newPool(), newHighestTimestampMetric(), nil, false, false, true, false, remoteapi.WriteV1MessageType, compression.Zstd, record.NewBuffersPool())
+			m.Start()
+			defer m.Stop()
+
+			recs := testwal.GenerateRecords(recCase{NoST: true, Series: 3, SamplesPerSeries: 1})
+			m.StoreSeries(recs.Series, 0)
+			m.Append(recs.Samples)
+
+			deadline := time.After(30 * time.Second)
+			for total := 0; total < len(recs.Samples); {
+				select {
+				case req := <-received:
+					for _, ts := range req.Timeseries {
+						total += len(ts.Samples)
+					}
+				case <-deadline:
+					t.Fatal("timed out waiting for the write request to be delivered")
+				}
+			}
+			require.Equal(t, tc.expectedCompr, m.currentCompression())
+		})
+	}
+}
@@ -260,6 +260,15 @@ func (ssf *seriesSetFilter) SetQuerier(querier storage.Querier) {
 	ssf.querier = querier
 }
 
+// HintsApplied implements storage.HintsAppliedSeriesSet by delegating to the
+// wrapped series set.
+func (ssf seriesSetFilter) HintsApplied() bool {
+	if hs, ok := ssf.SeriesSet.(storage.HintsAppliedSeriesSet); ok {
+		return hs.HintsApplied()
+	}
+	return false
+}
+
 func (ssf seriesSetFilter) At() storage.Series {
 	return seriesFilter{
 		Series:   ssf.SeriesSet.At(),
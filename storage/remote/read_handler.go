@@ -16,11 +16,13 @@ package remote
 import (
 	"context"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -33,27 +35,39 @@ import (
 )
 
 type readHandler struct {
-	logger                    *slog.Logger
-	queryable                 storage.SampleAndChunkQueryable
-	config                    func() config.Config
-	remoteReadSampleLimit     int
-	remoteReadMaxBytesInFrame int
-	remoteReadGate            *gate.Gate
-	queries                   prometheus.Gauge
-	marshalPool               *sync.Pool
+	logger                     *slog.Logger
+	queryable                  storage.SampleAndChunkQueryable
+	config                     func() config.Config
+	remoteReadSampleLimit      int
+	remoteReadMaxBytesInFrame  int
+	remoteReadMaxFrameBacklog  int
+	remoteReadIdleWriteTimeout time.Duration
+	remoteReadGate             *gate.Gate
+	remoteReadStreamGate       *gate.Gate
+	queries                    prometheus.Gauge
+	marshalPool                *sync.Pool
 }
 
 // NewReadHandler creates a http.Handler that accepts remote read requests and
 // writes them to the provided queryable.
-func NewReadHandler(logger *slog.Logger, r prometheus.Registerer, queryable storage.SampleAndChunkQueryable, config func() config.Config, remoteReadSampleLimit, remoteReadConcurrencyLimit, remoteReadMaxBytesInFrame int) http.Handler {
+// remoteReadMaxConcurrentStreams bounds the number of streamed chunk responses
+// served at once, on top of the overall concurrency limit; 0 means no extra
+// limit. remoteReadMaxFrameBacklog bounds the number of response bytes
+// buffered on the server before they are flushed towards the client; 0 means
+// flushing only happens once a query's series are exhausted.
+// remoteReadIdleWriteTimeout aborts a streamed response when the client does
+// not consume any data for that long; 0 disables the timeout.
+func NewReadHandler(logger *slog.Logger, r prometheus.Registerer, queryable storage.SampleAndChunkQueryable, config func() config.Config, remoteReadSampleLimit, remoteReadConcurrencyLimit, remoteReadMaxBytesInFrame, remoteReadMaxConcurrentStreams, remoteReadMaxFrameBacklog int, remoteReadIdleWriteTimeout time.Duration) http.Handler {
 	h := &readHandler{
-		logger:                    logger,
-		queryable:                 queryable,
-		config:                    config,
-		remoteReadSampleLimit:     remoteReadSampleLimit,
-		remoteReadGate:            gate.New(remoteReadConcurrencyLimit),
-		remoteReadMaxBytesInFrame: remoteReadMaxBytesInFrame,
-		marshalPool:               &sync.Pool{},
+		logger:                     logger,
+		queryable:                  queryable,
+		config:                     config,
+		remoteReadSampleLimit:      remoteReadSampleLimit,
+		remoteReadGate:             gate.New(remoteReadConcurrencyLimit),
+		remoteReadMaxBytesInFrame:  remoteReadMaxBytesInFrame,
+		remoteReadMaxFrameBacklog:  remoteReadMaxFrameBacklog,
+		remoteReadIdleWriteTimeout: remoteReadIdleWriteTimeout,
+		marshalPool:                &sync.Pool{},
 
 		queries: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: namespace,
@@ -62,6 +76,9 @@ func NewReadHandler(logger *slog.Logger, r prometheus.Registerer, queryable stor
 			Help:      "The current number of remote read queries that are either in execution or queued on the handler.",
 		}),
 	}
+	if remoteReadMaxConcurrentStreams > 0 {
+		h.remoteReadStreamGate = gate.New(remoteReadMaxConcurrentStreams)
+	}
 	if r != nil {
 		r.MustRegister(h.queries)
 	}
@@ -106,6 +123,13 @@ func (h *readHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	switch responseType {
 	case prompb.ReadRequest_STREAMED_XOR_CHUNKS:
+		if h.remoteReadStreamGate != nil {
+			if err := h.remoteReadStreamGate.Start(ctx); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			defer h.remoteReadStreamGate.Done()
+		}
 		h.remoteReadStreamedXORChunks(ctx, w, req, externalLabels, sortedExternalLabels)
 	default:
 		// On empty or unknown types in req.AcceptedResponseTypes we default to non streamed, raw samples response.
@@ -194,7 +218,15 @@ func (h *readHandler) remoteReadStreamedXORChunks(ctx context.Context, w http.Re
 		return
 	}
 
-	cw := NewChunkedWriter(w, f)
+	var wr io.Writer = w
+	if h.remoteReadIdleWriteTimeout > 0 {
+		wr = &idleTimeoutWriter{
+			rc:      http.NewResponseController(w),
+			w:       w,
+			timeout: h.remoteReadIdleWriteTimeout,
+		}
+	}
+	cw := NewChunkedWriterWithFlushThreshold(wr, f, h.remoteReadMaxFrameBacklog)
 	defer cw.Close()
 
 	for i, query := range req.Queries {
@@ -256,6 +288,22 @@ func (h *readHandler) remoteReadStreamedXORChunks(ctx context.Context, w http.Re
 	}
 }
 
+// idleTimeoutWriter arms a write deadline before every write so a stalled
+// downstream reader fails the response instead of blocking the handler, and
+// with it the chunks held by the open querier, indefinitely.
+type idleTimeoutWriter struct {
+	rc      *http.ResponseController
+	w       io.Writer
+	timeout time.Duration
+}
+
+func (tw *idleTimeoutWriter) Write(b []byte) (int, error) {
+	if err := tw.rc.SetWriteDeadline(time.Now().Add(tw.timeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return 0, err
+	}
+	return tw.w.Write(b)
+}
+
 // filterExtLabelsFromMatchers change equality matchers which match external labels
 // to a matcher that looks for an empty label,
 // as that label should not be present in the storage.
@@ -52,7 +52,7 @@ func TestSampledReadEndpoint(t *testing.T) {
 				ExternalLabels: labels.FromStrings("b", "c", "baz", "a", "d", "e"),
 			},
 		}
-	}, 1e6, 1, 0)
+	}, 1e6, 1, 0, 0, 0, 0)
 
 	// Encode the request.
 	matcher1, err := labels.NewMatcher(labels.MatchEqual, "__name__", "test_metric1")
@@ -177,7 +177,7 @@ func BenchmarkStreamReadEndpoint(b *testing.B) {
 	api := NewReadHandler(nil, nil, store, func() config.Config {
 		return config.Config{}
 	},
-		0, 1, 0,
+		0, 1, 0, 0, 0, 0,
 	)
 
 	matcher, err := labels.NewMatcher(labels.MatchEqual, "__name__", "test_metric1")
@@ -253,6 +253,7 @@ func TestStreamReadEndpoint(t *testing.T) {
 		1e6, 1,
 		// Labelset has 57 bytes. Full chunk in test data has roughly 240 bytes. This allows us to have at max 2 chunks in this test.
 		57+480,
+		0, 0, 0,
 	)
 
 	// Encode the request.
@@ -0,0 +1,308 @@
+// Copyright 2026 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/util/compression"
+)
+
+// errSpilledToDisk signals that a write request could not be delivered within
+// the retry budget and was persisted to the on-disk spill buffer instead.
+var errSpilledToDisk = errors.New("write request spilled to disk")
+
+const (
+	spillFileSuffix = ".spill"
+
+	// spillReplayInterval is how often the replay loop checks whether there
+	// are spilled requests to resend.
+	spillReplayInterval = 15 * time.Second
+)
+
+// Reasons for dropping data from the spill buffer, used as metric label values.
+const (
+	spillDropReasonQuota = "quota"
+	spillDropReasonAge   = "age"
+)
+
+// spillBuffer persists encoded remote write requests on disk so they survive
+// outages that outlast the WAL retention. Each request is stored as one file
+// whose name encodes the time it was spilled, so lexical order is
+// chronological order, and the compression the request was built with, so a
+// replay advertises the right content encoding. The buffer is bounded by a
+// byte quota and an optional maximum age; the oldest requests are dropped
+// first when either is exceeded.
+type spillBuffer struct {
+	logger   *slog.Logger
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+	metrics  *queueManagerMetrics
+
+	mtx  sync.Mutex
+	size int64 // Total bytes currently on disk.
+	seq  int   // Tie-breaker for requests spilled within the same millisecond.
+}
+
+// newSpillBuffer opens the spill buffer rooted at dir, creating the directory
+// if needed. Requests left over from a previous run are kept and accounted
+// for, so the buffer survives restarts.
+func newSpillBuffer(logger *slog.Logger, dir string, maxBytes int64, maxAge time.Duration, metrics *queueManagerMetrics) (*spillBuffer, error) {
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return nil, fmt.Errorf("create spill buffer directory: %w", err)
+	}
+	b := &spillBuffer{
+		logger:   logger,
+		dir:      dir,
+		maxBytes: maxBytes,
+		maxAge:   maxAge,
+		metrics:  metrics,
+	}
+	files, err := b.files()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		fi, err := os.Stat(filepath.Join(dir, f))
+		if err != nil {
+			continue
+		}
+		b.size += fi.Size()
+	}
+	b.metrics.spillBufferBytes.Set(float64(b.size))
+	return b, nil
+}
+
+// files returns the names of all spill files in the buffer directory, oldest
+// first.
+func (b *spillBuffer) files() ([]string, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, fmt.Errorf("list spill buffer directory: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), spillFileSuffix) {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// add persists one write request, encoded with compr. If the request does not
+// fit in the quota even on its own it is dropped and false is returned;
+// otherwise the oldest buffered requests are evicted until it fits.
+func (b *spillBuffer) add(req []byte, compr compression.Type) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if int64(len(req)) > b.maxBytes {
+		b.metrics.spillDroppedBytesTotal.WithLabelValues(spillDropReasonQuota).Add(float64(len(req)))
+		b.logger.Warn("Dropping write request larger than the spill buffer quota", "size", len(req), "max_size", b.maxBytes)
+		return false
+	}
+	if err := b.evictLocked(b.maxBytes - int64(len(req))); err != nil {
+		b.logger.Error("Failed to evict from spill buffer", "err", err)
+		return false
+	}
+
+	name := fmt.Sprintf("%020d-%09d-%s%s", time.Now().UnixMilli(), b.seq, compr, spillFileSuffix)
+	b.seq++
+	if err := os.WriteFile(filepath.Join(b.dir, name), req, 0o666); err != nil {
+		b.logger.Error("Failed to write spill buffer file", "file", name, "err", err)
+		return false
+	}
+	b.size += int64(len(req))
+	b.metrics.spillBufferBytes.Set(float64(b.size))
+	return true
+}
+
+// evictLocked removes the oldest spill files until at most limit bytes remain.
+// The caller must hold b.mtx.
+func (b *spillBuffer) evictLocked(limit int64) error {
+	if b.size <= limit {
+		return nil
+	}
+	files, err := b.files()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if b.size <= limit {
+			break
+		}
+		path := filepath.Join(b.dir, f)
+		fi, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		b.size -= fi.Size()
+		b.metrics.spillDroppedBytesTotal.WithLabelValues(spillDropReasonQuota).Add(float64(fi.Size()))
+		b.logger.Warn("Dropped spilled write request to stay within the spill buffer quota", "file", f)
+	}
+	b.metrics.spillBufferBytes.Set(float64(b.size))
+	return nil
+}
+
+// oldest returns the path, contents and compression of the oldest buffered
+// request, after expiring any requests older than the configured maximum age.
+// It returns an empty path when the buffer is empty.
+func (b *spillBuffer) oldest() (string, []byte, compression.Type, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	files, err := b.files()
+	if err != nil {
+		return "", nil, "", err
+	}
+	for _, f := range files {
+		path := filepath.Join(b.dir, f)
+		if b.maxAge > 0 && b.expiredLocked(f) {
+			fi, err := os.Stat(path)
+			if err == nil {
+				if err := os.Remove(path); err != nil {
+					return "", nil, "", err
+				}
+				b.size -= fi.Size()
+				b.metrics.spillBufferBytes.Set(float64(b.size))
+				b.metrics.spillDroppedBytesTotal.WithLabelValues(spillDropReasonAge).Add(float64(fi.Size()))
+				b.logger.Warn("Dropped spilled write request older than the spill buffer max age", "file", f)
+			}
+			continue
+		}
+		req, err := os.ReadFile(path)
+		if err != nil {
+			return "", nil, "", err
+		}
+		return path, req, spillCompression(f), nil
+	}
+	return "", nil, "", nil
+}
+
+// spillCompression returns the compression encoded in a spill file name.
+// Files written before the compression segment existed default to snappy.
+func spillCompression(name string) compression.Type {
+	parts := strings.Split(strings.TrimSuffix(name, spillFileSuffix), "-")
+	if len(parts) < 3 {
+		return compression.Snappy
+	}
+	return parts[2]
+}
+
+// expiredLocked reports whether the spill file with the given name is older
+// than the configured maximum age, based on the timestamp encoded in its name.
+func (b *spillBuffer) expiredLocked(name string) bool {
+	ts, _, ok := strings.Cut(strings.TrimSuffix(name, spillFileSuffix), "-")
+	if !ok {
+		return false
+	}
+	ms, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+	return time.Since(time.UnixMilli(ms)) > b.maxAge
+}
+
+// remove deletes a spilled request after it has been replayed, or after
+// replaying it failed with a non-recoverable error.
+func (b *spillBuffer) remove(path string) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	b.size -= fi.Size()
+	b.metrics.spillBufferBytes.Set(float64(b.size))
+	return nil
+}
+
+// replaySpillLoop periodically resends spilled write requests, oldest first,
+// until the queue manager is stopped.
+func (t *QueueManager) replaySpillLoop() {
+	defer t.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-t.quit
+		cancel()
+	}()
+
+	ticker := time.NewTicker(spillReplayInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.quit:
+			return
+		case <-ticker.C:
+			t.replaySpill(ctx)
+		}
+	}
+}
+
+// replaySpill drains the spill buffer until it is empty or the remote
+// endpoint reports a recoverable error, in which case the remaining requests
+// are kept for the next attempt.
+func (t *QueueManager) replaySpill(ctx context.Context) {
+	for {
+		path, req, compr, err := t.spill.oldest()
+		if err != nil {
+			t.logger.Error("Failed to read from spill buffer", "err", err)
+			return
+		}
+		if path == "" {
+			return
+		}
+
+		// A spilled request cannot be rebuilt with another compression, so a
+		// rejected content encoding is dropped like any other rejection.
+		if _, err := t.store(ctx, req, 0, compr); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			var recoverable RecoverableError
+			if errors.As(err, &recoverable) {
+				// The endpoint is still unhealthy; try again on the next tick.
+				return
+			}
+			t.logger.Error("Dropping spilled write request rejected by the remote endpoint", "err", err)
+		}
+
+		if err := t.spill.remove(path); err != nil {
+			t.logger.Error("Failed to remove replayed spill buffer file", "file", path, "err", err)
+			return
+		}
+	}
+}
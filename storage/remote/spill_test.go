@@ -0,0 +1,145 @@
+// Copyright 2026 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/units"
+	remoteapi "github.com/prometheus/client_golang/exp/api/remote"
+	client_testutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/promslog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/record"
+	"github.com/prometheus/prometheus/util/compression"
+	"github.com/prometheus/prometheus/util/runutil"
+	"github.com/prometheus/prometheus/util/testwal"
+)
+
+func TestSpillBufferQuota(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	metrics := newQueueManagerMetrics(nil, "", "")
+	b, err := newSpillBuffer(promslog.NewNopLogger(), dir, 100, 0, metrics)
+	require.NoError(t, err)
+
+	first := bytes.Repeat([]byte("a"), 40)
+	second := bytes.Repeat([]byte("b"), 40)
+	third := bytes.Repeat([]byte("c"), 40)
+
+	require.True(t, b.add(first, compression.Snappy))
+	require.True(t, b.add(second, compression.Snappy))
+	require.Equal(t, 80.0, client_testutil.ToFloat64(metrics.spillBufferBytes))
+
+	// The third request does not fit; the oldest one has to go.
+	require.True(t, b.add(third, compression.Snappy))
+	require.Equal(t, 80.0, client_testutil.ToFloat64(metrics.spillBufferBytes))
+	require.Equal(t, 40.0, client_testutil.ToFloat64(metrics.spillDroppedBytesTotal.WithLabelValues(spillDropReasonQuota)))
+
+	path, req, compr, err := b.oldest()
+	require.NoError(t, err)
+	require.Equal(t, second, req)
+	require.Equal(t, compression.Snappy, compr)
+
+	// A request larger than the whole quota is rejected outright.
+	require.False(t, b.add(bytes.Repeat([]byte("d"), 200), compression.Snappy))
+	require.Equal(t, 240.0, client_testutil.ToFloat64(metrics.spillDroppedBytesTotal.WithLabelValues(spillDropReasonQuota)))
+
+	// Reopening the buffer restores the accounting from disk.
+	metrics2 := newQueueManagerMetrics(nil, "", "")
+	b2, err := newSpillBuffer(promslog.NewNopLogger(), dir, 100, 0, metrics2)
+	require.NoError(t, err)
+	require.Equal(t, 80.0, client_testutil.ToFloat64(metrics2.spillBufferBytes))
+
+	require.NoError(t, b2.remove(path))
+	require.Equal(t, 40.0, client_testutil.ToFloat64(metrics2.spillBufferBytes))
+}
+
+func TestSpillBufferMaxAge(t *testing.T) {
+	t.Parallel()
+
+	metrics := newQueueManagerMetrics(nil, "", "")
+	b, err := newSpillBuffer(promslog.NewNopLogger(), t.TempDir(), 100, 10*time.Millisecond, metrics)
+	require.NoError(t, err)
+
+	require.True(t, b.add(bytes.Repeat([]byte("a"), 40), compression.Snappy))
+	time.Sleep(50 * time.Millisecond)
+
+	path, _, _, err := b.oldest()
+	require.NoError(t, err)
+	require.Empty(t, path)
+	require.Equal(t, 0.0, client_testutil.ToFloat64(metrics.spillBufferBytes))
+	require.Equal(t, 40.0, client_testutil.ToFloat64(metrics.spillDroppedBytesTotal.WithLabelValues(spillDropReasonAge)))
+}
+
+func TestQueueManagerSpillAndReplay(t *testing.T) {
+	t.Parallel()
+
+	for _, protoMsg := range []remoteapi.WriteMessageType{remoteapi.WriteV1MessageType, remoteapi.WriteV2MessageType} {
+		t.Run(fmt.Sprint(protoMsg), func(t *testing.T) {
+			cfg := testDefaultQueueConfig()
+			// Keep the backoff short so it saturates quickly and triggers the spill.
+			cfg.MinBackoff = model.Duration(time.Millisecond)
+			cfg.MaxBackoff = model.Duration(5 * time.Millisecond)
+			mcfg := config.DefaultMetadataConfig
+			spillCfg := config.SpillBufferConfig{MaxSize: units.MiB}
+
+			c := NewTestWriteClient(protoMsg)
+			metrics := newQueueManagerMetrics(nil, "", "")
+			m := NewQueueManager(metrics, nil, nil, nil, t.TempDir(), newEWMARate(ewmaWeight, shardUpdateDuration), cfg, mcfg, spillCfg, labels.EmptyLabels(), nil, c, defaultFlushDeadline, newPool(), newHighestTimestampMetric(), nil, false, false, true, false, protoMsg, compression.Snappy, record.NewBuffersPool())
+			m.Start()
+			defer m.Stop()
+
+			recs := testwal.GenerateRecords(recCase{
+				NoST:   protoMsg == remoteapi.WriteV1MessageType, // RW1 does not support ST.
+				Series: 1, SamplesPerSeries: 1,
+			})
+			m.StoreSeries(recs.Series, 0)
+			m.StoreMetadata(recs.Metadata)
+			c.expectSamples(recs.Samples, recs.Series)
+
+			// The endpoint is down; once the backoff saturates, the request must
+			// land in the spill buffer instead of blocking the shard or being
+			// counted as failed.
+			c.SetReturnError(RecoverableError{errors.New("pretend 500"), 0})
+			m.Append(recs.Samples)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			require.NoError(t, runutil.Retry(10*time.Millisecond, ctx.Done(), func() error {
+				if client_testutil.ToFloat64(metrics.spillBufferBytes) == 0 {
+					return errors.New("expected the write request to be spilled to disk")
+				}
+				return nil
+			}))
+			require.Equal(t, 0.0, client_testutil.ToFloat64(metrics.failedSamplesTotal))
+
+			// The endpoint recovers and the spilled request is replayed.
+			c.SetReturnError(nil)
+			m.replaySpill(context.Background())
+			c.waitForExpectedData(t, 5*time.Second)
+			require.Equal(t, 0.0, client_testutil.ToFloat64(metrics.spillBufferBytes))
+		})
+	}
+}
@@ -14,10 +14,12 @@
 package remote
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
 	"math"
+	"slices"
 	"sync"
 	"time"
 
@@ -67,6 +69,9 @@ type WriteStorage struct {
 	externalLabels    labels.Labels
 	dir               string
 	queues            map[string]*QueueManager
+	primaryHash       string
+	queueGroups       map[string]string
+	groupQuorums      map[string]int
 	samplesIn         *ewmaRate
 	flushDeadline     time.Duration
 	interner          *pool
@@ -151,15 +156,35 @@ func (rws *WriteStorage) ApplyConfig(conf *config.Config) error {
 
 	newQueues := make(map[string]*QueueManager)
 	newHashes := []string{}
+	primaryHash := ""
+	queueGroups := make(map[string]string)
+	groupQuorums := make(map[string]int)
 	for _, rwConf := range conf.RemoteWriteConfigs {
 		hash, err := toHash(rwConf)
 		if err != nil {
 			return err
 		}
+		if rwConf.Primary {
+			primaryHash = hash
+		}
+		if r := rwConf.Replication; r != nil {
+			queueGroups[hash] = r.Group
+			groupQuorums[r.Group] = r.Quorum
+		}
+
+		// Redacted to remove any passwords in the URL (that are
+		// technically accepted but not recommended) since this is
+		// only used for metric labels.
+		var endpoint string
+		if rwConf.KafkaConfig != nil {
+			endpoint = rwConf.KafkaConfig.Endpoint()
+		} else {
+			endpoint = rwConf.URL.Redacted()
+		}
 
 		// Don't allow duplicate remote write configs.
 		if _, ok := newQueues[hash]; ok {
-			return fmt.Errorf("duplicate remote write configs are not allowed, found duplicate for URL: %s", rwConf.URL)
+			return fmt.Errorf("duplicate remote write configs are not allowed, found duplicate for endpoint: %s", endpoint)
 		}
 
 		// Set the queue name to the config hash if the user has not set
@@ -170,18 +195,24 @@ func (rws *WriteStorage) ApplyConfig(conf *config.Config) error {
 			name = rwConf.Name
 		}
 
-		c, err := NewWriteClient(name, &ClientConfig{
-			URL:              rwConf.URL,
-			WriteProtoMsg:    rwConf.ProtobufMessage,
-			Timeout:          rwConf.RemoteTimeout,
-			HTTPClientConfig: rwConf.HTTPClientConfig,
-			SigV4Config:      rwConf.SigV4Config,
-			AzureADConfig:    rwConf.AzureADConfig,
-			GoogleIAMConfig:  rwConf.GoogleIAMConfig,
-			Headers:          rwConf.Headers,
-			RetryOnRateLimit: rwConf.QueueConfig.RetryOnRateLimit,
-			RoundRobinDNS:    rwConf.RoundRobinDNS,
-		})
+		var c WriteClient
+		if rwConf.KafkaConfig != nil {
+			c, err = NewKafkaWriteClient(name, rwConf.KafkaConfig, time.Duration(rwConf.RemoteTimeout))
+		} else {
+			c, err = NewWriteClient(name, &ClientConfig{
+				URL:              rwConf.URL,
+				WriteProtoMsg:    rwConf.ProtobufMessage,
+				Timeout:          rwConf.RemoteTimeout,
+				HTTPClientConfig: rwConf.HTTPClientConfig,
+				SigV4Config:      rwConf.SigV4Config,
+				AzureADConfig:    rwConf.AzureADConfig,
+				GoogleIAMConfig:  rwConf.GoogleIAMConfig,
+				Headers:          rwConf.Headers,
+				RetryOnRateLimit: rwConf.QueueConfig.RetryOnRateLimit,
+				RoundRobinDNS:    rwConf.RoundRobinDNS,
+				Compression:      rwConf.Compression,
+			})
+		}
 		if err != nil {
 			return err
 		}
@@ -195,10 +226,6 @@ func (rws *WriteStorage) ApplyConfig(conf *config.Config) error {
 			continue
 		}
 
-		// Redacted to remove any passwords in the URL (that are
-		// technically accepted but not recommended) since this is
-		// only used for metric labels.
-		endpoint := rwConf.URL.Redacted()
 		newQueues[hash] = NewQueueManager(
 			newQueueManagerMetrics(rws.reg, name, endpoint),
 			rws.watcherMetrics,
@@ -208,6 +235,7 @@ func (rws *WriteStorage) ApplyConfig(conf *config.Config) error {
 			rws.samplesIn,
 			rwConf.QueueConfig,
 			rwConf.MetadataConfig,
+			rwConf.SpillBuffer,
 			conf.GlobalConfig.ExternalLabels,
 			rwConf.WriteRelabelConfigs,
 			c,
@@ -217,8 +245,10 @@ func (rws *WriteStorage) ApplyConfig(conf *config.Config) error {
 			rws.scraper,
 			rwConf.SendExemplars,
 			rwConf.SendNativeHistograms,
+			rwConf.SendStalenessMarkers,
 			rws.enableTypeAndUnitLabels,
 			rwConf.ProtobufMessage,
+			rwConf.Compression,
 			rws.recordBuf,
 		)
 		// Keep track of which queues are new so we know which to start.
@@ -236,6 +266,9 @@ func (rws *WriteStorage) ApplyConfig(conf *config.Config) error {
 	}
 
 	rws.queues = newQueues
+	rws.primaryHash = primaryHash
+	rws.queueGroups = queueGroups
+	rws.groupQuorums = groupQuorums
 
 	return nil
 }
@@ -261,18 +294,38 @@ func (rws *WriteStorage) AppenderV2(context.Context) storage.AppenderV2 {
 }
 
 // LowestSentTimestamp returns the lowest sent timestamp across all queues.
+// If one of the queues is marked as primary, only its sent timestamp is
+// considered: the remaining queues are best-effort and must not hold back
+// WAL truncation in agent mode. Queues belonging to a replication group
+// contribute the timestamp acknowledged by the group's quorum rather than
+// their individual timestamps, so laggards beyond the quorum catch up from
+// the WAL on a best-effort basis.
 func (rws *WriteStorage) LowestSentTimestamp() int64 {
 	rws.mtx.Lock()
 	defer rws.mtx.Unlock()
 
+	if q, ok := rws.queues[rws.primaryHash]; ok {
+		return int64(q.metrics.highestSentTimestamp.Get() * 1000)
+	}
+
 	var lowestTs int64 = math.MaxInt64
 
-	for _, q := range rws.queues {
+	groupTss := make(map[string][]int64)
+	for hash, q := range rws.queues {
 		ts := int64(q.metrics.highestSentTimestamp.Get() * 1000)
+		if group, ok := rws.queueGroups[hash]; ok {
+			groupTss[group] = append(groupTss[group], ts)
+			continue
+		}
 		if ts < lowestTs {
 			lowestTs = ts
 		}
 	}
+	for group, tss := range groupTss {
+		if ts := quorumTimestamp(tss, rws.groupQuorums[group]); ts < lowestTs {
+			lowestTs = ts
+		}
+	}
 	if len(rws.queues) == 0 {
 		lowestTs = 0
 	}
@@ -280,6 +333,18 @@ func (rws *WriteStorage) LowestSentTimestamp() int64 {
 	return lowestTs
 }
 
+// quorumTimestamp returns the highest timestamp that at least quorum of the
+// given members have reached.
+func quorumTimestamp(tss []int64, quorum int) int64 {
+	slices.SortFunc(tss, func(a, b int64) int { return cmp.Compare(b, a) })
+	if quorum > len(tss) {
+		// Config validation guarantees the quorum fits the group, but a
+		// group can temporarily be smaller while a reload is in flight.
+		quorum = len(tss)
+	}
+	return tss[max(quorum, 1)-1]
+}
+
 // Close closes the WriteStorage.
 func (rws *WriteStorage) Close() error {
 	rws.mtx.Lock()
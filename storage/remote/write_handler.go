@@ -52,6 +52,11 @@ type writeHandler struct {
 
 const maxAheadTime = 10 * time.Minute
 
+// SampleOriginHeader is an optional remote write request header declaring the
+// provenance of the contained samples, either "primary" or "replicated". It
+// is stored as a dedup hint for highly-available setups.
+const SampleOriginHeader = "X-Prometheus-Sample-Origin"
+
 // NewWriteHandler creates a http.Handler that accepts remote write requests with
 // the given message in acceptedMsgs and writes them to the provided appendable.
 //
@@ -100,6 +105,17 @@ func (h *writeHandler) Store(r *http.Request, msgType remoteapi.WriteMessageType
 		return nil, err
 	}
 
+	ctx := r.Context()
+	if v := r.Header.Get(SampleOriginHeader); v != "" {
+		origin, err := storage.ParseSampleOrigin(v)
+		if err != nil {
+			wr := remoteapi.NewWriteResponse()
+			wr.SetStatusCode(http.StatusBadRequest)
+			return wr, err
+		}
+		ctx = storage.ContextWithSampleOrigin(ctx, origin)
+	}
+
 	wr := remoteapi.NewWriteResponse()
 	if msgType == remoteapi.WriteV1MessageType {
 		// PRW 1.0 flow has different proto message and no partial write handling.
@@ -110,7 +126,7 @@ func (h *writeHandler) Store(r *http.Request, msgType remoteapi.WriteMessageType
 			wr.SetStatusCode(http.StatusBadRequest)
 			return wr, err
 		}
-		if err = h.write(r.Context(), &req); err != nil {
+		if err = h.write(ctx, &req); err != nil {
 			switch {
 			case errors.Is(err, storage.ErrOutOfOrderSample), errors.Is(err, storage.ErrOutOfBounds), errors.Is(err, storage.ErrDuplicateSampleForTimestamp), errors.Is(err, storage.ErrTooOldSample):
 				// Indicated an out-of-order sample is a bad request to prevent retries.
@@ -136,7 +152,7 @@ func (h *writeHandler) Store(r *http.Request, msgType remoteapi.WriteMessageType
 		return wr, err
 	}
 
-	respStats, errHTTPCode, err := h.writeV2(r.Context(), &req)
+	respStats, errHTTPCode, err := h.writeV2(ctx, &req)
 	// Add stats required X-Prometheus-Remote-Write-Written-* response headers.
 	wr.Add(respStats)
 	if err != nil {
@@ -121,6 +121,26 @@ func TestRemoteWriteHandlerHeadersHandling_V1Message(t *testing.T) {
 			},
 			expectedCode: http.StatusUnsupportedMediaType,
 		},
+		{
+			name: "valid sample origin",
+			reqHeaders: map[string]string{
+				"Content-Type":           remoteWriteContentTypeHeaders[remoteapi.WriteV1MessageType],
+				"Content-Encoding":       compression.Snappy,
+				RemoteWriteVersionHeader: RemoteWriteVersion20HeaderValue,
+				SampleOriginHeader:       "replicated",
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name: "invalid sample origin",
+			reqHeaders: map[string]string{
+				"Content-Type":           remoteWriteContentTypeHeaders[remoteapi.WriteV1MessageType],
+				"Content-Encoding":       compression.Snappy,
+				RemoteWriteVersionHeader: RemoteWriteVersion20HeaderValue,
+				SampleOriginHeader:       "yolo",
+			},
+			expectedCode: http.StatusBadRequest,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			req, err := http.NewRequest(http.MethodPost, "", bytes.NewReader(payload))
@@ -41,6 +41,13 @@ import (
 type OTLPOptions struct {
 	// Convert delta samples to their cumulative equivalent by aggregating in-memory
 	ConvertDelta bool
+	// ConvertDeltaMaxStale is the time after which a delta stream is dropped
+	// from the conversion state when no new samples arrive for it. Zero keeps
+	// the converter's default.
+	ConvertDeltaMaxStale time.Duration
+	// ConvertDeltaMaxStreams limits the number of delta streams tracked by the
+	// conversion state at once. Zero means no limit.
+	ConvertDeltaMaxStreams int
 	// Store the raw delta samples as metrics with unknown type (we don't have a proper type for delta yet, therefore
 	// marking the metric type as unknown for now).
 	// We're in an early phase of implementing delta support (proposal: https://github.com/prometheus/proposals/pull/48/)
@@ -83,7 +90,14 @@ func NewOTLPWriteHandler(logger *slog.Logger, reg prometheus.Registerer, appenda
 			ID:                component.NewID(fac.Type()),
 			TelemetrySettings: component.TelemetrySettings{MeterProvider: noop.NewMeterProvider()},
 		}
-		d2c, err := fac.CreateMetrics(context.Background(), set, fac.CreateDefaultConfig(), wh.defaultConsumer)
+		d2cCfg := fac.CreateDefaultConfig().(*deltatocumulative.Config)
+		if opts.ConvertDeltaMaxStale > 0 {
+			d2cCfg.MaxStale = opts.ConvertDeltaMaxStale
+		}
+		if opts.ConvertDeltaMaxStreams > 0 {
+			d2cCfg.MaxStreams = opts.ConvertDeltaMaxStreams
+		}
+		d2c, err := fac.CreateMetrics(context.Background(), set, d2cCfg, wh.defaultConsumer)
 		if err != nil {
 			// fac.CreateMetrics directly calls [deltatocumulativeprocessor.createMetricsProcessor],
 			// which only errors if:
@@ -468,6 +468,64 @@ func TestOTLPDelta(t *testing.T) {
 	}
 }
 
+func TestOTLPDeltaMaxStreams(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	appendable := teststorage.NewAppendable()
+	cfg := func() config.Config {
+		return config.Config{OTLPConfig: config.DefaultOTLPConfig}
+	}
+	handler := NewOTLPWriteHandler(log, nil, appendable, cfg, OTLPOptions{
+		ConvertDelta:           true,
+		ConvertDeltaMaxStreams: 1,
+	})
+
+	ts := time.Date(2000, 1, 2, 3, 4, 0, 0, time.UTC)
+	send := func(instance string) {
+		md := pmetric.NewMetrics()
+		ms := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+
+		m := ms.AppendEmpty()
+		m.SetName("some.delta.total")
+
+		sum := m.SetEmptySum()
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+
+		dp := sum.DataPoints().AppendEmpty()
+		dp.SetIntValue(1)
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+		dp.Attributes().PutStr("instance", instance)
+
+		proto, err := pmetricotlp.NewExportRequestFromMetrics(md).MarshalProto()
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("", "", bytes.NewReader(proto))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-protobuf")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	}
+
+	// The first stream takes the only tracked slot, so the second stream is
+	// dropped by the conversion state.
+	send("a")
+	send("b")
+
+	want := []sample{
+		{
+			MF: "some_delta_total",
+			M:  metadata.Metadata{Type: model.MetricTypeGauge},
+			T:  ts.UnixMilli(),
+			L:  labels.FromStrings("__name__", "some_delta_total", "instance", "a"),
+			V:  1,
+		},
+	}
+	if diff := cmp.Diff(want, appendable.ResultSamples(), cmp.Exporter(func(reflect.Type) bool { return true })); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
 func BenchmarkOTLP(b *testing.B) {
 	start := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
 
@@ -0,0 +1,192 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/route"
+
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/storage"
+)
+
+type openMetricsPushHandler struct {
+	logger     *slog.Logger
+	appendable storage.Appendable
+
+	samplesAppendedTotal prometheus.Counter
+}
+
+// NewOpenMetricsPushHandler creates a http.Handler that accepts OpenMetrics
+// text pushes and appends their samples to the provided appendable.
+//
+// Grouping follows the Pushgateway URL convention: the path below the mount
+// point must be of the form /job/<job>, optionally followed by further
+// /<label>/<value> pairs, and the grouping labels overwrite labels with the
+// same name parsed from the body. Unlike the Pushgateway, pushes are
+// append-only: every pushed sample is appended with its own timestamp (the
+// time of the push for samples without an explicit one) and no state is kept
+// between pushes.
+//
+// The handler must be registered on a route whose trailing catch-all
+// parameter is named "labels".
+func NewOpenMetricsPushHandler(logger *slog.Logger, reg prometheus.Registerer, appendable storage.Appendable) http.Handler {
+	return &openMetricsPushHandler{
+		logger:     logger,
+		appendable: appendable,
+		samplesAppendedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: "prometheus",
+			Subsystem: "api",
+			Name:      "openmetrics_push_appended_samples_total",
+			Help:      "The total number of samples appended through the OpenMetrics push endpoint.",
+		}),
+	}
+}
+
+// parsePushGroupingLabels parses Pushgateway-style grouping labels from the
+// trailing part of a push URL, e.g. "/job/batch/instance/host1".
+func parsePushGroupingLabels(path string) (labels.Labels, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "job" {
+		return labels.EmptyLabels(), errors.New("push path must start with /job/<job>")
+	}
+	if len(parts)%2 != 0 {
+		return labels.EmptyLabels(), errors.New("push path must consist of label name and value pairs")
+	}
+	b := labels.NewBuilder(labels.EmptyLabels())
+	for i := 0; i < len(parts); i += 2 {
+		name, value := parts[i], parts[i+1]
+		if name == labels.MetricName || !model.LabelName(name).IsValid() {
+			return labels.EmptyLabels(), fmt.Errorf("invalid grouping label name %q", name)
+		}
+		if value == "" {
+			return labels.EmptyLabels(), fmt.Errorf("empty value for grouping label %q", name)
+		}
+		b.Set(name, value)
+	}
+	return b.Labels(), nil
+}
+
+func (h *openMetricsPushHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	groupings, err := parsePushGroupingLabels(route.Param(r.Context(), "labels"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Error("Error reading OpenMetrics push request body", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	code, err := h.push(r.Context(), groupings, body, timestamp.FromTime(time.Now()))
+	if err != nil {
+		h.logger.Error("Error ingesting OpenMetrics push", "job", groupings.Get(model.JobLabel), "err", err.Error())
+		http.Error(w, err.Error(), code)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// push parses body as OpenMetrics text and appends all samples with the
+// grouping labels applied. Samples without a timestamp get defaultTimestamp.
+// In error cases it returns the HTTP status code to respond with.
+func (h *openMetricsPushHandler) push(ctx context.Context, groupings labels.Labels, body []byte, defaultTimestamp int64) (_ int, err error) {
+	app := &remoteWriteAppender{
+		Appender: h.appendable.Appender(ctx),
+		maxTime:  timestamp.FromTime(time.Now().Add(maxAheadTime)),
+	}
+
+	samplesAppended := 0
+	defer func() {
+		if err != nil {
+			_ = app.Rollback()
+			return
+		}
+		if err = app.Commit(); err != nil {
+			return
+		}
+		h.samplesAppendedTotal.Add(float64(samplesAppended))
+	}()
+
+	var (
+		l  labels.Labels
+		e  exemplar.Exemplar
+		lb = labels.NewBuilder(labels.EmptyLabels())
+		p  = textparse.NewOpenMetricsParser(body, labels.NewSymbolTable())
+	)
+	for {
+		entry, err := p.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return http.StatusBadRequest, fmt.Errorf("decode pushed OpenMetrics data: %w", err)
+		}
+		if entry != textparse.EntrySeries {
+			continue
+		}
+
+		_, ts, v := p.Series()
+		t := defaultTimestamp
+		if ts != nil {
+			t = *ts
+		}
+
+		p.Labels(&l)
+		lb.Reset(l)
+		groupings.Range(func(gl labels.Label) {
+			lb.Set(gl.Name, gl.Value)
+		})
+		lbls := lb.Labels()
+
+		if _, err := app.Append(0, lbls, t, v); err != nil {
+			if errors.Is(err, storage.ErrOutOfOrderSample) ||
+				errors.Is(err, storage.ErrOutOfBounds) ||
+				errors.Is(err, storage.ErrDuplicateSampleForTimestamp) ||
+				errors.Is(err, storage.ErrTooOldSample) {
+				return http.StatusBadRequest, err
+			}
+			return http.StatusInternalServerError, err
+		}
+		samplesAppended++
+
+		for p.Exemplar(&e) {
+			if !e.HasTs {
+				e.Ts = t
+			}
+			if _, err := app.AppendExemplar(0, lbls, e); err != nil {
+				// Since exemplar storage is still experimental, we don't fail the push on ingestion errors.
+				h.logger.Debug("Error while adding exemplar from push", "series", lbls.String(), "exemplar", fmt.Sprintf("%+v", e), "err", err)
+			}
+		}
+	}
+	return http.StatusNoContent, nil
+}
@@ -0,0 +1,139 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/promslog"
+	"github.com/prometheus/common/route"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+)
+
+// pushMetrics pushes body to an OpenMetrics push handler registered the same
+// way as in the API and returns the response.
+func pushMetrics(t *testing.T, appendable *mockAppendable, path, body string) *http.Response {
+	t.Helper()
+
+	handler := NewOpenMetricsPushHandler(promslog.NewNopLogger(), nil, appendable)
+	router := route.New()
+	router.Post("/push/metrics/*labels", handler.ServeHTTP)
+
+	req, err := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder.Result()
+}
+
+func TestOpenMetricsPushHandler(t *testing.T) {
+	appendable := &mockAppendable{}
+	before := timestamp.FromTime(time.Now())
+	resp := pushMetrics(t, appendable, "/push/metrics/job/batch/instance/host1", `# TYPE duration_seconds gauge
+duration_seconds 42.5
+# TYPE processed_total counter
+processed_total{instance="overridden",shard="a"} 7 120.5
+processed_total{shard="b"} 3 121 # {trace_id="abc"} 3 121
+# EOF
+`)
+	after := timestamp.FromTime(time.Now())
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	require.Len(t, appendable.samples, 3)
+
+	// The sample without a timestamp gets the time of the push.
+	s := appendable.samples[0]
+	requireEqual(t, labels.FromStrings("__name__", "duration_seconds", "job", "batch", "instance", "host1"), s.l)
+	require.Equal(t, 42.5, s.v)
+	require.GreaterOrEqual(t, s.t, before)
+	require.LessOrEqual(t, s.t, after)
+
+	// Grouping labels override labels from the pushed body.
+	requireEqual(t, mockSample{
+		l: labels.FromStrings("__name__", "processed_total", "job", "batch", "instance", "host1", "shard", "a"),
+		t: 120500,
+		v: 7,
+	}, appendable.samples[1])
+	requireEqual(t, mockSample{
+		l: labels.FromStrings("__name__", "processed_total", "job", "batch", "instance", "host1", "shard", "b"),
+		t: 121000,
+		v: 3,
+	}, appendable.samples[2])
+
+	require.Len(t, appendable.exemplars, 1)
+	requireEqual(t, mockExemplar{
+		l:  labels.FromStrings("__name__", "processed_total", "job", "batch", "instance", "host1", "shard", "b"),
+		el: labels.FromStrings("trace_id", "abc"),
+		t:  121000,
+		v:  3,
+	}, appendable.exemplars[0])
+}
+
+func TestOpenMetricsPushHandler_Errors(t *testing.T) {
+	const validBody = "some_metric 1 100\n# EOF\n"
+
+	for _, tc := range []struct {
+		name         string
+		path         string
+		body         string
+		expectedCode int
+	}{
+		{
+			name:         "missing job grouping label",
+			path:         "/push/metrics/instance/host1",
+			body:         validBody,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "grouping label without value",
+			path:         "/push/metrics/job/batch/instance",
+			body:         validBody,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid grouping label name",
+			path:         "/push/metrics/job/batch/__name__/foo",
+			body:         validBody,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "body without EOF marker",
+			path:         "/push/metrics/job/batch",
+			body:         "some_metric 1 100\n",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "body not parseable as OpenMetrics",
+			path:         "/push/metrics/job/batch",
+			body:         "some_metric one hundred\n# EOF\n",
+			expectedCode: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			appendable := &mockAppendable{}
+			resp := pushMetrics(t, appendable, tc.path, tc.body)
+			require.Equal(t, tc.expectedCode, resp.StatusCode)
+			// Failed pushes must not leave partial data behind.
+			require.Empty(t, appendable.samples)
+		})
+	}
+}
@@ -86,7 +86,7 @@ func TestWriteStorageApplyConfig_NoDuplicateWriteConfigs(t *testing.T) {
 	}{
 		{ // Two duplicates, we should get an error.
 			cfgs:        []*config.RemoteWriteConfig{&cfg1, &cfg1},
-			expectedErr: errors.New("duplicate remote write configs are not allowed, found duplicate for URL: http://localhost"),
+			expectedErr: errors.New("duplicate remote write configs are not allowed, found duplicate for endpoint: http://localhost"),
 		},
 		{ // Duplicates but with different names, we should not get an error.
 			cfgs: []*config.RemoteWriteConfig{&cfg1, &cfg2},
@@ -96,7 +96,7 @@ func TestWriteStorageApplyConfig_NoDuplicateWriteConfigs(t *testing.T) {
 		},
 		{ // Duplicates both with no name, we should get an error.
 			cfgs:        []*config.RemoteWriteConfig{&cfg3, &cfg3},
-			expectedErr: errors.New("duplicate remote write configs are not allowed, found duplicate for URL: http://localhost"),
+			expectedErr: errors.New("duplicate remote write configs are not allowed, found duplicate for endpoint: http://localhost"),
 		},
 	} {
 		t.Run("", func(t *testing.T) {
@@ -373,3 +373,86 @@ func TestWriteStorage_CanRegisterMetricsAfterClosing(t *testing.T) {
 	require.NoError(t, s.Close())
 	require.NotPanics(t, func() { NewWriteStorage(nil, reg, dir, time.Millisecond, nil, false) })
 }
+
+func TestWriteStorageLowestSentTimestamp(t *testing.T) {
+	newCfg := func(name string, primary bool, replication *config.ReplicationConfig) *config.RemoteWriteConfig {
+		return &config.RemoteWriteConfig{
+			Name: name,
+			URL: &common_config.URL{
+				URL: &url.URL{
+					Scheme: "http",
+					Host:   "localhost",
+				},
+			},
+			QueueConfig:     config.DefaultQueueConfig,
+			ProtobufMessage: remoteapi.WriteV1MessageType,
+			Primary:         primary,
+			Replication:     replication,
+		}
+	}
+	euQuorum2 := &config.ReplicationConfig{Group: "eu", Quorum: 2}
+
+	for _, tc := range []struct {
+		name     string
+		cfgs     []*config.RemoteWriteConfig
+		sent     map[string]float64 // Queue name to sent timestamp in seconds.
+		expected int64
+	}{
+		{
+			// With no primary the slowest endpoint wins, so the WAL is
+			// retained until every endpoint has acknowledged the data.
+			name:     "no primary",
+			cfgs:     []*config.RemoteWriteConfig{newCfg("a", false, nil), newCfg("b", false, nil)},
+			sent:     map[string]float64{"a": 10, "b": 5},
+			expected: 5000,
+		},
+		{
+			// With a primary only its progress counts; the lagging
+			// secondary is best-effort and does not hold back truncation.
+			name:     "primary ahead of secondary",
+			cfgs:     []*config.RemoteWriteConfig{newCfg("a", true, nil), newCfg("b", false, nil)},
+			sent:     map[string]float64{"a": 10, "b": 5},
+			expected: 10000,
+		},
+		{
+			// A replication group contributes the timestamp its quorum has
+			// reached: the laggard beyond the quorum does not count.
+			name: "quorum of replication group",
+			cfgs: []*config.RemoteWriteConfig{
+				newCfg("a", false, euQuorum2),
+				newCfg("b", false, euQuorum2),
+				newCfg("c", false, euQuorum2),
+			},
+			sent:     map[string]float64{"a": 10, "b": 8, "c": 5},
+			expected: 8000,
+		},
+		{
+			// An ungrouped endpoint lagging behind the group's quorum still
+			// holds back truncation on its own.
+			name: "replication group mixed with ungrouped endpoint",
+			cfgs: []*config.RemoteWriteConfig{
+				newCfg("a", false, euQuorum2),
+				newCfg("b", false, euQuorum2),
+				newCfg("c", false, nil),
+			},
+			sent:     map[string]float64{"a": 10, "b": 8, "c": 5},
+			expected: 5000,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewWriteStorage(nil, nil, t.TempDir(), time.Millisecond, nil, false)
+			conf := &config.Config{
+				GlobalConfig:       config.DefaultGlobalConfig,
+				RemoteWriteConfigs: tc.cfgs,
+			}
+			require.NoError(t, s.ApplyConfig(conf))
+
+			for _, q := range s.queues {
+				q.metrics.highestSentTimestamp.Set(tc.sent[q.client().Name()])
+			}
+			require.Equal(t, tc.expected, s.LowestSentTimestamp())
+
+			require.NoError(t, s.Close())
+		})
+	}
+}
@@ -56,6 +56,7 @@ var (
 	DefaultTruncateFrequency = 2 * time.Hour
 	DefaultMinWALTime        = int64(5 * time.Minute / time.Millisecond)
 	DefaultMaxWALTime        = int64(4 * time.Hour / time.Millisecond)
+	DefaultQueryableWindow   = int64(15 * time.Minute / time.Millisecond)
 )
 
 // Options of the WAL storage.
@@ -84,6 +85,11 @@ type Options struct {
 	// OutOfOrderTimeWindow specifies how much out of order is allowed, if any.
 	OutOfOrderTimeWindow int64
 
+	// QueryableWindow is the amount of the most recent data, in milliseconds,
+	// that is additionally kept in memory per series and served by Querier.
+	// 0 or less disables local querying and Querier returns ErrUnsupported.
+	QueryableWindow int64
+
 	// EnableSTAsZeroSample represents 'created-timestamp-zero-ingestion' feature flag.
 	// If true, ST, if non-empty and earlier than sample timestamp, will be stored
 	// as a zero sample before the actual sample.
@@ -100,6 +106,11 @@ type Options struct {
 	// supported record types.
 	EnableSTStorage bool
 
+	// EnableWALSampleGrouping determines whether float sample WAL records use
+	// the grouped SamplesV3 encoding.
+	// Controlled by the `--enable-feature=wal-sample-grouping` CLI flag.
+	EnableWALSampleGrouping bool
+
 	// CheckpointFromInMemorySeries changes checkpoint implementation to use only in-memory series data when building a checkpoint.
 	// This prevents re-reading the previous checkpoint and segments from disk.
 	CheckpointFromInMemorySeries bool
@@ -406,6 +417,9 @@ func validateOptions(opts *Options) *Options {
 	if t := int64(opts.TruncateFrequency / time.Millisecond); opts.MaxWALTime < t {
 		opts.MaxWALTime = t
 	}
+	if opts.QueryableWindow < 0 {
+		opts.QueryableWindow = 0
+	}
 	return opts
 }
 
@@ -506,7 +520,7 @@ func (db *DB) loadWAL(r *wlog.Reader, duplicateRefToValidRef map[chunks.HeadSeri
 					return
 				}
 				decoded <- series
-			case record.Samples, record.SamplesV2:
+			case record.Samples, record.SamplesV2, record.SamplesV3:
 				samples := db.walReplaySamplesPool.Get()[:0]
 				samples, err = dec.Samples(rec, samples)
 				if err != nil {
@@ -617,6 +631,9 @@ func (db *DB) loadWAL(r *wlog.Reader, duplicateRefToValidRef map[chunks.HeadSeri
 				if entry.T > series.lastTs {
 					series.lastTs = entry.T
 				}
+				if w := db.opts.QueryableWindow; w > 0 {
+					series.appendQuerySample(querySample{st: entry.ST, t: entry.T, f: entry.V}, w)
+				}
 			}
 			db.walReplaySamplesPool.Put(v)
 		case []record.RefHistogramSample:
@@ -640,6 +657,9 @@ func (db *DB) loadWAL(r *wlog.Reader, duplicateRefToValidRef map[chunks.HeadSeri
 				if entry.T > series.lastTs {
 					series.lastTs = entry.T
 				}
+				if w := db.opts.QueryableWindow; w > 0 {
+					series.appendQuerySample(querySample{st: entry.ST, t: entry.T, h: entry.H.Copy()}, w)
+				}
 			}
 			clear(v) // Zero out to avoid retaining histogram data.
 			db.walReplayHistogramsPool.Put(v[:0])
@@ -664,6 +684,9 @@ func (db *DB) loadWAL(r *wlog.Reader, duplicateRefToValidRef map[chunks.HeadSeri
 				if entry.T > series.lastTs {
 					series.lastTs = entry.T
 				}
+				if w := db.opts.QueryableWindow; w > 0 {
+					series.appendQuerySample(querySample{st: entry.ST, t: entry.T, fh: entry.FH.Copy()}, w)
+				}
 			}
 			clear(v) // Zero out to avoid retaining histogram data.
 			db.walReplayFloatHistogramsPool.Put(v[:0])
@@ -705,6 +728,10 @@ Loop:
 			//
 			// Subtracting a duration from ts will add a buffer for when series are
 			// considered inactive and safe for deletion.
+			//
+			// The lowest sent timestamp considers all remote write endpoints,
+			// unless one of them is marked as primary, in which case only the
+			// primary gates truncation and the others are best-effort.
 			ts := max(db.rs.LowestSentTimestamp()-db.opts.MinWALTime, 0)
 
 			// Network issues can prevent the result of getRemoteWriteTimestamp from
@@ -842,9 +869,14 @@ func (*DB) StartTime() (int64, error) {
 	return int64(model.Latest), nil
 }
 
-// Querier implements the Storage interface.
-func (*DB) Querier(int64, int64) (storage.Querier, error) {
-	return nil, ErrUnsupported
+// Querier implements the Storage interface. It serves the bounded in-memory
+// query buffer when Options.QueryableWindow is enabled and is unsupported
+// otherwise.
+func (db *DB) Querier(mint, maxt int64) (storage.Querier, error) {
+	if db.opts.QueryableWindow <= 0 {
+		return nil, ErrUnsupported
+	}
+	return &querier{db: db, mint: mint, maxt: maxt}, nil
 }
 
 // ChunkQuerier implements the Storage interface.
@@ -1223,7 +1255,7 @@ func (a *appenderBase) log() error {
 	a.mtx.RLock()
 	defer a.mtx.RUnlock()
 
-	encoder := record.Encoder{EnableSTStorage: a.opts.EnableSTStorage}
+	encoder := record.Encoder{EnableSTStorage: a.opts.EnableSTStorage, EnableSampleGrouping: a.opts.EnableWALSampleGrouping}
 	buf := a.bufPool.Get().([]byte)
 	defer func() {
 		a.bufPool.Put(buf) //nolint:staticcheck
@@ -1289,24 +1321,36 @@ func (a *appenderBase) log() error {
 		buf = buf[:0]
 	}
 
+	window := a.opts.QueryableWindow
 	var series *memSeries
 	for i, s := range a.pendingSamples {
 		series = a.sampleSeries[i]
 		if !series.updateTimestamp(s.T) {
 			a.metrics.totalOutOfOrderSamples.Inc()
 		}
+		if window > 0 {
+			series.appendQuerySample(querySample{st: s.ST, t: s.T, f: s.V}, window)
+		}
 	}
 	for i, s := range a.pendingHistograms {
 		series = a.histogramSeries[i]
 		if !series.updateTimestamp(s.T) {
 			a.metrics.totalOutOfOrderSamples.Inc()
 		}
+		if window > 0 {
+			// Copy the histogram; the caller may reuse it after Commit.
+			series.appendQuerySample(querySample{st: s.ST, t: s.T, h: s.H.Copy()}, window)
+		}
 	}
 	for i, s := range a.pendingFloatHistograms {
 		series = a.floatHistogramSeries[i]
 		if !series.updateTimestamp(s.T) {
 			a.metrics.totalOutOfOrderSamples.Inc()
 		}
+		if window > 0 {
+			// Copy the histogram; the caller may reuse it after Commit.
+			series.appendQuerySample(querySample{st: s.ST, t: s.T, fh: s.FH.Copy()}, window)
+		}
 	}
 
 	return nil
@@ -1347,7 +1391,7 @@ func (a *appenderBase) logSeries() error {
 			a.bufPool.Put(buf) //nolint:staticcheck
 		}()
 
-		encoder := record.Encoder{EnableSTStorage: a.opts.EnableSTStorage}
+		encoder := record.Encoder{EnableSTStorage: a.opts.EnableSTStorage, EnableSampleGrouping: a.opts.EnableWALSampleGrouping}
 		buf = encoder.Series(a.pendingSeries, buf)
 		if err := a.wal.Log(buf); err != nil {
 			return err
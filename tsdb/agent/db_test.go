@@ -38,6 +38,7 @@ import (
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/storage/remote"
 	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
 	"github.com/prometheus/prometheus/tsdb/chunks"
 	"github.com/prometheus/prometheus/tsdb/record"
 	"github.com/prometheus/prometheus/tsdb/tsdbutil"
@@ -179,6 +180,92 @@ func TestUnsupportedFunctions(t *testing.T) {
 	})
 }
 
+func TestDBQueryableWindow(t *testing.T) {
+	opts := DefaultOptions()
+	opts.QueryableWindow = 1000 // 1s of data is queryable.
+	s := createTestAgentDB(t, nil, opts)
+	defer s.Close()
+
+	ctx := context.Background()
+	lblsFloat := labels.FromStrings(model.MetricNameLabel, "agent_float_test")
+	lblsHist := labels.FromStrings(model.MetricNameLabel, "agent_histogram_test")
+	hists := tsdbutil.GenerateTestHistograms(3)
+
+	app := s.Appender(ctx)
+	for i := 0; i < 3; i++ {
+		ts := int64(i * 100)
+		_, err := app.Append(0, lblsFloat, ts, float64(i))
+		require.NoError(t, err)
+		_, err = app.AppendHistogram(0, lblsHist, ts, hists[i], nil)
+		require.NoError(t, err)
+	}
+	require.NoError(t, app.Commit())
+
+	q, err := s.Querier(0, 300)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, q.Close())
+	}()
+
+	names, _, err := q.LabelNames(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{model.MetricNameLabel}, names)
+
+	vals, _, err := q.LabelValues(ctx, model.MetricNameLabel, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"agent_float_test", "agent_histogram_test"}, vals)
+
+	ss := q.Select(ctx, true, nil, labels.MustNewMatcher(labels.MatchEqual, model.MetricNameLabel, "agent_float_test"))
+	require.True(t, ss.Next())
+	series := ss.At()
+	require.Equal(t, lblsFloat, series.Labels())
+	it := series.Iterator(nil)
+	for i := 0; i < 3; i++ {
+		require.Equal(t, chunkenc.ValFloat, it.Next())
+		ts, v := it.At()
+		require.Equal(t, int64(i*100), ts)
+		require.Equal(t, float64(i), v)
+	}
+	require.Equal(t, chunkenc.ValNone, it.Next())
+	require.False(t, ss.Next())
+	require.NoError(t, ss.Err())
+
+	ss = q.Select(ctx, true, nil, labels.MustNewMatcher(labels.MatchEqual, model.MetricNameLabel, "agent_histogram_test"))
+	require.True(t, ss.Next())
+	it = ss.At().Iterator(nil)
+	for i := 0; i < 3; i++ {
+		require.Equal(t, chunkenc.ValHistogram, it.Next())
+		ts, h := it.AtHistogram(nil)
+		require.Equal(t, int64(i*100), ts)
+		require.Equal(t, hists[i].Count, h.Count)
+	}
+	require.Equal(t, chunkenc.ValNone, it.Next())
+	require.False(t, ss.Next())
+
+	// A sample far beyond the window pushes the earlier samples out of the
+	// buffer.
+	app = s.Appender(ctx)
+	_, err = app.Append(0, lblsFloat, 5000, 50)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	q2, err := s.Querier(0, 6000)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, q2.Close())
+	}()
+
+	ss = q2.Select(ctx, false, nil, labels.MustNewMatcher(labels.MatchEqual, model.MetricNameLabel, "agent_float_test"))
+	require.True(t, ss.Next())
+	it = ss.At().Iterator(nil)
+	require.Equal(t, chunkenc.ValFloat, it.Next())
+	ts, v := it.At()
+	require.Equal(t, int64(5000), ts)
+	require.Equal(t, 50.0, v)
+	require.Equal(t, chunkenc.ValNone, it.Next())
+	require.False(t, ss.Next())
+}
+
 func TestCommit(t *testing.T) {
 	const (
 		numDatapoints = 1000
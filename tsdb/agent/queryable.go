@@ -0,0 +1,129 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/util/annotations"
+)
+
+// querier serves the bounded in-memory query buffer of an agent DB. It scans
+// all live series on every call; the agent keeps no index, which is acceptable
+// for the local debugging queries the buffer exists for.
+type querier struct {
+	db         *DB
+	mint, maxt int64
+}
+
+func (q *querier) Select(_ context.Context, sortSeries bool, _ *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	var series []storage.Series
+	for s := range q.db.series.liveSeries() {
+		if !matchesAll(s.lset, matchers) {
+			continue
+		}
+		samples := s.querySamples(q.mint, q.maxt)
+		if len(samples) == 0 {
+			continue
+		}
+		series = append(series, &storage.SeriesEntry{
+			Lset: s.lset,
+			SampleIteratorFn: func(chunkenc.Iterator) chunkenc.Iterator {
+				return storage.NewListSeriesIteratorWithCopy(storage.Samples(samplesSlice(samples)))
+			},
+		})
+	}
+	if sortSeries {
+		slices.SortFunc(series, func(a, b storage.Series) int {
+			return labels.Compare(a.Labels(), b.Labels())
+		})
+	}
+	return &seriesSet{series: series}
+}
+
+func (q *querier) LabelValues(_ context.Context, name string, _ *storage.LabelHints, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	values := map[string]struct{}{}
+	for s := range q.db.series.liveSeries() {
+		if !matchesAll(s.lset, matchers) {
+			continue
+		}
+		if v := s.lset.Get(name); v != "" {
+			values[v] = struct{}{}
+		}
+	}
+	out := make([]string, 0, len(values))
+	for v := range values {
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out, nil, nil
+}
+
+func (q *querier) LabelNames(_ context.Context, _ *storage.LabelHints, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	names := map[string]struct{}{}
+	for s := range q.db.series.liveSeries() {
+		if !matchesAll(s.lset, matchers) {
+			continue
+		}
+		s.lset.Range(func(l labels.Label) {
+			names[l.Name] = struct{}{}
+		})
+	}
+	out := make([]string, 0, len(names))
+	for n := range names {
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out, nil, nil
+}
+
+func (*querier) Close() error { return nil }
+
+func matchesAll(lset labels.Labels, matchers []*labels.Matcher) bool {
+	for _, m := range matchers {
+		if !m.Matches(lset.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// samplesSlice adapts a sample slice to the storage.Samples interface.
+type samplesSlice []chunks.Sample
+
+func (s samplesSlice) Get(i int) chunks.Sample { return s[i] }
+func (s samplesSlice) Len() int                { return len(s) }
+
+// seriesSet is a storage.SeriesSet over a fixed slice of series.
+type seriesSet struct {
+	series []storage.Series
+	idx    int
+}
+
+func (s *seriesSet) Next() bool {
+	s.idx++
+	return s.idx <= len(s.series)
+}
+
+func (s *seriesSet) At() storage.Series { return s.series[s.idx-1] }
+
+func (*seriesSet) Err() error { return nil }
+
+func (*seriesSet) Warnings() annotations.Annotations { return nil }
@@ -18,7 +18,9 @@ import (
 	"sync"
 
 	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
 	"github.com/prometheus/prometheus/tsdb/chunks"
 )
 
@@ -32,6 +34,86 @@ type memSeries struct {
 	// Last recorded timestamp. Used by Storage.gc to determine if a series is
 	// stale.
 	lastTs int64
+
+	// samples holds the most recent samples of the series when
+	// Options.QueryableWindow is enabled. Guarded by the series lock.
+	samples []querySample
+}
+
+// querySample is one buffered sample of the queryable window. It implements
+// chunks.Sample so that the buffer can be served through the storage helpers.
+type querySample struct {
+	st, t int64
+	f     float64
+	h     *histogram.Histogram
+	fh    *histogram.FloatHistogram
+}
+
+func (s querySample) T() int64                      { return s.t }
+func (s querySample) ST() int64                     { return s.st }
+func (s querySample) F() float64                    { return s.f }
+func (s querySample) H() *histogram.Histogram       { return s.h }
+func (s querySample) FH() *histogram.FloatHistogram { return s.fh }
+
+func (s querySample) Type() chunkenc.ValueType {
+	switch {
+	case s.h != nil:
+		return chunkenc.ValHistogram
+	case s.fh != nil:
+		return chunkenc.ValFloatHistogram
+	default:
+		return chunkenc.ValFloat
+	}
+}
+
+func (s querySample) Copy() chunks.Sample {
+	c := querySample{st: s.st, t: s.t, f: s.f}
+	if s.h != nil {
+		c.h = s.h.Copy()
+	}
+	if s.fh != nil {
+		c.fh = s.fh.Copy()
+	}
+	return c
+}
+
+// appendQuerySample adds s to the series' query buffer and drops buffered
+// samples that have fallen out of the window relative to the series' newest
+// timestamp. Samples at or before the newest buffered timestamp are dropped,
+// so the buffer stays sorted; out-of-order samples within the window are not
+// served by local queries.
+func (m *memSeries) appendQuerySample(s querySample, window int64) {
+	m.Lock()
+	defer m.Unlock()
+	if n := len(m.samples); n > 0 && s.t <= m.samples[n-1].t {
+		return
+	}
+	m.samples = append(m.samples, s)
+	mint := max(s.t, m.lastTs) - window
+	i := 0
+	for i < len(m.samples) && m.samples[i].t < mint {
+		i++
+	}
+	if i > 0 {
+		m.samples = append(m.samples[:0], m.samples[i:]...)
+	}
+}
+
+// querySamples returns a copy of the buffered samples within [mint, maxt].
+func (m *memSeries) querySamples(mint, maxt int64) []chunks.Sample {
+	m.Lock()
+	defer m.Unlock()
+	var out []chunks.Sample
+	for _, s := range m.samples {
+		if s.t < mint {
+			continue
+		}
+		if s.t > maxt {
+			break
+		}
+		out = append(out, s)
+	}
+	return out
 }
 
 // updateTimestamp obtains the lock on s and will attempt to update lastTs.
@@ -347,6 +429,29 @@ func (s *seriesSnapshot) LastSampleTimestamp() int64 {
 	return s.lastTs
 }
 
+// liveSeries yields a pointer to every live series. Unlike allSeries it does
+// not snapshot, so callers must only access the yielded series under its lock.
+func (s *stripeSeries) liveSeries() iter.Seq[*memSeries] {
+	return func(yield func(*memSeries) bool) {
+		var buf []*memSeries
+		for i := 0; i < s.size; i++ {
+			// Collect pointers under RLock to avoid blocking appenders.
+			s.locks[i].RLock()
+			buf = buf[:0]
+			for _, series := range s.series[i] {
+				buf = append(buf, series)
+			}
+			s.locks[i].RUnlock()
+
+			for _, series := range buf {
+				if !yield(series) {
+					return
+				}
+			}
+		}
+	}
+}
+
 func (s *stripeSeries) allSeries() iter.Seq[ActiveSeries] {
 	return func(yield func(ActiveSeries) bool) {
 		var buf []*memSeries
@@ -25,6 +25,7 @@ import (
 	"path/filepath"
 	"slices"
 	"sync"
+	"sync/atomic"
 
 	"github.com/oklog/ulid/v2"
 	"github.com/prometheus/common/promslog"
@@ -348,6 +349,12 @@ type Block struct {
 	numBytesIndex     int64
 	numBytesTombstone int64
 	numBytesMeta      int64
+
+	// labelPartitions is the block's time-partitioned label value index. It
+	// is built lazily in the background on the first query that could profit
+	// from it and stays nil until the build has finished.
+	labelPartitions     atomic.Pointer[labelPartitions]
+	labelPartitionsOnce sync.Once
 }
 
 // OpenBlock opens the block in the directory. It can be passed a chunk pool, which is used
@@ -484,6 +491,32 @@ func (pb *Block) GetSymbolTableSize() uint64 {
 	return pb.symbolTableSize
 }
 
+// labelValuePartitions implements labelPartitionReader. The first call for a
+// range narrower than the block kicks off a background scan of the block
+// index that builds the time-partitioned label value index; until the scan
+// has finished (or if it failed), nil is returned and queries proceed
+// unfiltered.
+func (pb *Block) labelValuePartitions(mint, maxt int64) *labelPartitions {
+	if mint <= pb.meta.MinTime && maxt >= pb.meta.MaxTime {
+		return nil
+	}
+	pb.labelPartitionsOnce.Do(func() {
+		if err := pb.startRead(); err != nil {
+			return
+		}
+		go func() {
+			defer pb.pendingReaders.Done()
+			lp, err := buildLabelPartitions(context.Background(), pb.indexr, pb.meta.MinTime, pb.meta.MaxTime)
+			if err != nil {
+				pb.logger.Warn("Building time-partitioned label value index failed", "block", pb.meta.ULID, "err", err)
+				return
+			}
+			pb.labelPartitions.Store(lp)
+		}()
+	})
+	return pb.labelPartitions.Load()
+}
+
 func (pb *Block) setCompactionFailed() error {
 	pb.meta.Compaction.Failed = true
 	n, err := writeMetaFile(pb.logger, pb.dir, &pb.meta)
@@ -565,6 +598,15 @@ func (r blockIndexReader) ShardedPostings(p index.Postings, shardIndex, shardCou
 	return r.ir.ShardedPostings(p, shardIndex, shardCount)
 }
 
+// HasChunkStats returns whether the underlying index stores per-chunk summary
+// statistics.
+func (r blockIndexReader) HasChunkStats() bool {
+	if sr, ok := r.ir.(chunkStatsReader); ok {
+		return sr.HasChunkStats()
+	}
+	return false
+}
+
 func (r blockIndexReader) Series(ref storage.SeriesRef, builder *labels.ScratchBuilder, chks *[]chunks.Meta) error {
 	if err := r.ir.Series(ref, builder, chks); err != nil {
 		return fmt.Errorf("block: %s: %w", r.b.Meta().ULID, err)
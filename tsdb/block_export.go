@@ -0,0 +1,235 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/oklog/ulid/v2"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+)
+
+// ErrNothingToExport is returned by ExportBlock when no samples match the
+// given matchers and time range.
+var ErrNothingToExport = errors.New("no samples matched the given matchers and time range")
+
+// Commit the export appender once this many samples have accumulated, so
+// exporting a large selection does not hold everything in a single
+// transaction.
+const exportCommitBatchSize = 5000
+
+// ExportBlock writes all data of the series matching any of the given matcher
+// sets within [mint, maxt] into a single new block and streams the block's
+// files to w as a tar archive. The archive is the format accepted by
+// ImportBlock, so a selection of series can be moved to another database
+// without copying whole data directories. The ULID of the exported block is
+// returned.
+//
+// The block is assembled in a temporary directory inside the database's
+// directory and removed again after streaming, so an aborted export leaves
+// nothing behind.
+func (db *DB) ExportBlock(ctx context.Context, w io.Writer, mint, maxt int64, matcherSets ...[]*labels.Matcher) (ulid.ULID, error) {
+	if len(matcherSets) == 0 {
+		return ulid.ULID{}, errors.New("at least one matcher set must be given")
+	}
+	// Clamp the requested range to the data actually present. This keeps the
+	// block size derived from the range reasonable for open-ended requests.
+	dbMint, dbMaxt, ok := db.dataTimeRange()
+	if !ok || maxt < dbMint || mint > dbMaxt {
+		return ulid.ULID{}, ErrNothingToExport
+	}
+	mint = max(mint, dbMint)
+	maxt = min(maxt, dbMaxt)
+
+	tmp := filepath.Join(db.dir, ulid.MustNew(ulid.Now(), rand.Reader).String()+tmpForCreationBlockDirSuffix)
+	if err := os.MkdirAll(tmp, 0o777); err != nil {
+		return ulid.ULID{}, fmt.Errorf("create temporary export directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tmp); err != nil {
+			db.logger.Error("remove tmp folder after block export", "err", err.Error())
+		}
+	}()
+
+	id, err := db.writeExportBlock(ctx, tmp, mint, maxt, matcherSets)
+	if err != nil {
+		return ulid.ULID{}, err
+	}
+
+	if err := writeBlockTar(w, filepath.Join(tmp, id.String()), id.String()); err != nil {
+		return ulid.ULID{}, fmt.Errorf("write block archive: %w", err)
+	}
+	return id, nil
+}
+
+// writeExportBlock queries the matching series and writes them into a single
+// block under dir, returning the new block's ULID.
+func (db *DB) writeExportBlock(ctx context.Context, dir string, mint, maxt int64, matcherSets [][]*labels.Matcher) (_ ulid.ULID, err error) {
+	bw, err := NewBlockWriter(db.logger, dir, (maxt-mint)+2)
+	if err != nil {
+		return ulid.ULID{}, fmt.Errorf("create block writer: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, bw.Close())
+	}()
+
+	q, err := db.Querier(mint, maxt)
+	if err != nil {
+		return ulid.ULID{}, fmt.Errorf("create querier: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, q.Close())
+	}()
+
+	var ss storage.SeriesSet
+	if len(matcherSets) > 1 {
+		sets := make([]storage.SeriesSet, 0, len(matcherSets))
+		for _, mset := range matcherSets {
+			sets = append(sets, q.Select(ctx, true, nil, mset...))
+		}
+		ss = storage.NewMergeSeriesSet(sets, 0, storage.ChainedSeriesMerge)
+	} else {
+		ss = q.Select(ctx, false, nil, matcherSets[0]...)
+	}
+	app := bw.Appender(ctx)
+	samplesInAppender := 0
+	for ss.Next() {
+		series := ss.At()
+		lbls := series.Labels().Copy()
+		var ref storage.SeriesRef
+		it := series.Iterator(nil)
+		for valType := it.Next(); valType != chunkenc.ValNone; valType = it.Next() {
+			switch valType {
+			case chunkenc.ValFloat:
+				t, v := it.At()
+				ref, err = app.Append(ref, lbls, t, v)
+			case chunkenc.ValHistogram:
+				t, h := it.AtHistogram(nil)
+				ref, err = app.AppendHistogram(ref, lbls, t, h, nil)
+			case chunkenc.ValFloatHistogram:
+				t, fh := it.AtFloatHistogram(nil)
+				ref, err = app.AppendHistogram(ref, lbls, t, nil, fh)
+			default:
+				err = fmt.Errorf("unknown sample type %v", valType)
+			}
+			if err != nil {
+				return ulid.ULID{}, errors.Join(fmt.Errorf("append sample: %w", err), app.Rollback())
+			}
+			samplesInAppender++
+			if samplesInAppender >= exportCommitBatchSize {
+				if err := app.Commit(); err != nil {
+					return ulid.ULID{}, fmt.Errorf("commit samples: %w", err)
+				}
+				app = bw.Appender(ctx)
+				samplesInAppender = 0
+			}
+		}
+		if it.Err() != nil {
+			return ulid.ULID{}, errors.Join(fmt.Errorf("iterate series: %w", it.Err()), app.Rollback())
+		}
+	}
+	if ss.Err() != nil {
+		return ulid.ULID{}, errors.Join(fmt.Errorf("select series: %w", ss.Err()), app.Rollback())
+	}
+	if err := app.Commit(); err != nil {
+		return ulid.ULID{}, fmt.Errorf("commit samples: %w", err)
+	}
+
+	id, err := bw.Flush(ctx)
+	if err != nil {
+		return ulid.ULID{}, fmt.Errorf("flush block: %w", err)
+	}
+	if id.Compare(ulid.ULID{}) == 0 {
+		return ulid.ULID{}, ErrNothingToExport
+	}
+	return id, nil
+}
+
+// dataTimeRange returns the time range covered by the database's blocks and
+// head. ok is false when the database holds no data at all.
+func (db *DB) dataTimeRange() (mint, maxt int64, ok bool) {
+	db.mtx.RLock()
+	for _, b := range db.blocks {
+		m := b.Meta()
+		if !ok || m.MinTime < mint {
+			mint = m.MinTime
+		}
+		if !ok || m.MaxTime > maxt {
+			maxt = m.MaxTime
+		}
+		ok = true
+	}
+	db.mtx.RUnlock()
+	if db.head.initialized() {
+		if !ok || db.head.MinTime() < mint {
+			mint = db.head.MinTime()
+		}
+		if !ok || db.head.MaxTime() > maxt {
+			maxt = db.head.MaxTime()
+		}
+		ok = true
+	}
+	return mint, maxt, ok
+}
+
+// writeBlockTar writes the files of the block directory dir to w as a tar
+// archive with all entries placed under prefix, matching the layout that
+// extractBlockTar accepts.
+func writeBlockTar(w io.Writer, dir, prefix string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		name := filepath.ToSlash(filepath.Join(prefix, rel))
+		if d.IsDir() {
+			return tw.WriteHeader(&tar.Header{Name: name + "/", Typeflag: tar.TypeDir, Mode: 0o777})
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o666, Size: info.Size()}); err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -0,0 +1,190 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"archive/tar"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oklog/ulid/v2"
+
+	"github.com/prometheus/prometheus/tsdb/fileutil"
+)
+
+// ImportBlock reads a tar stream holding the files of a single finished block
+// and installs the block into the database's data directory. The archive must
+// contain the block files (meta.json, index, chunks/...) either at its root or
+// inside a single directory, as produced by archiving a block directory.
+//
+// The block is validated by opening it before installation and must not
+// overlap any existing block or data already present in the head; importing
+// is meant for backfilling data older than what the database holds. The
+// extracted files are placed under a temporary directory first and made
+// visible with an atomic rename, so a failed or interrupted import never
+// leaves a partial block behind. On success the ULID of the installed block
+// is returned.
+func (db *DB) ImportBlock(r io.Reader) (ulid.ULID, error) {
+	tmp := filepath.Join(db.dir, ulid.MustNew(ulid.Now(), rand.Reader).String()+tmpForCreationBlockDirSuffix)
+	if err := os.MkdirAll(tmp, 0o777); err != nil {
+		return ulid.ULID{}, fmt.Errorf("create temporary import directory: %w", err)
+	}
+	defer func() {
+		// RemoveAll returns no error when tmp doesn't exist so it is safe to always run it.
+		if err := os.RemoveAll(tmp); err != nil {
+			db.logger.Error("removed tmp folder after failed block import", "err", err.Error())
+		}
+	}()
+
+	if err := extractBlockTar(r, tmp); err != nil {
+		return ulid.ULID{}, fmt.Errorf("extract block archive: %w", err)
+	}
+	root, err := importedBlockRoot(tmp)
+	if err != nil {
+		return ulid.ULID{}, err
+	}
+	meta, _, err := readMetaFile(root)
+	if err != nil {
+		return ulid.ULID{}, fmt.Errorf("read block meta: %w", err)
+	}
+	// Opening the block validates that the index and chunks are complete and
+	// readable before anything becomes visible to queries.
+	b, err := OpenBlock(db.logger, root, db.chunkPool, db.opts.PostingsDecoderFactory)
+	if err != nil {
+		return ulid.ULID{}, fmt.Errorf("validate block: %w", err)
+	}
+	if err := b.Close(); err != nil {
+		return ulid.ULID{}, fmt.Errorf("close block after validation: %w", err)
+	}
+
+	db.cmtx.Lock()
+	defer db.cmtx.Unlock()
+
+	db.mtx.RLock()
+	blockMetas := make([]BlockMeta, 0, len(db.blocks)+1)
+	for _, b := range db.blocks {
+		if b.Meta().ULID == meta.ULID {
+			db.mtx.RUnlock()
+			return ulid.ULID{}, fmt.Errorf("block %s already exists in the database", meta.ULID)
+		}
+		blockMetas = append(blockMetas, b.Meta())
+	}
+	db.mtx.RUnlock()
+
+	blockMetas = append(blockMetas, *meta)
+	if overlaps := OverlappingBlocks(blockMetas); len(overlaps) > 0 {
+		return ulid.ULID{}, fmt.Errorf("imported block overlaps existing blocks: %s", overlaps.String())
+	}
+	if db.head.initialized() && meta.MaxTime > db.head.MinTime() {
+		return ulid.ULID{}, fmt.Errorf("imported block time range [%d, %d] overlaps data in the head starting at %d", meta.MinTime, meta.MaxTime, db.head.MinTime())
+	}
+
+	// Sync the extracted directory before the rename makes it visible.
+	df, err := fileutil.OpenDir(root)
+	if err != nil {
+		return ulid.ULID{}, fmt.Errorf("open imported block dir: %w", err)
+	}
+	if err := df.Sync(); err != nil {
+		df.Close()
+		return ulid.ULID{}, fmt.Errorf("sync imported block dir: %w", err)
+	}
+	if err := df.Close(); err != nil {
+		return ulid.ULID{}, fmt.Errorf("close imported block dir: %w", err)
+	}
+	if err := fileutil.Replace(root, filepath.Join(db.dir, meta.ULID.String())); err != nil {
+		return ulid.ULID{}, fmt.Errorf("install block: %w", err)
+	}
+
+	if err := db.reloadBlocks(); err != nil {
+		return ulid.ULID{}, fmt.Errorf("reloadBlocks: %w", err)
+	}
+	return meta.ULID, nil
+}
+
+// extractBlockTar extracts a tar stream into dir. Only regular files and
+// directories with clean relative names are accepted; anything else, including
+// names escaping dir, aborts the extraction.
+func extractBlockTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if name == "." {
+			continue
+		}
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid entry name %q in block archive", hdr.Name)
+		}
+		path := filepath.Join(dir, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o777); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0o777); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o666)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return err
+			}
+			// Sync every file so the rename installing the block cannot become
+			// durable before its contents.
+			if err := f.Sync(); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported entry %q of type %d in block archive", hdr.Name, hdr.Typeflag)
+		}
+	}
+}
+
+// importedBlockRoot returns the directory holding the block's meta.json inside
+// an extracted archive: either dir itself or its only subdirectory.
+func importedBlockRoot(dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, metaFilename)); err == nil {
+		return dir, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		sub := filepath.Join(dir, entries[0].Name())
+		if _, err := os.Stat(filepath.Join(sub, metaFilename)); err == nil {
+			return sub, nil
+		}
+	}
+	return "", errors.New("no meta.json found in block archive")
+}
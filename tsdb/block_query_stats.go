@@ -0,0 +1,306 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+)
+
+// headBlockQueryStatsID is the pseudo block identifier under which query
+// statistics for the head are accumulated.
+const headBlockQueryStatsID = "head"
+
+// BlockQueryStat summarizes the query load observed for one block, or for the
+// head, since the DB was opened. Durations cover the index lookups and series
+// set iteration performed against the block; chunk decoding happens in the
+// caller's iterators and is reflected in BytesRead instead. BytesRead counts
+// the encoded chunk bytes handed out by the block's chunk reader and is always
+// zero for the head, which serves chunks from memory.
+type BlockQueryStat struct {
+	// ULID of the block, or "head" for the head block.
+	ULID    string `json:"ulid"`
+	MinTime int64  `json:"minTime"`
+	MaxTime int64  `json:"maxTime"`
+	// QueryCount is the number of queriers opened against the block.
+	QueryCount int64 `json:"queryCount"`
+	// TotalDuration is the cumulative time spent querying the block, in
+	// nanoseconds.
+	TotalDuration time.Duration `json:"totalDuration"`
+	BytesRead     int64         `json:"bytesRead"`
+}
+
+// blockQueryStats aggregates per-block query statistics for a DB and logs
+// queries that spend longer than the configured threshold on a single block.
+type blockQueryStats struct {
+	logger        *slog.Logger
+	slowThreshold time.Duration
+
+	mtx     sync.Mutex
+	entries map[string]*blockQueryStatEntry
+}
+
+type blockQueryStatEntry struct {
+	minTime, maxTime int64
+	queryCount       int64
+	totalDuration    time.Duration
+	bytesRead        int64
+}
+
+func newBlockQueryStats(logger *slog.Logger, slowThreshold time.Duration) *blockQueryStats {
+	return &blockQueryStats{
+		logger:        logger,
+		slowThreshold: slowThreshold,
+		entries:       map[string]*blockQueryStatEntry{},
+	}
+}
+
+// tracker returns a tracker that attributes the work of one querier to the
+// given block. The caller wraps the block's querier (and, for persisted
+// blocks, its BlockReader) with it; the accumulated numbers are reported when
+// the querier is closed.
+func (s *blockQueryStats) tracker(ulid string, mint, maxt int64) *blockQueryTracker {
+	return &blockQueryTracker{stats: s, ulid: ulid, minTime: mint, maxTime: maxt}
+}
+
+func (s *blockQueryStats) observe(ulid string, mint, maxt int64, d time.Duration, bytes int64) {
+	s.mtx.Lock()
+	e, ok := s.entries[ulid]
+	if !ok {
+		e = &blockQueryStatEntry{minTime: mint, maxTime: maxt}
+		s.entries[ulid] = e
+	}
+	e.minTime, e.maxTime = mint, maxt
+	e.queryCount++
+	e.totalDuration += d
+	e.bytesRead += bytes
+	s.mtx.Unlock()
+
+	if s.slowThreshold > 0 && d >= s.slowThreshold {
+		s.logger.Warn("Slow block query", "block", ulid, "mint", mint, "maxt", maxt, "duration", d, "bytes_read", bytes)
+	}
+}
+
+// stats returns a snapshot of the accumulated statistics, busiest block
+// first. Entries for blocks that are not in live anymore are dropped, so the
+// set of entries follows the block retention.
+func (s *blockQueryStats) stats(live map[string]struct{}) []BlockQueryStat {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	out := make([]BlockQueryStat, 0, len(s.entries))
+	for id, e := range s.entries {
+		if _, ok := live[id]; !ok && id != headBlockQueryStatsID {
+			delete(s.entries, id)
+			continue
+		}
+		out = append(out, BlockQueryStat{
+			ULID:          id,
+			MinTime:       e.minTime,
+			MaxTime:       e.maxTime,
+			QueryCount:    e.queryCount,
+			TotalDuration: e.totalDuration,
+			BytesRead:     e.bytesRead,
+		})
+	}
+	slices.SortFunc(out, func(a, b BlockQueryStat) int {
+		return cmp.Compare(b.TotalDuration, a.TotalDuration)
+	})
+	return out
+}
+
+// blockQueryTracker accumulates the duration and chunk bytes of one querier
+// against one block and reports them to the stats once the querier is closed.
+type blockQueryTracker struct {
+	stats            *blockQueryStats
+	ulid             string
+	minTime, maxTime int64
+
+	duration atomic.Int64 // Nanoseconds.
+	bytes    atomic.Int64
+	reported sync.Once
+}
+
+func (t *blockQueryTracker) report() {
+	t.reported.Do(func() {
+		t.stats.observe(t.ulid, t.minTime, t.maxTime, time.Duration(t.duration.Load()), t.bytes.Load())
+	})
+}
+
+// querier wraps a block's querier so that the time spent in Select calls and
+// series set iteration is attributed to the tracker. If the wrapped querier
+// supports search, that capability is preserved.
+func (t *blockQueryTracker) querier(q storage.Querier) storage.Querier {
+	tq := &trackedQuerier{Querier: q, t: t}
+	if s, ok := q.(storage.Searcher); ok {
+		return &trackedSearcherQuerier{trackedQuerier: tq, trackedSearcher: &trackedSearcher{s: s, t: t}}
+	}
+	return tq
+}
+
+// chunkQuerier is the chunk querier counterpart of querier.
+func (t *blockQueryTracker) chunkQuerier(q storage.ChunkQuerier) storage.ChunkQuerier {
+	tq := &trackedChunkQuerier{ChunkQuerier: q, t: t}
+	if s, ok := q.(storage.Searcher); ok {
+		return &trackedSearcherChunkQuerier{trackedChunkQuerier: tq, trackedSearcher: &trackedSearcher{s: s, t: t}}
+	}
+	return tq
+}
+
+// blockReader wraps a BlockReader so that the encoded chunk bytes read
+// through its chunk reader are attributed to the tracker.
+func (t *blockQueryTracker) blockReader(b BlockReader) BlockReader {
+	return &trackedBlockReader{BlockReader: b, t: t}
+}
+
+type trackedQuerier struct {
+	storage.Querier
+	t *blockQueryTracker
+}
+
+func (q *trackedQuerier) Select(ctx context.Context, sortSeries bool, hints *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	start := time.Now()
+	ss := q.Querier.Select(ctx, sortSeries, hints, matchers...)
+	q.t.duration.Add(int64(time.Since(start)))
+	return &trackedSeriesSet{SeriesSet: ss, t: q.t}
+}
+
+func (q *trackedQuerier) Close() error {
+	q.t.report()
+	return q.Querier.Close()
+}
+
+type trackedChunkQuerier struct {
+	storage.ChunkQuerier
+	t *blockQueryTracker
+}
+
+func (q *trackedChunkQuerier) Select(ctx context.Context, sortSeries bool, hints *storage.SelectHints, matchers ...*labels.Matcher) storage.ChunkSeriesSet {
+	start := time.Now()
+	ss := q.ChunkQuerier.Select(ctx, sortSeries, hints, matchers...)
+	q.t.duration.Add(int64(time.Since(start)))
+	return &trackedChunkSeriesSet{ChunkSeriesSet: ss, t: q.t}
+}
+
+func (q *trackedChunkQuerier) Close() error {
+	q.t.report()
+	return q.ChunkQuerier.Close()
+}
+
+// trackedSearcher forwards the storage.Searcher interface of a wrapped
+// querier and attributes the time spent in the search calls to the tracker.
+type trackedSearcher struct {
+	s storage.Searcher
+	t *blockQueryTracker
+}
+
+func (s *trackedSearcher) SearchLabelNames(ctx context.Context, hints *storage.SearchHints, matchers ...*labels.Matcher) storage.SearchResultSet {
+	start := time.Now()
+	rs := s.s.SearchLabelNames(ctx, hints, matchers...)
+	s.t.duration.Add(int64(time.Since(start)))
+	return rs
+}
+
+func (s *trackedSearcher) SearchLabelValues(ctx context.Context, name string, hints *storage.SearchHints, matchers ...*labels.Matcher) storage.SearchResultSet {
+	start := time.Now()
+	rs := s.s.SearchLabelValues(ctx, name, hints, matchers...)
+	s.t.duration.Add(int64(time.Since(start)))
+	return rs
+}
+
+// trackedSearcherQuerier is a trackedQuerier whose wrapped querier also
+// implements storage.Searcher. It is a separate type so that the tracking
+// wrapper only advertises search support when the wrapped querier has it.
+type trackedSearcherQuerier struct {
+	*trackedQuerier
+	*trackedSearcher
+}
+
+// trackedSearcherChunkQuerier is the chunk querier counterpart of
+// trackedSearcherQuerier.
+type trackedSearcherChunkQuerier struct {
+	*trackedChunkQuerier
+	*trackedSearcher
+}
+
+type trackedSeriesSet struct {
+	storage.SeriesSet
+	t *blockQueryTracker
+}
+
+func (s *trackedSeriesSet) Next() bool {
+	start := time.Now()
+	ok := s.SeriesSet.Next()
+	s.t.duration.Add(int64(time.Since(start)))
+	return ok
+}
+
+type trackedChunkSeriesSet struct {
+	storage.ChunkSeriesSet
+	t *blockQueryTracker
+}
+
+func (s *trackedChunkSeriesSet) Next() bool {
+	start := time.Now()
+	ok := s.ChunkSeriesSet.Next()
+	s.t.duration.Add(int64(time.Since(start)))
+	return ok
+}
+
+type trackedBlockReader struct {
+	BlockReader
+	t *blockQueryTracker
+}
+
+// labelValuePartitions forwards the optional labelPartitionReader interface
+// of the wrapped block, so that the wrapper does not hide its label value
+// index from queriers.
+func (r *trackedBlockReader) labelValuePartitions(mint, maxt int64) *labelPartitions {
+	if pr, ok := r.BlockReader.(labelPartitionReader); ok {
+		return pr.labelValuePartitions(mint, maxt)
+	}
+	return nil
+}
+
+func (r *trackedBlockReader) Chunks() (ChunkReader, error) {
+	cr, err := r.BlockReader.Chunks()
+	if err != nil {
+		return nil, err
+	}
+	return &trackedChunkReader{ChunkReader: cr, t: r.t}, nil
+}
+
+type trackedChunkReader struct {
+	ChunkReader
+	t *blockQueryTracker
+}
+
+func (r *trackedChunkReader) ChunkOrIterable(meta chunks.Meta) (chunkenc.Chunk, chunkenc.Iterable, error) {
+	chk, it, err := r.ChunkReader.ChunkOrIterable(meta)
+	if chk != nil {
+		r.t.bytes.Add(int64(len(chk.Bytes())))
+	}
+	return chk, it, err
+}
@@ -27,6 +27,10 @@ type histogramAppendable interface {
 		backwardPositiveInserts, backwardNegativeInserts []Insert,
 		okToAppend bool, counterResetHint CounterResetHeader,
 	)
+	// exponentialSchema returns the schema of the chunk's bucket layout, so
+	// that a higher-resolution histogram can be re-encoded to it before the
+	// cross-chunk counter reset detection.
+	exponentialSchema() int32
 }
 
 // floatHistogramAppendable is the float-histogram counterpart of
@@ -39,4 +43,8 @@ type floatHistogramAppendable interface {
 		backwardPositiveInserts, backwardNegativeInserts []Insert,
 		okToAppend, counterReset bool,
 	)
+	// exponentialSchema returns the schema of the chunk's bucket layout, so
+	// that a higher-resolution histogram can be re-encoded to it before the
+	// cross-chunk counter reset detection.
+	exponentialSchema() int32
 }
@@ -692,6 +692,11 @@ func (*FloatHistogramAppender) recodeHistogram(
 	}
 }
 
+// exponentialSchema implements floatHistogramAppendable.
+func (a *FloatHistogramAppender) exponentialSchema() int32 {
+	return a.schema
+}
+
 func (*FloatHistogramAppender) AppendHistogram(Appender, int64, int64, *histogram.Histogram, bool) (Chunk, bool, Appender, error) {
 	panic("appended a histogram sample to a float histogram chunk")
 }
@@ -704,6 +709,17 @@ func (a *FloatHistogramAppender) AppendFloatHistogram(prev Appender, _, t int64,
 	}
 
 	if numSamples == 0 {
+		// When continuing from a previous chunk (a cut for size, not for a
+		// layout change), re-encode a higher-resolution histogram to the
+		// previous chunk's schema so that the resolution stays stable across
+		// the cut and counter resets remain detectable.
+		if p, ok := prev.(floatHistogramAppendable); ok && !appendOnly &&
+			h.Schema > p.exponentialSchema() && histogram.IsExponentialSchema(h.Schema) &&
+			histogram.IsExponentialSchema(p.exponentialSchema()) && !value.IsStaleNaN(h.Sum) {
+			if err := h.ReduceResolution(p.exponentialSchema()); err != nil {
+				return nil, false, a, err
+			}
+		}
 		a.setNumSamples(a.appendFloatHistogram(numSamples, t, h))
 		if h.CounterResetHint == histogram.GaugeType {
 			a.setCounterResetHeader(GaugeType)
@@ -735,6 +751,19 @@ func (a *FloatHistogramAppender) AppendFloatHistogram(prev Appender, _, t int64,
 		return nil, false, a, nil
 	}
 
+	// If the incoming histogram has a higher resolution than the chunk, it
+	// can be re-encoded to the chunk's schema instead of cutting a new chunk,
+	// which would mark a spurious unknown counter reset. The zero bucket is
+	// unaffected by the resolution reduction, so a changed zero threshold
+	// still cuts the chunk.
+	if !appendOnly && h.Schema > a.schema &&
+		histogram.IsExponentialSchema(h.Schema) && histogram.IsExponentialSchema(a.schema) &&
+		!value.IsStaleNaN(h.Sum) && !value.IsStaleNaN(a.sum.value) {
+		if err := h.ReduceResolution(a.schema); err != nil {
+			return nil, false, a, err
+		}
+	}
+
 	// Adding counter-like histogram.
 	if h.CounterResetHint != histogram.GaugeType {
 		pForwardInserts, nForwardInserts, pBackwardInserts, nBackwardInserts, okToAppend, counterReset := a.appendable(h)
@@ -333,6 +333,15 @@ func (a *FloatHistogramSTAppender) AppendFloatHistogram(prev Appender, st, t int
 	}
 
 	if numSamples == 0 {
+		// Re-encode a higher-resolution histogram to the previous chunk's
+		// schema; see FloatHistogramAppender.AppendFloatHistogram.
+		if p, ok := prev.(floatHistogramAppendable); ok && !appendOnly &&
+			fh.Schema > p.exponentialSchema() && histogram.IsExponentialSchema(fh.Schema) &&
+			histogram.IsExponentialSchema(p.exponentialSchema()) && !value.IsStaleNaN(fh.Sum) {
+			if err := fh.ReduceResolution(p.exponentialSchema()); err != nil {
+				return nil, false, a, err
+			}
+		}
 		a.setNumSamples(a.appendFloatHistogramST(numSamples, st, t, fh))
 		if fh.CounterResetHint == histogram.GaugeType {
 			a.setCounterResetHeader(GaugeType)
@@ -355,6 +364,16 @@ func (a *FloatHistogramSTAppender) AppendFloatHistogram(prev Appender, st, t int
 		return nil, false, a, nil
 	}
 
+	// Re-encode a higher-resolution histogram to the chunk's schema instead of
+	// cutting a new chunk; see FloatHistogramAppender.AppendFloatHistogram.
+	if !appendOnly && fh.Schema > a.schema &&
+		histogram.IsExponentialSchema(fh.Schema) && histogram.IsExponentialSchema(a.schema) &&
+		!value.IsStaleNaN(fh.Sum) && !value.IsStaleNaN(a.sum.value) {
+		if err := fh.ReduceResolution(a.schema); err != nil {
+			return nil, false, a, err
+		}
+	}
+
 	// Adding counter-like histogram.
 	if fh.CounterResetHint != histogram.GaugeType {
 		pForwardInserts, nForwardInserts, pBackwardInserts, nBackwardInserts, okToAppend, counterReset := a.appendable(fh)
@@ -338,10 +338,34 @@ func TestFloatHistogramChunkAppendable(t *testing.T) {
 		return c, app.(*FloatHistogramAppender), ts, h
 	}
 
-	{ // Schema change.
+	{ // Schema increase: the histogram is re-encoded to the chunk's schema
+		// and appended to the same chunk.
 		c, hApp, ts, h1 := setup(eh)
 		h2 := h1.Copy()
 		h2.Schema++
+		// Distribute the buckets to the higher-resolution indices that merge
+		// back into the original layout.
+		h2.PositiveSpans = []histogram.Span{
+			{Offset: 0, Length: 1},
+			{Offset: 1, Length: 1},
+			{Offset: 5, Length: 1},
+			{Offset: 7, Length: 1},
+			{Offset: 1, Length: 1},
+			{Offset: 7, Length: 1},
+			{Offset: 3, Length: 1},
+		}
+		_, _, _, _, ok, _ := hApp.appendable(h2)
+		require.False(t, ok) // Not appendable as is, needs re-encoding first.
+
+		assertNoNewFloatHistogramChunkOnAppend(t, c, hApp, ts+1, h2, UnknownCounterReset)
+		require.Equal(t, h1.Schema, h2.Schema) // h2 was reduced to the chunk's schema.
+	}
+
+	{ // Schema decrease: the incoming histogram cannot be re-encoded to the
+		// chunk's higher resolution, so a new chunk is cut.
+		c, hApp, ts, h1 := setup(eh)
+		h2 := h1.Copy()
+		h2.Schema--
 		_, _, _, _, ok, _ := hApp.appendable(h2)
 		require.False(t, ok)
 
@@ -1027,10 +1051,34 @@ func TestFloatHistogramChunkAppendableGauge(t *testing.T) {
 		return c, app.(*FloatHistogramAppender), ts, h
 	}
 
-	{ // Schema change.
+	{ // Schema increase: the histogram is re-encoded to the chunk's schema
+		// and appended to the same chunk.
 		c, hApp, ts, h1 := setup(eh)
 		h2 := h1.Copy()
 		h2.Schema++
+		// Distribute the buckets to the higher-resolution indices that merge
+		// back into the original layout.
+		h2.PositiveSpans = []histogram.Span{
+			{Offset: 0, Length: 1},
+			{Offset: 1, Length: 1},
+			{Offset: 5, Length: 1},
+			{Offset: 7, Length: 1},
+			{Offset: 1, Length: 1},
+			{Offset: 7, Length: 1},
+			{Offset: 3, Length: 1},
+		}
+		_, _, _, _, _, _, ok := hApp.appendableGauge(h2)
+		require.False(t, ok) // Not appendable as is, needs re-encoding first.
+
+		assertNoNewFloatHistogramChunkOnAppend(t, c, hApp, ts+1, h2, GaugeType)
+		require.Equal(t, h1.Schema, h2.Schema) // h2 was reduced to the chunk's schema.
+	}
+
+	{ // Schema decrease: the incoming histogram cannot be re-encoded to the
+		// chunk's higher resolution, so a new chunk is cut.
+		c, hApp, ts, h1 := setup(eh)
+		h2 := h1.Copy()
+		h2.Schema--
 		_, _, _, _, _, _, ok := hApp.appendableGauge(h2)
 		require.False(t, ok)
 
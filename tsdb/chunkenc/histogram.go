@@ -744,6 +744,11 @@ func (a *HistogramAppender) writeSumDelta(v float64) {
 	xorWrite(a.b, v, a.sum, &a.leading, &a.trailing)
 }
 
+// exponentialSchema implements histogramAppendable.
+func (a *HistogramAppender) exponentialSchema() int32 {
+	return a.schema
+}
+
 func (*HistogramAppender) AppendFloatHistogram(Appender, int64, int64, *histogram.FloatHistogram, bool) (Chunk, bool, Appender, error) {
 	panic("appended a float histogram sample to a histogram chunk")
 }
@@ -756,6 +761,17 @@ func (a *HistogramAppender) AppendHistogram(prev Appender, _, t int64, h *histog
 	}
 
 	if numSamples == 0 {
+		// When continuing from a previous chunk (a cut for size, not for a
+		// layout change), re-encode a higher-resolution histogram to the
+		// previous chunk's schema so that the resolution stays stable across
+		// the cut and counter resets remain detectable.
+		if p, ok := prev.(histogramAppendable); ok && !appendOnly &&
+			h.Schema > p.exponentialSchema() && histogram.IsExponentialSchema(h.Schema) &&
+			histogram.IsExponentialSchema(p.exponentialSchema()) && !value.IsStaleNaN(h.Sum) {
+			if err := h.ReduceResolution(p.exponentialSchema()); err != nil {
+				return nil, false, a, err
+			}
+		}
 		a.setNumSamples(a.appendHistogram(numSamples, t, h))
 		if h.CounterResetHint == histogram.GaugeType {
 			a.setCounterResetHeader(GaugeType)
@@ -783,6 +799,19 @@ func (a *HistogramAppender) AppendHistogram(prev Appender, _, t int64, h *histog
 		return nil, false, a, nil
 	}
 
+	// If the incoming histogram has a higher resolution than the chunk, it
+	// can be re-encoded to the chunk's schema instead of cutting a new chunk,
+	// which would mark a spurious unknown counter reset. The zero bucket is
+	// unaffected by the resolution reduction, so a changed zero threshold
+	// still cuts the chunk.
+	if !appendOnly && h.Schema > a.schema &&
+		histogram.IsExponentialSchema(h.Schema) && histogram.IsExponentialSchema(a.schema) &&
+		!value.IsStaleNaN(h.Sum) && !value.IsStaleNaN(a.sum) {
+		if err := h.ReduceResolution(a.schema); err != nil {
+			return nil, false, a, err
+		}
+	}
+
 	// Adding counter-like histogram.
 	if h.CounterResetHint != histogram.GaugeType {
 		pForwardInserts, nForwardInserts, pBackwardInserts, nBackwardInserts, okToAppend, counterResetHint := a.appendable(h)
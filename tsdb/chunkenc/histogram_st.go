@@ -320,6 +320,15 @@ func (a *HistogramSTAppender) AppendHistogram(prev Appender, st, t int64, h *his
 	}
 
 	if numSamples == 0 {
+		// Re-encode a higher-resolution histogram to the previous chunk's
+		// schema; see HistogramAppender.AppendHistogram.
+		if p, ok := prev.(histogramAppendable); ok && !appendOnly &&
+			h.Schema > p.exponentialSchema() && histogram.IsExponentialSchema(h.Schema) &&
+			histogram.IsExponentialSchema(p.exponentialSchema()) && !value.IsStaleNaN(h.Sum) {
+			if err := h.ReduceResolution(p.exponentialSchema()); err != nil {
+				return nil, false, a, err
+			}
+		}
 		a.setNumSamples(a.appendHistogramST(numSamples, st, t, h))
 		if h.CounterResetHint == histogram.GaugeType {
 			a.setCounterResetHeader(GaugeType)
@@ -338,6 +347,16 @@ func (a *HistogramSTAppender) AppendHistogram(prev Appender, st, t int64, h *his
 		return nil, false, a, nil
 	}
 
+	// Re-encode a higher-resolution histogram to the chunk's schema instead of
+	// cutting a new chunk; see HistogramAppender.AppendHistogram.
+	if !appendOnly && h.Schema > a.schema &&
+		histogram.IsExponentialSchema(h.Schema) && histogram.IsExponentialSchema(a.schema) &&
+		!value.IsStaleNaN(h.Sum) && !value.IsStaleNaN(a.sum) {
+		if err := h.ReduceResolution(a.schema); err != nil {
+			return nil, false, a, err
+		}
+	}
+
 	// Adding counter-like histogram.
 	if h.CounterResetHint != histogram.GaugeType {
 		pForwardInserts, nForwardInserts, pBackwardInserts, nBackwardInserts, okToAppend, counterResetHint := a.appendable(h)
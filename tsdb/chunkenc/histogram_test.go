@@ -352,10 +352,34 @@ func TestHistogramChunkAppendable(t *testing.T) {
 		return c, app.(*HistogramAppender), ts, h
 	}
 
-	{ // Schema change.
+	{ // Schema increase: the histogram is re-encoded to the chunk's schema
+		// and appended to the same chunk.
 		c, hApp, ts, h1 := setup(eh)
 		h2 := h1.Copy()
 		h2.Schema++
+		// Distribute the buckets to the higher-resolution indices that merge
+		// back into the original layout.
+		h2.PositiveSpans = []histogram.Span{
+			{Offset: 0, Length: 1},
+			{Offset: 1, Length: 1},
+			{Offset: 5, Length: 1},
+			{Offset: 7, Length: 1},
+			{Offset: 1, Length: 1},
+			{Offset: 7, Length: 1},
+			{Offset: 3, Length: 1},
+		}
+		_, _, _, _, ok, _ := hApp.appendable(h2)
+		require.False(t, ok) // Not appendable as is, needs re-encoding first.
+
+		assertNoNewHistogramChunkOnAppend(t, c, hApp, ts+1, h2, UnknownCounterReset)
+		require.Equal(t, h1.Schema, h2.Schema) // h2 was reduced to the chunk's schema.
+	}
+
+	{ // Schema decrease: the incoming histogram cannot be re-encoded to the
+		// chunk's higher resolution, so a new chunk is cut.
+		c, hApp, ts, h1 := setup(eh)
+		h2 := h1.Copy()
+		h2.Schema--
 		_, _, _, _, ok, _ := hApp.appendable(h2)
 		require.False(t, ok)
 
@@ -741,7 +765,7 @@ func TestHistogramChunkAppendable(t *testing.T) {
 		assertRecodedHistogramChunkOnAppend(t, c, hApp, ts+1, h2, UnknownCounterReset)
 	}
 
-	{ // New histogram with a different schema.
+	{ // New histogram with a higher schema whose reduction loses buckets.
 		c, hApp, ts, h1 := setup(eh)
 		h2 := h1.Copy()
 		h2.Schema = 2
@@ -751,10 +775,13 @@ func TestHistogramChunkAppendable(t *testing.T) {
 		require.Empty(t, negInterjections)
 		require.Empty(t, backwardPositiveInserts)
 		require.Empty(t, backwardNegativeInserts)
-		require.False(t, ok) // Need to cut a new chunk.
+		require.False(t, ok)
 		require.Equal(t, UnknownCounterReset, cr)
 
-		assertNewHistogramChunkOnAppend(t, c, hApp, ts+1, h2, UnknownCounterReset, histogram.UnknownCounterReset)
+		// The histogram is re-encoded to the chunk's schema on append, which
+		// enables full counter reset detection: the re-encoded histogram has
+		// buckets missing, so this is a genuine counter reset.
+		assertNewHistogramChunkOnAppend(t, c, hApp, ts+1, h2, CounterReset, histogram.UnknownCounterReset)
 	}
 
 	{ // New histogram with a different schema.
@@ -1239,10 +1266,34 @@ func TestHistogramChunkAppendableGauge(t *testing.T) {
 		return c, app.(*HistogramAppender), ts, h
 	}
 
-	{ // Schema change.
+	{ // Schema increase: the histogram is re-encoded to the chunk's schema
+		// and appended to the same chunk.
 		c, hApp, ts, h1 := setup(eh)
 		h2 := h1.Copy()
 		h2.Schema++
+		// Distribute the buckets to the higher-resolution indices that merge
+		// back into the original layout.
+		h2.PositiveSpans = []histogram.Span{
+			{Offset: 0, Length: 1},
+			{Offset: 1, Length: 1},
+			{Offset: 5, Length: 1},
+			{Offset: 7, Length: 1},
+			{Offset: 1, Length: 1},
+			{Offset: 7, Length: 1},
+			{Offset: 3, Length: 1},
+		}
+		_, _, _, _, _, _, ok := hApp.appendableGauge(h2)
+		require.False(t, ok) // Not appendable as is, needs re-encoding first.
+
+		assertNoNewHistogramChunkOnAppend(t, c, hApp, ts+1, h2, GaugeType)
+		require.Equal(t, h1.Schema, h2.Schema) // h2 was reduced to the chunk's schema.
+	}
+
+	{ // Schema decrease: the incoming histogram cannot be re-encoded to the
+		// chunk's higher resolution, so a new chunk is cut.
+		c, hApp, ts, h1 := setup(eh)
+		h2 := h1.Copy()
+		h2.Schema--
 		_, _, _, _, _, _, ok := hApp.appendableGauge(h2)
 		require.False(t, ok)
 
@@ -20,10 +20,12 @@ import (
 	"hash"
 	"hash/crc32"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"strconv"
 
+	"github.com/prometheus/prometheus/model/value"
 	"github.com/prometheus/prometheus/tsdb/chunkenc"
 	"github.com/prometheus/prometheus/tsdb/fileutil"
 )
@@ -132,6 +134,86 @@ type Meta struct {
 	// Time range the data covers.
 	// When MaxTime == math.MaxInt64 the chunk is still open and being appended to.
 	MinTime, MaxTime int64
+
+	// Stats holds summary statistics of the chunk's samples. They are only
+	// present (Stats.Count > 0) when read from an index format that stores
+	// them, see index.FormatV5.
+	Stats ChunkStats
+
+	// Origin records the provenance of the chunk's samples, when known.
+	// Like Stats, it is only persisted by index formats that store
+	// per-chunk attributes, see index.FormatV5.
+	Origin SampleOrigin
+}
+
+// SampleOrigin records where the samples of a chunk were originally
+// produced. It is a hint for deduplication across highly-available
+// Prometheus pairs, not a guarantee: a chunk may mix samples from several
+// origins, in which case the most recently recorded one wins.
+type SampleOrigin uint8
+
+const (
+	// OriginUnknown means no provenance was recorded for the samples.
+	OriginUnknown SampleOrigin = iota
+	// OriginPrimary marks first-hand data, i.e. samples scraped directly
+	// or received from a sender that declared itself the primary.
+	OriginPrimary
+	// OriginReplicated marks samples copied from another instance.
+	OriginReplicated
+)
+
+func (o SampleOrigin) String() string {
+	switch o {
+	case OriginPrimary:
+		return "primary"
+	case OriginReplicated:
+		return "replicated"
+	default:
+		return "unknown"
+	}
+}
+
+// ChunkStats are summary statistics of the float samples of one chunk,
+// excluding staleness markers. A zero Count means no statistics are available,
+// which is always the case for histogram chunks.
+type ChunkStats struct {
+	Min, Max, Sum float64
+	Count         uint64
+}
+
+// CalcChunkStats computes summary statistics over the samples of c. It returns
+// zero ChunkStats for non-float chunks and for chunks that only contain
+// staleness markers.
+func CalcChunkStats(c chunkenc.Chunk) ChunkStats {
+	var stats ChunkStats
+	it := c.Iterator(nil)
+	for vt := it.Next(); vt != chunkenc.ValNone; vt = it.Next() {
+		if vt != chunkenc.ValFloat {
+			return ChunkStats{}
+		}
+		_, v := it.At()
+		if value.IsStaleNaN(v) {
+			continue
+		}
+		// NaN handling mirrors the PromQL over-time functions: NaN wins
+		// min/max comparisons only while no regular float has been seen.
+		if stats.Count == 0 {
+			stats.Min, stats.Max = v, v
+		} else {
+			if v < stats.Min || math.IsNaN(stats.Min) {
+				stats.Min = v
+			}
+			if v > stats.Max || math.IsNaN(stats.Max) {
+				stats.Max = v
+			}
+		}
+		stats.Sum += v
+		stats.Count++
+	}
+	if it.Err() != nil {
+		return ChunkStats{}
+	}
+	return stats
 }
 
 // ChunkFromSamples requires all samples to have the same type.
@@ -28,13 +28,16 @@ import (
 	"github.com/oklog/ulid/v2"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/promslog"
+	"golang.org/x/sync/errgroup"
 
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb/chunkenc"
 	"github.com/prometheus/prometheus/tsdb/chunks"
 	"github.com/prometheus/prometheus/tsdb/fileutil"
 	"github.com/prometheus/prometheus/tsdb/index"
 	"github.com/prometheus/prometheus/tsdb/tombstones"
+	"github.com/prometheus/prometheus/util/annotations"
 )
 
 // ExponentialBlockRanges returns the time ranges based on the stepSize.
@@ -85,21 +88,25 @@ type LeveledCompactor struct {
 	ctx                         context.Context
 	maxBlockChunkSegmentSize    int64
 	useUncachedIO               bool
+	indexFormatVersion          int
 	mergeFunc                   storage.VerticalChunkSeriesMergeFunc
 	blockExcludeFunc            BlockExcludeFilterFunc
 	postingsEncoder             index.PostingsEncoder
 	postingsDecoderFactory      PostingsDecoderFactory
 	enableOverlappingCompaction bool
+	labelRetention              func() []LabelRetentionPolicy
 }
 
 type CompactorMetrics struct {
-	Ran               prometheus.Counter
-	PopulatingBlocks  prometheus.Gauge
-	OverlappingBlocks prometheus.Counter
-	Duration          prometheus.Histogram
-	ChunkSize         prometheus.Histogram
-	ChunkSamples      prometheus.Histogram
-	ChunkRange        prometheus.Histogram
+	Ran                   prometheus.Counter
+	PopulatingBlocks      prometheus.Gauge
+	OverlappingBlocks     prometheus.Counter
+	Duration              prometheus.Histogram
+	ChunkSize             prometheus.Histogram
+	ChunkSamples          prometheus.Histogram
+	ChunkRange            prometheus.Histogram
+	IndexFormatVersion    prometheus.Gauge
+	IndexFormatCapability *prometheus.GaugeVec
 }
 
 // NewCompactorMetrics initializes metrics for Compactor.
@@ -141,6 +148,16 @@ func NewCompactorMetrics(r prometheus.Registerer) *CompactorMetrics {
 		Help:    "Final time range of chunks on their first compaction",
 		Buckets: prometheus.ExponentialBuckets(100, 4, 10),
 	})
+	m.IndexFormatVersion = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "prometheus_tsdb_compaction_index_format_version",
+		Help: "Index format version the compactor writes for new blocks.",
+	})
+	m.IndexFormatCapability = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "prometheus_tsdb_index_format_capability",
+		Help: "Highest index format version this binary supports, per operation (read or write).",
+	}, []string{"operation"})
+	m.IndexFormatCapability.WithLabelValues("read").Set(index.MaxSupportedReadVersion)
+	m.IndexFormatCapability.WithLabelValues("write").Set(index.MaxSupportedWriteVersion)
 
 	if r != nil {
 		r.MustRegister(
@@ -151,6 +168,8 @@ func NewCompactorMetrics(r prometheus.Registerer) *CompactorMetrics {
 			m.ChunkRange,
 			m.ChunkSamples,
 			m.ChunkSize,
+			m.IndexFormatVersion,
+			m.IndexFormatCapability,
 		)
 	}
 	return m
@@ -177,6 +196,14 @@ type LeveledCompactorOptions struct {
 	// is set.
 	FloatChunkEncoding func() chunkenc.Encoding
 
+	// LabelRetention returns the label retention policies to enforce during
+	// compaction. Labels named by a policy are stripped from all series of
+	// blocks older than the policy's minimum age, merging series that become
+	// identical. Called at compaction time, so runtime-reloaded configuration
+	// takes effect without recreating the compactor. Nil disables label
+	// retention.
+	LabelRetention func() []LabelRetentionPolicy
+
 	// BlockExcludeFilter is used to decide which blocks are excluded from compactions.
 	BlockExcludeFilter BlockExcludeFilterFunc
 
@@ -188,6 +215,23 @@ type LeveledCompactorOptions struct {
 	Metrics *CompactorMetrics
 	// UseUncachedIO allows bypassing the page cache when appropriate.
 	UseUncachedIO bool
+	// EnableRoaringPostings writes blocks with a FormatV4 index, which stores
+	// high-cardinality postings lists as roaring bitmaps. It takes precedence
+	// over PE. Older Prometheus versions cannot read FormatV4 blocks.
+	EnableRoaringPostings bool
+	// EnableChunkSummaries writes blocks with a FormatV5 index, which stores
+	// per-chunk summary statistics (min, max, sum, count) on top of the
+	// FormatV4 postings encoding. It takes precedence over both PE and
+	// EnableRoaringPostings. Older Prometheus versions cannot read FormatV5
+	// blocks.
+	EnableChunkSummaries bool
+	// IndexFormatVersion pins the index format version written for new
+	// blocks, taking precedence over EnableRoaringPostings and
+	// EnableChunkSummaries. Pinning a lower version keeps new blocks readable
+	// by older Prometheus versions, e.g. while canarying a new release.
+	// 0 selects the version from the feature flags above. Valid non-zero
+	// values are index.FormatV2, index.FormatV4 and index.FormatV5.
+	IndexFormatVersion int
 }
 
 type PostingsDecoderFactory func(meta *BlockMeta) index.PostingsDecoder
@@ -230,6 +274,22 @@ func NewLeveledCompactorWithOptions(ctx context.Context, r prometheus.Registerer
 	if opts.Metrics == nil {
 		opts.Metrics = NewCompactorMetrics(r)
 	}
+	indexFormatVersion := opts.IndexFormatVersion
+	switch indexFormatVersion {
+	case 0:
+		switch {
+		case opts.EnableChunkSummaries:
+			indexFormatVersion = index.FormatV5
+		case opts.EnableRoaringPostings:
+			indexFormatVersion = index.FormatV4
+		default:
+			indexFormatVersion = index.FormatV2
+		}
+	case index.FormatV2, index.FormatV4, index.FormatV5:
+	default:
+		return nil, fmt.Errorf("unsupported index format version %d, must be one of %d, %d, %d", indexFormatVersion, index.FormatV2, index.FormatV4, index.FormatV5)
+	}
+	opts.Metrics.IndexFormatVersion.Set(float64(indexFormatVersion))
 	return &LeveledCompactor{
 		ranges:                      ranges,
 		chunkPool:                   pool,
@@ -238,11 +298,13 @@ func NewLeveledCompactorWithOptions(ctx context.Context, r prometheus.Registerer
 		ctx:                         ctx,
 		maxBlockChunkSegmentSize:    maxBlockChunkSegmentSize,
 		useUncachedIO:               opts.UseUncachedIO,
+		indexFormatVersion:          indexFormatVersion,
 		mergeFunc:                   mergeFunc,
 		postingsEncoder:             pe,
 		postingsDecoderFactory:      opts.PD,
 		enableOverlappingCompaction: opts.EnableOverlappingCompaction,
 		blockExcludeFunc:            opts.BlockExcludeFilter,
+		labelRetention:              opts.LabelRetention,
 	}, nil
 }
 
@@ -582,10 +644,26 @@ func CompactBlockMetas(uid ulid.ULID, blocks ...*BlockMeta) *BlockMeta {
 // Compact creates a new block in the compactor's directory from the blocks in the
 // provided directories.
 func (c *LeveledCompactor) Compact(dest string, dirs []string, open []*Block) ([]ulid.ULID, error) {
-	return c.CompactWithBlockPopulator(dest, dirs, open, DefaultBlockPopulator{})
+	return c.compact(dest, dirs, open, DefaultBlockPopulator{LabelRetention: c.labelRetention}, 1)
 }
 
 func (c *LeveledCompactor) CompactWithBlockPopulator(dest string, dirs []string, open []*Block, blockPopulator BlockPopulator) ([]ulid.ULID, error) {
+	return c.compact(dest, dirs, open, blockPopulator, 1)
+}
+
+// CompactWithSplitting is like Compact, except the result is split into
+// shardCount output blocks, sharded by a hash of the series labels. The shards
+// are populated concurrently, so compacting very large blocks no longer
+// serializes on a single goroutine. Shards that end up with no samples are not
+// written. A shardCount of 0 or 1 is equivalent to Compact.
+func (c *LeveledCompactor) CompactWithSplitting(dest string, dirs []string, open []*Block, shardCount uint64) ([]ulid.ULID, error) {
+	if shardCount == 0 {
+		shardCount = 1
+	}
+	return c.compact(dest, dirs, open, DefaultBlockPopulator{LabelRetention: c.labelRetention}, shardCount)
+}
+
+func (c *LeveledCompactor) compact(dest string, dirs []string, open []*Block, blockPopulator BlockPopulator, shardCount uint64) ([]ulid.ULID, error) {
 	var (
 		blocks []BlockReader
 		bs     []*Block
@@ -632,12 +710,36 @@ func (c *LeveledCompactor) CompactWithBlockPopulator(dest string, dirs []string,
 		uids = append(uids, meta.ULID.String())
 	}
 
-	uid := ulid.MustNew(ulid.Now(), rand.Reader)
+	// All output blocks of a split compaction share the same ULID timestamp.
+	outBlocksTime := ulid.Now()
+	outMetas := make([]*BlockMeta, 0, shardCount)
+	for range shardCount {
+		outMetas = append(outMetas, CompactBlockMetas(ulid.MustNew(outBlocksTime, rand.Reader), metas...))
+	}
 
-	meta := CompactBlockMetas(uid, metas...)
-	err := c.write(dest, meta, blockPopulator, blocks...)
+	var err error
+	if shardCount == 1 {
+		err = c.write(dest, outMetas[0], blockPopulator, AllSortedPostings, blocks...)
+	} else {
+		var g errgroup.Group
+		for i, m := range outMetas {
+			g.Go(func() error {
+				return c.write(dest, m, blockPopulator, ShardedSortedPostings(uint64(i), shardCount), blocks...)
+			})
+		}
+		err = g.Wait()
+	}
 	if err == nil {
-		if meta.Stats.NumSamples == 0 {
+		result := make([]ulid.ULID, 0, shardCount)
+		var totalSamples uint64
+		for _, m := range outMetas {
+			totalSamples += m.Stats.NumSamples
+			// Empty shards are not written, see write.
+			if m.Stats.NumSamples > 0 {
+				result = append(result, m.ULID)
+			}
+		}
+		if totalSamples == 0 {
 			for _, b := range bs {
 				b.meta.Compaction.Deletable = true
 				n, err := writeMetaFile(c.logger, b.dir, &b.meta)
@@ -661,16 +763,24 @@ func (c *LeveledCompactor) CompactWithBlockPopulator(dest string, dirs []string,
 		c.logger.Info(
 			"compact blocks",
 			"count", len(blocks),
-			"mint", meta.MinTime,
-			"maxt", meta.MaxTime,
-			"ulid", meta.ULID,
+			"mint", outMetas[0].MinTime,
+			"maxt", outMetas[0].MaxTime,
+			"ulids", fmt.Sprintf("%v", result),
 			"sources", fmt.Sprintf("%v", uids),
 			"duration", time.Since(start),
+			"shard_count", shardCount,
 		)
-		return []ulid.ULID{uid}, nil
+		return result, nil
 	}
 
 	errs := []error{err}
+	// Shards written before another shard failed have already been moved into
+	// the destination dir, remove them again.
+	for _, m := range outMetas {
+		if rmErr := os.RemoveAll(filepath.Join(dest, m.ULID.String())); rmErr != nil {
+			errs = append(errs, fmt.Errorf("remove partial block %s: %w", m.ULID, rmErr))
+		}
+	}
 	if !errors.Is(err, context.Canceled) {
 		for _, b := range bs {
 			if err := b.setCompactionFailed(); err != nil {
@@ -711,7 +821,7 @@ func (c *LeveledCompactor) Write(dest string, b BlockReader, mint, maxt int64, b
 		}
 	}
 
-	err := c.write(dest, meta, DefaultBlockPopulator{}, b)
+	err := c.write(dest, meta, DefaultBlockPopulator{LabelRetention: c.labelRetention}, AllSortedPostings, b)
 	if err != nil {
 		return nil, err
 	}
@@ -757,7 +867,7 @@ func (w *instrumentedChunkWriter) WriteChunks(chunks ...chunks.Meta) error {
 }
 
 // write creates a new block that is the union of the provided blocks into dir.
-func (c *LeveledCompactor) write(dest string, meta *BlockMeta, blockPopulator BlockPopulator, blocks ...BlockReader) (err error) {
+func (c *LeveledCompactor) write(dest string, meta *BlockMeta, blockPopulator BlockPopulator, postingsFunc IndexReaderPostingsFunc, blocks ...BlockReader) (err error) {
 	dir := filepath.Join(dest, meta.ULID.String())
 	tmp := dir + tmpForCreationBlockDirSuffix
 	var closers []io.Closer
@@ -799,13 +909,21 @@ func (c *LeveledCompactor) write(dest string, meta *BlockMeta, blockPopulator Bl
 		}
 	}
 
-	indexw, err := index.NewWriterWithEncoder(c.ctx, filepath.Join(tmp, indexFilename), c.postingsEncoder)
+	var indexw *index.Writer
+	switch c.indexFormatVersion {
+	case index.FormatV5:
+		indexw, err = index.NewWriterV5(c.ctx, filepath.Join(tmp, indexFilename))
+	case index.FormatV4:
+		indexw, err = index.NewWriterV4(c.ctx, filepath.Join(tmp, indexFilename))
+	default:
+		indexw, err = index.NewWriterWithEncoder(c.ctx, filepath.Join(tmp, indexFilename), c.postingsEncoder)
+	}
 	if err != nil {
 		return fmt.Errorf("open index writer: %w", err)
 	}
 	closers = append(closers, indexw)
 
-	if err := blockPopulator.PopulateBlock(c.ctx, c.metrics, c.logger, c.chunkPool, c.mergeFunc, blocks, meta, indexw, chunkw, AllSortedPostings); err != nil {
+	if err := blockPopulator.PopulateBlock(c.ctx, c.metrics, c.logger, c.chunkPool, c.mergeFunc, blocks, meta, indexw, chunkw, postingsFunc); err != nil {
 		return fmt.Errorf("populate block: %w", err)
 	}
 
@@ -887,12 +1005,29 @@ func AllSortedPostings(ctx context.Context, reader IndexReader) index.Postings {
 	return reader.SortedPostings(all)
 }
 
-type DefaultBlockPopulator struct{}
+// ShardedSortedPostings returns an IndexReaderPostingsFunc that restricts the
+// sorted all postings iterator to the series of the given shard, computed by
+// hashing the series labels.
+func ShardedSortedPostings(shardIndex, shardCount uint64) IndexReaderPostingsFunc {
+	return func(ctx context.Context, reader IndexReader) index.Postings {
+		return reader.ShardedPostings(AllSortedPostings(ctx, reader), shardIndex, shardCount)
+	}
+}
+
+// DefaultBlockPopulator writes the union of the input blocks to the output
+// block.
+type DefaultBlockPopulator struct {
+	// LabelRetention, if non-nil, returns the label retention policies to
+	// enforce. Labels named by a policy are stripped from all series when the
+	// output block is older than the policy's minimum age, merging series
+	// that become identical.
+	LabelRetention func() []LabelRetentionPolicy
+}
 
 // PopulateBlock fills the index and chunk writers with new data gathered as the union
 // of the provided blocks. It returns meta information for the new block.
 // It expects sorted blocks input by mint.
-func (DefaultBlockPopulator) PopulateBlock(ctx context.Context, metrics *CompactorMetrics, logger *slog.Logger, chunkPool chunkenc.Pool, mergeFunc storage.VerticalChunkSeriesMergeFunc, blocks []BlockReader, meta *BlockMeta, indexw IndexWriter, chunkw ChunkWriter, postingsFunc IndexReaderPostingsFunc) (err error) {
+func (p DefaultBlockPopulator) PopulateBlock(ctx context.Context, metrics *CompactorMetrics, logger *slog.Logger, chunkPool chunkenc.Pool, mergeFunc storage.VerticalChunkSeriesMergeFunc, blocks []BlockReader, meta *BlockMeta, indexw IndexWriter, chunkw ChunkWriter, postingsFunc IndexReaderPostingsFunc) (err error) {
 	if len(blocks) == 0 {
 		return errors.New("cannot populate block from no readers")
 	}
@@ -984,6 +1119,13 @@ func (DefaultBlockPopulator) PopulateBlock(ctx context.Context, metrics *Compact
 		set = storage.NewMergeChunkSeriesSet(sets, 0, mergeFunc)
 	}
 
+	if p.LabelRetention != nil {
+		if names := labelsPastRetention(p.LabelRetention(), meta.MaxTime, time.Now().UnixMilli()); len(names) > 0 {
+			logger.Info("Dropping labels past their retention during compaction", "ulid", meta.ULID, "labels", fmt.Sprintf("%v", names))
+			set = newDropLabelsChunkSeriesSet(set, names, mergeFunc)
+		}
+	}
+
 	// Iterate over all sorted chunk series.
 	for set.Next() {
 		select {
@@ -1042,3 +1184,98 @@ func (DefaultBlockPopulator) PopulateBlock(ctx context.Context, metrics *Compact
 
 	return nil
 }
+
+// labelsPastRetention returns the sorted union of label names of all label
+// retention policies that apply to a block ending at maxTime, i.e. whose
+// minimum block age has passed at now. Both timestamps are in milliseconds.
+func labelsPastRetention(policies []LabelRetentionPolicy, maxTime, now int64) []string {
+	var names []string
+	for _, p := range policies {
+		if p.MinBlockAge <= 0 || maxTime > now-p.MinBlockAge {
+			continue
+		}
+		for _, n := range p.LabelNames {
+			if !slices.Contains(names, n) {
+				names = append(names, n)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// newDropLabelsChunkSeriesSet returns a ChunkSeriesSet that removes the given
+// label names from all series of the input set. Removing labels can change the
+// sort order and make series identical, so all series handles of the input set
+// are buffered and re-sorted on the first call to Next, and series whose label
+// sets become equal are merged with mergeFunc. Only the labels and chunk
+// references are held in memory; chunk data is still read lazily during
+// iteration.
+func newDropLabelsChunkSeriesSet(in storage.ChunkSeriesSet, names []string, mergeFunc storage.VerticalChunkSeriesMergeFunc) storage.ChunkSeriesSet {
+	return &dropLabelsChunkSeriesSet{in: in, names: names, mergeFunc: mergeFunc}
+}
+
+type dropLabelsChunkSeriesSet struct {
+	in        storage.ChunkSeriesSet
+	names     []string
+	mergeFunc storage.VerticalChunkSeriesMergeFunc
+
+	initialized bool
+	series      []storage.ChunkSeries
+	cur         storage.ChunkSeries
+	err         error
+}
+
+func (s *dropLabelsChunkSeriesSet) init() {
+	s.initialized = true
+	b := labels.NewBuilder(labels.EmptyLabels())
+	for s.in.Next() {
+		cs := s.in.At()
+		b.Reset(cs.Labels())
+		b.Del(s.names...)
+		s.series = append(s.series, &relabeledChunkSeries{ChunkSeries: cs, lset: b.Labels()})
+	}
+	if err := s.in.Err(); err != nil {
+		s.err = err
+		return
+	}
+	slices.SortStableFunc(s.series, func(a, b storage.ChunkSeries) int {
+		return labels.Compare(a.Labels(), b.Labels())
+	})
+}
+
+func (s *dropLabelsChunkSeriesSet) Next() bool {
+	if !s.initialized {
+		s.init()
+	}
+	if s.err != nil || len(s.series) == 0 {
+		return false
+	}
+	// Series that collapsed onto the same label set are adjacent after
+	// sorting; merge them into one.
+	i := 1
+	for i < len(s.series) && labels.Equal(s.series[0].Labels(), s.series[i].Labels()) {
+		i++
+	}
+	if i == 1 {
+		s.cur = s.series[0]
+	} else {
+		s.cur = s.mergeFunc(s.series[:i]...)
+	}
+	s.series = s.series[i:]
+	return true
+}
+
+func (s *dropLabelsChunkSeriesSet) At() storage.ChunkSeries { return s.cur }
+
+func (s *dropLabelsChunkSeriesSet) Err() error { return s.err }
+
+func (s *dropLabelsChunkSeriesSet) Warnings() annotations.Annotations { return s.in.Warnings() }
+
+// relabeledChunkSeries is a ChunkSeries with a replaced label set.
+type relabeledChunkSeries struct {
+	storage.ChunkSeries
+	lset labels.Labels
+}
+
+func (s *relabeledChunkSeries) Labels() labels.Labels { return s.lset }
@@ -579,7 +579,7 @@ func TestCompactionFailWillCleanUpTempDir(t *testing.T) {
 
 	tmpdir := t.TempDir()
 
-	require.Error(t, compactor.write(tmpdir, &BlockMeta{}, DefaultBlockPopulator{}, erringBReader{}))
+	require.Error(t, compactor.write(tmpdir, &BlockMeta{}, DefaultBlockPopulator{}, AllSortedPostings, erringBReader{}))
 	_, err = os.Stat(filepath.Join(tmpdir, BlockMeta{}.ULID.String()) + tmpForCreationBlockDirSuffix)
 	require.True(t, os.IsNotExist(err), "directory is not cleaned up")
 }
@@ -1458,6 +1458,228 @@ func TestCompactionFromHead(t *testing.T) {
 	})
 }
 
+func TestLeveledCompactorIndexFormatVersion(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		opts       LeveledCompactorOptions
+		expVersion int
+		expErr     string
+	}{
+		{
+			name:       "default",
+			expVersion: index.FormatV2,
+		},
+		{
+			name:       "roaring postings",
+			opts:       LeveledCompactorOptions{EnableRoaringPostings: true},
+			expVersion: index.FormatV4,
+		},
+		{
+			name:       "chunk summaries",
+			opts:       LeveledCompactorOptions{EnableChunkSummaries: true},
+			expVersion: index.FormatV5,
+		},
+		{
+			name:       "pin takes precedence over feature flags",
+			opts:       LeveledCompactorOptions{EnableChunkSummaries: true, IndexFormatVersion: index.FormatV2},
+			expVersion: index.FormatV2,
+		},
+		{
+			name:       "pin v4",
+			opts:       LeveledCompactorOptions{IndexFormatVersion: index.FormatV4},
+			expVersion: index.FormatV4,
+		},
+		{
+			name:       "pin v5",
+			opts:       LeveledCompactorOptions{IndexFormatVersion: index.FormatV5},
+			expVersion: index.FormatV5,
+		},
+		{
+			name:   "unwritable version",
+			opts:   LeveledCompactorOptions{IndexFormatVersion: index.FormatV3},
+			expErr: "unsupported index format version 3",
+		},
+		{
+			name:   "unknown version",
+			opts:   LeveledCompactorOptions{IndexFormatVersion: 42},
+			expErr: "unsupported index format version 42",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			compactor, err := NewLeveledCompactorWithOptions(context.Background(), nil, promslog.NewNopLogger(), []int64{1000000}, nil, tc.opts)
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, float64(tc.expVersion), prom_testutil.ToFloat64(compactor.metrics.IndexFormatVersion))
+
+			lbls := labels.FromStrings("foo", "bar")
+			smpls := []chunks.Sample{sample{t: 0, f: 1}, sample{t: 500, f: 2}}
+			head := createHead(t, nil, []storage.Series{storage.NewListSeries(lbls, smpls)}, t.TempDir())
+			defer func() { require.NoError(t, head.Close()) }()
+
+			dir := t.TempDir()
+			ulids, err := compactor.Write(dir, head, 0, 1000, nil)
+			require.NoError(t, err)
+			require.Len(t, ulids, 1)
+
+			r, err := index.NewFileReader(filepath.Join(dir, ulids[0].String(), indexFilename), index.DecodePostingsRaw)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, r.Close()) }()
+			require.Equal(t, tc.expVersion, r.Version())
+		})
+	}
+}
+
+func TestLeveledCompactor_CompactWithSplitting(t *testing.T) {
+	const (
+		numSeries          = 100
+		samplesPerSeries   = 1000
+		expSamples         = numSeries * samplesPerSeries
+		allSeriesLabelName = defaultLabelName
+	)
+	series := genSeries(numSeries, 4, 0, samplesPerSeries)
+	blockDir := createBlock(t, t.TempDir(), series)
+
+	expLabels := map[uint64]labels.Labels{}
+	for _, s := range series {
+		expLabels[labels.StableHash(s.Labels())] = s.Labels()
+	}
+
+	for _, shardCount := range []uint64{1, 3, 5} {
+		t.Run(fmt.Sprintf("shardCount=%d", shardCount), func(t *testing.T) {
+			c, err := NewLeveledCompactor(context.Background(), nil, promslog.NewNopLogger(), []int64{DefaultBlockDuration}, nil, nil)
+			require.NoError(t, err)
+
+			dest := t.TempDir()
+			ids, err := c.CompactWithSplitting(dest, []string{blockDir}, nil, shardCount)
+			require.NoError(t, err)
+			require.NotEmpty(t, ids)
+			require.LessOrEqual(t, uint64(len(ids)), shardCount)
+
+			var (
+				gotLabels    = map[uint64]labels.Labels{}
+				gotShards    = map[uint64]struct{}{}
+				totalSamples uint64
+			)
+			for _, id := range ids {
+				block, err := OpenBlock(nil, filepath.Join(dest, id.String()), nil, nil)
+				require.NoError(t, err)
+				t.Cleanup(func() { require.NoError(t, block.Close()) })
+
+				meta := block.Meta()
+				totalSamples += meta.Stats.NumSamples
+				require.Positive(t, meta.Stats.NumSamples, "empty shards must not be written")
+				require.Equal(t, 2, meta.Compaction.Level)
+				require.Equal(t, []BlockDesc{{ULID: ulid.MustParse(filepath.Base(blockDir)), MinTime: 0, MaxTime: samplesPerSeries}}, meta.Compaction.Parents)
+
+				// All series of one output block must belong to the same shard.
+				blockShard := uint64(math.MaxUint64)
+				q, err := NewBlockQuerier(block, math.MinInt64, math.MaxInt64)
+				require.NoError(t, err)
+				defer q.Close()
+
+				ss := q.Select(t.Context(), false, nil, labels.MustNewMatcher(labels.MatchRegexp, allSeriesLabelName, ".*"))
+				for ss.Next() {
+					lbls := ss.At().Labels()
+					h := labels.StableHash(lbls)
+					if blockShard == math.MaxUint64 {
+						blockShard = h % shardCount
+					} else {
+						require.Equal(t, blockShard, h%shardCount, "series %s in wrong shard", lbls)
+					}
+					gotLabels[h] = lbls.Copy()
+				}
+				require.NoError(t, ss.Err())
+
+				_, seen := gotShards[blockShard]
+				require.False(t, seen, "two output blocks hold series of the same shard")
+				gotShards[blockShard] = struct{}{}
+			}
+
+			// No series and no samples may get lost or duplicated by splitting.
+			require.Equal(t, expLabels, gotLabels)
+			require.Equal(t, uint64(expSamples), totalSamples)
+		})
+	}
+}
+
+func TestLeveledCompactor_LabelRetention(t *testing.T) {
+	now := time.Now().UnixMilli()
+	day := int64(24 * time.Hour / time.Millisecond)
+	mint := now - 40*day
+	maxt := mint + 1000
+
+	samplesFor := func(lo, hi int64) []chunks.Sample {
+		var s []chunks.Sample
+		for ts := lo; ts < hi; ts++ {
+			s = append(s, sample{t: ts, f: float64(ts)})
+		}
+		return s
+	}
+
+	// Two per-pod series of one deployment with disjoint sample ranges, plus
+	// an unrelated series that keeps its pod label value unique.
+	blockDir := createBlock(t, t.TempDir(), []storage.Series{
+		storage.NewListSeries(labels.FromStrings("__name__", "metric", "deployment", "api", "pod", "a"), samplesFor(mint, mint+500)),
+		storage.NewListSeries(labels.FromStrings("__name__", "metric", "deployment", "api", "pod", "b"), samplesFor(mint+500, maxt)),
+		storage.NewListSeries(labels.FromStrings("__name__", "metric", "deployment", "db", "pod", "c"), samplesFor(mint, maxt)),
+	})
+
+	for _, tc := range []struct {
+		name     string
+		policies []LabelRetentionPolicy
+		// Expected series and their sample counts.
+		expected map[string]int
+	}{
+		{
+			name:     "labels dropped and series merged once the block is old enough",
+			policies: []LabelRetentionPolicy{{LabelNames: []string{"pod"}, MinBlockAge: 30 * day}},
+			expected: map[string]int{
+				`{__name__="metric", deployment="api"}`: 1000,
+				`{__name__="metric", deployment="db"}`:  1000,
+			},
+		},
+		{
+			name:     "young blocks are left untouched",
+			policies: []LabelRetentionPolicy{{LabelNames: []string{"pod"}, MinBlockAge: 60 * day}},
+			expected: map[string]int{
+				`{__name__="metric", deployment="api", pod="a"}`: 500,
+				`{__name__="metric", deployment="api", pod="b"}`: 500,
+				`{__name__="metric", deployment="db", pod="c"}`:  1000,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewLeveledCompactorWithOptions(context.Background(), nil, promslog.NewNopLogger(), []int64{DefaultBlockDuration}, nil, LeveledCompactorOptions{
+				EnableOverlappingCompaction: true,
+				LabelRetention:              func() []LabelRetentionPolicy { return tc.policies },
+			})
+			require.NoError(t, err)
+
+			dest := t.TempDir()
+			ids, err := c.Compact(dest, []string{blockDir}, nil)
+			require.NoError(t, err)
+			require.Len(t, ids, 1)
+
+			block, err := OpenBlock(nil, filepath.Join(dest, ids[0].String()), nil, nil)
+			require.NoError(t, err)
+			t.Cleanup(func() { require.NoError(t, block.Close()) })
+
+			q, err := NewBlockQuerier(block, math.MinInt64, math.MaxInt64)
+			require.NoError(t, err)
+			res := query(t, q, labels.MustNewMatcher(labels.MatchEqual, "__name__", "metric"))
+
+			got := make(map[string]int, len(res))
+			for s, samples := range res {
+				got[s] = len(samples)
+			}
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
 func BenchmarkCompactionFromOOOHead(b *testing.B) {
 	dir := b.TempDir()
 	totalSeries := 100000
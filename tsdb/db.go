@@ -15,7 +15,9 @@
 package tsdb
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -134,6 +136,28 @@ type Options struct {
 	// If both MaxBytes and MaxPercentage are set, percentage prevails.
 	MaxPercentage float64
 
+	// RetentionPolicies are per-selector retention overrides. Samples of
+	// series matching a policy's matchers are dropped during compaction once
+	// they are older than the policy's retention duration. Policies only
+	// shorten retention for matching series; the global retention settings
+	// still apply to all data.
+	RetentionPolicies []RetentionPolicy
+
+	// LabelRetentionPolicies configure label names that are stripped from all
+	// series during compaction once blocks are older than the policy's
+	// minimum age, e.g. drop "pod" after 30 days but keep "deployment".
+	// Series that become identical after the labels are removed are merged,
+	// reducing long-term cardinality.
+	LabelRetentionPolicies []LabelRetentionPolicy
+
+	// BlockTiers are additional data directories for blocks with age-based
+	// placement, e.g. recent blocks on fast NVMe storage and old blocks on
+	// cheaper disks. Blocks are moved into the tier matching their age
+	// during retention maintenance and all tiers are queried transparently.
+	// Blocks younger than the smallest MinBlockAge stay in the main data
+	// directory. Empty disables tiering.
+	BlockTiers []BlockTier
+
 	// NoLockfile disables creation and consideration of a lock file.
 	NoLockfile bool
 
@@ -208,6 +232,13 @@ type Options struct {
 	// EnableSharding enables query sharding support in TSDB.
 	EnableSharding bool
 
+	// CompactionSplitShards is the number of output blocks a compaction of one
+	// time range is split into, sharded by a hash of the series labels. The
+	// shards are populated concurrently. 0 or 1 disables splitting. It only
+	// takes effect when the default leveled compactor is used, i.e. when
+	// NewCompactorFunc is unset.
+	CompactionSplitShards int
+
 	// EnableDelayedCompaction, when set to true, assigns a random value to CompactionDelay during DB opening.
 	// When set to false, delayed compaction is disabled, unless CompactionDelay is set directly.
 	EnableDelayedCompaction bool
@@ -226,6 +257,10 @@ type Options struct {
 	// BlockChunkQuerierFunc is a function to return storage.ChunkQuerier from a BlockReader.
 	BlockChunkQuerierFunc BlockChunkQuerierFunc
 
+	// SlowBlockQueryLogThreshold, when greater than zero, logs every query
+	// that spends at least this long on a single block or on the head.
+	SlowBlockQueryLogThreshold time.Duration
+
 	// PostingsDecoderFactory allows users to customize postings decoders based on BlockMeta.
 	// By default, DefaultPostingsDecoderFactory will be used to create raw posting decoder.
 	PostingsDecoderFactory PostingsDecoderFactory
@@ -233,6 +268,32 @@ type Options struct {
 	// UseUncachedIO allows bypassing the page cache when appropriate.
 	UseUncachedIO bool
 
+	// EnableRoaringPostings represents the 'roaring-postings' feature flag.
+	// If true, compacted blocks are written with a FormatV4 index, which
+	// stores high-cardinality postings lists as roaring bitmaps. Older
+	// Prometheus versions cannot read FormatV4 blocks.
+	EnableRoaringPostings bool
+
+	// EnableMmapLabelIndex represents the 'mmap-label-index' feature flag.
+	// If true, the head keeps a lazily materialized, memory-mapped snapshot
+	// of its label names and values to serve matcherless LabelNames and
+	// LabelValues calls with fewer allocations.
+	EnableMmapLabelIndex bool
+
+	// EnableChunkSummaries represents the 'chunk-summaries' feature flag.
+	// If true, compacted blocks are written with a FormatV5 index, which
+	// stores per-chunk summary statistics used to answer some over-time
+	// functions without decoding chunks. Older Prometheus versions cannot
+	// read FormatV5 blocks.
+	EnableChunkSummaries bool
+
+	// IndexFormatVersion pins the index format version written by compaction,
+	// taking precedence over EnableRoaringPostings and EnableChunkSummaries.
+	// Pinning a lower version keeps new blocks readable by older Prometheus
+	// versions, e.g. while canarying a new release. 0 selects the version
+	// from the feature flags.
+	IndexFormatVersion int
+
 	// EnableSTAsZeroSample represents 'created-timestamp-zero-ingestion' feature flag.
 	// If true, ST, if non-zero and earlier than sample timestamp, will be stored
 	// as a zero sample before the actual sample.
@@ -252,6 +313,12 @@ type Options struct {
 	// TODO(bwplotka): Implement this option as per PROM-60, currently it's noop.
 	EnableSTStorage bool
 
+	// EnableWALSampleGrouping determines whether float sample WAL/WBL records
+	// use the grouped SamplesV3 encoding, which reduces WAL volume when records
+	// contain multiple samples per series.
+	// Represents 'wal-sample-grouping' feature flag.
+	EnableWALSampleGrouping bool
+
 	// EnableMetadataWALRecords represents 'metadata-wal-records' feature flag.
 	// NOTE(bwplotka): This feature might be deprecated and removed once PROM-60
 	// is implemented.
@@ -261,6 +328,11 @@ type Options struct {
 	// It's passed down to the TSDB compactor.
 	BlockCompactionExcludeFunc BlockExcludeFilterFunc
 
+	// BlockShipper, if set, is invoked after each successful compaction or
+	// head truncation with the freshly persisted blocks, so they can be
+	// uploaded to remote object storage. Nil disables shipping.
+	BlockShipper BlockShipper
+
 	// BlockReloadInterval is the interval at which blocks are reloaded.
 	BlockReloadInterval time.Duration
 
@@ -296,6 +368,45 @@ type Options struct {
 	EnableFastStartup bool
 }
 
+// RetentionPolicy is a per-selector retention override. Samples of series
+// matching Matchers are only retained for RetentionDuration.
+type RetentionPolicy struct {
+	// Matchers select the series the policy applies to.
+	Matchers []*labels.Matcher
+
+	// RetentionDuration is how long samples of matching series are kept.
+	// Same unit as Options.RetentionDuration, typically milliseconds.
+	RetentionDuration int64
+}
+
+// LabelRetentionPolicy configures label names that are dropped from all series
+// of sufficiently old blocks during compaction.
+type LabelRetentionPolicy struct {
+	// LabelNames are the label names to drop.
+	LabelNames []string
+
+	// MinBlockAge is the minimum age of a block before the labels are
+	// dropped, measured from the block's maximum time to now. Same unit as
+	// Options.RetentionDuration, typically milliseconds. It must be greater
+	// than zero.
+	MinBlockAge int64
+}
+
+// BlockTier configures an additional data directory for blocks older than a
+// threshold.
+type BlockTier struct {
+	// Dir is the directory blocks of this tier are stored in. It must be on
+	// the same or a different filesystem than the main data directory, but
+	// must not be nested inside it.
+	Dir string
+
+	// MinBlockAge is the minimum age of a block before it is moved into
+	// this tier, measured from the block's maximum time to the maximum time
+	// of the newest block. Same unit as Options.RetentionDuration, typically
+	// milliseconds. It must be greater than zero.
+	MinBlockAge int64
+}
+
 type NewCompactorFunc func(ctx context.Context, r prometheus.Registerer, l *slog.Logger, ranges []int64, pool chunkenc.Pool, opts *Options) (Compactor, error)
 
 type BlocksToDeleteFunc func(blocks []*Block) map[ulid.ULID]struct{}
@@ -362,6 +473,16 @@ type DB struct {
 
 	writeNotified wlog.WriteNotified
 
+	// deleteAudit records delete and tombstone-clean actions on disk.
+	deleteAudit *deleteAuditLog
+
+	// shipper uploads freshly persisted blocks to remote storage. Nil when
+	// shipping is disabled.
+	shipper BlockShipper
+
+	// blockQueryStats aggregates per-block query statistics.
+	blockQueryStats *blockQueryStats
+
 	registerer prometheus.Registerer
 
 	blockQuerierFunc BlockQuerierFunc
@@ -381,6 +502,7 @@ type dbMetrics struct {
 	compactionsSkipped                 prometheus.Counter
 	sizeRetentionCount                 prometheus.Counter
 	timeRetentionCount                 prometheus.Counter
+	tierMovesCount                     prometheus.Counter
 	startTime                          prometheus.GaugeFunc
 	tombCleanTimer                     prometheus.Histogram
 	blocksBytes                        prometheus.Gauge
@@ -393,6 +515,9 @@ type dbMetrics struct {
 	selectedSeriesCompactionsTriggered prometheus.Counter
 	selectedSeriesCompactionsFailed    prometheus.Counter
 	selectedSeriesCompactionDuration   prometheus.Histogram
+	blockShipments                     prometheus.Counter
+	blockShipmentFailures              prometheus.Counter
+	blockShipmentRetries               prometheus.Counter
 }
 
 func newDBMetrics(db *DB, r prometheus.Registerer) *dbMetrics {
@@ -439,6 +564,10 @@ func newDBMetrics(db *DB, r prometheus.Registerer) *dbMetrics {
 		Name: "prometheus_tsdb_time_retentions_total",
 		Help: "The number of times that blocks were deleted because the maximum time limit was exceeded.",
 	})
+	m.tierMovesCount = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "prometheus_tsdb_block_tier_moves_total",
+		Help: "The number of times that a block was moved to a different storage tier.",
+	})
 	m.compactionsSkipped = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "prometheus_tsdb_compactions_skipped_total",
 		Help: "Total number of skipped compactions due to disabled auto compaction.",
@@ -513,6 +642,18 @@ func newDBMetrics(db *DB, r prometheus.Registerer) *dbMetrics {
 		NativeHistogramMaxBucketNumber:  100,
 		NativeHistogramMinResetDuration: 1 * time.Hour,
 	})
+	m.blockShipments = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "prometheus_tsdb_block_shipments_total",
+		Help: "Total number of blocks successfully handed to the configured block shipper.",
+	})
+	m.blockShipmentFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "prometheus_tsdb_block_shipment_failures_total",
+		Help: "Total number of blocks the configured block shipper failed to upload after all retries.",
+	})
+	m.blockShipmentRetries = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "prometheus_tsdb_block_shipment_retries_total",
+		Help: "Total number of retried block shipper upload attempts.",
+	})
 
 	if r != nil {
 		r.MustRegister(
@@ -525,6 +666,7 @@ func newDBMetrics(db *DB, r prometheus.Registerer) *dbMetrics {
 			m.compactionsSkipped,
 			m.sizeRetentionCount,
 			m.timeRetentionCount,
+			m.tierMovesCount,
 			m.startTime,
 			m.tombCleanTimer,
 			m.blocksBytes,
@@ -537,6 +679,9 @@ func newDBMetrics(db *DB, r prometheus.Registerer) *dbMetrics {
 			m.selectedSeriesCompactionsTriggered,
 			m.selectedSeriesCompactionsFailed,
 			m.selectedSeriesCompactionDuration,
+			m.blockShipments,
+			m.blockShipmentFailures,
+			m.blockShipmentRetries,
 		)
 	}
 	return m
@@ -739,6 +884,7 @@ func (db *DBReadOnly) loadDataAsQueryable(maxt int64) (storage.SampleAndChunkQue
 		blockQuerierFunc:      NewBlockQuerier,
 		blockChunkQuerierFunc: NewBlockChunkQuerier,
 		fsSizeFunc:            prom_runtime.FsSize,
+		blockQueryStats:       newBlockQueryStats(db.logger, 0),
 	}, nil
 }
 
@@ -769,7 +915,7 @@ func (db *DBReadOnly) Blocks() ([]BlockReader, error) {
 		return nil, ErrClosed
 	default:
 	}
-	loadable, corrupted, err := openBlocks(db.logger, db.dir, nil, nil, DefaultPostingsDecoderFactory)
+	loadable, corrupted, err := openBlocks(db.logger, []string{db.dir}, nil, nil, DefaultPostingsDecoderFactory)
 	if err != nil {
 		return nil, err
 	}
@@ -922,6 +1068,7 @@ func Open(dir string, l *slog.Logger, r prometheus.Registerer, opts *Options, st
 		opts.FeatureRegistry.Set(features.TSDB, "use_uncached_io", opts.UseUncachedIO)
 		opts.FeatureRegistry.Enable(features.TSDB, "native_histograms")
 		opts.FeatureRegistry.Set(features.TSDB, "st_storage", opts.EnableSTStorage)
+		opts.FeatureRegistry.Set(features.TSDB, "wal_sample_grouping", opts.EnableWALSampleGrouping)
 		opts.FeatureRegistry.Set(features.TSDB, "xor2_encoding", opts.XOR2EncodingAllowed)
 		opts.FeatureRegistry.Set(features.TSDB, "histograms_st_encoding", opts.EnableHistogramSTEncoding)
 	}
@@ -975,6 +1122,19 @@ func validateOpts(opts *Options, rngs []int64) (*Options, []int64, error) {
 	if opts.BlockReloadInterval < 1*time.Second {
 		opts.BlockReloadInterval = 1 * time.Second
 	}
+	for _, tier := range opts.BlockTiers {
+		if tier.Dir == "" {
+			return nil, nil, errors.New("block tier directory must not be empty")
+		}
+		if tier.MinBlockAge <= 0 {
+			return nil, nil, fmt.Errorf("block tier %q must have a minimum block age greater than zero", tier.Dir)
+		}
+	}
+	// Sort the tiers by age so that the tier a block belongs in is the last
+	// one whose threshold the block's age exceeds.
+	slices.SortStableFunc(opts.BlockTiers, func(a, b BlockTier) int {
+		return cmp.Compare(a.MinBlockAge, b.MinBlockAge)
+	})
 
 	if len(rngs) == 0 {
 		// Start with smallest block duration and create exponential buckets until the exceed the
@@ -1026,6 +1186,16 @@ func open(dir string, l *slog.Logger, r prometheus.Registerer, opts *Options, rn
 		}
 	}
 
+	for _, tier := range opts.BlockTiers {
+		if err := os.MkdirAll(tier.Dir, 0o777); err != nil {
+			return nil, err
+		}
+		// Remove tmp dirs left over from interrupted block moves.
+		if err := tsdbutil.RemoveTmpDirs(l, tier.Dir, isTmpDir); err != nil {
+			return nil, fmt.Errorf("remove tmp dirs: %w", err)
+		}
+	}
+
 	var wal, wbl *wlog.WL
 
 	db := &DB{
@@ -1038,8 +1208,11 @@ func open(dir string, l *slog.Logger, r prometheus.Registerer, opts *Options, rn
 		autoCompact:    true,
 		chunkPool:      chunkenc.NewPool(),
 		blocksToDelete: opts.BlocksToDelete,
+		deleteAudit:    newDeleteAuditLog(dir),
+		shipper:        opts.BlockShipper,
 		registerer:     r,
 	}
+	db.blockQueryStats = newBlockQueryStats(l, opts.SlowBlockQueryLogThreshold)
 	defer func() {
 		// Close files if startup fails somewhere.
 		if returnedErr == nil {
@@ -1093,8 +1266,12 @@ func open(dir string, l *slog.Logger, r prometheus.Registerer, opts *Options, rn
 			EnableOverlappingCompaction: opts.EnableOverlappingCompaction,
 			PD:                          opts.PostingsDecoderFactory,
 			UseUncachedIO:               opts.UseUncachedIO,
+			EnableRoaringPostings:       opts.EnableRoaringPostings,
+			EnableChunkSummaries:        opts.EnableChunkSummaries,
+			IndexFormatVersion:          opts.IndexFormatVersion,
 			BlockExcludeFilter:          opts.BlockCompactionExcludeFunc,
 			FloatChunkEncoding:          db.floatChunkEncoding,
+			LabelRetention:              db.getLabelRetentionPolicies,
 		})
 	}
 	if err != nil {
@@ -1162,10 +1339,12 @@ func open(dir string, l *slog.Logger, r prometheus.Registerer, opts *Options, rn
 	headOpts.EnableSharding = opts.EnableSharding
 	headOpts.EnableSTAsZeroSample = opts.EnableSTAsZeroSample
 	headOpts.EnableSTStorage.Store(opts.EnableSTStorage)
+	headOpts.EnableWALSampleGrouping.Store(opts.EnableWALSampleGrouping)
 	headOpts.FloatChunkEncoding.Store(uint32(opts.FloatChunkEncoding))
 	headOpts.EnableHistogramSTEncoding.Store(opts.EnableHistogramSTEncoding)
 	headOpts.EnableMetadataWALRecords = opts.EnableMetadataWALRecords
 	headOpts.EnableFastStartup = opts.EnableFastStartup
+	headOpts.EnableMmapLabelIndex = opts.EnableMmapLabelIndex
 	if opts.WALReplayConcurrency > 0 {
 		headOpts.WALReplayConcurrency = opts.WALReplayConcurrency
 	}
@@ -1263,6 +1442,17 @@ func (db *DB) BlockMetas() []BlockMeta {
 	return metas
 }
 
+// BlockQueryStats returns the per-block query statistics accumulated since
+// the DB was opened, busiest block first. Statistics for blocks that have
+// been deleted in the meantime are dropped.
+func (db *DB) BlockQueryStats() []BlockQueryStat {
+	live := make(map[string]struct{})
+	for _, b := range db.Blocks() {
+		live[b.Meta().ULID.String()] = struct{}{}
+	}
+	return db.blockQueryStats.stats(live)
+}
+
 func (db *DB) run(ctx context.Context) {
 	defer close(db.donec)
 
@@ -1383,6 +1573,22 @@ func (db *DB) ApplyConfig(conf *config.Config) error {
 			db.metrics.maxBytes.Set(float64(db.opts.MaxBytes))
 			db.opts.MaxPercentage = conf.StorageConfig.TSDBConfig.Retention.Percentage
 			db.metrics.maxPercentage.Set(db.opts.MaxPercentage)
+			policies := make([]RetentionPolicy, 0, len(conf.StorageConfig.TSDBConfig.Retention.Policies))
+			for _, p := range conf.StorageConfig.TSDBConfig.Retention.Policies {
+				policies = append(policies, RetentionPolicy{
+					Matchers:          p.Matchers,
+					RetentionDuration: time.Duration(p.Time).Milliseconds(),
+				})
+			}
+			db.opts.RetentionPolicies = policies
+			labelPolicies := make([]LabelRetentionPolicy, 0, len(conf.StorageConfig.TSDBConfig.Retention.LabelPolicies))
+			for _, p := range conf.StorageConfig.TSDBConfig.Retention.LabelPolicies {
+				labelPolicies = append(labelPolicies, LabelRetentionPolicy{
+					LabelNames:  p.Labels,
+					MinBlockAge: time.Duration(p.After).Milliseconds(),
+				})
+			}
+			db.opts.LabelRetentionPolicies = labelPolicies
 			db.retentionMtx.Unlock()
 		}
 		// Default to the startup encoding; overridden by an explicit value below.
@@ -1402,6 +1608,17 @@ func (db *DB) ApplyConfig(conf *config.Config) error {
 		oooTimeWindow = 0
 	}
 
+	// OOO support is needed as soon as the global window or any per-matcher
+	// override allows out-of-order samples.
+	maxOOOTimeWindow := oooTimeWindow
+	if conf.StorageConfig.TSDBConfig != nil {
+		for _, o := range conf.StorageConfig.TSDBConfig.OutOfOrderTimeWindowOverrides {
+			if o.TimeWindow > maxOOOTimeWindow {
+				maxOOOTimeWindow = o.TimeWindow
+			}
+		}
+	}
+
 	// Create WBL if it was not present and if OOO is enabled with WAL enabled.
 	var wblog *wlog.WL
 	var err error
@@ -1409,7 +1626,7 @@ func (db *DB) ApplyConfig(conf *config.Config) error {
 	case db.head.wbl != nil:
 		// The existing WBL from the disk might have been replayed while OOO was disabled.
 		wblog = db.head.wbl
-	case !db.oooWasEnabled.Load() && oooTimeWindow > 0 && db.opts.WALSegmentSize >= 0:
+	case !db.oooWasEnabled.Load() && maxOOOTimeWindow > 0 && db.opts.WALSegmentSize >= 0:
 		segmentSize := wlog.DefaultSegmentSize
 		// Wal is set to a custom size.
 		if db.opts.WALSegmentSize > 0 {
@@ -1426,7 +1643,7 @@ func (db *DB) ApplyConfig(conf *config.Config) error {
 	db.head.ApplyConfig(conf, wblog)
 
 	if !db.oooWasEnabled.Load() {
-		db.oooWasEnabled.Store(oooTimeWindow > 0)
+		db.oooWasEnabled.Store(maxOOOTimeWindow > 0)
 	}
 	return nil
 }
@@ -1445,6 +1662,20 @@ func (db *DB) getRetentionSettings() (int64, float64) {
 	return db.opts.MaxBytes, db.opts.MaxPercentage
 }
 
+// getRetentionPolicies returns the per-selector retention policies in a thread-safe manner.
+func (db *DB) getRetentionPolicies() []RetentionPolicy {
+	db.retentionMtx.RLock()
+	defer db.retentionMtx.RUnlock()
+	return db.opts.RetentionPolicies
+}
+
+// getLabelRetentionPolicies returns the label retention policies in a thread-safe manner.
+func (db *DB) getLabelRetentionPolicies() []LabelRetentionPolicy {
+	db.retentionMtx.RLock()
+	defer db.retentionMtx.RUnlock()
+	return db.opts.LabelRetentionPolicies
+}
+
 // dbAppender wraps the DB's head appender and triggers compactions on commit
 // if necessary.
 type dbAppender struct {
@@ -1606,9 +1837,53 @@ func (db *DB) Compact(ctx context.Context) (returnErr error) {
 		}
 	}
 
+	if err := db.applyRetentionPolicies(ctx); err != nil {
+		return fmt.Errorf("apply retention policies: %w", err)
+	}
+
 	return db.compactBlocks()
 }
 
+// applyRetentionPolicies writes tombstones for samples of series matching a
+// per-selector retention policy once they are older than the policy's
+// retention duration. The tombstoned data is hidden from queries immediately
+// and dropped from disk when the affected blocks are next compacted.
+func (db *DB) applyRetentionPolicies(ctx context.Context) error {
+	policies := db.getRetentionPolicies()
+	if len(policies) == 0 {
+		return nil
+	}
+
+	db.mtx.RLock()
+	blocks := slices.Clone(db.blocks)
+	db.mtx.RUnlock()
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	// Like time based retention, the newest data in storage is the reference
+	// point for the retention duration.
+	maxt := db.head.MaxTime()
+	for _, b := range blocks {
+		maxt = max(maxt, b.Meta().MaxTime)
+	}
+
+	for _, p := range policies {
+		// Block intervals are half-open: [b.MinTime, b.MaxTime), while
+		// tombstone intervals are closed, hence the -1.
+		cutoff := maxt - p.RetentionDuration
+		for _, b := range blocks {
+			if b.Meta().MinTime >= cutoff {
+				continue
+			}
+			if err := b.Delete(ctx, b.Meta().MinTime, cutoff-1, p.Matchers...); err != nil {
+				return fmt.Errorf("delete samples beyond retention policy in block %s: %w", b.Meta().ULID, err)
+			}
+		}
+	}
+	return nil
+}
+
 // CompactHead compacts the given RangeHead.
 func (db *DB) CompactHead(head *RangeHead) error {
 	db.cmtx.Lock()
@@ -1670,6 +1945,8 @@ func (db *DB) compactOOOHead(ctx context.Context) error {
 		return fmt.Errorf("reloadBlocks blocks after failed compact ooo head: %w", errors.Join(errs...))
 	}
 
+	db.shipBlocks(ulids)
+
 	lastWBLFile, minOOOMmapRef := oooHead.LastWBLFile(), oooHead.LastMmapRef()
 	if lastWBLFile != 0 || minOOOMmapRef != 0 {
 		if minOOOMmapRef != 0 {
@@ -1762,6 +2039,8 @@ func (db *DB) compactHead(head *RangeHead) error {
 		}
 		return errors.Join(errs...)
 	}
+	db.shipBlocks(uids)
+
 	if err = db.head.truncateMemory(head.BlockMaxTime()); err != nil {
 		return fmt.Errorf("head memory truncate: %w", err)
 	}
@@ -1853,6 +2132,8 @@ func (db *DB) compactHeadViewLocked(viewFactory headViewFactory, evict headSerie
 			}
 			return errors.Join(errs...)
 		}
+
+		db.shipBlocks(uids)
 	}
 
 	if compactHeadViewBeforeEvictTestingCallback != nil {
@@ -2016,7 +2297,12 @@ func (db *DB) compactBlocks() (err error) {
 		default:
 		}
 
-		uids, err := db.compactor.Compact(db.dir, plan, db.blocks)
+		var uids []ulid.ULID
+		if lc, ok := db.compactor.(*LeveledCompactor); ok && db.opts.CompactionSplitShards > 1 {
+			uids, err = lc.CompactWithSplitting(db.dir, plan, db.blocks, uint64(db.opts.CompactionSplitShards))
+		} else {
+			uids, err = db.compactor.Compact(db.dir, plan, db.blocks)
+		}
 		if err != nil {
 			return fmt.Errorf("compact %s: %w", plan, err)
 		}
@@ -2030,6 +2316,8 @@ func (db *DB) compactBlocks() (err error) {
 			}
 			return errors.Join(errs...)
 		}
+
+		db.shipBlocks(uids)
 	}
 
 	return nil
@@ -2074,7 +2362,7 @@ func (db *DB) reloadBlocks() (err error) {
 	}()
 
 	db.mtx.RLock()
-	loadable, corrupted, err := openBlocks(db.logger, db.dir, db.blocks, db.chunkPool, db.opts.PostingsDecoderFactory)
+	loadable, corrupted, err := openBlocks(db.logger, db.blockTierDirs(), db.blocks, db.chunkPool, db.opts.PostingsDecoderFactory)
 	db.mtx.RUnlock()
 	if err != nil {
 		return err
@@ -2117,6 +2405,11 @@ func (db *DB) reloadBlocks() (err error) {
 		return errors.Join(errs...)
 	}
 
+	// Move blocks whose age crossed a tier threshold into their tier
+	// directory. Moved blocks are reopened from the new location right away;
+	// the stale copies are cleaned up after the new blocks are exposed.
+	loadable, movedOld := db.moveBlocksBetweenTiers(loadable, deletableULIDs)
+
 	var (
 		toLoad     []*Block
 		blocksSize int64
@@ -2171,16 +2464,119 @@ func (db *DB) reloadBlocks() (err error) {
 	if err := db.deleteBlocks(deletable); err != nil {
 		return fmt.Errorf("delete %v blocks: %w", len(deletable), err)
 	}
+
+	// Close the stale copies of moved blocks and remove their directories.
+	// Closing waits for pending readers that still use the old location.
+	for _, b := range movedOld {
+		if err := b.Close(); err != nil {
+			db.logger.Warn("Closing moved block failed", "err", err, "block", b.Meta().ULID)
+		}
+		tmpToDelete := fmt.Sprintf("%s%s", b.Dir(), tmpForDeletionBlockDirSuffix)
+		if err := fileutil.Replace(b.Dir(), tmpToDelete); err != nil {
+			db.logger.Warn("Replace of stale block copy for deletion failed", "err", err, "block", b.Meta().ULID)
+			continue
+		}
+		if err := os.RemoveAll(tmpToDelete); err != nil {
+			db.logger.Warn("Deleting stale block copy failed", "err", err, "block", b.Meta().ULID)
+		}
+	}
 	return nil
 }
 
-func openBlocks(l *slog.Logger, dir string, loaded []*Block, chunkPool chunkenc.Pool, postingsDecoderFactory PostingsDecoderFactory) (blocks []*Block, corrupted map[ulid.ULID]error, err error) {
-	bDirs, err := blockDirs(dir)
-	if err != nil {
-		return nil, nil, fmt.Errorf("find blocks: %w", err)
+// blockTierDirs returns the main data directory followed by all configured
+// block tier directories, ordered by ascending minimum block age.
+func (db *DB) blockTierDirs() []string {
+	dirs := make([]string, 0, len(db.opts.BlockTiers)+1)
+	dirs = append(dirs, db.dir)
+	for _, tier := range db.opts.BlockTiers {
+		dirs = append(dirs, tier.Dir)
+	}
+	return dirs
+}
+
+// tierDirForAge returns the directory blocks of the given age belong in. It
+// relies on the tiers being sorted by ascending minimum block age.
+func (db *DB) tierDirForAge(age int64) string {
+	dir := db.dir
+	for _, tier := range db.opts.BlockTiers {
+		if age >= tier.MinBlockAge {
+			dir = tier.Dir
+		}
+	}
+	return dir
+}
+
+// moveBlocksBetweenTiers copies every block that belongs in a different tier
+// directory to its new location and reopens it from there, replacing the
+// block in the returned loadable slice. The stale blocks at the old locations
+// are returned so the caller can close and remove them once the new blocks
+// are exposed to queriers. Blocks about to be deleted are not moved. Failed
+// moves are logged and retried on the next reload.
+func (db *DB) moveBlocksBetweenTiers(loadable []*Block, deletable map[ulid.ULID]struct{}) ([]*Block, []*Block) {
+	if len(db.opts.BlockTiers) == 0 || len(loadable) == 0 {
+		return loadable, nil
+	}
+
+	// Block age is measured against the maximum time of the newest block so
+	// that tiering does not depend on the wall clock.
+	maxBlockTime := loadable[0].Meta().MaxTime
+	for _, block := range loadable[1:] {
+		maxBlockTime = max(maxBlockTime, block.Meta().MaxTime)
+	}
+
+	var movedOld []*Block
+	for i, block := range loadable {
+		meta := block.Meta()
+		if _, ok := deletable[meta.ULID]; ok {
+			continue
+		}
+		tierDir := db.tierDirForAge(maxBlockTime - meta.MaxTime)
+		if filepath.Dir(block.Dir()) == filepath.Clean(tierDir) {
+			continue
+		}
+
+		dest := filepath.Join(tierDir, meta.ULID.String())
+		tmp := fmt.Sprintf("%s%s", dest, tmpForCreationBlockDirSuffix)
+		if err := os.RemoveAll(tmp); err != nil {
+			db.logger.Warn("Removing leftover temporary block copy failed", "err", err, "block", meta.ULID, "dir", tmp)
+			continue
+		}
+		if err := fileutil.CopyDirs(block.Dir(), tmp); err != nil {
+			db.logger.Warn("Copying block to tier directory failed", "err", err, "block", meta.ULID, "dir", tierDir)
+			_ = os.RemoveAll(tmp)
+			continue
+		}
+		if err := fileutil.Replace(tmp, dest); err != nil {
+			db.logger.Warn("Replacing block in tier directory failed", "err", err, "block", meta.ULID, "dir", tierDir)
+			_ = os.RemoveAll(tmp)
+			continue
+		}
+		newBlock, err := OpenBlock(db.logger, dest, db.chunkPool, db.opts.PostingsDecoderFactory)
+		if err != nil {
+			db.logger.Warn("Opening moved block failed, keeping the old location", "err", err, "block", meta.ULID, "dir", dest)
+			_ = os.RemoveAll(dest)
+			continue
+		}
+		loadable[i] = newBlock
+		movedOld = append(movedOld, block)
+		db.metrics.tierMovesCount.Inc()
+		db.logger.Info("Moved block to storage tier", "block", meta.ULID, "dir", tierDir)
+	}
+	return loadable, movedOld
+}
+
+func openBlocks(l *slog.Logger, dirs []string, loaded []*Block, chunkPool chunkenc.Pool, postingsDecoderFactory PostingsDecoderFactory) (blocks []*Block, corrupted map[ulid.ULID]error, err error) {
+	var bDirs []string
+	for _, dir := range dirs {
+		ds, err := blockDirs(dir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("find blocks: %w", err)
+		}
+		bDirs = append(bDirs, ds...)
 	}
 
 	corrupted = make(map[ulid.ULID]error)
+	seen := make(map[ulid.ULID]struct{})
 	for _, bDir := range bDirs {
 		meta, _, err := readMetaFile(bDir)
 		if err != nil {
@@ -2188,6 +2584,15 @@ func openBlocks(l *slog.Logger, dir string, loaded []*Block, chunkPool chunkenc.
 			continue
 		}
 
+		// A block may exist in several tier directories when a move between
+		// tiers was interrupted. The copy in the earlier directory wins; the
+		// next move attempt cleans the duplicate up.
+		if _, ok := seen[meta.ULID]; ok {
+			l.Warn("Found duplicate block across data directories, likely an interrupted tier move. Skipping", "dir", bDir, "block", meta.ULID)
+			continue
+		}
+		seen[meta.ULID] = struct{}{}
+
 		// See if we already have the block in memory or open it otherwise.
 		block, open := getBlock(loaded, meta.ULID)
 		if !open {
@@ -2323,17 +2728,26 @@ func (db *DB) deleteBlocks(blocks map[ulid.ULID]*Block) error {
 			}
 		}
 
-		toDelete := filepath.Join(db.dir, ulid.String())
-		switch _, err := os.Stat(toDelete); {
-		case os.IsNotExist(err):
+		// The block may live in any of the tier directories.
+		toDelete := ""
+		for _, dir := range db.blockTierDirs() {
+			candidate := filepath.Join(dir, ulid.String())
+			switch _, err := os.Stat(candidate); {
+			case os.IsNotExist(err):
+				continue
+			case err != nil:
+				return fmt.Errorf("stat dir %v: %w", candidate, err)
+			}
+			toDelete = candidate
+			break
+		}
+		if toDelete == "" {
 			// Noop.
 			continue
-		case err != nil:
-			return fmt.Errorf("stat dir %v: %w", toDelete, err)
 		}
 
 		// Replace atomically to avoid partial block when process would crash during deletion.
-		tmpToDelete := filepath.Join(db.dir, fmt.Sprintf("%s%s", ulid, tmpForDeletionBlockDirSuffix))
+		tmpToDelete := fmt.Sprintf("%s%s", toDelete, tmpForDeletionBlockDirSuffix)
 		if err := fileutil.Replace(toDelete, tmpToDelete); err != nil {
 			return fmt.Errorf("replace of obsolete block for deletion %s: %w", ulid, err)
 		}
@@ -2539,9 +2953,67 @@ func (db *DB) ForceHeadMMap() {
 	db.head.mmapHeadChunks()
 }
 
+// SnapshotManifestFilename is the name of the manifest file written into each
+// snapshot directory. It lists the blocks the snapshot contains.
+const SnapshotManifestFilename = "manifest.json"
+
+// SnapshotManifest lists the blocks contained in a snapshot. Incremental
+// snapshots use the manifest of their base snapshot to decide which blocks
+// can be skipped.
+type SnapshotManifest struct {
+	Version int      `json:"version"`
+	Blocks  []string `json:"blocks"`
+}
+
+// ReadSnapshotManifest reads the manifest of the snapshot in dir.
+func ReadSnapshotManifest(dir string) (*SnapshotManifest, error) {
+	b, err := os.ReadFile(filepath.Join(dir, SnapshotManifestFilename))
+	if err != nil {
+		return nil, err
+	}
+	m := &SnapshotManifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func writeSnapshotManifest(dir string, m *SnapshotManifest) error {
+	b, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, SnapshotManifestFilename+".tmp")
+	if err := os.WriteFile(tmp, b, 0o666); err != nil {
+		return err
+	}
+	return fileutil.Replace(tmp, filepath.Join(dir, SnapshotManifestFilename))
+}
+
 // Snapshot writes the current data to the directory. If withHead is set to true it
 // will create a new block containing all data that's currently in the memory buffer/WAL.
 func (db *DB) Snapshot(dir string, withHead bool) error {
+	return db.snapshot(dir, withHead, nil)
+}
+
+// SnapshotIncremental writes the current data to dir like Snapshot, but
+// hard-links only blocks that are not listed in the manifest of the previous
+// snapshot at baseDir. The manifest written to dir still lists all blocks of
+// the database, so an incremental snapshot can serve as the base of the next
+// one.
+func (db *DB) SnapshotIncremental(dir, baseDir string, withHead bool) error {
+	base, err := ReadSnapshotManifest(baseDir)
+	if err != nil {
+		return fmt.Errorf("read base snapshot manifest: %w", err)
+	}
+	skip := make(map[string]struct{}, len(base.Blocks))
+	for _, b := range base.Blocks {
+		skip[b] = struct{}{}
+	}
+	return db.snapshot(dir, withHead, skip)
+}
+
+func (db *DB) snapshot(dir string, withHead bool, skip map[string]struct{}) error {
 	if dir == db.dir {
 		return errors.New("cannot snapshot into base directory")
 	}
@@ -2555,24 +3027,35 @@ func (db *DB) Snapshot(dir string, withHead bool) error {
 	db.mtx.RLock()
 	defer db.mtx.RUnlock()
 
+	manifest := &SnapshotManifest{Version: 1}
 	for _, b := range db.blocks {
+		manifest.Blocks = append(manifest.Blocks, b.Meta().ULID.String())
+		if _, ok := skip[b.Meta().ULID.String()]; ok {
+			db.logger.Debug("Skipping block present in base snapshot", "block", b)
+			continue
+		}
 		db.logger.Info("Snapshotting block", "block", b)
 
 		if err := b.Snapshot(dir); err != nil {
 			return fmt.Errorf("error snapshotting block: %s: %w", b.Dir(), err)
 		}
 	}
-	if !withHead {
-		return nil
+	if withHead {
+		mint := db.head.MinTime()
+		maxt := db.head.MaxTime()
+		head := NewRangeHead(db.head, mint, maxt)
+		// Add +1 millisecond to block maxt because block intervals are half-open: [b.MinTime, b.MaxTime).
+		// Because of this block intervals are always +1 than the total samples it includes.
+		uids, err := db.compactor.Write(dir, head, mint, maxt+1, nil)
+		if err != nil {
+			return fmt.Errorf("snapshot head block: %w", err)
+		}
+		for _, uid := range uids {
+			manifest.Blocks = append(manifest.Blocks, uid.String())
+		}
 	}
-
-	mint := db.head.MinTime()
-	maxt := db.head.MaxTime()
-	head := NewRangeHead(db.head, mint, maxt)
-	// Add +1 millisecond to block maxt because block intervals are half-open: [b.MinTime, b.MaxTime).
-	// Because of this block intervals are always +1 than the total samples it includes.
-	if _, err := db.compactor.Write(dir, head, mint, maxt+1, nil); err != nil {
-		return fmt.Errorf("snapshot head block: %w", err)
+	if err := writeSnapshotManifest(dir, manifest); err != nil {
+		return fmt.Errorf("write snapshot manifest: %w", err)
 	}
 	return nil
 }
@@ -2641,15 +3124,18 @@ func (db *DB) Querier(mint, maxt int64) (_ storage.Querier, err error) {
 	}
 
 	if headQuerier != nil {
-		blockQueriers = append(blockQueriers, headQuerier)
+		tr := db.blockQueryStats.tracker(headBlockQueryStatsID, db.head.MinTime(), db.head.MaxTime())
+		blockQueriers = append(blockQueriers, tr.querier(headQuerier))
 	}
 
 	for _, b := range blocks {
-		q, err := db.blockQuerierFunc(b, mint, maxt)
+		meta := b.Meta()
+		tr := db.blockQueryStats.tracker(meta.ULID.String(), meta.MinTime, meta.MaxTime)
+		q, err := db.blockQuerierFunc(tr.blockReader(b), mint, maxt)
 		if err != nil {
 			return nil, fmt.Errorf("open querier for block %s: %w", b, err)
 		}
-		blockQueriers = append(blockQueriers, q)
+		blockQueriers = append(blockQueriers, tr.querier(q))
 	}
 
 	return storage.NewMergeQuerier(blockQueriers, nil, storage.ChainedSeriesMerge), nil
@@ -2719,15 +3205,18 @@ func (db *DB) blockChunkQuerierForRange(mint, maxt int64) (_ []storage.ChunkQuer
 	}
 
 	if headQuerier != nil {
-		blockQueriers = append(blockQueriers, headQuerier)
+		tr := db.blockQueryStats.tracker(headBlockQueryStatsID, db.head.MinTime(), db.head.MaxTime())
+		blockQueriers = append(blockQueriers, tr.chunkQuerier(headQuerier))
 	}
 
 	for _, b := range blocks {
-		q, err := db.blockChunkQuerierFunc(b, mint, maxt)
+		meta := b.Meta()
+		tr := db.blockQueryStats.tracker(meta.ULID.String(), meta.MinTime, meta.MaxTime)
+		q, err := db.blockChunkQuerierFunc(tr.blockReader(b), mint, maxt)
 		if err != nil {
 			return nil, fmt.Errorf("open querier for block %s: %w", b, err)
 		}
-		blockQueriers = append(blockQueriers, q)
+		blockQueriers = append(blockQueriers, tr.chunkQuerier(q))
 	}
 
 	return blockQueriers, nil
@@ -2771,6 +3260,8 @@ func (db *DB) Delete(ctx context.Context, mint, maxt int64, ms ...*labels.Matche
 	db.mtx.RLock()
 	defer db.mtx.RUnlock()
 
+	before := db.tombstoneTotal(mint, maxt)
+
 	for _, b := range db.blocks {
 		if b.OverlapsClosedInterval(mint, maxt) {
 			g.Go(func(b *Block) func() error {
@@ -2784,7 +3275,55 @@ func (db *DB) Delete(ctx context.Context, mint, maxt int64, ms ...*labels.Matche
 		})
 	}
 
-	return g.Wait()
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	// Merging with existing tombstone intervals can make the total shrink,
+	// so guard against underflow.
+	affected := uint64(0)
+	if after := db.tombstoneTotal(mint, maxt); after > before {
+		affected = after - before
+	}
+	db.auditDelete(ctx, mint, maxt, affected, ms...)
+	return nil
+}
+
+// tombstoneTotal returns the number of tombstone intervals in the head and in
+// all blocks overlapping the given range. The caller must hold db.cmtx and at
+// least a read lock on db.mtx.
+func (db *DB) tombstoneTotal(mint, maxt int64) uint64 {
+	total := uint64(0)
+	for _, b := range db.blocks {
+		if b.OverlapsClosedInterval(mint, maxt) {
+			total += b.tombstones.Total()
+		}
+	}
+	if db.head.OverlapsClosedInterval(mint, maxt) {
+		total += db.head.tombstones.Total()
+	}
+	return total
+}
+
+// auditDelete records a completed Delete call in the audit log. Audit
+// failures are logged but do not fail the deletion.
+func (db *DB) auditDelete(ctx context.Context, mint, maxt int64, seriesAffected uint64, ms ...*labels.Matcher) {
+	matchers := make([]string, 0, len(ms))
+	for _, m := range ms {
+		matchers = append(matchers, m.String())
+	}
+	err := db.deleteAudit.append(DeleteAuditEntry{
+		Time:           time.Now().UnixMilli(),
+		Action:         DeleteAuditActionDelete,
+		Source:         deleteAuditSource(ctx),
+		Matchers:       matchers,
+		MinTime:        mint,
+		MaxTime:        maxt,
+		SeriesAffected: seriesAffected,
+	})
+	if err != nil {
+		db.logger.Error("failed to write delete audit log", "err", err)
+	}
 }
 
 // CleanTombstones re-writes any blocks with tombstones.
@@ -2838,9 +3377,22 @@ func (db *DB) CleanTombstones() (err error) {
 			return fmt.Errorf("reload blocks: %w", err)
 		}
 	}
+
+	if err := db.deleteAudit.append(DeleteAuditEntry{
+		Time:   time.Now().UnixMilli(),
+		Action: DeleteAuditActionCleanTombstones,
+	}); err != nil {
+		db.logger.Error("failed to write delete audit log", "err", err)
+	}
 	return nil
 }
 
+// DeleteAuditLog returns all delete and tombstone-clean actions recorded for
+// this database, oldest first.
+func (db *DB) DeleteAuditLog() ([]DeleteAuditEntry, error) {
+	return ReadDeleteAuditLog(db.dir)
+}
+
 func (db *DB) SetWriteNotified(wn wlog.WriteNotified) {
 	db.writeNotified = wn
 	// It's possible we already created the head struct, so we should also set the WN for that.
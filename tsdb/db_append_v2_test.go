@@ -7187,6 +7187,17 @@ func testHistogramAppendAndQueryHelperAppendV2(t *testing.T, floatHistogram bool
 		t.Run("changing schema", func(t *testing.T) {
 			h.Schema = 2
 			appendHistogram(t, series1, 102, h, &exp1, histogram.UnknownCounterReset)
+			// The incoming histogram had a higher resolution than the open
+			// chunk, so it was re-encoded to schema 1 at append time and we
+			// read back the reduced version.
+			reduced := h.Copy()
+			require.NoError(t, reduced.ReduceResolution(1))
+			reduced.CounterResetHint = histogram.UnknownCounterReset
+			if floatHistogram {
+				exp1[len(exp1)-1] = sample{t: minute(102), fh: reduced.ToFloat(nil)}
+			} else {
+				exp1[len(exp1)-1] = sample{t: minute(102), h: reduced}
+			}
 			testQuery(t, "foo", "bar1", map[string][]chunks.Sample{series1.String(): exp1})
 
 			// Schema back to old.
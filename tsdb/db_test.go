@@ -14,6 +14,7 @@
 package tsdb
 
 import (
+	"archive/tar"
 	"bufio"
 	"bytes"
 	"context"
@@ -22,6 +23,7 @@ import (
 	"flag"
 	"fmt"
 	"hash/crc32"
+	"io/fs"
 	"log/slog"
 	"math"
 	"math/rand"
@@ -49,6 +51,7 @@ import (
 	"go.uber.org/goleak"
 
 	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/metadata"
@@ -428,6 +431,48 @@ func TestDataNotAvailableAfterRollback(t *testing.T) {
 	require.Equal(t, 0, walFloatHistogramCount, "float histograms should not have been written to WAL")
 }
 
+func TestDBExemplarsSurviveRestart(t *testing.T) {
+	t.Parallel()
+
+	opts := DefaultOptions()
+	opts.EnableExemplarStorage = true
+	opts.MaxExemplars = 10
+	db := newTestDB(t, withOpts(opts))
+
+	ctx := context.Background()
+	lbls := labels.FromStrings(labels.MetricName, "http_requests_total", "job", "app-server")
+	exemplars := []exemplar.Exemplar{
+		{Labels: labels.FromStrings("trace_id", "abc123"), Value: 0.5, Ts: 1, HasTs: true},
+		{Labels: labels.FromStrings("trace_id", "def456"), Value: 1.5, Ts: 2, HasTs: true},
+	}
+
+	app := db.Appender(ctx)
+	for _, e := range exemplars {
+		ref, err := app.Append(0, lbls, e.Ts, e.Value)
+		require.NoError(t, err)
+		_, err = app.AppendExemplar(ref, lbls, e)
+		require.NoError(t, err)
+	}
+	require.NoError(t, app.Commit())
+	require.NoError(t, db.Close())
+
+	// Reopen the DB, replaying the WAL. The exemplars must be queryable again.
+	db = newTestDB(t, withDir(db.Dir()), withOpts(opts))
+
+	eq, err := db.ExemplarQuerier(ctx)
+	require.NoError(t, err)
+	res, err := eq.Select(0, 100, []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "http_requests_total")})
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	// The WAL record does not carry the HasTs flag, so it is not restored.
+	expected := make([]exemplar.Exemplar, 0, len(exemplars))
+	for _, e := range exemplars {
+		e.HasTs = false
+		expected = append(expected, e)
+	}
+	require.Equal(t, expected, res[0].Exemplars)
+}
+
 func TestDBAppenderAddRef(t *testing.T) {
 	db := newTestDB(t)
 
@@ -591,6 +636,84 @@ func TestDeleteSimple(t *testing.T) {
 	}
 }
 
+func TestDeleteAuditLog(t *testing.T) {
+	db := newTestDB(t)
+
+	ctx := context.Background()
+	app := db.Appender(ctx)
+	for i := range int64(10) {
+		_, err := app.Append(0, labels.FromStrings("a", "b"), i, float64(i))
+		require.NoError(t, err)
+	}
+	require.NoError(t, app.Commit())
+
+	// No audit entries before any delete.
+	entries, err := db.DeleteAuditLog()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	ctx = WithDeleteAuditSource(ctx, "127.0.0.1:9090")
+	require.NoError(t, db.Delete(ctx, 1, 3, labels.MustNewMatcher(labels.MatchEqual, "a", "b")))
+	require.NoError(t, db.CleanTombstones())
+
+	entries, err = db.DeleteAuditLog()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	del := entries[0]
+	require.Equal(t, DeleteAuditActionDelete, del.Action)
+	require.Equal(t, "127.0.0.1:9090", del.Source)
+	require.Equal(t, []string{`a="b"`}, del.Matchers)
+	require.Equal(t, int64(1), del.MinTime)
+	require.Equal(t, int64(3), del.MaxTime)
+	require.Equal(t, uint64(1), del.SeriesAffected)
+	require.NotZero(t, del.Time)
+
+	clean := entries[1]
+	require.Equal(t, DeleteAuditActionCleanTombstones, clean.Action)
+	require.GreaterOrEqual(t, clean.Time, del.Time)
+
+	// ReadDeleteAuditLog reads the same entries directly from the directory,
+	// without opening the database.
+	direct, err := ReadDeleteAuditLog(db.Dir())
+	require.NoError(t, err)
+	require.Equal(t, entries, direct)
+}
+
+func TestSampleOriginHint(t *testing.T) {
+	db := newTestDB(t)
+
+	ctx := storage.ContextWithSampleOrigin(context.Background(), chunks.OriginReplicated)
+	app := db.Appender(ctx)
+	_, err := app.Append(0, labels.FromStrings("a", "b"), 0, 1)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	// A series appended without an origin in the context stays unknown.
+	app = db.Appender(context.Background())
+	_, err = app.Append(0, labels.FromStrings("a", "c"), 0, 1)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	q, err := db.Querier(0, 10)
+	require.NoError(t, err)
+	defer q.Close()
+
+	origins := map[string]chunks.SampleOrigin{}
+	ss := q.Select(context.Background(), false, nil, labels.MustNewMatcher(labels.MatchRegexp, "a", ".+"))
+	for ss.Next() {
+		s := ss.At()
+		op, ok := s.(storage.SeriesOriginProvider)
+		require.True(t, ok)
+		origins[s.Labels().Get("a")] = op.SampleOrigin()
+	}
+	require.NoError(t, ss.Err())
+	require.Equal(t, map[string]chunks.SampleOrigin{
+		"b": chunks.OriginReplicated,
+		"c": chunks.OriginUnknown,
+	}, origins)
+}
+
 func TestAmendHistogramDatapointCausesError(t *testing.T) {
 	db := newTestDB(t)
 
@@ -769,6 +892,99 @@ func TestDB_Snapshot(t *testing.T) {
 	require.Equal(t, 1000.0, sum)
 }
 
+func TestDBBlockQueryStats(t *testing.T) {
+	db := newTestDB(t, withRngs(1000))
+
+	createBlock(t, db.Dir(), genSeries(3, 1, 0, 1000))
+	require.NoError(t, db.reloadBlocks())
+
+	app := db.Appender(context.Background())
+	_, err := app.Append(0, labels.FromStrings(defaultLabelName, "head"), 1500, 1)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	// Nothing has been queried yet.
+	require.Empty(t, db.BlockQueryStats())
+
+	querier, err := db.Querier(0, 2000)
+	require.NoError(t, err)
+	ss := querier.Select(context.Background(), false, nil, labels.MustNewMatcher(labels.MatchRegexp, defaultLabelName, ".+"))
+	var it chunkenc.Iterator
+	for ss.Next() {
+		it = ss.At().Iterator(it)
+		for it.Next() != chunkenc.ValNone {
+		}
+	}
+	require.NoError(t, ss.Err())
+	require.NoError(t, querier.Close())
+
+	stats := db.BlockQueryStats()
+	require.Len(t, stats, 2)
+	byULID := make(map[string]BlockQueryStat, len(stats))
+	for _, s := range stats {
+		byULID[s.ULID] = s
+	}
+
+	blockMeta := db.Blocks()[0].Meta()
+	blockStat, ok := byULID[blockMeta.ULID.String()]
+	require.True(t, ok, "expected an entry for the persisted block")
+	require.Equal(t, int64(1), blockStat.QueryCount)
+	require.Equal(t, blockMeta.MinTime, blockStat.MinTime)
+	require.Equal(t, blockMeta.MaxTime, blockStat.MaxTime)
+	require.Positive(t, blockStat.BytesRead)
+
+	headStat, ok := byULID[headBlockQueryStatsID]
+	require.True(t, ok, "expected an entry for the head")
+	require.Equal(t, int64(1), headStat.QueryCount)
+	require.Zero(t, headStat.BytesRead)
+
+	// A second query accumulates on top of the first.
+	querier, err = db.Querier(0, 2000)
+	require.NoError(t, err)
+	require.NoError(t, querier.Close())
+	for _, s := range db.BlockQueryStats() {
+		require.Equal(t, int64(2), s.QueryCount)
+	}
+}
+
+func TestDB_SnapshotIncremental(t *testing.T) {
+	db := newTestDB(t, withRngs(1000))
+
+	createBlock(t, db.Dir(), genSeries(10, 2, 0, 1000))
+	require.NoError(t, db.reloadBlocks())
+
+	// Take a full snapshot of the first block.
+	baseSnap := filepath.Join(t.TempDir(), "base")
+	require.NoError(t, db.Snapshot(baseSnap, false))
+
+	baseManifest, err := ReadSnapshotManifest(baseSnap)
+	require.NoError(t, err)
+	require.Len(t, baseManifest.Blocks, 1)
+
+	// Add a second block and take an incremental snapshot against the first.
+	createBlock(t, db.Dir(), genSeries(10, 2, 1000, 2000))
+	require.NoError(t, db.reloadBlocks())
+
+	incSnap := filepath.Join(t.TempDir(), "inc")
+	require.NoError(t, db.SnapshotIncremental(incSnap, baseSnap, false))
+
+	// The incremental snapshot must contain only the new block but list both
+	// blocks in its manifest.
+	incManifest, err := ReadSnapshotManifest(incSnap)
+	require.NoError(t, err)
+	require.Len(t, incManifest.Blocks, 2)
+
+	for i, b := range db.Blocks() {
+		_, err := os.Stat(filepath.Join(incSnap, b.Meta().ULID.String()))
+		if i == 0 {
+			require.True(t, os.IsNotExist(err), "expected base block to be skipped")
+		} else {
+			require.NoError(t, err, "expected new block to be snapshotted")
+		}
+		require.Equal(t, b.Meta().ULID.String(), incManifest.Blocks[i])
+	}
+}
+
 // TestDB_Snapshot_ChunksOutsideOfCompactedRange ensures that a snapshot removes chunks samples
 // that are outside the set block time range.
 // See https://github.com/prometheus/prometheus/issues/5105
@@ -1537,6 +1753,105 @@ func TestTimeRetention(t *testing.T) {
 	}
 }
 
+func TestDBBlockTiers(t *testing.T) {
+	t.Parallel()
+
+	coldDir := t.TempDir()
+	opts := DefaultOptions()
+	opts.BlockTiers = []BlockTier{{Dir: coldDir, MinBlockAge: 1000}}
+	db := newTestDB(t, withOpts(opts), withRngs(1000))
+
+	blocks := []*BlockMeta{
+		{MinTime: 500, MaxTime: 900}, // Oldest block, old enough for the cold tier.
+		{MinTime: 1000, MaxTime: 1500},
+		{MinTime: 1500, MaxTime: 2000}, // Newest block.
+	}
+	for _, m := range blocks {
+		createBlock(t, db.Dir(), genSeries(10, 10, m.MinTime, m.MaxTime))
+	}
+
+	// The reload registers the blocks and moves the oldest one to the cold
+	// tier, as its age relative to the newest block exceeds the threshold.
+	require.NoError(t, db.reloadBlocks())
+
+	actBlocks := db.Blocks()
+	require.Len(t, actBlocks, 3)
+	require.Equal(t, 1, int(prom_testutil.ToFloat64(db.metrics.tierMovesCount)), "metric tier moves count mismatch")
+	require.Equal(t, coldDir, filepath.Dir(actBlocks[0].Dir()), "expected the oldest block in the cold tier")
+	for _, b := range actBlocks[1:] {
+		require.Equal(t, db.Dir(), filepath.Dir(b.Dir()), "expected recent blocks in the main directory")
+	}
+
+	// The stale copy in the main directory is cleaned up.
+	mainDirs, err := blockDirs(db.Dir())
+	require.NoError(t, err)
+	require.Len(t, mainDirs, 2)
+
+	// All tiers are queried transparently.
+	q, err := db.Querier(math.MinInt64, math.MaxInt64)
+	require.NoError(t, err)
+	res := query(t, q, labels.MustNewMatcher(labels.MatchRegexp, defaultLabelName, ".+"))
+	require.Len(t, res, 10)
+	for _, samples := range res {
+		require.Len(t, samples, 1400, "expected samples of all three blocks")
+	}
+
+	// A subsequent reload does not move anything else.
+	require.NoError(t, db.reloadBlocks())
+	require.Equal(t, 1, int(prom_testutil.ToFloat64(db.metrics.tierMovesCount)), "metric tier moves count mismatch")
+
+	// Time retention deletes blocks from the cold tier as well.
+	db.opts.RetentionDuration = 500
+	require.NoError(t, db.reloadBlocks())
+
+	actBlocks = db.Blocks()
+	require.Len(t, actBlocks, 1)
+	require.Equal(t, int64(2000), actBlocks[0].meta.MaxTime)
+	coldDirs, err := blockDirs(coldDir)
+	require.NoError(t, err)
+	require.Empty(t, coldDirs, "expected the cold tier block to be deleted by retention")
+}
+
+func TestRetentionPolicies(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t, withRngs(1000))
+
+	seriesFor := func(job string, mint, maxt int64) storage.Series {
+		var samples []chunks.Sample
+		for ts := mint; ts < maxt; ts++ {
+			samples = append(samples, sample{t: ts, f: float64(ts)})
+		}
+		return storage.NewListSeries(labels.FromStrings("__name__", "metric", "job", job), samples)
+	}
+
+	// An old and a recent block, each holding a debug and a prod series.
+	for _, r := range [][2]int64{{0, 500}, {2000, 2500}} {
+		createBlock(t, db.Dir(), []storage.Series{
+			seriesFor("debug", r[0], r[1]),
+			seriesFor("prod", r[0], r[1]),
+		})
+	}
+	require.NoError(t, db.reloadBlocks())
+	require.Len(t, db.Blocks(), 2)
+
+	db.opts.RetentionPolicies = []RetentionPolicy{{
+		Matchers:          []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "job", "debug")},
+		RetentionDuration: 1000,
+	}}
+	require.NoError(t, db.applyRetentionPolicies(context.Background()))
+
+	// The debug series must be gone from the old block while the prod series
+	// and all recent data are untouched.
+	q, err := db.Querier(math.MinInt64, math.MaxInt64)
+	require.NoError(t, err)
+	res := query(t, q, labels.MustNewMatcher(labels.MatchRegexp, "job", ".+"))
+
+	require.Len(t, res, 2)
+	require.Len(t, res[`{__name__="metric", job="debug"}`], 500, "expected old debug samples to be dropped")
+	require.Len(t, res[`{__name__="metric", job="prod"}`], 1000, "expected prod samples to be retained")
+}
+
 func TestRetentionDurationMetric(t *testing.T) {
 	db := newTestDB(t, withOpts(&Options{
 		RetentionDuration: 1000,
@@ -7190,6 +7505,72 @@ func testOOODisabled(t *testing.T, scenario sampleTypeScenario) {
 	require.Nil(t, ms.ooo)
 }
 
+func TestOOOTimeWindowOverrides(t *testing.T) {
+	opts := DefaultOptions()
+	opts.OutOfOrderTimeWindow = 0
+	db := newTestDB(t, withOpts(opts))
+	db.DisableCompactions()
+
+	// OOO stays globally disabled, but the iot-gateway job gets a 1h window.
+	require.NoError(t, db.ApplyConfig(&config.Config{
+		StorageConfig: config.StorageConfig{
+			TSDBConfig: &config.TSDBConfig{
+				OutOfOrderTimeWindow: 0,
+				OutOfOrderTimeWindowOverrides: []config.OOOTimeWindowOverrideConfig{{
+					Selector:   `{job="iot-gateway"}`,
+					Matchers:   []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "job", "iot-gateway")},
+					TimeWindow: 60 * time.Minute.Milliseconds(),
+				}},
+			},
+		},
+	}))
+
+	iot := labels.FromStrings("foo", "bar", "job", "iot-gateway")
+	other := labels.FromStrings("foo", "bar", "job", "other")
+	minutes := func(m int64) int64 { return m * time.Minute.Milliseconds() }
+	expSamples := make(map[string][]chunks.Sample)
+
+	addSample := func(lbls labels.Labels, mins int64, faceError bool) {
+		app := db.Appender(context.Background())
+		_, err := app.Append(0, lbls, minutes(mins), float64(mins))
+		if faceError {
+			require.Error(t, err)
+			require.NoError(t, app.Rollback())
+			return
+		}
+		require.NoError(t, err)
+		require.NoError(t, app.Commit())
+		expSamples[lbls.String()] = append(expSamples[lbls.String()], sample{t: minutes(mins), f: float64(mins)})
+	}
+
+	// In-order samples for both series.
+	addSample(iot, 300, false)
+	addSample(other, 300, false)
+	// Within the override window: accepted for the overridden series only.
+	addSample(iot, 260, false)
+	addSample(other, 260, true)
+	// Outside the override window: rejected for both.
+	addSample(iot, 200, true)
+	addSample(other, 200, true)
+
+	// Sort as the OOO sample is appended out of order.
+	for k := range expSamples {
+		sort.Slice(expSamples[k], func(i, j int) bool { return expSamples[k][i].T() < expSamples[k][j].T() })
+	}
+
+	querier, err := db.Querier(math.MinInt64, math.MaxInt64)
+	require.NoError(t, err)
+	seriesSet := query(t, querier, labels.MustNewMatcher(labels.MatchEqual, "foo", "bar"))
+	requireEqualSeries(t, expSamples, seriesSet, true)
+	requireEqualOOOSamples(t, 1, db)
+
+	// Dropping the override disables OOO ingestion for the series again.
+	require.NoError(t, db.ApplyConfig(&config.Config{
+		StorageConfig: config.StorageConfig{TSDBConfig: &config.TSDBConfig{OutOfOrderTimeWindow: 0}},
+	}))
+	addSample(iot, 270, true)
+}
+
 func TestWBLAndMmapReplay(t *testing.T) {
 	for name, scenario := range sampleTypeScenarios {
 		t.Run(name, func(t *testing.T) {
@@ -8901,6 +9282,17 @@ func testHistogramAppendAndQueryHelper(t *testing.T, floatHistogram bool) {
 		t.Run("changing schema", func(t *testing.T) {
 			h.Schema = 2
 			appendHistogram(t, series1, 102, h, &exp1, histogram.UnknownCounterReset)
+			// The incoming histogram had a higher resolution than the open
+			// chunk, so it was re-encoded to schema 1 at append time and we
+			// read back the reduced version.
+			reduced := h.Copy()
+			require.NoError(t, reduced.ReduceResolution(1))
+			reduced.CounterResetHint = histogram.UnknownCounterReset
+			if floatHistogram {
+				exp1[len(exp1)-1] = sample{t: minute(102), fh: reduced.ToFloat(nil)}
+			} else {
+				exp1[len(exp1)-1] = sample{t: minute(102), h: reduced}
+			}
 			testQuery(t, "foo", "bar1", map[string][]chunks.Sample{series1.String(): exp1})
 
 			// Schema back to old.
@@ -9647,7 +10039,7 @@ func TestBlockClosingBlockedDuringRemoteRead(t *testing.T) {
 		nil, nil, db,
 		func() config.Config {
 			return config.Config{}
-		}, 0, 1, 0,
+		}, 0, 1, 0, 0, 0, 0,
 	)
 
 	matcher, err := labels.NewMatcher(labels.MatchRegexp, "__name__", ".*")
@@ -11562,3 +11954,188 @@ func TestBeyondSizeRetentionWithPercentage(t *testing.T) {
 	require.Len(t, deletable, 1)
 	require.Contains(t, deletable, ulid)
 }
+
+// tarBlockDir archives the files of the block at dir into a tar stream. All
+// entry names are prefixed with prefix when it is non-empty, mimicking an
+// archive created from the parent directory of a block.
+func tarBlockDir(t *testing.T, dir, prefix string) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	require.NoError(t, filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		rel, err := filepath.Rel(dir, path)
+		require.NoError(t, err)
+		if rel == "." {
+			return nil
+		}
+		name := filepath.ToSlash(filepath.Join(prefix, rel))
+		if d.IsDir() {
+			return tw.WriteHeader(&tar.Header{Name: name + "/", Typeflag: tar.TypeDir, Mode: 0o777})
+		}
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		if err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o666, Size: int64(len(b))}); err != nil {
+			return err
+		}
+		_, err = tw.Write(b)
+		return err
+	}))
+	require.NoError(t, tw.Close())
+	return buf
+}
+
+func TestDBImportBlock(t *testing.T) {
+	db := newTestDB(t)
+
+	blockDir := createBlock(t, t.TempDir(), genSeries(3, 2, 0, 100))
+
+	uid, err := db.ImportBlock(tarBlockDir(t, blockDir, ""))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Base(blockDir), uid.String())
+	require.Len(t, db.Blocks(), 1)
+	require.Equal(t, uid, db.Blocks()[0].Meta().ULID)
+
+	// The imported data must be queryable.
+	q, err := db.Querier(0, 100)
+	require.NoError(t, err)
+	res := query(t, q, labels.MustNewMatcher(labels.MatchRegexp, defaultLabelName, ".+"))
+	require.Len(t, res, 3)
+
+	t.Run("duplicate block is rejected", func(t *testing.T) {
+		_, err := db.ImportBlock(tarBlockDir(t, blockDir, ""))
+		require.ErrorContains(t, err, "already exists")
+		require.Len(t, db.Blocks(), 1)
+	})
+
+	t.Run("overlapping block is rejected", func(t *testing.T) {
+		overlapping := createBlock(t, t.TempDir(), genSeries(3, 2, 50, 150))
+		_, err := db.ImportBlock(tarBlockDir(t, overlapping, ""))
+		require.ErrorContains(t, err, "overlaps")
+		require.Len(t, db.Blocks(), 1)
+	})
+
+	t.Run("archive with a top-level directory", func(t *testing.T) {
+		nested := createBlock(t, t.TempDir(), genSeries(3, 2, 200, 300))
+		uid, err := db.ImportBlock(tarBlockDir(t, nested, filepath.Base(nested)))
+		require.NoError(t, err)
+		require.Len(t, db.Blocks(), 2)
+		require.Equal(t, uid, db.Blocks()[1].Meta().ULID)
+	})
+
+	t.Run("garbage input is rejected", func(t *testing.T) {
+		_, err := db.ImportBlock(bytes.NewBufferString("this is not a tar archive"))
+		require.Error(t, err)
+		require.Len(t, db.Blocks(), 2)
+	})
+
+	t.Run("block overlapping the head is rejected", func(t *testing.T) {
+		app := db.Appender(context.Background())
+		_, err := app.Append(0, labels.FromStrings("foo", "bar"), 500, 1)
+		require.NoError(t, err)
+		require.NoError(t, app.Commit())
+
+		overlapping := createBlock(t, t.TempDir(), genSeries(3, 2, 400, 600))
+		_, err = db.ImportBlock(tarBlockDir(t, overlapping, ""))
+		require.ErrorContains(t, err, "head")
+		require.Len(t, db.Blocks(), 2)
+	})
+}
+
+func TestDBExportBlock(t *testing.T) {
+	db := newTestDB(t)
+
+	app := db.Appender(context.Background())
+	for i := int64(0); i < 100; i++ {
+		_, err := app.Append(0, labels.FromStrings("__name__", "http_requests_total", "job", "api", "instance", "a"), i*10, float64(i))
+		require.NoError(t, err)
+		_, err = app.Append(0, labels.FromStrings("__name__", "http_requests_total", "job", "db", "instance", "b"), i*10, float64(2*i))
+		require.NoError(t, err)
+	}
+	require.NoError(t, app.Commit())
+
+	apiMatchers := []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "job", "api")}
+
+	var buf bytes.Buffer
+	uid, err := db.ExportBlock(context.Background(), &buf, 0, 1000, apiMatchers)
+	require.NoError(t, err)
+	require.NotEqual(t, ulid.ULID{}, uid)
+
+	// The export must not leave temporary directories or new blocks behind.
+	entries, err := os.ReadDir(db.Dir())
+	require.NoError(t, err)
+	for _, e := range entries {
+		require.NotContains(t, e.Name(), tmpForCreationBlockDirSuffix)
+		require.NotEqual(t, uid.String(), e.Name())
+	}
+
+	// The archive must be importable into another database and contain
+	// exactly the selected series.
+	db2 := newTestDB(t)
+	imported, err := db2.ImportBlock(&buf)
+	require.NoError(t, err)
+	require.Equal(t, uid, imported)
+
+	q, err := db2.Querier(0, 1000)
+	require.NoError(t, err)
+	res := query(t, q, labels.MustNewMatcher(labels.MatchRegexp, "job", ".+"))
+	require.Len(t, res, 1)
+	samples := res[`{__name__="http_requests_total", instance="a", job="api"}`]
+	require.Len(t, samples, 100)
+	require.Equal(t, int64(990), samples[99].T())
+	require.Equal(t, 99.0, samples[99].F())
+
+	t.Run("union of matcher sets", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := db.ExportBlock(context.Background(), &buf, 0, 1000,
+			apiMatchers, []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "job", "db")})
+		require.NoError(t, err)
+
+		db3 := newTestDB(t)
+		_, err = db3.ImportBlock(&buf)
+		require.NoError(t, err)
+
+		q, err := db3.Querier(0, 1000)
+		require.NoError(t, err)
+		res := query(t, q, labels.MustNewMatcher(labels.MatchRegexp, "job", ".+"))
+		require.Len(t, res, 2)
+	})
+
+	t.Run("time range restricts the export", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := db.ExportBlock(context.Background(), &buf, 500, 1000, apiMatchers)
+		require.NoError(t, err)
+
+		db4 := newTestDB(t)
+		_, err = db4.ImportBlock(&buf)
+		require.NoError(t, err)
+
+		q, err := db4.Querier(0, 1000)
+		require.NoError(t, err)
+		res := query(t, q, apiMatchers...)
+		require.Len(t, res, 1)
+		samples := res[`{__name__="http_requests_total", instance="a", job="api"}`]
+		require.Len(t, samples, 50)
+		require.Equal(t, int64(500), samples[0].T())
+	})
+
+	t.Run("nothing matches the matchers", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := db.ExportBlock(context.Background(), &buf, 0, 1000,
+			[]*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "job", "missing")})
+		require.ErrorIs(t, err, ErrNothingToExport)
+		require.Zero(t, buf.Len())
+	})
+
+	t.Run("nothing matches the time range", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := db.ExportBlock(context.Background(), &buf, 5000, 6000, apiMatchers)
+		require.ErrorIs(t, err, ErrNothingToExport)
+		require.Zero(t, buf.Len())
+	})
+
+	t.Run("no matcher sets", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := db.ExportBlock(context.Background(), &buf, 0, 1000)
+		require.Error(t, err)
+	})
+}
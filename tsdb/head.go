@@ -88,6 +88,10 @@ type Head struct {
 	// This should be typecasted to chunks.ChunkDiskMapperRef after loading.
 	minOOOMmapRef atomic.Uint64
 
+	// Per-matcher overrides of the out-of-order time window. Series matching
+	// an override use its window instead of OutOfOrderTimeWindow.
+	oooTimeWindowOverrides atomic.Pointer[[]OOOTimeWindowOverride]
+
 	metrics         *headMetrics
 	opts            *HeadOptions
 	wal, wbl        *wlog.WL
@@ -116,6 +120,7 @@ type Head struct {
 	wlReplayFloatHistogramsPool zeropool.Pool[[]record.RefFloatHistogramSample]
 	wlReplayMetadataPool        zeropool.Pool[[]record.RefMetadata]
 	wlReplayMmapMarkersPool     zeropool.Pool[[]record.RefMmapMarker]
+	wlReplayRecordsPool         zeropool.Pool[[]byte]
 
 	// All series addressable by their ID or hash.
 	series *stripeSeries
@@ -126,6 +131,14 @@ type Head struct {
 	// TODO(codesome): Extend MemPostings to return only OOOPostings, Set OOOStatus, ... Like an additional map of ooo postings.
 	postings *index.MemPostings // Postings lists for terms.
 
+	// labelIndex is the optional mmap-backed label enumeration cache. Nil
+	// unless HeadOptions.EnableMmapLabelIndex is set.
+	labelIndex *mmapLabelIndex
+
+	// labelPartitions is the time-partitioned label value index of the head.
+	// It is maintained incrementally as samples are committed.
+	labelPartitions *labelPartitions
+
 	tombstones *tombstones.MemTombstones
 
 	iso *isolation
@@ -179,6 +192,11 @@ type HeadOptions struct {
 	// Represents 'st-storage' feature flag.
 	EnableSTStorage atomic.Bool
 
+	// EnableWALSampleGrouping determines whether float sample WAL/WBL records
+	// use the grouped SamplesV3 encoding.
+	// Represents 'wal-sample-grouping' feature flag.
+	EnableWALSampleGrouping atomic.Bool
+
 	// FloatChunkEncoding is the encoding applied to new float chunks.
 	// Updated atomically on config reload. Always initialise via DefaultHeadOptions();
 	// the zero value (EncNone) is not a valid sentinel.
@@ -233,6 +251,12 @@ type HeadOptions struct {
 
 	// EnableFastStartup enables scraping in parallel with WAL replay but with queries still disabled.
 	EnableFastStartup bool
+
+	// EnableMmapLabelIndex enables a lazily materialized, memory-mapped
+	// snapshot of the head's label names and values that serves matcherless
+	// LabelNames and LabelValues calls with fewer allocations.
+	// Represents the 'mmap-label-index' feature flag.
+	EnableMmapLabelIndex bool
 }
 
 const (
@@ -331,6 +355,13 @@ func NewHead(r prometheus.Registerer, l *slog.Logger, wal, wbl *wlog.WL, opts *H
 		return nil, err
 	}
 
+	if opts.EnableMmapLabelIndex {
+		h.labelIndex, err = newMmapLabelIndex(opts.ChunkDirRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if opts.ChunkPool == nil {
 		opts.ChunkPool = chunkenc.NewPool()
 	}
@@ -390,8 +421,14 @@ func (h *Head) resetInMemoryState() error {
 	h.exemplarMetrics = em
 	h.exemplars = es
 	h.postings = index.NewUnorderedMemPostings()
+	if h.labelIndex != nil {
+		// The version counter of the new postings index starts over, so the
+		// cached snapshot can no longer be trusted.
+		h.labelIndex.invalidate()
+	}
 	h.tombstones = tombstones.NewMemTombstones()
 	h.walExpiries = map[chunks.HeadSeriesRef]int64{}
+	h.labelPartitions = newLabelPartitions(h.opts.ChunkRange)
 	h.chunkRange.Store(h.opts.ChunkRange)
 	h.minTime.Store(math.MaxInt64)
 	h.maxTime.Store(math.MinInt64)
@@ -411,6 +448,7 @@ func (h *Head) resetWLReplayResources() {
 	h.wlReplayFloatHistogramsPool = zeropool.Pool[[]record.RefFloatHistogramSample]{}
 	h.wlReplayMetadataPool = zeropool.Pool[[]record.RefMetadata]{}
 	h.wlReplayMmapMarkersPool = zeropool.Pool[[]record.RefMmapMarker]{}
+	h.wlReplayRecordsPool = zeropool.Pool[[]byte]{}
 }
 
 type headMetrics struct {
@@ -1118,14 +1156,31 @@ func (h *Head) removeCorruptedMmappedChunks(err error) (map[chunks.HeadSeriesRef
 
 func (h *Head) ApplyConfig(cfg *config.Config, wbl *wlog.WL) {
 	oooTimeWindow := int64(0)
+	var overrides []OOOTimeWindowOverride
 	if cfg.StorageConfig.TSDBConfig != nil {
 		oooTimeWindow = cfg.StorageConfig.TSDBConfig.OutOfOrderTimeWindow
+		for _, o := range cfg.StorageConfig.TSDBConfig.OutOfOrderTimeWindowOverrides {
+			overrides = append(overrides, OOOTimeWindowOverride{
+				Matchers:   o.Matchers,
+				TimeWindow: o.TimeWindow,
+			})
+		}
 	}
 	if oooTimeWindow < 0 {
 		oooTimeWindow = 0
 	}
 
+	// An override can enable OOO ingestion even when the global window is 0,
+	// in which case the WBL must be attached here as SetOutOfOrderTimeWindow
+	// would ignore it.
+	for _, o := range overrides {
+		if o.TimeWindow > 0 && h.wbl == nil {
+			h.wbl = wbl
+			break
+		}
+	}
 	h.SetOutOfOrderTimeWindow(oooTimeWindow, wbl)
+	h.SetOutOfOrderTimeWindowOverrides(overrides)
 
 	if !h.opts.EnableExemplarStorage {
 		return
@@ -1158,6 +1213,62 @@ func (h *Head) SetOutOfOrderTimeWindow(oooTimeWindow int64, wbl *wlog.WL) {
 	h.opts.OutOfOrderTimeWindow.Store(oooTimeWindow)
 }
 
+// OOOTimeWindowOverride overrides the out-of-order time window for series
+// matching its matchers. The window unit matches OutOfOrderTimeWindow,
+// typically milliseconds.
+type OOOTimeWindowOverride struct {
+	// Matchers select the series the override applies to.
+	Matchers []*labels.Matcher
+
+	// TimeWindow is the out-of-order time window for matching series.
+	// It may be 0 to disable out-of-order ingestion for them.
+	TimeWindow int64
+}
+
+// SetOutOfOrderTimeWindowOverrides updates the per-matcher out-of-order time
+// window overrides and re-resolves the effective window of all known series.
+func (h *Head) SetOutOfOrderTimeWindowOverrides(overrides []OOOTimeWindowOverride) {
+	h.oooTimeWindowOverrides.Store(&overrides)
+
+	for i := range h.series.series {
+		h.series.locks[i].RLock()
+		for _, s := range h.series.series[i] {
+			w := oooTimeWindowForSeries(overrides, s.labels())
+			s.Lock()
+			s.oooTimeWindow = w
+			s.Unlock()
+		}
+		h.series.locks[i].RUnlock()
+	}
+}
+
+// oooTimeWindowForSeries returns the window of the first override matching
+// lset, or -1 when no override matches and the global window applies.
+func oooTimeWindowForSeries(overrides []OOOTimeWindowOverride, lset labels.Labels) int64 {
+Outer:
+	for _, o := range overrides {
+		for _, m := range o.Matchers {
+			if !m.Matches(lset.Get(m.Name)) {
+				continue Outer
+			}
+		}
+		return o.TimeWindow
+	}
+	return -1
+}
+
+// maxOOOTimeWindow returns the widest out-of-order time window any series can
+// have, taking per-matcher overrides into account.
+func (h *Head) maxOOOTimeWindow() int64 {
+	maxWindow := h.opts.OutOfOrderTimeWindow.Load()
+	if overrides := h.oooTimeWindowOverrides.Load(); overrides != nil {
+		for _, o := range *overrides {
+			maxWindow = max(maxWindow, o.TimeWindow)
+		}
+	}
+	return maxWindow
+}
+
 // PostingsCardinalityStats returns highest cardinality stats by label and value names.
 func (h *Head) PostingsCardinalityStats(statsByLabelName string, limit int) *index.PostingsStats {
 	cacheKey := statsByLabelName + ";" + strconv.Itoa(limit)
@@ -1640,11 +1751,11 @@ func (h *Head) truncateSeriesAndChunkDiskMapper(caller string) error {
 			h.minValidTime.Store(appendableMinValidTime)
 		}
 	}
-	if headMaxt-h.opts.OutOfOrderTimeWindow.Load() < minOOOTime {
+	if maxOOOWindow := h.maxOOOTimeWindow(); headMaxt-maxOOOWindow < minOOOTime {
 		// The allowed OOO window is lower than the min OOO time seen during GC.
 		// So it is possible that some OOO sample was inserted that was less that minOOOTime.
 		// So we play safe and set it to the min that was possible.
-		minOOOTime = headMaxt - h.opts.OutOfOrderTimeWindow.Load()
+		minOOOTime = headMaxt - maxOOOWindow
 	}
 	h.minOOOTime.Store(minOOOTime)
 
@@ -1714,6 +1825,12 @@ func (h *RangeHead) Tombstones() (tombstones.Reader, error) {
 	return h.head.tombstones, nil
 }
 
+// labelValuePartitions implements labelPartitionReader by delegating to the
+// underlying head.
+func (h *RangeHead) labelValuePartitions(mint, maxt int64) *labelPartitions {
+	return h.head.labelValuePartitions(mint, maxt)
+}
+
 func (h *RangeHead) MinTime() int64 {
 	return h.mint
 }
@@ -2010,6 +2127,49 @@ func (h *Head) MaxOOOTime() int64 {
 	return h.maxOOOTime.Load()
 }
 
+// labelValuePartitions implements labelPartitionReader. It returns nil when
+// the requested range covers all data in the head, as no label value could be
+// filtered out then.
+func (h *Head) labelValuePartitions(mint, maxt int64) *labelPartitions {
+	headMint, headMaxt := h.MinTime(), h.MaxTime()
+	if oooMint := h.MinOOOTime(); oooMint < headMint {
+		headMint = oooMint
+	}
+	if oooMaxt := h.MaxOOOTime(); oooMaxt > headMaxt {
+		headMaxt = oooMaxt
+	}
+	if mint <= headMint && maxt >= headMaxt {
+		return nil
+	}
+	return h.labelPartitions
+}
+
+// observeLabelPartition records in the head's time-partitioned label value
+// index that series s received a sample at time t. The caller must hold the
+// series lock. On the first observation of a series after startup, the mask
+// covers the series' whole existing data range, so that samples restored from
+// the WAL are accounted for as well.
+func (h *Head) observeLabelPartition(s *memSeries, t int64) {
+	lp := h.labelPartitions
+	p := lp.partition(t)
+	if p == s.lastLabelPartition {
+		return
+	}
+	mask := maskBit(p)
+	if s.lastLabelPartition == math.MinInt64 {
+		if mint := s.minTime(); mint != math.MinInt64 {
+			mask |= lp.rangeMask(mint, s.maxTime())
+		}
+		if s.ooo != nil {
+			// The in-order bounds do not cover out-of-order chunks; be
+			// conservative rather than tracking their ranges separately.
+			mask = ^uint64(0)
+		}
+	}
+	s.lastLabelPartition = p
+	lp.add(s.labels(), mask)
+}
+
 // initialized returns true if the head has a MinTime set, false otherwise.
 func (h *Head) initialized() bool {
 	return h.MinTime() != math.MaxInt64
@@ -2053,6 +2213,9 @@ func (h *Head) Close() error {
 	if h.wbl != nil {
 		errs = errors.Join(errs, h.wbl.Close())
 	}
+	if h.labelIndex != nil {
+		errs = errors.Join(errs, h.labelIndex.Close())
+	}
 	if errs == nil && h.opts.EnableMemorySnapshotOnShutdown {
 		errs = errors.Join(errs, h.performChunkSnapshot())
 	}
@@ -2090,6 +2253,9 @@ func (h *Head) getOrCreateWithOptionalID(id chunks.HeadSeriesRef, hash uint64, l
 		shardHash = labels.StableHash(lset)
 	}
 	optimisticallyCreatedSeries := newMemSeries(lset, id, shardHash, h.opts.IsolationDisabled, pendingCommit)
+	if overrides := h.oooTimeWindowOverrides.Load(); overrides != nil {
+		optimisticallyCreatedSeries.oooTimeWindow = oooTimeWindowForSeries(*overrides, lset)
+	}
 
 	s, created := h.series.setUnlessAlreadySet(hash, lset, optimisticallyCreatedSeries)
 	if !created {
@@ -2767,9 +2933,23 @@ type memSeries struct {
 
 	mmMaxTime int64 // Max time of any mmapped chunk, only used during WAL replay.
 
+	// Per-series out-of-order time window resolved from the per-matcher
+	// overrides; -1 when no override matches and the global window applies.
+	oooTimeWindow int64
+
+	// lastLabelPartition is the index of the last partition recorded for this
+	// series in the head's time-partitioned label value index, to avoid
+	// touching the shared index on every committed sample. math.MinInt64
+	// means the series has not been recorded yet.
+	lastLabelPartition int64
+
 	nextAt                           int64 // Timestamp at which to cut the next chunk.
 	histogramChunkHasComputedEndTime bool  // True if nextAt has been predicted for the current histograms chunk; false otherwise.
 	pendingCommit                    bool  // Whether there are samples waiting to be committed to this series.
+	// origin is the provenance most recently recorded for this series'
+	// samples. It is a best-effort hint: appends from several origins
+	// overwrite each other, and the value is not replayed from the WAL.
+	origin chunks.SampleOrigin
 	// headChunkCount tracks the number of head chunks. All mutations of the
 	// headChunks/headChunkCount pair go through pushHeadChunk and setHeadChunks.
 	// Chunk counts are bounded by the 3-byte field in HeadChunkRef, so cannot overflow uint32.
@@ -2817,11 +2997,13 @@ type memSeriesOOOFields struct {
 
 func newMemSeries(lset labels.Labels, id chunks.HeadSeriesRef, shardHash uint64, isolationDisabled, pendingCommit bool) *memSeries {
 	s := &memSeries{
-		lset:          lset,
-		ref:           id,
-		nextAt:        math.MinInt64,
-		shardHash:     shardHash,
-		pendingCommit: pendingCommit,
+		lset:               lset,
+		ref:                id,
+		nextAt:             math.MinInt64,
+		lastLabelPartition: math.MinInt64,
+		shardHash:          shardHash,
+		pendingCommit:      pendingCommit,
+		oooTimeWindow:      -1,
 	}
 	if !isolationDisabled {
 		s.txs = newTxRing(0)
@@ -35,8 +35,9 @@ import (
 // initAppender is a helper to initialize the time bounds of the head
 // upon the first sample it receives.
 type initAppender struct {
-	app  storage.Appender
-	head *Head
+	app    storage.Appender
+	head   *Head
+	origin chunks.SampleOrigin
 }
 
 var _ storage.GetRef = &initAppender{}
@@ -53,7 +54,7 @@ func (a *initAppender) Append(ref storage.SeriesRef, lset labels.Labels, t int64
 	}
 
 	a.head.initTime(t)
-	a.app = a.head.appender()
+	a.app = a.head.appenderWithOrigin(a.origin)
 	return a.app.Append(ref, lset, t, v)
 }
 
@@ -69,7 +70,7 @@ func (a *initAppender) AppendExemplar(ref storage.SeriesRef, l labels.Labels, e
 	// We should never reach here given we would call Append before AppendExemplar
 	// and we probably want to always base head/WAL min time on sample times.
 	a.head.initTime(e.Ts)
-	a.app = a.head.appender()
+	a.app = a.head.appenderWithOrigin(a.origin)
 
 	return a.app.AppendExemplar(ref, l, e)
 }
@@ -79,7 +80,7 @@ func (a *initAppender) AppendHistogram(ref storage.SeriesRef, l labels.Labels, t
 		return a.app.AppendHistogram(ref, l, t, h, fh)
 	}
 	a.head.initTime(t)
-	a.app = a.head.appender()
+	a.app = a.head.appenderWithOrigin(a.origin)
 
 	return a.app.AppendHistogram(ref, l, t, h, fh)
 }
@@ -89,7 +90,7 @@ func (a *initAppender) AppendHistogramSTZeroSample(ref storage.SeriesRef, l labe
 		return a.app.AppendHistogramSTZeroSample(ref, l, t, st, h, fh)
 	}
 	a.head.initTime(t)
-	a.app = a.head.appender()
+	a.app = a.head.appenderWithOrigin(a.origin)
 
 	return a.app.AppendHistogramSTZeroSample(ref, l, t, st, h, fh)
 }
@@ -99,7 +100,7 @@ func (a *initAppender) UpdateMetadata(ref storage.SeriesRef, l labels.Labels, m
 		return a.app.UpdateMetadata(ref, l, m)
 	}
 
-	a.app = a.head.appender()
+	a.app = a.head.appenderWithOrigin(a.origin)
 	return a.app.UpdateMetadata(ref, l, m)
 }
 
@@ -109,7 +110,7 @@ func (a *initAppender) AppendSTZeroSample(ref storage.SeriesRef, lset labels.Lab
 	}
 
 	a.head.initTime(t)
-	a.app = a.head.appender()
+	a.app = a.head.appenderWithOrigin(a.origin)
 
 	return a.app.AppendSTZeroSample(ref, lset, t, st)
 }
@@ -163,17 +164,27 @@ func (a *initAppender) Rollback() error {
 }
 
 // Appender returns a new Appender on the database.
-func (h *Head) Appender(context.Context) storage.Appender {
+func (h *Head) Appender(ctx context.Context) storage.Appender {
 	h.metrics.activeAppenders.Inc()
 
+	origin := storage.SampleOriginFromContext(ctx)
 	// The head cache might not have a starting point yet. The init appender
 	// picks up the first appended timestamp as the base.
 	if !h.initialized() {
 		return &initAppender{
-			head: h,
+			head:   h,
+			origin: origin,
 		}
 	}
-	return h.appender()
+	return h.appenderWithOrigin(origin)
+}
+
+// appenderWithOrigin returns a fresh appender that marks committed series
+// with the given sample origin.
+func (h *Head) appenderWithOrigin(origin chunks.SampleOrigin) *headAppender {
+	app := h.appender()
+	app.origin = origin
+	return app
 }
 
 func (h *Head) appender() *headAppender {
@@ -185,12 +196,14 @@ func (h *Head) appender() *headAppender {
 			minValidTime:          minValidTime,
 			headMaxt:              h.MaxTime(),
 			oooTimeWindow:         h.opts.OutOfOrderTimeWindow.Load(),
+			maxOOOTimeWindow:      h.maxOOOTimeWindow(),
 			seriesRefs:            h.getRefSeriesBuffer(),
 			series:                h.getSeriesBuffer(),
 			typesInBatch:          h.getTypeMap(),
 			appendID:              appendID,
 			cleanupAppendIDsBelow: cleanupAppendIDsBelow,
 			storeST:               h.opts.EnableSTStorage.Load(),
+			groupWALSamples:       h.opts.EnableWALSampleGrouping.Load(),
 			useXOR2:               h.opts.UseXOR2FloatEncoding(),
 			useHistogramST:        h.opts.EnableHistogramSTEncoding.Load(),
 		},
@@ -412,6 +425,8 @@ type headAppenderBase struct {
 	headMaxt      int64 // We track it here to not take the lock for every sample appended.
 	oooTimeWindow int64 // Use the same for the entire append, and don't load the atomic for each sample.
 
+	maxOOOTimeWindow int64 // Largest OOO time window across the global setting and per-matcher overrides.
+
 	seriesRefs []record.RefSeries // New series records held by this appender.
 	series     []*memSeries       // New series held by this appender (using corresponding slices indexes from seriesRefs)
 	batches    []*appendBatch     // Holds all the other data to append. (In regular cases, there should be only one of these.)
@@ -419,8 +434,10 @@ type headAppenderBase struct {
 	typesInBatch map[chunks.HeadSeriesRef]sampleType // Which (one) sample type each series holds in the most recent batch.
 
 	appendID, cleanupAppendIDsBelow uint64
+	origin                          chunks.SampleOrigin // Provenance recorded for samples of this append, when known.
 	closed                          bool
 	storeST                         bool // Whether start-timestamp storage is enabled for this append.
+	groupWALSamples                 bool // Whether the grouped SamplesV3 WAL encoding is used for this append.
 	useXOR2                         bool // Whether XOR2 encoding is used for float chunks in this append.
 	useHistogramST                  bool // Whether ST-capable histogram chunk encoding is used in this append.
 }
@@ -436,7 +453,7 @@ func (a *headAppender) SetOptions(opts *storage.AppendOptions) {
 func (a *headAppender) Append(ref storage.SeriesRef, lset labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
 	// Fail fast if OOO is disabled and the sample is out of bounds.
 	// Otherwise a full check will be done later to decide if the sample is in-order or out-of-order.
-	if a.oooTimeWindow == 0 && t < a.minValidTime {
+	if a.maxOOOTimeWindow == 0 && t < a.minValidTime {
 		a.head.metrics.outOfBoundSamples.WithLabelValues(sampleMetricTypeFloat).Inc()
 		return 0, storage.ErrOutOfBounds
 	}
@@ -652,6 +669,10 @@ func (a *headAppenderBase) getCurrentBatch(st sampleType, s chunks.HeadSeriesRef
 // If the sample belongs to the out-of-order chunk, it returns true with no error.
 // If the sample cannot be handled, it returns an error.
 func (s *memSeries) appendable(t int64, v float64, headMaxt, minValidTime, oooTimeWindow int64) (isOOO bool, oooDelta int64, err error) {
+	if s.oooTimeWindow >= 0 {
+		// A per-matcher override applies to this series.
+		oooTimeWindow = s.oooTimeWindow
+	}
 	// Check if we can append in the in-order chunk.
 	if t >= minValidTime {
 		if s.headChunks == nil {
@@ -697,6 +718,10 @@ func (s *memSeries) appendable(t int64, v float64, headMaxt, minValidTime, oooTi
 // The sample belongs to the out of order chunk if we return true and no error.
 // An error signifies the sample cannot be handled.
 func (s *memSeries) appendableHistogram(t int64, h *histogram.Histogram, headMaxt, minValidTime, oooTimeWindow int64) (isOOO bool, oooDelta int64, err error) {
+	if s.oooTimeWindow >= 0 {
+		// A per-matcher override applies to this series.
+		oooTimeWindow = s.oooTimeWindow
+	}
 	// Check if we can append in the in-order chunk.
 	if t >= minValidTime {
 		if s.headChunks == nil {
@@ -739,6 +764,10 @@ func (s *memSeries) appendableHistogram(t int64, h *histogram.Histogram, headMax
 // The sample belongs to the out of order chunk if we return true and no error.
 // An error signifies the sample cannot be handled.
 func (s *memSeries) appendableFloatHistogram(t int64, fh *histogram.FloatHistogram, headMaxt, minValidTime, oooTimeWindow int64) (isOOO bool, oooDelta int64, err error) {
+	if s.oooTimeWindow >= 0 {
+		// A per-matcher override applies to this series.
+		oooTimeWindow = s.oooTimeWindow
+	}
 	// Check if we can append in the in-order chunk.
 	if t >= minValidTime {
 		if s.headChunks == nil {
@@ -818,7 +847,7 @@ func (a *headAppender) AppendExemplar(ref storage.SeriesRef, lset labels.Labels,
 func (a *headAppender) AppendHistogram(ref storage.SeriesRef, lset labels.Labels, t int64, h *histogram.Histogram, fh *histogram.FloatHistogram) (storage.SeriesRef, error) {
 	// Fail fast if OOO is disabled and the sample is out of bounds.
 	// Otherwise a full check will be done later to decide if the sample is in-order or out-of-order.
-	if a.oooTimeWindow == 0 && t < a.minValidTime {
+	if a.maxOOOTimeWindow == 0 && t < a.minValidTime {
 		a.head.metrics.outOfBoundSamples.WithLabelValues(sampleMetricTypeHistogram).Inc()
 		return 0, storage.ErrOutOfBounds
 	}
@@ -1070,7 +1099,7 @@ func (a *headAppenderBase) log() error {
 	defer func() { a.head.putBytesBuffer(buf) }()
 
 	var rec []byte
-	enc := record.Encoder{EnableSTStorage: a.storeST}
+	enc := record.Encoder{EnableSTStorage: a.storeST, EnableSampleGrouping: a.groupWALSamples}
 
 	if len(a.seriesRefs) > 0 {
 		rec = enc.Series(a.seriesRefs, buf)
@@ -1325,6 +1354,9 @@ func (a *headAppenderBase) commitFloats(b *appendBatch, acc *appenderCommitConte
 	for i, s := range b.floats {
 		series = b.floatSeries[i]
 		series.Lock()
+		if a.origin != chunks.OriginUnknown {
+			series.origin = a.origin
+		}
 
 		if value.IsStaleNaN(s.V) {
 			// If a float staleness marker had been appended for a
@@ -1435,6 +1467,7 @@ func (a *headAppenderBase) commitFloats(b *appendBatch, acc *appenderCommitConte
 					acc.oooMaxT = s.T
 				}
 				acc.oooFloatsAccepted++
+				a.head.observeLabelPartition(series, s.T)
 			} else {
 				// Sample is an exact duplicate of the last sample.
 				// NOTE: We can only detect updates if they clash with a sample in the OOOHeadChunk,
@@ -1457,6 +1490,7 @@ func (a *headAppenderBase) commitFloats(b *appendBatch, acc *appenderCommitConte
 				if wasHistogram {
 					a.head.updateNativeHistogramMetricsOnAppend(true, false, oldBuckets, 0)
 				}
+				a.head.observeLabelPartition(series, s.T)
 			} else {
 				// The sample is an exact duplicate, and should be silently dropped.
 				acc.floatsAppended--
@@ -1481,6 +1515,9 @@ func (a *headAppenderBase) commitHistograms(b *appendBatch, acc *appenderCommitC
 	for i, s := range b.histograms {
 		series = b.histogramSeries[i]
 		series.Lock()
+		if a.origin != chunks.OriginUnknown {
+			series.origin = a.origin
+		}
 
 		// At this point, we could encounter a histogram staleness
 		// marker that should better be a float staleness marker or a
@@ -1538,6 +1575,7 @@ func (a *headAppenderBase) commitHistograms(b *appendBatch, acc *appenderCommitC
 					acc.oooMaxT = s.T
 				}
 				acc.oooHistogramAccepted++
+				a.head.observeLabelPartition(series, s.T)
 			} else {
 				// Sample is an exact duplicate of the last sample.
 				// NOTE: We can only detect updates if they clash with a sample in the OOOHeadChunk,
@@ -1559,6 +1597,7 @@ func (a *headAppenderBase) commitHistograms(b *appendBatch, acc *appenderCommitC
 				}
 				a.head.updateStaleSeriesMetricOnAppend(wasStale, isStale)
 				a.head.updateNativeHistogramMetricsOnAppend(wasHistogram, true, oldBuckets, newBuckets)
+				a.head.observeLabelPartition(series, s.T)
 			} else {
 				acc.histogramsAppended--
 				acc.histoOOORejected++
@@ -1583,6 +1622,9 @@ func (a *headAppenderBase) commitFloatHistograms(b *appendBatch, acc *appenderCo
 	for i, s := range b.floatHistograms {
 		series = b.floatHistogramSeries[i]
 		series.Lock()
+		if a.origin != chunks.OriginUnknown {
+			series.origin = a.origin
+		}
 
 		// At this point, we could encounter a float histogram staleness
 		// marker that should better be a float staleness marker or an
@@ -1640,6 +1682,7 @@ func (a *headAppenderBase) commitFloatHistograms(b *appendBatch, acc *appenderCo
 					acc.oooMaxT = s.T
 				}
 				acc.oooHistogramAccepted++
+				a.head.observeLabelPartition(series, s.T)
 			} else {
 				// Sample is an exact duplicate of the last sample.
 				// NOTE: We can only detect updates if they clash with a sample in the OOOHeadChunk,
@@ -1661,6 +1704,7 @@ func (a *headAppenderBase) commitFloatHistograms(b *appendBatch, acc *appenderCo
 				}
 				a.head.updateStaleSeriesMetricOnAppend(wasStale, isStale)
 				a.head.updateNativeHistogramMetricsOnAppend(wasHistogram, true, oldBuckets, newBuckets)
+				a.head.observeLabelPartition(series, s.T)
 			} else {
 				acc.histogramsAppended--
 				acc.histoOOORejected++
@@ -1742,7 +1786,8 @@ func (a *headAppenderBase) Commit() (err error) {
 			storeST:         a.storeST,
 		},
 		oooEnc: record.Encoder{
-			EnableSTStorage: a.storeST,
+			EnableSTStorage:      a.storeST,
+			EnableSampleGrouping: a.groupWALSamples,
 		},
 	}
 
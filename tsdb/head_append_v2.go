@@ -30,8 +30,9 @@ import (
 // initAppenderV2 is a helper to initialize the time bounds of the head
 // upon the first sample it receives.
 type initAppenderV2 struct {
-	app  storage.AppenderV2
-	head *Head
+	app    storage.AppenderV2
+	head   *Head
+	origin chunks.SampleOrigin
 }
 
 var _ storage.GetRef = &initAppenderV2{}
@@ -40,6 +41,7 @@ func (a *initAppenderV2) Append(ref storage.SeriesRef, ls labels.Labels, st, t i
 	if a.app == nil {
 		a.head.initTime(t)
 		a.app = a.head.appenderV2()
+		a.app.(*headAppenderV2).origin = a.origin
 	}
 	return a.app.Append(ref, ls, st, t, v, h, fh, opts)
 }
@@ -68,17 +70,21 @@ func (a *initAppenderV2) Rollback() error {
 }
 
 // AppenderV2 returns a new AppenderV2 on the database.
-func (h *Head) AppenderV2(context.Context) storage.AppenderV2 {
+func (h *Head) AppenderV2(ctx context.Context) storage.AppenderV2 {
 	h.metrics.activeAppenders.Inc()
 
+	origin := storage.SampleOriginFromContext(ctx)
 	// The head cache might not have a starting point yet. The init appender
 	// picks up the first appended timestamp as the base.
 	if !h.initialized() {
 		return &initAppenderV2{
-			head: h,
+			head:   h,
+			origin: origin,
 		}
 	}
-	return h.appenderV2()
+	app := h.appenderV2()
+	app.origin = origin
+	return app
 }
 
 func (h *Head) appenderV2() *headAppenderV2 {
@@ -2614,10 +2614,12 @@ func TestHeadAppenderV2_Append_CounterResetHeader(t *testing.T) {
 			}
 			checkExpCounterResetHeader(chunkenc.NotCounterReset, chunkenc.NotCounterReset)
 
-			// Changing schema will cut a new chunk with unknown counter reset.
+			// Increasing the schema re-encodes the histogram to the chunk's
+			// schema. The merged buckets no longer cover the chunk's layout,
+			// so a counter reset is detected and a new chunk is cut.
 			h.Schema++
 			appendHistogram(h)
-			checkExpCounterResetHeader(chunkenc.UnknownCounterReset)
+			checkExpCounterResetHeader(chunkenc.CounterReset)
 
 			// Changing schema will zero threshold a new chunk with unknown counter reset.
 			h.ZeroThreshold += 0.01
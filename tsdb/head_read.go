@@ -90,6 +90,13 @@ func (h *headIndexReader) LabelValues(ctx context.Context, name string, hints *s
 	}
 
 	if len(matchers) == 0 {
+		if h.head.labelIndex != nil {
+			limit := 0
+			if hints != nil {
+				limit = hints.Limit
+			}
+			return h.head.labelIndex.labelValues(h.head.postings, name, limit)
+		}
 		return h.head.postings.LabelValues(ctx, name, hints), nil
 	}
 
@@ -104,6 +111,9 @@ func (h *headIndexReader) LabelNames(ctx context.Context, matchers ...*labels.Ma
 	}
 
 	if len(matchers) == 0 {
+		if h.head.labelIndex != nil {
+			return h.head.labelIndex.labelNames(h.head.postings, 0)
+		}
 		labelNames := h.head.postings.LabelNames()
 		slices.Sort(labelNames)
 		return labelNames, nil
@@ -212,6 +222,13 @@ func (h *headIndexReader) Series(ref storage.SeriesRef, builder *labels.ScratchB
 		h.headChunksBuf = nil
 	}
 
+	// The head only tracks one origin per series, so stamp it on all chunks.
+	if s.origin != chunks.OriginUnknown {
+		for i := range *chks {
+			(*chks)[i].Origin = s.origin
+		}
+	}
+
 	return nil
 }
 
@@ -921,6 +921,62 @@ func TestHead_ReadWAL(t *testing.T) {
 	}
 }
 
+func TestHead_ReadWAL_Ordering(t *testing.T) {
+	// Replay many small interleaved records to exercise the concurrent decode
+	// pipeline. Each sample and exemplar record references a series record
+	// written just before it, and samples per series are strictly increasing,
+	// so any reordering during replay would drop data as unknown refs or
+	// out-of-order samples.
+	const (
+		numSeries        = 100
+		samplesPerSeries = 5
+	)
+
+	var entries []any
+	for i := 1; i <= numSeries; i++ {
+		ref := chunks.HeadSeriesRef(i)
+		entries = append(entries,
+			[]record.RefSeries{{Ref: ref, Labels: labels.FromStrings("foo", fmt.Sprintf("bar%03d", i))}},
+			[]record.RefSample{{Ref: ref, T: 0, V: float64(i)}},
+			[]record.RefExemplar{{Ref: ref, T: 0, V: float64(i), Labels: labels.FromStrings("trace_id", strconv.Itoa(i))}},
+		)
+	}
+	for ts := int64(1); ts < samplesPerSeries; ts++ {
+		for i := 1; i <= numSeries; i++ {
+			entries = append(entries, []record.RefSample{{Ref: chunks.HeadSeriesRef(i), T: ts, V: float64(i) + float64(ts)}})
+		}
+	}
+
+	head, w := newTestHead(t, 1000, compression.None, false)
+	populateTestWL(t, w, entries, nil, false)
+
+	require.NoError(t, head.Init(math.MinInt64))
+	require.Equal(t, uint64(numSeries), head.lastSeriesID.Load())
+
+	matcher := labels.MustNewMatcher(labels.MatchRegexp, "foo", "bar.*")
+
+	q, err := NewBlockQuerier(head, math.MinInt64, math.MaxInt64)
+	require.NoError(t, err)
+	series := query(t, q, matcher)
+	require.Len(t, series, numSeries)
+	for i := 1; i <= numSeries; i++ {
+		expSamples := make([]chunks.Sample, 0, samplesPerSeries)
+		for ts := int64(0); ts < samplesPerSeries; ts++ {
+			expSamples = append(expSamples, sample{t: ts, f: float64(i) + float64(ts)})
+		}
+		require.Equal(t, expSamples, series[labels.FromStrings("foo", fmt.Sprintf("bar%03d", i)).String()])
+	}
+
+	eq, err := head.ExemplarQuerier(context.Background())
+	require.NoError(t, err)
+	res, err := eq.Select(0, 1000, []*labels.Matcher{matcher})
+	require.NoError(t, err)
+	require.Len(t, res, numSeries)
+	for _, r := range res {
+		require.Len(t, r.Exemplars, 1)
+	}
+}
+
 func TestHead_WALMultiRef(t *testing.T) {
 	head, w := newTestHead(t, 1000, compression.None, false)
 
@@ -5507,10 +5563,12 @@ func TestHistogramCounterResetHeader(t *testing.T) {
 			}
 			checkExpCounterResetHeader(chunkenc.NotCounterReset, chunkenc.NotCounterReset)
 
-			// Changing schema will cut a new chunk with unknown counter reset.
+			// Increasing the schema re-encodes the histogram to the chunk's
+			// schema. The merged buckets no longer cover the chunk's layout,
+			// so a counter reset is detected and a new chunk is cut.
 			h.Schema++
 			appendHistogram(h)
-			checkExpCounterResetHeader(chunkenc.UnknownCounterReset)
+			checkExpCounterResetHeader(chunkenc.CounterReset)
 
 			// Changing schema will zero threshold a new chunk with unknown counter reset.
 			h.ZeroThreshold += 0.01
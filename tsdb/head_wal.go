@@ -78,6 +78,118 @@ func counterAddNonZero(v *prometheus.CounterVec, value float64, lvs ...string) {
 	}
 }
 
+// walDecodeJob carries one raw WAL record through the concurrent decode
+// pipeline. The read loop queues every job on both the decode worker channel
+// and the in-order dispatch channel; once done is closed, result holds the
+// decoded record (or nil for unknown record types) and err a corruption error.
+type walDecodeJob struct {
+	rec     []byte
+	segment int
+	offset  int64
+	done    chan struct{}
+	result  any
+	err     error
+}
+
+// decodeWALRecord decodes the raw record held by job into a buffer taken from
+// the matching replay pool, setting job.result on success and job.err on
+// corruption. The raw record buffer is recycled in either case.
+func (h *Head) decodeWALRecord(dec *record.Decoder, job *walDecodeJob) {
+	defer func() {
+		h.wlReplayRecordsPool.Put(job.rec[:0])
+		job.rec = nil
+	}()
+	var err error
+	switch dec.Type(job.rec) {
+	case record.Series:
+		series := h.wlReplaySeriesPool.Get()[:0]
+		series, err = dec.Series(job.rec, series)
+		if err != nil {
+			job.err = &wlog.CorruptionErr{
+				Err:     fmt.Errorf("decode series: %w", err),
+				Segment: job.segment,
+				Offset:  job.offset,
+			}
+			return
+		}
+		job.result = series
+	case record.Samples, record.SamplesV2, record.SamplesV3:
+		samples := h.wlReplaySamplesPool.Get()[:0]
+		samples, err = dec.Samples(job.rec, samples)
+		if err != nil {
+			job.err = &wlog.CorruptionErr{
+				Err:     fmt.Errorf("decode samples: %w", err),
+				Segment: job.segment,
+				Offset:  job.offset,
+			}
+			return
+		}
+		job.result = samples
+	case record.Tombstones:
+		tstones := h.wlReplaytStonesPool.Get()[:0]
+		tstones, err = dec.Tombstones(job.rec, tstones)
+		if err != nil {
+			job.err = &wlog.CorruptionErr{
+				Err:     fmt.Errorf("decode tombstones: %w", err),
+				Segment: job.segment,
+				Offset:  job.offset,
+			}
+			return
+		}
+		job.result = tstones
+	case record.Exemplars:
+		exemplars := h.wlReplayExemplarsPool.Get()[:0]
+		exemplars, err = dec.Exemplars(job.rec, exemplars)
+		if err != nil {
+			job.err = &wlog.CorruptionErr{
+				Err:     fmt.Errorf("decode exemplars: %w", err),
+				Segment: job.segment,
+				Offset:  job.offset,
+			}
+			return
+		}
+		job.result = exemplars
+	case record.HistogramSamples, record.CustomBucketsHistogramSamples, record.HistogramSamplesV2:
+		hists := h.wlReplayHistogramsPool.Get()[:0]
+		hists, err = dec.HistogramSamples(job.rec, hists)
+		if err != nil {
+			job.err = &wlog.CorruptionErr{
+				Err:     fmt.Errorf("decode histograms: %w", err),
+				Segment: job.segment,
+				Offset:  job.offset,
+			}
+			return
+		}
+		job.result = hists
+	case record.FloatHistogramSamples, record.CustomBucketsFloatHistogramSamples, record.FloatHistogramSamplesV2:
+		hists := h.wlReplayFloatHistogramsPool.Get()[:0]
+		hists, err = dec.FloatHistogramSamples(job.rec, hists)
+		if err != nil {
+			job.err = &wlog.CorruptionErr{
+				Err:     fmt.Errorf("decode float histograms: %w", err),
+				Segment: job.segment,
+				Offset:  job.offset,
+			}
+			return
+		}
+		job.result = hists
+	case record.Metadata:
+		meta := h.wlReplayMetadataPool.Get()[:0]
+		meta, err = dec.Metadata(job.rec, meta)
+		if err != nil {
+			job.err = &wlog.CorruptionErr{
+				Err:     fmt.Errorf("decode metadata: %w", err),
+				Segment: job.segment,
+				Offset:  job.offset,
+			}
+			return
+		}
+		job.result = meta
+	default:
+		// Noop.
+	}
+}
+
 func (h *Head) loadWAL(r *wlog.Reader, syms *labels.SymbolTable, multiRef map[chunks.HeadSeriesRef]chunks.HeadSeriesRef, mmappedChunks, oooMmappedChunks map[chunks.HeadSeriesRef][]*mmappedChunk) (err error) {
 	// Track number of missing series records that were referenced by other records.
 	unknownSeriesRefs := &seriesRefSet{refs: make(map[chunks.HeadSeriesRef]struct{}), mtx: sync.Mutex{}}
@@ -101,7 +213,13 @@ func (h *Head) loadWAL(r *wlog.Reader, syms *labels.SymbolTable, multiRef map[ch
 		shards          = make([][]record.RefSample, concurrency)
 		histogramShards = make([][]histogramRecord, concurrency)
 
-		decoded                      = make(chan any, 10)
+		// Raw records flow to the decode workers through decodeJobs, while
+		// decoded keeps the same jobs in WAL order for the dispatch loop.
+		decodeJobs = make(chan *walDecodeJob, 4*concurrency)
+		decoded    = make(chan *walDecodeJob, 4*concurrency)
+		// stopReading tells the read loop to stop handing out new decode
+		// jobs after the dispatch loop hit an error.
+		stopReading                  atomic.Bool
 		decodeErr, seriesCreationErr error
 	)
 
@@ -154,107 +272,50 @@ func (h *Head) loadWAL(r *wlog.Reader, syms *labels.SymbolTable, multiRef map[ch
 		unknownSeriesRefs.merge(missingSeries)
 	}(exemplarsInput)
 
+	// Start the decode workers. Each job is handed to exactly one worker,
+	// while the dispatch loop below consumes the jobs in WAL order.
+	var decodeWg sync.WaitGroup
+	decodeWg.Add(concurrency)
+	for range concurrency {
+		go func() {
+			defer decodeWg.Done()
+			dec := record.NewDecoder(syms, h.logger)
+			for job := range decodeJobs {
+				h.decodeWALRecord(&dec, job)
+				close(job.done)
+			}
+		}()
+	}
+
+	// Read raw records sequentially and fan them out to the decode workers.
 	go func() {
+		defer close(decodeJobs)
 		defer close(decoded)
-		var err error
-		dec := record.NewDecoder(syms, h.logger)
-		for r.Next() {
-			switch dec.Type(r.Record()) {
-			case record.Series:
-				series := h.wlReplaySeriesPool.Get()[:0]
-				series, err = dec.Series(r.Record(), series)
-				if err != nil {
-					decodeErr = &wlog.CorruptionErr{
-						Err:     fmt.Errorf("decode series: %w", err),
-						Segment: r.Segment(),
-						Offset:  r.Offset(),
-					}
-					return
-				}
-				decoded <- series
-			case record.Samples, record.SamplesV2:
-				samples := h.wlReplaySamplesPool.Get()[:0]
-				samples, err = dec.Samples(r.Record(), samples)
-				if err != nil {
-					decodeErr = &wlog.CorruptionErr{
-						Err:     fmt.Errorf("decode samples: %w", err),
-						Segment: r.Segment(),
-						Offset:  r.Offset(),
-					}
-					return
-				}
-				decoded <- samples
-			case record.Tombstones:
-				tstones := h.wlReplaytStonesPool.Get()[:0]
-				tstones, err = dec.Tombstones(r.Record(), tstones)
-				if err != nil {
-					decodeErr = &wlog.CorruptionErr{
-						Err:     fmt.Errorf("decode tombstones: %w", err),
-						Segment: r.Segment(),
-						Offset:  r.Offset(),
-					}
-					return
-				}
-				decoded <- tstones
-			case record.Exemplars:
-				exemplars := h.wlReplayExemplarsPool.Get()[:0]
-				exemplars, err = dec.Exemplars(r.Record(), exemplars)
-				if err != nil {
-					decodeErr = &wlog.CorruptionErr{
-						Err:     fmt.Errorf("decode exemplars: %w", err),
-						Segment: r.Segment(),
-						Offset:  r.Offset(),
-					}
-					return
-				}
-				decoded <- exemplars
-			case record.HistogramSamples, record.CustomBucketsHistogramSamples, record.HistogramSamplesV2:
-				hists := h.wlReplayHistogramsPool.Get()[:0]
-				hists, err = dec.HistogramSamples(r.Record(), hists)
-				if err != nil {
-					decodeErr = &wlog.CorruptionErr{
-						Err:     fmt.Errorf("decode histograms: %w", err),
-						Segment: r.Segment(),
-						Offset:  r.Offset(),
-					}
-					return
-				}
-				decoded <- hists
-			case record.FloatHistogramSamples, record.CustomBucketsFloatHistogramSamples, record.FloatHistogramSamplesV2:
-				hists := h.wlReplayFloatHistogramsPool.Get()[:0]
-				hists, err = dec.FloatHistogramSamples(r.Record(), hists)
-				if err != nil {
-					decodeErr = &wlog.CorruptionErr{
-						Err:     fmt.Errorf("decode float histograms: %w", err),
-						Segment: r.Segment(),
-						Offset:  r.Offset(),
-					}
-					return
-				}
-				decoded <- hists
-			case record.Metadata:
-				meta := h.wlReplayMetadataPool.Get()[:0]
-				meta, err := dec.Metadata(r.Record(), meta)
-				if err != nil {
-					decodeErr = &wlog.CorruptionErr{
-						Err:     fmt.Errorf("decode metadata: %w", err),
-						Segment: r.Segment(),
-						Offset:  r.Offset(),
-					}
-					return
-				}
-				decoded <- meta
-			default:
-				// Noop.
+		for !stopReading.Load() && r.Next() {
+			rec := r.Record()
+			job := &walDecodeJob{
+				rec:     append(h.wlReplayRecordsPool.Get()[:0], rec...),
+				segment: r.Segment(),
+				offset:  r.Offset(),
+				done:    make(chan struct{}),
 			}
+			decoded <- job
+			decodeJobs <- job
 		}
 	}()
 
-	// The records are always replayed from the oldest to the newest.
+	// The records are always replayed from the oldest to the newest: the
+	// decode stage keeps the jobs in WAL order, so waiting on each job in
+	// turn yields the records exactly as written.
 	missingSeries := make(map[chunks.HeadSeriesRef]struct{})
 Outer:
-	for d := range decoded {
-		switch v := d.(type) {
+	for job := range decoded {
+		<-job.done
+		if job.err != nil {
+			decodeErr = job.err
+			break Outer
+		}
+		switch v := job.result.(type) {
 		case []record.RefSeries:
 			for _, walSeries := range v {
 				mSeries, created, err := h.getOrCreateWithOptionalID(walSeries.Ref, walSeries.Labels.Hash(), walSeries.Labels, false)
@@ -473,21 +534,27 @@ Outer:
 			}
 			clear(v) // Zero out to avoid retaining metadata strings.
 			h.wlReplayMetadataPool.Put(v[:0])
+		case nil:
+			// Record type unknown to the decoder, skip.
 		default:
-			panic(fmt.Errorf("unexpected decoded type: %T", d))
+			panic(fmt.Errorf("unexpected decoded type: %T", job.result))
 		}
 	}
 	unknownSeriesRefs.merge(missingSeries)
 
-	if decodeErr != nil {
-		return decodeErr
-	}
-	if seriesCreationErr != nil {
-		// Drain the channel to unblock the goroutine.
+	if decodeErr != nil || seriesCreationErr != nil {
+		// Stop the read loop and drain the pipeline to unblock it and the
+		// decode workers.
+		stopReading.Store(true)
 		for range decoded {
 		}
+		decodeWg.Wait()
+		if decodeErr != nil {
+			return decodeErr
+		}
 		return seriesCreationErr
 	}
+	decodeWg.Wait()
 
 	// Signal termination to each worker and wait for it to close its output channel.
 	for i := range concurrency {
@@ -922,7 +989,7 @@ func (h *Head) loadWBL(r *wlog.Reader, syms *labels.SymbolTable, multiRef map[ch
 			var err error
 			rec := r.Record()
 			switch dec.Type(rec) {
-			case record.Samples, record.SamplesV2:
+			case record.Samples, record.SamplesV2, record.SamplesV3:
 				samples := h.wlReplaySamplesPool.Get()[:0]
 				samples, err = dec.Samples(rec, samples)
 				if err != nil {
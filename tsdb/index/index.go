@@ -48,6 +48,22 @@ const (
 	FormatV2 = 2
 	// FormatV3 represents version 3 of index.
 	FormatV3 = 3
+	// FormatV4 represents version 4 of index. It prefixes every postings list
+	// with an encoding byte, allowing high-cardinality lists to be stored as
+	// roaring bitmaps. See EncodePostingsV4.
+	FormatV4 = 4
+	// FormatV5 represents version 5 of index. On top of FormatV4 it appends
+	// per-chunk summary statistics (min, max, sum, count of the float samples)
+	// to every series entry, so aggregations over full chunks can be answered
+	// without decoding them.
+	FormatV5 = 5
+
+	// MaxSupportedReadVersion is the highest index format version this binary
+	// can read.
+	MaxSupportedReadVersion = FormatV5
+	// MaxSupportedWriteVersion is the highest index format version this binary
+	// can write.
+	MaxSupportedWriteVersion = FormatV5
 
 	indexFilename = "index"
 
@@ -206,6 +222,25 @@ func NewTOCFromByteSlice(bs ByteSlice) (*TOC, error) {
 // serializes data in format version 2. It uses the given encoder to encode each
 // postings list.
 func NewWriterWithEncoder(ctx context.Context, fn string, encoder PostingsEncoder) (*Writer, error) {
+	return newWriterWithVersion(ctx, fn, encoder, FormatV2)
+}
+
+// NewWriterV4 returns a new Writer to the given filename. It serializes data
+// in format version 4, storing high-cardinality postings lists as roaring
+// bitmaps. See EncodePostingsV4.
+func NewWriterV4(ctx context.Context, fn string) (*Writer, error) {
+	return newWriterWithVersion(ctx, fn, EncodePostingsV4, FormatV4)
+}
+
+// NewWriterV5 returns a new Writer to the given filename. It serializes data
+// in format version 5, which extends version 4 with per-chunk summary
+// statistics in the series entries. The statistics are taken from the chunk
+// metas, or computed from the chunk data for metas that carry none.
+func NewWriterV5(ctx context.Context, fn string) (*Writer, error) {
+	return newWriterWithVersion(ctx, fn, EncodePostingsV4, FormatV5)
+}
+
+func newWriterWithVersion(ctx context.Context, fn string, encoder PostingsEncoder, version int) (*Writer, error) {
 	dir := filepath.Dir(fn)
 
 	df, err := fileutil.OpenDir(dir)
@@ -251,6 +286,7 @@ func NewWriterWithEncoder(ctx context.Context, fn string, encoder PostingsEncode
 		symbolCache:     make(map[string]uint32, 1<<16),
 		labelNames:      make(map[string]uint64, 1<<8),
 		crc32:           newCRC32(),
+		Version:         version,
 		postingsEncoder: encoder,
 	}
 	if err := iw.writeMeta(); err != nil {
@@ -425,11 +461,20 @@ func (w *Writer) ensureStage(s indexWriterStage) error {
 func (w *Writer) writeMeta() error {
 	w.buf1.Reset()
 	w.buf1.PutBE32(MagicIndex)
-	w.buf1.PutByte(FormatV2)
+	w.buf1.PutByte(byte(w.Version))
 
 	return w.write(w.buf1.Get())
 }
 
+// chunkStats returns the summary statistics of c, computing them from the
+// chunk data when the meta carries none.
+func chunkStats(c chunks.Meta) chunks.ChunkStats {
+	if c.Stats.Count > 0 || c.Chunk == nil {
+		return c.Stats
+	}
+	return chunks.CalcChunkStats(c.Chunk)
+}
+
 // AddSeries adds the series one at a time along with its chunks.
 func (w *Writer) AddSeries(ref storage.SeriesRef, lset labels.Labels, chunks ...chunks.Meta) error {
 	if err := w.ensureStage(idxStageSeries); err != nil {
@@ -511,6 +556,26 @@ func (w *Writer) AddSeries(ref storage.SeriesRef, lset labels.Labels, chunks ...
 		}
 	}
 
+	if w.Version >= FormatV5 {
+		for _, c := range chunks {
+			stats := chunkStats(c)
+			// Bit 0 flags the presence of statistics, bits 1-2 carry
+			// the sample origin of the chunk.
+			flags := byte(c.Origin) << 1
+			if stats.Count > 0 {
+				flags |= 1
+			}
+			w.buf2.PutByte(flags)
+			if stats.Count == 0 {
+				continue
+			}
+			w.buf2.PutBEFloat64(stats.Min)
+			w.buf2.PutBEFloat64(stats.Max)
+			w.buf2.PutBEFloat64(stats.Sum)
+			w.buf2.PutUvarint64(stats.Count)
+		}
+	}
+
 	w.buf1.Reset()
 	w.buf1.PutUvarint(w.buf2.Len())
 
@@ -1037,6 +1102,10 @@ func newReader(b ByteSlice, c io.Closer, postingsDecoder PostingsDecoder) (*Read
 
 	switch r.version {
 	case FormatV1, FormatV2, FormatV3:
+	case FormatV4, FormatV5:
+		// These formats define their own postings encoding, so the configured
+		// decoder does not apply.
+		postingsDecoder = DecodePostingsV4
 	default:
 		return nil, fmt.Errorf("unknown index file version %d", r.version)
 	}
@@ -1117,7 +1186,7 @@ func newReader(b ByteSlice, c io.Closer, postingsDecoder PostingsDecoder) (*Read
 		r.nameSymbols[off] = k
 	}
 
-	r.dec = &Decoder{LookupSymbol: r.lookupSymbol, DecodePostings: postingsDecoder}
+	r.dec = &Decoder{LookupSymbol: r.lookupSymbol, DecodePostings: postingsDecoder, Version: r.version}
 
 	return r, nil
 }
@@ -1127,6 +1196,12 @@ func (r *Reader) Version() int {
 	return r.version
 }
 
+// HasChunkStats returns whether the index stores per-chunk summary statistics
+// in its series entries.
+func (r *Reader) HasChunkStats() bool {
+	return r.version >= FormatV5
+}
+
 // Range marks a byte range.
 type Range struct {
 	Start, End int64
@@ -1759,6 +1834,9 @@ func (stringListIter) Err() error   { return nil }
 type Decoder struct {
 	LookupSymbol   func(context.Context, uint32) (string, error)
 	DecodePostings PostingsDecoder
+	// Version is the index format version the decoded entries were written
+	// with. Series entries of FormatV5 and later carry per-chunk statistics.
+	Version int
 }
 
 // DecodePostingsRaw returns a postings list for d and its number of elements.
@@ -1866,6 +1944,25 @@ func (dec *Decoder) Series(b []byte, builder *labels.ScratchBuilder, chks *[]chu
 			MaxTime: maxt,
 		})
 	}
+
+	if dec.Version >= FormatV5 {
+		for i := range *chks {
+			flags := d.Byte()
+			(*chks)[i].Origin = chunks.SampleOrigin(flags >> 1)
+			if flags&1 == 0 {
+				continue
+			}
+			(*chks)[i].Stats = chunks.ChunkStats{
+				Min:   d.Be64Float64(),
+				Max:   d.Be64Float64(),
+				Sum:   d.Be64Float64(),
+				Count: d.Uvarint64(),
+			}
+			if d.Err() != nil {
+				return fmt.Errorf("read stats for chunk %d: %w", i, d.Err())
+			}
+		}
+	}
 	return d.Err()
 }
 
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"hash/crc32"
+	"math"
 	"os"
 	"path/filepath"
 	"slices"
@@ -29,6 +30,7 @@ import (
 	"go.uber.org/goleak"
 
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb/chunkenc"
 	"github.com/prometheus/prometheus/tsdb/chunks"
@@ -413,6 +415,68 @@ func TestPersistence_index_e2e(t *testing.T) {
 	require.Equal(t, expSymbols, gotSymbols)
 }
 
+func TestIndexRW_ChunkStatsV5(t *testing.T) {
+	ctx := context.Background()
+	fn := filepath.Join(t.TempDir(), indexFilename)
+
+	iw, err := NewWriterV5(ctx, fn)
+	require.NoError(t, err)
+
+	floatChunk := func(samples ...float64) chunkenc.Chunk {
+		c := chunkenc.NewXORChunk()
+		app, err := c.Appender()
+		require.NoError(t, err)
+		for i, v := range samples {
+			app.Append(0, int64(i), v)
+		}
+		return c
+	}
+
+	metas := []chunks.Meta{
+		{Ref: 1, MinTime: 0, MaxTime: 2, Chunk: floatChunk(4, -2, math.Float64frombits(value.StaleNaN)), Origin: chunks.OriginPrimary},
+		{Ref: 2, MinTime: 10, MaxTime: 30, Chunk: floatChunk(1, 2, 3), Origin: chunks.OriginReplicated},
+		// Histogram chunks get no summary statistics, but still carry an origin.
+		{Ref: 3, MinTime: 40, MaxTime: 40, Chunk: chunkenc.NewHistogramChunk()},
+	}
+
+	lbls := labels.FromStrings("foo", "bar")
+	require.NoError(t, iw.AddSymbol("bar"))
+	require.NoError(t, iw.AddSymbol("foo"))
+	require.NoError(t, iw.AddSeries(1, lbls, metas...))
+	require.NoError(t, iw.Close())
+
+	ir, err := NewFileReader(fn, DecodePostingsRaw)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, ir.Close())
+	})
+	require.Equal(t, FormatV5, ir.Version())
+	require.True(t, ir.HasChunkStats())
+
+	p, err := ir.Postings(ctx, "foo", "bar")
+	require.NoError(t, err)
+	require.True(t, p.Next())
+
+	var builder labels.ScratchBuilder
+	var chks []chunks.Meta
+	require.NoError(t, ir.Series(p.At(), &builder, &chks))
+	testutil.RequireEqual(t, lbls, builder.Labels())
+	require.Len(t, chks, len(metas))
+
+	// Stale markers do not contribute to the statistics.
+	require.Equal(t, chunks.ChunkStats{Min: -2, Max: 4, Sum: 2, Count: 2}, chks[0].Stats)
+	require.Equal(t, chunks.ChunkStats{Min: 1, Max: 3, Sum: 6, Count: 3}, chks[1].Stats)
+	require.Equal(t, chunks.ChunkStats{}, chks[2].Stats)
+
+	// The sample origin round-trips alongside the statistics.
+	require.Equal(t, chunks.OriginPrimary, chks[0].Origin)
+	require.Equal(t, chunks.OriginReplicated, chks[1].Origin)
+	require.Equal(t, chunks.OriginUnknown, chks[2].Origin)
+
+	require.False(t, p.Next())
+	require.NoError(t, p.Err())
+}
+
 func TestWriter_ShouldReturnErrorOnSeriesWithDuplicatedLabelNames(t *testing.T) {
 	w, err := NewWriter(context.Background(), filepath.Join(t.TempDir(), "index"))
 	require.NoError(t, err)
@@ -25,6 +25,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bboreham/go-loser"
@@ -75,6 +76,11 @@ type MemPostings struct {
 	// Since it's append-only, it is safe to read the label values slice after releasing the lock.
 	lvs map[string][]string
 
+	// version is incremented whenever a label name or value is added to or
+	// removed from the index. It lets callers cache structures derived from
+	// the label set and cheaply detect when they became stale.
+	version atomic.Uint64
+
 	ordered bool
 }
 
@@ -365,6 +371,16 @@ func (p *MemPostings) Delete(deleted map[storage.SeriesRef]struct{}, affected ma
 		}
 		p.lvs[name] = lvs
 	}
+	if len(affectedLabelNames) > 0 {
+		p.version.Add(1)
+	}
+}
+
+// Version returns a counter that changes whenever a label name or value is
+// added to or removed from the index. Callers can use it to detect when
+// structures derived from the label set have become stale.
+func (p *MemPostings) Version() uint64 {
+	return p.version.Load()
 }
 
 // unlockWaitAndLockAgain will unlock an already locked p.mtx.Lock() and then wait a little bit before locking it again,
@@ -429,6 +445,7 @@ func (p *MemPostings) addFor(id storage.SeriesRef, l labels.Label) {
 	vm, ok := nm[l.Value]
 	if !ok {
 		p.lvs[l.Name] = appendWithExponentialGrowth(p.lvs[l.Name], l.Value)
+		p.version.Add(1)
 	}
 	list := appendWithExponentialGrowth(vm, id)
 	nm[l.Value] = list
@@ -0,0 +1,137 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/encoding"
+	"github.com/prometheus/prometheus/util/testutil"
+)
+
+func TestRoaringPostings(t *testing.T) {
+	sparse := make([]uint32, 0, 2*roaringMinSeries)
+	for i := uint32(0); i < 2*roaringMinSeries; i++ {
+		// Spread values over many containers with few entries each.
+		sparse = append(sparse, i*777)
+	}
+	dense := make([]uint32, 0, 2*roaringArrayMaxSize)
+	for i := uint32(0); i < 2*roaringArrayMaxSize; i++ {
+		// More than roaringArrayMaxSize values in one container forces the
+		// bitmap representation.
+		dense = append(dense, 1<<16+i*3)
+	}
+	short := []uint32{1, 5, 63, 64, 0xffff, 0x10000, 1 << 20}
+
+	for name, offs := range map[string][]uint32{
+		"short list keeps raw encoding": short,
+		"sparse array containers":       sparse,
+		"dense bitmap container":        dense,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var e encoding.Encbuf
+			require.NoError(t, EncodePostingsV4(&e, offs))
+
+			exp := make([]storage.SeriesRef, 0, len(offs))
+			for _, o := range offs {
+				exp = append(exp, storage.SeriesRef(o))
+			}
+
+			n, p, err := DecodePostingsV4(encoding.Decbuf{B: e.Get()})
+			require.NoError(t, err)
+			require.Equal(t, len(offs), n)
+			res, err := ExpandPostings(p)
+			require.NoError(t, err)
+			require.Equal(t, exp, res)
+
+			// Seek must behave like on a plain list, including seeking
+			// backwards, to absent values and past the end.
+			for _, seek := range []storage.SeriesRef{0, exp[0], exp[0] + 1, exp[len(exp)/2], exp[len(exp)-1], exp[len(exp)-1] + 1} {
+				n, p, err := DecodePostingsV4(encoding.Decbuf{B: e.Get()})
+				require.NoError(t, err)
+				require.Equal(t, len(offs), n)
+				lp := NewListPostings(exp)
+				require.Equal(t, lp.Seek(seek), p.Seek(seek), "seek to %d", seek)
+				expRest, err := ExpandPostings(lp)
+				require.NoError(t, err)
+				rest, err := ExpandPostings(p)
+				require.NoError(t, err)
+				require.Equal(t, expRest, rest, "seek to %d", seek)
+			}
+		})
+	}
+}
+
+func TestIndexRW_PostingsV4(t *testing.T) {
+	ctx := context.Background()
+	fn := filepath.Join(t.TempDir(), indexFilename)
+
+	iw, err := NewWriterV4(ctx, fn)
+	require.NoError(t, err)
+
+	// Enough series under one label pair to trigger the roaring encoding.
+	var input indexWriterSeriesSlice
+	for i := range 2 * roaringMinSeries {
+		input = append(input, &indexWriterSeries{
+			labels: labels.FromStrings("foo", "bar", "i", strconv.Itoa(i)),
+		})
+	}
+	slices.SortFunc(input, func(a, b *indexWriterSeries) int { return labels.Compare(a.labels, b.labels) })
+
+	symbols := map[string]struct{}{}
+	for _, s := range input {
+		s.labels.Range(func(l labels.Label) {
+			symbols[l.Name] = struct{}{}
+			symbols[l.Value] = struct{}{}
+		})
+	}
+	syms := make([]string, 0, len(symbols))
+	for s := range symbols {
+		syms = append(syms, s)
+	}
+	slices.Sort(syms)
+	for _, s := range syms {
+		require.NoError(t, iw.AddSymbol(s))
+	}
+	for i, s := range input {
+		require.NoError(t, iw.AddSeries(storage.SeriesRef(i), s.labels))
+	}
+	require.NoError(t, iw.Close())
+
+	// The reader must pick the V4 postings decoding regardless of the
+	// decoder it is given.
+	ir, err := NewFileReader(fn, DecodePostingsRaw)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, ir.Close())
+	})
+	require.Equal(t, FormatV4, ir.Version())
+
+	p, err := ir.Postings(ctx, "foo", "bar")
+	require.NoError(t, err)
+	var builder labels.ScratchBuilder
+	for i := 0; p.Next(); i++ {
+		require.NoError(t, ir.Series(p.At(), &builder, nil))
+		testutil.RequireEqual(t, input[i].labels, builder.Labels())
+	}
+	require.NoError(t, p.Err())
+}
@@ -0,0 +1,179 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/tsdb/index"
+)
+
+// labelPartitionCount is the number of time partitions tracked per
+// labelPartitions index. Partition membership is stored as a uint64 bitmask,
+// with the partition index taken modulo labelPartitionCount, so ranges wider
+// than labelPartitionCount partitions alias onto earlier bits. Aliasing only
+// ever adds false positives, it never hides a partition a value is in.
+const labelPartitionCount = 64
+
+// labelPartitions is a time-partitioned index of the label values present in
+// a block or in the head. For every label name/value pair it records a bitmask
+// of the fixed-width time partitions in which series carrying that pair have
+// samples. It is an over-approximation: a set bit means the value may have
+// samples in that partition, a value missing from the index entirely must be
+// assumed to be present everywhere. This makes it safe to consult a partially
+// populated index; the only cost of missing information is weaker filtering.
+type labelPartitions struct {
+	// width is the partition width in milliseconds. Immutable.
+	width int64
+
+	mtx sync.RWMutex
+	// masks maps label name to label value to the bitmask of partitions the
+	// pair has samples in. Masks are only ever extended.
+	masks map[string]map[string]uint64
+}
+
+// newLabelPartitions returns an empty index with the given partition width in
+// milliseconds.
+func newLabelPartitions(width int64) *labelPartitions {
+	if width < 1 {
+		width = 1
+	}
+	return &labelPartitions{
+		width: width,
+		masks: map[string]map[string]uint64{},
+	}
+}
+
+// partition returns the index of the partition containing t. It floors rather
+// than truncates so that negative timestamps do not share a partition with
+// positive ones.
+func (lp *labelPartitions) partition(t int64) int64 {
+	p := t / lp.width
+	if t%lp.width != 0 && t < 0 {
+		p--
+	}
+	return p
+}
+
+// maskBit returns the bitmask bit for partition index p.
+func maskBit(p int64) uint64 {
+	return 1 << uint64((p%labelPartitionCount+labelPartitionCount)%labelPartitionCount)
+}
+
+// bit returns the bitmask bit for the partition containing t.
+func (lp *labelPartitions) bit(t int64) uint64 {
+	return maskBit(lp.partition(t))
+}
+
+// rangeMask returns the bitmask covering all partitions overlapping the
+// closed interval [mint, maxt].
+func (lp *labelPartitions) rangeMask(mint, maxt int64) uint64 {
+	if maxt < mint {
+		return 0
+	}
+	// The unsigned subtraction is exact for any int64 pair with maxt >= mint
+	// and avoids overflow for queries over (almost) the whole int64 range.
+	if (uint64(maxt)-uint64(mint))/uint64(lp.width) >= labelPartitionCount-1 {
+		return ^uint64(0)
+	}
+	var mask uint64
+	for p, end := lp.partition(mint), lp.partition(maxt); ; p++ {
+		mask |= maskBit(p)
+		if p == end {
+			break
+		}
+	}
+	return mask
+}
+
+// add ORs mask into the partitions recorded for every label of lset.
+func (lp *labelPartitions) add(lset labels.Labels, mask uint64) {
+	lp.mtx.Lock()
+	defer lp.mtx.Unlock()
+	lset.Range(func(l labels.Label) {
+		vals, ok := lp.masks[l.Name]
+		if !ok {
+			vals = map[string]uint64{}
+			lp.masks[l.Name] = vals
+		}
+		vals[l.Value] |= mask
+	})
+}
+
+// filterValues returns the subset of values that may have samples in the
+// partitions of mask. Values the index has never seen are kept, so an
+// incomplete index degrades to weaker filtering rather than wrong results.
+// The returned slice reuses the backing array of values.
+func (lp *labelPartitions) filterValues(name string, values []string, mask uint64) []string {
+	lp.mtx.RLock()
+	defer lp.mtx.RUnlock()
+	vals, ok := lp.masks[name]
+	if !ok {
+		return values
+	}
+	filtered := values[:0]
+	for _, v := range values {
+		if m, ok := vals[v]; !ok || m&mask != 0 {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
+
+// labelPartitionReader is implemented by block readers that maintain a
+// time-partitioned label value index. labelValuePartitions returns the index
+// to consult for a query over [mint, maxt], or nil when no index is available
+// or the range covers all of the reader's data so that nothing can be
+// narrowed down.
+type labelPartitionReader interface {
+	labelValuePartitions(mint, maxt int64) *labelPartitions
+}
+
+// labelPartitionWidth returns the partition width that divides [mint, maxt]
+// into labelPartitionCount partitions.
+func labelPartitionWidth(mint, maxt int64) int64 {
+	return (maxt-mint)/labelPartitionCount + 1
+}
+
+// buildLabelPartitions constructs a labelPartitions index covering [mint,
+// maxt] from a full scan of the given block index, using the chunk metas of
+// every series to mark the partitions its labels have samples in.
+func buildLabelPartitions(ctx context.Context, ir IndexReader, mint, maxt int64) (*labelPartitions, error) {
+	lp := newLabelPartitions(labelPartitionWidth(mint, maxt))
+
+	k, v := index.AllPostingsKey()
+	p, err := ir.Postings(ctx, k, v)
+	if err != nil {
+		return nil, err
+	}
+	var builder labels.ScratchBuilder
+	var chks []chunks.Meta
+	for p.Next() {
+		if err := ir.Series(p.At(), &builder, &chks); err != nil {
+			return nil, err
+		}
+		var mask uint64
+		for _, c := range chks {
+			mask |= lp.rangeMask(c.MinTime, c.MaxTime)
+		}
+		lp.add(builder.Labels(), mask)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return lp, nil
+}
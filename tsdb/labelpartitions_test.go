@@ -0,0 +1,209 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/util/compression"
+)
+
+func TestLabelPartitionsRangeMask(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		width      int64
+		mint, maxt int64
+		expected   uint64
+	}{
+		{
+			name:     "empty range",
+			width:    10,
+			mint:     5,
+			maxt:     4,
+			expected: 0,
+		},
+		{
+			name:     "single partition",
+			width:    10,
+			mint:     12,
+			maxt:     18,
+			expected: 1 << 1,
+		},
+		{
+			name:     "partition boundaries",
+			width:    10,
+			mint:     10,
+			maxt:     20,
+			expected: 1<<1 | 1<<2,
+		},
+		{
+			name:     "negative timestamps floor",
+			width:    10,
+			mint:     -5,
+			maxt:     5,
+			expected: maskBit(-1) | maskBit(0),
+		},
+		{
+			name:     "wraps around the partition count",
+			width:    10,
+			mint:     630,
+			maxt:     645,
+			expected: 1<<63 | 1<<0,
+		},
+		{
+			name:     "wider than all partitions",
+			width:    10,
+			mint:     0,
+			maxt:     10_000,
+			expected: ^uint64(0),
+		},
+		{
+			name:     "whole int64 range does not overflow",
+			width:    10,
+			mint:     math.MinInt64,
+			maxt:     math.MaxInt64,
+			expected: ^uint64(0),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lp := newLabelPartitions(tc.width)
+			require.Equal(t, tc.expected, lp.rangeMask(tc.mint, tc.maxt))
+		})
+	}
+}
+
+func TestLabelPartitionsFilterValues(t *testing.T) {
+	lp := newLabelPartitions(10)
+	lp.add(labels.FromStrings("job", "early"), lp.rangeMask(0, 9))
+	lp.add(labels.FromStrings("job", "late"), lp.rangeMask(100, 109))
+
+	for _, tc := range []struct {
+		name       string
+		labelName  string
+		values     []string
+		mint, maxt int64
+		expected   []string
+	}{
+		{
+			name:      "matching partition kept",
+			labelName: "job",
+			values:    []string{"early", "late"},
+			mint:      0,
+			maxt:      5,
+			expected:  []string{"early"},
+		},
+		{
+			name:      "disjoint partitions dropped",
+			labelName: "job",
+			values:    []string{"early", "late"},
+			mint:      50,
+			maxt:      59,
+			expected:  []string{},
+		},
+		{
+			name:      "unseen values are kept",
+			labelName: "job",
+			values:    []string{"early", "unknown"},
+			mint:      50,
+			maxt:      59,
+			expected:  []string{"unknown"},
+		},
+		{
+			name:      "unseen label names pass through",
+			labelName: "instance",
+			values:    []string{"a", "b"},
+			mint:      50,
+			maxt:      59,
+			expected:  []string{"a", "b"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			values := append([]string{}, tc.values...)
+			require.Equal(t, tc.expected, lp.filterValues(tc.labelName, values, lp.rangeMask(tc.mint, tc.maxt)))
+		})
+	}
+}
+
+func TestBlockLabelValuesTimePartitioned(t *testing.T) {
+	ctx := context.Background()
+
+	blockDir := createBlock(t, t.TempDir(), []storage.Series{
+		storage.NewListSeries(labels.FromStrings("job", "early"), []chunks.Sample{sample{0, 0, 1, nil, nil}}),
+		storage.NewListSeries(labels.FromStrings("job", "late"), []chunks.Sample{sample{0, 64000, 1, nil, nil}}),
+	})
+	b, err := OpenBlock(nil, blockDir, nil, nil)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, b.Close()) }()
+
+	labelValues := func(mint, maxt int64) []string {
+		q, err := NewBlockQuerier(b, mint, maxt)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, q.Close()) }()
+		values, _, err := q.LabelValues(ctx, "job", nil)
+		require.NoError(t, err)
+		return values
+	}
+
+	// Queries over the whole block are never filtered.
+	require.Equal(t, []string{"early", "late"}, labelValues(b.MinTime(), b.MaxTime()))
+
+	// The partition index is built in the background on the first query over
+	// a subrange; once available, values without data in range are dropped.
+	require.Eventually(t, func() bool {
+		return len(labelValues(30000, 40000)) == 0
+	}, 5*time.Second, 10*time.Millisecond, "expected no label values in an empty subrange")
+
+	require.Equal(t, []string{"early"}, labelValues(0, 500))
+	require.Equal(t, []string{"late"}, labelValues(63500, b.MaxTime()))
+}
+
+func TestHeadLabelValuesTimePartitioned(t *testing.T) {
+	ctx := context.Background()
+
+	head, _ := newTestHead(t, 1000, compression.None, false)
+	defer func() { require.NoError(t, head.Close()) }()
+	head.initTime(0)
+
+	app := head.Appender(ctx)
+	_, err := app.Append(0, labels.FromStrings("job", "early"), 0, 1)
+	require.NoError(t, err)
+	_, err = app.Append(0, labels.FromStrings("job", "late"), 63000, 1)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	labelValues := func(mint, maxt int64) []string {
+		q, err := NewBlockQuerier(NewRangeHead(head, mint, maxt), mint, maxt)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, q.Close()) }()
+		values, _, err := q.LabelValues(ctx, "job", nil)
+		require.NoError(t, err)
+		return values
+	}
+
+	// Queries covering all data in the head are never filtered.
+	require.Equal(t, []string{"early", "late"}, labelValues(0, 63000))
+
+	// The head index is maintained on commit, so filtering is immediate.
+	require.Empty(t, labelValues(30000, 40000))
+	require.Equal(t, []string{"early"}, labelValues(0, 500))
+	require.Equal(t, []string{"late"}, labelValues(62500, 63000))
+}
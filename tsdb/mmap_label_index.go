@@ -0,0 +1,260 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/prometheus/prometheus/tsdb/fileutil"
+	"github.com/prometheus/prometheus/tsdb/index"
+)
+
+// mmapLabelIndexPrefix is the file name prefix of the snapshot files written
+// into the head directory.
+const mmapLabelIndexPrefix = "label_index."
+
+// mmapLabelIndex is a lazily materialized, memory-mapped snapshot of the
+// head's label names and values. It serves matcherless LabelNames and
+// LabelValues calls without allocating every string on each call, which
+// matters for metadata queries against heads with millions of series.
+//
+// The snapshot is rebuilt when the set of label names or values in the head
+// changes, detected through index.MemPostings.Version. Returned strings point
+// into the mapped file; superseded mappings are retired but kept open until
+// the index is closed so strings handed out earlier stay valid.
+type mmapLabelIndex struct {
+	dir string
+
+	mtx     sync.RWMutex
+	version uint64
+	seq     int
+	cur     *fileutil.MmapFile
+	retired []*fileutil.MmapFile
+	names   []string            // Sorted label names, backed by the mapped file.
+	values  map[string][]string // Sorted label values per name, backed by the mapped file.
+}
+
+// newMmapLabelIndex returns a label index writing its snapshot files into dir.
+// Snapshot files left over from a previous run are removed.
+func newMmapLabelIndex(dir string) (*mmapLabelIndex, error) {
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return nil, err
+	}
+	if err := removeMmapLabelIndexSnapshots(dir); err != nil {
+		return nil, fmt.Errorf("remove stale label index snapshots: %w", err)
+	}
+	return &mmapLabelIndex{dir: dir}, nil
+}
+
+// labelNames returns up to limit sorted label names. A limit of 0 or less
+// means no limit.
+func (idx *mmapLabelIndex) labelNames(p *index.MemPostings, limit int) ([]string, error) {
+	if err := idx.ensure(p); err != nil {
+		return nil, err
+	}
+	idx.mtx.RLock()
+	names := idx.names
+	idx.mtx.RUnlock()
+
+	if limit > 0 && len(names) > limit {
+		names = names[:limit]
+	}
+	return slices.Clone(names), nil
+}
+
+// labelValues returns up to limit sorted values of the label name. A limit of
+// 0 or less means no limit.
+func (idx *mmapLabelIndex) labelValues(p *index.MemPostings, name string, limit int) ([]string, error) {
+	if err := idx.ensure(p); err != nil {
+		return nil, err
+	}
+	idx.mtx.RLock()
+	values := idx.values[name]
+	idx.mtx.RUnlock()
+
+	if limit > 0 && len(values) > limit {
+		values = values[:limit]
+	}
+	return slices.Clone(values), nil
+}
+
+// ensure materializes a new snapshot if the postings' label set has changed
+// since the current one was built.
+func (idx *mmapLabelIndex) ensure(p *index.MemPostings) error {
+	v := p.Version()
+	idx.mtx.RLock()
+	fresh := idx.cur != nil && idx.version == v
+	idx.mtx.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+	if idx.cur != nil && idx.version == v {
+		return nil
+	}
+
+	// Read the version before enumerating: names or values added while the
+	// snapshot is built are then picked up by the next rebuild at the latest.
+	v = p.Version()
+	names := p.LabelNames()
+	slices.Sort(names)
+
+	buf := binary.AppendUvarint(nil, uint64(len(names)))
+	for _, n := range names {
+		buf = binary.AppendUvarint(buf, uint64(len(n)))
+		buf = append(buf, n...)
+		vals := p.LabelValues(context.Background(), n, nil)
+		slices.Sort(vals)
+		buf = binary.AppendUvarint(buf, uint64(len(vals)))
+		for _, val := range vals {
+			buf = binary.AppendUvarint(buf, uint64(len(val)))
+			buf = append(buf, val...)
+		}
+	}
+
+	idx.seq++
+	path := filepath.Join(idx.dir, fmt.Sprintf("%s%08d", mmapLabelIndexPrefix, idx.seq))
+	if err := os.WriteFile(path, buf, 0o666); err != nil {
+		return fmt.Errorf("write label index snapshot: %w", err)
+	}
+	mf, err := fileutil.OpenMmapFile(path)
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("mmap label index snapshot: %w", err)
+	}
+	mNames, mValues, err := parseMmapLabelIndex(mf.Bytes())
+	if err != nil {
+		mf.Close()
+		os.Remove(path)
+		return fmt.Errorf("parse label index snapshot: %w", err)
+	}
+
+	if idx.cur != nil {
+		idx.retired = append(idx.retired, idx.cur)
+	}
+	idx.cur = mf
+	idx.names = mNames
+	idx.values = mValues
+	idx.version = v
+	return nil
+}
+
+// invalidate forces the next enumeration to rebuild the snapshot. It must be
+// called when the head's postings index is replaced wholesale, as the version
+// counter of the new index starts over.
+func (idx *mmapLabelIndex) invalidate() {
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+	if idx.cur != nil {
+		idx.retired = append(idx.retired, idx.cur)
+		idx.cur = nil
+	}
+}
+
+// Close unmaps all snapshots, invalidating every string returned earlier, and
+// removes the snapshot files.
+func (idx *mmapLabelIndex) Close() error {
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+
+	var errs []error
+	for _, mf := range idx.retired {
+		errs = append(errs, mf.Close())
+	}
+	idx.retired = nil
+	if idx.cur != nil {
+		errs = append(errs, idx.cur.Close())
+		idx.cur = nil
+	}
+	idx.names, idx.values = nil, nil
+	errs = append(errs, removeMmapLabelIndexSnapshots(idx.dir))
+	return errors.Join(errs...)
+}
+
+// parseMmapLabelIndex decodes the snapshot format written by ensure. The
+// returned strings alias b and stay valid only as long as b stays mapped.
+func parseMmapLabelIndex(b []byte) ([]string, map[string][]string, error) {
+	readString := func() (string, error) {
+		l, n := binary.Uvarint(b)
+		if n <= 0 || uint64(len(b)-n) < l {
+			return "", errors.New("corrupted label index snapshot")
+		}
+		s := yoloString(b[n : n+int(l)])
+		b = b[n+int(l):]
+		return s, nil
+	}
+	numNames, n := binary.Uvarint(b)
+	if n <= 0 {
+		return nil, nil, errors.New("corrupted label index snapshot")
+	}
+	b = b[n:]
+
+	names := make([]string, 0, numNames)
+	values := make(map[string][]string, numNames)
+	for range numNames {
+		name, err := readString()
+		if err != nil {
+			return nil, nil, err
+		}
+		numValues, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, nil, errors.New("corrupted label index snapshot")
+		}
+		b = b[n:]
+		vals := make([]string, 0, numValues)
+		for range numValues {
+			val, err := readString()
+			if err != nil {
+				return nil, nil, err
+			}
+			vals = append(vals, val)
+		}
+		names = append(names, name)
+		values[name] = vals
+	}
+	return names, values, nil
+}
+
+// removeMmapLabelIndexSnapshots deletes all label index snapshot files in dir.
+func removeMmapLabelIndexSnapshots(dir string) error {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var errs []error
+	for _, f := range files {
+		if strings.HasPrefix(f.Name(), mmapLabelIndexPrefix) {
+			errs = append(errs, os.Remove(filepath.Join(dir, f.Name())))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func yoloString(b []byte) string {
+	return *((*string)(unsafe.Pointer(&b)))
+}
@@ -0,0 +1,149 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+)
+
+func TestDBMmapLabelIndex(t *testing.T) {
+	opts := DefaultOptions()
+	opts.EnableMmapLabelIndex = true
+	db := newTestDB(t, withOpts(opts))
+
+	ctx := context.Background()
+	app := db.Appender(ctx)
+	for i, job := range []string{"a", "a", "b"} {
+		_, err := app.Append(0, labels.FromStrings(
+			"__name__", "metric",
+			"job", job,
+			"instance", fmt.Sprintf("host-%d", i),
+		), 100, 1)
+		require.NoError(t, err)
+	}
+	require.NoError(t, app.Commit())
+
+	newQuerier := func() storage.Querier {
+		q, err := db.Querier(math.MinInt64, math.MaxInt64)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, q.Close())
+		})
+		return q
+	}
+
+	q := newQuerier()
+	names, _, err := q.LabelNames(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"__name__", "instance", "job"}, names)
+
+	values, _, err := q.LabelValues(ctx, "job", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, values)
+
+	values, _, err = q.LabelValues(ctx, "instance", &storage.LabelHints{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"host-0", "host-1"}, values)
+
+	values, _, err = q.LabelValues(ctx, "unknown", nil)
+	require.NoError(t, err)
+	require.Empty(t, values)
+
+	// The matcher path must not be affected by the label index.
+	values, _, err = q.LabelValues(ctx, "instance", nil, labels.MustNewMatcher(labels.MatchEqual, "job", "b"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"host-2"}, values)
+
+	// A snapshot file must have been materialized in the data directory.
+	snapshots := func() int {
+		files, err := os.ReadDir(db.Dir())
+		require.NoError(t, err)
+		n := 0
+		for _, f := range files {
+			if strings.HasPrefix(f.Name(), mmapLabelIndexPrefix) {
+				n++
+			}
+		}
+		return n
+	}
+	require.Equal(t, 1, snapshots())
+
+	// New label values must show up after the snapshot is rebuilt.
+	app = db.Appender(ctx)
+	_, err = app.Append(0, labels.FromStrings("__name__", "metric", "job", "c", "instance", "host-3"), 200, 1)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	q = newQuerier()
+	values, _, err = q.LabelValues(ctx, "job", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, values)
+	require.Equal(t, 2, snapshots())
+
+	// Closing the database removes the snapshot files.
+	dir := db.Dir()
+	require.NoError(t, db.Close())
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, f := range files {
+		require.False(t, strings.HasPrefix(f.Name(), mmapLabelIndexPrefix), "leftover snapshot file %s", f.Name())
+	}
+}
+
+func BenchmarkHeadLabelValuesMmapIndex(b *testing.B) {
+	for _, enabled := range []bool{false, true} {
+		b.Run(fmt.Sprintf("mmapLabelIndex=%t", enabled), func(b *testing.B) {
+			opts := DefaultOptions()
+			opts.EnableMmapLabelIndex = enabled
+			db := newTestDB(b, withOpts(opts))
+
+			ctx := context.Background()
+			app := db.Appender(ctx)
+			for i := range 100000 {
+				_, err := app.Append(0, labels.FromStrings(
+					"__name__", "metric",
+					"instance", fmt.Sprintf("host-%d", i),
+				), 100, 1)
+				require.NoError(b, err)
+			}
+			require.NoError(b, app.Commit())
+
+			q, err := db.Querier(math.MinInt64, math.MaxInt64)
+			require.NoError(b, err)
+			defer q.Close()
+
+			b.ResetTimer()
+			b.ReportAllocs()
+			for b.Loop() {
+				values, _, err := q.LabelValues(ctx, "instance", nil)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(values) != 100000 {
+					b.Fatalf("unexpected number of values: %d", len(values))
+				}
+			}
+		})
+	}
+}
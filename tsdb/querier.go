@@ -41,6 +41,10 @@ type blockBaseQuerier struct {
 	chunks     ChunkReader
 	tombstones tombstones.Reader
 
+	// partitionr is set when the underlying block maintains a
+	// time-partitioned label value index; nil otherwise.
+	partitionr labelPartitionReader
+
 	closed bool
 
 	mint, maxt int64
@@ -68,19 +72,58 @@ func newBlockBaseQuerier(b BlockReader, mint, maxt int64) (*blockBaseQuerier, er
 	if tombsr == nil {
 		tombsr = tombstones.NewMemTombstones()
 	}
-	return &blockBaseQuerier{
+	q := &blockBaseQuerier{
 		blockID:    b.Meta().ULID,
 		mint:       mint,
 		maxt:       maxt,
 		index:      indexr,
 		chunks:     chunkr,
 		tombstones: tombsr,
-	}, nil
+	}
+	if pr, ok := b.(labelPartitionReader); ok {
+		q.partitionr = pr
+	}
+	return q, nil
+}
+
+// labelValuesFilter returns a function dropping label values that have no
+// samples in the queried time range, or nil when no time-partitioned label
+// value index can narrow this query down. The filter is an over-
+// approximation: it only drops values known to have no data in range.
+func (q *blockBaseQuerier) labelValuesFilter(name string) func([]string) []string {
+	if q.partitionr == nil {
+		return nil
+	}
+	lp := q.partitionr.labelValuePartitions(q.mint, q.maxt)
+	if lp == nil {
+		return nil
+	}
+	mask := lp.rangeMask(q.mint, q.maxt)
+	if mask == ^uint64(0) {
+		return nil
+	}
+	return func(values []string) []string {
+		return lp.filterValues(name, values, mask)
+	}
 }
 
 func (q *blockBaseQuerier) LabelValues(ctx context.Context, name string, hints *storage.LabelHints, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
-	res, err := q.index.SortedLabelValues(ctx, name, hints, matchers...)
-	return res, nil, err
+	filter := q.labelValuesFilter(name)
+	indexHints := hints
+	if filter != nil && hints != nil && hints.Limit > 0 {
+		// The limit can only be applied once values outside the queried
+		// time range have been dropped.
+		indexHints = nil
+	}
+	res, err := q.index.SortedLabelValues(ctx, name, indexHints, matchers...)
+	if err != nil || filter == nil {
+		return res, nil, err
+	}
+	res = filter(res)
+	if hints != nil && hints.Limit > 0 && len(res) > hints.Limit {
+		res = res[:hints.Limit]
+	}
+	return res, nil, nil
 }
 
 func (q *blockBaseQuerier) LabelNames(ctx context.Context, hints *storage.LabelHints, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
@@ -112,11 +155,13 @@ func (q *blockBaseQuerier) SearchLabelValues(ctx context.Context, name string, h
 		hints = &storage.SearchHints{}
 	}
 
+	filter := q.labelValuesFilter(name)
+
 	// Limit pushdown is only correct when natural (ascending) index order
 	// is preserved all the way to the output and no filtering discards
 	// values ahead of the limit.
 	labelHints := &storage.LabelHints{}
-	if hints.OrderBy == storage.OrderByValueAsc && hints.Filter == nil {
+	if hints.OrderBy == storage.OrderByValueAsc && hints.Filter == nil && filter == nil {
 		labelHints.Limit = hints.Limit
 	}
 
@@ -135,6 +180,9 @@ func (q *blockBaseQuerier) SearchLabelValues(ctx context.Context, name string, h
 	if err != nil {
 		return storage.ErrSearchResultSet(err)
 	}
+	if filter != nil {
+		values = filter(values)
+	}
 
 	return storage.NewSearchResultSetFromSlice(storage.ApplySearchHints(values, hints), nil)
 }
@@ -184,6 +232,41 @@ func enableChunkCache(cr ChunkReader) {
 	}
 }
 
+// chunkStatsReader is an optional interface implemented by index readers whose
+// series entries carry per-chunk summary statistics, see index.FormatV5.
+type chunkStatsReader interface {
+	HasChunkStats() bool
+}
+
+// chunkSummaryFuncs are the over-time functions whose result over a full chunk
+// can be answered from the chunk's summary statistics alone. count_over_time
+// cannot be listed here: the engine counts the returned samples itself, so the
+// number of samples must not be reduced.
+var chunkSummaryFuncs = map[string]bool{
+	"min_over_time": true,
+	"max_over_time": true,
+	"sum_over_time": true,
+}
+
+// useChunkSummaries returns whether a Select call may answer from per-chunk
+// summary statistics. This is only sound for an instant evaluation of one of
+// the chunkSummaryFuncs over a plain matrix selector window: for stepped
+// evaluations every step covers a different fraction of each chunk, so the
+// full samples are needed.
+func useChunkSummaries(hints *storage.SelectHints, ir IndexReader) bool {
+	if hints == nil || !chunkSummaryFuncs[hints.Func] {
+		return false
+	}
+	// The engine selects the left-open window (End-Range, End] for a matrix
+	// selector, i.e. Start = End - Range + 1. Anything else (lookback,
+	// subqueries, stepped evaluation) disqualifies the fast path.
+	if hints.Step != 0 || hints.Range == 0 || hints.End-hints.Start != hints.Range-1 || hints.DisableTrimming {
+		return false
+	}
+	sr, ok := ir.(chunkStatsReader)
+	return ok && sr.HasChunkStats()
+}
+
 func selectSeriesSet(ctx context.Context, sortSeries bool, hints *storage.SelectHints, ms []*labels.Matcher,
 	index IndexReader, chunks ChunkReader, tombstones tombstones.Reader, mint, maxt int64,
 ) storage.SeriesSet {
@@ -213,11 +296,98 @@ func selectSeriesSet(ctx context.Context, sortSeries bool, hints *storage.Select
 			// When you're only looking up metadata (for example series API), you don't need to load any chunks.
 			return newBlockSeriesSet(index, newNopChunkReader(), tombstones, p, mint, maxt, disableTrimming)
 		}
+		if useChunkSummaries(hints, index) {
+			return newSummarySeriesSet(NewBlockChunkSeriesSet(ulid.ULID{}, index, chunks, tombstones, p, mint, maxt, disableTrimming), hints.Func)
+		}
 	}
 
 	return newBlockSeriesSet(index, chunks, tombstones, p, mint, maxt, disableTrimming)
 }
 
+// summarySeriesSet reduces every chunk that carries summary statistics to a
+// single synthetic sample holding the aggregate the caller asked for, decoding
+// only the chunks without statistics (boundary chunks re-encoded by trimming
+// or deletions, and histogram chunks). The returned values are only meaningful
+// to the over-time function named in the Select hints.
+type summarySeriesSet struct {
+	set  storage.ChunkSeriesSet
+	fn   string
+	curr storage.Series
+	err  error
+}
+
+func newSummarySeriesSet(set storage.ChunkSeriesSet, fn string) storage.SeriesSet {
+	return &summarySeriesSet{set: set, fn: fn}
+}
+
+func (s *summarySeriesSet) Next() bool {
+	if s.err != nil || !s.set.Next() {
+		return false
+	}
+	cs := s.set.At()
+
+	var (
+		samples []chunks.Sample
+		it      chunkenc.Iterator
+	)
+	metas := cs.Iterator(nil)
+	for metas.Next() {
+		meta := metas.At()
+		if stats := meta.Stats; stats.Count > 0 {
+			var v float64
+			switch s.fn {
+			case "min_over_time":
+				v = stats.Min
+			case "max_over_time":
+				v = stats.Max
+			case "sum_over_time":
+				v = stats.Sum
+			}
+			samples = append(samples, newSample(0, meta.MaxTime, v, nil, nil))
+			continue
+		}
+		it = meta.Chunk.Iterator(it)
+		for vt := it.Next(); vt != chunkenc.ValNone; vt = it.Next() {
+			switch vt {
+			case chunkenc.ValFloat:
+				t, v := it.At()
+				samples = append(samples, newSample(it.AtST(), t, v, nil, nil))
+			case chunkenc.ValHistogram:
+				t, h := it.AtHistogram(nil)
+				samples = append(samples, newSample(it.AtST(), t, 0, h, nil))
+			case chunkenc.ValFloatHistogram:
+				t, fh := it.AtFloatHistogram(nil)
+				samples = append(samples, newSample(it.AtST(), t, 0, nil, fh))
+			default:
+				s.err = fmt.Errorf("summarySeriesSet: value type %v unsupported", vt)
+				return false
+			}
+		}
+		if err := it.Err(); err != nil {
+			s.err = fmt.Errorf("summarySeriesSet: iterate chunk: %w", err)
+			return false
+		}
+	}
+	if err := metas.Err(); err != nil {
+		s.err = fmt.Errorf("summarySeriesSet: iterate chunk metas: %w", err)
+		return false
+	}
+
+	s.curr = storage.NewListSeries(cs.Labels(), samples)
+	return true
+}
+
+func (s *summarySeriesSet) At() storage.Series { return s.curr }
+
+func (s *summarySeriesSet) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.set.Err()
+}
+
+func (s *summarySeriesSet) Warnings() annotations.Annotations { return s.set.Warnings() }
+
 // blockChunkQuerier provides chunk querying access to a single block database.
 type blockChunkQuerier struct {
 	*blockBaseQuerier
@@ -776,6 +946,12 @@ type blockSeriesEntry struct {
 	seriesData
 }
 
+// SampleOrigin implements storage.SeriesOriginProvider. It reports the
+// provenance recorded for the series' most recent chunk that carries one.
+func (s *blockSeriesEntry) SampleOrigin() chunks.SampleOrigin {
+	return seriesSampleOrigin(s.chks)
+}
+
 func (s *blockSeriesEntry) Iterator(it chunkenc.Iterator) chunkenc.Iterator {
 	pi, ok := it.(*populateWithDelSeriesIterator)
 	if !ok {
@@ -791,6 +967,21 @@ type chunkSeriesEntry struct {
 	seriesData
 }
 
+// SampleOrigin implements storage.SeriesOriginProvider. It reports the
+// provenance recorded for the series' most recent chunk that carries one.
+func (s *chunkSeriesEntry) SampleOrigin() chunks.SampleOrigin {
+	return seriesSampleOrigin(s.chks)
+}
+
+func seriesSampleOrigin(chks []chunks.Meta) chunks.SampleOrigin {
+	for i := len(chks) - 1; i >= 0; i-- {
+		if o := chks[i].Origin; o != chunks.OriginUnknown {
+			return o
+		}
+	}
+	return chunks.OriginUnknown
+}
+
 func (s *chunkSeriesEntry) Iterator(it chunks.Iterator) chunks.Iterator {
 	pi, ok := it.(*populateWithDelChunkSeriesIterator)
 	if !ok {
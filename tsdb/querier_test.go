@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/oklog/ulid/v2"
+	"github.com/prometheus/common/promslog"
 	"github.com/stretchr/testify/require"
 
 	"github.com/prometheus/prometheus/model/histogram"
@@ -727,6 +728,103 @@ type fakeChunksReader struct {
 	iterables map[chunks.ChunkRef]chunkenc.Iterable
 }
 
+func TestBlockQuerier_ChunkSummaries(t *testing.T) {
+	ctx := context.Background()
+	lbls := labels.FromStrings("a", "1")
+	var smpls []chunks.Sample
+	for ts := int64(0); ts < 1000; ts++ {
+		smpls = append(smpls, sample{0, ts, float64(ts%100) - 50, nil, nil})
+	}
+	head := createHead(t, nil, []storage.Series{storage.NewListSeries(lbls, smpls)}, t.TempDir())
+	defer func() { require.NoError(t, head.Close()) }()
+
+	compactor, err := NewLeveledCompactorWithOptions(ctx, nil, promslog.NewNopLogger(), []int64{1000000}, chunkenc.NewPool(), LeveledCompactorOptions{
+		EnableChunkSummaries: true,
+	})
+	require.NoError(t, err)
+	dir := t.TempDir()
+	ulids, err := compactor.Write(dir, head, 0, 1000, nil)
+	require.NoError(t, err)
+	require.Len(t, ulids, 1)
+
+	block, err := OpenBlock(nil, filepath.Join(dir, ulids[0].String()), nil, nil)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, block.Close()) }()
+
+	// An instant over-time function evaluation over a plain 900ms window.
+	const mint, maxt = 100, 999
+	expMin, expMax, expSum, expCount := math.Inf(1), math.Inf(-1), 0.0, 0
+	for _, s := range smpls {
+		if s.T() < mint || s.T() > maxt {
+			continue
+		}
+		expMin = math.Min(expMin, s.F())
+		expMax = math.Max(expMax, s.F())
+		expSum += s.F()
+		expCount++
+	}
+
+	query := func(hints *storage.SelectHints) []float64 {
+		q, err := NewBlockQuerier(block, mint, maxt)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, q.Close()) }()
+
+		ss := q.Select(ctx, false, hints, labels.MustNewMatcher(labels.MatchEqual, "a", "1"))
+		require.True(t, ss.Next())
+		var vs []float64
+		it := ss.At().Iterator(nil)
+		for it.Next() == chunkenc.ValFloat {
+			_, v := it.At()
+			vs = append(vs, v)
+		}
+		require.NoError(t, it.Err())
+		require.False(t, ss.Next())
+		require.NoError(t, ss.Err())
+		return vs
+	}
+
+	hints := func(f string) *storage.SelectHints {
+		return &storage.SelectHints{Start: mint, End: maxt, Range: maxt - mint + 1, Func: f}
+	}
+
+	for fn, exp := range map[string]float64{
+		"min_over_time": expMin,
+		"max_over_time": expMax,
+		"sum_over_time": expSum,
+	} {
+		vs := query(hints(fn))
+		// Chunks fully inside the window collapse into one summary sample each,
+		// so far fewer samples come back than the window holds.
+		require.NotEmpty(t, vs, "function %s", fn)
+		require.Less(t, len(vs), expCount, "function %s", fn)
+
+		gotMin, gotMax, gotSum := math.Inf(1), math.Inf(-1), 0.0
+		for _, v := range vs {
+			gotMin = math.Min(gotMin, v)
+			gotMax = math.Max(gotMax, v)
+			gotSum += v
+		}
+		switch fn {
+		case "min_over_time":
+			require.Equal(t, exp, gotMin)
+		case "max_over_time":
+			require.Equal(t, exp, gotMax)
+		case "sum_over_time":
+			require.InDelta(t, exp, gotSum, 1e-9)
+		}
+	}
+
+	// Functions that need the individual samples, range queries and plain
+	// selects must all see the full data.
+	for _, h := range []*storage.SelectHints{
+		nil,
+		hints("count_over_time"),
+		{Start: mint, End: maxt, Range: maxt - mint + 1, Step: 60, Func: "max_over_time"},
+	} {
+		require.Len(t, query(h), expCount)
+	}
+}
+
 func createFakeReaderAndNotPopulatedChunks(s ...[]chunks.Sample) (*fakeChunksReader, []chunks.Meta) {
 	f := &fakeChunksReader{
 		chks:      map[chunks.ChunkRef]chunkenc.Chunk{},
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"math/bits"
 	"unsafe"
 
 	"github.com/prometheus/common/model"
@@ -64,6 +65,12 @@ const (
 	HistogramSamplesV2 Type = 12
 	// FloatHistogramSamplesV2 is an enhanced float histogram record that supports start time per sample.
 	FloatHistogramSamplesV2 Type = 13
+	// SamplesV3 groups consecutive samples of the same series, so that the series
+	// reference is written once per group and timestamps and values are
+	// delta-encoded against the previous sample of the group. This reduces the
+	// record size considerably when a record holds multiple samples per series,
+	// e.g. for high-frequency scrapes.
+	SamplesV3 Type = 14
 )
 
 func (rt Type) String() string {
@@ -74,6 +81,8 @@ func (rt Type) String() string {
 		return "samples"
 	case SamplesV2:
 		return "samples_v2"
+	case SamplesV3:
+		return "samples_v3"
 	case Tombstones:
 		return "tombstones"
 	case Exemplars:
@@ -232,7 +241,7 @@ func (*Decoder) Type(rec []byte) Type {
 		return Unknown
 	}
 	switch t := Type(rec[0]); t {
-	case Series, Samples, SamplesV2, Tombstones, Exemplars, MmapMarkers, Metadata,
+	case Series, Samples, SamplesV2, SamplesV3, Tombstones, Exemplars, MmapMarkers, Metadata,
 		HistogramSamples, FloatHistogramSamples, CustomBucketsHistogramSamples, CustomBucketsFloatHistogramSamples,
 		HistogramSamplesV2, FloatHistogramSamplesV2:
 		return t
@@ -332,8 +341,10 @@ func (d *Decoder) Samples(rec []byte, samples []RefSample) ([]RefSample, error)
 		return d.samplesV1(&dec, samples)
 	case SamplesV2:
 		return d.samplesV2(&dec, samples)
+	case SamplesV3:
+		return d.samplesV3(&dec, samples)
 	default:
-		return nil, fmt.Errorf("invalid record type %v, expected Samples(2) or SamplesV2(11)", typ)
+		return nil, fmt.Errorf("invalid record type %v, expected Samples(2), SamplesV2(11) or SamplesV3(14)", typ)
 	}
 }
 
@@ -418,6 +429,59 @@ func (*Decoder) samplesV2(dec *encoding.Decbuf, samples []RefSample) ([]RefSampl
 	return samples, nil
 }
 
+// samplesV3 appends samples in rec to the given slice using the V3 algorithm,
+// which groups consecutive samples per series (see Encoder.samplesV3).
+func (*Decoder) samplesV3(dec *encoding.Decbuf, samples []RefSample) ([]RefSample, error) {
+	if dec.Len() == 0 {
+		return samples, nil
+	}
+	// Each sample takes at least a varint timestamp, an ST marker and a value
+	// byte; the output slice must be at least that big.
+	if minSize := dec.Len() / (1 + 1 + 1); cap(samples) < minSize {
+		samples = make([]RefSample, 0, minSize)
+	}
+	var (
+		ref, t, st, firstST int64
+		vbits               uint64
+		first               = true
+	)
+	for len(dec.B) > 0 && dec.Err() == nil {
+		ref += dec.Varint64()
+		n := dec.Uvarint()
+		for i := 0; i < n && dec.Err() == nil; i++ {
+			switch {
+			case first:
+				t = dec.Varint64()
+				st = dec.Varint64()
+				firstST = st
+				first = false
+			default:
+				t += dec.Varint64()
+				st = readSTMarker(dec, st, firstST)
+			}
+			if i == 0 {
+				vbits = dec.Be64()
+			} else {
+				vbits ^= bits.ReverseBytes64(dec.Uvarint64())
+			}
+			samples = append(samples, RefSample{
+				Ref: chunks.HeadSeriesRef(ref),
+				ST:  st,
+				T:   t,
+				V:   math.Float64frombits(vbits),
+			})
+		}
+	}
+
+	if dec.Err() != nil {
+		return nil, fmt.Errorf("decode error after %d samples: %w", len(samples), dec.Err())
+	}
+	if len(dec.B) > 0 {
+		return nil, fmt.Errorf("unexpected %d bytes left in entry", len(dec.B))
+	}
+	return samples, nil
+}
+
 func readSTMarker(buf *encoding.Decbuf, prevST, firstST int64) int64 {
 	stMarker := buf.Byte()
 	switch stMarker {
@@ -878,6 +942,10 @@ type Encoder struct {
 	// EnableSTStorage enables the SamplesV2 encoding, which is more efficient
 	// than V1 and supports start time per sample.
 	EnableSTStorage bool
+	// EnableSampleGrouping enables the SamplesV3 encoding, which additionally
+	// groups consecutive samples of the same series and takes precedence over
+	// EnableSTStorage for float samples.
+	EnableSampleGrouping bool
 }
 
 // Series appends the encoded series to b and returns the resulting slice.
@@ -924,13 +992,17 @@ func EncodeLabels(buf *encoding.Encbuf, lbls labels.Labels) {
 }
 
 // Samples appends the encoded samples to b and returns the resulting slice.
-// Depending on EnableSTStorage, it writes either a Samples or SamplesV2
-// record.
+// Depending on EnableSampleGrouping and EnableSTStorage, it writes a
+// SamplesV3, SamplesV2 or Samples record.
 func (e *Encoder) Samples(samples []RefSample, b []byte) []byte {
-	if e.EnableSTStorage {
+	switch {
+	case e.EnableSampleGrouping:
+		return e.samplesV3(samples, b)
+	case e.EnableSTStorage:
 		return e.samplesV2(samples, b)
+	default:
+		return e.samplesV1(samples, b)
 	}
-	return e.samplesV1(samples, b)
 }
 
 // samplesV1 appends the encoded samples to b and returns the resulting slice.
@@ -999,6 +1071,63 @@ func (*Encoder) samplesV2(samples []RefSample, b []byte) []byte {
 	return buf.Get()
 }
 
+// samplesV3 appends the encoded samples to b and returns the resulting slice.
+// Consecutive samples of the same series form a group: the series reference
+// and the sample count are written once per group, timestamps are
+// delta-encoded against the immediately preceding sample, and values after
+// the first of a group are encoded as the XOR against the previous value,
+// byte-reversed so that unchanged trailing bits shrink the varint. Start
+// timestamps use the same marker scheme as V2.
+func (*Encoder) samplesV3(samples []RefSample, b []byte) []byte {
+	buf := encoding.Encbuf{B: b}
+	buf.PutByte(byte(SamplesV3))
+
+	if len(samples) == 0 {
+		return buf.Get()
+	}
+
+	var (
+		ref, t, st, firstST int64
+		vbits               uint64
+		first               = true
+	)
+	for i := 0; i < len(samples); {
+		// Find the end of the group of consecutive samples with the same ref.
+		j := i + 1
+		for j < len(samples) && samples[j].Ref == samples[i].Ref {
+			j++
+		}
+
+		buf.PutVarint64(int64(samples[i].Ref) - ref)
+		ref = int64(samples[i].Ref)
+		buf.PutUvarint(j - i)
+
+		for k := i; k < j; k++ {
+			s := samples[k]
+			if first {
+				buf.PutVarint64(s.T)
+				buf.PutVarint64(s.ST)
+				firstST = s.ST
+				first = false
+			} else {
+				buf.PutVarint64(s.T - t)
+				writeSTMarker(&buf, s.ST, firstST, st)
+			}
+			t, st = s.T, s.ST
+
+			if k == i {
+				buf.PutBE64(math.Float64bits(s.V))
+				vbits = math.Float64bits(s.V)
+			} else {
+				buf.PutUvarint64(bits.ReverseBytes64(math.Float64bits(s.V) ^ vbits))
+				vbits = math.Float64bits(s.V)
+			}
+		}
+		i = j
+	}
+	return buf.Get()
+}
+
 func writeSTMarker(buf *encoding.Encbuf, st, firstST, prevST int64) {
 	switch st {
 	case 0:
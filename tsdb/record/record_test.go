@@ -133,6 +133,30 @@ func TestRecord_EncodeDecode(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, samplesWithConstST, decSamples)
 
+	enc = Encoder{EnableSampleGrouping: true}
+	// Grouped encoding with multiple consecutive samples per series, as
+	// written for high-frequency scrapes, including single-sample groups,
+	// repeated values and STs.
+	groupedSamples := []RefSample{
+		{Ref: 0, T: 12423400, ST: 12423300, V: 1.2345},
+		{Ref: 0, T: 12423500, ST: 12423300, V: 1.2345},
+		{Ref: 0, T: 12423600, ST: 12423300, V: 1.2346},
+		{Ref: 123, T: 12423400, V: -123},
+		{Ref: 123, T: 12423500, V: -120},
+		{Ref: 2, T: 0, ST: 14, V: 99999},
+		{Ref: 0, T: 12423700, ST: 12423300, V: 1.2347},
+	}
+	encoded = enc.Samples(groupedSamples, nil)
+	require.Equal(t, SamplesV3, dec.Type(encoded))
+	decSamples, err = dec.Samples(encoded, nil)
+	require.NoError(t, err)
+	require.Equal(t, groupedSamples, decSamples)
+
+	// The grouped record must stay smaller than the ungrouped V2 one for
+	// records holding several samples per series.
+	encV2 := Encoder{EnableSTStorage: true}
+	require.Less(t, len(encoded), len(encV2.Samples(groupedSamples, nil)))
+
 	// Intervals get split up into single entries. So we don't get back exactly
 	// what we put in.
 	tstones := []tombstones.Stone{
@@ -916,8 +940,7 @@ func TestRecord_DecodeTooHighResolutionFloatHistogramSchema(t *testing.T) {
 // TestRecord_Corrupted ensures that corrupted records return the correct error.
 // Bugfix check for pull/521 and pull/523.
 func TestRecord_Corrupted(t *testing.T) {
-	for _, enableSTStorage := range []bool{false, true} {
-		enc := Encoder{EnableSTStorage: enableSTStorage}
+	for _, enc := range []Encoder{{}, {EnableSTStorage: true}, {EnableSampleGrouping: true}} {
 		dec := NewDecoder(labels.NewSymbolTable(), promslog.NewNopLogger())
 
 		t.Run("Test corrupted series record", func(t *testing.T) {
@@ -1046,6 +1069,11 @@ func TestRecord_Type(t *testing.T) {
 	recordType = dec.Type(enc.Samples(samplesST, nil))
 	require.Equal(t, SamplesV2, recordType)
 
+	// With EnableSampleGrouping set, all Samples are V3.
+	enc = Encoder{EnableSampleGrouping: true}
+	recordType = dec.Type(enc.Samples(samplesST, nil))
+	require.Equal(t, SamplesV3, recordType)
+
 	tstones := []tombstones.Stone{{Ref: 1, Intervals: tombstones.Intervals{{Mint: 1, Maxt: 2}}}}
 	recordType = dec.Type(enc.Tombstones(tstones, nil))
 	require.Equal(t, Tombstones, recordType)
@@ -1324,6 +1352,48 @@ func BenchmarkWAL_HistogramEncoding(b *testing.B) {
 	}
 }
 
+// BenchmarkWAL_SamplesEncoding compares the sample record encodings on records
+// holding multiple consecutive samples per series, as written by
+// high-frequency scrapes or batched remote-write receivers.
+func BenchmarkWAL_SamplesEncoding(b *testing.B) {
+	for _, numSeries := range []int{100, 1000} {
+		for _, samplesPerSeries := range []int{1, 10, 60} {
+			samples := make([]RefSample, 0, numSeries*samplesPerSeries)
+			for i := range numSeries {
+				v := float64(i)
+				for j := range samplesPerSeries {
+					// Counter-like values with occasional repeats.
+					if j%3 != 0 {
+						v++
+					}
+					samples = append(samples, RefSample{
+						Ref: chunks.HeadSeriesRef(i),
+						ST:  1000,
+						T:   int64(100_000 + j*250),
+						V:   v,
+					})
+				}
+			}
+			for _, version := range []string{"v1", "v2", "v3"} {
+				var enc Encoder
+				switch version {
+				case "v2":
+					enc = Encoder{EnableSTStorage: true}
+				case "v3":
+					enc = Encoder{EnableSampleGrouping: true}
+				}
+				b.Run(fmt.Sprintf("series=%d/samplesPerSeries=%d/%s", numSeries, samplesPerSeries, version), func(b *testing.B) {
+					var rec []byte
+					for b.Loop() {
+						rec = enc.Samples(samples, rec[:0])
+					}
+					b.ReportMetric(float64(len(rec)), "recordBytes/op")
+				})
+			}
+		}
+	}
+}
+
 // BenchmarkDecodeHistogramSamples measures per-sample allocation cost for
 // histogram WAL decoding — both V1 and V2 paths. This exercises the hot
 // path identified in finding B/C of the allocation analysis: one
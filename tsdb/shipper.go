@@ -0,0 +1,97 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// BlockShipper uploads finished blocks to remote storage. The database invokes
+// it after a block has been persisted and made visible to queries, e.g.
+// following head truncation or block compaction, so integrations can ship
+// blocks directly to object storage such as S3 or GCS without running a
+// sidecar process.
+//
+// ShipBlock must be safe for concurrent use and idempotent: the same block may
+// be shipped again after a failed attempt or a restart. The block directory
+// must be treated as read-only and is only guaranteed to exist for the
+// duration of the call; the block may be deleted by compaction or retention
+// afterwards.
+type BlockShipper interface {
+	ShipBlock(ctx context.Context, meta BlockMeta, dir string) error
+}
+
+// NoopBlockShipper is a BlockShipper that does nothing. It can be embedded by
+// integrations that only want to implement parts of a richer shipper
+// interface, and is the behaviour the database falls back to when no shipper
+// is configured.
+type NoopBlockShipper struct{}
+
+// ShipBlock implements the BlockShipper interface.
+func (NoopBlockShipper) ShipBlock(context.Context, BlockMeta, string) error { return nil }
+
+// blockShipperMaxAttempts is the number of times a block upload is tried
+// before it is given up on.
+const blockShipperMaxAttempts = 3
+
+// blockShipperInitialBackoff is the wait before the first retry. It is doubled
+// after every failed attempt. A variable so tests can lower it.
+var blockShipperInitialBackoff = 10 * time.Second
+
+// shipBlocks hands the given freshly persisted blocks to the configured
+// BlockShipper, retrying failed uploads with exponential backoff. Shipping
+// errors are logged and counted but do not fail the compaction or truncation
+// that produced the blocks: the blocks stay on local disk either way and an
+// idempotent shipper can pick them up again later.
+func (db *DB) shipBlocks(uids []ulid.ULID) {
+	if db.shipper == nil {
+		return
+	}
+	for _, uid := range uids {
+		dir := filepath.Join(db.dir, uid.String())
+		meta, _, err := readMetaFile(dir)
+		if err != nil {
+			db.logger.Error("Reading meta of block to ship failed", "block", uid, "err", err)
+			db.metrics.blockShipmentFailures.Inc()
+			continue
+		}
+
+		backoff := blockShipperInitialBackoff
+		for attempt := 1; ; attempt++ {
+			err := db.shipper.ShipBlock(context.Background(), *meta, dir)
+			if err == nil {
+				db.metrics.blockShipments.Inc()
+				break
+			}
+			if attempt >= blockShipperMaxAttempts {
+				db.logger.Error("Shipping block failed, giving up", "block", uid, "attempts", attempt, "err", err)
+				db.metrics.blockShipmentFailures.Inc()
+				break
+			}
+			db.logger.Warn("Shipping block failed, retrying", "block", uid, "attempt", attempt, "err", err)
+			db.metrics.blockShipmentRetries.Inc()
+			select {
+			case <-db.stopc:
+				db.metrics.blockShipmentFailures.Inc()
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsdb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	prom_testutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// recordingBlockShipper records shipped blocks and can be told to fail a
+// number of upload attempts first.
+type recordingBlockShipper struct {
+	mtx      sync.Mutex
+	failures int
+	attempts int
+	shipped  []BlockMeta
+	dirs     []string
+}
+
+func (s *recordingBlockShipper) ShipBlock(_ context.Context, meta BlockMeta, dir string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.attempts++
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("upload failed")
+	}
+	s.shipped = append(s.shipped, meta)
+	s.dirs = append(s.dirs, dir)
+	return nil
+}
+
+func TestDBBlockShipper(t *testing.T) {
+	oldBackoff := blockShipperInitialBackoff
+	blockShipperInitialBackoff = time.Millisecond
+	t.Cleanup(func() { blockShipperInitialBackoff = oldBackoff })
+
+	// The first upload attempt fails, exercising the retry policy.
+	shipper := &recordingBlockShipper{failures: 1}
+	opts := DefaultOptions()
+	opts.BlockShipper = shipper
+	db := newTestDB(t, withOpts(opts))
+
+	app := db.Appender(context.Background())
+	for ts := int64(0); ts <= 100; ts += 10 {
+		_, err := app.Append(0, labels.FromStrings("foo", "bar"), ts, float64(ts))
+		require.NoError(t, err)
+	}
+	require.NoError(t, app.Commit())
+
+	require.NoError(t, db.CompactHead(NewRangeHead(db.Head(), 0, 100)))
+
+	require.Len(t, shipper.shipped, 1)
+	require.Equal(t, 2, shipper.attempts)
+	require.Len(t, db.Blocks(), 1)
+	meta := db.Blocks()[0].Meta()
+	require.Equal(t, meta.ULID, shipper.shipped[0].ULID)
+	require.Equal(t, filepath.Join(db.Dir(), meta.ULID.String()), shipper.dirs[0])
+	// The shipped directory must still hold the block files.
+	_, err := os.Stat(filepath.Join(shipper.dirs[0], metaFilename))
+	require.NoError(t, err)
+
+	require.Equal(t, 1.0, prom_testutil.ToFloat64(db.metrics.blockShipments))
+	require.Equal(t, 1.0, prom_testutil.ToFloat64(db.metrics.blockShipmentRetries))
+	require.Equal(t, 0.0, prom_testutil.ToFloat64(db.metrics.blockShipmentFailures))
+}
+
+func TestDBBlockShipperGivesUpAfterRetries(t *testing.T) {
+	oldBackoff := blockShipperInitialBackoff
+	blockShipperInitialBackoff = time.Millisecond
+	t.Cleanup(func() { blockShipperInitialBackoff = oldBackoff })
+
+	shipper := &recordingBlockShipper{failures: blockShipperMaxAttempts}
+	opts := DefaultOptions()
+	opts.BlockShipper = shipper
+	db := newTestDB(t, withOpts(opts))
+
+	app := db.Appender(context.Background())
+	_, err := app.Append(0, labels.FromStrings("foo", "bar"), 50, 1)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	// A failed shipment must not fail the compaction itself.
+	require.NoError(t, db.CompactHead(NewRangeHead(db.Head(), 0, 100)))
+
+	require.Empty(t, shipper.shipped)
+	require.Equal(t, blockShipperMaxAttempts, shipper.attempts)
+	require.Len(t, db.Blocks(), 1)
+
+	require.Equal(t, 0.0, prom_testutil.ToFloat64(db.metrics.blockShipments))
+	require.Equal(t, float64(blockShipperMaxAttempts-1), prom_testutil.ToFloat64(db.metrics.blockShipmentRetries))
+	require.Equal(t, 1.0, prom_testutil.ToFloat64(db.metrics.blockShipmentFailures))
+}
@@ -155,6 +155,8 @@ var (
 	NativeHistogramNotCounterWarning        = fmt.Errorf("%w: this native histogram metric is not a counter:", PromQLWarning)
 	NativeHistogramNotGaugeWarning          = fmt.Errorf("%w: this native histogram metric is not a gauge:", PromQLWarning)
 	MixedExponentialCustomHistogramsWarning = fmt.Errorf("%w: vector contains a mix of histograms with exponential and custom buckets schemas for metric name", PromQLWarning)
+	ConvertToNativeHistogramFailedWarning   = fmt.Errorf("%w: cannot convert classic histogram series to a native histogram", PromQLWarning)
+	ConvertToClassicHistogramFailedWarning  = fmt.Errorf("%w: cannot convert a native histogram to classic histogram series", PromQLWarning)
 	IncompatibleBucketLayoutInBinOpWarning  = fmt.Errorf("%w: incompatible bucket layout encountered for binary operator", PromQLWarning)
 	SortInRangeQueryWarning                 = fmt.Errorf("%w: sort is ineffective for range queries since results are always ordered by labels", PromQLWarning)
 
@@ -304,6 +306,25 @@ func NewMixedExponentialCustomHistogramsWarning(metricName string, pos posrange.
 	}
 }
 
+// NewConvertToNativeHistogramFailedWarning is used when convert_to_native_histogram
+// cannot assemble a native histogram from a group of classic histogram series.
+func NewConvertToNativeHistogramFailedWarning(metricName string, err error, pos posrange.PositionRange) error {
+	return &annoErr{
+		PositionRange: pos,
+		Err:           maybeAddMetricName(fmt.Errorf("%w: %s", ConvertToNativeHistogramFailedWarning, err), metricName),
+	}
+}
+
+// NewConvertToClassicHistogramFailedWarning is used when convert_to_classic_histogram
+// cannot decompose a native histogram, e.g. because it does not use a custom
+// buckets schema.
+func NewConvertToClassicHistogramFailedWarning(metricName string, err error, pos posrange.PositionRange) error {
+	return &annoErr{
+		PositionRange: pos,
+		Err:           maybeAddMetricName(fmt.Errorf("%w: %s", ConvertToClassicHistogramFailedWarning, err), metricName),
+	}
+}
+
 // NewPossibleNonCounterInfo is used when a named counter metric with only float samples does not
 // have the suffixes _total, _sum, _count, or _bucket.
 func NewPossibleNonCounterInfo(metricName string, pos posrange.PositionRange) error {
@@ -0,0 +1,159 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stateevents
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// The possible values of the Type field of an Event.
+const (
+	TypeAlert  = "alert"
+	TypeTarget = "target"
+)
+
+// AlertEvent describes a state transition of a single alert instance.
+type AlertEvent struct {
+	RuleName      string        `json:"ruleName"`
+	Labels        labels.Labels `json:"labels"`
+	Annotations   labels.Labels `json:"annotations"`
+	PreviousState string        `json:"previousState"`
+	State         string        `json:"state"`
+	ActiveAt      time.Time     `json:"activeAt"`
+	Value         float64       `json:"value"`
+}
+
+// TargetEvent describes a health transition of a single scrape target.
+type TargetEvent struct {
+	Labels         labels.Labels `json:"labels"`
+	ScrapeURL      string        `json:"scrapeUrl"`
+	PreviousHealth string        `json:"previousHealth"`
+	Health         string        `json:"health"`
+	LastError      string        `json:"lastError,omitempty"`
+}
+
+// Event is a single state change. Exactly one of Alert and Target is set,
+// matching the Type field.
+type Event struct {
+	Type      string       `json:"type"`
+	Timestamp time.Time    `json:"timestamp"`
+	Alert     *AlertEvent  `json:"alert,omitempty"`
+	Target    *TargetEvent `json:"target,omitempty"`
+}
+
+// Broker fans out alert state and target health change events to live
+// subscribers. Unlike notifications, events are not retained: subscribers
+// only see changes that happen while they are subscribed.
+type Broker struct {
+	mu             sync.Mutex
+	subscribers    map[chan Event]struct{} // Active subscribers.
+	maxSubscribers int
+
+	subscriberGauge prometheus.Gauge
+	eventsSent      prometheus.Counter
+	eventsDropped   prometheus.Counter
+}
+
+// NewBroker creates a new Broker instance.
+func NewBroker(maxSubscribers int, reg prometheus.Registerer) *Broker {
+	b := &Broker{
+		subscribers:    make(map[chan Event]struct{}),
+		maxSubscribers: maxSubscribers,
+		subscriberGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "prometheus",
+			Subsystem: "api",
+			Name:      "state_event_active_subscribers",
+			Help:      "The current number of active state event subscribers.",
+		}),
+		eventsSent: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "prometheus",
+			Subsystem: "api",
+			Name:      "state_events_sent_total",
+			Help:      "Total number of state events sent to subscribers.",
+		}),
+		eventsDropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "prometheus",
+			Subsystem: "api",
+			Name:      "state_events_dropped_total",
+			Help:      "Total number of state events dropped because a subscriber was too slow.",
+		}),
+	}
+
+	if reg != nil {
+		reg.MustRegister(b.subscriberGauge, b.eventsSent, b.eventsDropped)
+	}
+
+	return b
+}
+
+// Publish sends the event to all active subscribers. If the event carries no
+// timestamp, the current time is filled in. Publish never blocks: events for
+// subscribers with a full channel are dropped.
+func (b *Broker) Publish(ev Event) {
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		// Non-blocking send to avoid subscriber blocking issues.
+		b.eventsSent.Inc()
+		select {
+		case sub <- ev:
+			// Event sent to the subscriber.
+		default:
+			// Drop the event if the subscriber's channel is full.
+			b.eventsDropped.Inc()
+		}
+	}
+}
+
+// Sub allows a client to subscribe to live state events.
+// It returns a channel where the subscriber will receive events and a
+// function to unsubscribe. It returns false if the maximum number of
+// subscribers has been reached.
+func (b *Broker) Sub() (<-chan Event, func(), bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.subscribers) >= b.maxSubscribers {
+		return nil, nil, false
+	}
+
+	ch := make(chan Event, 64) // Buffered channel to absorb event bursts.
+
+	// Add the new subscriber to the list.
+	b.subscribers[ch] = struct{}{}
+	b.subscriberGauge.Set(float64(len(b.subscribers)))
+
+	// Unsubscribe function to remove the channel from subscribers.
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		// Close the channel and remove it from the subscribers map.
+		close(ch)
+		delete(b.subscribers, ch)
+		b.subscriberGauge.Set(float64(len(b.subscribers)))
+	}
+
+	return ch, unsubscribe, true
+}
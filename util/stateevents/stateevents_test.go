@@ -0,0 +1,102 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stateevents
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// TestSubscriberReceivesEvents tests that a subscriber receives published
+// events of both types, and no events published after unsubscribing.
+func TestSubscriberReceivesEvents(t *testing.T) {
+	broker := NewBroker(10, nil)
+
+	sub, unsubscribe, ok := broker.Sub()
+	require.True(t, ok)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	receivedEvents := make([]Event, 0)
+
+	// Goroutine to listen for events.
+	go func() {
+		defer wg.Done()
+		for event := range sub {
+			receivedEvents = append(receivedEvents, event)
+		}
+	}()
+
+	broker.Publish(Event{
+		Type: TypeAlert,
+		Alert: &AlertEvent{
+			RuleName:      "HighRequestLatency",
+			Labels:        labels.FromStrings("alertname", "HighRequestLatency", "job", "app-server"),
+			PreviousState: "pending",
+			State:         "firing",
+		},
+	})
+	broker.Publish(Event{
+		Type: TypeTarget,
+		Target: &TargetEvent{
+			Labels:         labels.FromStrings("job", "app-server", "instance", "host:9090"),
+			ScrapeURL:      "http://host:9090/metrics",
+			PreviousHealth: "up",
+			Health:         "down",
+			LastError:      "connection refused",
+		},
+	})
+
+	// Wait for events to propagate.
+	time.Sleep(100 * time.Millisecond)
+
+	unsubscribe()
+
+	// Events published after unsubscribing must not be received.
+	broker.Publish(Event{Type: TypeAlert, Alert: &AlertEvent{RuleName: "Late"}})
+
+	wg.Wait() // Wait for the subscriber goroutine to finish.
+
+	require.Len(t, receivedEvents, 2, "Expected 2 events before unsubscribe.")
+	require.Equal(t, TypeAlert, receivedEvents[0].Type)
+	require.Equal(t, "HighRequestLatency", receivedEvents[0].Alert.RuleName)
+	require.False(t, receivedEvents[0].Timestamp.IsZero(), "Expected the publish timestamp to be filled in.")
+	require.Equal(t, TypeTarget, receivedEvents[1].Type)
+	require.Equal(t, "down", receivedEvents[1].Target.Health)
+}
+
+// TestBrokerMaxSubscribers tests that exceeding the max subscribers limit
+// prevents additional subscriptions.
+func TestBrokerMaxSubscribers(t *testing.T) {
+	broker := NewBroker(1, nil)
+
+	_, unsubscribe1, ok1 := broker.Sub()
+	require.True(t, ok1, "Expected first subscription to succeed.")
+
+	_, _, ok2 := broker.Sub()
+	require.False(t, ok2, "Expected second subscription to fail due to max subscriber limit.")
+
+	// Unsubscribe and try again.
+	unsubscribe1()
+
+	_, unsubscribe3, ok3 := broker.Sub()
+	require.True(t, ok3, "Expected subscription to succeed after unsubscribing a subscriber.")
+	unsubscribe3()
+}
@@ -17,6 +17,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel"
@@ -110,10 +112,37 @@ type querySamples struct {
 	PeakSamples                  int        `json:"peakSamples"`
 }
 
+// operationStat holds the accumulated cost of one class of engine operation.
+type operationStat struct {
+	// Operation names the class, e.g. "selector", "join" or "aggregation".
+	Operation string `json:"operation"`
+	// Count is the number of expression nodes evaluated for this class.
+	Count int64 `json:"count"`
+	// Time is the self time spent in this class in seconds, excluding time
+	// spent evaluating child expressions.
+	Time float64 `json:"time"`
+	// Samples is the number of samples this class loaded itself.
+	Samples int64 `json:"samples"`
+}
+
+// subexpressionsStat reports the savings of common subexpression elimination
+// within a single query.
+type subexpressionsStat struct {
+	// ReusedEvaluations is the number of expression evaluations that were
+	// served from the result of an identical subexpression evaluated earlier
+	// in the same query.
+	ReusedEvaluations int64 `json:"reusedEvaluations"`
+	// ReusedSamples is the number of samples contained in the reused results,
+	// i.e. samples that did not have to be computed a second time.
+	ReusedSamples int64 `json:"reusedSamples"`
+}
+
 // BuiltinStats holds the statistics that Prometheus's core gathers.
 type BuiltinStats struct {
-	Timings queryTimings  `json:"timings,omitempty"`
-	Samples *querySamples `json:"samples,omitempty"`
+	Timings        queryTimings        `json:"timings,omitempty"`
+	Samples        *querySamples       `json:"samples,omitempty"`
+	Operations     []operationStat     `json:"operations,omitempty"`
+	Subexpressions *subexpressionsStat `json:"subexpressions,omitempty"`
 }
 
 // QueryStats holds BuiltinStats and any other stats the particular
@@ -163,7 +192,13 @@ func NewQueryStats(s *Statistics) QueryStats {
 		samples.SamplesReadPerStep = sp.samplesReadPerStepPoints()
 	}
 
-	qs := BuiltinStats{Timings: qt, Samples: samples}
+	qs := BuiltinStats{Timings: qt, Samples: samples, Operations: s.Operations.Stats()}
+	if se := s.Subexpressions; se != nil && se.ReusedEvaluations > 0 {
+		qs.Subexpressions = &subexpressionsStat{
+			ReusedEvaluations: se.ReusedEvaluations,
+			ReusedSamples:     se.ReusedSamples,
+		}
+	}
 	return &qs
 }
 
@@ -247,8 +282,133 @@ func (s *SpanTimer) Finish() {
 }
 
 type Statistics struct {
-	Timers  *QueryTimers
-	Samples *QuerySamples
+	Timers         *QueryTimers
+	Samples        *QuerySamples
+	Operations     *QueryOperations
+	Subexpressions *QuerySubexpressions
+}
+
+// QuerySubexpressions tracks how often expression evaluations were served
+// from the result of an identical subexpression evaluated earlier in the
+// same query (common subexpression elimination).
+type QuerySubexpressions struct {
+	// ReusedEvaluations is the number of evaluations served from cache.
+	ReusedEvaluations int64
+	// ReusedSamples is the number of samples contained in the reused
+	// results, i.e. samples that did not have to be computed a second time.
+	ReusedSamples int64
+}
+
+// NewQuerySubexpressions returns an empty subexpression reuse collector.
+func NewQuerySubexpressions() *QuerySubexpressions {
+	return &QuerySubexpressions{}
+}
+
+// AddReuse records one reused subexpression evaluation whose result held the
+// given number of samples.
+func (qs *QuerySubexpressions) AddReuse(samples int64) {
+	if qs == nil {
+		return
+	}
+	qs.ReusedEvaluations++
+	qs.ReusedSamples += samples
+}
+
+// QueryOperations attributes evaluation time and loaded samples to the class
+// of operation that caused them (selector fetch, join, aggregation, ...).
+// Expression evaluation is strictly nested and single-threaded, so a stack of
+// open frames suffices. Time and samples are exclusive: a frame only keeps
+// what its child frames did not already claim.
+type QueryOperations struct {
+	stack []operationFrame
+	ops   map[string]*operationStat
+}
+
+// operationFrame is one open evaluation of an expression node.
+type operationFrame struct {
+	operation    string
+	start        time.Time
+	counter      *QuerySamples
+	startSamples int64
+	childTime    time.Duration
+	childSamples int64
+}
+
+// NewQueryOperations returns an empty per-operation statistics collector.
+func NewQueryOperations() *QueryOperations {
+	return &QueryOperations{ops: map[string]*operationStat{}}
+}
+
+// StartOperation opens a frame for the given operation class. The counter is
+// the sample counter of the evaluator running the frame; samples it counts
+// while the frame is open are attributed to the frame. The returned function
+// closes the frame and must be called exactly once.
+func (qo *QueryOperations) StartOperation(operation string, counter *QuerySamples) func() {
+	if qo == nil {
+		return func() {}
+	}
+	var startSamples int64
+	if counter != nil {
+		startSamples = counter.TotalSamples
+	}
+	qo.stack = append(qo.stack, operationFrame{
+		operation:    operation,
+		start:        time.Now(),
+		counter:      counter,
+		startSamples: startSamples,
+	})
+	return qo.finish
+}
+
+func (qo *QueryOperations) finish() {
+	f := qo.stack[len(qo.stack)-1]
+	qo.stack = qo.stack[:len(qo.stack)-1]
+
+	elapsed := time.Since(f.start)
+	var inclusive int64
+	if f.counter != nil {
+		inclusive = f.counter.TotalSamples - f.startSamples
+	}
+
+	st := qo.ops[f.operation]
+	if st == nil {
+		st = &operationStat{Operation: f.operation}
+		qo.ops[f.operation] = st
+	}
+	st.Count++
+	st.Time += (elapsed - f.childTime).Seconds()
+	st.Samples += inclusive - f.childSamples
+
+	if len(qo.stack) == 0 {
+		return
+	}
+	parent := &qo.stack[len(qo.stack)-1]
+	parent.childTime += elapsed
+	// Subquery evaluation runs on its own sample counter; those samples are
+	// claimed there and must not be subtracted from a parent that counts on a
+	// different counter.
+	if parent.counter == f.counter {
+		parent.childSamples += inclusive
+	}
+}
+
+// Stats returns the collected per-operation statistics ordered by descending
+// self time, or nil if nothing was collected.
+func (qo *QueryOperations) Stats() []operationStat {
+	if qo == nil || len(qo.ops) == 0 {
+		return nil
+	}
+	ops := make([]operationStat, 0, len(qo.ops))
+	for _, st := range qo.ops {
+		ops = append(ops, *st)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Time != ops[j].Time {
+			return ops[i].Time > ops[j].Time
+		}
+		return ops[i].Operation < ops[j].Operation
+	})
+	return ops
 }
 
 type QueryTimers struct {
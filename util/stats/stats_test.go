@@ -68,6 +68,74 @@ func TestQueryStatsWithTimersAndSamples(t *testing.T) {
 	require.Regexpf(t, `[,{]"samplesReadPerStep":\[\[20001,5\],\[21001,0\],\[22001,0\],\[23001,0\],\[24001,0\],\[25001,5\]\]`, string(actual), "expected samplesReadPerStep")
 }
 
+func TestQueryOperations(t *testing.T) {
+	qo := NewQueryOperations()
+	counter := &QuerySamples{}
+
+	// join
+	// ├── selector (loads 4 samples)
+	// └── selector (loads 6 samples)
+	finishJoin := qo.StartOperation("join", counter)
+	finishSel := qo.StartOperation("selector", counter)
+	counter.TotalSamples += 4
+	finishSel()
+	finishSel = qo.StartOperation("selector", counter)
+	counter.TotalSamples += 6
+	finishSel()
+	finishJoin()
+
+	// A subquery runs on its own sample counter; its samples must stay
+	// attributed to the frames using that counter.
+	childCounter := &QuerySamples{}
+	finishSubq := qo.StartOperation("subquery", counter)
+	finishSel = qo.StartOperation("selector", childCounter)
+	childCounter.TotalSamples += 5
+	finishSel()
+	finishSubq()
+
+	ops := qo.Stats()
+	require.Len(t, ops, 3)
+
+	byOp := map[string]operationStat{}
+	for _, op := range ops {
+		byOp[op.Operation] = op
+	}
+	require.Equal(t, int64(3), byOp["selector"].Count)
+	require.Equal(t, int64(15), byOp["selector"].Samples)
+	require.Equal(t, int64(1), byOp["join"].Count)
+	require.Equal(t, int64(0), byOp["join"].Samples)
+	require.Equal(t, int64(1), byOp["subquery"].Count)
+	require.Equal(t, int64(0), byOp["subquery"].Samples)
+	for _, op := range ops {
+		require.GreaterOrEqual(t, op.Time, 0.0, "self time of %q must not be negative", op.Operation)
+	}
+
+	qstats := NewQueryStats(&Statistics{Timers: NewQueryTimers(), Samples: counter, Operations: qo})
+	actual, err := json.Marshal(qstats)
+	require.NoError(t, err, "unexpected error during serialization")
+	require.Regexpf(t, `"operations":\[`, string(actual), "expected operations breakdown")
+	require.Regexpf(t, `\{"operation":"selector","count":3,"time":\d+(\.\d+)?(e-\d+)?,"samples":15\}`, string(actual), "expected selector operation entry")
+}
+
+func TestQuerySubexpressions(t *testing.T) {
+	qs := NewQuerySubexpressions()
+	qs.AddReuse(4)
+	qs.AddReuse(6)
+	require.Equal(t, int64(2), qs.ReusedEvaluations)
+	require.Equal(t, int64(10), qs.ReusedSamples)
+
+	qstats := NewQueryStats(&Statistics{Timers: NewQueryTimers(), Subexpressions: qs})
+	actual, err := json.Marshal(qstats)
+	require.NoError(t, err, "unexpected error during serialization")
+	require.Regexpf(t, `"subexpressions":\{"reusedEvaluations":2,"reusedSamples":10\}`, string(actual), "expected subexpressions block")
+
+	// Without any reuse, the block is omitted from the output.
+	qstats = NewQueryStats(&Statistics{Timers: NewQueryTimers(), Subexpressions: NewQuerySubexpressions()})
+	actual, err = json.Marshal(qstats)
+	require.NoError(t, err, "unexpected error during serialization")
+	require.NotContains(t, string(actual), "subexpressions")
+}
+
 func TestQueryStatsWithSpanTimers(t *testing.T) {
 	qt := NewQueryTimers()
 	qs := NewQuerySamples(false)
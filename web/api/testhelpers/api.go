@@ -17,12 +17,14 @@ package testhelpers
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"testing"
 	"time"
 
+	"github.com/oklog/ulid/v2"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/promslog"
 
@@ -49,6 +51,8 @@ type TargetRetriever interface {
 	TargetsDropped() map[string][]*scrape.Target
 	TargetsDroppedCounts() map[string]int
 	ScrapePoolConfig(string) (*config.ScrapeConfig, error)
+	OverrideTargets(pool string, matchers []*labels.Matcher, override *scrape.TargetOverride) (int, error)
+	MarkTargetsStale(pool string, matchers []*labels.Matcher) (int, error)
 }
 
 // ScrapePoolsRetriever provide the list of all scrape pools.
@@ -67,9 +71,14 @@ type TSDBAdminStats interface {
 	CleanTombstones() error
 	Delete(ctx context.Context, mint, maxt int64, ms ...*labels.Matcher) error
 	Snapshot(dir string, withHead bool) error
+	SnapshotIncremental(dir, baseDir string, withHead bool) error
 	Stats(statsByLabelName string, limit int) (*tsdb.Stats, error)
 	WALReplayStatus() (tsdb.WALReplayStatus, error)
 	BlockMetas() ([]tsdb.BlockMeta, error)
+	BlockQueryStats() ([]tsdb.BlockQueryStat, error)
+	DeleteAuditLog() ([]tsdb.DeleteAuditEntry, error)
+	ImportBlock(r io.Reader) (ulid.ULID, error)
+	ExportBlock(ctx context.Context, w io.Writer, mint, maxt int64, matcherSets ...[]*labels.Matcher) (ulid.ULID, error)
 }
 
 // APIConfig holds configuration for creating a test API instance.
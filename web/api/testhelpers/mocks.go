@@ -16,8 +16,11 @@ package testhelpers
 
 import (
 	"context"
+	"io"
 	"net/url"
 
+	"github.com/oklog/ulid/v2"
+
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/histogram"
@@ -415,6 +418,14 @@ func (f *FakeTargetRetriever) TargetsDroppedCounts() map[string]int {
 	return f.droppedCounts
 }
 
+func (f *FakeTargetRetriever) OverrideTargets(string, []*labels.Matcher, *scrape.TargetOverride) (int, error) {
+	return 0, nil
+}
+
+func (f *FakeTargetRetriever) MarkTargetsStale(string, []*labels.Matcher) (int, error) {
+	return 0, nil
+}
+
 func (f *FakeTargetRetriever) ScrapePoolConfig(name string) (*config.ScrapeConfig, error) {
 	if f.scrapeConfig == nil {
 		return nil, nil
@@ -460,6 +471,10 @@ func (*FakeTSDBAdminStats) Snapshot(_ string, _ bool) error {
 	return nil
 }
 
+func (*FakeTSDBAdminStats) SnapshotIncremental(_, _ string, _ bool) error {
+	return nil
+}
+
 func (*FakeTSDBAdminStats) Stats(_ string, _ int) (*tsdb.Stats, error) {
 	return &tsdb.Stats{}, nil
 }
@@ -472,6 +487,22 @@ func (*FakeTSDBAdminStats) BlockMetas() ([]tsdb.BlockMeta, error) {
 	return []tsdb.BlockMeta{}, nil
 }
 
+func (*FakeTSDBAdminStats) BlockQueryStats() ([]tsdb.BlockQueryStat, error) {
+	return []tsdb.BlockQueryStat{}, nil
+}
+
+func (*FakeTSDBAdminStats) DeleteAuditLog() ([]tsdb.DeleteAuditEntry, error) {
+	return []tsdb.DeleteAuditEntry{}, nil
+}
+
+func (*FakeTSDBAdminStats) ImportBlock(io.Reader) (ulid.ULID, error) {
+	return ulid.ULID{}, nil
+}
+
+func (*FakeTSDBAdminStats) ExportBlock(context.Context, io.Writer, int64, int64, ...[]*labels.Matcher) (ulid.ULID, error) {
+	return ulid.ULID{}, nil
+}
+
 // NewEmptyQueryable returns a queryable with no series.
 func NewEmptyQueryable() storage.SampleAndChunkQueryable {
 	return &FakeQueryable{series: []storage.Series{}}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"math"
 	"math/rand"
@@ -37,6 +38,7 @@ import (
 	"github.com/grafana/regexp"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/munnerz/goautoneg"
+	"github.com/oklog/ulid/v2"
 	remoteapi "github.com/prometheus/client_golang/exp/api/remote"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
@@ -44,6 +46,7 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/metadata"
 	"github.com/prometheus/prometheus/model/relabel"
@@ -60,6 +63,7 @@ import (
 	"github.com/prometheus/prometheus/util/features"
 	"github.com/prometheus/prometheus/util/httputil"
 	"github.com/prometheus/prometheus/util/notifications"
+	"github.com/prometheus/prometheus/util/stateevents"
 	"github.com/prometheus/prometheus/util/stats"
 )
 
@@ -134,6 +138,8 @@ type TargetRetriever interface {
 	TargetsDropped() map[string][]*scrape.Target
 	TargetsDroppedCounts() map[string]int
 	ScrapePoolConfig(string) (*config.ScrapeConfig, error)
+	OverrideTargets(pool string, matchers []*labels.Matcher, override *scrape.TargetOverride) (int, error)
+	MarkTargetsStale(pool string, matchers []*labels.Matcher) (int, error)
 }
 
 // AlertmanagerRetriever provides a list of all/dropped AlertManager URLs.
@@ -215,9 +221,14 @@ type TSDBAdminStats interface {
 	CleanTombstones() error
 	Delete(ctx context.Context, mint, maxt int64, ms ...*labels.Matcher) error
 	Snapshot(dir string, withHead bool) error
+	SnapshotIncremental(dir, baseDir string, withHead bool) error
 	Stats(statsByLabelName string, limit int) (*tsdb.Stats, error)
 	WALReplayStatus() (tsdb.WALReplayStatus, error)
 	BlockMetas() ([]tsdb.BlockMeta, error)
+	BlockQueryStats() ([]tsdb.BlockQueryStat, error)
+	DeleteAuditLog() ([]tsdb.DeleteAuditEntry, error)
+	ImportBlock(r io.Reader) (ulid.ULID, error)
+	ExportBlock(ctx context.Context, w io.Writer, mint, maxt int64, matcherSets ...[]*labels.Matcher) (ulid.ULID, error)
 }
 
 type QueryOpts interface {
@@ -238,6 +249,7 @@ type API struct {
 	rulesRetriever        func(context.Context) RulesRetriever
 	now                   func() time.Time
 	config                func() config.Config
+	previousConfig        func() string
 	flagsMap              map[string]string
 	ready                 func(http.HandlerFunc) http.HandlerFunc
 	globalURLOptions      GlobalURLOptions
@@ -254,16 +266,20 @@ type API struct {
 	runtimeInfo         func() (RuntimeInfo, error)
 	gatherer            prometheus.Gatherer
 	isAgent             bool
+	agentLocalQuery     bool
 	statsRenderer       StatsRenderer
 	customStatsRenderer bool // See validateStatsParam: a custom StatsRenderer's `stats` vocabulary is not validated.
 	notificationsGetter func() []notifications.Notification
 	notificationsSub    func() (<-chan notifications.Notification, func(), bool)
+	stateEventsSub      func() (<-chan stateevents.Event, func(), bool)
+	sdUpdateHistory     func() map[string][]discovery.UpdateDiff
 	// Allows customizing the default mapping
 	overrideErrorCode OverrideErrorCode
 
 	remoteWriteHandler http.Handler
 	remoteReadHandler  http.Handler
 	otlpWriteHandler   http.Handler
+	omPushHandler      http.Handler
 
 	codecs []Codec
 
@@ -283,6 +299,7 @@ func NewAPI(
 	tr func(context.Context) TargetRetriever,
 	ar func(context.Context) AlertmanagerRetriever,
 	configFunc func() config.Config,
+	previousConfigFunc func() string,
 	flagsMap map[string]string,
 	globalURLOptions GlobalURLOptions,
 	readyFunc func(http.HandlerFunc) http.HandlerFunc,
@@ -293,21 +310,31 @@ func NewAPI(
 	maxSearchLimit int,
 	logger *slog.Logger,
 	rr func(context.Context) RulesRetriever,
+	sdUpdateHistory func() map[string][]discovery.UpdateDiff,
 	remoteReadSampleLimit int,
 	remoteReadConcurrencyLimit int,
 	remoteReadMaxBytesInFrame int,
+	remoteReadMaxConcurrentStreams int,
+	remoteReadMaxFrameBacklog int,
+	remoteReadIdleWriteTimeout time.Duration,
 	isAgent bool,
+	agentLocalQuery bool,
 	corsOrigin *regexp.Regexp,
 	runtimeInfo func() (RuntimeInfo, error),
 	buildInfo *PrometheusVersion,
 	notificationsGetter func() []notifications.Notification,
 	notificationsSub func() (<-chan notifications.Notification, func(), bool),
+	stateEventsSub func() (<-chan stateevents.Event, func(), bool),
 	gatherer prometheus.Gatherer,
 	registerer prometheus.Registerer,
 	statsRenderer StatsRenderer,
 	rwEnabled bool,
 	acceptRemoteWriteProtoMsgs remoteapi.MessageTypes,
-	otlpEnabled, otlpDeltaToCumulative, otlpNativeDeltaIngestion bool,
+	otlpEnabled, otlpDeltaToCumulative bool,
+	otlpDeltaMaxStale time.Duration,
+	otlpDeltaMaxStreams int,
+	otlpNativeDeltaIngestion bool,
+	omPushEnabled bool,
 	stZeroIngestionEnabled bool,
 	lookbackDelta time.Duration,
 	enableTypeAndUnitLabels bool,
@@ -328,6 +355,7 @@ func NewAPI(
 
 		now:                 time.Now,
 		config:              configFunc,
+		previousConfig:      previousConfigFunc,
 		flagsMap:            flagsMap,
 		ready:               readyFunc,
 		globalURLOptions:    globalURLOptions,
@@ -338,21 +366,24 @@ func NewAPI(
 		maxSearchLimit:      maxSearchLimit,
 		metaCache:           &searchMetadataCache{},
 		rulesRetriever:      rr,
+		sdUpdateHistory:     sdUpdateHistory,
 		logger:              logger,
 		CORSOrigin:          corsOrigin,
 		runtimeInfo:         runtimeInfo,
 		buildInfo:           buildInfo,
 		gatherer:            gatherer,
 		isAgent:             isAgent,
+		agentLocalQuery:     agentLocalQuery,
 		statsRenderer:       DefaultStatsRenderer,
 		notificationsGetter: notificationsGetter,
 		notificationsSub:    notificationsSub,
+		stateEventsSub:      stateEventsSub,
 		overrideErrorCode:   overrideErrorCode,
 		featureRegistry:     featureRegistry,
 		openAPIBuilder:      NewOpenAPIBuilder(openAPIOptions, logger),
 		parser:              promqlParser,
 
-		remoteReadHandler: remote.NewReadHandler(logger, registerer, q, configFunc, remoteReadSampleLimit, remoteReadConcurrencyLimit, remoteReadMaxBytesInFrame),
+		remoteReadHandler: remote.NewReadHandler(logger, registerer, q, configFunc, remoteReadSampleLimit, remoteReadConcurrencyLimit, remoteReadMaxBytesInFrame, remoteReadMaxConcurrentStreams, remoteReadMaxFrameBacklog, remoteReadIdleWriteTimeout),
 	}
 
 	if a.parser == nil {
@@ -366,8 +397,8 @@ func NewAPI(
 		a.customStatsRenderer = true
 	}
 
-	if (ap == nil || apV2 == nil) && (rwEnabled || otlpEnabled) {
-		panic("remote write or otlp write enabled, but no appender passed in.")
+	if (ap == nil || apV2 == nil) && (rwEnabled || otlpEnabled || omPushEnabled) {
+		panic("remote write, otlp write or OpenMetrics push enabled, but no appender passed in.")
 	}
 
 	if rwEnabled {
@@ -376,11 +407,16 @@ func NewAPI(
 	if otlpEnabled {
 		a.otlpWriteHandler = remote.NewOTLPWriteHandler(logger, registerer, apV2, configFunc, remote.OTLPOptions{
 			ConvertDelta:            otlpDeltaToCumulative,
+			ConvertDeltaMaxStale:    otlpDeltaMaxStale,
+			ConvertDeltaMaxStreams:  otlpDeltaMaxStreams,
 			NativeDelta:             otlpNativeDeltaIngestion,
 			LookbackDelta:           lookbackDelta,
 			EnableTypeAndUnitLabels: enableTypeAndUnitLabels,
 		})
 	}
+	if omPushEnabled {
+		a.omPushHandler = remote.NewOpenMetricsPushHandler(logger, registerer, ap)
+	}
 
 	return a
 }
@@ -438,27 +474,38 @@ func (api *API) Register(r *route.Router) {
 		})
 	}
 
+	// wrapAgentQuery is like wrapAgent, but additionally allows the endpoint
+	// in Agent mode when local querying is enabled.
+	wrapAgentQuery := func(f apiFunc) http.HandlerFunc {
+		return wrap(func(r *http.Request) apiFuncResult {
+			if api.isAgent && !api.agentLocalQuery {
+				return apiFuncResult{nil, &apiError{errorExec, errors.New("unavailable with Prometheus Agent")}, nil, nil}
+			}
+			return f(r)
+		})
+	}
+
 	r.Options("/*path", wrap(api.options))
 
-	r.Get("/query", wrapAgent(api.query))
-	r.Post("/query", wrapAgent(api.query))
-	r.Get("/query_range", wrapAgent(api.queryRange))
-	r.Post("/query_range", wrapAgent(api.queryRange))
+	r.Get("/query", wrapAgentQuery(api.query))
+	r.Post("/query", wrapAgentQuery(api.query))
+	r.Get("/query_range", wrapAgentQuery(api.queryRange))
+	r.Post("/query_range", wrapAgentQuery(api.queryRange))
 	r.Get("/query_exemplars", wrapAgent(api.queryExemplars))
 	r.Post("/query_exemplars", wrapAgent(api.queryExemplars))
 
-	r.Get("/format_query", wrapAgent(api.formatQuery))
-	r.Post("/format_query", wrapAgent(api.formatQuery))
+	r.Get("/format_query", wrapAgentQuery(api.formatQuery))
+	r.Post("/format_query", wrapAgentQuery(api.formatQuery))
 
-	r.Get("/parse_query", wrapAgent(api.parseQuery))
-	r.Post("/parse_query", wrapAgent(api.parseQuery))
+	r.Get("/parse_query", wrapAgentQuery(api.parseQuery))
+	r.Post("/parse_query", wrapAgentQuery(api.parseQuery))
 
-	r.Get("/labels", wrapAgent(api.labelNames))
-	r.Post("/labels", wrapAgent(api.labelNames))
-	r.Get("/label/:name/values", wrapAgent(api.labelValues))
+	r.Get("/labels", wrapAgentQuery(api.labelNames))
+	r.Post("/labels", wrapAgentQuery(api.labelNames))
+	r.Get("/label/:name/values", wrapAgentQuery(api.labelValues))
 
-	r.Get("/series", wrapAgent(api.series))
-	r.Post("/series", wrapAgent(api.series))
+	r.Get("/series", wrapAgentQuery(api.series))
+	r.Post("/series", wrapAgentQuery(api.series))
 
 	r.Get("/scrape_pools", wrap(api.scrapePools))
 	r.Get("/targets", wrap(api.targets))
@@ -474,14 +521,18 @@ func (api *API) Register(r *route.Router) {
 	r.Get("/status/flags", wrap(api.serveFlags))
 	r.Get("/status/tsdb", wrapAgent(api.serveTSDBStatus))
 	r.Get("/status/tsdb/blocks", wrapAgent(api.serveTSDBBlocks))
+	r.Get("/status/tsdb/block_query_stats", wrapAgent(api.serveTSDBBlockQueryStats))
 	r.Get("/status/self_metrics", wrap(api.selfMetrics))
+	r.Get("/status/sd_updates", wrap(api.serveSDUpdates))
 	r.Get("/features", wrap(api.features))
 	r.Get("/status/walreplay", api.serveWALReplayStatus)
 	r.Get("/notifications", api.notifications)
 	r.Get("/notifications/live", api.notificationsSSE)
+	r.Get("/state_events", api.stateEvents)
 	r.Post("/read", api.ready(api.remoteRead))
 	r.Post("/write", api.ready(api.remoteWrite))
 	r.Post("/otlp/v1/metrics", api.ready(api.otlpWrite))
+	r.Post("/push/metrics/*labels", api.ready(api.openMetricsPush))
 
 	// Search endpoints.
 	r.Get("/search/metric_names", api.ready(api.searchMetricNames))
@@ -498,11 +549,24 @@ func (api *API) Register(r *route.Router) {
 	r.Post("/admin/tsdb/delete_series", wrapAgent(api.deleteSeries))
 	r.Post("/admin/tsdb/clean_tombstones", wrapAgent(api.cleanTombstones))
 	r.Post("/admin/tsdb/snapshot", wrapAgent(api.snapshot))
+	r.Post("/admin/tsdb/import_block", wrapAgent(api.importBlock))
+	r.Get("/admin/tsdb/export", api.ready(api.exportBlock))
+	r.Post("/admin/tsdb/export", api.ready(api.exportBlock))
 
 	r.Put("/admin/tsdb/delete_series", wrapAgent(api.deleteSeries))
 	r.Put("/admin/tsdb/clean_tombstones", wrapAgent(api.cleanTombstones))
 	r.Put("/admin/tsdb/snapshot", wrapAgent(api.snapshot))
 
+	r.Get("/admin/tsdb/delete_audit", wrapAgent(api.deleteAuditLog))
+
+	r.Post("/admin/scrape/override_targets", wrap(api.overrideScrapeTargets))
+	r.Put("/admin/scrape/override_targets", wrap(api.overrideScrapeTargets))
+	r.Post("/admin/scrape/mark_targets_stale", wrap(api.markScrapeTargetsStale))
+	r.Put("/admin/scrape/mark_targets_stale", wrap(api.markScrapeTargetsStale))
+
+	r.Post("/admin/remote_write/test", wrap(api.remoteWriteTest))
+	r.Put("/admin/remote_write/test", wrap(api.remoteWriteTest))
+
 	// OpenAPI endpoint.
 	r.Get("/openapi.yaml", api.ready(api.openAPIBuilder.ServeOpenAPI))
 }
@@ -595,15 +659,116 @@ func (api *API) query(r *http.Request) (result apiFuncResult) {
 	}, nil, warnings, qry.Close}
 }
 
+// maxEstimatedSeries caps the number of series counted when estimating the
+// cardinality of a query, so that analyzing a very broad query does not scan
+// the whole index.
+const maxEstimatedSeries = 100000
+
+// QueryAnalysis holds the static analysis results returned by the
+// format_query endpoint when the analyze parameter is set.
+type QueryAnalysis struct {
+	// Selectors is the number of vector selectors in the query.
+	Selectors int `json:"selectors"`
+	// EstimatedSeries is the number of series currently in storage matched by
+	// the query's selectors, summed over all selectors. It is an upper bound
+	// on the number of series the query touches at this moment.
+	EstimatedSeries uint64 `json:"estimatedSeries"`
+	// Warnings lists potentially problematic or deprecated constructs found
+	// in the query.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// FormattedQuery is the format_query response payload when the analyze
+// parameter is set.
+type FormattedQuery struct {
+	Formatted string         `json:"formatted"`
+	Analysis  *QueryAnalysis `json:"analysis"`
+}
+
 func (api *API) formatQuery(r *http.Request) (result apiFuncResult) {
 	expr, err := api.parser.ParseExpr(r.FormValue("query"))
 	if err != nil {
 		return invalidParamError(err, "query")
 	}
 
+	if v := r.FormValue("analyze"); v != "" {
+		analyze, err := strconv.ParseBool(v)
+		if err != nil {
+			return invalidParamError(err, "analyze")
+		}
+		if analyze {
+			return apiFuncResult{&FormattedQuery{
+				Formatted: expr.Pretty(0),
+				Analysis:  api.analyzeQuery(r.Context(), expr),
+			}, nil, nil, nil}
+		}
+	}
+
 	return apiFuncResult{expr.Pretty(0), nil, nil, nil}
 }
 
+// analyzeQuery statically analyzes the given query expression and estimates
+// its cardinality against the current storage contents.
+func (api *API) analyzeQuery(ctx context.Context, expr parser.Expr) *QueryAnalysis {
+	a := &QueryAnalysis{}
+	var selectors []*parser.VectorSelector
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		switch n := node.(type) {
+		case *parser.VectorSelector:
+			a.Selectors++
+			selectors = append(selectors, n)
+			hasName := false
+			for _, m := range n.LabelMatchers {
+				if m.Name == labels.MetricName && m.Type == labels.MatchEqual {
+					hasName = true
+				}
+				if (m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp) && m.Value == ".*" {
+					a.Warnings = append(a.Warnings, fmt.Sprintf("matcher %s matches all values of label %q", m, m.Name))
+				}
+			}
+			if !hasName {
+				a.Warnings = append(a.Warnings, fmt.Sprintf("selector %s has no metric name and may match many series", n))
+			}
+		case *parser.Call:
+			if n.Func.Experimental {
+				a.Warnings = append(a.Warnings, fmt.Sprintf("function %q is experimental and may change or be removed in a future release", n.Func.Name))
+			}
+		}
+		return nil
+	})
+
+	// Estimate the cardinality by counting the series each selector matches
+	// over the last hour. This is best-effort: if storage cannot be queried
+	// (e.g. in agent mode), the estimate is reported as a warning instead.
+	now := api.now()
+	q, err := api.Queryable.Querier(timestamp.FromTime(now.Add(-time.Hour)), timestamp.FromTime(now))
+	if err != nil {
+		a.Warnings = append(a.Warnings, "could not estimate cardinality: "+err.Error())
+		return a
+	}
+	defer q.Close()
+	// Issue all selects up front: queriers backed by remote read require
+	// every Select call before any returned set is iterated.
+	ssets := make([]storage.SeriesSet, 0, len(selectors))
+	for _, vs := range selectors {
+		ssets = append(ssets, q.Select(ctx, false, nil, vs.LabelMatchers...))
+	}
+	for _, sset := range ssets {
+		for sset.Next() {
+			a.EstimatedSeries++
+			if a.EstimatedSeries >= maxEstimatedSeries {
+				a.Warnings = append(a.Warnings, fmt.Sprintf("cardinality estimate truncated at %d series", maxEstimatedSeries))
+				return a
+			}
+		}
+		if err := sset.Err(); err != nil {
+			a.Warnings = append(a.Warnings, "could not estimate cardinality: "+err.Error())
+			return a
+		}
+	}
+	return a
+}
+
 func (api *API) parseQuery(r *http.Request) apiFuncResult {
 	expr, err := api.parser.ParseExpr(r.FormValue("query"))
 	if err != nil {
@@ -1117,6 +1282,9 @@ type Target struct {
 	ScrapePool string `json:"scrapePool"`
 	ScrapeURL  string `json:"scrapeUrl"`
 	GlobalURL  string `json:"globalUrl"`
+	// The remote address of the connection used by the most recent scrape,
+	// empty if the target was never reached.
+	ResolvedAddress string `json:"resolvedAddress,omitempty"`
 
 	LastError          string              `json:"lastError"`
 	LastScrape         time.Time           `json:"lastScrape"`
@@ -1125,6 +1293,10 @@ type Target struct {
 
 	ScrapeInterval string `json:"scrapeInterval"`
 	ScrapeTimeout  string `json:"scrapeTimeout"`
+	// The scrape interval currently in effect for the target. It differs from
+	// ScrapeInterval while scrape failure backoff is active. Empty until the
+	// target's scrape loop has started.
+	EffectiveScrapeInterval string `json:"effectiveScrapeInterval,omitempty"`
 }
 
 type ScrapePoolsDiscovery struct {
@@ -1262,6 +1434,7 @@ func (api *API) targets(r *http.Request) apiFuncResult {
 					ScrapePool:       pool,
 					ScrapeURL:        target.URL().String(),
 					GlobalURL:        globalURL.String(),
+					ResolvedAddress:  target.ResolvedAddress(),
 					LastError: func() string {
 						switch {
 						case err == nil && lastErrStr == "":
@@ -1277,6 +1450,12 @@ func (api *API) targets(r *http.Request) apiFuncResult {
 					Health:             target.Health(),
 					ScrapeInterval:     target.GetValue(model.ScrapeIntervalLabel),
 					ScrapeTimeout:      target.GetValue(model.ScrapeTimeoutLabel),
+					EffectiveScrapeInterval: func() string {
+						if d := target.EffectiveInterval(); d > 0 {
+							return d.String()
+						}
+						return ""
+					}(),
 				})
 			}
 		}
@@ -1614,6 +1793,22 @@ type AlertingRule struct {
 	LastError      string           `json:"lastError,omitempty"`
 	EvaluationTime float64          `json:"evaluationTime"`
 	LastEvaluation time.Time        `json:"lastEvaluation"`
+	// EvaluationStats holds the resource usage of the last successful
+	// evaluation of the rule.
+	EvaluationStats rules.EvaluationStats `json:"evaluationStats"`
+	// NotificationsSent is the number of alert notifications sent for this
+	// rule, including resolved notifications and resends.
+	NotificationsSent int64 `json:"notificationsSent"`
+	// Flaps is the number of times an alert of this rule started firing again
+	// after having resolved shortly before.
+	Flaps int64 `json:"flaps"`
+	// FiringTime is the cumulative time alerts of this rule have spent in the
+	// firing state, in seconds.
+	FiringTime float64 `json:"firingTime"`
+	// LastSamples is a preview of the result samples of the last successful
+	// evaluation. It is only populated when the preview_samples query
+	// parameter is set.
+	LastSamples []promql.Sample `json:"lastSamples,omitempty"`
 	// Type of an alertingRule is always "alerting".
 	Type string `json:"type"`
 }
@@ -1626,6 +1821,13 @@ type RecordingRule struct {
 	LastError      string           `json:"lastError,omitempty"`
 	EvaluationTime float64          `json:"evaluationTime"`
 	LastEvaluation time.Time        `json:"lastEvaluation"`
+	// EvaluationStats holds the resource usage of the last successful
+	// evaluation of the rule.
+	EvaluationStats rules.EvaluationStats `json:"evaluationStats"`
+	// LastSamples is a preview of the result samples of the last successful
+	// evaluation. It is only populated when the preview_samples query
+	// parameter is set.
+	LastSamples []promql.Sample `json:"lastSamples,omitempty"`
 	// Type of a recordingRule is always "recording".
 	Type string `json:"type"`
 }
@@ -1668,6 +1870,11 @@ func (api *API) rules(r *http.Request) apiFuncResult {
 		return invalidParamError(err, "exclude_alerts")
 	}
 
+	previewSamples, err := parsePreviewSamples(r)
+	if err != nil {
+		return invalidParamError(err, "preview_samples")
+	}
+
 	maxGroups, nextToken, parseErr := parseListRulesPaginationRequest(r)
 	if parseErr != nil {
 		return *parseErr
@@ -1720,6 +1927,13 @@ func (api *API) rules(r *http.Request) apiFuncResult {
 			if rr.LastError() != nil {
 				lastError = rr.LastError().Error()
 			}
+			var lastSamples []promql.Sample
+			if previewSamples > 0 {
+				lastSamples = rr.GetLastSamples()
+				if len(lastSamples) > previewSamples {
+					lastSamples = lastSamples[:previewSamples]
+				}
+			}
 			switch rule := rr.(type) {
 			case *rules.AlertingRule:
 				if !returnAlerts {
@@ -1731,19 +1945,24 @@ func (api *API) rules(r *http.Request) apiFuncResult {
 				}
 
 				enrichedRule = AlertingRule{
-					State:          rule.State().String(),
-					Name:           rule.Name(),
-					Query:          rule.Query().String(),
-					Duration:       rule.HoldDuration().Seconds(),
-					KeepFiringFor:  rule.KeepFiringFor().Seconds(),
-					Labels:         rule.Labels(),
-					Annotations:    rule.Annotations(),
-					Alerts:         activeAlerts,
-					Health:         rule.Health(),
-					LastError:      lastError,
-					EvaluationTime: rule.GetEvaluationDuration().Seconds(),
-					LastEvaluation: rule.GetEvaluationTimestamp(),
-					Type:           "alerting",
+					State:             rule.State().String(),
+					Name:              rule.Name(),
+					Query:             rule.Query().String(),
+					Duration:          rule.HoldDuration().Seconds(),
+					KeepFiringFor:     rule.KeepFiringFor().Seconds(),
+					Labels:            rule.Labels(),
+					Annotations:       rule.Annotations(),
+					Alerts:            activeAlerts,
+					Health:            rule.Health(),
+					LastError:         lastError,
+					EvaluationTime:    rule.GetEvaluationDuration().Seconds(),
+					LastEvaluation:    rule.GetEvaluationTimestamp(),
+					EvaluationStats:   rule.GetEvaluationStats(),
+					NotificationsSent: rule.NotificationsSent(),
+					Flaps:             rule.Flaps(),
+					FiringTime:        rule.FiringDuration().Seconds(),
+					LastSamples:       lastSamples,
+					Type:              "alerting",
 				}
 
 			case *rules.RecordingRule:
@@ -1751,14 +1970,16 @@ func (api *API) rules(r *http.Request) apiFuncResult {
 					break
 				}
 				enrichedRule = RecordingRule{
-					Name:           rule.Name(),
-					Query:          rule.Query().String(),
-					Labels:         rule.Labels(),
-					Health:         rule.Health(),
-					LastError:      lastError,
-					EvaluationTime: rule.GetEvaluationDuration().Seconds(),
-					LastEvaluation: rule.GetEvaluationTimestamp(),
-					Type:           "recording",
+					Name:            rule.Name(),
+					Query:           rule.Query().String(),
+					Labels:          rule.Labels(),
+					Health:          rule.Health(),
+					LastError:       lastError,
+					EvaluationTime:  rule.GetEvaluationDuration().Seconds(),
+					LastEvaluation:  rule.GetEvaluationTimestamp(),
+					EvaluationStats: rule.GetEvaluationStats(),
+					LastSamples:     lastSamples,
+					Type:            "recording",
 				}
 			default:
 				err := fmt.Errorf("failed to assert type of rule '%v'", rule.Name())
@@ -1804,6 +2025,25 @@ func parseExcludeAlerts(r *http.Request) (bool, error) {
 	return excludeAlerts, nil
 }
 
+// parsePreviewSamples returns the number of last evaluation result samples to
+// include per rule, or 0 if no preview was requested.
+func parsePreviewSamples(r *http.Request) (int, error) {
+	previewSamplesParam := r.URL.Query().Get("preview_samples")
+
+	if previewSamplesParam == "" {
+		return 0, nil
+	}
+
+	previewSamples, err := strconv.Atoi(previewSamplesParam)
+	if err != nil {
+		return 0, fmt.Errorf("error converting preview_samples: %w", err)
+	}
+	if previewSamples < 0 {
+		return 0, errors.New("preview_samples needs to be greater than or equal to 0")
+	}
+	return previewSamples, nil
+}
+
 func parseListRulesPaginationRequest(r *http.Request) (int64, string, *apiFuncResult) {
 	var (
 		parsedMaxGroups int64 = -1
@@ -1844,6 +2084,10 @@ func getRuleGroupNextToken(file, group string) string {
 
 type prometheusConfig struct {
 	YAML string `json:"yaml"`
+	// PreviousYAML is the previously loaded configuration with secrets
+	// redacted. It is empty until the configuration has been reloaded, and
+	// lets clients show what changed with the last reload.
+	PreviousYAML string `json:"previousYAML,omitempty"`
 }
 
 func (api *API) serveRuntimeInfo(*http.Request) apiFuncResult {
@@ -1862,6 +2106,9 @@ func (api *API) serveConfig(*http.Request) apiFuncResult {
 	cfg := &prometheusConfig{
 		YAML: api.config().String(),
 	}
+	if api.previousConfig != nil {
+		cfg.PreviousYAML = api.previousConfig()
+	}
 	return apiFuncResult{cfg, nil, nil, nil}
 }
 
@@ -1869,6 +2116,16 @@ func (api *API) serveFlags(*http.Request) apiFuncResult {
 	return apiFuncResult{api.flagsMap, nil, nil, nil}
 }
 
+// serveSDUpdates returns the recent service discovery update diffs recorded
+// by the discovery managers, keyed by manager name. It helps answering "why
+// did my target disappear" without having to raise the log level.
+func (api *API) serveSDUpdates(*http.Request) apiFuncResult {
+	if api.sdUpdateHistory == nil {
+		return apiFuncResult{map[string][]discovery.UpdateDiff{}, nil, nil, nil}
+	}
+	return apiFuncResult{api.sdUpdateHistory(), nil, nil, nil}
+}
+
 // featuresData wraps feature flags data to provide custom JSON marshaling without HTML escaping.
 // featuresData does not contain user-provided input, and it is more convenient to have unescaped
 // representation of PromQL operators like >=.
@@ -1971,6 +2228,19 @@ func (api *API) serveTSDBBlocks(*http.Request) apiFuncResult {
 	}
 }
 
+func (api *API) serveTSDBBlockQueryStats(*http.Request) apiFuncResult {
+	stats, err := api.db.BlockQueryStats()
+	if err != nil {
+		return apiFuncResult{nil, &apiError{errorInternal, fmt.Errorf("error getting block query statistics: %w", err)}, nil, nil}
+	}
+
+	return apiFuncResult{
+		data: map[string][]tsdb.BlockQueryStat{
+			"blockQueryStats": stats,
+		},
+	}
+}
+
 func (api *API) serveTSDBStatus(r *http.Request) apiFuncResult {
 	const maxTSDBLimit = 10000
 	limit := 10
@@ -2087,6 +2357,65 @@ func (api *API) notificationsSSE(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (api *API) stateEvents(w http.ResponseWriter, r *http.Request) {
+	httputil.SetCORS(w, api.CORSOrigin, r)
+
+	typ := r.URL.Query().Get("type")
+	switch typ {
+	case "", stateevents.TypeAlert, stateevents.TypeTarget:
+	default:
+		http.Error(w, fmt.Sprintf("invalid event type %q", typ), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	// Subscribe to state events.
+	events, unsubscribe, ok := api.stateEventsSub()
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	defer unsubscribe()
+
+	// Set up a flusher to push the response to the client.
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// Flush the response to ensure the headers are immediately and eventSource
+	// onopen is triggered client-side.
+	flusher.Flush()
+
+	for {
+		select {
+		case event := <-events:
+			if typ != "" && event.Type != typ {
+				continue
+			}
+
+			// Marshal the event to JSON.
+			jsonData, err := json.Marshal(event)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				continue
+			}
+
+			// Write the event data in SSE format with JSON content.
+			fmt.Fprintf(w, "data: %s\n\n", jsonData)
+
+			// Flush the response to ensure the data is sent immediately.
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
 func (api *API) remoteRead(w http.ResponseWriter, r *http.Request) {
 	// This is only really for tests - this will never be nil IRL.
 	if api.remoteReadHandler != nil {
@@ -2112,6 +2441,14 @@ func (api *API) otlpWrite(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (api *API) openMetricsPush(w http.ResponseWriter, r *http.Request) {
+	if api.omPushHandler != nil {
+		api.omPushHandler.ServeHTTP(w, r)
+	} else {
+		http.Error(w, "OpenMetrics push receiver needs to be enabled with --web.enable-openmetrics-push-receiver", http.StatusNotFound)
+	}
+}
+
 func (api *API) deleteSeries(r *http.Request) apiFuncResult {
 	if !api.enableAdmin {
 		return apiFuncResult{nil, &apiError{errorUnavailable, errors.New("admin APIs disabled")}, nil, nil}
@@ -2137,7 +2474,8 @@ func (api *API) deleteSeries(r *http.Request) apiFuncResult {
 		if err != nil {
 			return invalidParamError(err, "match[]")
 		}
-		if err := api.db.Delete(r.Context(), timestamp.FromTime(start), timestamp.FromTime(end), matchers...); err != nil {
+		ctx := tsdb.WithDeleteAuditSource(r.Context(), r.RemoteAddr)
+		if err := api.db.Delete(ctx, timestamp.FromTime(start), timestamp.FromTime(end), matchers...); err != nil {
 			return apiFuncResult{nil, &apiError{errorInternal, err}, nil, nil}
 		}
 	}
@@ -2145,6 +2483,111 @@ func (api *API) deleteSeries(r *http.Request) apiFuncResult {
 	return apiFuncResult{nil, nil, nil, nil}
 }
 
+func (api *API) overrideScrapeTargets(r *http.Request) apiFuncResult {
+	if !api.enableAdmin {
+		return apiFuncResult{nil, &apiError{errorUnavailable, errors.New("admin APIs disabled")}, nil, nil}
+	}
+	if err := r.ParseForm(); err != nil {
+		return apiFuncResult{nil, &apiError{errorBadData, fmt.Errorf("error parsing form values: %w", err)}, nil, nil}
+	}
+	if len(r.Form["match[]"]) == 0 {
+		return apiFuncResult{nil, &apiError{errorBadData, errors.New("no match[] parameter provided")}, nil, nil}
+	}
+
+	var (
+		override      *scrape.TargetOverride
+		clearOverride bool
+		err           error
+	)
+	if v := r.FormValue("clear"); v != "" {
+		if clearOverride, err = strconv.ParseBool(v); err != nil {
+			return invalidParamError(fmt.Errorf("unable to parse boolean: %w", err), "clear")
+		}
+	}
+	if !clearOverride {
+		o := &scrape.TargetOverride{}
+		if v := r.FormValue("interval"); v != "" {
+			d, err := model.ParseDuration(v)
+			if err != nil {
+				return invalidParamError(err, "interval")
+			}
+			o.Interval = time.Duration(d)
+		}
+		if v := r.FormValue("timeout"); v != "" {
+			d, err := model.ParseDuration(v)
+			if err != nil {
+				return invalidParamError(err, "timeout")
+			}
+			o.Timeout = time.Duration(d)
+		}
+		if v := r.FormValue("pause"); v != "" {
+			if o.Paused, err = strconv.ParseBool(v); err != nil {
+				return invalidParamError(fmt.Errorf("unable to parse boolean: %w", err), "pause")
+			}
+		}
+		if o.Interval == 0 && o.Timeout == 0 && !o.Paused {
+			return apiFuncResult{nil, &apiError{errorBadData, errors.New("at least one of interval, timeout and pause must be provided")}, nil, nil}
+		}
+		override = o
+	}
+
+	affected := 0
+	for _, s := range r.Form["match[]"] {
+		matchers, err := api.parser.ParseMetricSelector(s)
+		if err != nil {
+			return invalidParamError(err, "match[]")
+		}
+		n, err := api.targetRetriever(r.Context()).OverrideTargets(r.FormValue("scrape_pool"), matchers, override)
+		if err != nil {
+			return apiFuncResult{nil, &apiError{errorBadData, err}, nil, nil}
+		}
+		affected += n
+	}
+
+	return apiFuncResult{struct {
+		Affected int `json:"affected"`
+	}{affected}, nil, nil, nil}
+}
+
+func (api *API) markScrapeTargetsStale(r *http.Request) apiFuncResult {
+	if !api.enableAdmin {
+		return apiFuncResult{nil, &apiError{errorUnavailable, errors.New("admin APIs disabled")}, nil, nil}
+	}
+	if err := r.ParseForm(); err != nil {
+		return apiFuncResult{nil, &apiError{errorBadData, fmt.Errorf("error parsing form values: %w", err)}, nil, nil}
+	}
+	if len(r.Form["match[]"]) == 0 {
+		return apiFuncResult{nil, &apiError{errorBadData, errors.New("no match[] parameter provided")}, nil, nil}
+	}
+
+	affected := 0
+	for _, s := range r.Form["match[]"] {
+		matchers, err := api.parser.ParseMetricSelector(s)
+		if err != nil {
+			return invalidParamError(err, "match[]")
+		}
+		n, err := api.targetRetriever(r.Context()).MarkTargetsStale(r.FormValue("scrape_pool"), matchers)
+		if err != nil {
+			return apiFuncResult{nil, &apiError{errorBadData, err}, nil, nil}
+		}
+		affected += n
+	}
+
+	return apiFuncResult{struct {
+		Affected int `json:"affected"`
+	}{affected}, nil, nil, nil}
+}
+
+// remoteWriteTest sends a small synthetic write to every configured remote
+// write endpoint and reports per-endpoint auth, TLS and protocol results.
+func (api *API) remoteWriteTest(r *http.Request) apiFuncResult {
+	if !api.enableAdmin {
+		return apiFuncResult{nil, &apiError{errorUnavailable, errors.New("admin APIs disabled")}, nil, nil}
+	}
+	cfg := api.config()
+	return apiFuncResult{remote.CheckEndpoints(r.Context(), &cfg), nil, nil, nil}
+}
+
 func (api *API) snapshot(r *http.Request) apiFuncResult {
 	if !api.enableAdmin {
 		return apiFuncResult{nil, &apiError{errorUnavailable, errors.New("admin APIs disabled")}, nil, nil}
@@ -2167,10 +2610,27 @@ func (api *API) snapshot(r *http.Request) apiFuncResult {
 			rand.Int63())
 		dir = filepath.Join(snapdir, name)
 	)
+
+	// An incremental snapshot skips blocks already contained in the named base snapshot.
+	base := r.FormValue("base")
+	if base != "" {
+		if base != filepath.Base(base) {
+			return invalidParamError(errors.New("must be the name of a previous snapshot"), "base")
+		}
+		if _, err := os.Stat(filepath.Join(snapdir, base)); err != nil {
+			return invalidParamError(fmt.Errorf("base snapshot not found: %w", err), "base")
+		}
+	}
+
 	if err := os.MkdirAll(dir, 0o777); err != nil {
 		return apiFuncResult{nil, &apiError{errorInternal, fmt.Errorf("create snapshot directory: %w", err)}, nil, nil}
 	}
-	if err := api.db.Snapshot(dir, !skipHead); err != nil {
+	if base != "" {
+		err = api.db.SnapshotIncremental(dir, filepath.Join(snapdir, base), !skipHead)
+	} else {
+		err = api.db.Snapshot(dir, !skipHead)
+	}
+	if err != nil {
 		return apiFuncResult{nil, &apiError{errorInternal, fmt.Errorf("create snapshot: %w", err)}, nil, nil}
 	}
 
@@ -2179,6 +2639,96 @@ func (api *API) snapshot(r *http.Request) apiFuncResult {
 	}{name}, nil, nil, nil}
 }
 
+func (api *API) importBlock(r *http.Request) apiFuncResult {
+	if !api.enableAdmin {
+		return apiFuncResult{nil, &apiError{errorUnavailable, errors.New("admin APIs disabled")}, nil, nil}
+	}
+	uid, err := api.db.ImportBlock(r.Body)
+	if err != nil {
+		return apiFuncResult{nil, &apiError{errorBadData, fmt.Errorf("import block: %w", err)}, nil, nil}
+	}
+
+	return apiFuncResult{struct {
+		ULID string `json:"ulid"`
+	}{uid.String()}, nil, nil, nil}
+}
+
+// exportBlock streams a block holding the data selected by the given matchers
+// and time range as a tar archive that the import_block endpoint of another
+// Prometheus accepts. It is a raw handler rather than an apiFunc because the
+// response is a stream, not a JSON document.
+func (api *API) exportBlock(w http.ResponseWriter, r *http.Request) {
+	if !api.enableAdmin {
+		api.respondError(w, &apiError{errorUnavailable, errors.New("admin APIs disabled")}, nil)
+		return
+	}
+	if api.isAgent {
+		api.respondError(w, &apiError{errorExec, errors.New("unavailable with Prometheus Agent")}, nil)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		api.respondError(w, &apiError{errorBadData, fmt.Errorf("error parsing form values: %w", err)}, nil)
+		return
+	}
+	if len(r.Form["match[]"]) == 0 {
+		api.respondError(w, &apiError{errorBadData, errors.New("no match[] parameter provided")}, nil)
+		return
+	}
+
+	start, err := parseTimeParam(r, "start", MinTime)
+	if err != nil {
+		api.respondError(w, &apiError{errorBadData, fmt.Errorf("invalid parameter start: %w", err)}, nil)
+		return
+	}
+	end, err := parseTimeParam(r, "end", MaxTime)
+	if err != nil {
+		api.respondError(w, &apiError{errorBadData, fmt.Errorf("invalid parameter end: %w", err)}, nil)
+		return
+	}
+	matcherSets := make([][]*labels.Matcher, 0, len(r.Form["match[]"]))
+	for _, s := range r.Form["match[]"] {
+		matchers, err := api.parser.ParseMetricSelector(s)
+		if err != nil {
+			api.respondError(w, &apiError{errorBadData, fmt.Errorf("invalid parameter match[]: %w", err)}, nil)
+			return
+		}
+		matcherSets = append(matcherSets, matchers)
+	}
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	w.Header().Set("Content-Disposition", `attachment; filename="export.tar"`)
+	cw := &countingWriter{w: w}
+	if _, err := api.db.ExportBlock(r.Context(), cw, timestamp.FromTime(start), timestamp.FromTime(end), matcherSets...); err != nil {
+		if cw.written == 0 {
+			// Nothing has been streamed yet, so a proper error response is
+			// still possible.
+			w.Header().Del("Content-Type")
+			w.Header().Del("Content-Disposition")
+			if errors.Is(err, tsdb.ErrNothingToExport) {
+				api.respondError(w, &apiError{errorBadData, err}, nil)
+			} else {
+				api.respondError(w, &apiError{errorInternal, fmt.Errorf("export block: %w", err)}, nil)
+			}
+			return
+		}
+		// The archive is already partially written; all we can do is abort it.
+		api.logger.Error("error streaming block export", "err", err)
+	}
+}
+
+// countingWriter counts the bytes written through it so a streaming handler
+// can tell whether the response has started.
+type countingWriter struct {
+	w       io.Writer
+	written int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.written += int64(n)
+	return n, err
+}
+
 func (api *API) cleanTombstones(*http.Request) apiFuncResult {
 	if !api.enableAdmin {
 		return apiFuncResult{nil, &apiError{errorUnavailable, errors.New("admin APIs disabled")}, nil, nil}
@@ -2190,6 +2740,21 @@ func (api *API) cleanTombstones(*http.Request) apiFuncResult {
 	return apiFuncResult{nil, nil, nil, nil}
 }
 
+func (api *API) deleteAuditLog(*http.Request) apiFuncResult {
+	if !api.enableAdmin {
+		return apiFuncResult{nil, &apiError{errorUnavailable, errors.New("admin APIs disabled")}, nil, nil}
+	}
+	entries, err := api.db.DeleteAuditLog()
+	if err != nil {
+		return apiFuncResult{nil, &apiError{errorInternal, err}, nil, nil}
+	}
+	if entries == nil {
+		entries = []tsdb.DeleteAuditEntry{}
+	}
+
+	return apiFuncResult{entries, nil, nil, nil}
+}
+
 // Query string is needed to get the position information for the annotations, and it
 // can be empty if the position information isn't needed.
 func (api *API) respond(w http.ResponseWriter, req *http.Request, data any, warnings annotations.Annotations, query string) {
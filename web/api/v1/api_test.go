@@ -46,6 +46,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/metadata"
@@ -171,6 +172,52 @@ func (t testTargetRetriever) TargetsDroppedCounts() map[string]int {
 	return r
 }
 
+func (t *testTargetRetriever) OverrideTargets(pool string, matchers []*labels.Matcher, override *scrape.TargetOverride) (int, error) {
+	affected := 0
+	for p, targets := range t.activeTargets {
+		if pool != "" && p != pool {
+			continue
+		}
+		for _, target := range targets {
+			matches := true
+			for _, m := range matchers {
+				if !m.Matches(target.Labels(labels.NewBuilder(labels.EmptyLabels())).Get(m.Name)) {
+					matches = false
+					break
+				}
+			}
+			if !matches {
+				continue
+			}
+			target.SetOverride(override)
+			affected++
+		}
+	}
+	return affected, nil
+}
+
+func (t *testTargetRetriever) MarkTargetsStale(pool string, matchers []*labels.Matcher) (int, error) {
+	affected := 0
+	for p, targets := range t.activeTargets {
+		if pool != "" && p != pool {
+			continue
+		}
+		for _, target := range targets {
+			matches := true
+			for _, m := range matchers {
+				if !m.Matches(target.Labels(labels.NewBuilder(labels.EmptyLabels())).Get(m.Name)) {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				affected++
+			}
+		}
+	}
+	return affected, nil
+}
+
 func (testTargetRetriever) ScrapePoolConfig(pool string) (*config.ScrapeConfig, error) {
 	cfg := &config.ScrapeConfig{
 		RelabelConfigs: []*relabel.Config{
@@ -426,6 +473,65 @@ func appendExemplars(t testing.TB, s storage.Storage, ex []exemplar.QueryResult)
 	require.NoError(t, app.Commit())
 }
 
+func TestAnalyzeQuery(t *testing.T) {
+	s := promqltest.LoadedStorage(t, `
+		load 1m
+			test_metric1{foo="bar"} 0+100x100
+			test_metric1{foo="boo"} 1+0x100
+			test_metric2{foo="boo"} 1+0x100
+	`)
+	t.Cleanup(func() { s.Close() })
+
+	api := &API{
+		Queryable: s,
+		// A fixed evaluation time within the range of the loaded data, so the
+		// cardinality estimation window covers the series above.
+		now:    func() time.Time { return time.Unix(3600, 0) },
+		parser: testParser,
+	}
+
+	for _, tc := range []struct {
+		name     string
+		query    string
+		expected *QueryAnalysis
+	}{
+		{
+			name:     "single selector",
+			query:    "test_metric1",
+			expected: &QueryAnalysis{Selectors: 1, EstimatedSeries: 2},
+		},
+		{
+			name:  "selector without metric name",
+			query: `{foo="boo"}`,
+			expected: &QueryAnalysis{
+				Selectors:       1,
+				EstimatedSeries: 2,
+				Warnings:        []string{`selector {foo="boo"} has no metric name and may match many series`},
+			},
+		},
+		{
+			name:  "match-all regex matcher",
+			query: `rate(test_metric1{foo=~".*"}[5m]) + test_metric2`,
+			expected: &QueryAnalysis{
+				Selectors:       2,
+				EstimatedSeries: 3,
+				Warnings:        []string{`matcher foo=~".*" matches all values of label "foo"`},
+			},
+		},
+		{
+			name:     "no selectors",
+			query:    "time()",
+			expected: &QueryAnalysis{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := testParser.ParseExpr(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, api.analyzeQuery(context.Background(), expr))
+		})
+	}
+}
+
 func TestEndpoints(t *testing.T) {
 	s := promqltest.LoadedStorage(t, `
 		load 1m
@@ -1235,17 +1341,25 @@ func testEndpoints(t *testing.T, api *API, tr *testTargetRetriever, testLabelAPI
 					case AlertingRule:
 						r.LastEvaluation = time.Time{}
 						r.EvaluationTime = float64(0)
+						r.EvaluationStats = rules.EvaluationStats{}
 						r.LastError = ""
 						r.Health = "ok"
 						for _, alert := range r.Alerts {
 							alert.ActiveAt = nil
 						}
+						for i := range r.LastSamples {
+							r.LastSamples[i].T = 0
+						}
 						ruleGroup.Rules[k] = r
 					case RecordingRule:
 						r.LastEvaluation = time.Time{}
 						r.EvaluationTime = float64(0)
+						r.EvaluationStats = rules.EvaluationStats{}
 						r.LastError = ""
 						r.Health = "ok"
+						for i := range r.LastSamples {
+							r.LastSamples[i].T = 0
+						}
 						ruleGroup.Rules[k] = r
 					}
 				}
@@ -1670,6 +1784,25 @@ func testEndpoints(t *testing.T, api *API, tr *testTargetRetriever, testLabelAPI
 			},
 			errType: errorBadData,
 		},
+		{
+			endpoint: api.formatQuery,
+			query: url.Values{
+				"query":   []string{"foo+bar"},
+				"analyze": []string{"true"},
+			},
+			response: &FormattedQuery{
+				Formatted: "foo + bar",
+				Analysis:  &QueryAnalysis{Selectors: 2},
+			},
+		},
+		{
+			endpoint: api.formatQuery,
+			query: url.Values{
+				"query":   []string{"foo+bar"},
+				"analyze": []string{"not-a-bool"},
+			},
+			errType: errorBadData,
+		},
 		{
 			endpoint: api.series,
 			query: url.Values{
@@ -1831,7 +1964,7 @@ func testEndpoints(t *testing.T, api *API, tr *testTargetRetriever, testLabelAPI
 			response: &TargetDiscovery{
 				ActiveTargets: []*Target{
 					{
-						DiscoveredLabels:   labels.FromStrings("__always_scrape_classic_histograms__", "false", "__convert_classic_histograms_to_nhcb__", "false", "__param_target", "example.com", "__scrape_interval__", "0s", "__scrape_native_histograms__", "false", "__scrape_timeout__", "0s"),
+						DiscoveredLabels:   labels.FromStrings("__always_scrape_classic_histograms__", "false", "__convert_classic_histograms_to_nhcb__", "false", "__param_target", "example.com", "__scrape_interval__", "0s", "__scrape_native_histograms__", "false", "__scrape_timeout__", "0s", "__scrape_without_jitter__", "false"),
 						Labels:             labels.FromStrings("job", "blackbox"),
 						ScrapePool:         "blackbox",
 						ScrapeURL:          "http://localhost:9115/probe?target=example.com",
@@ -1844,7 +1977,7 @@ func testEndpoints(t *testing.T, api *API, tr *testTargetRetriever, testLabelAPI
 						ScrapeTimeout:      "10s",
 					},
 					{
-						DiscoveredLabels:   labels.FromStrings("__always_scrape_classic_histograms__", "false", "__convert_classic_histograms_to_nhcb__", "false", "__scrape_interval__", "0s", "__scrape_native_histograms__", "false", "__scrape_timeout__", "0s"),
+						DiscoveredLabels:   labels.FromStrings("__always_scrape_classic_histograms__", "false", "__convert_classic_histograms_to_nhcb__", "false", "__scrape_interval__", "0s", "__scrape_native_histograms__", "false", "__scrape_timeout__", "0s", "__scrape_without_jitter__", "false"),
 						Labels:             labels.FromStrings("job", "test"),
 						ScrapePool:         "test",
 						ScrapeURL:          "http://example.com:8080/metrics",
@@ -1869,6 +2002,7 @@ func testEndpoints(t *testing.T, api *API, tr *testTargetRetriever, testLabelAPI
 							"__scrape_interval__", "30s",
 							"__scrape_native_histograms__", "false",
 							"__scrape_timeout__", "15s",
+							"__scrape_without_jitter__", "false",
 						),
 						ScrapePool: "blackbox",
 					},
@@ -1884,7 +2018,7 @@ func testEndpoints(t *testing.T, api *API, tr *testTargetRetriever, testLabelAPI
 			response: &TargetDiscovery{
 				ActiveTargets: []*Target{
 					{
-						DiscoveredLabels:   labels.FromStrings("__always_scrape_classic_histograms__", "false", "__convert_classic_histograms_to_nhcb__", "false", "__param_target", "example.com", "__scrape_interval__", "0s", "__scrape_native_histograms__", "false", "__scrape_timeout__", "0s"),
+						DiscoveredLabels:   labels.FromStrings("__always_scrape_classic_histograms__", "false", "__convert_classic_histograms_to_nhcb__", "false", "__param_target", "example.com", "__scrape_interval__", "0s", "__scrape_native_histograms__", "false", "__scrape_timeout__", "0s", "__scrape_without_jitter__", "false"),
 						Labels:             labels.FromStrings("job", "blackbox"),
 						ScrapePool:         "blackbox",
 						ScrapeURL:          "http://localhost:9115/probe?target=example.com",
@@ -1897,7 +2031,7 @@ func testEndpoints(t *testing.T, api *API, tr *testTargetRetriever, testLabelAPI
 						ScrapeTimeout:      "10s",
 					},
 					{
-						DiscoveredLabels:   labels.FromStrings("__always_scrape_classic_histograms__", "false", "__convert_classic_histograms_to_nhcb__", "false", "__scrape_interval__", "0s", "__scrape_native_histograms__", "false", "__scrape_timeout__", "0s"),
+						DiscoveredLabels:   labels.FromStrings("__always_scrape_classic_histograms__", "false", "__convert_classic_histograms_to_nhcb__", "false", "__scrape_interval__", "0s", "__scrape_native_histograms__", "false", "__scrape_timeout__", "0s", "__scrape_without_jitter__", "false"),
 						Labels:             labels.FromStrings("job", "test"),
 						ScrapePool:         "test",
 						ScrapeURL:          "http://example.com:8080/metrics",
@@ -1922,6 +2056,7 @@ func testEndpoints(t *testing.T, api *API, tr *testTargetRetriever, testLabelAPI
 							"__scrape_interval__", "30s",
 							"__scrape_native_histograms__", "false",
 							"__scrape_timeout__", "15s",
+							"__scrape_without_jitter__", "false",
 						),
 						ScrapePool: "blackbox",
 					},
@@ -1937,7 +2072,7 @@ func testEndpoints(t *testing.T, api *API, tr *testTargetRetriever, testLabelAPI
 			response: &TargetDiscovery{
 				ActiveTargets: []*Target{
 					{
-						DiscoveredLabels:   labels.FromStrings("__always_scrape_classic_histograms__", "false", "__convert_classic_histograms_to_nhcb__", "false", "__param_target", "example.com", "__scrape_interval__", "0s", "__scrape_native_histograms__", "false", "__scrape_timeout__", "0s"),
+						DiscoveredLabels:   labels.FromStrings("__always_scrape_classic_histograms__", "false", "__convert_classic_histograms_to_nhcb__", "false", "__param_target", "example.com", "__scrape_interval__", "0s", "__scrape_native_histograms__", "false", "__scrape_timeout__", "0s", "__scrape_without_jitter__", "false"),
 						Labels:             labels.FromStrings("job", "blackbox"),
 						ScrapePool:         "blackbox",
 						ScrapeURL:          "http://localhost:9115/probe?target=example.com",
@@ -1950,7 +2085,7 @@ func testEndpoints(t *testing.T, api *API, tr *testTargetRetriever, testLabelAPI
 						ScrapeTimeout:      "10s",
 					},
 					{
-						DiscoveredLabels:   labels.FromStrings("__always_scrape_classic_histograms__", "false", "__convert_classic_histograms_to_nhcb__", "false", "__scrape_interval__", "0s", "__scrape_native_histograms__", "false", "__scrape_timeout__", "0s"),
+						DiscoveredLabels:   labels.FromStrings("__always_scrape_classic_histograms__", "false", "__convert_classic_histograms_to_nhcb__", "false", "__scrape_interval__", "0s", "__scrape_native_histograms__", "false", "__scrape_timeout__", "0s", "__scrape_without_jitter__", "false"),
 						Labels:             labels.FromStrings("job", "test"),
 						ScrapePool:         "test",
 						ScrapeURL:          "http://example.com:8080/metrics",
@@ -1985,6 +2120,7 @@ func testEndpoints(t *testing.T, api *API, tr *testTargetRetriever, testLabelAPI
 							"__scrape_interval__", "30s",
 							"__scrape_native_histograms__", "false",
 							"__scrape_timeout__", "15s",
+							"__scrape_without_jitter__", "false",
 						),
 						ScrapePool: "blackbox",
 					},
@@ -2683,6 +2819,50 @@ func testEndpoints(t *testing.T, api *API, tr *testTargetRetriever, testLabelAPI
 			},
 			zeroFunc: rulesZeroFunc,
 		},
+		{
+			endpoint: api.rules,
+			query: url.Values{
+				"rule_name[]":     []string{"recording-rule-1"},
+				"preview_samples": []string{"1"},
+			},
+			response: &RuleDiscovery{
+				RuleGroups: []*RuleGroup{
+					{
+						Name:     "grp",
+						File:     "/path/to/file",
+						Interval: 1,
+						Limit:    0,
+						Rules: []Rule{
+							RecordingRule{
+								Name:   "recording-rule-1",
+								Query:  "vector(1)",
+								Labels: labels.Labels{},
+								Health: "ok",
+								LastSamples: []promql.Sample{
+									{Metric: labels.FromStrings(model.MetricNameLabel, "recording-rule-1"), F: 1},
+								},
+								Type: "recording",
+							},
+						},
+					},
+				},
+			},
+			zeroFunc: rulesZeroFunc,
+		},
+		{
+			endpoint: api.rules,
+			query: url.Values{
+				"preview_samples": []string{"-1"},
+			},
+			errType: errorBadData,
+		},
+		{
+			endpoint: api.rules,
+			query: url.Values{
+				"preview_samples": []string{"all"},
+			},
+			errType: errorBadData,
+		},
 		{
 			endpoint: api.rules,
 			query: url.Values{
@@ -3985,8 +4165,17 @@ func (f *fakeDB) CleanTombstones() error { return f.err }
 func (f *fakeDB) BlockMetas() ([]tsdb.BlockMeta, error) {
 	return f.blockMetas, nil
 }
+
+func (f *fakeDB) BlockQueryStats() ([]tsdb.BlockQueryStat, error) {
+	return []tsdb.BlockQueryStat{}, f.err
+}
 func (f *fakeDB) Delete(context.Context, int64, int64, ...*labels.Matcher) error { return f.err }
 func (f *fakeDB) Snapshot(string, bool) error                                    { return f.err }
+func (f *fakeDB) SnapshotIncremental(_, _ string, _ bool) error                  { return f.err }
+func (f *fakeDB) ImportBlock(io.Reader) (ulid.ULID, error)                       { return ulid.ULID{}, f.err }
+func (f *fakeDB) ExportBlock(context.Context, io.Writer, int64, int64, ...[]*labels.Matcher) (ulid.ULID, error) {
+	return ulid.ULID{}, f.err
+}
 func (*fakeDB) Stats(statsByLabelName string, limit int) (_ *tsdb.Stats, retErr error) {
 	dbDir, err := os.MkdirTemp("", "tsdb-api-ready")
 	if err != nil {
@@ -4008,11 +4197,15 @@ func (*fakeDB) WALReplayStatus() (tsdb.WALReplayStatus, error) {
 	return tsdb.WALReplayStatus{}, nil
 }
 
+func (f *fakeDB) DeleteAuditLog() ([]tsdb.DeleteAuditEntry, error) { return nil, f.err }
+
 func TestAdminEndpoints(t *testing.T) {
 	tsdb, tsdbWithError, tsdbNotReady := &fakeDB{}, &fakeDB{err: errors.New("some error")}, &fakeDB{err: fmt.Errorf("wrap: %w", tsdb.ErrNotReady)}
 	snapshotAPI := func(api *API) apiFunc { return api.snapshot }
 	cleanAPI := func(api *API) apiFunc { return api.cleanTombstones }
 	deleteAPI := func(api *API) apiFunc { return api.deleteSeries }
+	auditAPI := func(api *API) apiFunc { return api.deleteAuditLog }
+	importAPI := func(api *API) apiFunc { return api.importBlock }
 
 	for _, tc := range []struct {
 		db          *fakeDB
@@ -4166,6 +4359,64 @@ func TestAdminEndpoints(t *testing.T) {
 			endpoint:    deleteAPI,
 			values:      map[string][]string{"match[]": {"up"}},
 
+			errType: errorUnavailable,
+		},
+		// Tests for the delete audit log endpoint.
+		{
+			db:          tsdb,
+			enableAdmin: false,
+			endpoint:    auditAPI,
+
+			errType: errorUnavailable,
+		},
+		{
+			db:          tsdb,
+			enableAdmin: true,
+			endpoint:    auditAPI,
+
+			errType: errorNone,
+		},
+		{
+			db:          tsdbWithError,
+			enableAdmin: true,
+			endpoint:    auditAPI,
+
+			errType: errorInternal,
+		},
+		{
+			db:          tsdbNotReady,
+			enableAdmin: true,
+			endpoint:    auditAPI,
+
+			errType: errorUnavailable,
+		},
+		// Tests for the importBlock endpoint.
+		{
+			db:          tsdb,
+			enableAdmin: false,
+			endpoint:    importAPI,
+
+			errType: errorUnavailable,
+		},
+		{
+			db:          tsdb,
+			enableAdmin: true,
+			endpoint:    importAPI,
+
+			errType: errorNone,
+		},
+		{
+			db:          tsdbWithError,
+			enableAdmin: true,
+			endpoint:    importAPI,
+
+			errType: errorBadData,
+		},
+		{
+			db:          tsdbNotReady,
+			enableAdmin: true,
+			endpoint:    importAPI,
+
 			errType: errorUnavailable,
 		},
 	} {
@@ -4190,6 +4441,88 @@ func TestAdminEndpoints(t *testing.T) {
 	}
 }
 
+// TestExportBlockEndpoint covers the export endpoint separately from
+// TestAdminEndpoints because it is a raw streaming handler, not an apiFunc.
+func TestExportBlockEndpoint(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		db          *fakeDB
+		enableAdmin bool
+		values      url.Values
+
+		expectedCode int
+	}{
+		{
+			name:         "admin disabled",
+			db:           &fakeDB{},
+			enableAdmin:  false,
+			values:       url.Values{"match[]": {"up"}},
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name:         "no matchers",
+			db:           &fakeDB{},
+			enableAdmin:  true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "bad matcher",
+			db:           &fakeDB{},
+			enableAdmin:  true,
+			values:       url.Values{"match[]": {"123"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "bad start time",
+			db:           &fakeDB{},
+			enableAdmin:  true,
+			values:       url.Values{"match[]": {"up"}, "start": {"xxx"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "success",
+			db:           &fakeDB{},
+			enableAdmin:  true,
+			values:       url.Values{"match[]": {"up"}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "nothing to export",
+			db:           &fakeDB{err: tsdb.ErrNothingToExport},
+			enableAdmin:  true,
+			values:       url.Values{"match[]": {"up"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "export fails",
+			db:           &fakeDB{err: errors.New("some error")},
+			enableAdmin:  true,
+			values:       url.Values{"match[]": {"up"}},
+			expectedCode: http.StatusInternalServerError,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			api := &API{
+				db:          tc.db,
+				logger:      promslog.NewNopLogger(),
+				ready:       func(f http.HandlerFunc) http.HandlerFunc { return f },
+				enableAdmin: tc.enableAdmin,
+				parser:      testParser,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("?%s", tc.values.Encode()), http.NoBody)
+			require.NoError(t, err)
+			rec := httptest.NewRecorder()
+			api.exportBlock(rec, req)
+
+			require.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				require.Equal(t, "application/x-tar", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
 func TestRespondSuccess(t *testing.T) {
 	api := API{
 		logger: promslog.NewNopLogger(),
@@ -4349,6 +4682,32 @@ func TestServeTSDBBlocks(t *testing.T) {
 	require.Equal(t, blockMeta, resultData.Blocks[0])
 }
 
+func TestServeSDUpdates(t *testing.T) {
+	// Without wiring, the endpoint responds with an empty object rather than
+	// an error, e.g. in test setups.
+	api := &API{}
+	result := api.serveSDUpdates(nil)
+	require.Nil(t, result.err)
+	require.Equal(t, map[string][]discovery.UpdateDiff{}, result.data)
+
+	history := map[string][]discovery.UpdateDiff{
+		"scrape": {
+			{
+				Provider:  "static/0",
+				TargetSet: "job1",
+				Groups: []discovery.GroupDiff{
+					{Source: "tg1", Added: true, TargetsAdded: []string{"a:80"}},
+				},
+			},
+		},
+		"notify": {},
+	}
+	api = &API{sdUpdateHistory: func() map[string][]discovery.UpdateDiff { return history }}
+	result = api.serveSDUpdates(nil)
+	require.Nil(t, result.err)
+	require.Equal(t, history, result.data)
+}
+
 func TestRespondError(t *testing.T) {
 	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		api := API{}
@@ -141,6 +141,7 @@ func createPrometheusAPI(t *testing.T, q storage.SampleAndChunkQueryable, overri
 		func(context.Context) TargetRetriever { return &DummyTargetRetriever{} },
 		func(context.Context) AlertmanagerRetriever { return &DummyAlertmanagerRetriever{} },
 		func() config.Config { return config.Config{} },
+		func() string { return "" },
 		map[string]string{}, // TODO: include configuration flags
 		GlobalURLOptions{},
 		func(f http.HandlerFunc) http.HandlerFunc { return f },
@@ -151,13 +152,16 @@ func createPrometheusAPI(t *testing.T, q storage.SampleAndChunkQueryable, overri
 		0,     // Default search max-limit.
 		promslog.NewNopLogger(),
 		func(context.Context) RulesRetriever { return &DummyRulesRetriever{} },
-		0, 0, 0, // Remote read samples and concurrency limit.
+		nil,              // No SD update history.
+		0, 0, 0, 0, 0, 0, // Remote read samples, concurrency, frame, stream and backlog limits.
 		false, // Not an agent.
+		false, // No agent local query.
 		regexp.MustCompile(".*"),
 		func() (RuntimeInfo, error) { return RuntimeInfo{}, errors.New("not implemented") },
 		&PrometheusVersion{},
 		nil,
 		nil,
+		nil,
 		prometheus.DefaultGatherer,
 		nil,
 		nil,
@@ -165,6 +169,9 @@ func createPrometheusAPI(t *testing.T, q storage.SampleAndChunkQueryable, overri
 		remoteapi.MessageTypes{remoteapi.WriteV1MessageType, remoteapi.WriteV2MessageType},
 		false,
 		false,
+		0,
+		0,
+		false,
 		false,
 		false,
 		5*time.Minute,
@@ -276,6 +283,16 @@ func (DummyTargetRetriever) ScrapePoolConfig(_ string) (*config.ScrapeConfig, er
 	return nil, errors.New("not implemented")
 }
 
+// OverrideTargets implements targetRetriever.
+func (DummyTargetRetriever) OverrideTargets(string, []*labels.Matcher, *scrape.TargetOverride) (int, error) {
+	return 0, errors.New("not implemented")
+}
+
+// MarkTargetsStale implements targetRetriever.
+func (DummyTargetRetriever) MarkTargetsStale(string, []*labels.Matcher) (int, error) {
+	return 0, errors.New("not implemented")
+}
+
 // DummyAlertmanagerRetriever implements AlertmanagerRetriever.
 type DummyAlertmanagerRetriever struct{}
 
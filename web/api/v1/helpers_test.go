@@ -46,6 +46,7 @@ func newTestAPI(t *testing.T, cfg testhelpers.APIConfig) *testhelpers.APIWrapper
 			return adaptAlertmanagerRetriever(params.AlertmanagerRetriever(ctx))
 		},
 		params.ConfigFunc,
+		func() string { return "" },
 		params.FlagsMap,
 		GlobalURLOptions{},
 		params.ReadyFunc,
@@ -58,10 +59,15 @@ func newTestAPI(t *testing.T, cfg testhelpers.APIConfig) *testhelpers.APIWrapper
 		func(ctx context.Context) RulesRetriever {
 			return adaptRulesRetriever(params.RulesRetriever(ctx))
 		},
+		nil,   // sdUpdateHistory
 		0,     // remoteReadSampleLimit
 		0,     // remoteReadConcurrencyLimit
 		0,     // remoteReadMaxBytesInFrame
+		0,     // remoteReadMaxConcurrentStreams
+		0,     // remoteReadMaxFrameBacklog
+		0,     // remoteReadIdleWriteTimeout
 		false, // isAgent
+		false, // agentLocalQuery
 		nil,   // corsOrigin
 		func() (RuntimeInfo, error) {
 			info, err := params.RuntimeInfoFunc()
@@ -91,6 +97,7 @@ func newTestAPI(t *testing.T, cfg testhelpers.APIConfig) *testhelpers.APIWrapper
 		},
 		params.NotificationsGetter,
 		params.NotificationsSub,
+		nil, // stateEventsSub
 		params.Gatherer,
 		params.Registerer,
 		nil,                                // statsRenderer
@@ -98,7 +105,10 @@ func newTestAPI(t *testing.T, cfg testhelpers.APIConfig) *testhelpers.APIWrapper
 		nil,                                // acceptRemoteWriteProtoMsgs
 		false,                              // otlpEnabled
 		false,                              // otlpDeltaToCumulative
+		0,                                  // otlpDeltaMaxStale
+		0,                                  // otlpDeltaMaxStreams
 		false,                              // otlpNativeDeltaIngestion
+		false,                              // omPushEnabled
 		false,                              // stZeroIngestionEnabled
 		5*time.Minute,                      // lookbackDelta
 		false,                              // enableTypeAndUnitLabels
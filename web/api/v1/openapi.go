@@ -158,8 +158,8 @@ func (b *OpenAPIBuilder) shouldIncludePathForVersion(path, version string) bool
 	}
 
 	// OpenAPI 3.1 excludes paths that require 3.2 features.
-	// The /notifications/live endpoint uses itemSchema which is a 3.2-only feature.
-	if version == openAPIVersion31 && path == "/notifications/live" {
+	// The SSE endpoints use itemSchema which is a 3.2-only feature.
+	if version == openAPIVersion31 && (path == "/notifications/live" || path == "/state_events") {
 		return false
 	}
 
@@ -237,6 +237,7 @@ func (*OpenAPIBuilder) buildTags(version string) []*base.Tag {
 		{"remote", "Remote Storage", "Remote read and write endpoints."},
 		{"otlp", "OTLP", "OpenTelemetry Protocol metrics ingestion."},
 		{"notifications", "Notifications", "Server notifications and events."},
+		{"state_events", "State Events", "Alert state and target health change events."},
 	}
 
 	tags := make([]*base.Tag, 0, len(tagData))
@@ -313,23 +314,35 @@ func (b *OpenAPIBuilder) getAllPathDefinitions() *orderedmap.Map[string, *v3.Pat
 	paths.Set("/status/flags", b.statusFlagsPath())
 	paths.Set("/status/tsdb", b.statusTSDBPath())
 	paths.Set("/status/tsdb/blocks", b.statusTSDBBlocksPath())
+	paths.Set("/status/tsdb/block_query_stats", b.statusTSDBBlockQueryStatsPath())
 	paths.Set("/status/walreplay", b.statusWALReplayPath())
 	paths.Set("/status/self_metrics", b.statusSelfMetricsPath())
+	paths.Set("/status/sd_updates", b.statusSDUpdatesPath())
 
 	// Admin endpoints.
 	paths.Set("/admin/tsdb/delete_series", b.adminDeleteSeriesPath())
 	paths.Set("/admin/tsdb/clean_tombstones", b.adminCleanTombstonesPath())
 	paths.Set("/admin/tsdb/snapshot", b.adminSnapshotPath())
+	paths.Set("/admin/tsdb/import_block", b.adminImportBlockPath())
+	paths.Set("/admin/tsdb/export", b.adminExportBlockPath())
+	paths.Set("/admin/tsdb/delete_audit", b.adminDeleteAuditPath())
+	paths.Set("/admin/scrape/override_targets", b.adminOverrideScrapeTargetsPath())
+	paths.Set("/admin/scrape/mark_targets_stale", b.adminMarkScrapeTargetsStalePath())
+	paths.Set("/admin/remote_write/test", b.adminRemoteWriteTestPath())
 
 	// Remote endpoints.
 	paths.Set("/read", b.remoteReadPath())
 	paths.Set("/write", b.remoteWritePath())
 	paths.Set("/otlp/v1/metrics", b.otlpWritePath())
+	paths.Set("/push/metrics/{labels}", b.openMetricsPushPath())
 
 	// Notifications endpoints.
 	paths.Set("/notifications", b.notificationsPath())
 	paths.Set("/notifications/live", b.notificationsLivePath())
 
+	// State events endpoint.
+	paths.Set("/state_events", b.stateEventsPath())
+
 	// Features endpoint.
 	paths.Set("/features", b.featuresPath())
 
@@ -132,14 +132,17 @@ func extractRoutesFromRegister(t *testing.T, source string) []routeInfo {
 	return routes
 }
 
-// normalizePathForOpenAPI converts route paths with colon parameters to OpenAPI format.
+// normalizePathForOpenAPI converts route paths with colon or catch-all
+// parameters to OpenAPI format.
 // e.g., "/label/:name/values" -> "/label/{name}/values".
 func normalizePathForOpenAPI(path string) string {
-	// Replace :param with {param}.
+	// Replace :param and *param with {param}.
 	parts := strings.Split(path, "/")
 	for i, part := range parts {
 		if trimmed, ok := strings.CutPrefix(part, ":"); ok {
 			parts[i] = "{" + trimmed + "}"
+		} else if trimmed, ok := strings.CutPrefix(part, "*"); ok {
+			parts[i] = "{" + trimmed + "}"
 		}
 	}
 	return strings.Join(parts, "/")
@@ -184,7 +187,7 @@ func TestOpenAPICoverage(t *testing.T) {
 	// Check coverage for each route.
 	var missingRoutes []string
 	ignoredRoutes := map[string]bool{
-		"/*path:OPTIONS":          true, // Wildcard OPTIONS handler.
+		"/{path}:OPTIONS":         true, // Wildcard OPTIONS handler.
 		"/openapi.yaml:GET":       true, // Self-referential endpoint.
 		"/notifications/live:GET": true, // SSE endpoint (version-specific).
 	}
@@ -108,6 +108,7 @@ func (*OpenAPIBuilder) queryExemplarsPath() *v3.PathItem {
 func (*OpenAPIBuilder) formatQueryPath() *v3.PathItem {
 	params := []*v3.Parameter{
 		queryParamWithExample("query", "PromQL expression to format.", true, stringSchema(), []example{{"example", "sum(rate(http_requests_total[5m])) by (job)"}}),
+		queryParamWithExample("analyze", "Also return static analysis results for the query. Optional.", false, booleanSchema(), []example{{"example", true}}),
 	}
 	return &v3.PathItem{
 		Get: &v3.Operation{
@@ -549,6 +550,17 @@ func (*OpenAPIBuilder) statusTSDBBlocksPath() *v3.PathItem {
 	}
 }
 
+func (*OpenAPIBuilder) statusTSDBBlockQueryStatsPath() *v3.PathItem {
+	return &v3.PathItem{
+		Get: &v3.Operation{
+			OperationId: "status-tsdb-block-query-stats",
+			Summary:     "Get per-block query statistics",
+			Tags:        []string{"status"},
+			Responses:   responsesWithErrorExamples("StatusTSDBBlockQueryStatsOutputBody", statusTSDBBlockQueryStatsResponseExamples(), errorResponseExamples(), "Per-block query statistics retrieved successfully.", "Error retrieving per-block query statistics."),
+		},
+	}
+}
+
 func (*OpenAPIBuilder) statusWALReplayPath() *v3.PathItem {
 	return &v3.PathItem{
 		Get: &v3.Operation{
@@ -576,6 +588,18 @@ func (*OpenAPIBuilder) statusSelfMetricsPath() *v3.PathItem {
 	}
 }
 
+func (*OpenAPIBuilder) statusSDUpdatesPath() *v3.PathItem {
+	return &v3.PathItem{
+		Get: &v3.Operation{
+			OperationId: "get-status-sd-updates",
+			Summary:     "Get recent service discovery updates",
+			Description: "Returns the most recent service discovery updates per provider, with the target groups, targets and labels each update changed. Intended for debugging why targets appeared or disappeared.",
+			Tags:        []string{"status"},
+			Responses:   responsesWithErrorExamples("StatusSDUpdatesOutputBody", statusSDUpdatesResponseExamples(), errorResponseExamples(), "Service discovery updates retrieved successfully.", "Error retrieving service discovery updates."),
+		},
+	}
+}
+
 func (*OpenAPIBuilder) adminDeleteSeriesPath() *v3.PathItem {
 	params := []*v3.Parameter{
 		queryParamWithExample("match[]", "Series selectors to identify series to delete.", true, base.CreateSchemaProxy(&base.Schema{
@@ -648,6 +672,152 @@ func (*OpenAPIBuilder) adminSnapshotPath() *v3.PathItem {
 	}
 }
 
+func (*OpenAPIBuilder) adminImportBlockPath() *v3.PathItem {
+	return &v3.PathItem{
+		Post: &v3.Operation{
+			OperationId: "importBlockPost",
+			Summary:     "Import a finished TSDB block",
+			Description: "Uploads a tar archive of a compacted block and installs it into the data directory after validation and overlap checks.",
+			Tags:        []string{"admin"},
+			Responses:   responsesWithErrorExamples("ImportBlockOutputBody", importBlockResponseExamples(), errorResponseExamples(), "Block imported successfully.", "Error importing block."),
+		},
+	}
+}
+
+func (*OpenAPIBuilder) adminExportBlockPath() *v3.PathItem {
+	params := []*v3.Parameter{
+		queryParamWithExample("match[]", "Series selectors identifying the series to export. Series matching any of the selectors are included.", true, base.CreateSchemaProxy(&base.Schema{
+			Type:  []string{"array"},
+			Items: &base.DynamicValue[*base.SchemaProxy, bool]{A: stringSchema()},
+		}), []example{{"example", []string{"{job=\"api\"}"}}}),
+		queryParamWithExample("start", "Start timestamp for the export.", false, timestampSchema(), timestampExamples(exampleTime.Add(-1*time.Hour))),
+		queryParamWithExample("end", "End timestamp for the export.", false, timestampSchema(), timestampExamples(exampleTime)),
+	}
+	content := orderedmap.New[string, *v3.MediaType]()
+	content.Set("application/x-tar", &v3.MediaType{
+		Schema: base.CreateSchemaProxy(&base.Schema{
+			Type:        []string{"string"},
+			Format:      "binary",
+			Description: "Tar archive of a single TSDB block, accepted by the import_block endpoint.",
+		}),
+	})
+	codes := orderedmap.New[string, *v3.Response]()
+	codes.Set("200", &v3.Response{
+		Description: "Block exported successfully.",
+		Content:     content,
+	})
+	codes.Set("default", errorResponse())
+	return &v3.PathItem{
+		Get: &v3.Operation{
+			OperationId: "exportBlockGet",
+			Summary:     "Export selected series as a block archive",
+			Description: "Writes all data for the selected series and time range into a single block and streams it as a tar archive importable via the import_block endpoint.",
+			Tags:        []string{"admin"},
+			Parameters:  params,
+			Responses:   &v3.Responses{Codes: codes},
+		},
+		Post: &v3.Operation{
+			OperationId: "exportBlockPost",
+			Summary:     "Export selected series as a block archive via POST",
+			Description: "Writes all data for the selected series and time range into a single block and streams it as a tar archive using POST method.",
+			Tags:        []string{"admin"},
+			Parameters:  params,
+			Responses:   &v3.Responses{Codes: codes},
+		},
+	}
+}
+
+func (*OpenAPIBuilder) adminDeleteAuditPath() *v3.PathItem {
+	return &v3.PathItem{
+		Get: &v3.Operation{
+			OperationId: "deleteAuditLog",
+			Summary:     "Get the delete audit log",
+			Description: "Returns recorded delete and tombstone-clean actions, oldest first.",
+			Tags:        []string{"admin"},
+			Responses:   responsesWithErrorExamples("DeleteAuditLogOutputBody", deleteAuditResponseExamples(), errorResponseExamples(), "Delete audit log returned successfully.", "Error reading the delete audit log."),
+		},
+	}
+}
+
+func (*OpenAPIBuilder) adminOverrideScrapeTargetsPath() *v3.PathItem {
+	params := []*v3.Parameter{
+		queryParamWithExample("match[]", "Series selectors matched against target labels to identify the targets to override.", true, base.CreateSchemaProxy(&base.Schema{
+			Type:  []string{"array"},
+			Items: &base.DynamicValue[*base.SchemaProxy, bool]{A: stringSchema()},
+		}), []example{{"example", []string{"{instance=\"localhost:9090\"}"}}}),
+		queryParamWithExample("scrape_pool", "Restrict the override to targets of this scrape pool.", false, stringSchema(), []example{{"example", "prometheus"}}),
+		queryParamWithExample("interval", "Temporary scrape interval override.", false, stringSchema(), []example{{"example", "5m"}}),
+		queryParamWithExample("timeout", "Temporary scrape timeout override.", false, stringSchema(), []example{{"example", "30s"}}),
+		queryParamWithExample("pause", "If true, pause scraping the matched targets.", false, stringSchema(), []example{{"example", "true"}}),
+		queryParamWithExample("clear", "If true, clear previously applied overrides from the matched targets.", false, stringSchema(), []example{{"example", "false"}}),
+	}
+	return &v3.PathItem{
+		Post: &v3.Operation{
+			OperationId: "overrideScrapeTargetsPost",
+			Summary:     "Temporarily override scrape parameters of targets",
+			Description: "Applies an in-memory override of the scrape interval or timeout, or pauses scraping, for matching targets without a configuration reload. Overrides do not survive a restart or a reload of the target's scrape pool.",
+			Tags:        []string{"admin"},
+			Parameters:  params,
+			Responses:   responsesWithErrorExamples("OverrideScrapeTargetsOutputBody", overrideScrapeTargetsResponseExamples(), errorResponseExamples(), "Scrape target overrides applied successfully.", "Error applying scrape target overrides."),
+		},
+		Put: &v3.Operation{
+			OperationId: "overrideScrapeTargetsPut",
+			Summary:     "Temporarily override scrape parameters of targets via PUT",
+			Description: "Applies an in-memory override of the scrape interval or timeout, or pauses scraping, for matching targets without a configuration reload using PUT method.",
+			Tags:        []string{"admin"},
+			Parameters:  params,
+			Responses:   responsesWithErrorExamples("OverrideScrapeTargetsOutputBody", overrideScrapeTargetsResponseExamples(), errorResponseExamples(), "Scrape target overrides applied successfully via PUT.", "Error applying scrape target overrides via PUT."),
+		},
+	}
+}
+
+func (*OpenAPIBuilder) adminMarkScrapeTargetsStalePath() *v3.PathItem {
+	params := []*v3.Parameter{
+		queryParamWithExample("match[]", "Series selectors matched against target labels to identify the targets to mark stale.", true, base.CreateSchemaProxy(&base.Schema{
+			Type:  []string{"array"},
+			Items: &base.DynamicValue[*base.SchemaProxy, bool]{A: stringSchema()},
+		}), []example{{"example", []string{"{instance=\"localhost:9090\"}"}}}),
+		queryParamWithExample("scrape_pool", "Restrict the request to targets of this scrape pool.", false, stringSchema(), []example{{"example", "prometheus"}}),
+	}
+	return &v3.PathItem{
+		Post: &v3.Operation{
+			OperationId: "markScrapeTargetsStalePost",
+			Summary:     "Explicitly mark the series of targets stale",
+			Description: "Writes staleness markers for all series of matching targets, useful for batch jobs and spot instances that are known not to come back. The markers are written asynchronously by the scrape loops of the affected targets.",
+			Tags:        []string{"admin"},
+			Parameters:  params,
+			Responses:   responsesWithErrorExamples("MarkScrapeTargetsStaleOutputBody", markScrapeTargetsStaleResponseExamples(), errorResponseExamples(), "Staleness markers requested successfully.", "Error marking scrape targets stale."),
+		},
+		Put: &v3.Operation{
+			OperationId: "markScrapeTargetsStalePut",
+			Summary:     "Explicitly mark the series of targets stale via PUT",
+			Description: "Writes staleness markers for all series of matching targets using PUT method.",
+			Tags:        []string{"admin"},
+			Parameters:  params,
+			Responses:   responsesWithErrorExamples("MarkScrapeTargetsStaleOutputBody", markScrapeTargetsStaleResponseExamples(), errorResponseExamples(), "Staleness markers requested successfully via PUT.", "Error marking scrape targets stale via PUT."),
+		},
+	}
+}
+
+func (*OpenAPIBuilder) adminRemoteWriteTestPath() *v3.PathItem {
+	return &v3.PathItem{
+		Post: &v3.Operation{
+			OperationId: "remoteWriteTestPost",
+			Summary:     "Test the configured remote write endpoints",
+			Description: "Sends a small synthetic write to every configured remote write endpoint and reports per-endpoint authentication, TLS and protocol negotiation results.",
+			Tags:        []string{"admin"},
+			Responses:   responsesWithErrorExamples("RemoteWriteTestOutputBody", remoteWriteTestResponseExamples(), errorResponseExamples(), "Remote write endpoints tested successfully.", "Error testing remote write endpoints."),
+		},
+		Put: &v3.Operation{
+			OperationId: "remoteWriteTestPut",
+			Summary:     "Test the configured remote write endpoints via PUT",
+			Description: "Sends a small synthetic write to every configured remote write endpoint using PUT method.",
+			Tags:        []string{"admin"},
+			Responses:   responsesWithErrorExamples("RemoteWriteTestOutputBody", remoteWriteTestResponseExamples(), errorResponseExamples(), "Remote write endpoints tested successfully via PUT.", "Error testing remote write endpoints via PUT."),
+		},
+	}
+}
+
 func (*OpenAPIBuilder) remoteReadPath() *v3.PathItem {
 	return &v3.PathItem{
 		Post: &v3.Operation{
@@ -684,6 +854,21 @@ func (*OpenAPIBuilder) otlpWritePath() *v3.PathItem {
 	}
 }
 
+func (*OpenAPIBuilder) openMetricsPushPath() *v3.PathItem {
+	return &v3.PathItem{
+		Post: &v3.Operation{
+			OperationId: "openMetricsPush",
+			Summary:     "OpenMetrics push endpoint",
+			Description: "Accepts OpenMetrics text pushes with Pushgateway-style grouping. Pushes are append-only; samples without a timestamp get the time of the push.",
+			Tags:        []string{"remote"},
+			Parameters: []*v3.Parameter{
+				pathParam("labels", "Grouping labels as label name and value pairs, starting with the mandatory job label, e.g. job/batch/instance/host1. The grouping labels override labels with the same name from the pushed body.", stringSchema()),
+			},
+			Responses: responsesNoContent(),
+		},
+	}
+}
+
 func (*OpenAPIBuilder) notificationsPath() *v3.PathItem {
 	return &v3.PathItem{
 		Get: &v3.Operation{
@@ -742,6 +927,56 @@ func (*OpenAPIBuilder) notificationsLivePath() *v3.PathItem {
 	}
 }
 
+// stateEventsPath defines the /state_events endpoint.
+// This endpoint uses OpenAPI 3.2's itemSchema feature for documenting SSE streams.
+// It is excluded from the OpenAPI 3.1 specification.
+func (*OpenAPIBuilder) stateEventsPath() *v3.PathItem {
+	codes := orderedmap.New[string, *v3.Response]()
+	content := orderedmap.New[string, *v3.MediaType]()
+
+	// Create a schema for the SSE message structure.
+	// Each SSE message has a 'data' field containing JSON.
+	sseItemProps := orderedmap.New[string, *base.SchemaProxy]()
+	sseItemProps.Set("data", base.CreateSchemaProxy(&base.Schema{
+		Type:             []string{"string"},
+		Description:      "SSE data field containing JSON-encoded state event.",
+		ContentMediaType: "application/json",
+		ContentSchema:    schemaRef("#/components/schemas/StateEvent"),
+	}))
+
+	content.Set("text/event-stream", &v3.MediaType{
+		// Use ItemSchema (OpenAPI 3.2) instead of Schema to describe each SSE message.
+		ItemSchema: base.CreateSchemaProxy(&base.Schema{
+			Type:                 []string{"object"},
+			Title:                "Server Sent Event Message",
+			Description:          "A single SSE message. The data field contains a JSON-encoded StateEvent object.",
+			Properties:           sseItemProps,
+			Required:             []string{"data"},
+			AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: false},
+		}),
+		Examples: stateEventLiveExamples(),
+	})
+
+	codes.Set("200", &v3.Response{
+		Description: "Server-sent events stream established.",
+		Content:     content,
+	})
+	codes.Set("default", errorResponse())
+
+	return &v3.PathItem{
+		Get: &v3.Operation{
+			OperationId: "state-events",
+			Summary:     "Stream alert state and target health changes via Server-Sent Events",
+			Description: "Subscribe to real-time alert state transitions and target health changes using SSE. Each event contains a JSON-encoded StateEvent object in the data field.",
+			Tags:        []string{"state_events"},
+			Parameters: []*v3.Parameter{
+				queryParamWithExample("type", "Restrict the stream to a single event type. Optional; all events are streamed by default.", false, enumStringSchema("alert", "target"), []example{{"example", "alert"}}),
+			},
+			Responses: &v3.Responses{Codes: codes},
+		},
+	}
+}
+
 func (*OpenAPIBuilder) featuresPath() *v3.PathItem {
 	return &v3.PathItem{
 		Get: &v3.Operation{
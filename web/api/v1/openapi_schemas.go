@@ -113,20 +113,37 @@ func (b *OpenAPIBuilder) buildComponents() *v3.Components {
 	schemas.Set("BlockMeta", b.blockMetaSchema())
 	schemas.Set("StatusTSDBBlocksData", b.statusTSDBBlocksDataSchema())
 	schemas.Set("StatusTSDBBlocksOutputBody", b.refResponseBodySchema("StatusTSDBBlocksData", "Response body for status TSDB blocks endpoint."))
+	schemas.Set("BlockQueryStat", b.blockQueryStatSchema())
+	schemas.Set("StatusTSDBBlockQueryStatsData", b.statusTSDBBlockQueryStatsDataSchema())
+	schemas.Set("StatusTSDBBlockQueryStatsOutputBody", b.refResponseBodySchema("StatusTSDBBlockQueryStatsData", "Response body for status TSDB block query stats endpoint."))
 	schemas.Set("StatusWALReplayData", b.statusWALReplayDataSchema())
 	schemas.Set("StatusWALReplayOutputBody", b.refResponseBodySchema("StatusWALReplayData", "Response body for status WAL replay endpoint."))
 	schemas.Set("StatusSelfMetricsOutputBody", b.simpleResponseBodySchema())
+	schemas.Set("SDTargetDiff", b.sdTargetDiffSchema())
+	schemas.Set("SDGroupDiff", b.sdGroupDiffSchema())
+	schemas.Set("SDUpdateDiff", b.sdUpdateDiffSchema())
+	schemas.Set("StatusSDUpdatesOutputBody", b.statusSDUpdatesOutputBodySchema())
 
 	// Admin schemas.
 	schemas.Set("DeleteSeriesOutputBody", b.statusOnlyResponseBodySchema())
 	schemas.Set("CleanTombstonesOutputBody", b.statusOnlyResponseBodySchema())
 	schemas.Set("DataStruct", b.dataStructSchema())
 	schemas.Set("SnapshotOutputBody", b.refResponseBodySchema("DataStruct", "Response body for snapshot endpoint."))
+	schemas.Set("ImportBlockOutputBody", b.refResponseBodySchema("DataStruct", "Response body for block import endpoint."))
+	schemas.Set("DeleteAuditEntry", b.deleteAuditEntrySchema())
+	schemas.Set("DeleteAuditLogOutputBody", b.deleteAuditLogResponseBodySchema())
+	schemas.Set("OverrideScrapeTargetsOutputBody", b.refResponseBodySchema("DataStruct", "Response body for scrape target override endpoint."))
+	schemas.Set("MarkScrapeTargetsStaleOutputBody", b.refResponseBodySchema("DataStruct", "Response body for the mark scrape targets stale endpoint."))
+	schemas.Set("RemoteWriteCheckResult", b.remoteWriteCheckResultSchema())
+	schemas.Set("RemoteWriteTestOutputBody", b.remoteWriteTestResponseBodySchema())
 
 	// Notification schemas.
 	schemas.Set("Notification", b.notificationSchema())
 	schemas.Set("NotificationsOutputBody", b.notificationArrayResponseBodySchema())
 
+	// State event schema.
+	schemas.Set("StateEvent", b.stateEventSchema())
+
 	// Features schema.
 	schemas.Set("FeaturesOutputBody", b.simpleResponseBodySchema())
 
@@ -305,6 +322,188 @@ func (*OpenAPIBuilder) notificationArrayResponseBodySchema() *base.SchemaProxy {
 	})
 }
 
+func (*OpenAPIBuilder) deleteAuditEntrySchema() *base.SchemaProxy {
+	props := orderedmap.New[string, *base.SchemaProxy]()
+	props.Set("time", integerSchemaWithDescription("When the action completed, in milliseconds since the Unix epoch."))
+	props.Set("action", stringSchemaWithDescription("Either delete or clean_tombstones."))
+	props.Set("source", stringSchemaWithDescription("Remote address of the API request that triggered the action, when known."))
+	props.Set("matchers", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"array"},
+		Description: "Series selectors passed to the delete.",
+		Items:       &base.DynamicValue[*base.SchemaProxy, bool]{A: stringSchema()},
+	}))
+	props.Set("minTime", integerSchemaWithDescription("Lower bound of the deleted time range, in milliseconds since the Unix epoch."))
+	props.Set("maxTime", integerSchemaWithDescription("Upper bound of the deleted time range, in milliseconds since the Unix epoch."))
+	props.Set("seriesAffected", integerSchemaWithDescription("Number of new tombstone intervals the delete created."))
+
+	return base.CreateSchemaProxy(&base.Schema{
+		Type:                 []string{"object"},
+		Description:          "A recorded delete or tombstone-clean action.",
+		AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: false},
+		Required:             []string{"time", "action", "seriesAffected"},
+		Properties:           props,
+	})
+}
+
+func (*OpenAPIBuilder) deleteAuditLogResponseBodySchema() *base.SchemaProxy {
+	props := orderedmap.New[string, *base.SchemaProxy]()
+	props.Set("status", statusSchema())
+	props.Set("data", base.CreateSchemaProxy(&base.Schema{
+		Type:  []string{"array"},
+		Items: &base.DynamicValue[*base.SchemaProxy, bool]{A: schemaRef("#/components/schemas/DeleteAuditEntry")},
+	}))
+	props.Set("warnings", warningsSchema())
+	props.Set("infos", infosSchema())
+
+	return base.CreateSchemaProxy(&base.Schema{
+		Type:                 []string{"object"},
+		Description:          "Response body with the delete audit log.",
+		AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: false},
+		Required:             []string{"status", "data"},
+		Properties:           props,
+	})
+}
+
+func (*OpenAPIBuilder) remoteWriteCheckResultSchema() *base.SchemaProxy {
+	props := orderedmap.New[string, *base.SchemaProxy]()
+	props.Set("name", stringSchemaWithDescription("Name of the remote write config, or a hash prefix if the config is unnamed."))
+	props.Set("url", stringSchemaWithDescription("Redacted endpoint URL."))
+	props.Set("success", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"boolean"},
+		Description: "Whether the endpoint accepted the synthetic write.",
+	}))
+	props.Set("error", stringSchemaWithDescription("Why the synthetic write failed, if it did."))
+
+	return base.CreateSchemaProxy(&base.Schema{
+		Type:                 []string{"object"},
+		Description:          "The outcome of a synthetic write sent to a single remote write endpoint.",
+		AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: false},
+		Required:             []string{"name", "url", "success"},
+		Properties:           props,
+	})
+}
+
+func (*OpenAPIBuilder) remoteWriteTestResponseBodySchema() *base.SchemaProxy {
+	props := orderedmap.New[string, *base.SchemaProxy]()
+	props.Set("status", statusSchema())
+	props.Set("data", base.CreateSchemaProxy(&base.Schema{
+		Type:  []string{"array"},
+		Items: &base.DynamicValue[*base.SchemaProxy, bool]{A: schemaRef("#/components/schemas/RemoteWriteCheckResult")},
+	}))
+	props.Set("warnings", warningsSchema())
+	props.Set("infos", infosSchema())
+
+	return base.CreateSchemaProxy(&base.Schema{
+		Type:                 []string{"object"},
+		Description:          "Response body with per-endpoint remote write check results.",
+		AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: false},
+		Required:             []string{"status", "data"},
+		Properties:           props,
+	})
+}
+
+func (*OpenAPIBuilder) sdTargetDiffSchema() *base.SchemaProxy {
+	props := orderedmap.New[string, *base.SchemaProxy]()
+	props.Set("address", stringSchemaWithDescription("The __address__ label of the target, or its full label set when it has no address."))
+	props.Set("changedLabels", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"array"},
+		Description: "Names of the labels whose values changed, including labels that were added or removed.",
+		Items:       &base.DynamicValue[*base.SchemaProxy, bool]{A: stringSchema()},
+	}))
+
+	return base.CreateSchemaProxy(&base.Schema{
+		Type:                 []string{"object"},
+		Description:          "How the labels of a single discovered target changed in an update.",
+		AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: false},
+		Required:             []string{"address", "changedLabels"},
+		Properties:           props,
+	})
+}
+
+func (*OpenAPIBuilder) sdGroupDiffSchema() *base.SchemaProxy {
+	props := orderedmap.New[string, *base.SchemaProxy]()
+	props.Set("source", stringSchemaWithDescription("Group identifier assigned by the service discovery provider."))
+	props.Set("added", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"boolean"},
+		Description: "Whether the previous update did not contain the group.",
+	}))
+	props.Set("removed", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"boolean"},
+		Description: "Whether the update dropped the group entirely.",
+	}))
+	props.Set("targetsAdded", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"array"},
+		Description: "Addresses of targets that appeared in the group.",
+		Items:       &base.DynamicValue[*base.SchemaProxy, bool]{A: stringSchema()},
+	}))
+	props.Set("targetsRemoved", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"array"},
+		Description: "Addresses of targets that disappeared from the group.",
+		Items:       &base.DynamicValue[*base.SchemaProxy, bool]{A: stringSchema()},
+	}))
+	props.Set("targetsChanged", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"array"},
+		Description: "Targets present in both updates whose labels changed.",
+		Items:       &base.DynamicValue[*base.SchemaProxy, bool]{A: schemaRef("#/components/schemas/SDTargetDiff")},
+	}))
+	props.Set("groupLabelsChanged", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"array"},
+		Description: "Names of the group-wide labels whose values changed.",
+		Items:       &base.DynamicValue[*base.SchemaProxy, bool]{A: stringSchema()},
+	}))
+
+	return base.CreateSchemaProxy(&base.Schema{
+		Type:                 []string{"object"},
+		Description:          "How a single target group changed in a service discovery update.",
+		AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: false},
+		Required:             []string{"source"},
+		Properties:           props,
+	})
+}
+
+func (*OpenAPIBuilder) sdUpdateDiffSchema() *base.SchemaProxy {
+	props := orderedmap.New[string, *base.SchemaProxy]()
+	props.Set("timestamp", stringSchemaWithDescription("When the update was received."))
+	props.Set("provider", stringSchemaWithDescription("Name of the service discovery provider that sent the update."))
+	props.Set("targetSet", stringSchemaWithDescription("Name of the target set, e.g. the scrape job, the update applies to."))
+	props.Set("groups", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"array"},
+		Description: "The target groups the update changed.",
+		Items:       &base.DynamicValue[*base.SchemaProxy, bool]{A: schemaRef("#/components/schemas/SDGroupDiff")},
+	}))
+
+	return base.CreateSchemaProxy(&base.Schema{
+		Type:                 []string{"object"},
+		Description:          "One service discovery update and how it changed the target groups of a target set.",
+		AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: false},
+		Required:             []string{"timestamp", "provider", "targetSet", "groups"},
+		Properties:           props,
+	})
+}
+
+func (*OpenAPIBuilder) statusSDUpdatesOutputBodySchema() *base.SchemaProxy {
+	props := orderedmap.New[string, *base.SchemaProxy]()
+	props.Set("status", statusSchema())
+	props.Set("data", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"object"},
+		Description: "Update diffs keyed by discovery manager name, e.g. scrape or notify.",
+		AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{A: base.CreateSchemaProxy(&base.Schema{
+			Type:  []string{"array"},
+			Items: &base.DynamicValue[*base.SchemaProxy, bool]{A: schemaRef("#/components/schemas/SDUpdateDiff")},
+		})},
+	}))
+	props.Set("warnings", warningsSchema())
+	props.Set("infos", infosSchema())
+
+	return base.CreateSchemaProxy(&base.Schema{
+		Type:                 []string{"object"},
+		Description:          "Response body for the service discovery updates endpoint.",
+		AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: false},
+		Required:             []string{"status", "data"},
+		Properties:           props,
+	})
+}
+
 func (*OpenAPIBuilder) floatSampleSchema() *base.SchemaProxy {
 	props := orderedmap.New[string, *base.SchemaProxy]()
 	props.Set("metric", schemaRef("#/components/schemas/Labels"))
@@ -892,12 +1091,14 @@ func (*OpenAPIBuilder) targetSchema() *base.SchemaProxy {
 	props.Set("scrapePool", stringSchemaWithDescription("Name of the scrape pool."))
 	props.Set("scrapeUrl", stringSchemaWithDescription("URL of the target."))
 	props.Set("globalUrl", stringSchemaWithDescription("Global URL of the target."))
+	props.Set("resolvedAddress", stringSchemaWithDescription("Remote address of the connection used by the most recent scrape, absent if the target was never reached."))
 	props.Set("lastError", stringSchemaWithDescription("Last error message from scraping."))
 	props.Set("lastScrape", dateTimeSchemaWithDescription("Timestamp of the last scrape."))
 	props.Set("lastScrapeDuration", numberSchemaWithDescription("Duration of the last scrape in seconds."))
 	props.Set("health", stringSchemaWithDescription("Health status of the target (up, down, or unknown)."))
 	props.Set("scrapeInterval", stringSchemaWithDescription("Scrape interval for this target."))
 	props.Set("scrapeTimeout", stringSchemaWithDescription("Scrape timeout for this target."))
+	props.Set("effectiveScrapeInterval", stringSchemaWithDescription("Scrape interval currently in effect for this target, which differs from scrapeInterval while scrape failure backoff is active. Absent until the target's scrape loop has started."))
 
 	return base.CreateSchemaProxy(&base.Schema{
 		Type:                 []string{"object"},
@@ -1121,6 +1322,7 @@ func (*OpenAPIBuilder) alertmanagerDiscoverySchema() *base.SchemaProxy {
 func (*OpenAPIBuilder) statusConfigDataSchema() *base.SchemaProxy {
 	props := orderedmap.New[string, *base.SchemaProxy]()
 	props.Set("yaml", stringSchemaWithDescription("Prometheus configuration in YAML format."))
+	props.Set("previousYAML", stringSchemaWithDescription("Previously loaded Prometheus configuration in YAML format, with secrets redacted. Absent until the configuration has been reloaded."))
 
 	return base.CreateSchemaProxy(&base.Schema{
 		Type:                 []string{"object"},
@@ -1346,6 +1548,40 @@ func (*OpenAPIBuilder) statusTSDBBlocksDataSchema() *base.SchemaProxy {
 	})
 }
 
+func (*OpenAPIBuilder) blockQueryStatSchema() *base.SchemaProxy {
+	props := orderedmap.New[string, *base.SchemaProxy]()
+	props.Set("ulid", stringSchemaWithDescription("ULID of the block, or \"head\" for the head block."))
+	props.Set("minTime", integerSchema())
+	props.Set("maxTime", integerSchema())
+	props.Set("queryCount", integerSchema())
+	props.Set("totalDuration", integerSchemaWithDescription("Cumulative time spent querying the block, in nanoseconds."))
+	props.Set("bytesRead", integerSchema())
+
+	return base.CreateSchemaProxy(&base.Schema{
+		Type:                 []string{"object"},
+		Description:          "Query statistics for one block.",
+		AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: false},
+		Required:             []string{"ulid", "minTime", "maxTime", "queryCount", "totalDuration", "bytesRead"},
+		Properties:           props,
+	})
+}
+
+func (*OpenAPIBuilder) statusTSDBBlockQueryStatsDataSchema() *base.SchemaProxy {
+	props := orderedmap.New[string, *base.SchemaProxy]()
+	props.Set("blockQueryStats", base.CreateSchemaProxy(&base.Schema{
+		Type:  []string{"array"},
+		Items: &base.DynamicValue[*base.SchemaProxy, bool]{A: schemaRef("#/components/schemas/BlockQueryStat")},
+	}))
+
+	return base.CreateSchemaProxy(&base.Schema{
+		Type:                 []string{"object"},
+		Description:          "Per-block query statistics.",
+		AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: false},
+		Required:             []string{"blockQueryStats"},
+		Properties:           props,
+	})
+}
+
 func (*OpenAPIBuilder) statusWALReplayDataSchema() *base.SchemaProxy {
 	props := orderedmap.New[string, *base.SchemaProxy]()
 	props.Set("min", integerSchema())
@@ -1388,3 +1624,43 @@ func (*OpenAPIBuilder) notificationSchema() *base.SchemaProxy {
 		Properties:           props,
 	})
 }
+
+func (*OpenAPIBuilder) stateEventSchema() *base.SchemaProxy {
+	alertProps := orderedmap.New[string, *base.SchemaProxy]()
+	alertProps.Set("ruleName", stringSchema())
+	alertProps.Set("labels", schemaRef("#/components/schemas/Labels"))
+	alertProps.Set("annotations", schemaRef("#/components/schemas/Labels"))
+	alertProps.Set("previousState", enumStringSchema("inactive", "pending", "firing"))
+	alertProps.Set("state", enumStringSchema("inactive", "pending", "firing"))
+	alertProps.Set("activeAt", base.CreateSchemaProxy(&base.Schema{Type: []string{"string"}, Format: "date-time"}))
+	alertProps.Set("value", base.CreateSchemaProxy(&base.Schema{Type: []string{"number"}}))
+
+	targetProps := orderedmap.New[string, *base.SchemaProxy]()
+	targetProps.Set("labels", schemaRef("#/components/schemas/Labels"))
+	targetProps.Set("scrapeUrl", stringSchema())
+	targetProps.Set("previousHealth", enumStringSchema("unknown", "up", "down"))
+	targetProps.Set("health", enumStringSchema("unknown", "up", "down"))
+	targetProps.Set("lastError", stringSchema())
+
+	props := orderedmap.New[string, *base.SchemaProxy]()
+	props.Set("type", enumStringSchema("alert", "target"))
+	props.Set("timestamp", base.CreateSchemaProxy(&base.Schema{Type: []string{"string"}, Format: "date-time"}))
+	props.Set("alert", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"object"},
+		Description: "State transition of a single alert instance. Only set for alert events.",
+		Properties:  alertProps,
+	}))
+	props.Set("target", base.CreateSchemaProxy(&base.Schema{
+		Type:        []string{"object"},
+		Description: "Health transition of a single scrape target. Only set for target events.",
+		Properties:  targetProps,
+	}))
+
+	return base.CreateSchemaProxy(&base.Schema{
+		Type:                 []string{"object"},
+		Description:          "Alert state or target health change event.",
+		AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{N: 1, B: false},
+		Required:             []string{"type", "timestamp"},
+		Properties:           props,
+	})
+}
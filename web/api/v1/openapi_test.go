@@ -275,17 +275,19 @@ func TestOpenAPIVersionConsistency(t *testing.T) {
 	require.Equal(t, "3.1.0", spec31["openapi"])
 	require.Equal(t, "3.2.0", spec32["openapi"])
 
-	// Verify /notifications/live is only in 3.2.
+	// Verify the SSE endpoints are only in 3.2.
 	paths31 := spec31["paths"].(map[any]any)
 	paths32 := spec32["paths"].(map[any]any)
 
 	require.NotContains(t, paths31, "/notifications/live")
+	require.NotContains(t, paths31, "/state_events")
 
 	require.Contains(t, paths32, "/notifications/live")
+	require.Contains(t, paths32, "/state_events")
 
-	// Verify 3.2 has exactly one more path than 3.1.
-	require.Len(t, paths32, len(paths31)+1,
-		"OpenAPI 3.2 should have exactly one more path than 3.1")
+	// Verify 3.2 has exactly two more paths than 3.1.
+	require.Len(t, paths32, len(paths31)+2,
+		"OpenAPI 3.2 should have exactly two more paths than 3.1")
 }
 
 // TestOpenAPISearchDefaultLimit verifies that the search endpoint default for "limit"
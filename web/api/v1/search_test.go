@@ -898,6 +898,14 @@ func (c *countingTargetRetriever) ScrapePoolConfig(pool string) (*config.ScrapeC
 	return c.inner.ScrapePoolConfig(pool)
 }
 
+func (c *countingTargetRetriever) OverrideTargets(pool string, matchers []*labels.Matcher, override *scrape.TargetOverride) (int, error) {
+	return c.inner.OverrideTargets(pool, matchers, override)
+}
+
+func (c *countingTargetRetriever) MarkTargetsStale(pool string, matchers []*labels.Matcher) (int, error) {
+	return c.inner.MarkTargetsStale(pool, matchers)
+}
+
 func (c *countingTargetRetriever) TargetsActive() map[string][]*scrape.Target {
 	c.targetsActiveCnt++
 	return c.inner.TargetsActive()
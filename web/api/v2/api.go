@@ -0,0 +1,424 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v2 implements the experimental v2 HTTP API. Unlike v1, responses
+// are served from a protobuf-defined schema (see v2.proto), either
+// proto-encoded or as its JSON mapping, so that result values, warnings,
+// infos and statistics are fully typed and consumers do not need to match on
+// strings.
+package v2
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/common/route"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/util/annotations"
+	"github.com/prometheus/prometheus/util/httputil"
+)
+
+const (
+	contentTypeProto = "application/x-protobuf"
+	contentTypeJSON  = "application/json"
+
+	// statusClientClosedConnection is a non-standard status code introduced by
+	// nginx for the case when a client closes the connection while the server
+	// is still processing the request.
+	statusClientClosedConnection = 499
+)
+
+// API serves the /api/v2 endpoints.
+type API struct {
+	queryEngine promql.QueryEngine
+	queryable   storage.SampleAndChunkQueryable
+
+	now    func() time.Time
+	ready  func(http.HandlerFunc) http.HandlerFunc
+	logger *slog.Logger
+}
+
+// NewAPI returns a v2 API serving queries from the given engine and
+// queryable. The readyFunc wrapper gates requests until the server is ready
+// to serve them.
+func NewAPI(qe promql.QueryEngine, q storage.SampleAndChunkQueryable, readyFunc func(http.HandlerFunc) http.HandlerFunc, logger *slog.Logger) *API {
+	return &API{
+		queryEngine: qe,
+		queryable:   q,
+		now:         time.Now,
+		ready:       readyFunc,
+		logger:      logger,
+	}
+}
+
+// Register registers the v2 API endpoints on the given router.
+func (api *API) Register(r *route.Router) {
+	wrap := func(f func(r *http.Request) (*QueryResponse, int)) http.HandlerFunc {
+		hf := func(w http.ResponseWriter, r *http.Request) {
+			resp, code := f(r)
+			api.respond(w, r, resp, code)
+		}
+		return api.ready(httputil.CompressionHandler{Handler: http.HandlerFunc(hf)}.ServeHTTP)
+	}
+
+	r.Get("/query", wrap(api.query))
+	r.Post("/query", wrap(api.query))
+	r.Get("/query_range", wrap(api.queryRange))
+	r.Post("/query_range", wrap(api.queryRange))
+}
+
+func (api *API) query(r *http.Request) (*QueryResponse, int) {
+	ts, err := parseTimeParam(r, "time", api.now())
+	if err != nil {
+		return invalidParamResponse(err, "time")
+	}
+	ctx := r.Context()
+	if to := r.FormValue("timeout"); to != "" {
+		var cancel context.CancelFunc
+		timeout, err := parseDuration(to)
+		if err != nil {
+			return invalidParamResponse(err, "timeout")
+		}
+
+		ctx, cancel = context.WithDeadline(ctx, api.now().Add(timeout))
+		defer cancel()
+	}
+
+	qry, err := api.queryEngine.NewInstantQuery(ctx, api.queryable, nil, r.FormValue("query"), ts)
+	if err != nil {
+		return invalidParamResponse(err, "query")
+	}
+	defer qry.Close()
+
+	return api.execQuery(httputil.ContextFromRequest(ctx, r), r, qry)
+}
+
+func (api *API) queryRange(r *http.Request) (*QueryResponse, int) {
+	start, err := parseTime(r.FormValue("start"))
+	if err != nil {
+		return invalidParamResponse(err, "start")
+	}
+	end, err := parseTime(r.FormValue("end"))
+	if err != nil {
+		return invalidParamResponse(err, "end")
+	}
+	if end.Before(start) {
+		return invalidParamResponse(errors.New("end timestamp must not be before start time"), "end")
+	}
+
+	step, err := parseDuration(r.FormValue("step"))
+	if err != nil {
+		return invalidParamResponse(err, "step")
+	}
+	if step <= 0 {
+		return invalidParamResponse(errors.New("zero or negative query resolution step widths are not accepted. Try a positive integer"), "step")
+	}
+
+	// For safety, limit the number of returned points per timeseries.
+	// This is sufficient for 60s resolution for a week or 1h resolution for a year.
+	if end.Sub(start)/step > 11000 {
+		return errorResponse(ErrorType_BAD_DATA, errors.New("exceeded maximum resolution of 11,000 points per timeseries. Try decreasing the query resolution (?step=XX)"))
+	}
+
+	ctx := r.Context()
+	if to := r.FormValue("timeout"); to != "" {
+		var cancel context.CancelFunc
+		timeout, err := parseDuration(to)
+		if err != nil {
+			return invalidParamResponse(err, "timeout")
+		}
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	qry, err := api.queryEngine.NewRangeQuery(ctx, api.queryable, nil, r.FormValue("query"), start, end, step)
+	if err != nil {
+		return invalidParamResponse(err, "query")
+	}
+	defer qry.Close()
+
+	return api.execQuery(httputil.ContextFromRequest(ctx, r), r, qry)
+}
+
+// execQuery executes the query and converts the result into the response
+// schema. The conversion copies all values, so the caller can close the query
+// once execQuery returns.
+func (api *API) execQuery(ctx context.Context, r *http.Request, qry promql.Query) (*QueryResponse, int) {
+	res := qry.Exec(ctx)
+	if res.Err != nil {
+		resp, code := errorResponse(returnErrorType(res.Err), res.Err)
+		resp.Warnings, resp.Infos = annotationsToProto(res.Warnings, qry.String())
+		return resp, code
+	}
+
+	data, err := resultToProto(res.Value)
+	if err != nil {
+		return errorResponse(ErrorType_INTERNAL, err)
+	}
+
+	resp := &QueryResponse{
+		Status: Status_SUCCESS,
+		Data:   data,
+	}
+	resp.Warnings, resp.Infos = annotationsToProto(res.Warnings, qry.String())
+
+	if v := r.FormValue("stats"); v != "" {
+		if st := qry.Stats(); st != nil && st.Samples != nil {
+			resp.Stats = &QueryStats{
+				TotalQueryableSamples: st.Samples.TotalSamples,
+				PeakSamples:           int64(st.Samples.PeakSamples),
+			}
+		}
+	}
+
+	return resp, http.StatusOK
+}
+
+func (api *API) respond(w http.ResponseWriter, r *http.Request, resp *QueryResponse, code int) {
+	var (
+		b           []byte
+		err         error
+		contentType string
+	)
+	if acceptsProto(r) {
+		contentType = contentTypeProto
+		b, err = proto.Marshal(resp)
+	} else {
+		contentType = contentTypeJSON
+		var buf bytes.Buffer
+		// The canonical proto JSON mapping, with the original field names of
+		// the schema rather than camel case.
+		m := jsonpb.Marshaler{OrigName: true}
+		err = m.Marshal(&buf, resp)
+		b = buf.Bytes()
+	}
+	if err != nil {
+		api.logger.Error("Error marshaling v2 API response", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	if _, err := w.Write(b); err != nil {
+		api.logger.Error("Error writing v2 API response", "bytesWritten", len(b), "err", err)
+	}
+}
+
+// acceptsProto returns whether the client asked for a proto-encoded response.
+// JSON is the default for any other (or absent) Accept header.
+func acceptsProto(r *http.Request) bool {
+	for part := range strings.SplitSeq(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if mediaType == contentTypeProto {
+			return true
+		}
+	}
+	return false
+}
+
+func invalidParamResponse(err error, parameter string) (*QueryResponse, int) {
+	return errorResponse(ErrorType_BAD_DATA, fmt.Errorf("invalid parameter %q: %w", parameter, err))
+}
+
+func errorResponse(errType ErrorType, err error) (*QueryResponse, int) {
+	var code int
+	switch errType {
+	case ErrorType_BAD_DATA:
+		code = http.StatusBadRequest
+	case ErrorType_EXEC:
+		code = http.StatusUnprocessableEntity
+	case ErrorType_CANCELED:
+		code = statusClientClosedConnection
+	case ErrorType_TIMEOUT, ErrorType_UNAVAILABLE:
+		code = http.StatusServiceUnavailable
+	case ErrorType_NOT_FOUND:
+		code = http.StatusNotFound
+	default:
+		code = http.StatusInternalServerError
+	}
+	return &QueryResponse{
+		Status:    Status_ERROR,
+		ErrorType: errType,
+		Error:     err.Error(),
+	}, code
+}
+
+// returnErrorType categorizes a query execution error, mirroring the
+// classification of the v1 API.
+func returnErrorType(err error) ErrorType {
+	var eqc promql.ErrQueryCanceled
+	var eqt promql.ErrQueryTimeout
+	var es promql.ErrStorage
+	switch {
+	case errors.As(err, &eqc), errors.Is(err, context.Canceled):
+		return ErrorType_CANCELED
+	case errors.As(err, &eqt):
+		return ErrorType_TIMEOUT
+	case errors.As(err, &es):
+		return ErrorType_INTERNAL
+	}
+	return ErrorType_EXEC
+}
+
+// annotationsToProto splits the annotations of a query into typed warnings
+// and infos. The query string provides position information.
+func annotationsToProto(annos annotations.Annotations, query string) (warnings, infos []*Annotation) {
+	warnStrings, infoStrings := annos.AsStrings(query, 0, 0)
+	for _, w := range warnStrings {
+		warnings = append(warnings, &Annotation{Severity: Severity_WARNING, Message: w})
+	}
+	for _, i := range infoStrings {
+		infos = append(infos, &Annotation{Severity: Severity_INFO, Message: i})
+	}
+	return warnings, infos
+}
+
+func resultToProto(v parser.Value) (*QueryResult, error) {
+	switch v := v.(type) {
+	case promql.Scalar:
+		return &QueryResult{Result: &QueryResult_Scalar{Scalar: &Scalar{TimestampMs: v.T, Value: v.V}}}, nil
+	case promql.String:
+		return &QueryResult{Result: &QueryResult_String_{String_: &StringValue{TimestampMs: v.T, Value: v.V}}}, nil
+	case promql.Vector:
+		vec := &Vector{Samples: make([]*Sample, 0, len(v))}
+		for _, s := range v {
+			vec.Samples = append(vec.Samples, &Sample{
+				Labels: labelsToProto(s.Metric),
+				Point:  &Point{TimestampMs: s.T, Value: s.F, Histogram: histogramToProto(s.H)},
+			})
+		}
+		return &QueryResult{Result: &QueryResult_Vector{Vector: vec}}, nil
+	case promql.Matrix:
+		m := &Matrix{Series: make([]*Series, 0, len(v))}
+		for _, s := range v {
+			m.Series = append(m.Series, &Series{
+				Labels: labelsToProto(s.Metric),
+				Points: pointsToProto(s),
+			})
+		}
+		return &QueryResult{Result: &QueryResult_Matrix{Matrix: m}}, nil
+	default:
+		return nil, fmt.Errorf("unexpected result value type %T", v)
+	}
+}
+
+func labelsToProto(lbls labels.Labels) []*Label {
+	res := make([]*Label, 0, lbls.Len())
+	lbls.Range(func(l labels.Label) {
+		res = append(res, &Label{Name: l.Name, Value: l.Value})
+	})
+	return res
+}
+
+// pointsToProto merges the float and histogram points of a series into a
+// single slice ordered by timestamp.
+func pointsToProto(s promql.Series) []*Point {
+	points := make([]*Point, 0, len(s.Floats)+len(s.Histograms))
+	f, h := 0, 0
+	for f < len(s.Floats) || h < len(s.Histograms) {
+		if h >= len(s.Histograms) || (f < len(s.Floats) && s.Floats[f].T <= s.Histograms[h].T) {
+			points = append(points, &Point{TimestampMs: s.Floats[f].T, Value: s.Floats[f].F})
+			f++
+		} else {
+			points = append(points, &Point{TimestampMs: s.Histograms[h].T, Histogram: histogramToProto(s.Histograms[h].H)})
+			h++
+		}
+	}
+	return points
+}
+
+func histogramToProto(h *histogram.FloatHistogram) *FloatHistogram {
+	if h == nil {
+		return nil
+	}
+	res := &FloatHistogram{
+		Count:          h.Count,
+		Sum:            h.Sum,
+		Schema:         h.Schema,
+		ZeroThreshold:  h.ZeroThreshold,
+		ZeroCount:      h.ZeroCount,
+		NegativeSpans:  spansToProto(h.NegativeSpans),
+		NegativeCounts: append([]float64(nil), h.NegativeBuckets...),
+		PositiveSpans:  spansToProto(h.PositiveSpans),
+		PositiveCounts: append([]float64(nil), h.PositiveBuckets...),
+		CustomValues:   append([]float64(nil), h.CustomValues...),
+	}
+	return res
+}
+
+func spansToProto(spans []histogram.Span) []*BucketSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+	res := make([]*BucketSpan, 0, len(spans))
+	for _, s := range spans {
+		res = append(res, &BucketSpan{Offset: s.Offset, Length: s.Length})
+	}
+	return res
+}
+
+func parseTimeParam(r *http.Request, paramName string, defaultValue time.Time) (time.Time, error) {
+	val := r.FormValue(paramName)
+	if val == "" {
+		return defaultValue, nil
+	}
+	result, err := parseTime(val)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time value for '%s': %w", paramName, err)
+	}
+	return result, nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	if t, err := strconv.ParseFloat(s, 64); err == nil {
+		s, ns := math.Modf(t)
+		ns = math.Round(ns*1000) / 1000
+		return time.Unix(int64(s), int64(ns*float64(time.Second))).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp", s)
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if d, err := strconv.ParseFloat(s, 64); err == nil {
+		ts := d * float64(time.Second)
+		if ts > float64(math.MaxInt64) || ts < float64(math.MinInt64) {
+			return 0, fmt.Errorf("cannot parse %q to a valid duration. It overflows int64", s)
+		}
+		return time.Duration(ts), nil
+	}
+	if d, err := model.ParseDuration(s); err == nil {
+		return time.Duration(d), nil
+	}
+	return 0, fmt.Errorf("cannot parse %q to a valid duration", s)
+}
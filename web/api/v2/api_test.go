@@ -0,0 +1,230 @@
+// Copyright The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/prometheus/common/promslog"
+	"github.com/prometheus/common/route"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/promqltest"
+)
+
+func testAPIRouter(t *testing.T) *route.Router {
+	t.Helper()
+
+	storage := promqltest.LoadedStorage(t, `
+		load 1m
+			http_requests{job="api", instance="0"} 0+100x10
+	`)
+	t.Cleanup(func() { storage.Close() })
+
+	engine := promqltest.NewTestEngineWithOpts(t, promql.EngineOpts{
+		MaxSamples: 10000,
+		Timeout:    100 * time.Second,
+	})
+
+	api := NewAPI(engine, storage, func(f http.HandlerFunc) http.HandlerFunc { return f }, promslog.NewNopLogger())
+	router := route.New()
+	api.Register(router)
+	return router
+}
+
+// doProto performs a request asking for a proto-encoded response and decodes
+// it.
+func doProto(t *testing.T, router *route.Router, target string) (*QueryResponse, int) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Accept", contentTypeProto)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, contentTypeProto, rec.Result().Header.Get("Content-Type"))
+	resp := &QueryResponse{}
+	require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), resp))
+	return resp, rec.Result().StatusCode
+}
+
+func TestQueryEndpoints(t *testing.T) {
+	router := testAPIRouter(t)
+
+	for _, tc := range []struct {
+		name     string
+		target   string
+		wantCode int
+		want     *QueryResponse
+	}{
+		{
+			name:     "scalar",
+			target:   "/query?query=2&time=123",
+			wantCode: http.StatusOK,
+			want: &QueryResponse{
+				Status: Status_SUCCESS,
+				Data: &QueryResult{Result: &QueryResult_Scalar{
+					Scalar: &Scalar{TimestampMs: 123000, Value: 2},
+				}},
+			},
+		},
+		{
+			name:     "string",
+			target:   "/query?query=%22a%22&time=123",
+			wantCode: http.StatusOK,
+			want: &QueryResponse{
+				Status: Status_SUCCESS,
+				Data: &QueryResult{Result: &QueryResult_String_{
+					String_: &StringValue{TimestampMs: 123000, Value: "a"},
+				}},
+			},
+		},
+		{
+			name:     "vector",
+			target:   "/query?query=http_requests&time=120",
+			wantCode: http.StatusOK,
+			want: &QueryResponse{
+				Status: Status_SUCCESS,
+				Data: &QueryResult{Result: &QueryResult_Vector{
+					Vector: &Vector{Samples: []*Sample{
+						{
+							Labels: []*Label{
+								{Name: "__name__", Value: "http_requests"},
+								{Name: "instance", Value: "0"},
+								{Name: "job", Value: "api"},
+							},
+							Point: &Point{TimestampMs: 120000, Value: 200},
+						},
+					}},
+				}},
+			},
+		},
+		{
+			name:     "matrix",
+			target:   "/query_range?query=http_requests&start=0&end=120&step=60",
+			wantCode: http.StatusOK,
+			want: &QueryResponse{
+				Status: Status_SUCCESS,
+				Data: &QueryResult{Result: &QueryResult_Matrix{
+					Matrix: &Matrix{Series: []*Series{
+						{
+							Labels: []*Label{
+								{Name: "__name__", Value: "http_requests"},
+								{Name: "instance", Value: "0"},
+								{Name: "job", Value: "api"},
+							},
+							Points: []*Point{
+								{TimestampMs: 0, Value: 0},
+								{TimestampMs: 60000, Value: 100},
+								{TimestampMs: 120000, Value: 200},
+							},
+						},
+					}},
+				}},
+			},
+		},
+		{
+			name:     "invalid query expression",
+			target:   "/query?query=invalid%7D&time=123",
+			wantCode: http.StatusBadRequest,
+			want: &QueryResponse{
+				Status:    Status_ERROR,
+				ErrorType: ErrorType_BAD_DATA,
+				Error:     `invalid parameter "query": 1:8: parse error: unexpected character: '}'`,
+			},
+		},
+		{
+			name:     "invalid time",
+			target:   "/query?query=2&time=baz",
+			wantCode: http.StatusBadRequest,
+			want: &QueryResponse{
+				Status:    Status_ERROR,
+				ErrorType: ErrorType_BAD_DATA,
+				Error:     `invalid parameter "time": invalid time value for 'time': cannot parse "baz" to a valid timestamp`,
+			},
+		},
+		{
+			name:     "zero step",
+			target:   "/query_range?query=2&start=0&end=120&step=0",
+			wantCode: http.StatusBadRequest,
+			want: &QueryResponse{
+				Status:    Status_ERROR,
+				ErrorType: ErrorType_BAD_DATA,
+				Error:     `invalid parameter "step": zero or negative query resolution step widths are not accepted. Try a positive integer`,
+			},
+		},
+		{
+			name:     "end before start",
+			target:   "/query_range?query=2&start=120&end=0&step=60",
+			wantCode: http.StatusBadRequest,
+			want: &QueryResponse{
+				Status:    Status_ERROR,
+				ErrorType: ErrorType_BAD_DATA,
+				Error:     `invalid parameter "end": end timestamp must not be before start time`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, code := doProto(t, router, tc.target)
+			require.Equal(t, tc.wantCode, code)
+			require.Equal(t, tc.want, resp)
+		})
+	}
+}
+
+func TestQueryAnnotations(t *testing.T) {
+	router := testAPIRouter(t)
+
+	// Applying rate to a metric whose name does not look like a counter
+	// produces an info-level annotation.
+	resp, code := doProto(t, router, "/query?query=rate(http_requests%5B2m%5D)&time=120")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, Status_SUCCESS, resp.Status)
+	require.Empty(t, resp.Warnings)
+	require.Len(t, resp.Infos, 1)
+	require.Equal(t, Severity_INFO, resp.Infos[0].Severity)
+	require.Contains(t, resp.Infos[0].Message, "metric might not be a counter")
+}
+
+func TestQueryStats(t *testing.T) {
+	router := testAPIRouter(t)
+
+	resp, code := doProto(t, router, "/query?query=http_requests&time=120")
+	require.Equal(t, http.StatusOK, code)
+	require.Nil(t, resp.Stats)
+
+	resp, code = doProto(t, router, "/query?query=http_requests&time=120&stats=all")
+	require.Equal(t, http.StatusOK, code)
+	require.NotNil(t, resp.Stats)
+	require.Equal(t, int64(1), resp.Stats.TotalQueryableSamples)
+	require.Positive(t, resp.Stats.PeakSamples)
+}
+
+func TestQueryJSONResponse(t *testing.T) {
+	router := testAPIRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/query?query=2&time=1", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	require.Equal(t, contentTypeJSON, rec.Result().Header.Get("Content-Type"))
+	// Per the proto3 JSON mapping, int64 values are encoded as strings.
+	require.JSONEq(t, `{"data":{"scalar":{"timestamp_ms":"1000","value":2}}}`, rec.Body.String())
+}
@@ -2,4 +2,6 @@
 // See: https://prometheus.io/docs/prometheus/latest/querying/api/#config
 export default interface ConfigResult {
   yaml: string;
+  // Only present once the configuration has been reloaded at least once.
+  previousYAML?: string;
 }
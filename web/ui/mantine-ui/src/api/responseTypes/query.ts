@@ -23,9 +23,17 @@ export interface RangeSamples {
 export type SampleValue = [number, string];
 export type SampleHistogram = [number, Histogram];
 
+export type OperationStat = {
+  operation: string;
+  count: number;
+  time: number;
+  samples: number;
+};
+
 export type QueryStats = {
   timings: Record<string, number>;
   samples: Record<string, number>;
+  operations?: OperationStat[];
 };
 
 // Result type for /api/v1/query endpoint.
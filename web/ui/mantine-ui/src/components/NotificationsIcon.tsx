@@ -21,7 +21,7 @@ import { formatTimestamp } from "../lib/formatTime";
 
 const NotificationsIcon = () => {
   const { notifications, isConnectionError } = useNotifications();
-  const { useLocalTime } = useSettings();
+  const { displayTimezone } = useSettings();
 
   return notifications.length === 0 && !isConnectionError ? null : (
     <Indicator
@@ -87,7 +87,7 @@ const NotificationsIcon = () => {
                         <Text size="xs" c="dimmed">
                           {formatTimestamp(
                             new Date(notification.date).valueOf() / 1000,
-                            useLocalTime
+                            displayTimezone
                           )}
                         </Text>
                       </Stack>
@@ -12,10 +12,11 @@ import { FC } from "react";
 import { useAppDispatch } from "../state/hooks";
 import { updateSettings, useSettings } from "../state/settingsSlice";
 import { actionIconStyle } from "../styles";
+import TimezoneSelect from "./TimezoneSelect";
 
 const SettingsMenu: FC = () => {
   const {
-    useLocalTime,
+    displayTimezone,
     enableQueryHistory,
     enableAutocomplete,
     enableSyntaxHighlighting,
@@ -44,16 +45,18 @@ const SettingsMenu: FC = () => {
         <Group align="flex-start">
           <Stack>
             <Fieldset p="md" legend="Global settings">
-              <Checkbox
-                checked={useLocalTime}
-                label="Use local time"
-                onChange={(event) =>
-                  dispatch(
-                    updateSettings({
-                      useLocalTime: event.currentTarget.checked,
-                    })
-                  )
-                }
+              <TimezoneSelect
+                label="Display timezone"
+                value={displayTimezone}
+                onChange={(value) => {
+                  if (value !== null) {
+                    dispatch(
+                      updateSettings({
+                        displayTimezone: value,
+                      })
+                    );
+                  }
+                }}
               />
             </Fieldset>
 
@@ -0,0 +1,28 @@
+import { Select, SelectProps } from "@mantine/core";
+import { FC } from "react";
+
+// Intl.supportedValuesOf() is not part of the ES2020 TS lib yet, but it is
+// available in all browsers that we support.
+const supportedTimezones: string[] =
+  (
+    Intl as typeof Intl & {
+      supportedValuesOf?: (key: "timeZone") => string[];
+    }
+  ).supportedValuesOf?.("timeZone") ?? [];
+
+const timezoneData = [
+  { value: "local", label: "Browser time" },
+  { value: "UTC", label: "UTC" },
+  ...supportedTimezones
+    .filter((tz) => tz !== "UTC")
+    .map((tz) => ({ value: tz, label: tz })),
+];
+
+// TimezoneSelect is a searchable dropdown for choosing a display timezone,
+// offering all IANA timezones known to the browser as well as the special
+// values "local" (browser timezone) and "UTC".
+const TimezoneSelect: FC<Omit<SelectProps, "data">> = (props) => (
+  <Select searchable data={timezoneData} {...props} />
+);
+
+export default TimezoneSelect;
@@ -0,0 +1,19 @@
+import { diffLines } from "./diffLines";
+
+describe("diffLines", () => {
+  test("marks all lines unchanged for identical inputs", () => {
+    expect(diffLines("a\nb", "a\nb")).toBe(" a\n b");
+  });
+
+  test("marks changed lines with - and +", () => {
+    expect(diffLines("a\nb\nc", "a\nx\nc")).toBe(" a\n-b\n+x\n c");
+  });
+
+  test("handles additions and removals at the edges", () => {
+    expect(diffLines("b\nc", "a\nb")).toBe("+a\n b\n-c");
+  });
+
+  test("handles insertion into an empty text", () => {
+    expect(diffLines("", "a")).toBe("-\n+a");
+  });
+});
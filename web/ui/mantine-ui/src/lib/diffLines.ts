@@ -0,0 +1,68 @@
+// Computes a unified-style line diff between two texts, without hunk headers.
+// Unchanged lines are prefixed with a space, removed lines with "-" and added
+// lines with "+", so the result can be rendered as a "diff" code block.
+export const diffLines = (oldText: string, newText: string): string => {
+  const a = oldText.split("\n");
+  const b = newText.split("\n");
+
+  // Trim the common prefix and suffix to keep the LCS table small for the
+  // typical case of a few localized changes in a large configuration.
+  let start = 0;
+  while (start < a.length && start < b.length && a[start] === b[start]) {
+    start++;
+  }
+  let endA = a.length;
+  let endB = b.length;
+  while (endA > start && endB > start && a[endA - 1] === b[endB - 1]) {
+    endA--;
+    endB--;
+  }
+
+  const midA = a.slice(start, endA);
+  const midB = b.slice(start, endB);
+  const n = midA.length;
+  const m = midB.length;
+
+  // Longest common subsequence lengths for the changed region.
+  const lcs: number[][] = Array.from({ length: n + 1 }, () =>
+    new Array<number>(m + 1).fill(0)
+  );
+  for (let i = n - 1; i >= 0; i--) {
+    for (let j = m - 1; j >= 0; j--) {
+      lcs[i][j] =
+        midA[i] === midB[j]
+          ? lcs[i + 1][j + 1] + 1
+          : Math.max(lcs[i + 1][j], lcs[i][j + 1]);
+    }
+  }
+
+  const out: string[] = a.slice(0, start).map((l) => " " + l);
+  let i = 0;
+  let j = 0;
+  while (i < n && j < m) {
+    if (midA[i] === midB[j]) {
+      out.push(" " + midA[i]);
+      i++;
+      j++;
+    } else if (lcs[i + 1][j] >= lcs[i][j + 1]) {
+      out.push("-" + midA[i]);
+      i++;
+    } else {
+      out.push("+" + midB[j]);
+      j++;
+    }
+  }
+  while (i < n) {
+    out.push("-" + midA[i]);
+    i++;
+  }
+  while (j < m) {
+    out.push("+" + midB[j]);
+    j++;
+  }
+  for (const l of a.slice(endA)) {
+    out.push(" " + l);
+  }
+
+  return out.join("\n");
+};
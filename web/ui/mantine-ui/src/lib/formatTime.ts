@@ -3,6 +3,8 @@ import duration from "dayjs/plugin/duration";
 dayjs.extend(duration);
 import utc from "dayjs/plugin/utc";
 dayjs.extend(utc);
+import timezonePlugin from "dayjs/plugin/timezone";
+dayjs.extend(timezonePlugin);
 
 // Parse Prometheus-specific duration strings such as "5m" or "1d2h3m4s" into milliseconds.
 export const parsePrometheusDuration = (durationStr: string): number | null => {
@@ -121,7 +123,33 @@ export const humanizeDurationRelative = (
   return humanizeDuration(end - start) + suffix;
 };
 
-export const formatTimestamp = (t: number, useLocalTime: boolean) =>
-  useLocalTime
-    ? dayjs.unix(t).tz(dayjs.tz.guess()).format()
-    : dayjs.unix(t).utc().format();
+// Resolve a display timezone setting value ("local", "UTC" or an IANA
+// timezone name) to a concrete IANA timezone name.
+export const resolveTimezone = (timezone: string): string =>
+  timezone === "local" ? dayjs.tz.guess() : timezone;
+
+// Offset of the display timezone from UTC in minutes at time t (milliseconds).
+const timezoneOffset = (timezone: string, t: number): number =>
+  timezone === "local"
+    ? dayjs(t).utcOffset()
+    : dayjs(t).tz(timezone).utcOffset();
+
+export const formatTimestamp = (t: number, timezone: string) =>
+  timezone === "UTC"
+    ? dayjs.unix(t).utc().format()
+    : dayjs.unix(t).tz(resolveTimezone(timezone)).format();
+
+// The date picker inputs always render and parse wall-clock times in the
+// browser timezone. These helpers shift timestamps so that the picker shows
+// (and reads back) the wall-clock time of the display timezone instead.
+export const formatPickerTime = (t: number, timezone: string): string =>
+  dayjs(t)
+    .add(timezoneOffset(timezone, t) - dayjs(t).utcOffset(), "minutes")
+    .format();
+
+export const parsePickerTime = (value: string, timezone: string): number => {
+  const local = dayjs(value);
+  return local
+    .subtract(timezoneOffset(timezone, local.valueOf()) - local.utcOffset(), "minutes")
+    .valueOf();
+};
@@ -1,23 +1,60 @@
+import { useState } from "react";
+import { Group, SegmentedControl, Text } from "@mantine/core";
 import { CodeHighlight } from "@mantine/code-highlight";
 import { useSuspenseAPIQuery } from "../api/api";
 import ConfigResult from "../api/responseTypes/config";
+import { diffLines } from "../lib/diffLines";
+import { formatTimestamp } from "../lib/formatTime";
+import { useSettings } from "../state/settingsSlice";
 
 export default function ConfigPage() {
   const {
     data: {
-      data: { yaml },
+      data: { yaml, previousYAML },
     },
   } = useSuspenseAPIQuery<ConfigResult>({ path: `/status/config` });
+  const { data: runtimeinfo } = useSuspenseAPIQuery<Record<string, string>>({
+    path: `/status/runtimeinfo`,
+  });
+
+  const { displayTimezone } = useSettings();
+  const [view, setView] = useState<string>("current");
+
+  const hasDiff = previousYAML !== undefined && previousYAML !== yaml;
+  const showDiff = view === "diff" && hasDiff;
 
   return (
-    <CodeHighlight
-      code={yaml}
-      language="yaml"
-      miw="50vw"
-      w="fit-content"
-      maw="calc(100vw - 75px)"
-      mx="auto"
-      mt="xs"
-    />
+    <>
+      <Group justify="center" mt="xs">
+        <Text size="sm" c="gray.6">
+          Last {runtimeinfo.data.reloadConfigSuccess ? "successful" : "failed"}{" "}
+          configuration reload:{" "}
+          {formatTimestamp(
+            new Date(runtimeinfo.data.lastConfigTime).valueOf() / 1000,
+            displayTimezone
+          )}
+        </Text>
+        {hasDiff && (
+          <SegmentedControl
+            size="xs"
+            value={view}
+            onChange={setView}
+            data={[
+              { label: "Current", value: "current" },
+              { label: "Diff since previous reload", value: "diff" },
+            ]}
+          />
+        )}
+      </Group>
+      <CodeHighlight
+        code={showDiff ? diffLines(previousYAML ?? "", yaml) : yaml}
+        language={showDiff ? "diff" : "yaml"}
+        miw="50vw"
+        w="fit-content"
+        maw="calc(100vw - 75px)"
+        mx="auto"
+        mt="xs"
+      />
+    </>
   );
 }
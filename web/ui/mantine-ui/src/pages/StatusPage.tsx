@@ -14,7 +14,7 @@ export default function StatusPage() {
     path: `/status/runtimeinfo`,
   });
 
-  const { useLocalTime } = useSettings();
+  const { displayTimezone } = useSettings();
 
   const statusConfig: Record<
     string,
@@ -26,14 +26,14 @@ export default function StatusPage() {
     startTime: {
       title: "Start time",
       formatValue: (v: string | boolean) =>
-        formatTimestamp(new Date(v as string).valueOf() / 1000, useLocalTime),
+        formatTimestamp(new Date(v as string).valueOf() / 1000, displayTimezone),
     },
     CWD: { title: "Working directory" },
     hostname: { title: "Hostname" },
     serverTime: {
       title: "Server Time",
       formatValue: (v: string | boolean) =>
-        formatTimestamp(new Date(v as string).valueOf() / 1000, useLocalTime),
+        formatTimestamp(new Date(v as string).valueOf() / 1000, displayTimezone),
     },
     reloadConfigSuccess: {
       title: "Configuration reload",
@@ -42,7 +42,7 @@ export default function StatusPage() {
     lastConfigTime: {
       title: "Last successful configuration reload",
       formatValue: (v: string | boolean) =>
-        formatTimestamp(new Date(v as string).valueOf() / 1000, useLocalTime),
+        formatTimestamp(new Date(v as string).valueOf() / 1000, displayTimezone),
     },
     corruptionCount: { title: "WAL corruptions" },
     goroutineCount: { title: "Goroutines" },
@@ -10,18 +10,18 @@ import {
 } from "@mantine/core";
 import { DateTimePicker } from "@mantine/dates";
 import { IconAlertTriangle, IconCheck, IconTrash } from "@tabler/icons-react";
-import dayjs from "dayjs";
-import utc from "dayjs/plugin/utc";
 import { useSuspenseAPIQuery, API_PATH } from "../api/api";
 import { FeaturesResult } from "../api/responseTypes/features";
 import { TSDBStatusResult } from "../api/responseTypes/tsdbStatus";
-import { formatTimestamp } from "../lib/formatTime";
+import {
+  formatPickerTime,
+  formatTimestamp,
+  parsePickerTime,
+} from "../lib/formatTime";
 import { useSettings } from "../state/settingsSlice";
 import InfoPageStack from "../components/InfoPageStack";
 import InfoPageCard from "../components/InfoPageCard";
 
-dayjs.extend(utc);
-
 export default function TSDBStatusPage() {
   const {
     data: {
@@ -43,10 +43,10 @@ export default function TSDBStatusPage() {
 
   const adminApiEnabled = featureApi?.admin ?? false;
 
-  const { useLocalTime, pathPrefix } = useSettings();
+  const { displayTimezone, pathPrefix } = useSettings();
 
   const unixToTime = (unix: number): string => {
-    const formatted = formatTimestamp(unix, useLocalTime);
+    const formatted = formatTimestamp(unix, displayTimezone);
     if (formatted === "Invalid Date") {
       if (numSeries === 0) {
         return "No datapoints yet";
@@ -280,20 +280,12 @@ export default function TSDBStatusPage() {
               withSeconds
               value={
                 startTime !== null
-                  ? useLocalTime
-                    ? dayjs(startTime).format()
-                    : dayjs(startTime)
-                        .subtract(dayjs().utcOffset(), "minutes")
-                        .format()
+                  ? formatPickerTime(startTime, displayTimezone)
                   : undefined
               }
               onChange={(value) =>
                 setStartTime(
-                  value
-                    ? useLocalTime
-                      ? new Date(value).getTime()
-                      : dayjs.utc(value).valueOf()
-                    : null
+                  value ? parsePickerTime(value, displayTimezone) : null
                 )
               }
               clearable
@@ -308,21 +300,11 @@ export default function TSDBStatusPage() {
               withSeconds
               value={
                 endTime !== null
-                  ? useLocalTime
-                    ? dayjs(endTime).format()
-                    : dayjs(endTime)
-                        .subtract(dayjs().utcOffset(), "minutes")
-                        .format()
+                  ? formatPickerTime(endTime, displayTimezone)
                   : undefined
               }
               onChange={(value) =>
-                setEndTime(
-                  value
-                    ? useLocalTime
-                      ? new Date(value).getTime()
-                      : dayjs.utc(value).valueOf()
-                    : null
-                )
+                setEndTime(value ? parsePickerTime(value, displayTimezone) : null)
               }
               clearable
             />
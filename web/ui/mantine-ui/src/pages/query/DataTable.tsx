@@ -24,7 +24,6 @@ import { formatTimestamp } from "../../lib/formatTime";
 import HistogramChart from "./HistogramChart";
 import { Histogram } from "../../types/types";
 import { bucketRangeString } from "./HistogramHelpers";
-import { useSettings } from "../../state/settingsSlice";
 dayjs.extend(timezone);
 
 const maxFormattableSeries = 1000;
@@ -44,15 +43,16 @@ export interface DataTableProps {
   data: InstantQueryResult;
   limitResults: boolean;
   setLimitResults: (limit: boolean) => void;
+  timezone: string; // Display timezone setting value.
 }
 
 const DataTable: FC<DataTableProps> = ({
   data,
   limitResults,
   setLimitResults,
+  timezone: displayTimezone,
 }) => {
   const [scale, setScale] = useState<string>("exponential");
-  const { useLocalTime } = useSettings();
 
   const { result, resultType } = data;
   const doFormat = result.length <= maxFormattableSeries;
@@ -140,7 +140,7 @@ const DataTable: FC<DataTableProps> = ({
                             span
                             c="gray.7"
                             size="1em"
-                            title={formatTimestamp(v[0], useLocalTime)}
+                            title={formatTimestamp(v[0], displayTimezone)}
                           >
                             @ {v[0]}
                           </Text>
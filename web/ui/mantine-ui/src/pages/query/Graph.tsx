@@ -26,6 +26,7 @@ export interface GraphProps {
   showExemplars: boolean;
   displayMode: GraphDisplayMode;
   yAxisMin: number | null;
+  timezone: string; // Display timezone setting value.
   retriggerIdx: number;
   onSelectRange: (start: number, end: number) => void;
 }
@@ -39,6 +40,7 @@ const Graph: FC<GraphProps> = ({
   showExemplars,
   displayMode,
   yAxisMin,
+  timezone,
   retriggerIdx,
   onSelectRange,
 }) => {
@@ -226,6 +228,7 @@ const Graph: FC<GraphProps> = ({
           showExemplars={showExemplars}
           displayMode={displayMode}
           yAxisMin={yAxisMin}
+          timezone={timezone}
           onSelectRange={onSelectRange}
         />
       </Box>
@@ -43,6 +43,8 @@ import ASTNode from "../../promql/ast";
 import serializeNode from "../../promql/serialize";
 import ExplainView from "./ExplainViews/ExplainView";
 import { actionIconStyle } from "../../styles";
+import { useSettings } from "../../state/settingsSlice";
+import TimezoneSelect from "../../components/TimezoneSelect";
 
 export interface PanelProps {
   idx: number;
@@ -60,6 +62,9 @@ const QueryPanel: FC<PanelProps> = ({ idx, metricNames }) => {
 
   const panel = useAppSelector((state) => state.queryPage.panels[idx]);
   const dispatch = useAppDispatch();
+  const { displayTimezone } = useSettings();
+  // The per-panel timezone override takes precedence over the global setting.
+  const panelTimezone = panel.visualizer.timezone ?? displayTimezone;
 
   const [selectedNode, setSelectedNode] = useState<{
     id: string;
@@ -192,6 +197,7 @@ const QueryPanel: FC<PanelProps> = ({ idx, metricNames }) => {
                 time={panel.visualizer.endTime}
                 range={panel.visualizer.range}
                 description="End time"
+                timezone={panelTimezone}
                 onChangeTime={(time) =>
                   dispatch(
                     setVisualizer({
@@ -314,22 +320,42 @@ const QueryPanel: FC<PanelProps> = ({ idx, metricNames }) => {
                   </ActionIcon>
                 </Popover.Target>
                 <Popover.Dropdown p="lg">
-                  <Checkbox
-                    size="xs"
-                    checked={panel.visualizer.yAxisMin !== null}
-                    label="Start Y axis at 0"
-                    onChange={(event) =>
-                      dispatch(
-                        setVisualizer({
-                          idx,
-                          visualizer: {
-                            ...panel.visualizer,
-                            yAxisMin: event.currentTarget.checked ? 0 : null,
-                          },
-                        })
-                      )
-                    }
-                  />
+                  <Stack>
+                    <Checkbox
+                      size="xs"
+                      checked={panel.visualizer.yAxisMin !== null}
+                      label="Start Y axis at 0"
+                      onChange={(event) =>
+                        dispatch(
+                          setVisualizer({
+                            idx,
+                            visualizer: {
+                              ...panel.visualizer,
+                              yAxisMin: event.currentTarget.checked ? 0 : null,
+                            },
+                          })
+                        )
+                      }
+                    />
+                    <TimezoneSelect
+                      size="xs"
+                      label="Panel timezone"
+                      placeholder="Global setting"
+                      clearable
+                      value={panel.visualizer.timezone}
+                      onChange={(value) =>
+                        dispatch(
+                          setVisualizer({
+                            idx,
+                            visualizer: {
+                              ...panel.visualizer,
+                              timezone: value,
+                            },
+                          })
+                        )
+                      }
+                    />
+                  </Stack>
                 </Popover.Dropdown>
               </Popover>
             </Group>
@@ -344,6 +370,7 @@ const QueryPanel: FC<PanelProps> = ({ idx, metricNames }) => {
             showExemplars={panel.visualizer.showExemplars}
             displayMode={panel.visualizer.displayMode}
             yAxisMin={panel.visualizer.yAxisMin}
+            timezone={panelTimezone}
             retriggerIdx={retriggerIdx}
             onSelectRange={onSelectRange}
           />
@@ -1,6 +1,6 @@
 import { FC } from "react";
 import { Box, Text, Tooltip, Table } from "@mantine/core";
-import { QueryStats } from "../../api/responseTypes/query";
+import { OperationStat, QueryStats } from "../../api/responseTypes/query";
 
 const statsTable = (stats: Record<string, number>) => {
   return (
@@ -21,6 +21,47 @@ const statsTable = (stats: Record<string, number>) => {
   );
 };
 
+const operationsTable = (operations: OperationStat[]) => {
+  return (
+    <Table withRowBorders={false}>
+      <Table.Thead>
+        <Table.Tr>
+          <Table.Th pl={0} py={3}>
+            operation
+          </Table.Th>
+          <Table.Th py={3} ta="right">
+            evals
+          </Table.Th>
+          <Table.Th py={3} ta="right">
+            time (s)
+          </Table.Th>
+          <Table.Th pr={0} py={3} ta="right">
+            samples
+          </Table.Th>
+        </Table.Tr>
+      </Table.Thead>
+      <Table.Tbody>
+        {operations.map((op) => (
+          <Table.Tr key={op.operation}>
+            <Table.Td pl={0} py={3} c="dimmed">
+              {op.operation}
+            </Table.Td>
+            <Table.Td py={3} ta="right">
+              {op.count}
+            </Table.Td>
+            <Table.Td py={3} ta="right">
+              {op.time}
+            </Table.Td>
+            <Table.Td pr={0} py={3} ta="right">
+              {op.samples}
+            </Table.Td>
+          </Table.Tr>
+        ))}
+      </Table.Tbody>
+    </Table>
+  );
+};
+
 const QueryStatsDisplay: FC<{
   numResults: number;
   responseTime: number;
@@ -36,6 +77,14 @@ const QueryStatsDisplay: FC<{
             Sample stats:
           </Text>
           {statsTable(stats.samples)}
+          {stats.operations && stats.operations.length > 0 && (
+            <>
+              <Text mt="sm" mb="xs">
+                Operation breakdown (self time and samples):
+              </Text>
+              {operationsTable(stats.operations)}
+            </>
+          )}
         </Box>
       }
     >
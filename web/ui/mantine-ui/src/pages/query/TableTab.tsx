@@ -27,9 +27,12 @@ const TableTab: FC<TableTabProps> = ({ panelIdx, retriggerIdx, expr }) => {
     (state) => state.queryPage.panels[panelIdx]
   );
   const dispatch = useAppDispatch();
-  const { showQueryWarnings, showQueryInfoNotices } = useSettings();
+  const { showQueryWarnings, showQueryInfoNotices, displayTimezone } =
+    useSettings();
 
   const { endTime, range } = visualizer;
+  // The per-panel timezone override takes precedence over the global setting.
+  const panelTimezone = visualizer.timezone ?? displayTimezone;
 
   const { data, error, isFetching, refetch } = useAPIQuery<InstantQueryResult>({
     key: [useId()],
@@ -60,6 +63,7 @@ const TableTab: FC<TableTabProps> = ({ panelIdx, retriggerIdx, expr }) => {
           time={endTime}
           range={range}
           description="Evaluation time"
+          timezone={panelTimezone}
           onChangeTime={(time) =>
             dispatch(
               setVisualizer({
@@ -128,6 +132,7 @@ const TableTab: FC<TableTabProps> = ({ panelIdx, retriggerIdx, expr }) => {
             data={data.data}
             limitResults={limitResults}
             setLimitResults={setLimitResults}
+            timezone={panelTimezone}
           />
         </>
       )}
@@ -2,13 +2,13 @@ import { Group, ActionIcon, CloseButton } from "@mantine/core";
 import { DateTimePicker } from "@mantine/dates";
 import { IconChevronLeft, IconChevronRight } from "@tabler/icons-react";
 import { FC } from "react";
-import { useSettings } from "../../state/settingsSlice";
-import dayjs from "dayjs";
+import { formatPickerTime, parsePickerTime } from "../../lib/formatTime";
 
 interface TimeInputProps {
   time: number | null; // Timestamp in milliseconds.
   range: number; // Range in seconds.
   description: string;
+  timezone: string; // Display timezone setting value.
   onChangeTime: (time: number | null) => void;
 }
 
@@ -18,14 +18,12 @@ const TimeInput: FC<TimeInputProps> = ({
   time,
   range,
   description,
+  timezone,
   onChangeTime,
 }) => {
   const baseTime = () => (time !== null ? time : Date.now().valueOf());
-  const { useLocalTime } = useSettings();
 
-  const dateString = useLocalTime
-    ? dayjs(time).format()
-    : dayjs(time).subtract(dayjs().utcOffset(), "minutes").format();
+  const dateString = time !== null ? formatPickerTime(time, timezone) : "";
 
   return (
     <Group gap={5}>
@@ -36,13 +34,7 @@ const TimeInput: FC<TimeInputProps> = ({
         withSeconds
         value={time !== null ? dateString : undefined}
         onChange={(value) =>
-          onChangeTime(
-            value
-              ? useLocalTime
-                ? new Date(value).getTime()
-                : dayjs.utc(value).valueOf()
-              : null
-          )
+          onChangeTime(value ? parsePickerTime(value, timezone) : null)
         }
         aria-label={description}
         placeholder={description}
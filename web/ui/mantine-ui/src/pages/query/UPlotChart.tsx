@@ -4,7 +4,6 @@ import classes from "./Graph.module.css";
 import { GraphDisplayMode } from "../../state/queryPageSlice";
 import uPlot from "uplot";
 import UplotReact from "uplot-react";
-import { useSettings } from "../../state/settingsSlice";
 import { useComputedColorScheme, Text } from "@mantine/core";
 
 import "uplot/dist/uPlot.min.css";
@@ -25,6 +24,7 @@ export interface UPlotChartProps {
   showExemplars: boolean;
   displayMode: GraphDisplayMode;
   yAxisMin: number | null;
+  timezone: string; // Display timezone setting value.
   onSelectRange: (start: number, end: number) => void;
 }
 
@@ -36,13 +36,13 @@ const UPlotChart: FC<UPlotChartProps> = ({
   width,
   displayMode,
   yAxisMin,
+  timezone,
   onSelectRange,
 }) => {
   const [options, setOptions] = useState<uPlot.Options | null>(null);
   const [processedData, setProcessedData] = useState<uPlot.AlignedData | null>(
     null
   );
-  const { useLocalTime } = useSettings();
   const theme = useComputedColorScheme();
 
   useEffect(() => {
@@ -61,7 +61,7 @@ const UPlotChart: FC<UPlotChartProps> = ({
       seriesData,
       width,
       data,
-      useLocalTime,
+      timezone,
       yAxisMin,
       theme === "light",
       onSelectRange
@@ -81,7 +81,7 @@ const UPlotChart: FC<UPlotChartProps> = ({
     startTime,
     endTime,
     resolution,
-    useLocalTime,
+    timezone,
     theme,
     onSelectRange,
     yAxisMin,
@@ -1,6 +1,6 @@
 import { RangeSamples } from "../../api/responseTypes/query";
 import { formatSeries } from "../../lib/formatSeries";
-import { formatTimestamp } from "../../lib/formatTime";
+import { formatTimestamp, resolveTimezone } from "../../lib/formatTime";
 import { getSeriesColor } from "./colorPool";
 import { computePosition, shift, flip, offset } from "@floating-ui/dom";
 import uPlot, { AlignedData, Series } from "uplot";
@@ -108,7 +108,7 @@ const formatLabels = (labels: { [key: string]: string }): string => `
                 .join("")}
             </div>`;
 
-const tooltipPlugin = (useLocalTime: boolean, data: AlignedData) => {
+const tooltipPlugin = (timezone: string, data: AlignedData) => {
   let over: HTMLDivElement;
   let selectedSeriesIdx: number | null = null;
 
@@ -177,7 +177,7 @@ const tooltipPlugin = (useLocalTime: boolean, data: AlignedData) => {
         const y = top + bbox.top;
 
         overlay.innerHTML = `
-            <div class="date">${formatTimestamp(ts, useLocalTime)}</div>
+            <div class="date">${formatTimestamp(ts, timezone)}</div>
             <div class="series-value">
               <span class="detail-swatch" style="background-color: ${color}"></span>
               <span>${labels.__name__ ? escapeHTML(labels.__name__) + ": " : " "}<strong>${value}</strong></span>
@@ -311,7 +311,7 @@ export const getUPlotOptions = (
   data: AlignedData,
   width: number,
   result: RangeSamples[],
-  useLocalTime: boolean,
+  timezone: string,
   yAxisMin: number | null,
   light: boolean,
   onSelectRange: (_start: number, _end: number) => void,
@@ -330,10 +330,11 @@ export const getUPlotOptions = (
       setScale: false,
     },
   },
-  tzDate: useLocalTime
-    ? undefined
-    : (ts) => uPlot.tzDate(new Date(ts * 1e3), "Etc/UTC"),
-  plugins: [tooltipPlugin(useLocalTime, data)],
+  tzDate:
+    timezone === "local"
+      ? undefined
+      : (ts) => uPlot.tzDate(new Date(ts * 1e3), resolveTimezone(timezone)),
+  plugins: [tooltipPlugin(timezone, data)],
   legend: {
     show: true,
     live: false,
@@ -162,6 +162,18 @@ describe("decodePanelOptionsFromURLParams", () => {
     expect(panels[0].visualizer.yAxisMin).toBeNull();
   });
 
+  test("decodes timezone parameter", () => {
+    const panels = decodePanelOptionsFromURLParams(
+      "g0.expr=up&g0.timezone=Europe%2FBerlin"
+    );
+    expect(panels[0].visualizer.timezone).toBe("Europe/Berlin");
+  });
+
+  test("decodes empty timezone as null", () => {
+    const panels = decodePanelOptionsFromURLParams("g0.expr=up&g0.timezone=");
+    expect(panels[0].visualizer.timezone).toBeNull();
+  });
+
   test("decodes show_exemplars parameter", () => {
     const panelsWithExemplars = decodePanelOptionsFromURLParams(
       "g0.expr=up&g0.show_exemplars=1"
@@ -313,6 +325,7 @@ describe("encodePanelOptionsToURLParams", () => {
       displayMode: GraphDisplayMode.Lines,
       showExemplars: false,
       yAxisMin: null,
+      timezone: null,
     },
     ...overrides,
   });
@@ -510,6 +523,30 @@ describe("encodePanelOptionsToURLParams", () => {
     expect(params.has("g0.y_axis_min")).toBe(false);
   });
 
+  test("encodes timezone when set", () => {
+    const panel = createPanel({
+      visualizer: {
+        ...createPanel().visualizer,
+        timezone: "Europe/Berlin",
+      },
+    });
+    const params = encodePanelOptionsToURLParams([panel]);
+
+    expect(params.get("g0.timezone")).toBe("Europe/Berlin");
+  });
+
+  test("does not encode timezone when null", () => {
+    const panel = createPanel({
+      visualizer: {
+        ...createPanel().visualizer,
+        timezone: null,
+      },
+    });
+    const params = encodePanelOptionsToURLParams([panel]);
+
+    expect(params.has("g0.timezone")).toBe(false);
+  });
+
   test("encodes show_exemplars", () => {
     const panelWithExemplars = createPanel({
       visualizer: {
@@ -556,6 +593,7 @@ describe("encode and decode roundtrip", () => {
       displayMode: GraphDisplayMode.Lines,
       showExemplars: false,
       yAxisMin: null,
+      timezone: null,
     },
     ...overrides,
   });
@@ -595,6 +633,7 @@ describe("encode and decode roundtrip", () => {
         displayMode: GraphDisplayMode.Stacked,
         showExemplars: true,
         yAxisMin: 0,
+        timezone: "Europe/Berlin",
       },
     });
     const encoded = encodePanelOptionsToURLParams([original]);
@@ -614,6 +653,7 @@ describe("encode and decode roundtrip", () => {
       original.visualizer.showExemplars
     );
     expect(decoded[0].visualizer.yAxisMin).toBe(original.visualizer.yAxisMin);
+    expect(decoded[0].visualizer.timezone).toBe(original.visualizer.timezone);
   });
 
   test("roundtrip preserves multiple panels", () => {
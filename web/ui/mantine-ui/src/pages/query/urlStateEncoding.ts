@@ -69,6 +69,9 @@ export const decodePanelOptionsFromURLParams = (query: string): Panel[] => {
     decodeSetting("show_exemplars", (value) => {
       panel.visualizer.showExemplars = value === "1";
     });
+    decodeSetting("timezone", (value) => {
+      panel.visualizer.timezone = value === "" ? null : value;
+    });
     decodeSetting("range_input", (value) => {
       panel.visualizer.range =
         parsePrometheusDuration(value) || panel.visualizer.range;
@@ -179,6 +182,10 @@ export const encodePanelOptionsToURLParams = (
       addParam(idx, "y_axis_min", p.visualizer.yAxisMin.toString());
     }
 
+    if (p.visualizer.timezone !== null) {
+      addParam(idx, "timezone", p.visualizer.timezone);
+    }
+
     addParam(idx, "show_exemplars", p.visualizer.showExemplars ? "1" : "0");
   });
 
@@ -593,6 +593,49 @@ const funcDocs: Record<string, React.ReactNode> = {
       </p>
     </>
   ),
+  bitand: (
+    <>
+      <p>
+        <code>bitand(v instant-vector, mask scalar)</code> returns a vector containing all float samples in the input
+        vector combined with <code>mask</code> using a bitwise AND. Histogram samples in the input vector are ignored
+        silently.
+      </p>
+
+      <p>
+        Bit manipulation is only exact for integers between 0 and 2^53. Samples or masks outside that range yield{" "}
+        <code>NaN</code>.
+      </p>
+    </>
+  ),
+  bitor: (
+    <>
+      <p>
+        <code>bitor(v instant-vector, mask scalar)</code> returns a vector containing all float samples in the input
+        vector combined with <code>mask</code> using a bitwise OR. Histogram samples in the input vector are ignored
+        silently.
+      </p>
+
+      <p>
+        Bit manipulation is only exact for integers between 0 and 2^53. Samples or masks outside that range yield{" "}
+        <code>NaN</code>.
+      </p>
+    </>
+  ),
+  bitshift: (
+    <>
+      <p>
+        <code>bitshift(v instant-vector, shift scalar)</code> returns a vector containing all float samples in the
+        input vector shifted by <code>shift</code> bits. A positive <code>shift</code> moves bits towards the most
+        significant end, a negative <code>shift</code> towards the least significant end. Histogram samples in the
+        input vector are ignored silently.
+      </p>
+
+      <p>
+        Bit manipulation is only exact for integers between 0 and 2^53. Samples outside that range, non-integer shift
+        amounts, and left shifts whose result would exceed 2^53 yield <code>NaN</code>.
+      </p>
+    </>
+  ),
   ceil: (
     <>
       <p>
@@ -1266,6 +1309,17 @@ const funcDocs: Record<string, React.ReactNode> = {
       </ul>
     </>
   ),
+  from_hexstring: (
+    <>
+      <p>
+        <code>from_hexstring(v instant-vector, src_label string)</code> returns a vector where the value of each float
+        sample is replaced by the value of its <code>src_label</code> label parsed as a hexadecimal integer. An
+        optional <code>0x</code> prefix is accepted, and parsing is case-insensitive. The metric name is dropped from
+        the result. Series whose label is absent, not valid hexadecimal, or larger than 2^53 are dropped, as are
+        histogram samples.
+      </p>
+    </>
+  ),
   histogram_avg: (
     <>
       <p>
@@ -1920,6 +1974,19 @@ const funcDocs: Record<string, React.ReactNode> = {
       </pre>
     </>
   ),
+  label_lower: (
+    <>
+      <p>
+        For each timeseries in <code>v</code>,{" "}
+        <code>label_lower(v instant-vector, label_1 string, label_2 string, ...)</code> lowercases the values of all
+        the given labels. Labels that are not present on a series are left absent.
+      </p>
+
+      <p>
+        <code>label_lower</code> acts on float and histogram samples in the same way.
+      </p>
+    </>
+  ),
   label_replace: (
     <>
       <p>
@@ -1964,6 +2031,49 @@ const funcDocs: Record<string, React.ReactNode> = {
       </pre>
     </>
   ),
+  label_substr: (
+    <>
+      <p>
+        For each timeseries in <code>v</code>,{" "}
+        <code>label_substr(v instant-vector, start scalar, end scalar, label_1 string, label_2 string, ...)</code>{" "}
+        replaces the values of all the given labels with the characters from position <code>start</code> (inclusive) up
+        to position <code>end</code> (exclusive). Positions are zero-based, count characters rather than bytes and are
+        clamped to the value length. <code>start</code> and <code>end</code> must be number literals. If the resulting
+        value is empty, the label is dropped.
+      </p>
+
+      <p>
+        <code>label_substr</code> acts on float and histogram samples in the same way.
+      </p>
+    </>
+  ),
+  label_trim: (
+    <>
+      <p>
+        For each timeseries in <code>v</code>,{" "}
+        <code>label_trim(v instant-vector, cutset string, label_1 string, label_2 string, ...)</code> removes all
+        leading and trailing characters contained in <code>cutset</code> from the values of all the given labels. If{" "}
+        <code>cutset</code> is empty, whitespace is trimmed. If the resulting value is empty, the label is dropped.
+      </p>
+
+      <p>
+        <code>label_trim</code> acts on float and histogram samples in the same way.
+      </p>
+    </>
+  ),
+  label_upper: (
+    <>
+      <p>
+        For each timeseries in <code>v</code>,{" "}
+        <code>label_upper(v instant-vector, label_1 string, label_2 string, ...)</code> uppercases the values of all
+        the given labels. Labels that are not present on a series are left absent.
+      </p>
+
+      <p>
+        <code>label_upper</code> acts on float and histogram samples in the same way.
+      </p>
+    </>
+  ),
   last_over_time: (
     <>
       <p>
@@ -16,6 +16,24 @@ export const functionSignatures: Record<string, Func> = {
   atan: { name: "atan", argTypes: [valueType.vector], variadic: 0, returnType: valueType.vector },
   atanh: { name: "atanh", argTypes: [valueType.vector], variadic: 0, returnType: valueType.vector },
   avg_over_time: { name: "avg_over_time", argTypes: [valueType.matrix], variadic: 0, returnType: valueType.vector },
+  bitand: {
+    name: "bitand",
+    argTypes: [valueType.vector, valueType.scalar],
+    variadic: 0,
+    returnType: valueType.vector,
+  },
+  bitor: {
+    name: "bitor",
+    argTypes: [valueType.vector, valueType.scalar],
+    variadic: 0,
+    returnType: valueType.vector,
+  },
+  bitshift: {
+    name: "bitshift",
+    argTypes: [valueType.vector, valueType.scalar],
+    variadic: 0,
+    returnType: valueType.vector,
+  },
   ceil: { name: "ceil", argTypes: [valueType.vector], variadic: 0, returnType: valueType.vector },
   changes: { name: "changes", argTypes: [valueType.matrix], variadic: 0, returnType: valueType.vector },
   clamp: {
@@ -56,6 +74,12 @@ export const functionSignatures: Record<string, Func> = {
   exp: { name: "exp", argTypes: [valueType.vector], variadic: 0, returnType: valueType.vector },
   first_over_time: { name: "first_over_time", argTypes: [valueType.matrix], variadic: 0, returnType: valueType.vector },
   floor: { name: "floor", argTypes: [valueType.vector], variadic: 0, returnType: valueType.vector },
+  from_hexstring: {
+    name: "from_hexstring",
+    argTypes: [valueType.vector, valueType.string],
+    variadic: 0,
+    returnType: valueType.vector,
+  },
   histogram_avg: { name: "histogram_avg", argTypes: [valueType.vector], variadic: 0, returnType: valueType.vector },
   histogram_count: { name: "histogram_count", argTypes: [valueType.vector], variadic: 0, returnType: valueType.vector },
   histogram_fraction: {
@@ -100,12 +124,36 @@ export const functionSignatures: Record<string, Func> = {
     variadic: -1,
     returnType: valueType.vector,
   },
+  label_lower: {
+    name: "label_lower",
+    argTypes: [valueType.vector, valueType.string],
+    variadic: -1,
+    returnType: valueType.vector,
+  },
   label_replace: {
     name: "label_replace",
     argTypes: [valueType.vector, valueType.string, valueType.string, valueType.string, valueType.string],
     variadic: 0,
     returnType: valueType.vector,
   },
+  label_substr: {
+    name: "label_substr",
+    argTypes: [valueType.vector, valueType.scalar, valueType.scalar, valueType.string],
+    variadic: -1,
+    returnType: valueType.vector,
+  },
+  label_trim: {
+    name: "label_trim",
+    argTypes: [valueType.vector, valueType.string, valueType.string],
+    variadic: -1,
+    returnType: valueType.vector,
+  },
+  label_upper: {
+    name: "label_upper",
+    argTypes: [valueType.vector, valueType.string],
+    variadic: -1,
+    returnType: valueType.vector,
+  },
   last_over_time: { name: "last_over_time", argTypes: [valueType.matrix], variadic: 0, returnType: valueType.vector },
   ln: { name: "ln", argTypes: [valueType.vector], variadic: 0, returnType: valueType.vector },
   log10: { name: "log10", argTypes: [valueType.vector], variadic: 0, returnType: valueType.vector },
@@ -57,7 +57,7 @@ startAppListening({
   effect: ({ payload }) => {
     Object.entries(payload).forEach(([key, value]) => {
       switch (key) {
-        case "useLocalTime":
+        case "displayTimezone":
         case "enableQueryHistory":
         case "enableAutocomplete":
         case "enableSyntaxHighlighting":
@@ -59,6 +59,8 @@ export interface Visualizer {
   displayMode: GraphDisplayMode;
   showExemplars: boolean;
   yAxisMin: number | null;
+  // Per-panel display timezone override, null means using the global setting.
+  timezone: string | null;
 }
 
 export type Panel = {
@@ -88,6 +90,7 @@ export const newDefaultPanel = (): Panel => ({
     displayMode: GraphDisplayMode.Lines,
     showExemplars: false,
     yAxisMin: null,
+    timezone: null,
   },
 });
 
@@ -8,7 +8,8 @@ interface Settings {
   agentMode: boolean;
   ready: boolean;
   pathPrefix: string;
-  useLocalTime: boolean;
+  // Either an IANA timezone name, "UTC" or "local" (the browser timezone).
+  displayTimezone: string;
   enableQueryHistory: boolean;
   enableAutocomplete: boolean;
   enableSyntaxHighlighting: boolean;
@@ -25,7 +26,11 @@ declare const GLOBAL_CONSOLES_LINK: string;
 declare const GLOBAL_AGENT_MODE: string;
 declare const GLOBAL_READY: string;
 declare const GLOBAL_LOOKBACKDELTA: string;
+declare const GLOBAL_DISPLAY_TIMEZONE: string;
 
+export const localStorageKeyDisplayTimezone = "settings.displayTimezone";
+// Legacy boolean setting that predates the display timezone setting. It is
+// only read as a fallback for users who had enabled it.
 export const localStorageKeyUseLocalTime = "settings.useLocalTime";
 export const localStorageKeyEnableQueryHistory = "settings.enableQueryHistory";
 export const localStorageKeyEnableAutocomplete = "settings.enableAutocomplete";
@@ -80,9 +85,15 @@ export const initialState: Settings = {
       ? ""
       : GLOBAL_LOOKBACKDELTA,
   pathPrefix: getPathPrefix(window.location.pathname),
-  useLocalTime: initializeFromLocalStorage<boolean>(
-    localStorageKeyUseLocalTime,
-    false
+  displayTimezone: initializeFromLocalStorage<string>(
+    localStorageKeyDisplayTimezone,
+    initializeFromLocalStorage<boolean>(localStorageKeyUseLocalTime, false)
+      ? "local"
+      : GLOBAL_DISPLAY_TIMEZONE === "DISPLAY_TIMEZONE_PLACEHOLDER" ||
+          GLOBAL_DISPLAY_TIMEZONE === "" ||
+          GLOBAL_DISPLAY_TIMEZONE === null
+        ? "UTC"
+        : GLOBAL_DISPLAY_TIMEZONE
   ),
   enableQueryHistory: initializeFromLocalStorage<boolean>(
     localStorageKeyEnableQueryHistory,
@@ -130,6 +130,24 @@ export const functionIdentifierTerms = [
     info: 'Average series values over time',
     type: 'function',
   },
+  {
+    label: 'bitand',
+    detail: 'function',
+    info: 'Bitwise AND of input series values and a mask',
+    type: 'function',
+  },
+  {
+    label: 'bitor',
+    detail: 'function',
+    info: 'Bitwise OR of input series values and a mask',
+    type: 'function',
+  },
+  {
+    label: 'bitshift',
+    detail: 'function',
+    info: 'Shift bits of input series values',
+    type: 'function',
+  },
   {
     label: 'ceil',
     detail: 'function',
@@ -238,6 +256,12 @@ export const functionIdentifierTerms = [
     info: 'Round down values of input series to nearest integer',
     type: 'function',
   },
+  {
+    label: 'from_hexstring',
+    detail: 'function',
+    info: 'Parse the given label of input series as a hexadecimal integer',
+    type: 'function',
+  },
   {
     label: 'histogram_avg',
     detail: 'function',
@@ -340,6 +364,30 @@ export const functionIdentifierTerms = [
     info: 'Join together label values into new label',
     type: 'function',
   },
+  {
+    label: 'label_lower',
+    detail: 'function',
+    info: 'Lowercase the values of the given labels',
+    type: 'function',
+  },
+  {
+    label: 'label_substr',
+    detail: 'function',
+    info: 'Keep a substring of the values of the given labels',
+    type: 'function',
+  },
+  {
+    label: 'label_trim',
+    detail: 'function',
+    info: 'Trim characters from the values of the given labels',
+    type: 'function',
+  },
+  {
+    label: 'label_upper',
+    detail: 'function',
+    info: 'Uppercase the values of the given labels',
+    type: 'function',
+  },
   {
     label: 'first_over_time',
     detail: 'function',
@@ -22,6 +22,9 @@ import {
   Atan,
   Atanh,
   AvgOverTime,
+  Bitand,
+  Bitor,
+  Bitshift,
   Ceil,
   Changes,
   Clamp,
@@ -41,6 +44,7 @@ import {
   Exp,
   FirstOverTime,
   Floor,
+  FromHexstring,
   HistogramAvg,
   HistogramCount,
   HistogramFraction,
@@ -56,7 +60,11 @@ import {
   Info,
   Irate,
   LabelJoin,
+  LabelLower,
   LabelReplace,
+  LabelSubstr,
+  LabelTrim,
+  LabelUpper,
   LastOverTime,
   Ln,
   Log10,
@@ -180,6 +188,24 @@ const promqlFunctions: { [key: number]: PromQLFunction } = {
     variadic: 0,
     returnType: ValueType.vector,
   },
+  [Bitand]: {
+    name: 'bitand',
+    argTypes: [ValueType.vector, ValueType.scalar],
+    variadic: 0,
+    returnType: ValueType.vector,
+  },
+  [Bitor]: {
+    name: 'bitor',
+    argTypes: [ValueType.vector, ValueType.scalar],
+    variadic: 0,
+    returnType: ValueType.vector,
+  },
+  [Bitshift]: {
+    name: 'bitshift',
+    argTypes: [ValueType.vector, ValueType.scalar],
+    variadic: 0,
+    returnType: ValueType.vector,
+  },
   [Ceil]: {
     name: 'ceil',
     argTypes: [ValueType.vector],
@@ -294,6 +320,12 @@ const promqlFunctions: { [key: number]: PromQLFunction } = {
     variadic: 0,
     returnType: ValueType.vector,
   },
+  [FromHexstring]: {
+    name: 'from_hexstring',
+    argTypes: [ValueType.vector, ValueType.string],
+    variadic: 0,
+    returnType: ValueType.vector,
+  },
   [HistogramAvg]: {
     name: 'histogram_avg',
     argTypes: [ValueType.vector],
@@ -390,6 +422,30 @@ const promqlFunctions: { [key: number]: PromQLFunction } = {
     variadic: -1,
     returnType: ValueType.vector,
   },
+  [LabelLower]: {
+    name: 'label_lower',
+    argTypes: [ValueType.vector, ValueType.string],
+    variadic: -1,
+    returnType: ValueType.vector,
+  },
+  [LabelSubstr]: {
+    name: 'label_substr',
+    argTypes: [ValueType.vector, ValueType.scalar, ValueType.scalar, ValueType.string],
+    variadic: -1,
+    returnType: ValueType.vector,
+  },
+  [LabelTrim]: {
+    name: 'label_trim',
+    argTypes: [ValueType.vector, ValueType.string, ValueType.string],
+    variadic: -1,
+    returnType: ValueType.vector,
+  },
+  [LabelUpper]: {
+    name: 'label_upper',
+    argTypes: [ValueType.vector, ValueType.string],
+    variadic: -1,
+    returnType: ValueType.vector,
+  },
   [LastOverTime]: {
     name: 'last_over_time',
     argTypes: [ValueType.matrix],
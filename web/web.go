@@ -52,6 +52,7 @@ import (
 	"go.uber.org/atomic"
 
 	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/notifier"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/promql/parser"
@@ -63,7 +64,9 @@ import (
 	"github.com/prometheus/prometheus/util/httputil"
 	"github.com/prometheus/prometheus/util/netconnlimit"
 	"github.com/prometheus/prometheus/util/notifications"
+	"github.com/prometheus/prometheus/util/stateevents"
 	api_v1 "github.com/prometheus/prometheus/web/api/v1"
+	api_v2 "github.com/prometheus/prometheus/web/api/v2"
 	"github.com/prometheus/prometheus/web/ui"
 )
 
@@ -232,6 +235,7 @@ type Handler struct {
 	notifier        *notifier.Manager
 
 	apiV1 *api_v1.API
+	apiV2 *api_v2.API
 
 	router      *route.Router
 	quitCh      chan struct{}
@@ -244,6 +248,10 @@ type Handler struct {
 	cwd         string
 	flagsMap    map[string]string
 
+	// previousConfigYAML is the redacted YAML of the configuration that was
+	// loaded before the current one. It is empty until the first reload.
+	previousConfigYAML string
+
 	mtx sync.RWMutex
 	now func() model.Time
 
@@ -255,6 +263,11 @@ func (h *Handler) ApplyConfig(conf *config.Config) error {
 	h.mtx.Lock()
 	defer h.mtx.Unlock()
 
+	if h.config != nil {
+		// Keep the redacted YAML of the outgoing configuration so the config
+		// API can serve a diff of the last two loaded configurations.
+		h.previousConfigYAML = h.config.String()
+	}
 	h.config = conf
 	if conf.StorageConfig.TSDBConfig != nil && conf.StorageConfig.TSDBConfig.Retention != nil {
 		h.options.TSDBRetentionDuration = conf.StorageConfig.TSDBConfig.Retention.Time
@@ -283,6 +296,8 @@ type Options struct {
 	Version               *PrometheusVersion
 	NotificationsGetter   func() []notifications.Notification
 	NotificationsSub      func() (<-chan notifications.Notification, func(), bool)
+	StateEventsSub        func() (<-chan stateevents.Event, func(), bool)
+	SDUpdateHistory       func() map[string][]discovery.UpdateDiff
 	Flags                 map[string]string
 
 	ListenAddresses            []string
@@ -301,14 +316,22 @@ type Options struct {
 	EnableSearch               bool
 	MaxSearchLimit             int
 	PageTitle                  string
+	DefaultDisplayTimezone     string
 	RemoteReadSampleLimit      int
 	RemoteReadConcurrencyLimit int
 	RemoteReadBytesInFrame     int
+	RemoteReadMaxStreams       int
+	RemoteReadMaxFrameBacklog  int
+	RemoteReadIdleWriteTimeout time.Duration
 	EnableRemoteWriteReceiver  bool
 	EnableOTLPWriteReceiver    bool
 	ConvertOTLPDelta           bool
+	ConvertOTLPDeltaMaxStale   time.Duration
+	ConvertOTLPDeltaMaxStreams int
 	NativeOTLPDeltaIngestion   bool
+	EnableOMPushReceiver       bool
 	IsAgent                    bool
+	AgentLocalQuery            bool
 	STZeroIngestionEnabled     bool
 	EnableTypeAndUnitLabels    bool
 	AppendMetadata             bool
@@ -381,7 +404,7 @@ func New(logger *slog.Logger, o *Options) *Handler {
 		app   storage.Appendable
 		appV2 storage.AppendableV2
 	)
-	if o.EnableRemoteWriteReceiver || o.EnableOTLPWriteReceiver {
+	if o.EnableRemoteWriteReceiver || o.EnableOTLPWriteReceiver || o.EnableOMPushReceiver {
 		app, appV2 = h.storage, h.storage
 	}
 
@@ -396,6 +419,11 @@ func New(logger *slog.Logger, o *Options) *Handler {
 			defer h.mtx.RUnlock()
 			return *h.config
 		},
+		func() string {
+			h.mtx.RLock()
+			defer h.mtx.RUnlock()
+			return h.previousConfigYAML
+		},
 		o.Flags,
 		api_v1.GlobalURLOptions{
 			ListenAddress: o.ListenAddresses[0],
@@ -410,15 +438,21 @@ func New(logger *slog.Logger, o *Options) *Handler {
 		h.options.MaxSearchLimit,
 		logger,
 		FactoryRr,
+		o.SDUpdateHistory,
 		h.options.RemoteReadSampleLimit,
 		h.options.RemoteReadConcurrencyLimit,
 		h.options.RemoteReadBytesInFrame,
+		h.options.RemoteReadMaxStreams,
+		h.options.RemoteReadMaxFrameBacklog,
+		h.options.RemoteReadIdleWriteTimeout,
 		h.options.IsAgent,
+		h.options.AgentLocalQuery,
 		h.options.CORSOrigin,
 		h.runtimeInfo,
 		h.versionInfo,
 		h.options.NotificationsGetter,
 		h.options.NotificationsSub,
+		h.options.StateEventsSub,
 		o.Gatherer,
 		o.Registerer,
 		nil,
@@ -426,7 +460,10 @@ func New(logger *slog.Logger, o *Options) *Handler {
 		o.AcceptRemoteWriteProtoMsgs,
 		o.EnableOTLPWriteReceiver,
 		o.ConvertOTLPDelta,
+		o.ConvertOTLPDeltaMaxStale,
+		o.ConvertOTLPDeltaMaxStreams,
 		o.NativeOTLPDeltaIngestion,
+		o.EnableOMPushReceiver,
 		o.STZeroIngestionEnabled,
 		o.LookbackDelta,
 		o.EnableTypeAndUnitLabels,
@@ -441,12 +478,17 @@ func New(logger *slog.Logger, o *Options) *Handler {
 		o.Parser,
 	)
 
+	if !o.IsAgent {
+		h.apiV2 = api_v2.NewAPI(h.queryEngine, h.storage, h.testReady, logger.With("component", "web/api/v2"))
+	}
+
 	if r := o.FeatureRegistry; r != nil {
 		// Set dynamic API features (based on configuration).
 		r.Set(features.API, "lifecycle", o.EnableLifecycle)
 		r.Set(features.API, "admin", o.EnableAdminAPI)
 		r.Set(features.API, "remote_write_receiver", o.EnableRemoteWriteReceiver)
 		r.Set(features.API, "otlp_write_receiver", o.EnableOTLPWriteReceiver)
+		r.Set(features.API, "openmetrics_push_receiver", o.EnableOMPushReceiver)
 		r.Set(features.API, "search", o.EnableSearch)
 		for _, alg := range api_v1.FuzzAlgorithms() {
 			r.Enable(features.API, "search_fuzz_alg_"+alg)
@@ -527,6 +569,7 @@ func New(logger *slog.Logger, o *Options) *Handler {
 		replacedIdx = bytes.ReplaceAll(replacedIdx, []byte("AGENT_MODE_PLACEHOLDER"), []byte(strconv.FormatBool(h.options.IsAgent)))
 		replacedIdx = bytes.ReplaceAll(replacedIdx, []byte("READY_PLACEHOLDER"), []byte(strconv.FormatBool(h.isReady())))
 		replacedIdx = bytes.ReplaceAll(replacedIdx, []byte("LOOKBACKDELTA_PLACEHOLDER"), []byte(model.Duration(h.options.LookbackDelta).String()))
+		replacedIdx = bytes.ReplaceAll(replacedIdx, []byte("DISPLAY_TIMEZONE_PLACEHOLDER"), []byte(h.options.DefaultDisplayTimezone))
 		w.Write(replacedIdx)
 	}
 
@@ -765,6 +808,15 @@ func (h *Handler) Run(ctx context.Context, listeners []net.Listener, webConfig s
 
 	mux.Handle(apiPath+"/v1/", http.StripPrefix(apiPath+"/v1", av1))
 
+	if h.apiV2 != nil {
+		av2 := route.New().
+			WithInstrumentation(h.metrics.instrumentHandlerWithPrefix("/api/v2")).
+			WithInstrumentation(setPathWithPrefix(apiPath + "/v2"))
+		h.apiV2.Register(av2)
+
+		mux.Handle(apiPath+"/v2/", http.StripPrefix(apiPath+"/v2", av2))
+	}
+
 	errlog := slog.NewLogLogger(h.logger.Handler(), slog.LevelError)
 
 	spanNameFormatter := otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
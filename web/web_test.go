@@ -56,6 +56,10 @@ func (a *dbAdapter) BlockMetas() ([]tsdb.BlockMeta, error) {
 	return a.DB.BlockMetas(), nil
 }
 
+func (a *dbAdapter) BlockQueryStats() ([]tsdb.BlockQueryStat, error) {
+	return a.DB.BlockQueryStats(), nil
+}
+
 func (a *dbAdapter) Stats(statsByLabelName string, limit int) (*tsdb.Stats, error) {
 	return a.Head().Stats(statsByLabelName, limit), nil
 }
@@ -641,6 +645,7 @@ func cleanupSnapshot(t *testing.T, dbDir string, resp *http.Response) {
 	require.NoError(t, err)
 	require.NoError(t, json.Unmarshal(b, snapshot))
 	require.NotEmpty(t, snapshot.Data.Name, "snapshot directory not returned")
+	require.NoError(t, os.Remove(filepath.Join(dbDir, "snapshots", snapshot.Data.Name, tsdb.SnapshotManifestFilename)))
 	require.NoError(t, os.Remove(filepath.Join(dbDir, "snapshots", snapshot.Data.Name)))
 	require.NoError(t, os.Remove(filepath.Join(dbDir, "snapshots")))
 }